@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"crypto/rand"
-	"encoding/base64"
 	"fmt"
 	"os"
 	"os/signal"
@@ -13,10 +12,14 @@ import (
 	"chat-gateway/internal/grpc"
 	"chat-gateway/internal/platform/config"
 	"chat-gateway/internal/platform/driver"
+	"chat-gateway/internal/platform/health"
 	"chat-gateway/internal/platform/logger"
 	"chat-gateway/internal/platform/server"
 	"chat-gateway/internal/security/keymanager"
 	"chat-gateway/internal/storage/database"
+	"chat-gateway/internal/storage/database/chatroom"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
 func main() {
@@ -27,37 +30,34 @@ func main() {
 }
 
 // loadMasterKey 載入主密鑰
-// 從環境變量 MASTER_KEY 讀取 base64 編碼的 32 bytes 密鑰
-// 如果未設置，生成臨時隨機密鑰（開發環境）
+// 從 MASTER_KEY（base64）或 MASTER_KEY_FILE（掛載的 secret 文件）讀取 32 bytes 密鑰，只能設置其中一個
+// 如果都未設置，生成臨時隨機密鑰（開發環境）
 func loadMasterKey() ([]byte, error) {
 	ctx := context.Background()
-	masterKeyEnv := os.Getenv("MASTER_KEY")
 
-	logger.Info(ctx, "=== 檢查 MASTER_KEY 環境變量 ===", logger.WithDetails(map[string]interface{}{
-		"exists": masterKeyEnv != "",
-		"length": len(masterKeyEnv),
+	provider, err := keymanager.ResolveMasterKeyProvider(os.Getenv)
+	if err != nil {
+		logger.Error(ctx, "主密鑰來源配置錯誤", logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return nil, fmt.Errorf("invalid master key configuration: %w", err)
+	}
+
+	logger.Info(ctx, "=== 檢查主密鑰來源 ===", logger.WithDetails(map[string]interface{}{
+		"configured": provider != nil,
 	}))
 
-	if masterKeyEnv != "" {
-		// 從環境變量讀取（base64 解碼）
-		masterKey, err := base64.StdEncoding.DecodeString(masterKeyEnv)
+	if provider != nil {
+		masterKey, err := keymanager.LoadMasterKey(ctx, provider)
 		if err != nil {
-			logger.Error(ctx, "Master Key 格式錯誤", logger.WithDetails(map[string]interface{}{"error": err.Error()}))
-			return nil, fmt.Errorf("invalid master key configuration")
-		}
-
-		// 驗證長度必須是 32 bytes
-		if len(masterKey) != 32 {
-			logger.Error(ctx, "Master Key 長度錯誤", logger.WithDetails(map[string]interface{}{"expected": 32, "got": len(masterKey)}))
+			logger.Error(ctx, "主密鑰載入失敗", logger.WithDetails(map[string]interface{}{"error": err.Error()}))
 			return nil, fmt.Errorf("invalid master key configuration")
 		}
 
 		// 遮罩顯示（只顯示前4個字元，其餘用*代替）
 		masked := fmt.Sprintf("%x****", masterKey[:2])
-		logger.Info(ctx, "[SUCCESS] 成功從環境變量載入主密鑰", logger.WithDetails(map[string]interface{}{
+		logger.Info(ctx, "[SUCCESS] 成功載入主密鑰", logger.WithDetails(map[string]interface{}{
 			"masked": masked,
 			"length": len(masterKey),
-			"source": "MASTER_KEY environment variable",
+			"source": provider.Name(),
 		}))
 		return masterKey, nil
 	}
@@ -75,14 +75,142 @@ func loadMasterKey() ([]byte, error) {
 		"masked": masked,
 		"source": "randomly generated",
 	}))
-	logger.Info(ctx, "[WARNING] 提示：生產環境請設置 MASTER_KEY 環境變量")
+	logger.Info(ctx, "[WARNING] 提示：生產環境請設置 MASTER_KEY 或 MASTER_KEY_FILE 環境變量")
 	logger.Info(ctx, "生成方式：export MASTER_KEY=$(openssl rand -base64 32)")
 
 	return masterKey, nil
 }
 
 // mainNoExit 分離主要邏輯以避免 exitAfterDefer 問題，確保 defer 函數正常執行.
+// keyWarmupTimeout 啟動時預加載密鑰的最長時間，超時後剩餘聊天室按需加載
+const keyWarmupTimeout = 30 * time.Second
+
+// warmUpRoomKeys 預加載最近活躍的聊天室密鑰（失敗不影響啟動）
+func warmUpRoomKeys(ctx context.Context, repos *database.Repositories, keyManager *keymanager.KeyManagerWithPersistence, limit int) {
+	ctx, cancel := context.WithTimeout(ctx, keyWarmupTimeout)
+	defer cancel()
+
+	start := time.Now()
+	roomIDs, err := repos.ChatRoom.ListRecentlyActiveRoomIDs(ctx, limit)
+	if err != nil {
+		logger.Error(ctx, "[KeyManager] 獲取最近活躍聊天室失敗，跳過密鑰預加載", logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	loaded, err := keyManager.WarmUp(ctx, roomIDs)
+	details := map[string]interface{}{
+		"rooms":       len(roomIDs),
+		"loaded":      loaded,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		details["error"] = err.Error()
+		logger.Warning(ctx, "[KeyManager] 部分聊天室密鑰預加載失敗", logger.WithDetails(details))
+		return
+	}
+	logger.Info(ctx, "[KeyManager] 聊天室密鑰預加載完成", logger.WithDetails(details))
+}
+
+// indexBootstrapTimeout 啟動時創建索引的最長時間
+const indexBootstrapTimeout = 60 * time.Second
+
+// ensureIndexes 創建聊天室和訊息集合的索引（已存在的跳過，失敗不影響啟動）
+func ensureIndexes(ctx context.Context, db *mongo.Database) {
+	ctx, cancel := context.WithTimeout(ctx, indexBootstrapTimeout)
+	defer cancel()
+
+	start := time.Now()
+	results, err := chatroom.CreateIndexes(ctx, db)
+
+	var created, existing []string
+	for _, r := range results {
+		name := r.Collection + "." + r.Name
+		switch {
+		case r.Created:
+			created = append(created, name)
+		case r.Err == nil:
+			existing = append(existing, name)
+		}
+	}
+	details := map[string]interface{}{
+		"created":     created,
+		"existing":    len(existing),
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+
+	if err != nil {
+		details["error"] = err.Error()
+		logger.Error(ctx, "[Database] 部分索引創建失敗，相關查詢將退化為全集合掃描，請檢查索引定義是否與現有索引衝突", logger.WithDetails(details))
+		return
+	}
+	logger.Info(ctx, "[Database] 索引檢查完成", logger.WithDetails(details))
+}
+
+// backfillDirectKeys 為舊的私聊補上 direct_key（已補上的會跳過，失敗不影響啟動）
+func backfillDirectKeys(ctx context.Context, repos *database.Repositories) {
+	ctx, cancel := context.WithTimeout(ctx, indexBootstrapTimeout)
+	defer cancel()
+
+	updated, duplicates, err := repos.ChatRoom.BackfillDirectKeys(ctx)
+	details := map[string]interface{}{
+		"updated":    updated,
+		"duplicates": duplicates,
+	}
+	if err != nil {
+		details["error"] = err.Error()
+		logger.Error(ctx, "[Database] 補充私聊唯一鍵失敗，舊私聊可能被重複創建", logger.WithDetails(details))
+		return
+	}
+	if len(duplicates) > 0 {
+		logger.Warning(ctx, "[Database] 發現重複的私聊，已保留最早創建的一個", logger.WithDetails(details))
+		return
+	}
+	if updated > 0 {
+		logger.Info(ctx, "[Database] 已為舊私聊補充唯一鍵", logger.WithDetails(details))
+	}
+}
+
+// migrateMembers 把內嵌成員遷移到獨立成員集合（已遷移的跳過，失敗不影響啟動）
+func migrateMembers(ctx context.Context, repos *database.Repositories) {
+	ctx, cancel := context.WithTimeout(ctx, indexBootstrapTimeout)
+	defer cancel()
+
+	migrated, err := repos.ChatRoom.MigrateMembersToCollection(ctx)
+	details := map[string]interface{}{"migrated_rooms": migrated}
+	if err != nil {
+		details["error"] = err.Error()
+		logger.Error(ctx, "[Database] 遷移聊天室成員失敗，未遷移的聊天室成員暫時不可見，重啟後會繼續遷移", logger.WithDetails(details))
+		return
+	}
+	if migrated > 0 {
+		logger.Info(ctx, "[Database] 已把聊天室成員遷移到獨立集合", logger.WithDetails(details))
+	}
+}
+
+// reloadConfig 重新載入配置文件，失敗時繼續使用原配置
+func reloadConfig(ctx context.Context) {
+	restartRequired, err := config.Reload()
+	if err != nil {
+		logger.Error(ctx, "[Config] 重新載入配置失敗，繼續使用原配置",
+			logger.WithAction("reload_config"),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	logger.Info(ctx, "[Config] 已重新載入配置", logger.WithAction("reload_config"))
+	if len(restartRequired) > 0 {
+		logger.Warning(ctx, "[Config] 部分修改需要重啟服務才能生效",
+			logger.WithAction("reload_config"),
+			logger.WithDetails(map[string]interface{}{"sections": restartRequired}))
+	}
+}
+
 func mainNoExit() error {
+	// 載入配置（日誌輪轉設定來自配置，需要先於日誌初始化）.
+	if err := config.Load(); err != nil {
+		return err
+	}
+
 	// 初始化日誌.
 	if err := logger.InitLogger(); err != nil {
 		return err
@@ -90,11 +218,6 @@ func mainNoExit() error {
 	defer logger.CloseLogger()
 
 	ctx := context.Background()
-
-	// 載入配置.
-	if err := config.Load(); err != nil {
-		return err
-	}
 	// 連接資料庫.
 	if err := driver.ConnectMongo(); err != nil {
 		return err
@@ -108,8 +231,19 @@ func mainNoExit() error {
 	// 設置 MongoDB 連接到 database 包
 	database.SetMongoDB(driver.GetMongoDatabase())
 
+	// 創建索引（新環境沒有索引時查詢會全集合掃描）
+	if db := driver.GetMongoDatabase(); db != nil {
+		ensureIndexes(ctx, db)
+	}
+
 	// 初始化 Repository.
 	repos := database.NewRepositories(config.Get())
+	if repos != nil {
+		backfillDirectKeys(ctx, repos)
+		if repos.ChatRoom.MemberCollectionEnabled() {
+			migrateMembers(ctx, repos)
+		}
+	}
 
 	// 獲取安全配置
 	cfg := config.Get()
@@ -139,6 +273,20 @@ func mainNoExit() error {
 			return fmt.Errorf("encryption initialization failed")
 		}
 
+		// 健康檢查包含密鑰存儲與主密鑰狀態
+		health.SetEncryptionChecker(keyManager)
+
+		// 已撤銷的密鑰默認仍可解密舊訊息
+		if cfg.Security.Encryption.DenyRevokedKeyDecryption {
+			keyManager.SetRevokedKeyDecryption(false)
+			logger.Info(ctx, "[KeyManager] 已禁止使用已撤銷的密鑰解密")
+		}
+
+		// 預加載最近活躍聊天室的密鑰，避免重啟後每個聊天室的第一條訊息都要查詢數據庫
+		if rooms := cfg.Security.Encryption.KeyWarmupRooms; rooms > 0 && repos != nil {
+			warmUpRoomKeys(ctx, repos, keyManager, rooms)
+		}
+
 		// 啟用自動密鑰輪換（可選）
 		if os.Getenv("KEY_ROTATION_ENABLED") == "true" {
 			keyManager.StartAutoRotation()
@@ -152,6 +300,7 @@ func mainNoExit() error {
 		logger.Error(ctx, "gRPC 服務器創建失敗", logger.WithDetails(map[string]interface{}{"error": err.Error()}))
 		return fmt.Errorf("server initialization failed")
 	}
+	health.SetGRPCReadiness(grpcServer.Ready)
 	go func() {
 		if err := grpcServer.Start("8081"); err != nil {
 			logger.Errorf(ctx, "gRPC 服務器啟動失敗: %v", err)
@@ -160,7 +309,7 @@ func mainNoExit() error {
 
 	// 啟動 HTTP 服務器（API 橋樑）
 	go func() {
-		if err := server.Start(repos); err != nil {
+		if err := server.Start(cfg, repos); err != nil {
 			logger.Errorf(ctx, "HTTP 服務器啟動失敗: %v", err)
 		}
 	}()
@@ -169,10 +318,15 @@ func mainNoExit() error {
 	time.Sleep(2 * time.Second)
 	logger.Info(ctx, "[System] 服務器啟動完成")
 
-	// 等待中斷信號
+	// 等待中斷信號（SIGHUP 重新載入配置，不退出）
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range quit {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		reloadConfig(ctx)
+	}
 
 	logger.Info(ctx, "正在關閉服務器...", logger.WithAction("shutdown"))
 	grpcServer.Stop()
@@ -0,0 +1,95 @@
+// rewrap-master-key 用新的主密鑰重新包裝所有聊天室密鑰（離線工具）
+//
+// 使用方式（執行前請停止服務並備份 encryption_keys）：
+//
+//	export OLD_MASTER_KEY=<當前 MASTER_KEY>
+//	export NEW_MASTER_KEY=$(openssl rand -base64 32)
+//	go run ./cmd/rewrap-master-key -dry-run
+//	go run ./cmd/rewrap-master-key
+//
+// 完成後將服務的 MASTER_KEY 更新為 NEW_MASTER_KEY 再啟動。
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/driver"
+	"chat-gateway/internal/security/keymanager"
+)
+
+// rewrapTimeout 重新包裝的最長執行時間
+const rewrapTimeout = 5 * time.Minute
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Fatal error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run 分離主要邏輯以避免 exitAfterDefer 問題，確保 defer 函數正常執行.
+func run() error {
+	dryRun := flag.Bool("dry-run", false, "只驗證並統計將會變更的密鑰數量，不寫入數據庫")
+	flag.Parse()
+
+	// 主密鑰從環境變量讀取，避免出現在命令行參數和 shell 歷史中
+	oldMasterKey, err := decodeMasterKey("OLD_MASTER_KEY")
+	if err != nil {
+		return err
+	}
+	newMasterKey, err := decodeMasterKey("NEW_MASTER_KEY")
+	if err != nil {
+		return err
+	}
+
+	if err := config.Load(); err != nil {
+		return err
+	}
+	if err := driver.ConnectMongo(); err != nil {
+		return err
+	}
+	defer func() {
+		if err := driver.CloseMongo(); err != nil {
+			fmt.Fprintf(os.Stderr, "關閉 MongoDB 連接失敗: %v\n", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), rewrapTimeout)
+	defer cancel()
+
+	store := keymanager.NewKeyStore(driver.GetMongoDatabase())
+	result, err := store.RewrapMasterKey(ctx, oldMasterKey, newMasterKey, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		fmt.Printf("[DRY-RUN] 共 %d 個密鑰，%d 個將被重新包裝，未寫入數據庫\n", result.Total, result.Rewrapped)
+		return nil
+	}
+	fmt.Printf("共 %d 個密鑰，已重新包裝 %d 個；請將 MASTER_KEY 更新為 NEW_MASTER_KEY 後重啟服務\n", result.Total, result.Rewrapped)
+	return nil
+}
+
+// decodeMasterKey 從環境變量讀取 base64 編碼的 32 bytes 主密鑰
+func decodeMasterKey(name string) ([]byte, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return nil, fmt.Errorf("%s is not set", name)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not valid base64", name)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%s must be 32 bytes, got %d", name, len(key))
+	}
+	return key, nil
+}
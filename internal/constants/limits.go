@@ -21,12 +21,18 @@ const (
 	DefaultMaxRoomMembers    = 1000
 	DefaultMaxRoomNameLength = 100
 	MinRoomNameLength        = 1
+	MaxWelcomeMessageLength  = 500
+
+	DefaultMaxConcurrentRoomCreates     = 3  // 每個擁有者同時進行中的創建請求數
+	DefaultRoomCreateBurstLimit         = 10 // 每個擁有者在短時間窗口內可創建的聊天室數
+	DefaultRoomCreateBurstWindowSeconds = 10 // 短時間窗口長度（秒）
 )
 
 // 訊息相關常數
 const (
-	DefaultMaxMessageLength = 10000
-	MessageChannelBuffer    = 10
+	DefaultMaxMessageLength     = 10000
+	MessageChannelBuffer        = 10
+	DefaultMaxMessageTTLSeconds = 7 * 24 * 3600 // 限時訊息最長的存活時間（7 天）
 )
 
 // Rate Limiting 默認值
@@ -36,15 +42,112 @@ const (
 	DefaultRoomCreateRateLimit  = 10
 	DefaultSSERateLimit         = 5
 	RateLimitCleanupIntervalMin = 10 // 分鐘
+
+	RateLimitKeyIP   = "ip"   // 按客戶端 IP 計數
+	RateLimitKeyUser = "user" // 按已認證用戶 ID 計數（未認證時退回 IP）
 )
 
 // SSE 連接相關常數
 const (
-	DefaultSSEMaxConnectionsPerIP   = 3
-	DefaultSSEMaxTotalConnections   = 1000
-	DefaultSSEMinConnectionInterval = 10 // 秒
-	DefaultSSEHeartbeatInterval     = 15 // 秒
-	SSEConnectionCleanupIntervalMin = 10 // 分鐘
+	DefaultSSEMaxConnectionsPerIP           = 3
+	DefaultSSEMaxTotalConnections           = 1000
+	DefaultSSEMinConnectionInterval         = 10   // 秒
+	DefaultSSEHeartbeatInterval             = 15   // 秒
+	DefaultSSEMaxConnectionDuration         = 3600 // 單個連接的最長存活時間（秒），到期後通知客戶端重連
+	SSEConnectionCleanupIntervalMin         = 10   // 分鐘
+	StreamFetchLimit                        = 100  // 每次輪詢抓取的最新訊息數量
+	DefaultStreamSeenSetSize                = 1000 // 已推送訊息 ID 集合的最大容量
+	DefaultStreamMembershipCheckIntervalSec = 30   // 訊息流重新檢查成員資格的間隔（秒）
+	DefaultStreamPollIntervalMs             = 2000 // 訊息流輪詢新訊息的間隔（毫秒）
+	MinStreamPollIntervalMs                 = 100  // 輪詢間隔下限，避免誤配置造成資料庫壓力
+)
+
+// 聊天室歡迎訊息的發送方式
+const (
+	WelcomeMessageOnce   = "once"    // 只在創建聊天室時發送一次
+	WelcomeMessageOnJoin = "on_join" // 創建時發送，之後每位新成員加入時再次發送
+)
+
+// 擁有者離開聊天室時的處理方式
+const (
+	OwnerLeaveTransfer = "transfer" // 自動轉讓給最早加入的管理員，沒有管理員時轉讓給最早加入的成員
+	OwnerLeaveBlock    = "block"    // 拒絕離開，擁有者需要先轉讓擁有權
+)
+
+// 未讀數量的計算方式
+const (
+	UnreadCountFast    = "fast"    // 統計成員 last_read_at 之後其他人發送的訊息（索引查詢，不檢查 read_by）
+	UnreadCountPrecise = "precise" // 按每條訊息的 read_by 統計（單獨標記已讀的訊息也會扣除）
+)
+
+// 用戶聊天室列表的排序方式
+const (
+	RoomSortActivity = "activity" // 最近活動優先（默認）
+	RoomSortUnread   = "unread"   // 有未讀訊息的聊天室優先，其次按最近活動
+	RoomSortName     = "name"     // 按名稱排序
+)
+
+// 系統訊息模板的鍵（可在 limits.system_message.templates 中按語言覆蓋）
+const (
+	SystemMessageMemberJoined     = "member_joined" // 佔位符 {user}
+	SystemMessageMemberLeft       = "member_left"   // 佔位符 {user}
+	SystemMessageMemberKicked     = "member_kicked" // 佔位符 {user}
+	SystemMessageOwnerChanged     = "owner_changed" // 佔位符 {user}（新擁有者）
+	SystemMessageRoomRenamed      = "room_renamed"  // 佔位符 {name}
+	SystemMessageAvatarChanged    = "avatar_changed"
+	SystemMessageSettingsChanged  = "settings_changed"
+	SystemMessageChangeSeparator  = "change_separator" // 一次更新多項時各項之間的分隔符
+	SystemMessageModeNormal       = "mode_normal"
+	SystemMessageModeSlowmode     = "mode_slowmode" // 佔位符 {seconds}
+	SystemMessageModeAnnouncement = "mode_announcement"
+	SystemMessageModeReadOnly     = "mode_read_only"
+
+	DefaultSystemMessageLocale = "zh-tw" // 聊天室和配置都沒有指定語言，或語言缺少模板時使用
+	MaxLocaleLength            = 35      // 語言標籤（BCP 47）的最大長度
+)
+
+// 響應中的錯誤碼（HTTP 層據此返回 400 / 403 / 404）
+const (
+	ErrorCodeInvalidArgument  = "invalid_argument"
+	ErrorCodeNotFound         = "not_found"
+	ErrorCodePermissionDenied = "permission_denied"
+	ErrorCodeInternal         = "internal"
+)
+
+// SSE 慢客戶端處理策略（訊息隊列已滿時）
+const (
+	SSESlowClientDropOldest = "drop_oldest" // 丟棄最舊的訊息，並在下一條訊息前發送 skipped 事件
+	SSESlowClientDisconnect = "disconnect"  // 發送 slow_client 錯誤事件並斷開連接
+)
+
+// 異步日誌相關常數
+const (
+	DefaultLogBufferSize      = 4096     // 等待寫入的日誌條數上限，寫滿時丟棄新日誌並計數
+	DefaultLogFlushIntervalMs = 1000     // 定期刷新緩衝區的間隔（毫秒）
+	LogWriteBufferBytes       = 64 << 10 // 寫入緩衝區大小，寫滿時立即刷新
+)
+
+// 聊天室靜音相關常數
+const (
+	MaxRoomMuteDurationSec = 365 * 24 * 60 * 60 // 限時靜音的最長時間（一年），更長請使用永久靜音
+)
+
+// 成員在線狀態相關常數
+const (
+	DefaultPresenceUpdateIntervalSec = 30  // 同一用戶兩次寫入最後在線時間的最小間隔（秒）
+	DefaultPresenceOnlineWindowSec   = 120 // 最後在線時間在此窗口內的成員視為在線（秒）
+)
+
+// 發送訊息成員資格緩存相關常數
+const (
+	SenderMembershipCacheTTLSec     = 5     // 成員資格查詢結果的緩存時間（秒）
+	SenderMembershipCacheMaxEntries = 10000 // 緩存的最大條目數
+)
+
+// 聊天室訊息數量緩存相關常數
+const (
+	MessageCountCacheTTLSec     = 10    // 訊息數量的緩存時間（秒），熱門聊天室不必每次重新統計
+	MessageCountCacheMaxEntries = 10000 // 緩存的最大條目數（每個聊天室 + 過濾條件一個條目）
 )
 
 // 密鑰管理相關常數
@@ -52,6 +155,69 @@ const (
 	DefaultKeyRotationIntervalHours = 24
 	DefaultKeyMaxAgeDays            = 30
 	DefaultKeepOldKeys              = 5
+	MaxKeyWarmupRooms               = 10000 // 啟動時預加載密鑰的聊天室數量上限
+)
+
+// 已讀回執批量寫入相關常數
+const (
+	DefaultReadReceiptFlushIntervalMs = 5000
+	DefaultReadReceiptFlushCount      = 20
+)
+
+// 訊息保留期限清理相關常數
+const (
+	DefaultRetentionIntervalMinutes = 60   // 清理間隔（分鐘）
+	DefaultRetentionBatchSize       = 1000 // 每批刪除的訊息數量
+)
+
+// 發送冪等鍵相關常數
+const (
+	MaxIdempotencyKeyLength         = 128   // 冪等鍵的最大長度
+	DefaultIdempotencyWindowSeconds = 86400 // 冪等鍵的有效期（24 小時），超過後同一個鍵會創建新訊息
+)
+
+// 討論串相關常數
+const (
+	MaxThreadMessages = 1000 // 單個討論串最多追蹤的回覆數量（沿回覆鏈展開時的上限）
+	MaxThreadDepth    = 50   // 沿回覆鏈展開的最大層數
+)
+
+// 定時訊息相關常數
+const (
+	DefaultScheduledPollIntervalSeconds = 10  // 檢查到期定時訊息的間隔（秒）
+	DefaultScheduledMaxDelayDays        = 30  // 發送時間最多可設定在多少天之後
+	DefaultScheduledMaxPendingPerUser   = 100 // 每個用戶待發送的定時訊息上限
+	ScheduledClaimTimeoutMinutes        = 5   // 領取後超過此時間仍未完成的定時訊息會被重新領取
+)
+
+// gRPC 客戶端連接相關常數
+const (
+	DefaultGRPCKeepaliveTimeSeconds     = 30 // 連接空閒多久後發送 keepalive ping（秒）
+	DefaultGRPCKeepaliveTimeoutSeconds  = 10 // 等待 ping 回應的時間（秒）
+	MinGRPCKeepaliveTimeSeconds         = 10 // gRPC 允許的最短 keepalive 間隔（秒）
+	DefaultGRPCMaxRecvMsgSizeMB         = 4  // 單個響應的最大大小（與 gRPC 默認值相同）
+	DefaultGRPCMaxRetryAttempts         = 3  // 返回 UNAVAILABLE 時的最多嘗試次數（包括第一次）
+	MaxGRPCRetryAttempts                = 5  // gRPC 重試策略允許的最多嘗試次數
+	DefaultGRPCReconnectMaxDelaySeconds = 5  // 斷線後重新連接的最長退避間隔（秒，gRPC 默認為 120 秒）
+	DefaultGRPCCallTimeoutSeconds       = 10 // HTTP 處理器單次調用 gRPC 服務的超時時間（秒）
+)
+
+// HTTP 處理器調用 gRPC 服務的重試相關常數（只用於冪等的調用）
+const (
+	HTTPGRPCRetryAttempts    = 3    // 最多嘗試次數（包括第一次）
+	HTTPGRPCRetryBaseDelayMs = 200  // 第一次重試前的等待時間（毫秒），之後每次加倍
+	HTTPGRPCRetryMaxDelayMs  = 2000 // 單次等待時間上限（毫秒）
+)
+
+// 批量導入相關常數
+const (
+	MaxImportBatchSize = 1000 // 每次導入請求最多包含的聊天室或訊息數量
+	ImportChunkSize    = 200  // 每次 InsertMany 寫入的文檔數量
+)
+
+// 用戶數據刪除相關常數
+const (
+	DefaultErasureBatchSize = 500 // 每批匿名化/刪除的訊息數量
 )
 
 // MongoDB 查詢相關常數
@@ -61,6 +227,8 @@ const (
 	MaxMongoHistoryLimit   = 50
 	DefaultUserRoomsLimit  = 100
 	MaxStreamMessagesLimit = 1000
+
+	DefaultMongoSlowQueryThresholdMs = 500 // 超過此耗時的 MongoDB 命令記錄為慢查詢
 )
 
 // 用戶 ID 相關常數
@@ -72,4 +240,12 @@ const (
 const (
 	EncryptedPrefixLength = 10
 	MasterKeyLength       = 32 // 256 bits
+	MinJWTSecretLength    = 32 // HS256 密鑰至少 256 bits
+)
+
+// HTTP 安全標頭相關常數
+const (
+	DefaultContentSecurityPolicy = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; " +
+		"img-src 'self' data: https:; font-src 'self'; connect-src 'self'; frame-ancestors 'none';"
+	DefaultHSTSMaxAgeSeconds = 365 * 24 * 60 * 60 // 一年（HSTS preload 的最低要求）
 )
@@ -0,0 +1,183 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/proto/chat"
+
+	"google.golang.org/grpc/status"
+)
+
+// chatSession 單個雙向聊天流的狀態
+// 發送確認與推送新訊息來自不同 goroutine，stream.Send 需要串行化
+type chatSession struct {
+	stream      chat.ChatRoomService_ChatServer
+	sendMessage func(ctx context.Context, req *chat.SendMessageRequest) (*chat.SendMessageResponse, error)
+	subscribe   func(ctx context.Context, req *chat.StreamMessagesRequest, send func(*chat.ChatMessage) error, seen *seenMessageSet) error
+	ackEnabled  bool
+
+	sendMu   sync.Mutex
+	sequence int64
+
+	seen         *seenMessageSet             // 本流已發送或已推送的訊息，避免回顯自己的訊息
+	subscription *chat.StreamMessagesRequest // 已訂閱的聊天室（未訂閱時為 nil）
+}
+
+// Chat 雙向聊天流
+// 客戶端透過 subscribe 訂閱聊天室、透過 send 發送訊息；
+// 服務端對每條訊息回傳確認（服務端 ID、創建時間、序號），並推送聊天室新訊息
+func (s *Server) Chat(stream chat.ChatRoomService_ChatServer) error {
+	ackEnabled := true
+	if cfg := config.Get(); cfg != nil {
+		ackEnabled = cfg.GRPC.ChatAckEnabled
+	}
+
+	_, seenSetSize := streamSeenSetLimits()
+	session := &chatSession{
+		stream:      stream,
+		sendMessage: s.SendMessage,
+		subscribe:   s.pollRoomMessages,
+		ackEnabled:  ackEnabled,
+		seen:        newSeenMessageSet(seenSetSize),
+	}
+	err := session.run()
+
+	// 連線結束時寫入該用戶的已讀回執
+	if sub := session.subscription; sub != nil {
+		s.flushReadReceipts(sub.RoomId, sub.UserId)
+	}
+	return err
+}
+
+// pollRoomMessages 接收聊天室新訊息並推送（與 StreamMessages 共用聊天室分發中心）
+func (s *Server) pollRoomMessages(
+	ctx context.Context,
+	req *chat.StreamMessagesRequest,
+	send func(*chat.ChatMessage) error,
+	seen *seenMessageSet,
+) error {
+	membership, err := s.verifyStreamMembership(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return s.streamRoomMessages(ctx, req, send, seen, membership)
+}
+
+// run 處理客戶端事件直到流結束
+func (c *chatSession) run() error {
+	ctx, cancel := context.WithCancel(c.stream.Context())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+
+	for {
+		req, err := c.stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch payload := req.Payload.(type) {
+		case *chat.ChatStreamRequest_Subscribe:
+			if c.subscription != nil || payload.Subscribe == nil {
+				continue
+			}
+
+			streamReq := &chat.StreamMessagesRequest{
+				RoomId: payload.Subscribe.RoomId,
+				UserId: payload.Subscribe.UserId,
+			}
+			c.subscription = streamReq
+			logger.Info(ctx, "聊天流訂閱聊天室",
+				logger.WithUserID(streamReq.UserId),
+				logger.WithRoomID(streamReq.RoomId))
+
+			go func() {
+				if err := c.subscribe(ctx, streamReq, c.pushMessage, c.seen); err != nil {
+					errCh <- err
+					cancel()
+				}
+			}()
+
+		case *chat.ChatStreamRequest_Send:
+			if err := c.handleSend(ctx, payload.Send); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case err := <-errCh:
+			return err
+		default:
+		}
+	}
+}
+
+// handleSend 發送訊息並回傳確認
+func (c *chatSession) handleSend(ctx context.Context, send *chat.ChatSend) error {
+	if send == nil || send.Message == nil {
+		return c.sendAck(&chat.MessageAck{
+			ClientMessageId: send.GetClientMessageId(),
+			Success:         false,
+			Message:         "訊息內容不能為空",
+		})
+	}
+
+	resp, err := c.sendMessage(ctx, send.Message)
+	if err != nil {
+		return c.sendAck(&chat.MessageAck{
+			ClientMessageId: send.ClientMessageId,
+			Success:         false,
+			Message:         status.Convert(err).Message(),
+		})
+	}
+
+	ack := &chat.MessageAck{
+		ClientMessageId: send.ClientMessageId,
+		Success:         resp.Success,
+		Message:         resp.Message,
+	}
+	if resp.ChatMessage != nil {
+		ack.MessageId = resp.ChatMessage.Id
+		ack.CreatedAt = resp.ChatMessage.CreatedAt
+
+		// 自己發送的訊息已經透過確認告知，不再作為新訊息推送
+		c.seen.Add(resp.ChatMessage.Id)
+	}
+
+	return c.sendAck(ack)
+}
+
+// sendAck 回傳確認（未啟用確認時只在失敗時回傳）
+func (c *chatSession) sendAck(ack *chat.MessageAck) error {
+	if !c.ackEnabled && ack.Success {
+		return nil
+	}
+
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	c.sequence++
+	ack.Sequence = c.sequence
+	return c.stream.Send(&chat.ChatStreamResponse{
+		Payload: &chat.ChatStreamResponse_Ack{Ack: ack},
+	})
+}
+
+// pushMessage 推送聊天室新訊息
+func (c *chatSession) pushMessage(msg *chat.ChatMessage) error {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	return c.stream.Send(&chat.ChatStreamResponse{
+		Payload: &chat.ChatStreamResponse_Message{Message: msg},
+	})
+}
@@ -0,0 +1,195 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chat-gateway/proto/chat"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// chatStreamTestServer 使用假的發送邏輯運行 chatSession，不依賴數據庫
+type chatStreamTestServer struct {
+	chat.UnimplementedChatRoomServiceServer
+	ackEnabled bool
+	nextID     atomic.Int64
+}
+
+func (s *chatStreamTestServer) Chat(stream chat.ChatRoomService_ChatServer) error {
+	session := &chatSession{
+		stream: stream,
+		sendMessage: func(ctx context.Context, req *chat.SendMessageRequest) (*chat.SendMessageResponse, error) {
+			if req.Content == "" {
+				return &chat.SendMessageResponse{Success: false, Message: "消息內容不能為空"}, nil
+			}
+			id := s.nextID.Add(1)
+			return &chat.SendMessageResponse{
+				Success: true,
+				Message: "消息發送成功",
+				ChatMessage: &chat.ChatMessage{
+					Id:        fmt.Sprintf("server-%d", id),
+					RoomId:    req.RoomId,
+					SenderId:  req.SenderId,
+					Content:   req.Content,
+					CreatedAt: 1700000000 + id,
+				},
+			}, nil
+		},
+		subscribe: func(ctx context.Context, req *chat.StreamMessagesRequest, send func(*chat.ChatMessage) error, seen *seenMessageSet) error {
+			// 推送一條其他成員的訊息
+			return send(&chat.ChatMessage{Id: "inbound-1", RoomId: req.RoomId, SenderId: "other", Content: "hi"})
+		},
+		ackEnabled: s.ackEnabled,
+		seen:       newSeenMessageSet(100),
+	}
+	return session.run()
+}
+
+func newChatStreamTestClient(t *testing.T, ackEnabled bool) chat.ChatRoomServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	chat.RegisterChatRoomServiceServer(srv, &chatStreamTestServer{ackEnabled: ackEnabled})
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return chat.NewChatRoomServiceClient(conn)
+}
+
+func sendChat(t *testing.T, stream chat.ChatRoomService_ChatClient, clientID, content string) {
+	t.Helper()
+	err := stream.Send(&chat.ChatStreamRequest{
+		Payload: &chat.ChatStreamRequest_Send{Send: &chat.ChatSend{
+			ClientMessageId: clientID,
+			Message:         &chat.SendMessageRequest{RoomId: "room-1", SenderId: "user-1", Content: content, Type: "text"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+}
+
+func TestChatStream_AckRoundTrip(t *testing.T) {
+	client := newChatStreamTestClient(t, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Chat(ctx)
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		sendChat(t, stream, fmt.Sprintf("client-%d", i), fmt.Sprintf("message %d", i))
+
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv failed: %v", err)
+		}
+
+		ack := resp.GetAck()
+		if ack == nil {
+			t.Fatalf("Expected ack, got %v", resp)
+		}
+		if !ack.Success {
+			t.Errorf("Expected successful ack, got %q", ack.Message)
+		}
+		if ack.ClientMessageId != fmt.Sprintf("client-%d", i) {
+			t.Errorf("Ack client ID mismatch: %s", ack.ClientMessageId)
+		}
+		if ack.MessageId != fmt.Sprintf("server-%d", i) {
+			t.Errorf("Ack server ID mismatch: %s", ack.MessageId)
+		}
+		if ack.CreatedAt == 0 {
+			t.Error("Ack should carry created_at")
+		}
+		if ack.Sequence != int64(i) {
+			t.Errorf("Ack sequence: want %d, got %d", i, ack.Sequence)
+		}
+	}
+
+	// 失敗的發送也會收到確認
+	sendChat(t, stream, "client-empty", "")
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if ack := resp.GetAck(); ack == nil || ack.Success || ack.ClientMessageId != "client-empty" || ack.Sequence != 4 {
+		t.Errorf("Expected failed ack with sequence 4, got %v", resp)
+	}
+
+	_ = stream.CloseSend()
+}
+
+func TestChatStream_SubscribeReceivesInbound(t *testing.T) {
+	client := newChatStreamTestClient(t, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Chat(ctx)
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	err = stream.Send(&chat.ChatStreamRequest{
+		Payload: &chat.ChatStreamRequest_Subscribe{Subscribe: &chat.ChatSubscribe{RoomId: "room-1", UserId: "user-1"}},
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if msg := resp.GetMessage(); msg == nil || msg.Id != "inbound-1" {
+		t.Errorf("Expected inbound message, got %v", resp)
+	}
+}
+
+func TestChatStream_AckDisabled(t *testing.T) {
+	client := newChatStreamTestClient(t, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Chat(ctx)
+	if err != nil {
+		t.Fatalf("Chat failed: %v", err)
+	}
+
+	// 關閉確認時成功的訊息不回傳確認，失敗的仍然回傳
+	sendChat(t, stream, "client-ok", "hello")
+	sendChat(t, stream, "client-empty", "")
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if ack := resp.GetAck(); ack == nil || ack.ClientMessageId != "client-empty" || ack.Success {
+		t.Errorf("Expected only the failure ack, got %v", resp)
+	}
+}
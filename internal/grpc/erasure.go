@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/proto/chat"
+)
+
+// canEraseUserData 是否允許刪除用戶數據：本人或配置的數據保護管理員
+// 啟用 JWT 時 requester 必須是已認證的用戶，避免冒充管理員
+func canEraseUserData(authUserID, requesterID, userID string, adminIDs []string) bool {
+	if requesterID == "" || (authUserID != "" && authUserID != requesterID) {
+		return false
+	}
+	return requesterID == userID || slices.Contains(adminIDs, requesterID)
+}
+
+// erasureBatchSize 每批匿名化/刪除的訊息數量
+func erasureBatchSize() int {
+	if cfg := config.Get(); cfg != nil && cfg.Security.DataProtection.ErasureBatchSize > 0 {
+		return cfg.Security.DataProtection.ErasureBatchSize
+	}
+	return constants.DefaultErasureBatchSize
+}
+
+// userDataErasurePlan 刪除前統計的用戶數據
+type userDataErasurePlan struct {
+	messages      int64    // 用戶發送的訊息
+	references    int64    // 含有用戶回執、提及或轉發來源的訊息
+	roomIDs       []string // 用戶所在的聊天室
+	directRoomIDs []string // 將被整個刪除的私聊（只剩該用戶一個成員）
+}
+
+// planUserDataErasure 統計將受影響的用戶數據（試運行和實際刪除共用）
+func (s *Server) planUserDataErasure(ctx context.Context, userID string, deleteDirectRooms bool) (*userDataErasurePlan, error) {
+	plan := &userDataErasurePlan{}
+	var err error
+
+	if plan.messages, err = s.repos.Message.CountUserMessages(ctx, userID); err != nil {
+		return nil, err
+	}
+	if plan.references, err = s.repos.Message.CountUserReferences(ctx, userID); err != nil {
+		return nil, err
+	}
+	if plan.roomIDs, err = s.repos.ChatRoom.ListUserRoomIDs(ctx, userID); err != nil {
+		return nil, err
+	}
+	if deleteDirectRooms {
+		if plan.directRoomIDs, err = s.repos.ChatRoom.ListSoleMemberDirectRooms(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// eraseUserData 按計劃刪除用戶數據，把實際處理的數量寫入 resp（失敗時保留已完成部分的數量）
+// 先刪除私聊，剩餘的訊息再匿名化，最後把用戶從成員列表中移除
+func (s *Server) eraseUserData(ctx context.Context, req *chat.DeleteUserDataRequest, plan *userDataErasurePlan, resp *chat.DeleteUserDataResponse) error {
+	for _, roomID := range plan.directRoomIDs {
+		deletedMessages, deletedKeys, err := s.deleteRoomCascade(ctx, roomID)
+		if err != nil {
+			return err
+		}
+		s.audit.LogRoomDeletion(ctx, req.RequesterId, roomID, deletedMessages, deletedKeys)
+		resp.DirectRoomsDeleted++
+	}
+
+	// 未發送的定時訊息直接刪除，避免刪除後仍以該用戶名義發出
+	if _, err := s.repos.Scheduled.DeleteBySender(ctx, req.UserId); err != nil {
+		return err
+	}
+
+	batchSize := erasureBatchSize()
+	var err error
+	if resp.MessagesAnonymized, err = s.repos.Message.AnonymizeUserMessages(ctx, req.UserId, batchSize); err != nil {
+		return err
+	}
+	if resp.ReferencesRemoved, err = s.repos.Message.RemoveUserReferences(ctx, req.UserId, batchSize); err != nil {
+		return err
+	}
+	if resp.RoomsLeft, err = s.repos.ChatRoom.RemoveUserFromAllRooms(ctx, req.UserId); err != nil {
+		return err
+	}
+
+	// 已移除的成員不能再使用緩存的成員資格發送訊息
+	if s.senderMembership != nil {
+		for _, roomID := range plan.roomIDs {
+			s.senderMembership.Invalidate(roomID, req.UserId)
+		}
+	}
+	return nil
+}
+
+// DeleteUserData 刪除用戶數據（GDPR 被遺忘權）
+// 用戶的訊息替換為佔位訊息，已讀/送達回執和提及被刪除，並把用戶從所有聊天室移除；
+// dry_run 時只返回將受影響的數量
+func (s *Server) DeleteUserData(ctx context.Context, req *chat.DeleteUserDataRequest) (*chat.DeleteUserDataResponse, error) {
+	cfg := config.Get()
+	if cfg == nil || !cfg.Security.DataProtection.RightToErasure {
+		return &chat.DeleteUserDataResponse{
+			Success: false,
+			Message: "未啟用用戶數據刪除",
+		}, nil
+	}
+	if req.UserId == "" || req.RequesterId == "" {
+		return &chat.DeleteUserDataResponse{
+			Success: false,
+			Message: "缺少 user_id 或 requester_id",
+		}, nil
+	}
+
+	if !canEraseUserData(middleware.UserIDFromContext(ctx), req.RequesterId, req.UserId, cfg.Security.DataProtection.ErasureAdminIDs) {
+		s.audit.LogAccessDenied(ctx, req.RequesterId, "", "delete user data: not the user or a data protection admin")
+		return &chat.DeleteUserDataResponse{
+			Success: false,
+			Message: "只有本人或數據保護管理員可以刪除用戶數據",
+		}, nil
+	}
+
+	plan, err := s.planUserDataErasure(ctx, req.UserId, req.DeleteDirectRooms)
+	if err != nil {
+		logErrorWithUser(ctx, "統計用戶數據失敗", req.UserId, err)
+		return &chat.DeleteUserDataResponse{
+			Success: false,
+			Message: "統計用戶數據失敗",
+		}, nil
+	}
+
+	if req.DryRun {
+		return &chat.DeleteUserDataResponse{
+			Success:            true,
+			Message:            "試運行：以下數據將被刪除",
+			DryRun:             true,
+			MessagesAnonymized: plan.messages,
+			ReferencesRemoved:  plan.references,
+			RoomsLeft:          int64(len(plan.roomIDs)),
+			DirectRoomsDeleted: int64(len(plan.directRoomIDs)),
+		}, nil
+	}
+
+	resp := &chat.DeleteUserDataResponse{}
+	err = s.eraseUserData(ctx, req, plan, resp)
+	details := map[string]interface{}{
+		"requester_id":         req.RequesterId,
+		"user_id":              req.UserId,
+		"messages_anonymized":  resp.MessagesAnonymized,
+		"references_removed":   resp.ReferencesRemoved,
+		"rooms_left":           resp.RoomsLeft,
+		"direct_rooms_deleted": resp.DirectRoomsDeleted,
+	}
+	if err != nil {
+		logErrorWithUser(ctx, "刪除用戶數據失敗", req.UserId, err)
+		s.audit.LogSecurityEvent(ctx, "user_data_erasure_failed", "刪除用戶數據失敗（部分數據可能已刪除）", "high", details)
+		resp.Success = false
+		resp.Message = "刪除用戶數據失敗，可以重試以完成剩餘部分"
+		return resp, nil
+	}
+
+	s.audit.LogSecurityEvent(ctx, "user_data_erased", "刪除用戶數據（被遺忘權）", "high", details)
+	logger.Info(ctx, "刪除用戶數據成功",
+		logger.WithUserID(req.UserId),
+		logger.WithAction("delete_user_data"),
+		logger.WithDetails(details))
+
+	resp.Success = true
+	resp.Message = "用戶數據已刪除"
+	return resp, nil
+}
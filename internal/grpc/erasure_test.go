@@ -0,0 +1,31 @@
+package grpc
+
+import "testing"
+
+func TestCanEraseUserData(t *testing.T) {
+	admins := []string{"dpo"}
+
+	tests := []struct {
+		name       string
+		authUserID string
+		requester  string
+		userID     string
+		want       bool
+	}{
+		{"self without jwt", "", "alice", "alice", true},
+		{"self with jwt", "alice", "alice", "alice", true},
+		{"other user", "", "bob", "alice", false},
+		{"admin", "dpo", "dpo", "alice", true},
+		{"admin impersonated", "bob", "dpo", "alice", false},
+		{"self impersonated", "bob", "alice", "alice", false},
+		{"missing requester", "", "", "alice", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canEraseUserData(tt.authUserID, tt.requester, tt.userID, admins); got != tt.want {
+				t.Errorf("canEraseUserData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// ForwardMessage 轉發訊息到另一個聊天室
+// 轉發者必須是來源聊天室的成員（可以讀取）和目標聊天室的成員（可以發言），
+// 內容在服務端解密後以目標聊天室的密鑰重新加密
+func (s *Server) ForwardMessage(ctx context.Context, req *chat.ForwardMessageRequest) (*chat.ForwardMessageResponse, error) {
+	if req.UserId == "" || req.SourceRoomId == "" || req.MessageId == "" || req.TargetRoomId == "" {
+		return &chat.ForwardMessageResponse{
+			Success: false,
+			Message: "缺少 user_id、source_room_id、message_id 或 target_room_id",
+		}, nil
+	}
+
+	// 啟用 JWT 時轉發者必須是認證用戶，來源和目標聊天室的成員資格都按認證用戶檢查
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.SourceRoomId, "forward message: user is not the authenticated user")
+		return &chat.ForwardMessageResponse{
+			Success: false,
+			Message: "只能以自己的身份轉發訊息",
+		}, nil
+	}
+
+	// 訊息必須屬於請求中的來源聊天室，避免借由其他聊天室的成員資格讀取訊息
+	source, err := s.repos.Message.GetByID(ctx, req.MessageId)
+	if err != nil || source.RoomID != req.SourceRoomId || source.Expired(time.Now()) {
+		return &chat.ForwardMessageResponse{
+			Success: false,
+			Message: "訊息不存在",
+		}, nil
+	}
+	if source.Type == systemSenderID {
+		return &chat.ForwardMessageResponse{
+			Success: false,
+			Message: "系統訊息不能轉發",
+		}, nil
+	}
+	// 轉發的副本不會過期，限時訊息轉發後會比原訊息保留更久
+	if source.ExpiresAt != nil {
+		return &chat.ForwardMessageResponse{
+			Success: false,
+			Message: "限時訊息不能轉發",
+		}, nil
+	}
+
+	isMember, err := s.repos.ChatRoom.IsMember(ctx, req.SourceRoomId, req.UserId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "檢查來源聊天室成員資格失敗", req.UserId, req.SourceRoomId, err)
+		return &chat.ForwardMessageResponse{
+			Success: false,
+			Message: "檢查成員資格失敗",
+		}, nil
+	}
+	if !isMember {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.SourceRoomId, "forward message: not a member of source room")
+		return &chat.ForwardMessageResponse{
+			Success: false,
+			Message: "您不是來源聊天室的成員",
+		}, nil
+	}
+
+	content, err := s.encryption.DecryptMessageWithVersion(source.Content, source.RoomID, source.KeyVersion())
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "解密轉發來源訊息失敗", req.UserId, req.SourceRoomId, err)
+		return &chat.ForwardMessageResponse{
+			Success: false,
+			Message: "轉發訊息失敗",
+		}, nil
+	}
+
+	sendReq := &chat.SendMessageRequest{
+		RoomId:   req.TargetRoomId,
+		SenderId: req.UserId,
+		Content:  content,
+		Type:     source.Type,
+		Metadata: metadataToGRPC(&source.Metadata),
+	}
+	if err := s.checkSenderMembership(ctx, sendReq); err != nil {
+		return &chat.ForwardMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	message, _, err := s.createEncryptedMessage(ctx, sendReq, forwardSourceOf(source))
+	if err != nil {
+		return &chat.ForwardMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	s.updateRoomLastMessage(ctx, sendReq, &message)
+	s.touchPresence(ctx, req.UserId)
+
+	// 審計和日誌
+	s.audit.LogMessageSent(ctx, req.UserId, req.TargetRoomId, message.GetID(), sendReq.Type)
+	logger.Info(ctx, "轉發消息成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.TargetRoomId),
+		logger.WithMessageID(message.GetID()),
+		logger.WithAction("forward_message"),
+		logger.WithDetails(map[string]interface{}{
+			"source_room_id":    req.SourceRoomId,
+			"source_message_id": req.MessageId,
+		}))
+
+	return &chat.ForwardMessageResponse{
+		Success:     true,
+		Message:     "消息轉發成功",
+		ChatMessage: s.buildMessageResponse(ctx, &message),
+	}, nil
+}
+
+// forwardSourceOf 轉發來源；轉發已轉發的訊息時保留最初的來源
+func forwardSourceOf(source *chatroom.Message) []string {
+	if _, _, _, ok := source.ForwardSource(); ok {
+		return source.ForwardedFrom
+	}
+	forwarded := chatroom.Message{}
+	forwarded.SetForwardedFrom(source.RoomID, source.GetID(), source.SenderID)
+	return forwarded.ForwardedFrom
+}
+
+// forwardedFromToGRPC 轉換轉發來源為 gRPC 格式，不是轉發的訊息時返回 nil
+func forwardedFromToGRPC(message *chatroom.Message) *chat.ForwardedFrom {
+	roomID, messageID, senderID, ok := message.ForwardSource()
+	if !ok {
+		return nil
+	}
+	return &chat.ForwardedFrom{
+		RoomId:    roomID,
+		MessageId: messageID,
+		SenderId:  senderID,
+	}
+}
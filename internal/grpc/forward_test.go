@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+func TestForwardSourceOf(t *testing.T) {
+	original := chatroom.Message{ID: "msg-1", RoomID: "room-1", SenderID: "alice"}
+
+	forwarded := chatroom.Message{ForwardedFrom: forwardSourceOf(&original)}
+	got := forwardedFromToGRPC(&forwarded)
+	if got.GetRoomId() != "room-1" || got.GetMessageId() != "msg-1" || got.GetSenderId() != "alice" {
+		t.Fatalf("forwardedFromToGRPC() = %v", got)
+	}
+
+	// 再次轉發時保留最初的來源
+	forwarded.ID = "msg-2"
+	forwarded.RoomID = "room-2"
+	forwarded.SenderID = "bob"
+	again := chatroom.Message{ForwardedFrom: forwardSourceOf(&forwarded)}
+	if got := forwardedFromToGRPC(&again); got.GetMessageId() != "msg-1" || got.GetSenderId() != "alice" {
+		t.Errorf("re-forwarded source = %v, want original message", got)
+	}
+
+	if forwardedFromToGRPC(&original) != nil {
+		t.Error("expected nil forwarded_from for regular messages")
+	}
+}
+
+func TestForwardMessage_RejectsImpersonatedUser(t *testing.T) {
+	// 認證用戶借用成員的 ID：應在讀取來源訊息之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.ForwardMessage(ctx, &chat.ForwardMessageRequest{
+		UserId:       "alice",
+		SourceRoomId: "507f1f77bcf86cd799439011",
+		MessageId:    "507f1f77bcf86cd799439012",
+		TargetRoomId: "507f1f77bcf86cd799439013",
+	})
+	if err != nil || resp.Success {
+		t.Errorf("ForwardMessage() = %+v, %v; want Success=false", resp, err)
+	}
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// GetMessage 獲取單條訊息（解密後返回，包含元數據和已讀回執）
+// 只有訊息所在聊天室的成員可以讀取（啟用 JWT 時為認證用戶）；訊息不存在或已過期時返回 not_found，非成員返回 permission_denied
+func (s *Server) GetMessage(ctx context.Context, req *chat.GetMessageRequest) (*chat.GetMessageResponse, error) {
+	if err := database.ValidateObjectID(req.MessageId); err != nil {
+		return getMessageFailure(constants.ErrorCodeInvalidArgument, "無效的訊息 ID"), nil
+	}
+	if err := middleware.ValidateUserID(req.UserId); err != nil {
+		return getMessageFailure(constants.ErrorCodeInvalidArgument, err.Error()), nil
+	}
+	// 啟用 JWT 時按認證用戶檢查成員資格，避免借用其他成員的 ID 讀取訊息
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), "", "get message: user is not the authenticated user")
+		return getMessageFailure(constants.ErrorCodePermissionDenied, "您不是該聊天室的成員"), nil
+	}
+
+	message, err := s.repos.Message.GetByID(ctx, req.MessageId)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && message.Expired(time.Now())) {
+		return getMessageFailure(constants.ErrorCodeNotFound, "訊息不存在"), nil
+	}
+	if err != nil {
+		logger.Error(ctx, "獲取訊息失敗",
+			logger.WithMessageID(req.MessageId),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return getMessageFailure(constants.ErrorCodeInternal, "獲取訊息失敗"), nil
+	}
+
+	isMember, err := s.repos.ChatRoom.IsMember(ctx, message.RoomID, req.UserId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "檢查成員資格失敗", req.UserId, message.RoomID, err)
+		return getMessageFailure(constants.ErrorCodeInternal, "檢查成員資格失敗"), nil
+	}
+	if !isMember {
+		s.audit.LogAccessDenied(ctx, req.UserId, message.RoomID, "get message: not a member")
+		return getMessageFailure(constants.ErrorCodePermissionDenied, "您不是該聊天室的成員"), nil
+	}
+
+	grpcMessages := s.convertMessagesToGRPC(ctx, []*chatroom.Message{message})
+	s.applyAggregateStatus(ctx, message.RoomID, []*chatroom.Message{message}, grpcMessages)
+
+	logger.Debug(ctx, "獲取訊息成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(message.RoomID),
+		logger.WithMessageID(req.MessageId),
+		logger.WithAction("get_message"))
+
+	return &chat.GetMessageResponse{
+		Success:     true,
+		Message:     "獲取訊息成功",
+		ChatMessage: grpcMessages[0],
+	}, nil
+}
+
+// getMessageFailure 構建失敗響應
+func getMessageFailure(code, message string) *chat.GetMessageResponse {
+	return &chat.GetMessageResponse{
+		Success:   false,
+		Message:   message,
+		ErrorCode: code,
+	}
+}
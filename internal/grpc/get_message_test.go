@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/proto/chat"
+)
+
+func TestGetMessage_InvalidArguments(t *testing.T) {
+	s := &Server{}
+
+	tests := []struct {
+		name string
+		req  *chat.GetMessageRequest
+	}{
+		{"invalid message id", &chat.GetMessageRequest{MessageId: "not-an-id", UserId: "user_alice"}},
+		{"empty message id", &chat.GetMessageRequest{UserId: "user_alice"}},
+		{"missing user id", &chat.GetMessageRequest{MessageId: "507f1f77bcf86cd799439011"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetMessage(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("GetMessage returned error: %v", err)
+			}
+			if resp.Success || resp.ErrorCode != constants.ErrorCodeInvalidArgument {
+				t.Errorf("GetMessage() = success %v, code %q, want invalid_argument", resp.Success, resp.ErrorCode)
+			}
+		})
+	}
+}
+
+func TestGetMessage_RejectsImpersonatedMember(t *testing.T) {
+	// 認證用戶借用成員的 ID：應在讀取訊息之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.GetMessage(ctx, &chat.GetMessageRequest{
+		MessageId: "507f1f77bcf86cd799439011",
+		UserId:    "user_alice",
+	})
+	if err != nil {
+		t.Fatalf("GetMessage returned error: %v", err)
+	}
+	if resp.Success || resp.ErrorCode != constants.ErrorCodePermissionDenied {
+		t.Errorf("GetMessage() = success %v, code %q, want permission_denied", resp.Success, resp.ErrorCode)
+	}
+}
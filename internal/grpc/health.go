@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/proto/chat"
+
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// healthCheckInterval 檢查依賴並更新 gRPC 健康狀態的間隔
+const healthCheckInterval = 5 * time.Second
+
+// healthReporter 定期檢查依賴並更新標準 gRPC 健康服務（grpc.health.v1）的狀態
+// 依賴不可用時回報 NOT_SERVING，讓負載均衡器把流量從本實例移走
+type healthReporter struct {
+	server   *grpchealth.Server
+	check    func() error
+	interval time.Duration
+
+	last     healthpb.HealthCheckResponse_ServingStatus
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newHealthReporter 創建 gRPC 健康狀態回報器（啟動前所有服務都是 NOT_SERVING）
+func newHealthReporter(check func() error, interval time.Duration) *healthReporter {
+	r := &healthReporter{
+		server:   grpchealth.NewServer(),
+		check:    check,
+		interval: interval,
+		last:     healthpb.HealthCheckResponse_NOT_SERVING,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	r.setStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return r
+}
+
+// Start 立即檢查一次，之後定期更新狀態
+func (r *healthReporter) Start() {
+	go func() {
+		defer close(r.done)
+
+		r.update()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.update()
+			}
+		}
+	}()
+}
+
+// Stop 停止定期檢查，並將所有服務標記為 NOT_SERVING（關閉前讓負載均衡器摘除本實例）
+func (r *healthReporter) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+		r.server.Shutdown()
+	})
+}
+
+// update 檢查依賴並更新狀態，狀態變化時記錄日誌
+func (r *healthReporter) update() {
+	status := healthpb.HealthCheckResponse_SERVING
+	err := r.check()
+	if err != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	if status != r.last {
+		ctx := context.Background()
+		if err != nil {
+			logger.Warning(ctx, "依賴不可用，gRPC 健康狀態設為 NOT_SERVING",
+				logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		} else {
+			logger.Info(ctx, "依賴已恢復，gRPC 健康狀態設為 SERVING")
+		}
+		r.last = status
+	}
+
+	r.setStatus(status)
+}
+
+// setStatus 同時更新整體狀態（空服務名）和聊天室服務的狀態
+func (r *healthReporter) setStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	r.server.SetServingStatus("", status)
+	r.server.SetServingStatus(chat.ChatRoomService_ServiceDesc.ServiceName, status)
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-gateway/proto/chat"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func servingStatus(t *testing.T, r *healthReporter, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := r.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check(%q): %v", service, err)
+	}
+	return resp.Status
+}
+
+func TestHealthReporter(t *testing.T) {
+	var dbErr error
+	r := newHealthReporter(func() error { return dbErr }, time.Hour)
+	service := chat.ChatRoomService_ServiceDesc.ServiceName
+
+	if got := servingStatus(t, r, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("before first check = %v, want NOT_SERVING", got)
+	}
+
+	r.update()
+	if got := servingStatus(t, r, service); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("database reachable = %v, want SERVING", got)
+	}
+
+	dbErr = errors.New("connection lost")
+	r.update()
+	if got := servingStatus(t, r, ""); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("database unreachable = %v, want NOT_SERVING", got)
+	}
+
+	dbErr = nil
+	r.Start()
+	r.Stop()
+	r.Stop() // 重複調用不應 panic
+	if got := servingStatus(t, r, service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("after Stop = %v, want NOT_SERVING", got)
+	}
+}
@@ -0,0 +1,23 @@
+package grpc
+
+import (
+	"context"
+
+	"chat-gateway/internal/platform/middleware"
+)
+
+// actingAsAuthenticatedUser 請求中的操作者是否為 JWT 認證的用戶
+// 未啟用 JWT（context 中沒有認證用戶）時信任請求中的用戶 ID，規則與 canEraseUserData 相同
+func actingAsAuthenticatedUser(ctx context.Context, userID string) bool {
+	authUserID := middleware.UserIDFromContext(ctx)
+	return authUserID == "" || authUserID == userID
+}
+
+// rejectImpersonation 請求中的用戶不是 JWT 認證的用戶時記錄審計日誌並返回 true
+func (s *Server) rejectImpersonation(ctx context.Context, userID, roomID, action string) bool {
+	if actingAsAuthenticatedUser(ctx, userID) {
+		return false
+	}
+	s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), roomID, action+": user is not the authenticated user")
+	return true
+}
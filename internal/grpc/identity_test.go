@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/proto/chat"
+)
+
+// successResponse 各 RPC 響應共有的 Success 字段
+type successResponse interface {
+	GetSuccess() bool
+}
+
+func TestActingAsAuthenticatedUser(t *testing.T) {
+	if !actingAsAuthenticatedUser(context.Background(), "alice") {
+		t.Error("Expected request user to be trusted without JWT")
+	}
+
+	ctx := middleware.ContextWithUserID(context.Background(), "alice")
+	if !actingAsAuthenticatedUser(ctx, "alice") {
+		t.Error("Expected the authenticated user to be accepted")
+	}
+	if actingAsAuthenticatedUser(ctx, "bob") {
+		t.Error("Expected a different user to be rejected")
+	}
+}
+
+func TestUserScopedRPCs_RejectImpersonatedUser(t *testing.T) {
+	// 認證用戶借用其他用戶的 ID：應在讀取倉儲之前被拒絕（Server 沒有倉儲，讀取會 panic）
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	const roomID = "507f1f77bcf86cd799439011"
+
+	calls := map[string]func() (successResponse, error){
+		"CreateRoom": func() (successResponse, error) {
+			return s.CreateRoom(ctx, &chat.CreateRoomRequest{Name: "room", Type: "group", OwnerId: "alice"})
+		},
+		"JoinRoom": func() (successResponse, error) {
+			return s.JoinRoom(ctx, &chat.JoinRoomRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"MuteRoom": func() (successResponse, error) {
+			return s.MuteRoom(ctx, &chat.MuteRoomRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"UnmuteRoom": func() (successResponse, error) {
+			return s.UnmuteRoom(ctx, &chat.UnmuteRoomRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"ArchiveRoom": func() (successResponse, error) {
+			return s.ArchiveRoom(ctx, &chat.ArchiveRoomRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"UnarchiveRoom": func() (successResponse, error) {
+			return s.UnarchiveRoom(ctx, &chat.UnarchiveRoomRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"PinRoom": func() (successResponse, error) {
+			return s.PinRoom(ctx, &chat.PinRoomRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"UnpinRoom": func() (successResponse, error) {
+			return s.UnpinRoom(ctx, &chat.UnpinRoomRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"MarkAsRead": func() (successResponse, error) {
+			return s.MarkAsRead(ctx, &chat.MarkAsReadRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"MarkAllAsRead": func() (successResponse, error) {
+			return s.MarkAllAsRead(ctx, &chat.MarkAllAsReadRequest{UserId: "alice"})
+		},
+		"MarkAsDelivered": func() (successResponse, error) {
+			return s.MarkAsDelivered(ctx, &chat.MarkAsDeliveredRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"GetUnreadCount": func() (successResponse, error) {
+			return s.GetUnreadCount(ctx, &chat.GetUnreadCountRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"ListUserRooms": func() (successResponse, error) {
+			return s.ListUserRooms(ctx, &chat.ListUserRoomsRequest{UserId: "alice"})
+		},
+		"ListRoomMembers": func() (successResponse, error) {
+			return s.ListRoomMembers(ctx, &chat.ListRoomMembersRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"GetOnlineMembers": func() (successResponse, error) {
+			return s.GetOnlineMembers(ctx, &chat.GetOnlineMembersRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"GetRoomMessageCount": func() (successResponse, error) {
+			return s.GetRoomMessageCount(ctx, &chat.GetRoomMessageCountRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"GetMessages": func() (successResponse, error) {
+			return s.GetMessages(ctx, &chat.GetMessagesRequest{RoomId: roomID, UserId: "alice"})
+		},
+		"GetMessagesAround": func() (successResponse, error) {
+			return s.GetMessagesAround(ctx, &chat.GetMessagesAroundRequest{RoomId: roomID, UserId: "alice"})
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			resp, err := call()
+			if err != nil || resp.GetSuccess() {
+				t.Errorf("%s() = %+v, %v; want Success=false", name, resp, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,380 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// importClockSkew 允許導入時間略晚於服務器時間（源系統與本服務的時鐘誤差）
+const importClockSkew = time.Minute
+
+// importTime 轉換導入項目的原始時間；未提供時 required 為 false 則使用當前時間
+func importTime(unix int64, now time.Time, required bool) (time.Time, error) {
+	switch {
+	case unix < 0:
+		return time.Time{}, fmt.Errorf("created_at 不能小於 0")
+	case unix == 0 && required:
+		return time.Time{}, fmt.Errorf("created_at 不能為空")
+	case unix == 0:
+		return now, nil
+	}
+	t := time.Unix(unix, 0).UTC()
+	if t.After(now.Add(importClockSkew)) {
+		return time.Time{}, fmt.Errorf("created_at 不能晚於當前時間")
+	}
+	return t, nil
+}
+
+// validateImportBatch 檢查導入批次的大小
+func validateImportBatch(count int) string {
+	if count == 0 {
+		return "導入列表不能為空"
+	}
+	if count > constants.MaxImportBatchSize {
+		return fmt.Sprintf("每次最多導入 %d 項", constants.MaxImportBatchSize)
+	}
+	return ""
+}
+
+// importFailure 單個項目的失敗結果
+func importFailure(index int, reason string) *chat.ImportResult {
+	return &chat.ImportResult{Index: int32(index), Error: reason} // #nosec G115 -- bounded by MaxImportBatchSize
+}
+
+// importWriteError 寫入失敗的原因（不返回數據庫的原始錯誤）
+func importWriteError(err error) string {
+	if mongo.IsDuplicateKeyError(err) {
+		return "記錄已存在"
+	}
+	return "寫入失敗"
+}
+
+// insertImportChunks 按 ImportChunkSize 分批寫入通過驗證的項目，結果按 indexes 寫回請求中的位置
+// 一批整體失敗時只影響該批項目，後續批次繼續寫入
+func insertImportChunks[T any](
+	ctx context.Context,
+	items []T,
+	indexes []int,
+	results []*chat.ImportResult,
+	insert func(context.Context, []T) (map[int]error, error),
+	idOf func(T) string,
+) {
+	for start := 0; start < len(items); start += constants.ImportChunkSize {
+		end := min(start+constants.ImportChunkSize, len(items))
+		failures, err := insert(ctx, items[start:end])
+		if err != nil {
+			logger.Error(ctx, "批量導入寫入失敗",
+				logger.WithAction("import"),
+				logger.WithDetails(map[string]interface{}{"error": err.Error(), "batch_size": end - start}))
+		}
+		for j := start; j < end; j++ {
+			index := indexes[j]
+			switch failure, failed := failures[j-start]; {
+			case err != nil:
+				results[index] = importFailure(index, "寫入失敗")
+			case failed:
+				results[index] = importFailure(index, importWriteError(failure))
+			default:
+				results[index] = &chat.ImportResult{Index: int32(index), Success: true, Id: idOf(items[j])} // #nosec G115 -- bounded by MaxImportBatchSize
+			}
+		}
+	}
+}
+
+// countImportResults 統計成功和失敗的項目數量
+func countImportResults(results []*chat.ImportResult) (imported, failed int32) {
+	for _, result := range results {
+		if result.Success {
+			imported++
+		} else {
+			failed++
+		}
+	}
+	return imported, failed
+}
+
+// importedRoom 驗證並轉換導入的聊天室（與 CreateRoom 相同的名稱、成員和設置規則，不檢查名稱唯一性）
+func importedRoom(item *chat.ImportRoom, now time.Time) (*chatroom.ChatRoom, error) {
+	if item.Type != roomTypeGroup && item.Type != roomTypeDirect {
+		return nil, fmt.Errorf("type 必須是 group 或 direct")
+	}
+	if item.Type != roomTypeDirect || item.Name != "" {
+		if err := middleware.ValidateRoomName(item.Name); err != nil {
+			return nil, err
+		}
+	}
+	if err := middleware.ValidateUserID(item.OwnerId); err != nil {
+		return nil, err
+	}
+	for _, memberID := range item.MemberIds {
+		if err := middleware.ValidateUserID(memberID); err != nil {
+			return nil, fmt.Errorf("成員 ID 格式錯誤")
+		}
+	}
+	settings, err := middleware.ValidateRoomSettings(item.Type, item.Settings)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := importTime(item.CreatedAt, now, false)
+	if err != nil {
+		return nil, err
+	}
+
+	memberIDs := ensureOwnerInMembers(item.OwnerId, item.MemberIds)
+	if len(memberIDs) > int(settings.MaxMembers) {
+		return nil, fmt.Errorf("成員數量超過限制 (%d)", settings.MaxMembers)
+	}
+	members := createRoomMembers(memberIDs)
+	for i := range members {
+		members[i].JoinedAt = createdAt
+		members[i].LastSeen = createdAt
+	}
+
+	room := &chatroom.ChatRoom{
+		Name:    middleware.SanitizeInput(item.Name),
+		Type:    item.Type,
+		OwnerID: item.OwnerId,
+		Members: members,
+		Settings: chatroom.RoomSettings{
+			AllowInvite:         settings.AllowInvite,
+			AllowEditMessages:   settings.AllowEditMessages,
+			AllowDeleteMessages: settings.AllowDeleteMessages,
+			AllowPinMessages:    settings.AllowPinMessages,
+			MaxMembers:          int(settings.MaxMembers),
+			WelcomeMessage:      settings.WelcomeMessage,
+			Locale:              settings.Locale,
+		},
+		CreatedAt:     createdAt,
+		UpdatedAt:     createdAt,
+		LastMessageAt: createdAt,
+	}
+	if item.Type == roomTypeDirect {
+		if len(memberIDs) != middleware.DirectRoomMaxMembers {
+			return nil, fmt.Errorf("私聊必須正好有 %d 個成員", middleware.DirectRoomMaxMembers)
+		}
+		room.DirectKey = chatroom.DirectKey(memberIDs)
+	}
+	return room, nil
+}
+
+// ImportRooms 管理員批量導入聊天室（保留原始創建時間，不發送歡迎訊息）
+// 每個聊天室單獨驗證，失敗的項目不影響其他項目；私聊與已有私聊重複時記為失敗
+func (s *Server) ImportRooms(ctx context.Context, req *chat.ImportRoomsRequest) (*chat.ImportRoomsResponse, error) {
+	authUserID := middleware.UserIDFromContext(ctx)
+	if !isRoomAdmin(config.Get(), authUserID) {
+		s.audit.LogAccessDenied(ctx, authUserID, "", "import rooms: not a room admin")
+		return &chat.ImportRoomsResponse{
+			Success:   false,
+			Message:   "只有管理員可以導入聊天室",
+			ErrorCode: constants.ErrorCodePermissionDenied,
+		}, nil
+	}
+	if invalid := validateImportBatch(len(req.Rooms)); invalid != "" {
+		return &chat.ImportRoomsResponse{Success: false, Message: invalid, ErrorCode: constants.ErrorCodeInvalidArgument}, nil
+	}
+
+	now := time.Now().UTC()
+	results := make([]*chat.ImportResult, len(req.Rooms))
+	rooms := make([]*chatroom.ChatRoom, 0, len(req.Rooms))
+	indexes := make([]int, 0, len(req.Rooms))
+	for i, item := range req.Rooms {
+		room, err := importedRoom(item, now)
+		if err != nil {
+			results[i] = importFailure(i, err.Error())
+			continue
+		}
+		rooms = append(rooms, room)
+		indexes = append(indexes, i)
+	}
+
+	insertImportChunks(ctx, rooms, indexes, results, s.repos.ChatRoom.InsertMany,
+		func(room *chatroom.ChatRoom) string { return room.ID })
+
+	imported, failed := countImportResults(results)
+	s.audit.LogSecurityEvent(ctx, "admin_import_rooms", "管理員批量導入聊天室", "medium", map[string]interface{}{
+		"user_id":  authUserID,
+		"imported": imported,
+		"failed":   failed,
+	})
+	logger.Info(ctx, "批量導入聊天室完成",
+		logger.WithUserID(authUserID),
+		logger.WithAction("import_rooms"),
+		logger.WithDetails(map[string]interface{}{"imported": imported, "failed": failed}))
+
+	return &chat.ImportRoomsResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("導入完成：成功 %d，失敗 %d", imported, failed),
+		Results:  results,
+		Imported: imported,
+		Failed:   failed,
+	}, nil
+}
+
+// importedMessage 通過驗證的導入訊息，保留明文用於生成聊天室的最後訊息預覽
+type importedMessage struct {
+	message   *chatroom.Message
+	plaintext string
+}
+
+// validateImportedMessage 驗證導入的訊息（與 SendMessage 相同的內容和元數據規則，created_at 必填）
+func validateImportedMessage(item *chat.ImportMessage, now time.Time) (time.Time, error) {
+	if item.Type == "" {
+		item.Type = messageTypeText
+	}
+	if err := middleware.ValidateRoomID(item.RoomId); err != nil {
+		return time.Time{}, err
+	}
+	if err := middleware.ValidateUserID(item.SenderId); err != nil {
+		return time.Time{}, err
+	}
+	if err := validateSendContent(&chat.SendMessageRequest{Type: item.Type, Content: item.Content}); err != nil {
+		return time.Time{}, err
+	}
+	if err := validateMessageMetadata(item.Type, item.Content, item.Metadata); err != nil {
+		return time.Time{}, err
+	}
+	return importTime(item.CreatedAt, now, true)
+}
+
+// ImportMessages 管理員批量導入歷史訊息
+// 保留原始發送時間和發送者（發送者不需要仍是成員），內容使用聊天室密鑰加密；聊天室必須已存在。
+// 導入的訊息不觸發提及通知和實時推送，只在比聊天室現有最後訊息更新時更新最後訊息
+func (s *Server) ImportMessages(ctx context.Context, req *chat.ImportMessagesRequest) (*chat.ImportMessagesResponse, error) {
+	authUserID := middleware.UserIDFromContext(ctx)
+	if !isRoomAdmin(config.Get(), authUserID) {
+		s.audit.LogAccessDenied(ctx, authUserID, "", "import messages: not a room admin")
+		return &chat.ImportMessagesResponse{
+			Success:   false,
+			Message:   "只有管理員可以導入訊息",
+			ErrorCode: constants.ErrorCodePermissionDenied,
+		}, nil
+	}
+	if invalid := validateImportBatch(len(req.Messages)); invalid != "" {
+		return &chat.ImportMessagesResponse{Success: false, Message: invalid, ErrorCode: constants.ErrorCodeInvalidArgument}, nil
+	}
+
+	now := time.Now().UTC()
+	results := make([]*chat.ImportResult, len(req.Messages))
+	createdAt := make([]time.Time, len(req.Messages))
+	var roomIDs []string
+	seenRooms := make(map[string]bool)
+	for i, item := range req.Messages {
+		t, err := validateImportedMessage(item, now)
+		if err != nil {
+			results[i] = importFailure(i, err.Error())
+			continue
+		}
+		createdAt[i] = t
+		if !seenRooms[item.RoomId] {
+			seenRooms[item.RoomId] = true
+			roomIDs = append(roomIDs, item.RoomId)
+		}
+	}
+
+	// 引用完整性：訊息所屬的聊天室必須已存在
+	existing := map[string]bool{}
+	if len(roomIDs) > 0 {
+		var err error
+		if existing, err = s.repos.ChatRoom.ExistingRoomIDs(ctx, roomIDs); err != nil {
+			logErrorWithUser(ctx, "檢查導入訊息的聊天室失敗", authUserID, err)
+			return &chat.ImportMessagesResponse{Success: false, Message: "檢查聊天室失敗", ErrorCode: constants.ErrorCodeInternal}, nil
+		}
+	}
+
+	messages := make([]importedMessage, 0, len(req.Messages))
+	indexes := make([]int, 0, len(req.Messages))
+	for i, item := range req.Messages {
+		if results[i] != nil {
+			continue
+		}
+		if !existing[item.RoomId] {
+			results[i] = importFailure(i, "聊天室不存在")
+			continue
+		}
+		encrypted, keyVersion, err := s.encryption.EncryptMessageWithVersion(item.Content, item.RoomId)
+		if err != nil {
+			logErrorWithUserAndRoom(ctx, "導入訊息加密失敗", item.SenderId, item.RoomId, err)
+			results[i] = importFailure(i, "訊息加密失敗")
+			continue
+		}
+		message := chatroom.NewMessageAt(createdAt[i])
+		message.RoomID = item.RoomId
+		message.SenderID = item.SenderId
+		message.Content = encrypted
+		message.Type = item.Type
+		message.Metadata = metadataFromGRPC(item.Metadata)
+		message.SetKeyVersion(keyVersion)
+		messages = append(messages, importedMessage{message: &message, plaintext: item.Content})
+		indexes = append(indexes, i)
+	}
+
+	insertImportChunks(ctx, messages, indexes, results,
+		func(ctx context.Context, batch []importedMessage) (map[int]error, error) {
+			docs := make([]*chatroom.Message, len(batch))
+			for i := range batch {
+				docs[i] = batch[i].message
+			}
+			return s.repos.Message.InsertMany(ctx, docs)
+		},
+		func(m importedMessage) string { return m.message.ID })
+
+	s.advanceImportedLastMessages(ctx, messages, indexes, results)
+
+	imported, failed := countImportResults(results)
+	s.audit.LogSecurityEvent(ctx, "admin_import_messages", "管理員批量導入訊息", "medium", map[string]interface{}{
+		"user_id":  authUserID,
+		"rooms":    len(roomIDs),
+		"imported": imported,
+		"failed":   failed,
+	})
+	logger.Info(ctx, "批量導入訊息完成",
+		logger.WithUserID(authUserID),
+		logger.WithAction("import_messages"),
+		logger.WithDetails(map[string]interface{}{"rooms": len(roomIDs), "imported": imported, "failed": failed}))
+
+	return &chat.ImportMessagesResponse{
+		Success:  true,
+		Message:  fmt.Sprintf("導入完成：成功 %d，失敗 %d", imported, failed),
+		Results:  results,
+		Imported: imported,
+		Failed:   failed,
+	}, nil
+}
+
+// latestImportedMessages 每個聊天室中成功導入的最新訊息
+func latestImportedMessages(messages []importedMessage, indexes []int, results []*chat.ImportResult) map[string]importedMessage {
+	latest := make(map[string]importedMessage)
+	for j, m := range messages {
+		if !results[indexes[j]].Success {
+			continue
+		}
+		if current, ok := latest[m.message.RoomID]; !ok || m.message.CreatedAt.After(current.message.CreatedAt) {
+			latest[m.message.RoomID] = m
+		}
+	}
+	return latest
+}
+
+// advanceImportedLastMessages 用導入的最新訊息更新聊天室的最後訊息（失敗只記錄日誌，不影響導入結果）
+func (s *Server) advanceImportedLastMessages(ctx context.Context, messages []importedMessage, indexes []int, results []*chat.ImportResult) {
+	for roomID, m := range latestImportedMessages(messages, indexes, results) {
+		preview, version, err := s.encryption.EncryptMessageWithVersion(generateLastMessagePreview(m.message.Type, m.plaintext), roomID)
+		if err != nil {
+			logErrorWithRoom(ctx, "加密導入的最後訊息失敗", roomID, err)
+			continue
+		}
+		if err := s.repos.ChatRoom.AdvanceLastMessage(ctx, roomID, m.message.CreatedAt, preview, chatroom.EncryptionKeyID(version)); err != nil {
+			logErrorWithRoom(ctx, "更新導入聊天室的最後訊息失敗", roomID, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+func TestImportTime(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		unix     int64
+		required bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{"original time", now.Add(-time.Hour).Unix(), true, now.Add(-time.Hour), false},
+		{"missing optional uses now", 0, false, now, false},
+		{"missing required", 0, true, time.Time{}, true},
+		{"negative", -1, false, time.Time{}, true},
+		{"within clock skew", now.Add(30 * time.Second).Unix(), true, now.Add(30 * time.Second), false},
+		{"future", now.Add(time.Hour).Unix(), true, time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := importTime(tt.unix, now, tt.required)
+			if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+				t.Errorf("importTime() = %v, %v; want %v, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateImportBatch(t *testing.T) {
+	if validateImportBatch(0) == "" {
+		t.Error("empty batch should be rejected")
+	}
+	if validateImportBatch(constants.MaxImportBatchSize) != "" {
+		t.Error("batch at the limit should be accepted")
+	}
+	if validateImportBatch(constants.MaxImportBatchSize+1) == "" {
+		t.Error("oversized batch should be rejected")
+	}
+}
+
+func TestImportedRoom(t *testing.T) {
+	now := time.Now().UTC()
+	createdAt := now.Add(-24 * time.Hour).Truncate(time.Second)
+
+	room, err := importedRoom(&chat.ImportRoom{
+		Name:      "Team",
+		Type:      roomTypeGroup,
+		OwnerId:   "owner",
+		MemberIds: []string{"alice"},
+		CreatedAt: createdAt.Unix(),
+	}, now)
+	if err != nil {
+		t.Fatalf("importedRoom() error = %v", err)
+	}
+	if !room.CreatedAt.Equal(createdAt) || !room.LastMessageAt.Equal(createdAt) {
+		t.Errorf("times = %v / %v, want original %v", room.CreatedAt, room.LastMessageAt, createdAt)
+	}
+	if len(room.Members) != 2 || room.Members[0].UserID != "owner" || !room.Members[1].JoinedAt.Equal(createdAt) {
+		t.Errorf("members = %+v, want owner added and joined at creation", room.Members)
+	}
+
+	direct, err := importedRoom(&chat.ImportRoom{Type: roomTypeDirect, OwnerId: "alice", MemberIds: []string{"bob"}}, now)
+	if err != nil {
+		t.Fatalf("importedRoom(direct) error = %v", err)
+	}
+	if direct.DirectKey != chatroom.DirectKey([]string{"alice", "bob"}) {
+		t.Errorf("DirectKey = %q", direct.DirectKey)
+	}
+
+	invalid := []struct {
+		name string
+		item *chat.ImportRoom
+	}{
+		{"unknown type", &chat.ImportRoom{Name: "x", Type: "channel", OwnerId: "o"}},
+		{"missing group name", &chat.ImportRoom{Type: roomTypeGroup, OwnerId: "o"}},
+		{"invalid owner", &chat.ImportRoom{Name: "x", Type: roomTypeGroup, OwnerId: "$where"}},
+		{"invalid member", &chat.ImportRoom{Name: "x", Type: roomTypeGroup, OwnerId: "o", MemberIds: []string{"a{}"}}},
+		{"direct with one member", &chat.ImportRoom{Type: roomTypeDirect, OwnerId: "alice"}},
+		{"direct with three members", &chat.ImportRoom{Type: roomTypeDirect, OwnerId: "a", MemberIds: []string{"b", "c"}}},
+		{"future creation", &chat.ImportRoom{Name: "x", Type: roomTypeGroup, OwnerId: "o", CreatedAt: now.Add(time.Hour).Unix()}},
+	}
+	for _, tt := range invalid {
+		if _, err := importedRoom(tt.item, now); err == nil {
+			t.Errorf("%s: importedRoom() should fail", tt.name)
+		}
+	}
+}
+
+func TestValidateImportedMessage(t *testing.T) {
+	now := time.Now().UTC()
+	valid := func() *chat.ImportMessage {
+		return &chat.ImportMessage{
+			RoomId:    "507f1f77bcf86cd799439011",
+			SenderId:  "alice",
+			Content:   "hello",
+			CreatedAt: now.Add(-time.Hour).Unix(),
+		}
+	}
+
+	item := valid()
+	if _, err := validateImportedMessage(item, now); err != nil {
+		t.Fatalf("validateImportedMessage() error = %v", err)
+	}
+	if item.Type != messageTypeText {
+		t.Errorf("Type = %q, want default text", item.Type)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*chat.ImportMessage)
+	}{
+		{"invalid room", func(m *chat.ImportMessage) { m.RoomId = "room" }},
+		{"invalid sender", func(m *chat.ImportMessage) { m.SenderId = "" }},
+		{"empty text", func(m *chat.ImportMessage) { m.Content = " " }},
+		{"image without url", func(m *chat.ImportMessage) { m.Type = messageTypeImage }},
+		{"missing created_at", func(m *chat.ImportMessage) { m.CreatedAt = 0 }},
+	}
+	for _, tt := range tests {
+		item := valid()
+		tt.mutate(item)
+		if _, err := validateImportedMessage(item, now); err == nil {
+			t.Errorf("%s: validateImportedMessage() should fail", tt.name)
+		}
+	}
+}
+
+func TestInsertImportChunks(t *testing.T) {
+	total := constants.ImportChunkSize + 3
+	items := make([]string, total)
+	indexes := make([]int, total)
+	for i := range items {
+		items[i] = strings.Repeat("x", i%5+1)
+		indexes[i] = i + 1 // 請求中的第 0 項驗證失敗
+	}
+	results := make([]*chat.ImportResult, total+1)
+	results[0] = importFailure(0, "invalid")
+
+	var batches []int
+	insert := func(_ context.Context, batch []string) (map[int]error, error) {
+		batches = append(batches, len(batch))
+		if len(batches) == 2 {
+			return nil, errors.New("connection reset")
+		}
+		return map[int]error{1: errors.New("duplicate")}, nil
+	}
+	insertImportChunks(context.Background(), items, indexes, results, insert, func(s string) string { return s })
+
+	if len(batches) != 2 || batches[0] != constants.ImportChunkSize || batches[1] != 3 {
+		t.Fatalf("batches = %v, want [%d 3]", batches, constants.ImportChunkSize)
+	}
+	if results[1].Success != true || results[1].Id != items[0] {
+		t.Errorf("results[1] = %+v, want success", results[1])
+	}
+	if results[2].Success || results[2].Error != "寫入失敗" {
+		t.Errorf("results[2] = %+v, want per-item failure", results[2])
+	}
+	for i := constants.ImportChunkSize + 1; i <= total; i++ {
+		if results[i].Success || results[i].Index != int32(i) {
+			t.Errorf("results[%d] = %+v, want failure from the failed batch", i, results[i])
+		}
+	}
+
+	imported, failed := countImportResults(results)
+	if imported != int32(constants.ImportChunkSize-1) || failed != 5 {
+		t.Errorf("countImportResults() = %d, %d", imported, failed)
+	}
+}
+
+func TestLatestImportedMessages(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	message := func(roomID string, offset time.Duration) importedMessage {
+		m := chatroom.NewMessageAt(base.Add(offset))
+		m.RoomID = roomID
+		return importedMessage{message: &m, plaintext: roomID + offset.String()}
+	}
+	messages := []importedMessage{
+		message("r1", time.Minute),
+		message("r1", time.Hour), // 寫入失敗，不作為最後訊息
+		message("r1", 2*time.Minute),
+		message("r2", 0),
+	}
+	indexes := []int{0, 1, 2, 3}
+	results := []*chat.ImportResult{{Success: true}, {Success: false}, {Success: true}, {Success: true}}
+
+	latest := latestImportedMessages(messages, indexes, results)
+	if len(latest) != 2 {
+		t.Fatalf("latest rooms = %d, want 2", len(latest))
+	}
+	if got := latest["r1"].message.CreatedAt; !got.Equal(base.Add(2 * time.Minute)) {
+		t.Errorf("r1 latest = %v, want the newest successful message", got)
+	}
+}
@@ -0,0 +1,228 @@
+package grpc
+
+import (
+	"context"
+
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/keymanager"
+	"chat-gateway/proto/chat"
+)
+
+// manualKeyRotationEnabled 是否允許手動輪換密鑰（需要在配置中明確開啟）
+func manualKeyRotationEnabled() bool {
+	cfg := config.Get()
+	return cfg != nil && cfg.Security.Encryption.ManualRotationEnabled
+}
+
+// RotateRoomKey 手動輪換聊天室加密密鑰（僅限 JWT 認證的管理員，即 admin_user_ids 中的運維人員）
+// 聊天室還沒有密鑰時會創建新密鑰；請求中的 user_id 不用於授權
+func (s *Server) RotateRoomKey(ctx context.Context, req *chat.RotateRoomKeyRequest) (*chat.RotateRoomKeyResponse, error) {
+	if !manualKeyRotationEnabled() {
+		return &chat.RotateRoomKeyResponse{
+			Success: false,
+			Message: "未啟用手動密鑰輪換",
+		}, nil
+	}
+	authUserID := middleware.UserIDFromContext(ctx)
+	if !isRoomAdmin(config.Get(), authUserID) {
+		s.audit.LogAccessDenied(ctx, authUserID, req.RoomId, "rotate key: not an admin")
+		return &chat.RotateRoomKeyResponse{
+			Success: false,
+			Message: "只有管理員可以輪換密鑰",
+		}, nil
+	}
+	if s.keyManager == nil {
+		return &chat.RotateRoomKeyResponse{
+			Success: false,
+			Message: "未啟用消息加密",
+		}, nil
+	}
+	if err := middleware.ValidateRoomID(req.RoomId); err != nil {
+		return &chat.RotateRoomKeyResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if _, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId); err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", authUserID, req.RoomId, err)
+		return &chat.RotateRoomKeyResponse{
+			Success: false,
+			Message: "聊天室不存在",
+		}, nil
+	}
+
+	info, created, err := s.keyManager.RotateOrCreateRoomKey(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "輪換聊天室密鑰失敗", authUserID, req.RoomId, err)
+		s.audit.LogSecurityEvent(ctx, "room_key_rotation_failed", "手動輪換聊天室密鑰失敗", "high", map[string]interface{}{
+			"user_id": authUserID,
+			"room_id": req.RoomId,
+		})
+		return &chat.RotateRoomKeyResponse{
+			Success: false,
+			Message: "輪換密鑰失敗",
+		}, nil
+	}
+
+	rotatedAt := info.RotatedAt
+	if created || rotatedAt.IsZero() {
+		rotatedAt = info.CreatedAt
+	}
+
+	s.audit.LogSecurityEvent(ctx, "room_key_rotated", "手動輪換聊天室密鑰", "high", map[string]interface{}{
+		"user_id":     authUserID,
+		"room_id":     req.RoomId,
+		"key_version": info.Version,
+		"created":     created,
+	})
+
+	logger.Info(ctx, "手動輪換聊天室密鑰成功",
+		logger.WithUserID(authUserID),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("rotate_room_key"),
+		logger.WithDetails(map[string]interface{}{
+			"key_version": info.Version,
+			"created":     created,
+		}))
+
+	message := "密鑰已輪換"
+	if created {
+		message = "聊天室原本沒有密鑰，已創建新密鑰"
+	}
+	return &chat.RotateRoomKeyResponse{
+		Success:    true,
+		Message:    message,
+		KeyVersion: int32(info.Version), // #nosec G115 -- key versions are small counters
+		RotatedAt:  rotatedAt.Unix(),
+		Created:    created,
+	}, nil
+}
+
+// keyDebugEnabled 是否開放密鑰狀態查詢（需要在配置中明確開啟）
+func keyDebugEnabled() bool {
+	cfg := config.Get()
+	return cfg != nil && cfg.Security.Encryption.KeyDebugEnabled
+}
+
+// GetKeyStats 獲取密鑰管理器統計信息（僅限 JWT 認證的管理員）
+func (s *Server) GetKeyStats(ctx context.Context, _ *chat.GetKeyStatsRequest) (*chat.GetKeyStatsResponse, error) {
+	if !keyDebugEnabled() {
+		return &chat.GetKeyStatsResponse{
+			Success: false,
+			Message: "未啟用密鑰狀態查詢",
+		}, nil
+	}
+	authUserID := middleware.UserIDFromContext(ctx)
+	if !isRoomAdmin(config.Get(), authUserID) {
+		s.audit.LogAccessDenied(ctx, authUserID, "", "get key stats: not an admin")
+		return &chat.GetKeyStatsResponse{
+			Success: false,
+			Message: "只有管理員可以查詢密鑰狀態",
+		}, nil
+	}
+	if s.keyManager == nil {
+		return &chat.GetKeyStatsResponse{
+			Success: false,
+			Message: "未啟用消息加密",
+		}, nil
+	}
+
+	stats := s.keyManager.Stats()
+	logger.Info(ctx, "查詢密鑰管理器統計", logger.WithAction("get_key_stats"))
+
+	// #nosec G115 -- key counts are bounded by the number of rooms
+	return &chat.GetKeyStatsResponse{
+		Success:      true,
+		Message:      "獲取密鑰統計成功",
+		TotalKeys:    int32(stats.TotalKeys),
+		ActiveKeys:   int32(stats.ActiveKeys),
+		ArchivedKeys: int32(stats.ArchivedKeys),
+		RevokedKeys:  int32(stats.RevokedKeys),
+		CacheHits:    stats.CacheHits,
+		CacheMisses:  stats.CacheMisses,
+	}, nil
+}
+
+// GetRoomKeyInfo 獲取聊天室密鑰狀態（版本、年齡、狀態），不返回密鑰值（僅限 JWT 認證的管理員）
+func (s *Server) GetRoomKeyInfo(ctx context.Context, req *chat.GetRoomKeyInfoRequest) (*chat.GetRoomKeyInfoResponse, error) {
+	if !keyDebugEnabled() {
+		return &chat.GetRoomKeyInfoResponse{
+			Success: false,
+			Message: "未啟用密鑰狀態查詢",
+		}, nil
+	}
+	authUserID := middleware.UserIDFromContext(ctx)
+	if !isRoomAdmin(config.Get(), authUserID) {
+		s.audit.LogAccessDenied(ctx, authUserID, req.RoomId, "get room key info: not an admin")
+		return &chat.GetRoomKeyInfoResponse{
+			Success: false,
+			Message: "只有管理員可以查詢密鑰狀態",
+		}, nil
+	}
+	if s.keyManager == nil {
+		return &chat.GetRoomKeyInfoResponse{
+			Success: false,
+			Message: "未啟用消息加密",
+		}, nil
+	}
+	if err := middleware.ValidateRoomID(req.RoomId); err != nil {
+		return &chat.GetRoomKeyInfoResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	keyStatus, err := s.keyManager.GetRoomKeyStatus(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithRoom(ctx, "獲取聊天室密鑰狀態失敗", req.RoomId, err)
+		return &chat.GetRoomKeyInfoResponse{
+			Success: false,
+			Message: "獲取密鑰狀態失敗",
+		}, nil
+	}
+
+	logger.Info(ctx, "查詢聊天室密鑰狀態",
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("get_room_key_info"))
+
+	return buildRoomKeyInfoResponse(req.RoomId, keyStatus), nil
+}
+
+// buildRoomKeyInfoResponse 轉換聊天室密鑰狀態為 gRPC 響應
+func buildRoomKeyInfoResponse(roomID string, keyStatus *keymanager.RoomKeyStatus) *chat.GetRoomKeyInfoResponse {
+	resp := &chat.GetRoomKeyInfoResponse{
+		Success:          true,
+		RoomId:           roomID,
+		Cached:           keyStatus.Cached,
+		ArchivedVersions: toInt32s(keyStatus.ArchivedVersions),
+		StoredVersions:   toInt32s(keyStatus.StoredVersions),
+	}
+
+	current := keyStatus.Current
+	if current == nil {
+		resp.Message = "聊天室沒有密鑰"
+		return resp
+	}
+
+	resp.Message = "獲取密鑰狀態成功"
+	resp.KeyVersion = int32(current.Version) // #nosec G115 -- key versions are small counters
+	resp.Status = string(current.Status)
+	resp.CreatedAt = current.CreatedAt.Unix()
+	if !current.RotatedAt.IsZero() {
+		resp.RotatedAt = current.RotatedAt.Unix()
+	}
+	resp.AgeSeconds = int64(current.Age.Seconds())
+	return resp
+}
+
+// toInt32s 轉換版本號列表
+func toInt32s(values []int) []int32 {
+	result := make([]int32, len(values))
+	for i, v := range values {
+		result[i] = int32(v) // #nosec G115 -- key versions are small counters
+	}
+	return result
+}
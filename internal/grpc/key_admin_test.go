@@ -0,0 +1,84 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/security/keymanager"
+	"chat-gateway/proto/chat"
+)
+
+func TestRotateRoomKey_DisabledByDefault(t *testing.T) {
+	s := &Server{}
+
+	resp, err := s.RotateRoomKey(context.Background(), &chat.RotateRoomKeyRequest{
+		RoomId: "507f1f77bcf86cd799439011",
+		UserId: "owner",
+	})
+	if err != nil {
+		t.Fatalf("RotateRoomKey returned error: %v", err)
+	}
+	if resp.Success {
+		t.Error("expected manual rotation to be rejected unless enabled in config")
+	}
+}
+
+func TestKeyDebug_DisabledByDefault(t *testing.T) {
+	s := &Server{}
+
+	stats, err := s.GetKeyStats(context.Background(), &chat.GetKeyStatsRequest{})
+	if err != nil {
+		t.Fatalf("GetKeyStats returned error: %v", err)
+	}
+	if stats.Success {
+		t.Error("expected key stats to be rejected unless enabled in config")
+	}
+
+	info, err := s.GetRoomKeyInfo(context.Background(), &chat.GetRoomKeyInfoRequest{
+		RoomId: "507f1f77bcf86cd799439011",
+	})
+	if err != nil {
+		t.Fatalf("GetRoomKeyInfo returned error: %v", err)
+	}
+	if info.Success {
+		t.Error("expected room key info to be rejected unless enabled in config")
+	}
+}
+
+func TestBuildRoomKeyInfoResponse(t *testing.T) {
+	roomID := "507f1f77bcf86cd799439011"
+
+	t.Run("no key", func(t *testing.T) {
+		resp := buildRoomKeyInfoResponse(roomID, &keymanager.RoomKeyStatus{})
+		if !resp.Success || resp.KeyVersion != 0 || resp.Status != "" {
+			t.Errorf("unexpected response for room without key: %+v", resp)
+		}
+	})
+
+	t.Run("with key", func(t *testing.T) {
+		created := time.Unix(1700000000, 0)
+		resp := buildRoomKeyInfoResponse(roomID, &keymanager.RoomKeyStatus{
+			Current: &keymanager.KeyInfo{
+				RoomID:    roomID,
+				Version:   3,
+				CreatedAt: created,
+				Status:    keymanager.KeyStatusActive,
+				Age:       90 * time.Second,
+			},
+			Cached:           true,
+			ArchivedVersions: []int{2, 1},
+			StoredVersions:   []int{3, 2, 1},
+		})
+
+		if resp.KeyVersion != 3 || resp.Status != string(keymanager.KeyStatusActive) {
+			t.Errorf("version/status = %d/%q", resp.KeyVersion, resp.Status)
+		}
+		if resp.CreatedAt != created.Unix() || resp.RotatedAt != 0 || resp.AgeSeconds != 90 {
+			t.Errorf("times = created %d rotated %d age %d", resp.CreatedAt, resp.RotatedAt, resp.AgeSeconds)
+		}
+		if !resp.Cached || len(resp.ArchivedVersions) != 2 || len(resp.StoredVersions) != 3 {
+			t.Errorf("unexpected cache/version info: %+v", resp)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// isRoomAdmin 是否允許列出所有聊天室：必須啟用 JWT 且已認證的用戶在 admin_user_ids 中
+// 未啟用 JWT 時無法確認調用者身份，一律拒絕
+func isRoomAdmin(cfg *config.Config, authUserID string) bool {
+	if cfg == nil || !cfg.Security.Authentication.JWTEnabled || authUserID == "" {
+		return false
+	}
+	return slices.Contains(cfg.Security.Authentication.AdminUserIDs, authUserID)
+}
+
+// roomQueryFromRequest 驗證並轉換 ListRooms 的過濾條件
+func roomQueryFromRequest(req *chat.ListRoomsRequest) (chatroom.RoomQuery, string) {
+	query := chatroom.RoomQuery{
+		Type:    req.Type,
+		OwnerID: req.OwnerId,
+		Limit:   int(req.Limit),
+		Cursor:  req.Cursor,
+	}
+
+	if req.OwnerId != "" {
+		if err := middleware.ValidateUserID(req.OwnerId); err != nil {
+			return query, err.Error()
+		}
+	}
+	// 用戶輸入的名稱轉義後再作為正則使用，防止注入正則運算符
+	switch {
+	case req.NamePrefix != "" && req.NameContains != "":
+		return query, "name_prefix 和 name_contains 不能同時使用"
+	case req.NamePrefix != "":
+		query.NameFilter = database.SafePrefixRegexQuery(req.NamePrefix)
+	case req.NameContains != "":
+		query.NameFilter = database.SafeRegexQuery(req.NameContains)
+	}
+	if req.CreatedAfter < 0 || req.CreatedBefore < 0 {
+		return query, "創建時間不能小於 0"
+	}
+	if req.CreatedAfter > 0 {
+		query.CreatedAfter = time.Unix(req.CreatedAfter, 0).UTC()
+	}
+	if req.CreatedBefore > 0 {
+		query.CreatedBefore = time.Unix(req.CreatedBefore, 0).UTC()
+	}
+	if req.CreatedAfter > 0 && req.CreatedBefore > 0 && req.CreatedAfter >= req.CreatedBefore {
+		return query, "created_after 必須早於 created_before"
+	}
+	if req.Cursor != "" {
+		if err := chatroom.ValidateMessageCursor(req.Cursor); err != nil {
+			return query, "無效的 cursor"
+		}
+	}
+	return query, ""
+}
+
+// ListRooms 管理員列出和搜索所有聊天室（按創建時間倒序分頁）
+// 只返回成員數量，不返回成員列表和最後訊息內容；每次調用（包括被拒絕的）都寫入審計日誌
+func (s *Server) ListRooms(ctx context.Context, req *chat.ListRoomsRequest) (*chat.ListRoomsResponse, error) {
+	authUserID := middleware.UserIDFromContext(ctx)
+	if !isRoomAdmin(config.Get(), authUserID) {
+		s.audit.LogAccessDenied(ctx, authUserID, "", "list rooms: not a room admin")
+		return listRoomsFailure(constants.ErrorCodePermissionDenied, "只有管理員可以列出所有聊天室"), nil
+	}
+
+	query, invalid := roomQueryFromRequest(req)
+	if invalid != "" {
+		return listRoomsFailure(constants.ErrorCodeInvalidArgument, invalid), nil
+	}
+
+	rooms, cursor, hasMore, err := s.repos.ChatRoom.ListRooms(ctx, query)
+	if err != nil {
+		logErrorWithUser(ctx, "列出聊天室失敗", authUserID, err)
+		return listRoomsFailure(constants.ErrorCodeInternal, "列出聊天室失敗"), nil
+	}
+
+	grpcRooms := make([]*chat.ChatRoom, len(rooms))
+	for i, room := range rooms {
+		grpcRooms[i] = convertRoomToGRPC(room)
+		grpcRooms[i].MemberCount = int32(room.MemberCount) // #nosec G115 -- bounded by max_members
+	}
+
+	s.audit.LogSecurityEvent(ctx, "admin_list_rooms", "管理員列出聊天室", "low", map[string]interface{}{
+		"user_id":        authUserID,
+		"type":           req.Type,
+		"owner_id":       req.OwnerId,
+		"name_prefix":    req.NamePrefix,
+		"name_contains":  req.NameContains,
+		"created_after":  req.CreatedAfter,
+		"created_before": req.CreatedBefore,
+		"returned":       len(grpcRooms),
+	})
+
+	logger.Debug(ctx, "列出聊天室成功",
+		logger.WithUserID(authUserID),
+		logger.WithAction("list_rooms"))
+
+	return &chat.ListRoomsResponse{
+		Success: true,
+		Message: "獲取聊天室列表成功",
+		Rooms:   grpcRooms,
+		Cursor:  cursor,
+		HasMore: hasMore,
+	}, nil
+}
+
+func listRoomsFailure(code, message string) *chat.ListRoomsResponse {
+	return &chat.ListRoomsResponse{
+		Success:   false,
+		Message:   message,
+		ErrorCode: code,
+	}
+}
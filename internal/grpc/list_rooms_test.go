@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"testing"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestIsRoomAdmin(t *testing.T) {
+	withAdmins := func(jwtEnabled bool) *config.Config {
+		cfg := &config.Config{}
+		cfg.Security.Authentication.JWTEnabled = jwtEnabled
+		cfg.Security.Authentication.AdminUserIDs = []string{"admin-1"}
+		return cfg
+	}
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		authUserID string
+		want       bool
+	}{
+		{"admin", withAdmins(true), "admin-1", true},
+		{"not an admin", withAdmins(true), "user-1", false},
+		{"unauthenticated", withAdmins(true), "", false},
+		{"jwt disabled", withAdmins(false), "admin-1", false},
+		{"no config", nil, "admin-1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRoomAdmin(tt.cfg, tt.authUserID); got != tt.want {
+				t.Errorf("isRoomAdmin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoomQueryFromRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *chat.ListRoomsRequest
+		wantInvalid bool
+	}{
+		{"empty", &chat.ListRoomsRequest{}, false},
+		{"all filters", &chat.ListRoomsRequest{Type: "group", OwnerId: "user_alice", NamePrefix: "team", CreatedAfter: 100, CreatedBefore: 200}, false},
+		{"invalid owner", &chat.ListRoomsRequest{OwnerId: "$where"}, true},
+		{"negative time", &chat.ListRoomsRequest{CreatedAfter: -1}, true},
+		{"empty range", &chat.ListRoomsRequest{CreatedAfter: 200, CreatedBefore: 200}, true},
+		{"name contains", &chat.ListRoomsRequest{NameContains: "team"}, false},
+		{"prefix and contains", &chat.ListRoomsRequest{NamePrefix: "team", NameContains: "team"}, true},
+		{"invalid cursor", &chat.ListRoomsRequest{Cursor: "!!"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, invalid := roomQueryFromRequest(tt.req); (invalid != "") != tt.wantInvalid {
+				t.Errorf("roomQueryFromRequest() invalid = %q, wantInvalid %v", invalid, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestRoomQueryFromRequest_EscapesNameFilters(t *testing.T) {
+	query, invalid := roomQueryFromRequest(&chat.ListRoomsRequest{NamePrefix: "a.*(b"})
+	if invalid != "" {
+		t.Fatalf("roomQueryFromRequest() invalid = %q", invalid)
+	}
+	if want := (bson.M{"$regex": `^a\.\*\(b`, "$options": "i"}); query.NameFilter["$regex"] != want["$regex"] {
+		t.Errorf("NameFilter = %v, want %v", query.NameFilter, want)
+	}
+
+	query, _ = roomQueryFromRequest(&chat.ListRoomsRequest{NameContains: "a|b"})
+	if got := query.NameFilter["$regex"]; got != `a\|b` {
+		t.Errorf("NameContains regex = %v, want a\\|b", got)
+	}
+
+	cursor := chatroom.EncodeMessageCursor(query.CreatedBefore, bson.NewObjectID().Hex())
+	if _, invalid := roomQueryFromRequest(&chat.ListRoomsRequest{Cursor: cursor}); invalid != "" {
+		t.Errorf("valid cursor rejected: %q", invalid)
+	}
+}
+
+func TestValidRoomSort(t *testing.T) {
+	for sort, want := range map[string]bool{
+		constants.RoomSortActivity: true,
+		constants.RoomSortUnread:   true,
+		constants.RoomSortName:     true,
+		"":                         false,
+		"pinned":                   false,
+	} {
+		if got := validRoomSort(sort); got != want {
+			t.Errorf("validRoomSort(%q) = %v, want %v", sort, got, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// KickMember 將成員移出聊天室（僅限擁有者或管理員）
+// 啟用 JWT 時操作者必須是已認證的用戶，避免冒充擁有者或管理員
+// 與 LeaveRoom 不同：操作者和被移出的成員不是同一人，系統訊息為「已被移出群組」並記錄操作者
+func (s *Server) KickMember(ctx context.Context, req *chat.KickMemberRequest) (*chat.KickMemberResponse, error) {
+	if req.UserId == "" || req.MemberId == "" {
+		return &chat.KickMemberResponse{
+			Success: false,
+			Message: "缺少操作者或被移出的成員",
+		}, nil
+	}
+	if req.UserId == req.MemberId {
+		return &chat.KickMemberResponse{
+			Success: false,
+			Message: "不能移出自己，請使用離開聊天室",
+		}, nil
+	}
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "kick member: operator is not the authenticated user")
+		return &chat.KickMemberResponse{
+			Success: false,
+			Message: "操作者與已認證的用戶不一致",
+		}, nil
+	}
+
+	room, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.KickMemberResponse{
+			Success: false,
+			Message: "聊天室不存在",
+		}, nil
+	}
+
+	if err := checkKick(room, req.UserId, req.MemberId); err != nil {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "kick member: "+err.Error())
+		return &chat.KickMemberResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if err := s.repos.ChatRoom.RemoveMember(ctx, req.RoomId, req.MemberId); err != nil {
+		logErrorWithUserAndRoom(ctx, "移出成員失敗", req.UserId, req.RoomId, err)
+		return &chat.KickMemberResponse{
+			Success: false,
+			Message: "移出成員失敗: " + err.Error(),
+		}, nil
+	}
+	s.senderMembership.Invalidate(req.RoomId, req.MemberId)
+
+	// 發送系統消息：XXX 已被移出群組
+	kicked := systemMessageText(room.Settings.Locale, constants.SystemMessageMemberKicked, map[string]string{"user": req.MemberId})
+	s.createSystemMessageAndUpdateRoom(ctx, req.RoomId, kicked, "創建移出成員系統消息失敗")
+
+	// 審計日誌
+	s.audit.LogMemberRemoved(ctx, req.UserId, req.RoomId, req.MemberId)
+
+	logger.Info(ctx, "移出成員成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("kick_member"),
+		logger.WithDetails(map[string]interface{}{"member_id": req.MemberId}))
+
+	return &chat.KickMemberResponse{
+		Success: true,
+		Message: "成員已移出聊天室",
+	}, nil
+}
+
+// checkKick 檢查操作者能否移出成員：擁有者不能被移出，管理員只能移出普通成員
+func checkKick(room *chatroom.ChatRoom, operatorID, memberID string) error {
+	if !canManageRoom(room, operatorID) {
+		return errors.New("只有聊天室擁有者或管理員可以移出成員")
+	}
+	if memberID == room.OwnerID {
+		return errors.New("不能移出聊天室擁有者")
+	}
+
+	for i := range room.Members {
+		if room.Members[i].UserID != memberID {
+			continue
+		}
+		if room.Members[i].Role == roleAdmin && operatorID != room.OwnerID {
+			return errors.New("只有聊天室擁有者可以移出管理員")
+		}
+		return nil
+	}
+	return errors.New("用戶不是聊天室成員")
+}
@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+func TestCheckKick(t *testing.T) {
+	room := &chatroom.ChatRoom{
+		OwnerID: "owner",
+		Members: []chatroom.RoomMember{
+			{UserID: "owner", Role: "member"},
+			{UserID: "admin", Role: roleAdmin},
+			{UserID: "admin2", Role: roleAdmin},
+			{UserID: "alice", Role: "member"},
+			{UserID: "bob", Role: "member"},
+		},
+	}
+
+	tests := []struct {
+		operator string
+		member   string
+		wantErr  bool
+	}{
+		{"owner", "alice", false},
+		{"owner", "admin", false},
+		{"admin", "alice", false},
+		{"admin", "admin2", true}, // 管理員只能由擁有者移出
+		{"admin", "owner", true},  // 擁有者不能被移出
+		{"alice", "bob", true},    // 普通成員沒有權限
+		{"owner", "stranger", true},
+	}
+	for _, tt := range tests {
+		if err := checkKick(room, tt.operator, tt.member); (err != nil) != tt.wantErr {
+			t.Errorf("checkKick(%s, %s) error = %v, wantErr %v", tt.operator, tt.member, err, tt.wantErr)
+		}
+	}
+}
+
+func TestKickMember_RejectsSelf(t *testing.T) {
+	// 沒有倉儲的 Server 一旦讀取聊天室就會 panic，踢出自己應在此之前返回
+	s := &Server{}
+	resp, err := s.KickMember(context.Background(), &chat.KickMemberRequest{
+		RoomId:   "507f1f77bcf86cd799439011",
+		UserId:   "alice",
+		MemberId: "alice",
+	})
+	if err != nil || resp.Success {
+		t.Errorf("KickMember() = %+v, %v; want Success=false", resp, err)
+	}
+}
+
+func TestKickMember_RejectsImpersonatedOperator(t *testing.T) {
+	// 認證用戶冒充擁有者：應在讀取聊天室之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.KickMember(ctx, &chat.KickMemberRequest{
+		RoomId:   "507f1f77bcf86cd799439011",
+		UserId:   "owner",
+		MemberId: "alice",
+	})
+	if err != nil || resp.Success {
+		t.Errorf("KickMember() = %+v, %v; want Success=false", resp, err)
+	}
+}
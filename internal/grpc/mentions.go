@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// maxMentionsPerMessage 單條訊息最多提及的用戶數量
+const maxMentionsPerMessage = 50
+
+// mentionPattern 訊息內容中的 @user_id（@ 前面不能是單詞字符，避免匹配郵箱地址）
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_.\-]+)`)
+
+// parseMentions 從訊息內容中解析 @user_id
+func parseMentions(content string) []string {
+	matches := mentionPattern.FindAllStringSubmatch(content, -1)
+	mentions := make([]string, 0, len(matches))
+	for _, m := range matches {
+		// 去掉句末標點，例如 "@alice."
+		if userID := strings.TrimRight(m[1], ".-"); userID != "" {
+			mentions = append(mentions, userID)
+		}
+	}
+	return mentions
+}
+
+// collectMentions 合併明確指定的提及和內容中解析的提及（去重並保持順序）
+func collectMentions(explicit []string, content string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+
+	add := func(userID string) {
+		userID = strings.TrimSpace(userID)
+		if userID == "" || seen[userID] || len(result) >= maxMentionsPerMessage {
+			return
+		}
+		seen[userID] = true
+		result = append(result, userID)
+	}
+
+	for _, userID := range explicit {
+		add(userID)
+	}
+	for _, userID := range parseMentions(content) {
+		add(userID)
+	}
+	return result
+}
+
+// filterMentionsByMembers 只保留聊天室成員
+func filterMentionsByMembers(mentions []string, members []chatroom.RoomMember) []string {
+	memberSet := make(map[string]bool, len(members))
+	for i := range members {
+		memberSet[members[i].UserID] = true
+	}
+
+	result := []string{}
+	for _, userID := range mentions {
+		if memberSet[userID] {
+			result = append(result, userID)
+		}
+	}
+	return result
+}
+
+// notificationRecipients 計算訊息需要通知的成員（不包括發送者）
+// 靜音的成員（包括未過期的限時靜音）不通知，但被提及時覆蓋靜音設置
+func notificationRecipients(members []chatroom.RoomMember, senderID string, mentions []string, now time.Time) []string {
+	mentioned := make(map[string]bool, len(mentions))
+	for _, userID := range mentions {
+		mentioned[userID] = true
+	}
+
+	recipients := []string{}
+	for i := range members {
+		member := &members[i]
+		if member.UserID == senderID {
+			continue
+		}
+		if member.IsMuted(now) && !mentioned[member.UserID] {
+			continue
+		}
+		recipients = append(recipients, member.UserID)
+	}
+	return recipients
+}
+
+// resolveMentions 解析並驗證訊息的提及用戶，非聊天室成員會被忽略
+func (s *Server) resolveMentions(ctx context.Context, req *chat.SendMessageRequest) []string {
+	candidates := collectMentions(req.Mentions, req.Content)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	members, err := s.repos.ChatRoom.GetMembers(ctx, req.RoomId)
+	if err != nil {
+		logger.Warning(ctx, "獲取聊天室成員失敗，忽略提及",
+			logger.WithRoomID(req.RoomId),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return nil
+	}
+
+	mentions := filterMentionsByMembers(candidates, members)
+	if len(mentions) == 0 {
+		return nil
+	}
+	return mentions
+}
+
+// notifyMentions 記錄提及通知的接收者（被提及的成員即使靜音也會收到通知）
+func (s *Server) notifyMentions(ctx context.Context, message *chatroom.Message) {
+	if len(message.Mentions) == 0 {
+		return
+	}
+
+	members, err := s.repos.ChatRoom.GetMembers(ctx, message.RoomID)
+	if err != nil {
+		logErrorWithRoom(ctx, "獲取提及通知接收者失敗", message.RoomID, err)
+		return
+	}
+
+	logger.Info(ctx, "訊息提及通知",
+		logger.WithRoomID(message.RoomID),
+		logger.WithMessageID(message.GetID()),
+		logger.WithDetails(map[string]interface{}{
+			"mentions":   message.Mentions,
+			"recipients": notificationRecipients(members, message.SenderID, message.Mentions, time.Now()),
+		}))
+}
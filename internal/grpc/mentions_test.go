@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"hello @alice and @bob", []string{"alice", "bob"}},
+		{"@alice: start of message", []string{"alice"}},
+		{"end of sentence @carol.", []string{"carol"}},
+		{"ids with dots @user.name_1", []string{"user.name_1"}},
+		{"email user@example.com is not a mention", []string{}},
+		{"double @@alice is not a mention", []string{}},
+		{"no mentions here", []string{}},
+		{"中文 @alice 你好", []string{"alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			if got := parseMentions(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMentions(%q) = %v, want %v", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectMentions(t *testing.T) {
+	got := collectMentions([]string{"bob", " alice ", ""}, "hi @alice @carol @bob")
+	want := []string{"bob", "alice", "carol"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("collectMentions() = %v, want %v", got, want)
+	}
+
+	// 超過上限時截斷
+	many := make([]string, maxMentionsPerMessage+10)
+	for i := range many {
+		many[i] = string(rune('a'+i%26)) + string(rune('0'+i/26))
+	}
+	if got := collectMentions(many, ""); len(got) != maxMentionsPerMessage {
+		t.Errorf("expected mentions capped at %d, got %d", maxMentionsPerMessage, len(got))
+	}
+}
+
+func TestFilterMentionsByMembers(t *testing.T) {
+	members := []chatroom.RoomMember{{UserID: "alice"}, {UserID: "bob"}}
+
+	got := filterMentionsByMembers([]string{"alice", "mallory", "bob"}, members)
+	want := []string{"alice", "bob"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filterMentionsByMembers() = %v, want %v", got, want)
+	}
+}
+
+func TestNotificationRecipients_MentionOverridesMute(t *testing.T) {
+	members := []chatroom.RoomMember{
+		{UserID: "sender"},
+		{UserID: "active"},
+		{UserID: "muted"},
+		{UserID: "muted-mentioned", Muted: true},
+	}
+	members[2].Muted = true
+
+	got := notificationRecipients(members, "sender", []string{"muted-mentioned", "sender"}, time.Now())
+	want := []string{"active", "muted-mentioned"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("notificationRecipients() = %v, want %v", got, want)
+	}
+
+	// 沒有提及時靜音成員不通知
+	got = notificationRecipients(members, "sender", nil, time.Now())
+	want = []string{"active"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("notificationRecipients() without mentions = %v, want %v", got, want)
+	}
+}
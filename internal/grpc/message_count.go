@@ -0,0 +1,184 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// countableMessageTypes 可以按類型統計的訊息類型
+var countableMessageTypes = []string{
+	messageTypeText, messageTypeImage, messageTypeFile, messageTypeAudio,
+	messageTypeVideo, messageTypeLocation, systemSenderID,
+}
+
+// messageCountKey 訊息數量緩存的鍵（聊天室 + 過濾條件）
+type messageCountKey struct {
+	roomID  string
+	msgType string
+	since   int64 // Unix 秒，0 表示不限
+	until   int64
+}
+
+// query 轉換為存儲層的統計條件
+func (k messageCountKey) query() chatroom.MessageCountQuery {
+	query := chatroom.MessageCountQuery{RoomID: k.roomID, Type: k.msgType}
+	if k.since > 0 {
+		since := time.Unix(k.since, 0).UTC()
+		query.Since = &since
+	}
+	if k.until > 0 {
+		until := time.Unix(k.until, 0).UTC()
+		query.Until = &until
+	}
+	return query
+}
+
+// messageCountEntry 緩存的訊息數量
+type messageCountEntry struct {
+	count     int64
+	expiresAt time.Time
+}
+
+// messageCountCache 短時間緩存聊天室的訊息數量，避免熱門聊天室每次查詢都重新統計
+// 只按時間過期，不在發送訊息時失效：數量最多落後緩存時間內發送的訊息
+type messageCountCache struct {
+	mu         sync.Mutex
+	entries    map[messageCountKey]messageCountEntry
+	count      func(ctx context.Context, query chatroom.MessageCountQuery) (int64, error)
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+// newMessageCountCache 創建訊息數量緩存
+func newMessageCountCache(count func(ctx context.Context, query chatroom.MessageCountQuery) (int64, error), ttl time.Duration, maxEntries int) *messageCountCache {
+	return &messageCountCache{
+		entries:    make(map[messageCountKey]messageCountEntry),
+		count:      count,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Count 返回訊息數量，優先使用未過期的緩存
+func (c *messageCountCache) Count(ctx context.Context, key messageCountKey) (int64, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry.count, nil
+	}
+
+	count, err := c.count(ctx, key.query())
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.maxEntries {
+		c.sweepUnsafe()
+	}
+	if _, exists := c.entries[key]; exists || len(c.entries) < c.maxEntries {
+		c.entries[key] = messageCountEntry{count: count, expiresAt: c.now().Add(c.ttl)}
+	}
+	return count, nil
+}
+
+// sweepUnsafe 清理過期條目（調用者需持有鎖）
+func (c *messageCountCache) sweepUnsafe() {
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// newRoomMessageCountCache 創建查詢聊天室訊息數量使用的緩存
+func (s *Server) newRoomMessageCountCache() *messageCountCache {
+	return newMessageCountCache(s.repos.Message.CountMessages,
+		time.Duration(constants.MessageCountCacheTTLSec)*time.Second,
+		constants.MessageCountCacheMaxEntries)
+}
+
+// messageCountKeyFromRequest 驗證並轉換 GetRoomMessageCount 的過濾條件
+func messageCountKeyFromRequest(req *chat.GetRoomMessageCountRequest) (messageCountKey, string) {
+	key := messageCountKey{roomID: req.RoomId, msgType: req.Type, since: req.Since, until: req.Until}
+
+	if err := middleware.ValidateRoomID(req.RoomId); err != nil {
+		return key, err.Error()
+	}
+	if req.Type != "" && !slices.Contains(countableMessageTypes, req.Type) {
+		return key, "不支持的訊息類型: " + req.Type
+	}
+	if req.Since < 0 || req.Until < 0 {
+		return key, "時間不能小於 0"
+	}
+	if req.Since > 0 && req.Until > 0 && req.Since > req.Until {
+		return key, "since 不能晚於 until"
+	}
+	return key, ""
+}
+
+// GetRoomMessageCount 獲取聊天室訊息總數（可按類型和創建時間過濾，不包含已過期的訊息）
+// 請求者必須是聊天室成員（JWT 管理員可以查詢任何聊天室）；結果緩存 MessageCountCacheTTLSec 秒
+func (s *Server) GetRoomMessageCount(ctx context.Context, req *chat.GetRoomMessageCountRequest) (*chat.GetRoomMessageCountResponse, error) {
+	key, invalid := messageCountKeyFromRequest(req)
+	if invalid != "" {
+		return getRoomMessageCountFailure(constants.ErrorCodeInvalidArgument, invalid), nil
+	}
+
+	if !isRoomAdmin(config.Get(), middleware.UserIDFromContext(ctx)) {
+		if req.UserId == "" {
+			return getRoomMessageCountFailure(constants.ErrorCodeInvalidArgument, "缺少 user_id"), nil
+		}
+		if s.rejectImpersonation(ctx, req.UserId, req.RoomId, "get room message count") {
+			return getRoomMessageCountFailure(constants.ErrorCodePermissionDenied, "您不是此聊天室的成員"), nil
+		}
+		isMember, err := s.repos.ChatRoom.IsMember(ctx, req.RoomId, req.UserId)
+		if err != nil {
+			logErrorWithUserAndRoom(ctx, "檢查成員失敗", req.UserId, req.RoomId, err)
+			return getRoomMessageCountFailure(constants.ErrorCodeInternal, "檢查成員失敗"), nil
+		}
+		if !isMember {
+			s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "get room message count: not a room member")
+			return getRoomMessageCountFailure(constants.ErrorCodePermissionDenied, "您不是此聊天室的成員"), nil
+		}
+	}
+
+	count, err := s.messageCounts.Count(ctx, key)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "統計訊息數量失敗", req.UserId, req.RoomId, err)
+		return getRoomMessageCountFailure(constants.ErrorCodeInternal, "統計訊息數量失敗"), nil
+	}
+
+	logger.Debug(ctx, "獲取聊天室訊息數量成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("get_room_message_count"))
+
+	return &chat.GetRoomMessageCountResponse{
+		Success: true,
+		Message: "獲取訊息數量成功",
+		Count:   count,
+	}, nil
+}
+
+func getRoomMessageCountFailure(code, message string) *chat.GetRoomMessageCountResponse {
+	return &chat.GetRoomMessageCountResponse{
+		Success:   false,
+		Message:   message,
+		ErrorCode: code,
+	}
+}
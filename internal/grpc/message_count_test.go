@@ -0,0 +1,141 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+type fakeMessageCounter struct {
+	count int64
+	err   error
+	calls int
+	last  chatroom.MessageCountQuery
+}
+
+func (f *fakeMessageCounter) countMessages(_ context.Context, query chatroom.MessageCountQuery) (int64, error) {
+	f.calls++
+	f.last = query
+	return f.count, f.err
+}
+
+func newTestMessageCountCache(f *fakeMessageCounter, now *time.Time) *messageCountCache {
+	c := newMessageCountCache(f.countMessages, 10*time.Second, 2)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestMessageCountCache_CachesWithinTTL(t *testing.T) {
+	now := time.Now()
+	f := &fakeMessageCounter{count: 42}
+	c := newTestMessageCountCache(f, &now)
+	key := messageCountKey{roomID: "room-1"}
+
+	for i := 0; i < 3; i++ {
+		if count, err := c.Count(context.Background(), key); count != 42 || err != nil {
+			t.Fatalf("Count() = %d, %v, want 42, nil", count, err)
+		}
+	}
+	if f.calls != 1 {
+		t.Errorf("counts = %d, want 1 within TTL", f.calls)
+	}
+
+	f.count = 43
+	now = now.Add(10 * time.Second)
+	if count, _ := c.Count(context.Background(), key); count != 43 {
+		t.Errorf("Count() = %d, want 43 after TTL", count)
+	}
+	if f.calls != 2 {
+		t.Errorf("counts = %d, want 2 after TTL", f.calls)
+	}
+}
+
+func TestMessageCountCache_KeysByFilter(t *testing.T) {
+	now := time.Now()
+	f := &fakeMessageCounter{count: 1}
+	c := newTestMessageCountCache(f, &now)
+
+	if _, err := c.Count(context.Background(), messageCountKey{roomID: "room-1"}); err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if _, err := c.Count(context.Background(), messageCountKey{roomID: "room-1", msgType: messageTypeImage, since: 100}); err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if f.calls != 2 {
+		t.Errorf("counts = %d, want 2 for different filters", f.calls)
+	}
+	if f.last.Type != messageTypeImage || f.last.Since == nil || f.last.Since.Unix() != 100 || f.last.Until != nil {
+		t.Errorf("query = %+v, want type image since 100", f.last)
+	}
+}
+
+func TestMessageCountCache_DoesNotCacheErrors(t *testing.T) {
+	now := time.Now()
+	f := &fakeMessageCounter{err: errors.New("db down")}
+	c := newTestMessageCountCache(f, &now)
+	key := messageCountKey{roomID: "room-1"}
+
+	if _, err := c.Count(context.Background(), key); err == nil {
+		t.Fatal("expected error")
+	}
+	f.err, f.count = nil, 5
+	if count, err := c.Count(context.Background(), key); count != 5 || err != nil {
+		t.Errorf("Count() = %d, %v, want 5, nil", count, err)
+	}
+}
+
+func TestMessageCountCache_BoundedEntries(t *testing.T) {
+	now := time.Now()
+	f := &fakeMessageCounter{count: 1}
+	c := newTestMessageCountCache(f, &now)
+
+	for _, room := range []string{"room-1", "room-2", "room-3"} {
+		if _, err := c.Count(context.Background(), messageCountKey{roomID: room}); err != nil {
+			t.Fatalf("Count() error = %v", err)
+		}
+	}
+	if len(c.entries) > 2 {
+		t.Errorf("entries = %d, want at most 2", len(c.entries))
+	}
+}
+
+func TestMessageCountKeyFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *chat.GetRoomMessageCountRequest
+		invalid bool
+	}{
+		{"all messages", &chat.GetRoomMessageCountRequest{RoomId: "507f1f77bcf86cd799439011"}, false},
+		{"by type and range", &chat.GetRoomMessageCountRequest{RoomId: "507f1f77bcf86cd799439011", Type: messageTypeImage, Since: 100, Until: 200}, false},
+		{"system messages", &chat.GetRoomMessageCountRequest{RoomId: "507f1f77bcf86cd799439011", Type: systemSenderID}, false},
+		{"missing room", &chat.GetRoomMessageCountRequest{}, true},
+		{"unknown type", &chat.GetRoomMessageCountRequest{RoomId: "507f1f77bcf86cd799439011", Type: "sticker"}, true},
+		{"negative since", &chat.GetRoomMessageCountRequest{RoomId: "507f1f77bcf86cd799439011", Since: -1}, true},
+		{"since after until", &chat.GetRoomMessageCountRequest{RoomId: "507f1f77bcf86cd799439011", Since: 200, Until: 100}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, invalid := messageCountKeyFromRequest(tt.req)
+			if (invalid != "") != tt.invalid {
+				t.Errorf("messageCountKeyFromRequest() invalid = %q, want invalid %v", invalid, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestGetRoomMessageCount_InvalidRequest(t *testing.T) {
+	s := &Server{}
+	resp, err := s.GetRoomMessageCount(context.Background(), &chat.GetRoomMessageCountRequest{RoomId: "507f1f77bcf86cd799439011", Type: "sticker"})
+	if err != nil {
+		t.Fatalf("GetRoomMessageCount() error = %v", err)
+	}
+	if resp.Success || resp.ErrorCode != constants.ErrorCodeInvalidArgument {
+		t.Errorf("GetRoomMessageCount() = %+v, want invalid_argument failure", resp)
+	}
+}
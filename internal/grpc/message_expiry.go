@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// maxMessageTTLSeconds 限時訊息最長的存活時間
+func maxMessageTTLSeconds() int {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Message.MaxTTLSeconds > 0 {
+		return cfg.Limits.Message.MaxTTLSeconds
+	}
+	return constants.DefaultMaxMessageTTLSeconds
+}
+
+// validateMessageTTL 驗證限時訊息的存活時間（0 表示不會過期）
+func validateMessageTTL(ttlSeconds int32) error {
+	if ttlSeconds < 0 {
+		return fmt.Errorf("限時訊息的存活時間不能小於 0")
+	}
+	if maxTTL := maxMessageTTLSeconds(); int(ttlSeconds) > maxTTL {
+		return fmt.Errorf("限時訊息的存活時間不能超過 %d 秒", maxTTL)
+	}
+	return nil
+}
+
+// expiresAtToGRPC 限時訊息的過期時間（Unix 秒），不會過期的訊息返回 0
+func expiresAtToGRPC(msg *chatroom.Message) int64 {
+	if msg.ExpiresAt == nil {
+		return 0
+	}
+	return msg.ExpiresAt.Unix()
+}
+
+// expiryTracker 記錄訊息流已推送的限時訊息，到期後推送過期事件讓客戶端從畫面移除
+// 只在單個訊息流的 goroutine 中使用，不需要加鎖
+type expiryTracker struct {
+	pending map[string]*chat.ChatMessage // 訊息 ID -> 過期事件
+}
+
+func newExpiryTracker() *expiryTracker {
+	return &expiryTracker{pending: make(map[string]*chat.ChatMessage)}
+}
+
+// Track 記錄已推送的訊息（不是限時訊息時忽略）
+func (t *expiryTracker) Track(msg *chat.ChatMessage) {
+	if msg.ExpiresAt == 0 || msg.Expired {
+		return
+	}
+	t.pending[msg.Id] = &chat.ChatMessage{
+		Id:        msg.Id,
+		RoomId:    msg.RoomId,
+		ExpiresAt: msg.ExpiresAt,
+		Expired:   true,
+	}
+}
+
+// Due 取出已到期的過期事件（按過期時間升序）
+func (t *expiryTracker) Due(now time.Time) []*chat.ChatMessage {
+	var due []*chat.ChatMessage
+	for id, event := range t.pending {
+		if event.ExpiresAt <= now.Unix() {
+			due = append(due, event)
+			delete(t.pending, id)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].ExpiresAt != due[j].ExpiresAt {
+			return due[i].ExpiresAt < due[j].ExpiresAt
+		}
+		return due[i].Id < due[j].Id
+	})
+	return due
+}
+
+// trackExpiring 包裝推送函數，推送成功的限時訊息交給 tracker 記錄
+func trackExpiring(tracker *expiryTracker, send func(*chat.ChatMessage) error) func(*chat.ChatMessage) error {
+	return func(msg *chat.ChatMessage) error {
+		if err := send(msg); err != nil {
+			return err
+		}
+		tracker.Track(msg)
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package grpc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/proto/chat"
+)
+
+func TestValidateMessageTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     int32
+		wantErr bool
+	}{
+		{"no expiry", 0, false},
+		{"one minute", 60, false},
+		{"max", constants.DefaultMaxMessageTTLSeconds, false},
+		{"negative", -1, true},
+		{"beyond max", constants.DefaultMaxMessageTTLSeconds + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMessageTTL(tt.ttl); (err != nil) != tt.wantErr {
+				t.Errorf("validateMessageTTL(%d) error = %v, wantErr %v", tt.ttl, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpiryTracker_Due(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tracker := newExpiryTracker()
+	tracker.Track(&chat.ChatMessage{Id: "permanent", RoomId: "r1"})
+	tracker.Track(&chat.ChatMessage{Id: "later", RoomId: "r1", ExpiresAt: now.Unix() + 60})
+	tracker.Track(&chat.ChatMessage{Id: "b", RoomId: "r1", ExpiresAt: now.Unix()})
+	tracker.Track(&chat.ChatMessage{Id: "a", RoomId: "r1", ExpiresAt: now.Unix() - 5})
+
+	due := tracker.Due(now)
+	if len(due) != 2 || due[0].Id != "a" || due[1].Id != "b" {
+		t.Fatalf("Due() = %v, want [a b]", due)
+	}
+	if !due[0].Expired || due[0].RoomId != "r1" || due[0].Content != "" {
+		t.Errorf("event = %+v, want expired event with only id and room", due[0])
+	}
+
+	// 已推送的過期事件不會重複推送
+	if again := tracker.Due(now); len(again) != 0 {
+		t.Errorf("second Due() = %v, want none", again)
+	}
+	if later := tracker.Due(now.Add(time.Minute)); len(later) != 1 || later[0].Id != "later" {
+		t.Errorf("Due() after a minute = %v, want [later]", later)
+	}
+}
+
+func TestTrackExpiring_OnlyTracksSentMessages(t *testing.T) {
+	tracker := newExpiryTracker()
+	failing := trackExpiring(tracker, func(*chat.ChatMessage) error { return errors.New("closed") })
+	if err := failing(&chat.ChatMessage{Id: "m1", ExpiresAt: 1}); err == nil {
+		t.Fatal("expected send error")
+	}
+	if len(tracker.pending) != 0 {
+		t.Errorf("pending = %v, want none after failed send", tracker.pending)
+	}
+
+	ok := trackExpiring(tracker, func(*chat.ChatMessage) error { return nil })
+	if err := ok(&chat.ChatMessage{Id: "m2", ExpiresAt: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, tracked := tracker.pending["m2"]; !tracked {
+		t.Error("m2 should be tracked after a successful send")
+	}
+}
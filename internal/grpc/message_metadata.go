@@ -0,0 +1,119 @@
+package grpc
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// 訊息類型（系統訊息由服務端創建，不允許客戶端發送）
+const (
+	messageTypeText     = "text"
+	messageTypeImage    = "image"
+	messageTypeFile     = "file"
+	messageTypeAudio    = "audio"
+	messageTypeVideo    = "video"
+	messageTypeLocation = "location"
+)
+
+// validateMessageMetadata 驗證訊息類型及該類型必需的元數據
+// image 需要 image_url；file / audio / video 需要 file_url 和 file_type；location 需要有效的經緯度
+func validateMessageMetadata(msgType, content string, metadata *chat.MessageMetadata) error {
+	switch msgType {
+	case messageTypeText:
+		if strings.TrimSpace(content) == "" {
+			return fmt.Errorf("文字訊息內容不能為空")
+		}
+		return nil
+
+	case messageTypeImage:
+		if metadata == nil || metadata.ImageUrl == "" {
+			return fmt.Errorf("圖片訊息缺少 metadata.image_url")
+		}
+		if err := validateMediaURL("image_url", metadata.ImageUrl); err != nil {
+			return err
+		}
+		if metadata.ImageThumbnail != "" {
+			if err := validateMediaURL("image_thumbnail", metadata.ImageThumbnail); err != nil {
+				return err
+			}
+		}
+		if metadata.ImageWidth < 0 || metadata.ImageHeight < 0 {
+			return fmt.Errorf("圖片尺寸不能為負數")
+		}
+		return nil
+
+	case messageTypeFile, messageTypeAudio, messageTypeVideo:
+		if metadata == nil || metadata.FileUrl == "" || metadata.FileType == "" {
+			return fmt.Errorf("%s 訊息缺少 metadata.file_url 或 metadata.file_type", msgType)
+		}
+		return validateMediaURL("file_url", metadata.FileUrl)
+
+	case messageTypeLocation:
+		if metadata == nil || (metadata.Latitude == 0 && metadata.Longitude == 0) {
+			return fmt.Errorf("位置訊息缺少 metadata.latitude 和 metadata.longitude")
+		}
+		if metadata.Latitude < -90 || metadata.Latitude > 90 {
+			return fmt.Errorf("緯度必須在 -90 到 90 之間")
+		}
+		if metadata.Longitude < -180 || metadata.Longitude > 180 {
+			return fmt.Errorf("經度必須在 -180 到 180 之間")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("不支持的訊息類型: %q（支持 text, image, file, audio, video, location）", msgType)
+	}
+}
+
+// validateMediaURL 媒體地址必須是 http(s) 絕對地址，避免 javascript: 等危險協議
+func validateMediaURL(field, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("metadata.%s 必須是有效的 http(s) 地址", field)
+	}
+	return nil
+}
+
+// metadataFromGRPC 轉換 gRPC 訊息元數據為數據模型
+func metadataFromGRPC(metadata *chat.MessageMetadata) chatroom.MessageMetadata {
+	if metadata == nil {
+		return chatroom.MessageMetadata{}
+	}
+	return chatroom.MessageMetadata{
+		FileName:       metadata.FileName,
+		FileSize:       metadata.FileSize,
+		FileType:       metadata.FileType,
+		FileURL:        metadata.FileUrl,
+		ImageURL:       metadata.ImageUrl,
+		ImageThumbnail: metadata.ImageThumbnail,
+		ImageWidth:     metadata.ImageWidth,
+		ImageHeight:    metadata.ImageHeight,
+		Latitude:       metadata.Latitude,
+		Longitude:      metadata.Longitude,
+		LocationName:   metadata.LocationName,
+	}
+}
+
+// metadataToGRPC 轉換訊息元數據為 gRPC 格式，沒有元數據時返回 nil
+func metadataToGRPC(metadata *chatroom.MessageMetadata) *chat.MessageMetadata {
+	if *metadata == (chatroom.MessageMetadata{}) {
+		return nil
+	}
+	return &chat.MessageMetadata{
+		FileName:       metadata.FileName,
+		FileSize:       metadata.FileSize,
+		FileType:       metadata.FileType,
+		FileUrl:        metadata.FileURL,
+		ImageUrl:       metadata.ImageURL,
+		ImageThumbnail: metadata.ImageThumbnail,
+		ImageWidth:     metadata.ImageWidth,
+		ImageHeight:    metadata.ImageHeight,
+		Latitude:       metadata.Latitude,
+		Longitude:      metadata.Longitude,
+		LocationName:   metadata.LocationName,
+	}
+}
@@ -0,0 +1,95 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/internal/security/encryption"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+func TestValidateMessageMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		msgType  string
+		content  string
+		metadata *chat.MessageMetadata
+		wantErr  bool
+	}{
+		{"text", "text", "hello", nil, false},
+		{"empty text", "text", "  ", nil, true},
+		{"unknown type", "sticker", "hi", nil, true},
+		{"system type rejected", "system", "hi", nil, true},
+
+		{"image", "image", "", &chat.MessageMetadata{ImageUrl: "https://cdn.example.com/a.png"}, false},
+		{"image without url", "image", "", &chat.MessageMetadata{}, true},
+		{"image without metadata", "image", "", nil, true},
+		{"image with javascript url", "image", "", &chat.MessageMetadata{ImageUrl: "javascript:alert(1)"}, true},
+		{"image with bad thumbnail", "image", "", &chat.MessageMetadata{
+			ImageUrl: "https://cdn.example.com/a.png", ImageThumbnail: "data:image/png;base64,AAAA",
+		}, true},
+		{"image with negative size", "image", "", &chat.MessageMetadata{
+			ImageUrl: "https://cdn.example.com/a.png", ImageWidth: -1,
+		}, true},
+
+		{"file", "file", "", &chat.MessageMetadata{FileUrl: "https://cdn.example.com/a.pdf", FileType: "application/pdf"}, false},
+		{"file without type", "file", "", &chat.MessageMetadata{FileUrl: "https://cdn.example.com/a.pdf"}, true},
+		{"file without url", "file", "", &chat.MessageMetadata{FileType: "application/pdf"}, true},
+		{"audio", "audio", "", &chat.MessageMetadata{FileUrl: "https://cdn.example.com/a.m4a", FileType: "audio/mp4"}, false},
+		{"video without metadata", "video", "", nil, true},
+
+		{"location", "location", "", &chat.MessageMetadata{Latitude: 25.033, Longitude: 121.5654}, false},
+		{"location without coordinates", "location", "", &chat.MessageMetadata{LocationName: "Taipei"}, true},
+		{"latitude out of range", "location", "", &chat.MessageMetadata{Latitude: 91, Longitude: 121}, true},
+		{"longitude out of range", "location", "", &chat.MessageMetadata{Latitude: 25, Longitude: -181}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMessageMetadata(tt.msgType, tt.content, tt.metadata)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateMessageMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMessageMetadataRoundTrip(t *testing.T) {
+	in := &chat.MessageMetadata{
+		FileName: "a.pdf",
+		FileType: "application/pdf",
+		FileUrl:  "https://cdn.example.com/a.pdf",
+	}
+
+	stored := metadataFromGRPC(in)
+	out := metadataToGRPC(&stored)
+	if out.GetFileName() != in.FileName || out.GetFileType() != in.FileType || out.GetFileUrl() != in.FileUrl {
+		t.Errorf("round trip = %v, want %v", out, in)
+	}
+
+	empty := metadataFromGRPC(nil)
+	if metadataToGRPC(&empty) != nil {
+		t.Error("expected nil metadata for messages without metadata")
+	}
+}
+
+func TestConvertMessagesToGRPC_IncludesMetadata(t *testing.T) {
+	s := &Server{encryption: encryption.NewMessageEncryption(false, nil)}
+
+	msg := chatroom.NewMessage()
+	msg.RoomID = "room-1"
+	msg.SenderID = "user-1"
+	msg.Type = messageTypeImage
+	msg.Metadata = chatroom.MessageMetadata{ImageURL: "https://cdn.example.com/a.png", ImageWidth: 800}
+
+	got := s.convertMessagesToGRPC(context.Background(), []*chatroom.Message{&msg})
+	if len(got) != 1 || got[0].GetMetadata().GetImageUrl() != msg.Metadata.ImageURL || got[0].GetMetadata().GetImageWidth() != 800 {
+		t.Fatalf("metadata not propagated: %v", got)
+	}
+
+	resp := s.buildMessageResponse(context.Background(), &msg)
+	if resp.GetMetadata().GetImageUrl() != msg.Metadata.ImageURL {
+		t.Errorf("buildMessageResponse metadata = %v", resp.GetMetadata())
+	}
+}
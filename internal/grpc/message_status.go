@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// storedMessageStatus 訊息存儲的狀態（舊訊息沒有狀態時視為 sent）
+func storedMessageStatus(msg *chatroom.Message) string {
+	if msg.Status == "" {
+		return chatroom.MessageStatusSent
+	}
+	return msg.Status
+}
+
+// optionalMessageIDs 把可選的單個訊息 ID 轉成列表（空字串表示整個聊天室）
+func optionalMessageIDs(messageID string) []string {
+	if messageID == "" {
+		return nil
+	}
+	return []string{messageID}
+}
+
+// roomMemberIDs 獲取聊天室成員 ID 列表
+func (s *Server) roomMemberIDs(ctx context.Context, roomID string) ([]string, error) {
+	members, err := s.repos.ChatRoom.GetMembers(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(members))
+	for i := range members {
+		ids[i] = members[i].UserID
+	}
+	return ids, nil
+}
+
+// advanceMessageStatus 送達或已讀後推進訊息的存儲狀態
+// 失敗只記錄警告：回應中的狀態在查詢時會按成員重新計算
+func (s *Server) advanceMessageStatus(ctx context.Context, roomID string, messageIDs []string) {
+	memberIDs, err := s.roomMemberIDs(ctx, roomID)
+	if err == nil {
+		err = s.repos.Message.AdvanceStatus(ctx, roomID, memberIDs, messageIDs)
+	}
+	if err != nil {
+		logger.Warning(ctx, "更新訊息狀態失敗",
+			logger.WithRoomID(roomID),
+			logger.WithDetails(map[string]interface{}{
+				"count": len(messageIDs),
+				"error": err.Error(),
+			}))
+	}
+}
+
+// applyAggregateStatus 按當前成員重新計算回應中的訊息狀態
+// 成員變動（加入/離開）後存儲的狀態可能過時；獲取成員失敗時保留存儲的狀態
+func (s *Server) applyAggregateStatus(ctx context.Context, roomID string, messages []*chatroom.Message, grpcMessages []*chat.ChatMessage) {
+	if len(messages) == 0 {
+		return
+	}
+
+	memberIDs, err := s.roomMemberIDs(ctx, roomID)
+	if err != nil {
+		logger.Warning(ctx, "獲取聊天室成員失敗，使用存儲的訊息狀態",
+			logger.WithRoomID(roomID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	for i, msg := range messages {
+		grpcMessages[i].Status = msg.AggregateStatus(memberIDs)
+	}
+}
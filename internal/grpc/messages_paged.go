@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database"
+	"chat-gateway/proto/chat"
+)
+
+// GetMessagesPaged 管理員按頁碼瀏覽聊天室訊息（按創建時間倒序，skip/limit 分頁），返回訊息總數
+// 與 GetMessages 的游標分頁分開：skip 越大查詢越慢，只用於管理工具，不用於客戶端滾動
+// limit 和 skip 按 database.ValidateLimit / ValidateSkip 截斷；每次調用（包括被拒絕的）都寫入審計日誌
+func (s *Server) GetMessagesPaged(ctx context.Context, req *chat.GetMessagesPagedRequest) (*chat.GetMessagesPagedResponse, error) {
+	authUserID := middleware.UserIDFromContext(ctx)
+	if !isRoomAdmin(config.Get(), authUserID) {
+		s.audit.LogAccessDenied(ctx, authUserID, req.RoomId, "get messages paged: not a room admin")
+		return getMessagesPagedFailure(constants.ErrorCodePermissionDenied, "只有管理員可以按頁碼瀏覽訊息"), nil
+	}
+
+	if err := middleware.ValidateRoomID(req.RoomId); err != nil {
+		return getMessagesPagedFailure(constants.ErrorCodeInvalidArgument, err.Error()), nil
+	}
+	limit := database.ValidateLimit(int(req.Limit))
+	skip := database.ValidateSkip(int(req.Skip))
+
+	messages, total, err := s.repos.Message.GetPaged(ctx, req.RoomId, limit, skip)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "按頁碼獲取訊息失敗", authUserID, req.RoomId, err)
+		return getMessagesPagedFailure(constants.ErrorCodeInternal, "獲取消息失敗"), nil
+	}
+
+	grpcMessages := s.convertMessagesToGRPC(ctx, messages)
+
+	s.audit.LogSecurityEvent(ctx, "admin_get_messages_paged", "管理員按頁碼瀏覽訊息", "medium", map[string]interface{}{
+		"user_id":  authUserID,
+		"room_id":  req.RoomId,
+		"limit":    limit,
+		"skip":     skip,
+		"returned": len(grpcMessages),
+	})
+
+	logger.Debug(ctx, "按頁碼獲取訊息成功",
+		logger.WithUserID(authUserID),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("get_messages_paged"))
+
+	return &chat.GetMessagesPagedResponse{
+		Success:    true,
+		Message:    "獲取消息成功",
+		Messages:   grpcMessages,
+		TotalCount: total,
+		Limit:      int32(limit), // #nosec G115 -- capped by ValidateLimit
+		Skip:       int32(skip),  // #nosec G115 -- capped by ValidateSkip
+	}, nil
+}
+
+func getMessagesPagedFailure(code, message string) *chat.GetMessagesPagedResponse {
+	return &chat.GetMessagesPagedResponse{
+		Success:   false,
+		Message:   message,
+		ErrorCode: code,
+	}
+}
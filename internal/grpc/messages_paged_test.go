@@ -0,0 +1,23 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/proto/chat"
+)
+
+func TestGetMessagesPaged_RequiresAdmin(t *testing.T) {
+	// 沒有倉儲的 Server 一旦查詢訊息就會 panic，非管理員應在此之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+
+	resp, err := s.GetMessagesPaged(context.Background(), &chat.GetMessagesPagedRequest{
+		RoomId: "507f1f77bcf86cd799439011",
+		Skip:   100,
+	})
+	if err != nil || resp.Success || resp.ErrorCode != constants.ErrorCodePermissionDenied {
+		t.Errorf("GetMessagesPaged() = %+v, %v; want permission_denied", resp, err)
+	}
+}
@@ -0,0 +1,166 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// presenceTouchFunc 寫入用戶在所有所屬聊天室中的 last_seen
+type presenceTouchFunc func(ctx context.Context, userID string, seenAt time.Time) error
+
+// presenceTracker 記錄成員的最後在線時間
+// 發送、已讀、訊息流都會觸發，每個用戶在寫入間隔內最多寫入一次資料庫
+type presenceTracker struct {
+	mu        sync.Mutex
+	lastWrite map[string]time.Time // userID -> 上次寫入時間
+	lastSweep time.Time
+	touch     presenceTouchFunc
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// newPresenceTracker 創建在線狀態記錄器
+func newPresenceTracker(touch presenceTouchFunc, interval time.Duration) *presenceTracker {
+	return &presenceTracker{
+		lastWrite: make(map[string]time.Time),
+		touch:     touch,
+		interval:  interval,
+		now:       time.Now,
+	}
+}
+
+// Touch 記錄用戶在線，距離上次寫入不足間隔時跳過
+func (p *presenceTracker) Touch(ctx context.Context, userID string) {
+	if userID == "" {
+		return
+	}
+
+	now := p.now()
+
+	p.mu.Lock()
+	if last, ok := p.lastWrite[userID]; ok && now.Sub(last) < p.interval {
+		p.mu.Unlock()
+		return
+	}
+	// 先佔位，避免同一用戶的並發請求重複寫入
+	p.lastWrite[userID] = now
+	p.sweepUnsafe(now)
+	p.mu.Unlock()
+
+	if err := p.touch(ctx, userID, now); err != nil {
+		logger.Warning(ctx, "更新成員最後在線時間失敗",
+			logger.WithUserID(userID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+
+		// 寫入失敗時允許下一次活動立即重試
+		p.mu.Lock()
+		if p.lastWrite[userID].Equal(now) {
+			delete(p.lastWrite, userID)
+		}
+		p.mu.Unlock()
+	}
+}
+
+// sweepUnsafe 每個間隔清理一次已過間隔的條目，記錄數量只和最近活躍的用戶數有關（調用者需持有鎖）
+func (p *presenceTracker) sweepUnsafe(now time.Time) {
+	if now.Sub(p.lastSweep) < p.interval {
+		return
+	}
+	p.lastSweep = now
+
+	for userID, last := range p.lastWrite {
+		if now.Sub(last) >= p.interval {
+			delete(p.lastWrite, userID)
+		}
+	}
+}
+
+// presenceUpdateInterval 同一用戶兩次寫入 last_seen 的最小間隔
+func presenceUpdateInterval() time.Duration {
+	seconds := constants.DefaultPresenceUpdateIntervalSec
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Presence.UpdateIntervalSec > 0 {
+		seconds = cfg.Limits.Presence.UpdateIntervalSec
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// presenceOnlineWindow last_seen 在此窗口內的成員視為在線
+func presenceOnlineWindow() time.Duration {
+	seconds := constants.DefaultPresenceOnlineWindowSec
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Presence.OnlineWindowSec > 0 {
+		seconds = cfg.Limits.Presence.OnlineWindowSec
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// onlineMembers 篩選 last_seen 在窗口內的成員
+func onlineMembers(members []chatroom.RoomMember, now time.Time, window time.Duration) []chatroom.RoomMember {
+	since := now.Add(-window)
+	online := []chatroom.RoomMember{}
+	for i := range members {
+		if !members[i].LastSeen.Before(since) {
+			online = append(online, members[i])
+		}
+	}
+	return online
+}
+
+// touchPresence 記錄用戶在線（未初始化資料庫時忽略）
+func (s *Server) touchPresence(ctx context.Context, userID string) {
+	if s.presence != nil {
+		s.presence.Touch(ctx, userID)
+	}
+}
+
+// GetOnlineMembers 獲取最近在線的聊天室成員（只有成員可以查詢）
+func (s *Server) GetOnlineMembers(ctx context.Context, req *chat.GetOnlineMembersRequest) (*chat.GetOnlineMembersResponse, error) {
+	if req.UserId == "" {
+		return &chat.GetOnlineMembersResponse{
+			Success: false,
+			Message: "缺少 user_id",
+		}, nil
+	}
+	if s.rejectImpersonation(ctx, req.UserId, req.RoomId, "get online members") {
+		return &chat.GetOnlineMembersResponse{
+			Success: false,
+			Message: "您不是此聊天室的成員",
+		}, nil
+	}
+
+	// 查詢本身也是一次活動，先記錄以便請求者出現在結果中
+	s.touchPresence(ctx, req.UserId)
+
+	room, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.GetOnlineMembersResponse{
+			Success: false,
+			Message: "獲取聊天室失敗",
+		}, nil
+	}
+
+	if room.FindMember(req.UserId) == nil {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "get online members: not a room member")
+		return &chat.GetOnlineMembersResponse{
+			Success: false,
+			Message: "您不是此聊天室的成員",
+		}, nil
+	}
+
+	window := presenceOnlineWindow()
+	online := onlineMembers(room.Members, time.Now(), window)
+
+	return &chat.GetOnlineMembersResponse{
+		Success:             true,
+		Message:             "獲取在線成員成功",
+		Members:             convertMembersToGRPC(online),
+		OnlineWindowSeconds: int32(window / time.Second), // #nosec G115 -- 窗口來自配置，遠小於 int32 上限
+	}, nil
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+// fakePresenceStore 記錄 last_seen 寫入
+type fakePresenceStore struct {
+	writes map[string]int
+	err    error
+}
+
+func (f *fakePresenceStore) touch(_ context.Context, userID string, _ time.Time) error {
+	if f.writes == nil {
+		f.writes = make(map[string]int)
+	}
+	f.writes[userID]++
+	return f.err
+}
+
+func newTestPresenceTracker(f *fakePresenceStore, now *time.Time) *presenceTracker {
+	p := newPresenceTracker(f.touch, 30*time.Second)
+	p.now = func() time.Time { return *now }
+	return p
+}
+
+func TestPresenceTracker_ThrottlesPerUser(t *testing.T) {
+	now := time.Now()
+	f := &fakePresenceStore{}
+	p := newTestPresenceTracker(f, &now)
+
+	for i := 0; i < 5; i++ {
+		p.Touch(context.Background(), "alice")
+	}
+	p.Touch(context.Background(), "bob")
+	if f.writes["alice"] != 1 || f.writes["bob"] != 1 {
+		t.Fatalf("writes = %v, want one per user within interval", f.writes)
+	}
+
+	now = now.Add(30 * time.Second)
+	p.Touch(context.Background(), "alice")
+	if f.writes["alice"] != 2 {
+		t.Errorf("alice writes = %d, want 2 after interval", f.writes["alice"])
+	}
+}
+
+func TestPresenceTracker_RetriesAfterError(t *testing.T) {
+	now := time.Now()
+	f := &fakePresenceStore{err: errors.New("db down")}
+	p := newTestPresenceTracker(f, &now)
+
+	p.Touch(context.Background(), "alice")
+	f.err = nil
+	p.Touch(context.Background(), "alice")
+	if f.writes["alice"] != 2 {
+		t.Errorf("writes = %d, want retry after failed write", f.writes["alice"])
+	}
+}
+
+func TestPresenceTracker_SweepsIdleUsers(t *testing.T) {
+	now := time.Now()
+	f := &fakePresenceStore{}
+	p := newTestPresenceTracker(f, &now)
+
+	p.Touch(context.Background(), "alice")
+	p.Touch(context.Background(), "bob")
+
+	now = now.Add(time.Minute)
+	p.Touch(context.Background(), "carol")
+	if len(p.lastWrite) != 1 {
+		t.Errorf("tracked users = %d, want only carol after sweep", len(p.lastWrite))
+	}
+}
+
+func TestOnlineMembers(t *testing.T) {
+	now := time.Now()
+	members := []chatroom.RoomMember{
+		{UserID: "active", LastSeen: now.Add(-10 * time.Second)},
+		{UserID: "edge", LastSeen: now.Add(-2 * time.Minute)},
+		{UserID: "away", LastSeen: now.Add(-3 * time.Minute)},
+		{UserID: "never"},
+	}
+
+	got := onlineMembers(members, now, 2*time.Minute)
+	if len(got) != 2 || got[0].UserID != "active" || got[1].UserID != "edge" {
+		t.Errorf("onlineMembers() = %v, want active and edge", got)
+	}
+}
@@ -0,0 +1,340 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// readReceiptKey 批量已讀回執的鍵（每個用戶在每個聊天室一份）
+type readReceiptKey struct {
+	roomID string
+	userID string
+}
+
+// pendingReadReceipt 尚未寫入的已讀回執
+type pendingReadReceipt struct {
+	all        bool                // 是否標記全部訊息（MarkAsRead 未指定訊息 ID）
+	messageIDs map[string]struct{} // 指定的訊息 ID（all 為 true 時忽略）
+	readAt     time.Time           // 最近一次標記已讀的時間
+	count      int                 // 累積的標記次數
+}
+
+// merge 合併另一份待寫入回執（寫入失敗時放回）
+func (p *pendingReadReceipt) merge(other *pendingReadReceipt) {
+	p.all = p.all || other.all
+	for id := range other.messageIDs {
+		p.messageIDs[id] = struct{}{}
+	}
+	if other.readAt.After(p.readAt) {
+		p.readAt = other.readAt
+	}
+	p.count += other.count
+}
+
+// readReceiptFlushFunc 寫入 read_by 回執；messageIDs 為空表示 readAt 之前的全部訊息
+type readReceiptFlushFunc func(ctx context.Context, roomID, userID string, messageIDs []string, readAt time.Time) error
+
+// readReceiptBatcher 批量寫入 read_by 回執
+// 繁忙聊天室中每次 MarkAsRead 都會 UpdateMany，這裡按用戶和聊天室合併，
+// 累積到指定次數或定期寫入；訊息流結束和服務停止時強制寫入，避免丟失
+type readReceiptBatcher struct {
+	mu       sync.Mutex
+	pending  map[readReceiptKey]*pendingReadReceipt
+	flush    readReceiptFlushFunc
+	interval time.Duration
+	maxCount int
+
+	stopCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// newReadReceiptBatcher 創建已讀回執批量寫入器
+func newReadReceiptBatcher(interval time.Duration, maxCount int, flush readReceiptFlushFunc) *readReceiptBatcher {
+	if maxCount <= 0 {
+		maxCount = constants.DefaultReadReceiptFlushCount
+	}
+	if interval <= 0 {
+		interval = constants.DefaultReadReceiptFlushIntervalMs * time.Millisecond
+	}
+
+	return &readReceiptBatcher{
+		pending:  make(map[readReceiptKey]*pendingReadReceipt),
+		flush:    flush,
+		interval: interval,
+		maxCount: maxCount,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// newReadReceiptBatcherFromConfig 根據配置創建批量寫入器，未啟用時返回 nil
+func newReadReceiptBatcherFromConfig(flush readReceiptFlushFunc) *readReceiptBatcher {
+	cfg := config.Get()
+	if cfg == nil || !cfg.Limits.ReadReceipt.BatchEnabled {
+		return nil
+	}
+
+	interval := time.Duration(cfg.Limits.ReadReceipt.FlushIntervalMs) * time.Millisecond
+	return newReadReceiptBatcher(interval, cfg.Limits.ReadReceipt.FlushCount, flush)
+}
+
+// Start 啟動定期寫入
+func (b *readReceiptBatcher) Start() {
+	go b.loop()
+}
+
+// loop 定期寫入所有待寫入回執
+func (b *readReceiptBatcher) loop() {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		case <-ticker.C:
+			b.FlushAll(context.Background())
+		}
+	}
+}
+
+// Add 記錄一次已讀（messageID 為空表示全部訊息），累積到指定次數時立即寫入
+func (b *readReceiptBatcher) Add(ctx context.Context, roomID, userID, messageID string, readAt time.Time) error {
+	key := readReceiptKey{roomID: roomID, userID: userID}
+
+	b.mu.Lock()
+	p, ok := b.pending[key]
+	if !ok {
+		p = &pendingReadReceipt{messageIDs: make(map[string]struct{})}
+		b.pending[key] = p
+	}
+	if messageID == "" {
+		p.all = true
+	} else {
+		p.messageIDs[messageID] = struct{}{}
+	}
+	if readAt.After(p.readAt) {
+		p.readAt = readAt
+	}
+	p.count++
+	full := p.count >= b.maxCount
+	b.mu.Unlock()
+
+	if full {
+		return b.Flush(ctx, roomID, userID)
+	}
+	return nil
+}
+
+// Flush 立即寫入指定用戶在指定聊天室的回執
+func (b *readReceiptBatcher) Flush(ctx context.Context, roomID, userID string) error {
+	key := readReceiptKey{roomID: roomID, userID: userID}
+
+	b.mu.Lock()
+	p, ok := b.pending[key]
+	delete(b.pending, key)
+	b.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return b.write(ctx, key, p)
+}
+
+// FlushAll 寫入所有待寫入回執
+func (b *readReceiptBatcher) FlushAll(ctx context.Context) {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = make(map[readReceiptKey]*pendingReadReceipt)
+	b.mu.Unlock()
+
+	for key, p := range pending {
+		if err := b.write(ctx, key, p); err != nil {
+			logErrorWithUserAndRoom(ctx, "批量寫入已讀回執失敗", key.userID, key.roomID, err)
+		}
+	}
+}
+
+// Close 停止定期寫入並寫入剩餘回執
+func (b *readReceiptBatcher) Close(ctx context.Context) {
+	b.closeOnce.Do(func() {
+		close(b.stopCh)
+	})
+	b.FlushAll(ctx)
+}
+
+// write 寫入一份回執，失敗時放回待寫入隊列等待下次重試
+func (b *readReceiptBatcher) write(ctx context.Context, key readReceiptKey, p *pendingReadReceipt) error {
+	var messageIDs []string
+	if !p.all {
+		messageIDs = make([]string, 0, len(p.messageIDs))
+		for id := range p.messageIDs {
+			messageIDs = append(messageIDs, id)
+		}
+	}
+
+	err := b.flush(ctx, key.roomID, key.userID, messageIDs, p.readAt)
+	if err == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	if existing, ok := b.pending[key]; ok {
+		existing.merge(p)
+	} else {
+		b.pending[key] = p
+	}
+	b.mu.Unlock()
+
+	return err
+}
+
+// writeReadReceipts 寫入 read_by 回執並推進訊息狀態（批量寫入器的寫入函數）
+func (s *Server) writeReadReceipts(ctx context.Context, roomID, userID string, messageIDs []string, readAt time.Time) error {
+	if err := s.repos.Message.MarkManyAsRead(ctx, roomID, userID, messageIDs, readAt); err != nil {
+		return err
+	}
+	s.advanceMessageStatus(ctx, roomID, messageIDs)
+	return nil
+}
+
+// readPositionForMessage 單條訊息已讀時的已讀位置（該訊息的發送時間）
+// 訊息不屬於該聊天室時返回 false，不推進已讀位置
+func readPositionForMessage(msg *chatroom.Message, roomID string) (time.Time, bool) {
+	if msg == nil || msg.RoomID != roomID || msg.CreatedAt.IsZero() {
+		return time.Time{}, false
+	}
+	return msg.CreatedAt, true
+}
+
+// advanceLastReadAt 推進成員的 LastReadAt（$max，只會向前移動）
+// 未指定訊息時推進到 readAt；指定訊息時推進到該訊息的發送時間，
+// 客戶端通常標記最新看到的訊息，這樣快速路徑的未讀數才會隨之減少
+func (s *Server) advanceLastReadAt(ctx context.Context, roomID, userID, messageID string, readAt time.Time) error {
+	if messageID != "" {
+		msg, err := s.repos.Message.GetByID(ctx, messageID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if readAt, ok = readPositionForMessage(msg, roomID); !ok {
+			return nil
+		}
+	}
+	return s.repos.ChatRoom.UpdateLastReadAt(ctx, roomID, userID, readAt)
+}
+
+// markAsReadBatched 即時推進已讀位置，read_by 回執交給批量寫入器
+func (s *Server) markAsReadBatched(ctx context.Context, req *chat.MarkAsReadRequest) error {
+	if req.MessageId != "" {
+		if err := database.ValidateObjectID(req.MessageId); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	if err := s.advanceLastReadAt(ctx, req.RoomId, req.UserId, req.MessageId, now); err != nil {
+		return err
+	}
+
+	// 回執寫入失敗時已放回隊列等待重試，不影響本次請求
+	if err := s.readReceipts.Add(ctx, req.RoomId, req.UserId, req.MessageId, now); err != nil {
+		logger.Warning(ctx, "寫入已讀回執失敗，稍後重試",
+			logger.WithUserID(req.UserId),
+			logger.WithRoomID(req.RoomId),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+	}
+	return nil
+}
+
+// flushReadReceipts 訊息流結束時寫入該用戶的已讀回執
+func (s *Server) flushReadReceipts(roomID, userID string) {
+	if s.readReceipts == nil || roomID == "" || userID == "" {
+		return
+	}
+
+	ctx := context.Background()
+	if err := s.readReceipts.Flush(ctx, roomID, userID); err != nil {
+		logger.Warning(ctx, "訊息流結束時寫入已讀回執失敗",
+			logger.WithUserID(userID),
+			logger.WithRoomID(roomID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+	}
+}
+
+// MarkAllAsRead 標記用戶所有聊天室中其他人發送的訊息為已讀
+func (s *Server) MarkAllAsRead(ctx context.Context, req *chat.MarkAllAsReadRequest) (*chat.MarkAllAsReadResponse, error) {
+	if req.UserId == "" {
+		return &chat.MarkAllAsReadResponse{
+			Success: false,
+			Message: "缺少 user_id",
+		}, nil
+	}
+	if s.rejectImpersonation(ctx, req.UserId, "", "mark all as read") {
+		return &chat.MarkAllAsReadResponse{
+			Success: false,
+			Message: "只能標記自己的已讀狀態",
+		}, nil
+	}
+
+	roomIDs, err := s.repos.ChatRoom.ListUserRoomIDs(ctx, req.UserId)
+	if err != nil {
+		logErrorWithUser(ctx, "獲取用戶聊天室失敗", req.UserId, err)
+		return &chat.MarkAllAsReadResponse{
+			Success: false,
+			Message: "標記全部已讀失敗",
+		}, nil
+	}
+
+	// 只標記此刻之前的訊息，處理期間到達的新訊息保持未讀
+	readAt := time.Now()
+	if err := s.repos.ChatRoom.UpdateAllLastReadAt(ctx, req.UserId, readAt); err != nil {
+		logErrorWithUser(ctx, "更新已讀位置失敗", req.UserId, err)
+		return &chat.MarkAllAsReadResponse{
+			Success: false,
+			Message: "標記全部已讀失敗",
+		}, nil
+	}
+
+	rooms, messages, err := s.repos.Message.MarkRoomsAsRead(ctx, roomIDs, req.UserId, readAt)
+	if err != nil {
+		logErrorWithUser(ctx, "標記全部已讀失敗", req.UserId, err)
+		return &chat.MarkAllAsReadResponse{
+			Success: false,
+			Message: "標記全部已讀失敗",
+		}, nil
+	}
+
+	for _, roomID := range rooms {
+		s.advanceMessageStatus(ctx, roomID, nil)
+	}
+	s.touchPresence(ctx, req.UserId)
+
+	logger.Info(ctx, "標記全部已讀成功",
+		logger.WithUserID(req.UserId),
+		logger.WithAction("mark_all_as_read"),
+		logger.WithDetails(map[string]interface{}{
+			"rooms":    len(rooms),
+			"messages": messages,
+		}))
+
+	return &chat.MarkAllAsReadResponse{
+		Success:          true,
+		Message:          "已全部標記為已讀",
+		RoomsAffected:    int32(len(rooms)), // #nosec G115 -- 聊天室數量遠小於 int32 上限
+		MessagesAffected: messages,
+	}, nil
+}
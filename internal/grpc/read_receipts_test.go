@@ -0,0 +1,201 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/storage/database/chatroom"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// recordedFlush 一次回執寫入記錄
+type recordedFlush struct {
+	roomID     string
+	userID     string
+	messageIDs []string
+	readAt     time.Time
+}
+
+// fakeReceiptWriter 記錄所有寫入，可以模擬寫入失敗
+type fakeReceiptWriter struct {
+	mu      sync.Mutex
+	flushes []recordedFlush
+	fail    bool
+}
+
+func (w *fakeReceiptWriter) flush(_ context.Context, roomID, userID string, messageIDs []string, readAt time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.fail {
+		return errors.New("write failed")
+	}
+	ids := append([]string(nil), messageIDs...)
+	sort.Strings(ids)
+	w.flushes = append(w.flushes, recordedFlush{roomID: roomID, userID: userID, messageIDs: ids, readAt: readAt})
+	return nil
+}
+
+func (w *fakeReceiptWriter) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.flushes)
+}
+
+func TestReadReceiptBatcher_FlushOnCount(t *testing.T) {
+	writer := &fakeReceiptWriter{}
+	b := newReadReceiptBatcher(time.Hour, 3, writer.flush)
+	ctx := context.Background()
+	now := time.Now()
+
+	_ = b.Add(ctx, "room-1", "user-1", "m1", now)
+	_ = b.Add(ctx, "room-1", "user-1", "m2", now.Add(time.Second))
+	if writer.count() != 0 {
+		t.Fatalf("Expected no writes before reaching flush count, got %d", writer.count())
+	}
+
+	_ = b.Add(ctx, "room-1", "user-1", "m2", now.Add(2*time.Second))
+	if writer.count() != 1 {
+		t.Fatalf("Expected one write after reaching flush count, got %d", writer.count())
+	}
+
+	got := writer.flushes[0]
+	if len(got.messageIDs) != 2 || got.messageIDs[0] != "m1" || got.messageIDs[1] != "m2" {
+		t.Errorf("Expected deduplicated message IDs [m1 m2], got %v", got.messageIDs)
+	}
+	if !got.readAt.Equal(now.Add(2 * time.Second)) {
+		t.Errorf("Expected latest read time, got %v", got.readAt)
+	}
+}
+
+func TestReadReceiptBatcher_DebouncedInterval(t *testing.T) {
+	writer := &fakeReceiptWriter{}
+	b := newReadReceiptBatcher(20*time.Millisecond, 100, writer.flush)
+	b.Start()
+	defer b.Close(context.Background())
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		_ = b.Add(ctx, "room-1", "user-1", "", time.Now())
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for writer.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if writer.count() != 1 {
+		t.Fatalf("Expected repeated reads to be merged into one write, got %d", writer.count())
+	}
+	if writer.flushes[0].messageIDs != nil {
+		t.Errorf("Expected mark-all write without message IDs, got %v", writer.flushes[0].messageIDs)
+	}
+}
+
+func TestReadReceiptBatcher_FlushOnClose(t *testing.T) {
+	writer := &fakeReceiptWriter{}
+	b := newReadReceiptBatcher(time.Hour, 100, writer.flush)
+	ctx := context.Background()
+
+	_ = b.Add(ctx, "room-1", "user-1", "m1", time.Now())
+	_ = b.Add(ctx, "room-1", "user-2", "m1", time.Now())
+	_ = b.Add(ctx, "room-2", "user-1", "", time.Now())
+
+	// 訊息流結束時只寫入該用戶在該聊天室的回執
+	if err := b.Flush(ctx, "room-1", "user-1"); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if writer.count() != 1 || writer.flushes[0].userID != "user-1" || writer.flushes[0].roomID != "room-1" {
+		t.Fatalf("Expected only room-1/user-1 to be flushed, got %+v", writer.flushes)
+	}
+
+	// 服務停止時寫入所有剩餘回執
+	b.Close(ctx)
+	if writer.count() != 3 {
+		t.Errorf("Expected all pending receipts flushed on close, got %d", writer.count())
+	}
+
+	// 重複關閉是安全的
+	b.Close(ctx)
+}
+
+func TestReadReceiptBatcher_RetryAfterFailure(t *testing.T) {
+	writer := &fakeReceiptWriter{fail: true}
+	b := newReadReceiptBatcher(time.Hour, 100, writer.flush)
+	ctx := context.Background()
+
+	_ = b.Add(ctx, "room-1", "user-1", "m1", time.Now())
+	if err := b.Flush(ctx, "room-1", "user-1"); err == nil {
+		t.Fatal("Expected flush error")
+	}
+
+	// 失敗的回執保留，與之後的已讀合併
+	_ = b.Add(ctx, "room-1", "user-1", "m2", time.Now())
+
+	writer.mu.Lock()
+	writer.fail = false
+	writer.mu.Unlock()
+
+	if err := b.Flush(ctx, "room-1", "user-1"); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if writer.count() != 1 || len(writer.flushes[0].messageIDs) != 2 {
+		t.Errorf("Expected failed receipts to be retried with new ones, got %+v", writer.flushes)
+	}
+}
+
+func TestUnreadCountMode_DefaultsToFast(t *testing.T) {
+	if mode := unreadCountMode(); mode != constants.UnreadCountFast {
+		t.Errorf("unreadCountMode() = %q, want %q", mode, constants.UnreadCountFast)
+	}
+}
+
+func TestReadPositionForMessage_SingleMarkAdvancesUnreadCount(t *testing.T) {
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	msgs := []*chatroom.Message{
+		{ID: "m1", RoomID: "room-1", SenderID: "bob", CreatedAt: base},
+		{ID: "m2", RoomID: "room-1", SenderID: "bob", CreatedAt: base.Add(time.Minute)},
+		{ID: "m3", RoomID: "room-1", SenderID: "bob", CreatedAt: base.Add(2 * time.Minute)},
+	}
+
+	// 標記 m2 已讀後，GetUnreadCount 快速路徑只應統計 m2 之後的訊息
+	readAt, ok := readPositionForMessage(msgs[1], "room-1")
+	if !ok {
+		t.Fatal("Expected marking a room message to advance the read position")
+	}
+	if !readAt.Equal(msgs[1].CreatedAt) {
+		t.Fatalf("Expected read position %v, got %v", msgs[1].CreatedAt, readAt)
+	}
+
+	filter := chatroom.UnreadSinceFilter("room-1", "alice", readAt, base.Add(time.Hour))
+	createdAt, ok := filter["created_at"].(bson.M)
+	if !ok {
+		t.Fatalf("Expected created_at range in unread filter, got %v", filter["created_at"])
+	}
+	since, _ := createdAt["$gt"].(time.Time)
+	unread := 0
+	for _, m := range msgs {
+		if m.CreatedAt.After(since) {
+			unread++
+		}
+	}
+	if unread != 1 {
+		t.Errorf("Expected 1 unread message after marking m2, got %d", unread)
+	}
+}
+
+func TestReadPositionForMessage_RejectsOtherRoom(t *testing.T) {
+	msg := &chatroom.Message{ID: "m1", RoomID: "room-2", CreatedAt: time.Now()}
+	if _, ok := readPositionForMessage(msg, "room-1"); ok {
+		t.Error("Expected a message from another room not to advance the read position")
+	}
+	if _, ok := readPositionForMessage(nil, "room-1"); ok {
+		t.Error("Expected a missing message not to advance the read position")
+	}
+}
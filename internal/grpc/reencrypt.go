@@ -0,0 +1,126 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+// reencryptBatchSize 每批重新加密的訊息數量
+const reencryptBatchSize = 100
+
+// scheduleReencryption 密鑰輪換後在背景重新加密聊天室的歷史訊息
+// 同一聊天室同時只會有一個重新加密任務
+func (s *Server) scheduleReencryption(roomID string) {
+	if _, running := s.reencrypting.LoadOrStore(roomID, struct{}{}); running {
+		return
+	}
+
+	go func() {
+		defer s.reencrypting.Delete(roomID)
+
+		ctx := context.Background()
+		count, err := s.reencryptRoomMessages(ctx, roomID)
+		if err != nil {
+			logErrorWithRoom(ctx, "重新加密歷史訊息失敗", roomID, err)
+			return
+		}
+		if err := s.reencryptRoomPreview(ctx, roomID); err != nil {
+			logErrorWithRoom(ctx, "重新加密最後訊息預覽失敗", roomID, err)
+		}
+
+		logger.Info(ctx, "重新加密歷史訊息完成",
+			logger.WithRoomID(roomID),
+			logger.WithAction("reencrypt_messages"),
+			logger.WithDetails(map[string]interface{}{"count": count}))
+	}()
+}
+
+// reencryptRoomMessages 將聊天室中使用舊密鑰加密的訊息改用當前密鑰加密
+// 無法解密的訊息會被跳過（保留原內容），返回成功重新加密的數量
+func (s *Server) reencryptRoomMessages(ctx context.Context, roomID string) (int, error) {
+	currentVersion, err := s.encryption.CurrentKeyVersion(roomID)
+	if err != nil {
+		return 0, err
+	}
+	if currentVersion <= 0 {
+		return 0, nil
+	}
+	currentKeyID := strconv.Itoa(currentVersion)
+
+	count := 0
+	afterID := ""
+	for {
+		messages, err := s.repos.Message.GetForReencryption(ctx, roomID, currentKeyID, afterID, reencryptBatchSize)
+		if err != nil {
+			return count, err
+		}
+		if len(messages) == 0 {
+			return count, nil
+		}
+		afterID = messages[len(messages)-1].GetID()
+
+		for _, msg := range messages {
+			plaintext, err := s.encryption.DecryptMessageWithVersion(msg.Content, roomID, msg.KeyVersion())
+			if err != nil {
+				logger.Sampled(ctx, logger.SeverityWarning, "重新加密時解密失敗，跳過", roomID,
+					logger.WithMessageID(msg.GetID()),
+					logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+				continue
+			}
+
+			encrypted, version, err := s.encryption.EncryptMessageWithVersion(plaintext, roomID)
+			if err != nil {
+				return count, err
+			}
+
+			msg.SetKeyVersion(version)
+			if err := s.repos.Message.Update(ctx, msg.GetID(), map[string]interface{}{
+				"content":           encrypted,
+				"encryption_key_id": msg.EncryptionKeyID,
+			}); err != nil {
+				logger.Warning(ctx, "重新加密訊息寫入失敗",
+					logger.WithMessageID(msg.GetID()),
+					logger.WithRoomID(roomID),
+					logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+				continue
+			}
+			count++
+		}
+	}
+}
+
+// reencryptRoomPreview 將聊天室列表的最後訊息預覽改用當前密鑰加密
+// 預覽已使用當前密鑰、未加密或無法解密時不處理；期間收到新訊息時放棄（新預覽已使用當前密鑰）
+func (s *Server) reencryptRoomPreview(ctx context.Context, roomID string) error {
+	currentVersion, err := s.encryption.CurrentKeyVersion(roomID)
+	if err != nil || currentVersion <= 0 {
+		return err
+	}
+
+	room, err := s.repos.ChatRoom.GetByID(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	currentKeyID := chatroom.EncryptionKeyID(currentVersion)
+	if room.LastMessage == "" || room.LastMessageKeyID == currentKeyID || !s.encryption.IsEncrypted(room.LastMessage) {
+		return nil
+	}
+
+	plaintext, err := s.encryption.DecryptMessageWithVersion(room.LastMessage, roomID, room.LastMessageKeyVersion())
+	if err != nil {
+		logger.Warning(ctx, "重新加密時解密最後訊息預覽失敗，跳過",
+			logger.WithRoomID(roomID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return nil
+	}
+	encrypted, version, err := s.encryption.EncryptMessageWithVersion(plaintext, roomID)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repos.ChatRoom.ReplaceLastMessage(ctx, roomID, room.LastMessage, encrypted, chatroom.EncryptionKeyID(version))
+	return err
+}
@@ -0,0 +1,198 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database"
+)
+
+// retentionPolicy 訊息保留期限（0 表示永久保留）
+type retentionPolicy struct {
+	defaultAge  time.Duration            // 全局保留期限
+	roomTypeAge map[string]time.Duration // 按聊天室類型覆蓋的保留期限
+}
+
+// newRetentionPolicy 根據配置的天數創建保留期限
+func newRetentionPolicy(cfg config.RetentionLimitsConfig) retentionPolicy {
+	policy := retentionPolicy{defaultAge: retentionDays(cfg.Days)}
+	if len(cfg.RoomTypeDays) > 0 {
+		policy.roomTypeAge = make(map[string]time.Duration, len(cfg.RoomTypeDays))
+		for roomType, n := range cfg.RoomTypeDays {
+			policy.roomTypeAge[roomType] = retentionDays(n)
+		}
+	}
+	return policy
+}
+
+// retentionDays 天數轉為時長
+func retentionDays(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// empty 是否所有聊天室都永久保留
+func (p retentionPolicy) empty() bool {
+	if p.defaultAge > 0 {
+		return false
+	}
+	for _, age := range p.roomTypeAge {
+		if age > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// groupRooms 按保留期限分組聊天室（永久保留的聊天室不在結果中）
+func (p retentionPolicy) groupRooms(roomTypes map[string]string) map[time.Duration][]string {
+	groups := make(map[time.Duration][]string)
+	for roomID, roomType := range roomTypes {
+		age, ok := p.roomTypeAge[roomType]
+		if !ok {
+			age = p.defaultAge
+		}
+		if age > 0 {
+			groups[age] = append(groups[age], roomID)
+		}
+	}
+	return groups
+}
+
+// retentionJob 定期刪除超過保留期限的訊息
+// 只有全局期限時直接按時間刪除；有按類型覆蓋時先查出聊天室類型，再按期限分組刪除
+type retentionJob struct {
+	policy    retentionPolicy
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+
+	listRoomTypes func(ctx context.Context) (map[string]string, error)
+	deleteExpired func(ctx context.Context, roomIDs []string, before time.Time, batchSize int) (int64, error)
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newRetentionJobFromConfig 根據配置創建清理任務，未啟用或沒有任何保留期限時返回 nil
+func newRetentionJobFromConfig(repos *database.Repositories) *retentionJob {
+	cfg := config.Get()
+	if cfg == nil || !cfg.Limits.Retention.Enabled {
+		return nil
+	}
+
+	retention := cfg.Limits.Retention
+	policy := newRetentionPolicy(retention)
+	if policy.empty() {
+		return nil
+	}
+
+	interval := time.Duration(constants.DefaultRetentionIntervalMinutes) * time.Minute
+	if retention.IntervalMinutes > 0 {
+		interval = time.Duration(retention.IntervalMinutes) * time.Minute
+	}
+	batchSize := constants.DefaultRetentionBatchSize
+	if retention.BatchSize > 0 {
+		batchSize = retention.BatchSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &retentionJob{
+		policy:        policy,
+		interval:      interval,
+		batchSize:     batchSize,
+		now:           time.Now,
+		listRoomTypes: repos.ChatRoom.ListRoomTypes,
+		deleteExpired: repos.Message.DeleteExpired,
+		ctx:           ctx,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+	}
+}
+
+// Start 立即清理一次，之後定期清理
+func (j *retentionJob) Start() {
+	go func() {
+		defer close(j.done)
+
+		j.run()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				j.run()
+			}
+		}
+	}()
+}
+
+// Stop 停止定期清理，並中斷進行中的刪除（已刪除的批次不受影響）
+func (j *retentionJob) Stop() {
+	j.stopOnce.Do(func() {
+		j.cancel()
+		<-j.done
+	})
+}
+
+// run 執行一次清理並記錄刪除數量
+func (j *retentionJob) run() {
+	start := time.Now()
+	deleted, err := j.purge(j.ctx)
+	if err != nil && j.ctx.Err() == nil {
+		logger.Error(j.ctx, "清理過期訊息失敗",
+			logger.WithAction("message_retention"),
+			logger.WithDetails(map[string]interface{}{
+				"deleted": deleted,
+				"error":   err.Error(),
+			}))
+		return
+	}
+
+	logger.Info(j.ctx, "清理過期訊息完成",
+		logger.WithAction("message_retention"),
+		logger.WithDetails(map[string]interface{}{
+			"deleted":     deleted,
+			"duration_ms": time.Since(start).Milliseconds(),
+		}))
+}
+
+// purge 刪除所有超過保留期限的訊息，返回刪除數量
+func (j *retentionJob) purge(ctx context.Context) (int64, error) {
+	now := j.now()
+	if len(j.policy.roomTypeAge) == 0 {
+		return j.deleteExpired(ctx, nil, now.Add(-j.policy.defaultAge), j.batchSize)
+	}
+
+	roomTypes, err := j.listRoomTypes(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	groups := j.policy.groupRooms(roomTypes)
+	ages := make([]time.Duration, 0, len(groups))
+	for age := range groups {
+		ages = append(ages, age)
+	}
+	slices.Sort(ages)
+
+	var deleted int64
+	for _, age := range ages {
+		n, err := j.deleteExpired(ctx, groups[age], now.Add(-age), j.batchSize)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,105 @@
+package grpc
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/platform/config"
+)
+
+func TestRetentionPolicy_GroupRooms(t *testing.T) {
+	policy := newRetentionPolicy(config.RetentionLimitsConfig{
+		Days:         365,
+		RoomTypeDays: map[string]int{"direct": 30, "archive": 0},
+	})
+
+	groups := policy.groupRooms(map[string]string{
+		"room-1": "direct",
+		"room-2": "group",
+		"room-3": "archive",
+		"room-4": "direct",
+	})
+	for _, roomIDs := range groups {
+		slices.Sort(roomIDs)
+	}
+
+	want := map[time.Duration][]string{
+		30 * 24 * time.Hour:  {"room-1", "room-4"},
+		365 * 24 * time.Hour: {"room-2"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("groupRooms() = %v, want %v", groups, want)
+	}
+}
+
+func TestRetentionPolicy_Empty(t *testing.T) {
+	tests := []struct {
+		cfg  config.RetentionLimitsConfig
+		want bool
+	}{
+		{config.RetentionLimitsConfig{}, true},
+		{config.RetentionLimitsConfig{RoomTypeDays: map[string]int{"group": 0}}, true},
+		{config.RetentionLimitsConfig{Days: 30}, false},
+		{config.RetentionLimitsConfig{RoomTypeDays: map[string]int{"direct": 7}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := newRetentionPolicy(tt.cfg).empty(); got != tt.want {
+			t.Errorf("empty() for %+v = %v, want %v", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestRetentionJob_Purge(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	type call struct {
+		roomIDs []string
+		before  time.Time
+	}
+	newJob := func(cfg config.RetentionLimitsConfig, calls *[]call) *retentionJob {
+		return &retentionJob{
+			policy:    newRetentionPolicy(cfg),
+			batchSize: 100,
+			now:       func() time.Time { return now },
+			listRoomTypes: func(context.Context) (map[string]string, error) {
+				return map[string]string{"room-1": "direct", "room-2": "group"}, nil
+			},
+			deleteExpired: func(_ context.Context, roomIDs []string, before time.Time, batchSize int) (int64, error) {
+				if batchSize != 100 {
+					t.Errorf("batchSize = %d, want 100", batchSize)
+				}
+				*calls = append(*calls, call{roomIDs, before})
+				return int64(len(roomIDs) + 1), nil
+			},
+		}
+	}
+
+	// 只有全局期限時不需要聊天室列表
+	var calls []call
+	deleted, err := newJob(config.RetentionLimitsConfig{Days: 10}, &calls).purge(context.Background())
+	if err != nil {
+		t.Fatalf("purge() error = %v", err)
+	}
+	want := []call{{nil, now.Add(-10 * 24 * time.Hour)}}
+	if deleted != 1 || !reflect.DeepEqual(calls, want) {
+		t.Errorf("global purge = %d, %v, want 1, %v", deleted, calls, want)
+	}
+
+	// 按類型覆蓋時按期限分組，期限短的先刪除
+	calls = nil
+	deleted, err = newJob(config.RetentionLimitsConfig{Days: 10, RoomTypeDays: map[string]int{"direct": 1}}, &calls).purge(context.Background())
+	if err != nil {
+		t.Fatalf("purge() error = %v", err)
+	}
+	want = []call{
+		{[]string{"room-1"}, now.Add(-24 * time.Hour)},
+		{[]string{"room-2"}, now.Add(-10 * 24 * time.Hour)},
+	}
+	if deleted != 4 || !reflect.DeepEqual(calls, want) {
+		t.Errorf("per room type purge = %d, %v, want 4, %v", deleted, calls, want)
+	}
+}
@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/proto/chat"
+)
+
+// ArchiveRoom 封存聊天室（只影響請求的成員，聊天室和訊息不會被刪除）
+// 封存的聊天室不出現在默認的聊天室列表中，收到新訊息時自動取消封存；封存時同時取消置頂
+func (s *Server) ArchiveRoom(ctx context.Context, req *chat.ArchiveRoomRequest) (*chat.ArchiveRoomResponse, error) {
+	if ok, message := s.checkMemberPreference(ctx, req.RoomId, req.UserId); !ok {
+		return &chat.ArchiveRoomResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
+	if err := s.repos.ChatRoom.SetMemberArchived(ctx, req.RoomId, req.UserId, true); err != nil {
+		logErrorWithUserAndRoom(ctx, "封存聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.ArchiveRoomResponse{
+			Success: false,
+			Message: "封存聊天室失敗: " + err.Error(),
+		}, nil
+	}
+
+	logger.Info(ctx, "封存聊天室成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("archive_room"))
+
+	return &chat.ArchiveRoomResponse{
+		Success: true,
+		Message: "聊天室已封存",
+	}, nil
+}
+
+// UnarchiveRoom 取消封存聊天室
+func (s *Server) UnarchiveRoom(ctx context.Context, req *chat.UnarchiveRoomRequest) (*chat.UnarchiveRoomResponse, error) {
+	if ok, message := s.checkMemberPreference(ctx, req.RoomId, req.UserId); !ok {
+		return &chat.UnarchiveRoomResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
+	if err := s.repos.ChatRoom.SetMemberArchived(ctx, req.RoomId, req.UserId, false); err != nil {
+		logErrorWithUserAndRoom(ctx, "取消封存聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.UnarchiveRoomResponse{
+			Success: false,
+			Message: "取消封存聊天室失敗: " + err.Error(),
+		}, nil
+	}
+
+	logger.Info(ctx, "取消封存聊天室成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("unarchive_room"))
+
+	return &chat.UnarchiveRoomResponse{
+		Success: true,
+		Message: "已取消封存",
+	}, nil
+}
@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+// roomHubSubscriberBuffer 每個訂閱者可緩衝的訊息批次數量
+// 超出時視為消費過慢，關閉該訂閱讓客戶端重新連接，避免拖慢同一聊天室的其他訂閱者
+const roomHubSubscriberBuffer = 16
+
+// roomMessageFetcher 獲取聊天室最新訊息
+type roomMessageFetcher func(ctx context.Context, roomID string, limit int) ([]*chatroom.Message, error)
+
+// roomHub 聊天室訊息分發中心
+// 每個聊天室只有一個輪詢 goroutine，新訊息分發給所有訂閱者，
+// 避免每個訊息流各自輪詢資料庫；最後一個訂閱者離開時停止輪詢
+type roomHub struct {
+	mu    sync.Mutex
+	rooms map[string]*roomWatcher
+
+	fetch        roomMessageFetcher
+	interval     time.Duration
+	initialFetch int
+	seenSetSize  int
+}
+
+// roomWatcher 單個聊天室的輪詢狀態
+type roomWatcher struct {
+	roomID      string
+	cancel      context.CancelFunc
+	subscribers map[chan []*chatroom.Message]struct{}
+}
+
+// newRoomHub 創建聊天室訊息分發中心
+func newRoomHub(fetch roomMessageFetcher, interval time.Duration, initialFetch, seenSetSize int) *roomHub {
+	return &roomHub{
+		rooms:        make(map[string]*roomWatcher),
+		fetch:        fetch,
+		interval:     interval,
+		initialFetch: initialFetch,
+		seenSetSize:  seenSetSize,
+	}
+}
+
+// Subscribe 訂閱聊天室新訊息（按時間升序的批次）
+// 通道被關閉表示訂閱者消費過慢被移除；結束時必須調用 unsubscribe
+func (h *roomHub) Subscribe(roomID string) (messages <-chan []*chatroom.Message, unsubscribe func()) {
+	ch := make(chan []*chatroom.Message, roomHubSubscriberBuffer)
+
+	h.mu.Lock()
+	w, ok := h.rooms[roomID]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		w = &roomWatcher{
+			roomID:      roomID,
+			cancel:      cancel,
+			subscribers: make(map[chan []*chatroom.Message]struct{}),
+		}
+		h.rooms[roomID] = w
+		go h.watch(ctx, w)
+	}
+	w.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if _, ok := w.subscribers[ch]; ok {
+				delete(w.subscribers, ch)
+				h.releaseUnsafe(w)
+			}
+		})
+	}
+}
+
+// Close 停止所有聊天室的輪詢並關閉所有訂閱
+func (h *roomHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for roomID, w := range h.rooms {
+		w.cancel()
+		for ch := range w.subscribers {
+			delete(w.subscribers, ch)
+			close(ch)
+		}
+		delete(h.rooms, roomID)
+	}
+}
+
+// watch 輪詢聊天室新訊息並分發
+func (h *roomHub) watch(ctx context.Context, w *roomWatcher) {
+	// 訂閱之前的訊息不推送
+	seen := newSeenMessageSet(h.seenSetSize)
+	if existing, err := h.fetch(ctx, w.roomID, h.initialFetch); err == nil {
+		collectNewMessages(existing, seen)
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			messages, err := h.fetch(ctx, w.roomID, constants.StreamFetchLimit)
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Error(ctx, "獲取新訊息失敗",
+						logger.WithRoomID(w.roomID),
+						logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+				}
+				continue // 不中斷輪詢，繼續重試
+			}
+
+			if newMessages := collectNewMessages(messages, seen); len(newMessages) > 0 {
+				h.broadcast(w, newMessages)
+			}
+		}
+	}
+}
+
+// broadcast 分發訊息批次，消費過慢的訂閱者會被移除
+func (h *roomHub) broadcast(w *roomWatcher, messages []*chatroom.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range w.subscribers {
+		select {
+		case ch <- messages:
+		default:
+			logger.Warning(context.Background(), "訊息流訂閱者消費過慢，關閉訂閱",
+				logger.WithRoomID(w.roomID))
+			delete(w.subscribers, ch)
+			close(ch)
+		}
+	}
+	h.releaseUnsafe(w)
+}
+
+// releaseUnsafe 沒有訂閱者時停止輪詢（調用者需持有鎖）
+func (h *roomHub) releaseUnsafe(w *roomWatcher) {
+	if len(w.subscribers) > 0 {
+		return
+	}
+	w.cancel()
+	if h.rooms[w.roomID] == w {
+		delete(h.rooms, w.roomID)
+	}
+}
+
+// watcherCount 正在輪詢的聊天室數量
+func (h *roomHub) watcherCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.rooms)
+}
@@ -0,0 +1,168 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+// fakeRoomFeed 可追加訊息的聊天室，記錄每個聊天室的查詢次數
+type fakeRoomFeed struct {
+	mu       sync.Mutex
+	messages map[string][]*chatroom.Message
+	fetches  map[string]int
+}
+
+func newFakeRoomFeed() *fakeRoomFeed {
+	return &fakeRoomFeed{
+		messages: make(map[string][]*chatroom.Message),
+		fetches:  make(map[string]int),
+	}
+}
+
+func (f *fakeRoomFeed) fetch(_ context.Context, roomID string, _ int) ([]*chatroom.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fetches[roomID]++
+	return append([]*chatroom.Message(nil), f.messages[roomID]...), nil
+}
+
+func (f *fakeRoomFeed) post(roomID, id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages[roomID] = append(f.messages[roomID], newTestMessage(id, time.Now()))
+}
+
+func (f *fakeRoomFeed) fetchCount(roomID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fetches[roomID]
+}
+
+// receiveBatch 等待一個訊息批次
+func receiveBatch(t *testing.T, ch <-chan []*chatroom.Message) []*chatroom.Message {
+	t.Helper()
+	select {
+	case batch, ok := <-ch:
+		if !ok {
+			t.Fatal("subscription closed unexpectedly")
+		}
+		return batch
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for messages")
+		return nil
+	}
+}
+
+func TestRoomHub_FanOutWithSinglePoller(t *testing.T) {
+	feed := newFakeRoomFeed()
+	feed.post("room-1", "old")
+	hub := newRoomHub(feed.fetch, 5*time.Millisecond, 100, 100)
+	defer hub.Close()
+
+	subs := make([]<-chan []*chatroom.Message, 3)
+	for i := range subs {
+		ch, unsubscribe := hub.Subscribe("room-1")
+		defer unsubscribe()
+		subs[i] = ch
+	}
+
+	if hub.watcherCount() != 1 {
+		t.Fatalf("Expected one watcher for the room, got %d", hub.watcherCount())
+	}
+
+	// 等待初始抓取完成後再發訊息，訂閱前的訊息不推送
+	time.Sleep(20 * time.Millisecond)
+	feed.post("room-1", "new")
+
+	for i, ch := range subs {
+		batch := receiveBatch(t, ch)
+		if len(batch) != 1 || batch[0].ID != "new" {
+			t.Errorf("subscriber %d: expected only the new message, got %d messages", i, len(batch))
+		}
+	}
+
+	// 查詢次數與訂閱者數量無關
+	time.Sleep(20 * time.Millisecond)
+	perTick := feed.fetchCount("room-1")
+	time.Sleep(50 * time.Millisecond)
+	if polls := feed.fetchCount("room-1") - perTick; polls > 15 {
+		t.Errorf("Expected a single poller per room, got %d fetches in 50ms", polls)
+	}
+}
+
+func TestRoomHub_StopsWhenLastSubscriberLeaves(t *testing.T) {
+	feed := newFakeRoomFeed()
+	hub := newRoomHub(feed.fetch, 5*time.Millisecond, 100, 100)
+	defer hub.Close()
+
+	_, unsubscribe1 := hub.Subscribe("room-1")
+	_, unsubscribe2 := hub.Subscribe("room-1")
+
+	unsubscribe1()
+	if hub.watcherCount() != 1 {
+		t.Fatalf("Expected watcher to keep running with a remaining subscriber")
+	}
+
+	unsubscribe2()
+	unsubscribe2() // 重複取消是安全的
+	if hub.watcherCount() != 0 {
+		t.Fatalf("Expected watcher to stop after the last subscriber left, got %d", hub.watcherCount())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	before := feed.fetchCount("room-1")
+	time.Sleep(30 * time.Millisecond)
+	if after := feed.fetchCount("room-1"); after != before {
+		t.Errorf("Expected polling to stop, got %d more fetches", after-before)
+	}
+}
+
+func TestRoomHub_DropsSlowSubscriber(t *testing.T) {
+	hub := newRoomHub(newFakeRoomFeed().fetch, time.Hour, 100, 100)
+	defer hub.Close()
+
+	slow, unsubscribeSlow := hub.Subscribe("room-1")
+	defer unsubscribeSlow()
+	fast, unsubscribeFast := hub.Subscribe("room-1")
+	defer unsubscribeFast()
+
+	hub.mu.Lock()
+	w := hub.rooms["room-1"]
+	hub.mu.Unlock()
+
+	batch := []*chatroom.Message{newTestMessage("m1", time.Now())}
+	for i := 0; i <= roomHubSubscriberBuffer; i++ {
+		hub.broadcast(w, batch)
+		<-fast
+	}
+
+	// 慢訂閱者的緩衝區已滿，訂閱被關閉
+	for i := 0; i < roomHubSubscriberBuffer; i++ {
+		<-slow
+	}
+	if _, ok := <-slow; ok {
+		t.Fatal("Expected slow subscriber to be closed")
+	}
+	if hub.watcherCount() != 1 {
+		t.Errorf("Expected watcher to keep serving the remaining subscriber")
+	}
+}
+
+func TestRoomHub_CloseEndsSubscriptions(t *testing.T) {
+	hub := newRoomHub(newFakeRoomFeed().fetch, time.Hour, 100, 100)
+
+	ch, unsubscribe := hub.Subscribe("room-1")
+	hub.Close()
+
+	if _, ok := <-ch; ok {
+		t.Error("Expected subscription to be closed")
+	}
+	unsubscribe() // 關閉後取消訂閱是安全的
+	if hub.watcherCount() != 0 {
+		t.Errorf("Expected no watchers after close, got %d", hub.watcherCount())
+	}
+}
@@ -0,0 +1,117 @@
+package grpc
+
+import (
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+)
+
+// ownerCreations 單個擁有者的創建記錄
+type ownerCreations struct {
+	inFlight int         // 進行中的創建請求
+	recent   []time.Time // 時間窗口內的創建時間
+}
+
+// roomCreationLimiter 每個擁有者的 CreateRoom 並發與短時間頻率限制
+// 防止被盜用的帳號在短時間內大量創建聊天室
+type roomCreationLimiter struct {
+	mu            sync.Mutex
+	owners        map[string]*ownerCreations
+	maxConcurrent int
+	burstLimit    int
+	window        time.Duration
+	lastSweep     time.Time
+	now           func() time.Time
+}
+
+// newRoomCreationLimiter 創建聊天室創建限制器
+func newRoomCreationLimiter(maxConcurrent, burstLimit int, window time.Duration) *roomCreationLimiter {
+	return &roomCreationLimiter{
+		owners:        make(map[string]*ownerCreations),
+		maxConcurrent: maxConcurrent,
+		burstLimit:    burstLimit,
+		window:        window,
+		now:           time.Now,
+	}
+}
+
+// newRoomCreationLimiterFromConfig 根據配置創建限制器
+func newRoomCreationLimiterFromConfig() *roomCreationLimiter {
+	maxConcurrent := constants.DefaultMaxConcurrentRoomCreates
+	burstLimit := constants.DefaultRoomCreateBurstLimit
+	windowSeconds := constants.DefaultRoomCreateBurstWindowSeconds
+
+	if cfg := config.Get(); cfg != nil {
+		if cfg.Limits.Room.MaxConcurrentCreates > 0 {
+			maxConcurrent = cfg.Limits.Room.MaxConcurrentCreates
+		}
+		if cfg.Limits.Room.CreateBurstLimit > 0 {
+			burstLimit = cfg.Limits.Room.CreateBurstLimit
+		}
+		if cfg.Limits.Room.CreateBurstWindowSeconds > 0 {
+			windowSeconds = cfg.Limits.Room.CreateBurstWindowSeconds
+		}
+	}
+
+	return newRoomCreationLimiter(maxConcurrent, burstLimit, time.Duration(windowSeconds)*time.Second)
+}
+
+// Acquire 嘗試開始一次創建，超出限制時返回 false
+// 成功時必須調用返回的 release
+func (l *roomCreationLimiter) Acquire(ownerID string) (release func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweepUnsafe(now)
+
+	oc, exists := l.owners[ownerID]
+	if !exists {
+		oc = &ownerCreations{}
+		l.owners[ownerID] = oc
+	}
+	oc.recent = pruneBefore(oc.recent, now.Add(-l.window))
+
+	if oc.inFlight >= l.maxConcurrent || len(oc.recent) >= l.burstLimit {
+		return nil, false
+	}
+
+	oc.inFlight++
+	oc.recent = append(oc.recent, now)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			oc.inFlight--
+		})
+	}, true
+}
+
+// sweepUnsafe 定期清理沒有進行中請求且窗口已過期的擁有者（調用者需持有鎖）
+func (l *roomCreationLimiter) sweepUnsafe(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+
+	cutoff := now.Add(-l.window)
+	for ownerID, oc := range l.owners {
+		oc.recent = pruneBefore(oc.recent, cutoff)
+		if oc.inFlight == 0 && len(oc.recent) == 0 {
+			delete(l.owners, ownerID)
+		}
+	}
+}
+
+// pruneBefore 移除早於 cutoff 的時間（times 按時間升序）
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && !times[i].After(cutoff) {
+		i++
+	}
+	return times[i:]
+}
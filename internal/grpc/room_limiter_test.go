@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/proto/chat"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRoomCreationLimiter_BurstLimit(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := newRoomCreationLimiter(100, 3, 10*time.Second)
+	l.now = func() time.Time { return now }
+
+	// 快速創建：前 3 次允許，第 4 次被限制
+	for i := 0; i < 3; i++ {
+		release, ok := l.Acquire("owner-1")
+		if !ok {
+			t.Fatalf("Create %d should be allowed", i+1)
+		}
+		release()
+	}
+	if _, ok := l.Acquire("owner-1"); ok {
+		t.Fatal("Expected burst to be throttled")
+	}
+
+	// 其他擁有者不受影響
+	if _, ok := l.Acquire("owner-2"); !ok {
+		t.Error("Other owners should not be throttled")
+	}
+
+	// 窗口過後恢復
+	now = now.Add(11 * time.Second)
+	if _, ok := l.Acquire("owner-1"); !ok {
+		t.Error("Expected creation to be allowed after the window")
+	}
+}
+
+func TestRoomCreationLimiter_MaxConcurrent(t *testing.T) {
+	l := newRoomCreationLimiter(2, 100, time.Minute)
+
+	release1, ok1 := l.Acquire("owner-1")
+	_, ok2 := l.Acquire("owner-1")
+	if !ok1 || !ok2 {
+		t.Fatal("First two concurrent creates should be allowed")
+	}
+	if _, ok := l.Acquire("owner-1"); ok {
+		t.Fatal("Third concurrent create should be throttled")
+	}
+
+	// 釋放後可以再次創建，重複釋放不會多減
+	release1()
+	release1()
+	if _, ok := l.Acquire("owner-1"); !ok {
+		t.Error("Expected create to be allowed after release")
+	}
+	if _, ok := l.Acquire("owner-1"); ok {
+		t.Error("Double release should not free an extra slot")
+	}
+}
+
+func TestCreateRoom_ResourceExhausted(t *testing.T) {
+	s := &Server{
+		audit:         audit.NewAuditService(false),
+		roomCreations: newRoomCreationLimiter(1, 1, time.Minute),
+	}
+
+	// 佔用唯一的額度
+	if _, ok := s.roomCreations.Acquire("owner-1"); !ok {
+		t.Fatal("Expected first acquire to succeed")
+	}
+
+	_, err := s.CreateRoom(context.Background(), &chat.CreateRoomRequest{OwnerId: "owner-1", Name: "room"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected ResourceExhausted, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"context"
+
+	"chat-gateway/internal/storage/database"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// ListRoomMembers 分頁列出聊天室成員（只有成員可以查詢）
+// 成員在數據庫中分頁和過濾，大型群組不需要一次返回全部成員
+func (s *Server) ListRoomMembers(ctx context.Context, req *chat.ListRoomMembersRequest) (*chat.ListRoomMembersResponse, error) {
+	if req.UserId == "" || req.RoomId == "" {
+		return &chat.ListRoomMembersResponse{
+			Success: false,
+			Message: "缺少 room_id 或 user_id",
+		}, nil
+	}
+	if s.rejectImpersonation(ctx, req.UserId, req.RoomId, "list room members") {
+		return &chat.ListRoomMembersResponse{
+			Success: false,
+			Message: "您不是此聊天室的成員",
+		}, nil
+	}
+
+	isMember, err := s.repos.ChatRoom.IsMember(ctx, req.RoomId, req.UserId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "檢查成員失敗", req.UserId, req.RoomId, err)
+		return &chat.ListRoomMembersResponse{
+			Success: false,
+			Message: "檢查成員失敗",
+		}, nil
+	}
+	if !isMember {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "list room members: not a room member")
+		return &chat.ListRoomMembersResponse{
+			Success: false,
+			Message: "您不是此聊天室的成員",
+		}, nil
+	}
+
+	query := chatroom.MemberQuery{
+		Limit:  normalizePageSize(int(req.Limit)),
+		Cursor: req.Cursor,
+		Role:   req.Role,
+	}
+	if req.Search != "" {
+		// 用戶輸入的搜索詞轉義後再作為正則使用，防止注入正則運算符
+		query.UsernameFilter = database.SafePrefixRegexQuery(req.Search)
+	}
+
+	members, cursor, hasMore, err := s.repos.ChatRoom.ListMembers(ctx, req.RoomId, query)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室成員失敗", req.UserId, req.RoomId, err)
+		return &chat.ListRoomMembersResponse{
+			Success: false,
+			Message: "獲取聊天室成員失敗: " + err.Error(),
+		}, nil
+	}
+
+	return &chat.ListRoomMembersResponse{
+		Success: true,
+		Message: "獲取聊天室成員成功",
+		Members: convertMembersToGRPC(members),
+		Cursor:  cursor,
+		HasMore: hasMore,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// maxSlowmodeSeconds 慢速模式最大間隔（6 小時）
+const maxSlowmodeSeconds = 6 * 60 * 60
+
+var (
+	errRoomReadOnly         = errors.New("聊天室為只讀模式，無法發送訊息")
+	errRoomAnnouncementOnly = errors.New("聊天室為公告模式，只有擁有者和管理員可以發言")
+)
+
+// roomMode 聊天室當前模式（未設置時為普通模式）
+func roomMode(room *chatroom.ChatRoom) string {
+	if room.Settings.Mode == "" {
+		return chatroom.RoomModeNormal
+	}
+	return room.Settings.Mode
+}
+
+// validateRoomMode 驗證聊天室模式設置
+func validateRoomMode(mode string, slowmodeSeconds int) error {
+	switch mode {
+	case chatroom.RoomModeNormal, chatroom.RoomModeAnnouncement, chatroom.RoomModeReadOnly:
+		return nil
+	case chatroom.RoomModeSlowmode:
+		if slowmodeSeconds <= 0 || slowmodeSeconds > maxSlowmodeSeconds {
+			return fmt.Errorf("慢速模式間隔必須在 1 到 %d 秒之間", maxSlowmodeSeconds)
+		}
+		return nil
+	default:
+		return fmt.Errorf("不支持的聊天室模式: %s", mode)
+	}
+}
+
+// checkRoomMode 檢查聊天室模式是否允許用戶發言
+// lastSentAt 為用戶上一次發言時間（只在慢速模式下使用）
+func checkRoomMode(room *chatroom.ChatRoom, senderID string, lastSentAt, now time.Time) error {
+	switch roomMode(room) {
+	case chatroom.RoomModeReadOnly:
+		return errRoomReadOnly
+
+	case chatroom.RoomModeAnnouncement:
+		if !canManageRoom(room, senderID) {
+			return errRoomAnnouncementOnly
+		}
+
+	case chatroom.RoomModeSlowmode:
+		// 擁有者和管理員不受慢速模式限制
+		if canManageRoom(room, senderID) || lastSentAt.IsZero() {
+			return nil
+		}
+		interval := time.Duration(room.Settings.SlowmodeSeconds) * time.Second
+		if wait := lastSentAt.Add(interval).Sub(now); wait > 0 {
+			return fmt.Errorf("聊天室為慢速模式，請在 %d 秒後再發言", int(math.Ceil(wait.Seconds())))
+		}
+	}
+
+	return nil
+}
+
+// enforceRoomMode 發送訊息前檢查聊天室模式
+func (s *Server) enforceRoomMode(ctx context.Context, req *chat.SendMessageRequest) error {
+	room, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", req.SenderId, req.RoomId, err)
+		return fmt.Errorf("聊天室不存在")
+	}
+
+	var lastSentAt time.Time
+	if roomMode(room) == chatroom.RoomModeSlowmode && !canManageRoom(room, req.SenderId) {
+		lastSentAt, err = s.repos.Message.GetLastSentAt(ctx, req.RoomId, req.SenderId)
+		if err != nil {
+			logErrorWithUserAndRoom(ctx, "獲取最後發言時間失敗", req.SenderId, req.RoomId, err)
+			return fmt.Errorf("發送消息失敗: %w", err)
+		}
+	}
+
+	return checkRoomMode(room, req.SenderId, lastSentAt, time.Now())
+}
+
+// SetRoomMode 設置聊天室模式（僅限擁有者或管理員）
+func (s *Server) SetRoomMode(ctx context.Context, req *chat.SetRoomModeRequest) (*chat.SetRoomModeResponse, error) {
+	// 啟用 JWT 時操作者必須是認證用戶，避免冒充擁有者或管理員
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "set room mode: user is not the authenticated user")
+		return &chat.SetRoomModeResponse{
+			Success: false,
+			Message: "只有聊天室擁有者或管理員可以設置聊天室模式",
+		}, nil
+	}
+
+	slowmodeSeconds := int(req.SlowmodeSeconds)
+	if err := validateRoomMode(req.Mode, slowmodeSeconds); err != nil {
+		return &chat.SetRoomModeResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	room, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.SetRoomModeResponse{
+			Success: false,
+			Message: "聊天室不存在",
+		}, nil
+	}
+
+	if !canManageRoom(room, req.UserId) {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "not room owner or admin")
+		return &chat.SetRoomModeResponse{
+			Success: false,
+			Message: "只有聊天室擁有者或管理員可以設置聊天室模式",
+		}, nil
+	}
+
+	if req.Mode != chatroom.RoomModeSlowmode {
+		slowmodeSeconds = 0
+	}
+	update := map[string]interface{}{
+		"settings.mode":             req.Mode,
+		"settings.slowmode_seconds": slowmodeSeconds,
+	}
+	err = s.repos.ChatRoom.UpdateIf(ctx, req.RoomId, room.Version, update)
+	if errors.Is(err, chatroom.ErrVersionConflict) {
+		return &chat.SetRoomModeResponse{
+			Success: false,
+			Message: "聊天室已被其他人修改，請重試",
+		}, nil
+	}
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "設置聊天室模式失敗", req.UserId, req.RoomId, err)
+		return &chat.SetRoomModeResponse{
+			Success: false,
+			Message: "設置聊天室模式失敗: " + err.Error(),
+		}, nil
+	}
+
+	s.createSystemMessageAndUpdateRoom(ctx, req.RoomId, roomModeChangedText(room.Settings.Locale, req.Mode, slowmodeSeconds), "創建聊天室模式系統消息失敗")
+
+	// 審計日誌
+	s.audit.LogDataModification(ctx, req.UserId, "chat_room", req.RoomId, "set_room_mode", update)
+
+	logger.Info(ctx, "設置聊天室模式成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("set_room_mode"),
+		logger.WithDetails(map[string]interface{}{
+			"mode":             req.Mode,
+			"slowmode_seconds": slowmodeSeconds,
+		}))
+
+	return &chat.SetRoomModeResponse{
+		Success: true,
+		Message: "聊天室模式已更新",
+	}, nil
+}
+
+// roomModeChangedText 聊天室模式變更的系統訊息
+func roomModeChangedText(locale, mode string, slowmodeSeconds int) string {
+	switch mode {
+	case chatroom.RoomModeSlowmode:
+		return systemMessageText(locale, constants.SystemMessageModeSlowmode, map[string]string{"seconds": strconv.Itoa(slowmodeSeconds)})
+	case chatroom.RoomModeAnnouncement:
+		return systemMessageText(locale, constants.SystemMessageModeAnnouncement, nil)
+	case chatroom.RoomModeReadOnly:
+		return systemMessageText(locale, constants.SystemMessageModeReadOnly, nil)
+	default:
+		return systemMessageText(locale, constants.SystemMessageModeNormal, nil)
+	}
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+func newModeTestRoom(mode string, slowmodeSeconds int) *chatroom.ChatRoom {
+	return &chatroom.ChatRoom{
+		OwnerID: "owner",
+		Members: []chatroom.RoomMember{
+			{UserID: "owner", Role: "member"},
+			{UserID: "admin", Role: roleAdmin},
+			{UserID: "member", Role: "member"},
+		},
+		Settings: chatroom.RoomSettings{Mode: mode, SlowmodeSeconds: slowmodeSeconds},
+	}
+}
+
+func TestCheckRoomMode(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		room       *chatroom.ChatRoom
+		senderID   string
+		lastSentAt time.Time
+		wantErr    bool
+	}{
+		{"normal member can send", newModeTestRoom("", 0), "member", now, false},
+		{"explicit normal mode", newModeTestRoom(chatroom.RoomModeNormal, 0), "member", now, false},
+
+		{"announcement blocks member", newModeTestRoom(chatroom.RoomModeAnnouncement, 0), "member", time.Time{}, true},
+		{"announcement allows owner", newModeTestRoom(chatroom.RoomModeAnnouncement, 0), "owner", time.Time{}, false},
+		{"announcement allows admin", newModeTestRoom(chatroom.RoomModeAnnouncement, 0), "admin", time.Time{}, false},
+
+		{"read only blocks member", newModeTestRoom(chatroom.RoomModeReadOnly, 0), "member", time.Time{}, true},
+		{"read only blocks owner", newModeTestRoom(chatroom.RoomModeReadOnly, 0), "owner", time.Time{}, true},
+
+		{"slowmode first message", newModeTestRoom(chatroom.RoomModeSlowmode, 30), "member", time.Time{}, false},
+		{"slowmode too soon", newModeTestRoom(chatroom.RoomModeSlowmode, 30), "member", now.Add(-10 * time.Second), true},
+		{"slowmode after interval", newModeTestRoom(chatroom.RoomModeSlowmode, 30), "member", now.Add(-31 * time.Second), false},
+		{"slowmode exempts admin", newModeTestRoom(chatroom.RoomModeSlowmode, 30), "admin", now.Add(-time.Second), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRoomMode(tt.room, tt.senderID, tt.lastSentAt, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkRoomMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckRoomMode_AnnouncementError(t *testing.T) {
+	err := checkRoomMode(newModeTestRoom(chatroom.RoomModeAnnouncement, 0), "member", time.Time{}, time.Now())
+	if err != errRoomAnnouncementOnly {
+		t.Errorf("expected announcement-only error, got %v", err)
+	}
+}
+
+func TestValidateRoomMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		seconds int
+		wantErr bool
+	}{
+		{chatroom.RoomModeNormal, 0, false},
+		{chatroom.RoomModeAnnouncement, 0, false},
+		{chatroom.RoomModeReadOnly, 0, false},
+		{chatroom.RoomModeSlowmode, 10, false},
+		{chatroom.RoomModeSlowmode, 0, true},
+		{chatroom.RoomModeSlowmode, maxSlowmodeSeconds + 1, true},
+		{"party", 0, true},
+	}
+
+	for _, tt := range tests {
+		if err := validateRoomMode(tt.mode, tt.seconds); (err != nil) != tt.wantErr {
+			t.Errorf("validateRoomMode(%q, %d) error = %v, wantErr %v", tt.mode, tt.seconds, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSetRoomMode_RejectsImpersonatedAdmin(t *testing.T) {
+	// 認證用戶冒充管理員：應在讀取聊天室之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.SetRoomMode(ctx, &chat.SetRoomModeRequest{
+		RoomId: "507f1f77bcf86cd799439011",
+		UserId: "admin",
+		Mode:   chatroom.RoomModeReadOnly,
+	})
+	if err != nil || resp.Success {
+		t.Errorf("SetRoomMode() = %+v, %v; want Success=false", resp, err)
+	}
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// muteUntil 根據靜音時長計算結束時間，0 表示永久靜音（返回 nil）
+func muteUntil(durationSeconds int64, now time.Time) (*time.Time, error) {
+	if durationSeconds < 0 || durationSeconds > constants.MaxRoomMuteDurationSec {
+		return nil, fmt.Errorf("靜音時長必須在 0 到 %d 秒之間（0 表示直到手動取消）", constants.MaxRoomMuteDurationSec)
+	}
+	if durationSeconds == 0 {
+		return nil, nil
+	}
+	until := now.Add(time.Duration(durationSeconds) * time.Second).UTC()
+	return &until, nil
+}
+
+// memberMuteState 成員當前的靜音狀態，限時靜音已過期時視為未靜音
+func memberMuteState(member *chatroom.RoomMember, now time.Time) (muted bool, mutedUntil int64) {
+	if member == nil || !member.IsMuted(now) {
+		return false, 0
+	}
+	if !member.Muted && member.MutedUntil != nil {
+		return true, member.MutedUntil.Unix()
+	}
+	return true, 0
+}
+
+// MuteRoom 靜音聊天室（只影響請求的成員，被提及時仍會通知）
+func (s *Server) MuteRoom(ctx context.Context, req *chat.MuteRoomRequest) (*chat.MuteRoomResponse, error) {
+	until, err := muteUntil(req.DurationSeconds, time.Now())
+	if err != nil {
+		return &chat.MuteRoomResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if ok, message := s.checkMemberPreference(ctx, req.RoomId, req.UserId); !ok {
+		return &chat.MuteRoomResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
+	if err := s.repos.ChatRoom.SetMemberMute(ctx, req.RoomId, req.UserId, until == nil, until); err != nil {
+		logErrorWithUserAndRoom(ctx, "靜音聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.MuteRoomResponse{
+			Success: false,
+			Message: "靜音聊天室失敗: " + err.Error(),
+		}, nil
+	}
+
+	var mutedUntil int64
+	if until != nil {
+		mutedUntil = until.Unix()
+	}
+
+	logger.Info(ctx, "靜音聊天室成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("mute_room"),
+		logger.WithDetails(map[string]interface{}{
+			"duration_seconds": req.DurationSeconds,
+		}))
+
+	return &chat.MuteRoomResponse{
+		Success:    true,
+		Message:    "聊天室已靜音",
+		MutedUntil: mutedUntil,
+	}, nil
+}
+
+// UnmuteRoom 取消靜音聊天室
+func (s *Server) UnmuteRoom(ctx context.Context, req *chat.UnmuteRoomRequest) (*chat.UnmuteRoomResponse, error) {
+	if ok, message := s.checkMemberPreference(ctx, req.RoomId, req.UserId); !ok {
+		return &chat.UnmuteRoomResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
+	if err := s.repos.ChatRoom.SetMemberMute(ctx, req.RoomId, req.UserId, false, nil); err != nil {
+		logErrorWithUserAndRoom(ctx, "取消靜音聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.UnmuteRoomResponse{
+			Success: false,
+			Message: "取消靜音聊天室失敗: " + err.Error(),
+		}, nil
+	}
+
+	logger.Info(ctx, "取消靜音聊天室成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("unmute_room"))
+
+	return &chat.UnmuteRoomResponse{
+		Success: true,
+		Message: "已取消靜音",
+	}, nil
+}
+
+// checkMemberPreference 只有聊天室成員可以設置自己的靜音、封存等偏好（啟用 JWT 時必須是認證用戶本人）
+func (s *Server) checkMemberPreference(ctx context.Context, roomID, userID string) (bool, string) {
+	if userID == "" {
+		return false, "缺少 user_id"
+	}
+	if s.rejectImpersonation(ctx, userID, roomID, "member preference") {
+		return false, "只能設置自己的偏好"
+	}
+
+	isMember, err := s.repos.ChatRoom.IsMember(ctx, roomID, userID)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "檢查成員資格失敗", userID, roomID, err)
+		return false, "檢查成員資格失敗"
+	}
+	if !isMember {
+		return false, "您不是此聊天室的成員"
+	}
+	return true, ""
+}
+
+// isRoomMutedFor 用戶是否已靜音聊天室，查詢失敗時視為未靜音
+func (s *Server) isRoomMutedFor(ctx context.Context, roomID, userID string) bool {
+	room, err := s.repos.ChatRoom.GetByID(ctx, roomID)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", userID, roomID, err)
+		return false
+	}
+	muted, _ := memberMuteState(room.FindMember(userID), time.Now())
+	return muted
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+func TestMuteUntil(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	until, err := muteUntil(0, now)
+	if err != nil || until != nil {
+		t.Errorf("muteUntil(0) = %v, %v, want nil (indefinite)", until, err)
+	}
+
+	until, err = muteUntil(3600, now)
+	if err != nil || until == nil || !until.Equal(now.Add(time.Hour)) {
+		t.Errorf("muteUntil(3600) = %v, %v, want %v", until, err, now.Add(time.Hour))
+	}
+
+	for _, seconds := range []int64{-1, constants.MaxRoomMuteDurationSec + 1} {
+		if _, err := muteUntil(seconds, now); err == nil {
+			t.Errorf("muteUntil(%d) expected error", seconds)
+		}
+	}
+}
+
+func TestMemberMuteState(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		member    *chatroom.RoomMember
+		wantMuted bool
+		wantUntil int64
+	}{
+		{"not a member", nil, false, 0},
+		{"not muted", &chatroom.RoomMember{}, false, 0},
+		{"indefinite", &chatroom.RoomMember{Muted: true}, true, 0},
+		{"timed", &chatroom.RoomMember{MutedUntil: &future}, true, future.Unix()},
+		{"timed expired", &chatroom.RoomMember{MutedUntil: &past}, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			muted, until := memberMuteState(tt.member, now)
+			if muted != tt.wantMuted || until != tt.wantUntil {
+				t.Errorf("memberMuteState() = %v, %d, want %v, %d", muted, until, tt.wantMuted, tt.wantUntil)
+			}
+		})
+	}
+}
+
+func TestNotificationRecipients_TimedMute(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	future := now.Add(time.Hour)
+	past := now.Add(-time.Hour)
+
+	members := []chatroom.RoomMember{
+		{UserID: "sender"},
+		{UserID: "muted-timed", MutedUntil: &future},
+		{UserID: "mute-expired", MutedUntil: &past},
+	}
+
+	// 靜音只影響該成員自己
+	got := notificationRecipients(members, "sender", nil, now)
+	want := []string{"mute-expired"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("notificationRecipients() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,178 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// TransferOwnership 轉讓聊天室擁有權（僅限當前擁有者）
+// 新擁有者必須是聊天室成員；原擁有者留在聊天室，角色降為 former_owner_role（默認管理員）
+// 啟用 JWT 時 user_id 必須是已認證的用戶，避免冒充擁有者
+func (s *Server) TransferOwnership(ctx context.Context, req *chat.TransferOwnershipRequest) (*chat.TransferOwnershipResponse, error) {
+	if req.UserId == "" || req.NewOwnerId == "" {
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "缺少 user_id 或 new_owner_id",
+		}, nil
+	}
+	if req.UserId == req.NewOwnerId {
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "新擁有者不能是自己",
+		}, nil
+	}
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "transfer ownership: user is not the authenticated user")
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "操作者與已認證的用戶不一致",
+		}, nil
+	}
+
+	formerRole := req.FormerOwnerRole
+	switch formerRole {
+	case "":
+		formerRole = roleAdmin
+	case roleAdmin, "member":
+	default:
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "原擁有者的角色只能是 admin 或 member",
+		}, nil
+	}
+
+	room, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "聊天室不存在",
+		}, nil
+	}
+
+	if room.OwnerID != req.UserId {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "transfer ownership: not room owner")
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "只有聊天室擁有者可以轉讓擁有權",
+		}, nil
+	}
+	if room.FindMember(req.NewOwnerId) == nil {
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "新擁有者必須是聊天室成員",
+		}, nil
+	}
+
+	err = s.transferOwnership(ctx, room, req.NewOwnerId, formerRole)
+	if errors.Is(err, chatroom.ErrVersionConflict) {
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "聊天室已被其他人修改，請重試",
+		}, nil
+	}
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "轉讓擁有權失敗", req.UserId, req.RoomId, err)
+		return &chat.TransferOwnershipResponse{
+			Success: false,
+			Message: "轉讓擁有權失敗: " + err.Error(),
+		}, nil
+	}
+
+	logger.Info(ctx, "轉讓擁有權成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("transfer_ownership"),
+		logger.WithDetails(map[string]interface{}{
+			"new_owner_id":      req.NewOwnerId,
+			"former_owner_role": formerRole,
+		}))
+
+	return &chat.TransferOwnershipResponse{
+		Success: true,
+		Message: "擁有權已轉讓",
+	}, nil
+}
+
+// transferOwnership 寫入新擁有者（以讀取時的版本為條件），發送系統訊息並記錄審計日誌
+// formerRole 為空時不修改原擁有者的角色（原擁有者正在離開聊天室）
+func (s *Server) transferOwnership(ctx context.Context, room *chatroom.ChatRoom, newOwnerID, formerRole string) error {
+	if err := s.repos.ChatRoom.TransferOwnership(ctx, room.ID, room.Version, room.OwnerID, newOwnerID, formerRole); err != nil {
+		return err
+	}
+
+	changed := systemMessageText(room.Settings.Locale, constants.SystemMessageOwnerChanged, map[string]string{"user": newOwnerID})
+	s.createSystemMessageAndUpdateRoom(ctx, room.ID, changed, "創建擁有者變更系統消息失敗")
+	s.audit.LogOwnershipTransferred(ctx, room.OwnerID, room.ID, newOwnerID)
+	return nil
+}
+
+// releaseOwnership 擁有者離開聊天室前的處理：按 owner_leave_mode 自動轉讓擁有權或拒絕離開
+// 不是擁有者、或擁有者是最後一個成員時直接允許離開；返回 false 時附帶拒絕原因
+func (s *Server) releaseOwnership(ctx context.Context, roomID, userID string) (bool, string) {
+	room, err := s.repos.ChatRoom.GetByID(ctx, roomID)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", userID, roomID, err)
+		return false, "聊天室不存在"
+	}
+	if room.OwnerID != userID {
+		return true, ""
+	}
+
+	successor := ownerSuccessor(room, userID)
+	if successor == "" {
+		return true, ""
+	}
+	if ownerLeaveMode() == constants.OwnerLeaveBlock {
+		return false, "擁有者不能直接離開聊天室，請先轉讓擁有權"
+	}
+
+	if err := s.transferOwnership(ctx, room, successor, ""); err != nil {
+		logErrorWithUserAndRoom(ctx, "自動轉讓擁有權失敗", userID, roomID, err)
+		return false, "自動轉讓擁有權失敗，請重試"
+	}
+	logger.Info(ctx, "擁有者離開，已自動轉讓擁有權",
+		logger.WithUserID(userID),
+		logger.WithRoomID(roomID),
+		logger.WithAction("transfer_ownership"),
+		logger.WithDetails(map[string]interface{}{"new_owner_id": successor}))
+	return true, ""
+}
+
+// ownerSuccessor 擁有者離開時的繼任者：最早加入的管理員，沒有管理員時為最早加入的成員（沒有其他成員時返回空）
+func ownerSuccessor(room *chatroom.ChatRoom, leavingID string) string {
+	var successor *chatroom.RoomMember
+	for i := range room.Members {
+		member := &room.Members[i]
+		if member.UserID == leavingID {
+			continue
+		}
+		if successor == nil {
+			successor = member
+			continue
+		}
+		isAdmin, successorIsAdmin := member.Role == roleAdmin, successor.Role == roleAdmin
+		if (isAdmin && !successorIsAdmin) || (isAdmin == successorIsAdmin && member.JoinedAt.Before(successor.JoinedAt)) {
+			successor = member
+		}
+	}
+	if successor == nil {
+		return ""
+	}
+	return successor.UserID
+}
+
+// ownerLeaveMode 讀取擁有者離開聊天室時的處理方式（默認自動轉讓）
+func ownerLeaveMode() string {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Room.OwnerLeaveMode != "" {
+		return cfg.Limits.Room.OwnerLeaveMode
+	}
+	return constants.OwnerLeaveTransfer
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+func TestOwnerSuccessor(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	member := func(id, role string, joinedAfter time.Duration) chatroom.RoomMember {
+		return chatroom.RoomMember{UserID: id, Role: role, JoinedAt: base.Add(joinedAfter)}
+	}
+
+	tests := []struct {
+		name    string
+		members []chatroom.RoomMember
+		want    string
+	}{
+		{"oldest admin first", []chatroom.RoomMember{
+			member("owner", "member", 0),
+			member("alice", "member", time.Hour),
+			member("admin-late", roleAdmin, 3*time.Hour),
+			member("admin-early", roleAdmin, 2*time.Hour),
+		}, "admin-early"},
+		{"oldest member without admins", []chatroom.RoomMember{
+			member("bob", "member", 2*time.Hour),
+			member("owner", "member", 0),
+			member("alice", "member", time.Hour),
+		}, "alice"},
+		{"owner is the last member", []chatroom.RoomMember{member("owner", "member", 0)}, ""},
+	}
+	for _, tt := range tests {
+		room := &chatroom.ChatRoom{OwnerID: "owner", Members: tt.members}
+		if got := ownerSuccessor(room, "owner"); got != tt.want {
+			t.Errorf("%s: ownerSuccessor() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOwnerLeaveMode_DefaultsToTransfer(t *testing.T) {
+	if mode := ownerLeaveMode(); mode != constants.OwnerLeaveTransfer {
+		t.Errorf("ownerLeaveMode() = %q, want %q", mode, constants.OwnerLeaveTransfer)
+	}
+}
+
+func TestTransferOwnership_ValidatesRequest(t *testing.T) {
+	// 沒有倉儲的 Server 一旦讀取聊天室就會 panic，無效的請求應在此之前返回
+	s := &Server{}
+	for _, req := range []*chat.TransferOwnershipRequest{
+		{RoomId: "507f1f77bcf86cd799439011", UserId: "alice"},
+		{RoomId: "507f1f77bcf86cd799439011", UserId: "alice", NewOwnerId: "alice"},
+		{RoomId: "507f1f77bcf86cd799439011", UserId: "alice", NewOwnerId: "bob", FormerOwnerRole: "owner"},
+	} {
+		resp, err := s.TransferOwnership(context.Background(), req)
+		if err != nil || resp.Success {
+			t.Errorf("TransferOwnership(%+v) = %+v, %v; want Success=false", req, resp, err)
+		}
+	}
+}
+
+func TestTransferOwnership_RejectsImpersonatedOwner(t *testing.T) {
+	// 認證用戶冒充擁有者：應在讀取聊天室之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.TransferOwnership(ctx, &chat.TransferOwnershipRequest{
+		RoomId:     "507f1f77bcf86cd799439011",
+		UserId:     "owner",
+		NewOwnerId: "mallory",
+	})
+	if err != nil || resp.Success {
+		t.Errorf("TransferOwnership() = %+v, %v; want Success=false", resp, err)
+	}
+}
+
+func TestLeaveRoom_RejectsImpersonatedUser(t *testing.T) {
+	// 冒充擁有者離開會觸發自動轉讓擁有權，應在讀取聊天室之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.LeaveRoom(ctx, &chat.LeaveRoomRequest{
+		RoomId: "507f1f77bcf86cd799439011",
+		UserId: "owner",
+	})
+	if err != nil || resp.Success {
+		t.Errorf("LeaveRoom() = %+v, %v; want Success=false", resp, err)
+	}
+}
@@ -0,0 +1,65 @@
+package grpc
+
+import (
+	"context"
+
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/proto/chat"
+)
+
+// PinRoom 置頂聊天室（只影響請求的成員）
+// 置頂的聊天室在用戶的聊天室列表中排在最前；置頂和封存互斥，已封存的聊天室置頂時取消封存
+func (s *Server) PinRoom(ctx context.Context, req *chat.PinRoomRequest) (*chat.PinRoomResponse, error) {
+	if ok, message := s.checkMemberPreference(ctx, req.RoomId, req.UserId); !ok {
+		return &chat.PinRoomResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
+	if err := s.repos.ChatRoom.SetMemberPinned(ctx, req.RoomId, req.UserId, true); err != nil {
+		logErrorWithUserAndRoom(ctx, "置頂聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.PinRoomResponse{
+			Success: false,
+			Message: "置頂聊天室失敗: " + err.Error(),
+		}, nil
+	}
+
+	logger.Info(ctx, "置頂聊天室成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("pin_room"))
+
+	return &chat.PinRoomResponse{
+		Success: true,
+		Message: "聊天室已置頂",
+	}, nil
+}
+
+// UnpinRoom 取消置頂聊天室
+func (s *Server) UnpinRoom(ctx context.Context, req *chat.UnpinRoomRequest) (*chat.UnpinRoomResponse, error) {
+	if ok, message := s.checkMemberPreference(ctx, req.RoomId, req.UserId); !ok {
+		return &chat.UnpinRoomResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
+	if err := s.repos.ChatRoom.SetMemberPinned(ctx, req.RoomId, req.UserId, false); err != nil {
+		logErrorWithUserAndRoom(ctx, "取消置頂聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.UnpinRoomResponse{
+			Success: false,
+			Message: "取消置頂聊天室失敗: " + err.Error(),
+		}, nil
+	}
+
+	logger.Info(ctx, "取消置頂聊天室成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("unpin_room"))
+
+	return &chat.UnpinRoomResponse{
+		Success: true,
+		Message: "已取消置頂",
+	}, nil
+}
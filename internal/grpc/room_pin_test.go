@@ -0,0 +1,22 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/proto/chat"
+)
+
+func TestPinRoom_RequiresUserID(t *testing.T) {
+	// 沒有倉儲的 Server 一旦查詢成員資格就會 panic，缺少 user_id 時應在此之前返回
+	s := &Server{}
+
+	pin, err := s.PinRoom(context.Background(), &chat.PinRoomRequest{RoomId: "507f1f77bcf86cd799439011"})
+	if err != nil || pin.Success {
+		t.Errorf("PinRoom() = %+v, %v; want Success=false", pin, err)
+	}
+	unpin, err := s.UnpinRoom(context.Background(), &chat.UnpinRoomRequest{RoomId: "507f1f77bcf86cd799439011"})
+	if err != nil || unpin.Success {
+		t.Errorf("UnpinRoom() = %+v, %v; want Success=false", unpin, err)
+	}
+}
@@ -0,0 +1,398 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scheduledLimits 定時訊息的最長延遲和每個用戶的待發送上限
+func scheduledLimits() (maxDelay time.Duration, maxPending int) {
+	maxDays := constants.DefaultScheduledMaxDelayDays
+	maxPending = constants.DefaultScheduledMaxPendingPerUser
+	if cfg := config.Get(); cfg != nil {
+		if cfg.Limits.Scheduled.MaxDelayDays > 0 {
+			maxDays = cfg.Limits.Scheduled.MaxDelayDays
+		}
+		if cfg.Limits.Scheduled.MaxPendingPerUser > 0 {
+			maxPending = cfg.Limits.Scheduled.MaxPendingPerUser
+		}
+	}
+	return time.Duration(maxDays) * 24 * time.Hour, maxPending
+}
+
+// scheduledEnabled 是否啟用定時訊息
+func scheduledEnabled() bool {
+	cfg := config.Get()
+	return cfg != nil && cfg.Limits.Scheduled.Enabled
+}
+
+// validateSendAt 驗證發送時間必須晚於當前時間，且不超過最長延遲
+func validateSendAt(sendAt int64, now time.Time, maxDelay time.Duration) error {
+	if sendAt <= now.Unix() {
+		return fmt.Errorf("發送時間必須晚於當前時間")
+	}
+	if time.Unix(sendAt, 0).After(now.Add(maxDelay)) {
+		return fmt.Errorf("發送時間不能晚於 %d 天之後", int(maxDelay.Hours()/24))
+	}
+	return nil
+}
+
+// ScheduleMessage 創建定時訊息，到達發送時間後由背景任務發送到聊天室
+// 內容和元數據的驗證規則與 SendMessage 相同，創建時即檢查成員資格，發送時再檢查一次
+func (s *Server) ScheduleMessage(ctx context.Context, req *chat.ScheduleMessageRequest) (*chat.ScheduleMessageResponse, error) {
+	if !scheduledEnabled() {
+		return &chat.ScheduleMessageResponse{Success: false, Message: "定時訊息未啟用"}, nil
+	}
+	msg := req.Message
+	if msg == nil {
+		return &chat.ScheduleMessageResponse{Success: false, Message: "缺少訊息內容"}, nil
+	}
+	if msg.Type == "" {
+		msg.Type = messageTypeText
+	}
+	if err := validateSendContent(msg); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateMessageMetadata(msg.Type, msg.Content, msg.Metadata); err != nil {
+		return &chat.ScheduleMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+	if err := validateMessageTTL(msg.TtlSeconds); err != nil {
+		return &chat.ScheduleMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	maxDelay, maxPending := scheduledLimits()
+	if err := validateSendAt(req.SendAt, time.Now(), maxDelay); err != nil {
+		return &chat.ScheduleMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	if err := s.checkSenderMembership(ctx, msg); err != nil {
+		return &chat.ScheduleMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	pending, err := s.repos.Scheduled.CountPending(ctx, msg.SenderId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "統計待發送定時訊息失敗", msg.SenderId, msg.RoomId, err)
+		return &chat.ScheduleMessageResponse{Success: false, Message: "創建定時訊息失敗"}, nil
+	}
+	if pending >= int64(maxPending) {
+		return &chat.ScheduleMessageResponse{
+			Success: false,
+			Message: fmt.Sprintf("待發送的定時訊息已達上限（%d 條）", maxPending),
+		}, nil
+	}
+
+	// 等待發送期間內容同樣加密存儲
+	encryptedContent, keyVersion, err := s.encryption.EncryptMessageWithVersion(msg.Content, msg.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "定時訊息加密失敗", msg.SenderId, msg.RoomId, err)
+		return &chat.ScheduleMessageResponse{Success: false, Message: "創建定時訊息失敗"}, nil
+	}
+
+	scheduled := &chatroom.ScheduledMessage{
+		RoomID:     msg.RoomId,
+		SenderID:   msg.SenderId,
+		Content:    encryptedContent,
+		KeyVersion: keyVersion,
+		Type:       msg.Type,
+		Metadata:   metadataFromGRPC(msg.Metadata),
+		Mentions:   msg.Mentions,
+		TTLSeconds: msg.TtlSeconds,
+		SendAt:     time.Unix(req.SendAt, 0),
+	}
+	if err := s.repos.Scheduled.Create(ctx, scheduled); err != nil {
+		logErrorWithUserAndRoom(ctx, "創建定時訊息失敗", msg.SenderId, msg.RoomId, err)
+		return &chat.ScheduleMessageResponse{Success: false, Message: "創建定時訊息失敗"}, nil
+	}
+
+	logger.Info(ctx, "定時訊息已創建",
+		logger.WithUserID(msg.SenderId),
+		logger.WithRoomID(msg.RoomId),
+		logger.WithAction("schedule_message"),
+		logger.WithDetails(map[string]interface{}{
+			"scheduled_id": scheduled.ID.Hex(),
+			"send_at":      req.SendAt,
+			"type":         msg.Type,
+		}))
+
+	return &chat.ScheduleMessageResponse{
+		Success:          true,
+		Message:          "定時訊息已創建",
+		ScheduledMessage: scheduledToGRPC(scheduled, msg.Content),
+	}, nil
+}
+
+// ListScheduledMessages 列出用戶待發送的定時訊息（按發送時間升序）
+func (s *Server) ListScheduledMessages(ctx context.Context, req *chat.ListScheduledMessagesRequest) (*chat.ListScheduledMessagesResponse, error) {
+	// 啟用 JWT 時只能操作認證用戶自己的定時訊息（與 ScheduleMessage 的發送者檢查一致）
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "list scheduled messages: user is not the authenticated user")
+		return &chat.ListScheduledMessagesResponse{Success: false, Message: "只能查看自己的定時訊息"}, nil
+	}
+	if !scheduledEnabled() {
+		return &chat.ListScheduledMessagesResponse{Success: false, Message: "定時訊息未啟用"}, nil
+	}
+	if err := middleware.ValidateUserID(req.UserId); err != nil {
+		return &chat.ListScheduledMessagesResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	_, maxPending := scheduledLimits()
+	items, err := s.repos.Scheduled.ListPending(ctx, req.UserId, req.RoomId, maxPending)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取定時訊息失敗", req.UserId, req.RoomId, err)
+		return &chat.ListScheduledMessagesResponse{Success: false, Message: "獲取定時訊息失敗"}, nil
+	}
+
+	result := make([]*chat.ScheduledMessage, 0, len(items))
+	for _, item := range items {
+		content, err := s.encryption.DecryptMessageWithVersion(item.Content, item.RoomID, item.KeyVersion)
+		if err != nil {
+			logger.Sampled(ctx, logger.SeverityWarning, "定時訊息解密失敗", item.RoomID,
+				logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+			content = decryptFailedText
+		}
+		result = append(result, scheduledToGRPC(item, content))
+	}
+
+	return &chat.ListScheduledMessagesResponse{
+		Success:           true,
+		Message:           "獲取定時訊息成功",
+		ScheduledMessages: result,
+	}, nil
+}
+
+// CancelScheduledMessage 取消待發送的定時訊息，只有發送者可以取消，已開始發送的訊息不能取消
+func (s *Server) CancelScheduledMessage(ctx context.Context, req *chat.CancelScheduledMessageRequest) (*chat.CancelScheduledMessageResponse, error) {
+	// 啟用 JWT 時只能操作認證用戶自己的定時訊息（與 ScheduleMessage 的發送者檢查一致）
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), "", "cancel scheduled message: user is not the authenticated user")
+		return &chat.CancelScheduledMessageResponse{Success: false, Message: "只能取消自己的定時訊息"}, nil
+	}
+	if !scheduledEnabled() {
+		return &chat.CancelScheduledMessageResponse{Success: false, Message: "定時訊息未啟用"}, nil
+	}
+	if err := middleware.ValidateUserID(req.UserId); err != nil {
+		return &chat.CancelScheduledMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	err := s.repos.Scheduled.Cancel(ctx, req.Id, req.UserId)
+	if errors.Is(err, chatroom.ErrScheduledNotFound) {
+		return &chat.CancelScheduledMessageResponse{Success: false, Message: "定時訊息不存在或已無法取消"}, nil
+	}
+	if err != nil {
+		logger.Error(ctx, "取消定時訊息失敗",
+			logger.WithUserID(req.UserId),
+			logger.WithDetails(map[string]interface{}{"scheduled_id": req.Id, "error": err.Error()}))
+		return &chat.CancelScheduledMessageResponse{Success: false, Message: "取消定時訊息失敗"}, nil
+	}
+
+	logger.Info(ctx, "定時訊息已取消",
+		logger.WithUserID(req.UserId),
+		logger.WithAction("cancel_scheduled_message"),
+		logger.WithDetails(map[string]interface{}{"scheduled_id": req.Id}))
+
+	return &chat.CancelScheduledMessageResponse{Success: true, Message: "定時訊息已取消"}, nil
+}
+
+// scheduledToGRPC 轉換定時訊息為 gRPC 格式（content 為解密後的內容）
+func scheduledToGRPC(item *chatroom.ScheduledMessage, content string) *chat.ScheduledMessage {
+	return &chat.ScheduledMessage{
+		Id:        item.ID.Hex(),
+		RoomId:    item.RoomID,
+		SenderId:  item.SenderID,
+		Content:   content,
+		Type:      item.Type,
+		Metadata:  metadataToGRPC(&item.Metadata),
+		Mentions:  item.Mentions,
+		SendAt:    item.SendAt.Unix(),
+		Status:    item.Status,
+		MessageId: item.MessageID,
+		CreatedAt: item.CreatedAt.Unix(),
+	}
+}
+
+// deliverScheduled 發送一條已領取的定時訊息
+// 發送者已不是成員、聊天室不允許發言或內容無法解密時標記為失敗；寫入訊息時的暫時性錯誤返回 error，
+// 訊息保持領取狀態，超時後由發送任務重新領取。重新領取時預先分配的訊息 ID 已存在，說明上次已寫入，不會重複發送
+func (s *Server) deliverScheduled(ctx context.Context, item *chatroom.ScheduledMessage) error {
+	content, err := s.encryption.DecryptMessageWithVersion(item.Content, item.RoomID, item.KeyVersion)
+	if err != nil {
+		return s.failScheduled(ctx, item, "定時訊息解密失敗")
+	}
+
+	req := &chat.SendMessageRequest{
+		RoomId:     item.RoomID,
+		SenderId:   item.SenderID,
+		Content:    content,
+		Type:       item.Type,
+		Metadata:   metadataToGRPC(&item.Metadata),
+		Mentions:   item.Mentions,
+		TtlSeconds: item.TTLSeconds,
+	}
+	if err := s.checkSenderMembership(ctx, req); err != nil {
+		return s.failScheduled(ctx, item, err.Error())
+	}
+
+	// 按發送時的當前密鑰重新加密
+	message, encryptedContent, err := s.buildEncryptedMessage(ctx, req, nil)
+	if err != nil {
+		return s.failScheduled(ctx, item, err.Error())
+	}
+
+	err = s.repos.Message.CreateWithID(ctx, item.MessageID, &message)
+	if mongo.IsDuplicateKeyError(err) {
+		logger.Warning(ctx, "定時訊息已發送過，跳過",
+			logger.WithRoomID(item.RoomID),
+			logger.WithMessageID(item.MessageID))
+		return s.repos.Scheduled.MarkSent(ctx, item.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.updateRoomLastMessage(ctx, req, &message)
+	s.notifyMentions(ctx, &message)
+
+	s.audit.LogMessageSent(ctx, item.SenderID, item.RoomID, message.GetID(), item.Type)
+	logger.Info(ctx, "定時訊息發送成功",
+		logger.WithUserID(item.SenderID),
+		logger.WithRoomID(item.RoomID),
+		logger.WithMessageID(message.GetID()),
+		logger.WithAction("send_scheduled_message"),
+		logger.WithDetails(map[string]interface{}{
+			"encrypted":    s.encryption.IsEncrypted(encryptedContent),
+			"scheduled_id": item.ID.Hex(),
+			"type":         item.Type,
+		}))
+
+	return s.repos.Scheduled.MarkSent(ctx, item.ID)
+}
+
+// failScheduled 標記定時訊息發送失敗
+func (s *Server) failScheduled(ctx context.Context, item *chatroom.ScheduledMessage, reason string) error {
+	logger.Warning(ctx, "定時訊息發送失敗",
+		logger.WithUserID(item.SenderID),
+		logger.WithRoomID(item.RoomID),
+		logger.WithDetails(map[string]interface{}{
+			"scheduled_id": item.ID.Hex(),
+			"reason":       reason,
+		}))
+	return s.repos.Scheduled.MarkFailed(ctx, item.ID, reason)
+}
+
+// scheduledJob 定期領取並發送到期的定時訊息
+type scheduledJob struct {
+	interval     time.Duration
+	claimTimeout time.Duration
+	now          func() time.Time
+
+	claim   func(ctx context.Context, now, staleBefore time.Time) (*chatroom.ScheduledMessage, error)
+	deliver func(ctx context.Context, item *chatroom.ScheduledMessage) error
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// newScheduledJobFromConfig 根據配置創建發送任務，未啟用時返回 nil
+func (s *Server) newScheduledJobFromConfig() *scheduledJob {
+	cfg := config.Get()
+	if cfg == nil || !cfg.Limits.Scheduled.Enabled {
+		return nil
+	}
+
+	interval := time.Duration(constants.DefaultScheduledPollIntervalSeconds) * time.Second
+	if cfg.Limits.Scheduled.PollIntervalSeconds > 0 {
+		interval = time.Duration(cfg.Limits.Scheduled.PollIntervalSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &scheduledJob{
+		interval:     interval,
+		claimTimeout: constants.ScheduledClaimTimeoutMinutes * time.Minute,
+		now:          time.Now,
+		claim:        s.repos.Scheduled.ClaimDue,
+		deliver:      s.deliverScheduled,
+		ctx:          ctx,
+		cancel:       cancel,
+		done:         make(chan struct{}),
+	}
+}
+
+// Start 立即發送一次到期訊息（包括停機期間到期的），之後定期檢查
+func (j *scheduledJob) Start() {
+	go func() {
+		defer close(j.done)
+
+		j.run()
+
+		ticker := time.NewTicker(j.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.ctx.Done():
+				return
+			case <-ticker.C:
+				j.run()
+			}
+		}
+	}()
+}
+
+// Stop 停止發送任務，等待進行中的發送完成
+func (j *scheduledJob) Stop() {
+	j.stopOnce.Do(func() {
+		j.cancel()
+		<-j.done
+	})
+}
+
+// run 逐條領取並處理到期的定時訊息，直到沒有到期訊息，返回處理數量（包括標記為失敗的）
+func (j *scheduledJob) run() int {
+	processed := 0
+	for j.ctx.Err() == nil {
+		now := j.now()
+		item, err := j.claim(j.ctx, now, now.Add(-j.claimTimeout))
+		if err != nil {
+			if j.ctx.Err() == nil {
+				logger.Error(j.ctx, "領取定時訊息失敗",
+					logger.WithAction("send_scheduled_message"),
+					logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+			}
+			return processed
+		}
+		if item == nil {
+			return processed
+		}
+
+		// 單條訊息失敗不影響其他訊息，保持領取狀態的訊息超時後會被重新領取
+		if err := j.deliver(j.ctx, item); err != nil {
+			logger.Error(j.ctx, "發送定時訊息失敗",
+				logger.WithUserID(item.SenderID),
+				logger.WithRoomID(item.RoomID),
+				logger.WithAction("send_scheduled_message"),
+				logger.WithDetails(map[string]interface{}{
+					"scheduled_id": item.ID.Hex(),
+					"error":        err.Error(),
+				}))
+			continue
+		}
+		processed++
+	}
+	return processed
+}
@@ -0,0 +1,172 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestValidateSendAt(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	maxDelay := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name    string
+		sendAt  int64
+		wantErr bool
+	}{
+		{"past", now.Unix() - 60, true},
+		{"now", now.Unix(), true},
+		{"future", now.Unix() + 60, false},
+		{"at max delay", now.Add(maxDelay).Unix(), false},
+		{"beyond max delay", now.Add(maxDelay).Unix() + 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSendAt(tt.sendAt, now, maxDelay); (err != nil) != tt.wantErr {
+				t.Errorf("validateSendAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduledRPCs_Disabled(t *testing.T) {
+	s := &Server{}
+	ctx := context.Background()
+
+	scheduleResp, err := s.ScheduleMessage(ctx, &chat.ScheduleMessageRequest{
+		Message: &chat.SendMessageRequest{RoomId: "room-1", SenderId: "user-1", Content: "hi"},
+		SendAt:  time.Now().Add(time.Hour).Unix(),
+	})
+	if err != nil || scheduleResp.Success {
+		t.Errorf("ScheduleMessage() = %v, %v; want failure response when disabled", scheduleResp, err)
+	}
+
+	listResp, err := s.ListScheduledMessages(ctx, &chat.ListScheduledMessagesRequest{UserId: "user-1"})
+	if err != nil || listResp.Success {
+		t.Errorf("ListScheduledMessages() = %v, %v; want failure response when disabled", listResp, err)
+	}
+
+	cancelResp, err := s.CancelScheduledMessage(ctx, &chat.CancelScheduledMessageRequest{Id: "x", UserId: "user-1"})
+	if err != nil || cancelResp.Success {
+		t.Errorf("CancelScheduledMessage() = %v, %v; want failure response when disabled", cancelResp, err)
+	}
+}
+
+func TestScheduledRPCs_RejectImpersonatedUser(t *testing.T) {
+	// 認證用戶借用其他用戶的 ID 查看或取消定時訊息：應直接被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+
+	listResp, err := s.ListScheduledMessages(ctx, &chat.ListScheduledMessagesRequest{UserId: "user-1"})
+	if err != nil || listResp.Success {
+		t.Errorf("ListScheduledMessages() = %v, %v; want failure response", listResp, err)
+	}
+
+	cancelResp, err := s.CancelScheduledMessage(ctx, &chat.CancelScheduledMessageRequest{Id: "x", UserId: "user-1"})
+	if err != nil || cancelResp.Success {
+		t.Errorf("CancelScheduledMessage() = %v, %v; want failure response", cancelResp, err)
+	}
+}
+
+func TestScheduledToGRPC(t *testing.T) {
+	item := &chatroom.ScheduledMessage{
+		ID:        bson.NewObjectID(),
+		RoomID:    "room-1",
+		SenderID:  "user-1",
+		Content:   "encrypted",
+		Type:      messageTypeText,
+		SendAt:    time.Unix(1700000600, 0),
+		Status:    chatroom.ScheduledStatusPending,
+		MessageID: bson.NewObjectID().Hex(),
+		CreatedAt: time.Unix(1700000000, 0),
+	}
+
+	got := scheduledToGRPC(item, "hello")
+	if got.Id != item.ID.Hex() || got.Content != "hello" || got.SendAt != 1700000600 || got.MessageId != item.MessageID {
+		t.Errorf("scheduledToGRPC() = %+v", got)
+	}
+	if got.Metadata != nil {
+		t.Errorf("Metadata = %v, want nil for a text message", got.Metadata)
+	}
+}
+
+func TestScheduledJob_Run(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	queue := []*chatroom.ScheduledMessage{
+		{ID: bson.NewObjectID(), RoomID: "room-1"},
+		{ID: bson.NewObjectID(), RoomID: "room-2"},
+		{ID: bson.NewObjectID(), RoomID: "room-3"},
+	}
+
+	var staleBefore time.Time
+	var delivered []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	job := &scheduledJob{
+		claimTimeout: 5 * time.Minute,
+		now:          func() time.Time { return now },
+		claim: func(_ context.Context, _, stale time.Time) (*chatroom.ScheduledMessage, error) {
+			staleBefore = stale
+			if len(queue) == 0 {
+				return nil, nil
+			}
+			item := queue[0]
+			queue = queue[1:]
+			return item, nil
+		},
+		deliver: func(_ context.Context, item *chatroom.ScheduledMessage) error {
+			delivered = append(delivered, item.RoomID)
+			if item.RoomID == "room-2" {
+				return errors.New("temporary failure")
+			}
+			return nil
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	// 單條失敗不中斷，繼續處理後續訊息
+	if got := job.run(); got != 2 {
+		t.Errorf("run() = %d, want 2", got)
+	}
+	if len(delivered) != 3 {
+		t.Errorf("delivered = %v, want all three rooms", delivered)
+	}
+	if want := now.Add(-5 * time.Minute); !staleBefore.Equal(want) {
+		t.Errorf("staleBefore = %v, want %v", staleBefore, want)
+	}
+}
+
+func TestScheduledJob_RunStopsOnClaimError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	job := &scheduledJob{
+		now: time.Now,
+		claim: func(context.Context, time.Time, time.Time) (*chatroom.ScheduledMessage, error) {
+			calls++
+			return nil, errors.New("database unavailable")
+		},
+		deliver: func(context.Context, *chatroom.ScheduledMessage) error {
+			t.Fatal("deliver should not be called")
+			return nil
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	if got := job.run(); got != 0 || calls != 1 {
+		t.Errorf("run() = %d with %d claims, want 0 with 1 claim", got, calls)
+	}
+}
@@ -0,0 +1,92 @@
+package grpc
+
+import (
+	"sort"
+	"sync"
+
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+// seenMessageSet 有界的已推送訊息 ID 集合
+// 按插入順序淘汰最舊的 ID，避免長時間連線的訊息流無限增長
+// 雙向聊天流中確認與推送在不同 goroutine，因此需要加鎖
+type seenMessageSet struct {
+	mu       sync.Mutex
+	ids      map[string]struct{}
+	order    []string // 環形緩衝區，記錄插入順序
+	next     int      // 下一個寫入位置
+	capacity int
+}
+
+// newSeenMessageSet 創建已推送訊息集合
+func newSeenMessageSet(capacity int) *seenMessageSet {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &seenMessageSet{
+		ids:      make(map[string]struct{}, capacity),
+		order:    make([]string, 0, capacity),
+		capacity: capacity,
+	}
+}
+
+// Has 檢查訊息是否已推送
+func (s *seenMessageSet) Has(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.ids[id]
+	return ok
+}
+
+// Add 記錄已推送的訊息，超出容量時淘汰最舊的 ID
+func (s *seenMessageSet) Add(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ids[id]; ok {
+		return
+	}
+
+	if len(s.order) < s.capacity {
+		s.order = append(s.order, id)
+	} else {
+		delete(s.ids, s.order[s.next])
+		s.order[s.next] = id
+		s.next = (s.next + 1) % s.capacity
+	}
+	s.ids[id] = struct{}{}
+}
+
+// Len 當前記錄的訊息數量
+func (s *seenMessageSet) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.ids)
+}
+
+// collectNewMessages 過濾出未推送的訊息並按時間升序排列
+// GetByRoomID 返回的是降序結果，直接推送會導致同一批內新訊息在前
+func collectNewMessages(messages []*chatroom.Message, seen *seenMessageSet) []*chatroom.Message {
+	newMessages := make([]*chatroom.Message, 0, len(messages))
+	for _, msg := range messages {
+		if seen.Has(msg.GetID()) {
+			continue
+		}
+		newMessages = append(newMessages, msg)
+	}
+
+	sort.SliceStable(newMessages, func(i, j int) bool {
+		if !newMessages[i].CreatedAt.Equal(newMessages[j].CreatedAt) {
+			return newMessages[i].CreatedAt.Before(newMessages[j].CreatedAt)
+		}
+		return newMessages[i].GetID() < newMessages[j].GetID()
+	})
+
+	for _, msg := range newMessages {
+		seen.Add(msg.GetID())
+	}
+
+	return newMessages
+}
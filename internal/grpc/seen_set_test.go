@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+// newTestMessage 創建測試用訊息
+func newTestMessage(id string, createdAt time.Time) *chatroom.Message {
+	return &chatroom.Message{ID: id, CreatedAt: createdAt}
+}
+
+func TestCollectNewMessages_ChronologicalOrder(t *testing.T) {
+	base := time.Now()
+	seen := newSeenMessageSet(100)
+	seen.Add("m1")
+
+	// GetByRoomID 返回降序結果
+	messages := []*chatroom.Message{
+		newTestMessage("m4", base.Add(3*time.Second)),
+		newTestMessage("m3", base.Add(2*time.Second)),
+		newTestMessage("m2", base.Add(1*time.Second)),
+		newTestMessage("m1", base),
+	}
+
+	newMessages := collectNewMessages(messages, seen)
+
+	want := []string{"m2", "m3", "m4"}
+	if len(newMessages) != len(want) {
+		t.Fatalf("Expected %d new messages, got %d", len(want), len(newMessages))
+	}
+	for i, msg := range newMessages {
+		if msg.ID != want[i] {
+			t.Errorf("Position %d: want %s, got %s", i, want[i], msg.ID)
+		}
+	}
+
+	// 再次處理同一批訊息不應重複推送
+	if again := collectNewMessages(messages, seen); len(again) != 0 {
+		t.Errorf("Expected no new messages on second pass, got %d", len(again))
+	}
+}
+
+func TestCollectNewMessages_SameTimestamp(t *testing.T) {
+	ts := time.Now()
+	seen := newSeenMessageSet(10)
+
+	messages := []*chatroom.Message{
+		newTestMessage("b", ts),
+		newTestMessage("c", ts),
+		newTestMessage("a", ts),
+	}
+
+	newMessages := collectNewMessages(messages, seen)
+	for i, want := range []string{"a", "b", "c"} {
+		if newMessages[i].ID != want {
+			t.Errorf("Position %d: want %s, got %s", i, want, newMessages[i].ID)
+		}
+	}
+}
+
+func TestSeenMessageSet_Bounded(t *testing.T) {
+	const capacity = 50
+	seen := newSeenMessageSet(capacity)
+
+	for i := 0; i < capacity*10; i++ {
+		seen.Add(fmt.Sprintf("msg-%d", i))
+		if seen.Len() > capacity {
+			t.Fatalf("Seen set grew beyond capacity: %d > %d", seen.Len(), capacity)
+		}
+	}
+
+	if seen.Len() != capacity {
+		t.Errorf("Expected %d entries, got %d", capacity, seen.Len())
+	}
+
+	// 最舊的 ID 被淘汰，最新的保留
+	if seen.Has("msg-0") {
+		t.Error("Oldest entry should have been evicted")
+	}
+	if !seen.Has(fmt.Sprintf("msg-%d", capacity*10-1)) {
+		t.Error("Newest entry should be retained")
+	}
+	if !seen.Has(fmt.Sprintf("msg-%d", capacity*9)) {
+		t.Error("Entries within the capacity window should be retained")
+	}
+}
+
+func TestSeenMessageSet_DuplicateAdd(t *testing.T) {
+	seen := newSeenMessageSet(3)
+	seen.Add("a")
+	seen.Add("a")
+	seen.Add("b")
+	seen.Add("c")
+
+	// 重複添加不佔用容量
+	if !seen.Has("a") || seen.Len() != 3 {
+		t.Errorf("Duplicate add should not consume capacity, len=%d", seen.Len())
+	}
+}
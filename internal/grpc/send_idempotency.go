@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/proto/chat"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// idempotencyWindow 發送冪等鍵的有效期
+func idempotencyWindow() time.Duration {
+	seconds := constants.DefaultIdempotencyWindowSeconds
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Message.IdempotencyWindowSeconds > 0 {
+		seconds = cfg.Limits.Message.IdempotencyWindowSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// idempotencyKeyExpired 使用冪等鍵的訊息是否已超過有效期
+func idempotencyKeyExpired(createdAt, now time.Time, window time.Duration) bool {
+	return now.Sub(createdAt) > window
+}
+
+// replayIdempotentSend 冪等鍵已被同一發送者使用時返回第一次發送的訊息，否則返回 nil 繼續創建
+// 超過有效期的鍵會從舊訊息上移除，之後創建新訊息；重放的請求不會再次更新最後訊息、通知提及或寫入審計日誌
+func (s *Server) replayIdempotentSend(ctx context.Context, req *chat.SendMessageRequest) *chat.SendMessageResponse {
+	if req.IdempotencyKey == "" {
+		return nil
+	}
+
+	existing, err := s.repos.Message.GetByIdempotencyKey(ctx, req.SenderId, req.IdempotencyKey)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			logErrorWithUserAndRoom(ctx, "查詢冪等鍵失敗", req.SenderId, req.RoomId, err)
+		}
+		return nil
+	}
+
+	if idempotencyKeyExpired(existing.CreatedAt, time.Now(), idempotencyWindow()) {
+		if err := s.repos.Message.ReleaseIdempotencyKey(ctx, existing.GetID(), req.IdempotencyKey); err != nil {
+			logErrorWithUserAndRoom(ctx, "釋放過期的冪等鍵失敗", req.SenderId, req.RoomId, err)
+		}
+		return nil
+	}
+	if existing.RoomID != req.RoomId {
+		return &chat.SendMessageResponse{Success: false, Message: "冪等鍵已用於其他聊天室的訊息"}
+	}
+
+	logger.Info(ctx, "重複的發送請求，返回已創建的訊息",
+		logger.WithUserID(req.SenderId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithMessageID(existing.GetID()),
+		logger.WithAction("send_message_replay"))
+
+	return &chat.SendMessageResponse{
+		Success:     true,
+		Message:     "消息發送成功",
+		ChatMessage: s.buildMessageResponse(ctx, existing),
+		Replayed:    true,
+	}
+}
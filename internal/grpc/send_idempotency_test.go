@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/proto/chat"
+)
+
+func TestIdempotencyWindow_Default(t *testing.T) {
+	if got, want := idempotencyWindow(), time.Duration(constants.DefaultIdempotencyWindowSeconds)*time.Second; got != want {
+		t.Errorf("idempotencyWindow() = %v, want %v", got, want)
+	}
+}
+
+func TestIdempotencyKeyExpired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	window := time.Hour
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		want      bool
+	}{
+		{"just sent", now.Add(-time.Second), false},
+		{"at window edge", now.Add(-window), false},
+		{"beyond window", now.Add(-window - time.Second), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := idempotencyKeyExpired(tt.createdAt, now, window); got != tt.want {
+				t.Errorf("idempotencyKeyExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplayIdempotentSend_NoKey(t *testing.T) {
+	s := &Server{}
+	if resp := s.replayIdempotentSend(context.Background(), &chat.SendMessageRequest{RoomId: "room", SenderId: "user_alice"}); resp != nil {
+		t.Errorf("replayIdempotentSend() = %v, want nil without an idempotency key", resp)
+	}
+}
@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/proto/chat"
+)
+
+// membershipKey 成員資格緩存的鍵
+type membershipKey struct {
+	roomID string
+	userID string
+}
+
+// membershipCache 短時間緩存「是成員」的查詢結果，避免熱門聊天室每條訊息都查詢資料庫
+// 只緩存正向結果：剛加入的用戶可以立即發言，離開聊天室時主動失效
+type membershipCache struct {
+	mu         sync.Mutex
+	entries    map[membershipKey]time.Time // 過期時間
+	isMember   func(ctx context.Context, roomID, userID string) (bool, error)
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+// newMembershipCache 創建成員資格緩存
+func newMembershipCache(isMember func(ctx context.Context, roomID, userID string) (bool, error), ttl time.Duration, maxEntries int) *membershipCache {
+	return &membershipCache{
+		entries:    make(map[membershipKey]time.Time),
+		isMember:   isMember,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// IsMember 檢查用戶是否是聊天室成員，優先使用未過期的緩存
+func (c *membershipCache) IsMember(ctx context.Context, roomID, userID string) (bool, error) {
+	key := membershipKey{roomID: roomID, userID: userID}
+
+	c.mu.Lock()
+	expiresAt, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(expiresAt) {
+		return true, nil
+	}
+
+	isMember, err := c.isMember(ctx, roomID, userID)
+	if err != nil || !isMember {
+		c.Invalidate(roomID, userID)
+		return isMember, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.maxEntries {
+		c.sweepUnsafe()
+	}
+	if len(c.entries) < c.maxEntries {
+		c.entries[key] = c.now().Add(c.ttl)
+	}
+	return true, nil
+}
+
+// Invalidate 失效指定用戶在聊天室的緩存（離開或被移出聊天室時調用）
+func (c *membershipCache) Invalidate(roomID, userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, membershipKey{roomID: roomID, userID: userID})
+}
+
+// sweepUnsafe 清理過期條目（調用者需持有鎖）
+func (c *membershipCache) sweepUnsafe() {
+	now := c.now()
+	for key, expiresAt := range c.entries {
+		if !now.Before(expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// checkSenderMembership 發送訊息前檢查發送者是否是聊天室成員
+// 啟用 JWT 時發送者必須是已認證的用戶，避免非成員冒充成員發言
+func (s *Server) checkSenderMembership(ctx context.Context, req *chat.SendMessageRequest) error {
+	if !actingAsAuthenticatedUser(ctx, req.SenderId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "send message: sender is not the authenticated user")
+		return fmt.Errorf("發送者與已認證的用戶不一致")
+	}
+
+	isMember, err := s.senderMembership.IsMember(ctx, req.RoomId, req.SenderId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "檢查發送者成員資格失敗", req.SenderId, req.RoomId, err)
+		return fmt.Errorf("檢查成員資格失敗")
+	}
+	if !isMember {
+		s.audit.LogAccessDenied(ctx, req.SenderId, req.RoomId, "send message: not a room member")
+		return fmt.Errorf("您不是此聊天室的成員")
+	}
+	return nil
+}
+
+// newSenderMembershipCache 創建發送訊息使用的成員資格緩存
+func (s *Server) newSenderMembershipCache() *membershipCache {
+	return newMembershipCache(s.repos.ChatRoom.IsMember,
+		time.Duration(constants.SenderMembershipCacheTTLSec)*time.Second,
+		constants.SenderMembershipCacheMaxEntries)
+}
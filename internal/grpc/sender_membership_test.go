@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/proto/chat"
+)
+
+func newTestMembershipCache(f *fakeMembership, now *time.Time) *membershipCache {
+	c := newMembershipCache(f.isMember, 5*time.Second, 2)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestMembershipCache_CachesMembers(t *testing.T) {
+	now := time.Now()
+	f := &fakeMembership{member: true}
+	c := newTestMembershipCache(f, &now)
+
+	for i := 0; i < 3; i++ {
+		if ok, err := c.IsMember(context.Background(), "room-1", "user-1"); !ok || err != nil {
+			t.Fatalf("IsMember() = %v, %v, want true, nil", ok, err)
+		}
+	}
+	if f.calls != 1 {
+		t.Errorf("lookups = %d, want 1 within TTL", f.calls)
+	}
+
+	now = now.Add(5 * time.Second)
+	if _, err := c.IsMember(context.Background(), "room-1", "user-1"); err != nil {
+		t.Fatalf("IsMember() error = %v", err)
+	}
+	if f.calls != 2 {
+		t.Errorf("lookups = %d, want 2 after TTL", f.calls)
+	}
+}
+
+func TestMembershipCache_DoesNotCacheNonMembers(t *testing.T) {
+	now := time.Now()
+	f := &fakeMembership{member: false}
+	c := newTestMembershipCache(f, &now)
+
+	if ok, _ := c.IsMember(context.Background(), "room-1", "user-1"); ok {
+		t.Fatal("expected non-member")
+	}
+
+	// 剛加入的用戶應該立即可以發言
+	f.member = true
+	if ok, _ := c.IsMember(context.Background(), "room-1", "user-1"); !ok {
+		t.Error("expected member after joining")
+	}
+}
+
+func TestMembershipCache_Invalidate(t *testing.T) {
+	now := time.Now()
+	f := &fakeMembership{member: true}
+	c := newTestMembershipCache(f, &now)
+
+	if _, err := c.IsMember(context.Background(), "room-1", "user-1"); err != nil {
+		t.Fatalf("IsMember() error = %v", err)
+	}
+
+	f.member = false
+	c.Invalidate("room-1", "user-1")
+	if ok, _ := c.IsMember(context.Background(), "room-1", "user-1"); ok {
+		t.Error("expected non-member after invalidation")
+	}
+}
+
+func TestMembershipCache_LookupError(t *testing.T) {
+	now := time.Now()
+	f := &fakeMembership{err: errors.New("db down")}
+	c := newTestMembershipCache(f, &now)
+
+	if ok, err := c.IsMember(context.Background(), "room-1", "user-1"); ok || err == nil {
+		t.Errorf("IsMember() = %v, %v, want false with error", ok, err)
+	}
+}
+
+func TestMembershipCache_BoundedSize(t *testing.T) {
+	now := time.Now()
+	f := &fakeMembership{member: true}
+	c := newTestMembershipCache(f, &now)
+
+	for _, userID := range []string{"user-1", "user-2", "user-3"} {
+		if ok, err := c.IsMember(context.Background(), "room-1", userID); !ok || err != nil {
+			t.Fatalf("IsMember(%s) = %v, %v", userID, ok, err)
+		}
+	}
+	if len(c.entries) != 2 {
+		t.Errorf("entries = %d, want capped at 2", len(c.entries))
+	}
+
+	// 過期條目在容量滿時被清理
+	now = now.Add(5 * time.Second)
+	if _, err := c.IsMember(context.Background(), "room-1", "user-4"); err != nil {
+		t.Fatalf("IsMember() error = %v", err)
+	}
+	if len(c.entries) != 1 {
+		t.Errorf("entries = %d, want 1 after sweeping expired entries", len(c.entries))
+	}
+}
+
+func TestCheckSenderMembership_RejectsImpersonatedSender(t *testing.T) {
+	// 認證用戶冒充其他成員發言：應在查詢成員資格之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	err := s.checkSenderMembership(ctx, &chat.SendMessageRequest{
+		RoomId:   "507f1f77bcf86cd799439011",
+		SenderId: "alice",
+	})
+	if err == nil {
+		t.Error("checkSenderMembership() = nil, want error for impersonated sender")
+	}
+}
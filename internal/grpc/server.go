@@ -4,14 +4,21 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
+	"chat-gateway/internal/constants"
 	"chat-gateway/internal/platform/config"
+	apphealth "chat-gateway/internal/platform/health"
 	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
 	"chat-gateway/internal/security/audit"
 	"chat-gateway/internal/security/encryption"
 	"chat-gateway/internal/security/keymanager"
@@ -19,8 +26,14 @@ import (
 	"chat-gateway/internal/storage/database/chatroom"
 	"chat-gateway/proto/chat"
 
+	"go.mongodb.org/mongo-driver/v2/mongo"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -28,7 +41,10 @@ const (
 	messageFormatErrorText = "[訊息格式錯誤]"
 	messageText            = "[訊息]"
 	decryptFailedText      = "[解密失敗]"
+	expiringMessageText    = "[限時訊息]"
 	roomTypeDirect         = "direct"
+	roomTypeGroup          = "group"
+	roleAdmin              = "admin"
 )
 
 // Server gRPC 服務器
@@ -38,6 +54,51 @@ type Server struct {
 	repos      *database.Repositories
 	encryption *encryption.MessageEncryption
 	audit      *audit.AuditService
+	keyManager *keymanager.KeyManagerWithPersistence
+
+	readReceipts     *readReceiptBatcher  // 已讀回執批量寫入（未啟用時為 nil）
+	retention        *retentionJob        // 過期訊息清理（未啟用時為 nil）
+	scheduled        *scheduledJob        // 定時訊息發送（未啟用時為 nil）
+	roomCreations    *roomCreationLimiter // 每個擁有者的 CreateRoom 並發與頻率限制
+	hub              *roomHub             // 每個聊天室共用的新訊息輪詢與分發
+	senderMembership *membershipCache     // 發送訊息前的成員資格檢查緩存
+	messageCounts    *messageCountCache   // 聊天室訊息數量緩存
+	presence         *presenceTracker     // 成員最後在線時間（節流寫入）
+	health           *healthReporter      // 標準 gRPC 健康服務（grpc.health.v1）
+
+	reencrypting sync.Map // roomID -> struct{}，正在重新加密的聊天室
+
+	ready atomic.Bool // 已開始監聽端口（用於就緒探針）
+}
+
+// cleanReadReceipts 清理和去重已讀回執，保留每個用戶最早的已讀時間，並排除發送者本人
+func cleanReadReceipts(readByList []chatroom.MessageReadBy, senderID string) []*chat.ReadReceipt {
+	index := make(map[string]int)
+	result := []*chat.ReadReceipt{}
+
+	for _, item := range readByList {
+		// 跳過發送者本人
+		if item.UserID == senderID {
+			continue
+		}
+
+		var readAt int64
+		if !item.ReadAt.IsZero() {
+			readAt = item.ReadAt.Unix()
+		}
+
+		// 去重
+		if i, ok := index[item.UserID]; ok {
+			if readAt != 0 && (result[i].ReadAt == 0 || readAt < result[i].ReadAt) {
+				result[i].ReadAt = readAt
+			}
+			continue
+		}
+		index[item.UserID] = len(result)
+		result = append(result, &chat.ReadReceipt{UserId: item.UserID, ReadAt: readAt})
+	}
+
+	return result
 }
 
 // cleanReadBy 清理和去重 read_by 列表
@@ -61,7 +122,24 @@ func cleanReadBy(readByList []chatroom.MessageReadBy, senderID string) []string
 	return result
 }
 
+// cleanDeliveredTo 清理和去重 delivered_to 列表（排除發送者本人）
+func cleanDeliveredTo(deliveredToList []chatroom.MessageDeliveredTo, senderID string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+
+	for _, item := range deliveredToList {
+		if item.UserID == senderID || seen[item.UserID] {
+			continue
+		}
+		seen[item.UserID] = true
+		result = append(result, item.UserID)
+	}
+
+	return result
+}
+
 // isValidUTF8 檢查字符串是否是有效的 UTF-8 編碼
+// 發送時已拒絕無效的 UTF-8，讀取時仍保留檢查，用於此前寫入的舊資料
 func isValidUTF8(s string) bool {
 	return utf8.ValidString(s)
 }
@@ -73,32 +151,94 @@ func NewServer(
 	keyManager *keymanager.KeyManagerWithPersistence,
 	tlsConfig config.TLSConfig,
 ) (*Server, error) {
-	var grpcServer *grpc.Server
 	ctx := context.Background()
 
+	// 日誌攔截器放在最前面：生成 trace ID、記錄耗時與狀態碼、把 panic 轉為 Internal 錯誤
+	// JWT 驗證追加在後面，被拒絕的請求也會被記錄
+	jwt := middleware.NewJWTMiddlewareFromConfig()
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(middleware.GRPCLoggingUnaryInterceptor(), jwt.GRPCUnaryInterceptor()),
+		grpc.ChainStreamInterceptor(middleware.GRPCLoggingStreamInterceptor(), jwt.GRPCStreamInterceptor()),
+		grpc.KeepaliveEnforcementPolicy(keepaliveEnforcementPolicy()),
+	}
+
 	// 根據 TLS 配置決定是否啟用 TLS
 	if tlsConfig.Enabled {
 		tlsCreds, err := loadTLSCredentials(tlsConfig)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load TLS credentials: %w", err)
 		}
-		grpcServer = grpc.NewServer(grpc.Creds(tlsCreds))
+		opts = append(opts, grpc.Creds(tlsCreds))
 		logger.Info(ctx, "gRPC TLS 已啟用")
 	} else {
-		grpcServer = grpc.NewServer()
 		logger.Info(ctx, "gRPC 以非加密模式運行（開發環境）")
 	}
+	grpcServer := grpc.NewServer(opts...)
 
 	server := &Server{
 		grpcServer: grpcServer,
 		repos:      repos,
 		encryption: encryption.NewMessageEncryption(encryptionEnabled, keyManager),
 		audit:      audit.NewAuditService(auditEnabled),
+		keyManager: keyManager,
+
+		roomCreations: newRoomCreationLimiterFromConfig(),
+	}
+	initialFetchLimit, seenSetSize := streamSeenSetLimits()
+	server.hub = newRoomHub(server.fetchLatestMessages, streamPollInterval(), initialFetchLimit, seenSetSize)
+	if repos != nil {
+		server.senderMembership = server.newSenderMembershipCache()
+		server.messageCounts = server.newRoomMessageCountCache()
+		server.presence = newPresenceTracker(repos.ChatRoom.TouchMemberLastSeen, presenceUpdateInterval())
+	}
+
+	// 密鑰輪換後在背景重新加密歷史訊息（可選）
+	if cfg := config.Get(); encryptionEnabled && keyManager != nil && cfg != nil && cfg.Security.Encryption.ReencryptOnRotation {
+		keyManager.OnKeyRotated(server.scheduleReencryption)
+		logger.Info(ctx, "已啟用密鑰輪換後的歷史訊息重新加密")
+	}
+
+	// 已讀回執批量寫入（可選）
+	if repos != nil {
+		if batcher := newReadReceiptBatcherFromConfig(server.writeReadReceipts); batcher != nil {
+			server.readReceipts = batcher
+			batcher.Start()
+			logger.Info(ctx, "已啟用已讀回執批量寫入")
+		}
+	}
+
+	// 過期訊息清理（可選）
+	if repos != nil {
+		if job := newRetentionJobFromConfig(repos); job != nil {
+			server.retention = job
+			job.Start()
+			logger.Info(ctx, "已啟用過期訊息清理")
+		}
+	}
+
+	// 定時訊息發送（可選）
+	if repos != nil {
+		if job := server.newScheduledJobFromConfig(); job != nil {
+			server.scheduled = job
+			job.Start()
+			logger.Info(ctx, "已啟用定時訊息發送")
+		}
 	}
 
 	// 註冊服務
 	chat.RegisterChatRoomServiceServer(grpcServer, server)
 
+	// 標準 gRPC 健康服務，與 HTTP 健康檢查共用資料庫檢查
+	server.health = newHealthReporter(apphealth.CheckDatabase, healthCheckInterval)
+	healthpb.RegisterHealthServer(grpcServer, server.health.server)
+	server.health.Start()
+
+	// gRPC reflection 會暴露完整的服務定義，只在配置明確開啟時註冊（開發環境）
+	if cfg := config.Get(); cfg != nil && cfg.GRPC.ReflectionEnabled {
+		reflection.Register(grpcServer)
+		logger.Warning(ctx, "已啟用 gRPC reflection，生產環境請關閉")
+	}
+
 	logger.Infof(ctx, "gRPC 服務器初始化 - 加密: %v, 審計: %v, TLS: %v", encryptionEnabled, auditEnabled, tlsConfig.Enabled)
 
 	// 如果啟用加密，記錄密鑰管理器狀態
@@ -158,30 +298,103 @@ func (s *Server) Start(port string) error {
 
 	ctx := context.Background()
 	logger.Infof(ctx, "gRPC 服務器啟動在端口 %s", port)
+	s.ready.Store(true)
+	defer s.ready.Store(false)
 	return s.grpcServer.Serve(lis)
 }
 
+// Ready gRPC 服務器是否已開始接受連接
+func (s *Server) Ready() bool {
+	return s.ready.Load()
+}
+
 // Stop 停止 gRPC 服務器
 func (s *Server) Stop() {
+	s.ready.Store(false)
+
+	// 先回報 NOT_SERVING，讓負載均衡器停止分配新請求
+	s.health.Stop()
+
+	// 先關閉訊息分發，讓長連接的訊息流結束，GracefulStop 才不會一直等待
+	s.hub.Close()
 	s.grpcServer.GracefulStop()
+
+	if s.retention != nil {
+		s.retention.Stop()
+	}
+	if s.scheduled != nil {
+		s.scheduled.Stop()
+	}
+
+	// 服務停止前寫入剩餘的已讀回執
+	if s.readReceipts != nil {
+		s.readReceipts.Close(context.Background())
+	}
+
+	// 記錄密鑰緩存命中情況，用於調整啟動時預加載的聊天室數量
+	if s.keyManager != nil {
+		stats := s.keyManager.Stats()
+		logger.Infof(context.Background(), "密鑰緩存統計 - 命中: %d, 未命中: %d, 已緩存密鑰: %d",
+			stats.CacheHits, stats.CacheMisses, stats.TotalKeys)
+	}
 }
 
 // CreateRoom 創建聊天室
 func (s *Server) CreateRoom(ctx context.Context, req *chat.CreateRoomRequest) (*chat.CreateRoomResponse, error) {
-	// 如果是私聊，檢查是否已經存在相同的私聊聊天室
-	if req.Type == roomTypeDirect && len(req.MemberIds) == 2 {
-		if existingRoom := s.findExistingDirectChat(ctx, req.OwnerId, req.MemberIds); existingRoom != nil {
-			logger.Infof(ctx, "找到重複的私聊聊天室: %s", existingRoom.ID)
+	if s.rejectImpersonation(ctx, req.OwnerId, "", "create room") {
+		return &chat.CreateRoomResponse{
+			Success: false,
+			Message: "只能以自己的身份創建聊天室",
+		}, nil
+	}
+
+	// 限制同一擁有者的並發與短時間創建次數
+	release, ok := s.roomCreations.Acquire(req.OwnerId)
+	if !ok {
+		s.audit.LogSuspiciousActivity(ctx, req.OwnerId, "", "room_creation_burst", "短時間內大量創建聊天室")
+		logger.Warning(ctx, "創建聊天室過於頻繁",
+			logger.WithUserID(req.OwnerId),
+			logger.WithAction("create_room"))
+		return nil, status.Error(codes.ResourceExhausted, "創建聊天室過於頻繁，請稍後再試")
+	}
+	defer release()
+
+	// 驗證設置（與 HTTP 層使用相同的規則，直接調用 gRPC 的客戶端同樣受限）
+	settings, err := middleware.ValidateRoomSettings(req.Type, req.Settings)
+	if err != nil {
+		return &chat.CreateRoomResponse{
+			Success: false,
+			Message: "無效的聊天室設置: " + err.Error(),
+		}, nil
+	}
+	req.Settings = settings
+
+	// 檢查聊天室名稱唯一性（私聊不受限制）
+	if req.Type != roomTypeDirect {
+		exists, err := s.repos.ChatRoom.NameExists(ctx, roomNameUniqueness(), req.Name, req.OwnerId)
+		if err != nil {
+			logErrorWithUser(ctx, "檢查聊天室名稱失敗", req.OwnerId, err)
+			return &chat.CreateRoomResponse{
+				Success: false,
+				Message: "創建聊天室失敗: " + err.Error(),
+			}, nil
+		}
+		if exists {
 			return &chat.CreateRoomResponse{
-				Success: true,
-				Message: "聊天室已存在",
-				Room:    convertRoomToGRPC(existingRoom),
+				Success: false,
+				Message: "聊天室名稱已存在",
 			}, nil
 		}
 	}
 
 	// 確保創建者在成員列表中（如果不在，自動加入）
 	memberIds := ensureOwnerInMembers(req.OwnerId, req.MemberIds)
+	if len(memberIds) > int(settings.MaxMembers) {
+		return &chat.CreateRoomResponse{
+			Success: false,
+			Message: fmt.Sprintf("成員數量超過限制 (%d)", settings.MaxMembers),
+		}, nil
+	}
 
 	// 創建房間成員（所有人都是 member，沒有管理員）
 	members := createRoomMembers(memberIds)
@@ -199,13 +412,28 @@ func (s *Server) CreateRoom(ctx context.Context, req *chat.CreateRoomRequest) (*
 			AllowPinMessages:    req.Settings.AllowPinMessages,
 			MaxMembers:          int(req.Settings.MaxMembers),
 			WelcomeMessage:      req.Settings.WelcomeMessage,
+			Locale:              req.Settings.Locale,
 		},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
+	// 一對一私聊依賴 direct_key 唯一索引去重，相同兩個成員的私聊已存在時直接返回
+	if req.Type == roomTypeDirect {
+		room.DirectKey = chatroom.DirectKey(memberIds)
+	}
+
 	// 保存到數據庫
-	err := s.repos.ChatRoom.Create(ctx, room)
+	if room.DirectKey != "" {
+		var existingRoom *chatroom.ChatRoom
+		var created bool
+		existingRoom, created, err = s.repos.ChatRoom.CreateDirect(ctx, room)
+		if err == nil && !created {
+			return s.existingDirectRoomResponse(ctx, req.OwnerId, existingRoom), nil
+		}
+	} else {
+		err = s.repos.ChatRoom.Create(ctx, room)
+	}
 	if err != nil {
 		logger.Errorf(ctx, "創建聊天室失敗: %v", err)
 		return &chat.CreateRoomResponse{
@@ -216,6 +444,7 @@ func (s *Server) CreateRoom(ctx context.Context, req *chat.CreateRoomRequest) (*
 
 	// 審計日誌
 	s.audit.LogRoomCreation(ctx, req.OwnerId, room.ID, req.Type)
+	s.postWelcomeMessage(ctx, room.ID, room.Type, room.Settings.WelcomeMessage)
 
 	logger.Info(ctx, "創建聊天室成功",
 		logger.WithRoomID(room.ID),
@@ -239,9 +468,11 @@ func (s *Server) CreateRoom(ctx context.Context, req *chat.CreateRoomRequest) (*
 			AllowPinMessages:    room.Settings.AllowPinMessages,
 			MaxMembers:          int32(room.Settings.MaxMembers), // #nosec G115 -- MaxMembers is from DB
 			WelcomeMessage:      room.Settings.WelcomeMessage,
+			Locale:              room.Settings.Locale,
 		},
 		CreatedAt: room.CreatedAt.Unix(),
 		UpdatedAt: room.UpdatedAt.Unix(),
+		Version:   room.Version,
 	}
 
 	// 添加成員信息
@@ -256,6 +487,13 @@ func (s *Server) CreateRoom(ctx context.Context, req *chat.CreateRoomRequest) (*
 
 // JoinRoom 加入聊天室
 func (s *Server) JoinRoom(ctx context.Context, req *chat.JoinRoomRequest) (*chat.JoinRoomResponse, error) {
+	if s.rejectImpersonation(ctx, req.UserId, req.RoomId, "join room") {
+		return &chat.JoinRoomResponse{
+			Success: false,
+			Message: "只能讓自己加入聊天室",
+		}, nil
+	}
+
 	// 檢查成員是否已存在
 	isMember, err := s.repos.ChatRoom.IsMember(ctx, req.RoomId, req.UserId)
 	if err != nil {
@@ -282,8 +520,21 @@ func (s *Server) JoinRoom(ctx context.Context, req *chat.JoinRoomRequest) (*chat
 		Role:   "member",
 	}
 
-	// 添加成員到聊天室
-	err = s.repos.ChatRoom.AddMember(ctx, req.RoomId, member)
+	// 添加成員到聊天室（成員數量檢查和添加是原子操作）
+	err = s.repos.ChatRoom.AddMemberWithLimit(ctx, req.RoomId, member, maxRoomMembers())
+	if errors.Is(err, chatroom.ErrAlreadyMember) {
+		return &chat.JoinRoomResponse{
+			Success: true,
+			Message: "用戶已經是聊天室成員",
+		}, nil
+	}
+	if errors.Is(err, chatroom.ErrRoomFull) {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "join room: room is full")
+		return &chat.JoinRoomResponse{
+			Success: false,
+			Message: "聊天室成員已滿",
+		}, nil
+	}
 	if err != nil {
 		logErrorWithUserAndRoom(ctx, "加入聊天室失敗", req.UserId, req.RoomId, err)
 		return &chat.JoinRoomResponse{
@@ -293,7 +544,9 @@ func (s *Server) JoinRoom(ctx context.Context, req *chat.JoinRoomRequest) (*chat
 	}
 
 	// 發送系統消息：XXX 已加入群組
-	s.createSystemMessageAndUpdateRoom(ctx, req.RoomId, req.UserId+" 已加入群組", "創建加入群組系統消息失敗")
+	joined := systemMessageText(s.roomLocale(ctx, req.RoomId), constants.SystemMessageMemberJoined, map[string]string{"user": req.UserId})
+	s.createSystemMessageAndUpdateRoom(ctx, req.RoomId, joined, "創建加入群組系統消息失敗")
+	s.welcomeNewMember(ctx, req.RoomId)
 
 	// 審計日誌
 	s.audit.LogRoomJoin(ctx, req.UserId, req.RoomId)
@@ -308,8 +561,25 @@ func (s *Server) JoinRoom(ctx context.Context, req *chat.JoinRoomRequest) (*chat
 	}, nil
 }
 
-// LeaveRoom 離開聊天室
+// LeaveRoom 離開聊天室（只能自己離開，移出其他成員使用 KickMember）
 func (s *Server) LeaveRoom(ctx context.Context, req *chat.LeaveRoomRequest) (*chat.LeaveRoomResponse, error) {
+	// 啟用 JWT 時只能讓自己離開（移出他人使用 KickMember），也避免冒充擁有者觸發自動轉讓
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "leave room: user is not the authenticated user")
+		return &chat.LeaveRoomResponse{
+			Success: false,
+			Message: "只能讓自己離開聊天室",
+		}, nil
+	}
+
+	// 擁有者離開前先轉讓擁有權（或按配置拒絕），避免聊天室沒有擁有者
+	if ok, message := s.releaseOwnership(ctx, req.RoomId, req.UserId); !ok {
+		return &chat.LeaveRoomResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
 	// 從聊天室移除成員
 	err := s.repos.ChatRoom.RemoveMember(ctx, req.RoomId, req.UserId)
 	if err != nil {
@@ -319,9 +589,11 @@ func (s *Server) LeaveRoom(ctx context.Context, req *chat.LeaveRoomRequest) (*ch
 			Message: "離開聊天室失敗: " + err.Error(),
 		}, nil
 	}
+	s.senderMembership.Invalidate(req.RoomId, req.UserId)
 
 	// 發送系統消息：XXX 已離開群組
-	s.createSystemMessageAndUpdateRoom(ctx, req.RoomId, req.UserId+" 已離開群組", "創建離開群組系統消息失敗")
+	left := systemMessageText(s.roomLocale(ctx, req.RoomId), constants.SystemMessageMemberLeft, map[string]string{"user": req.UserId})
+	s.createSystemMessageAndUpdateRoom(ctx, req.RoomId, left, "創建離開群組系統消息失敗")
 
 	// 審計日誌
 	s.audit.LogRoomLeave(ctx, req.UserId, req.RoomId)
@@ -336,6 +608,218 @@ func (s *Server) LeaveRoom(ctx context.Context, req *chat.LeaveRoomRequest) (*ch
 	}, nil
 }
 
+// DeleteRoom 刪除聊天室（僅限擁有者）
+// 同時刪除聊天室的所有訊息和加密密鑰，避免留下孤立數據
+func (s *Server) DeleteRoom(ctx context.Context, req *chat.DeleteRoomRequest) (*chat.DeleteRoomResponse, error) {
+	// 啟用 JWT 時操作者必須是認證用戶，避免冒充擁有者刪除聊天室
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "delete room: user is not the authenticated user")
+		return &chat.DeleteRoomResponse{
+			Success: false,
+			Message: "只有聊天室擁有者可以刪除聊天室",
+		}, nil
+	}
+
+	room, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.DeleteRoomResponse{
+			Success: false,
+			Message: "聊天室不存在",
+		}, nil
+	}
+
+	// 只有擁有者可以刪除聊天室
+	if room.OwnerID != req.UserId {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "not room owner")
+		return &chat.DeleteRoomResponse{
+			Success: false,
+			Message: "只有聊天室擁有者可以刪除聊天室",
+		}, nil
+	}
+
+	deletedMessages, deletedKeys, err := s.deleteRoomCascade(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "刪除聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.DeleteRoomResponse{
+			Success: false,
+			Message: "刪除聊天室失敗: " + err.Error(),
+		}, nil
+	}
+
+	// 審計日誌
+	s.audit.LogRoomDeletion(ctx, req.UserId, req.RoomId, deletedMessages, deletedKeys)
+
+	logger.Info(ctx, "刪除聊天室成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("delete_room"),
+		logger.WithDetails(map[string]interface{}{
+			"deleted_messages": deletedMessages,
+			"deleted_keys":     deletedKeys,
+		}))
+
+	return &chat.DeleteRoomResponse{
+		Success:         true,
+		Message:         "聊天室已刪除",
+		DeletedMessages: deletedMessages,
+		DeletedKeys:     deletedKeys,
+	}, nil
+}
+
+// deleteRoomCascade 刪除聊天室及其訊息和加密密鑰，返回刪除的訊息和密鑰數量
+func (s *Server) deleteRoomCascade(ctx context.Context, roomID string) (int64, int64, error) {
+	// 與聊天室、訊息一起刪除加密密鑰
+	var deletedKeys int64
+	deleteKeys := func(ctx context.Context) error {
+		if s.keyManager == nil {
+			return nil
+		}
+		count, err := s.keyManager.DeleteRoomKeys(ctx, roomID)
+		deletedKeys = count
+		return err
+	}
+
+	deletedMessages, err := s.repos.ChatRoom.DeleteCascade(ctx, roomID, s.repos.Message, deleteKeys)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// 密鑰已刪除，失效該聊天室的加密器緩存
+	s.encryption.InvalidateRoom(roomID)
+	return deletedMessages, deletedKeys, nil
+}
+
+// UpdateRoom 更新聊天室名稱、頭像或設置（僅限擁有者或管理員）
+// 只修改請求中提供的字段，其他字段保持不變
+func (s *Server) UpdateRoom(ctx context.Context, req *chat.UpdateRoomRequest) (*chat.UpdateRoomResponse, error) {
+	// 啟用 JWT 時操作者必須是認證用戶，避免冒充擁有者或管理員
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "update room: user is not the authenticated user")
+		return &chat.UpdateRoomResponse{
+			Success: false,
+			Message: "只有聊天室擁有者或管理員可以更新聊天室",
+		}, nil
+	}
+
+	room, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.UpdateRoomResponse{
+			Success: false,
+			Message: "聊天室不存在",
+		}, nil
+	}
+
+	if !canManageRoom(room, req.UserId) {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "not room owner or admin")
+		return &chat.UpdateRoomResponse{
+			Success: false,
+			Message: "只有聊天室擁有者或管理員可以更新聊天室",
+		}, nil
+	}
+
+	// 客戶端讀取後聊天室已被修改時直接拒絕，避免覆蓋其他人的變更
+	expectedVersion := room.Version
+	if req.ExpectedVersion != nil {
+		expectedVersion = *req.ExpectedVersion
+	}
+	if expectedVersion != room.Version {
+		return roomVersionConflictResponse(room), nil
+	}
+
+	if err := validateRoomUpdate(req, room); err != nil {
+		return &chat.UpdateRoomResponse{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	// 名稱變更時檢查唯一性
+	if req.Name != nil && *req.Name != room.Name && room.Type != roomTypeDirect {
+		exists, err := s.repos.ChatRoom.NameExists(ctx, roomNameUniqueness(), *req.Name, room.OwnerID)
+		if err != nil {
+			logErrorWithUserAndRoom(ctx, "檢查聊天室名稱失敗", req.UserId, req.RoomId, err)
+			return &chat.UpdateRoomResponse{
+				Success: false,
+				Message: "更新聊天室失敗: " + err.Error(),
+			}, nil
+		}
+		if exists {
+			return &chat.UpdateRoomResponse{
+				Success: false,
+				Message: "聊天室名稱已存在",
+			}, nil
+		}
+	}
+
+	locale := room.Settings.Locale
+	if req.Settings != nil && req.Settings.Locale != nil {
+		locale = *req.Settings.Locale
+	}
+	update, changes := buildRoomUpdate(req, locale)
+	if len(update) == 0 {
+		return &chat.UpdateRoomResponse{
+			Success: true,
+			Message: "聊天室沒有變更",
+			Room:    convertRoomToGRPC(room),
+		}, nil
+	}
+
+	err = s.repos.ChatRoom.UpdateIf(ctx, req.RoomId, expectedVersion, update)
+	if errors.Is(err, chatroom.ErrVersionConflict) {
+		logger.Info(ctx, "聊天室版本衝突，拒絕更新",
+			logger.WithUserID(req.UserId),
+			logger.WithRoomID(req.RoomId),
+			logger.WithDetails(map[string]interface{}{"expected_version": expectedVersion}))
+		if latest, getErr := s.repos.ChatRoom.GetByID(ctx, req.RoomId); getErr == nil {
+			room = latest
+		}
+		return roomVersionConflictResponse(room), nil
+	}
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "更新聊天室失敗", req.UserId, req.RoomId, err)
+		return &chat.UpdateRoomResponse{
+			Success: false,
+			Message: "更新聊天室失敗: " + err.Error(),
+		}, nil
+	}
+
+	// 發送系統消息並更新最後訊息
+	separator := systemMessageText(locale, constants.SystemMessageChangeSeparator, nil)
+	s.createSystemMessageAndUpdateRoom(ctx, req.RoomId, strings.Join(changes, separator), "創建聊天室更新系統消息失敗")
+
+	// 審計日誌
+	s.audit.LogDataModification(ctx, req.UserId, "chat_room", req.RoomId, "update_room", update)
+
+	logger.Info(ctx, "更新聊天室成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("update_room"),
+		logger.WithDetails(map[string]interface{}{"changes": changes}))
+
+	updatedRoom, err := s.repos.ChatRoom.GetByID(ctx, req.RoomId)
+	if err != nil {
+		logErrorWithRoom(ctx, "獲取更新後的聊天室失敗", req.RoomId, err)
+		updatedRoom = room
+	}
+
+	return &chat.UpdateRoomResponse{
+		Success: true,
+		Message: "聊天室更新成功",
+		Room:    convertRoomToGRPC(updatedRoom),
+	}, nil
+}
+
+// roomVersionConflictResponse 聊天室版本衝突時的響應（附上最新的聊天室，客戶端可據此重新提交）
+func roomVersionConflictResponse(room *chatroom.ChatRoom) *chat.UpdateRoomResponse {
+	return &chat.UpdateRoomResponse{
+		Success: false,
+		Message: "聊天室已被其他人修改，請重新載入後再試",
+		Room:    convertRoomToGRPC(room),
+	}
+}
+
 // GetRoomInfo 獲取聊天室信息
 func (s *Server) GetRoomInfo(ctx context.Context, req *chat.GetRoomInfoRequest) (*chat.GetRoomInfoResponse, error) {
 	// TODO: 實現獲取聊天室信息邏輯
@@ -353,6 +837,13 @@ func (s *Server) GetRoomInfo(ctx context.Context, req *chat.GetRoomInfoRequest)
 
 // ListUserRooms 列出用戶的聊天室
 func (s *Server) ListUserRooms(ctx context.Context, req *chat.ListUserRoomsRequest) (*chat.ListUserRoomsResponse, error) {
+	if s.rejectImpersonation(ctx, req.UserId, "", "list user rooms") {
+		return &chat.ListUserRoomsResponse{
+			Success: false,
+			Message: "只能查看自己的聊天室列表",
+		}, nil
+	}
+
 	// 設定預設 limit
 	cfg := config.Get()
 	defaultLimit := 10
@@ -365,8 +856,19 @@ func (s *Server) ListUserRooms(ctx context.Context, req *chat.ListUserRoomsReque
 		limit = defaultLimit
 	}
 
+	sort := req.Sort
+	if sort == "" {
+		sort = constants.RoomSortActivity
+	}
+	if !validRoomSort(sort) {
+		return &chat.ListUserRoomsResponse{
+			Success: false,
+			Message: "無效的排序方式: " + req.Sort,
+		}, nil
+	}
+
 	// 從數據庫獲取用戶聊天室（使用 cursor 分頁）
-	rooms, cursor, hasMore, err := s.repos.ChatRoom.ListUserRooms(ctx, req.UserId, limit, req.Cursor)
+	rooms, cursor, hasMore, err := s.repos.ChatRoom.ListUserRooms(ctx, req.UserId, limit, req.Cursor, req.IncludeArchived, sort)
 	if err != nil {
 		logErrorWithUser(ctx, "獲取用戶聊天室失敗", req.UserId, err)
 		return &chat.ListUserRoomsResponse{
@@ -375,7 +877,14 @@ func (s *Server) ListUserRooms(ctx context.Context, req *chat.ListUserRoomsReque
 		}, nil
 	}
 
+	// 一次聚合計算所有聊天室的未讀數量，失敗時只記錄日誌，不影響列表本身
+	unreadCounts, err := s.countUnreadInRooms(ctx, req.UserId, rooms)
+	if err != nil {
+		logErrorWithUser(ctx, "獲取聊天室未讀數量失敗", req.UserId, err)
+	}
+
 	// 轉換為 gRPC 格式
+	now := time.Now()
 	grpcRooms := make([]*chat.ChatRoom, len(rooms))
 	for i, room := range rooms {
 		// 轉換成員信息
@@ -390,10 +899,9 @@ func (s *Server) ListUserRooms(ctx context.Context, req *chat.ListUserRoomsReque
 		// 解密 last_message（如果已加密）
 		lastMessage := room.LastMessage
 		if lastMessage != "" && s.encryption.IsEncrypted(lastMessage) {
-			decryptedLastMessage, err := s.encryption.DecryptMessage(lastMessage, room.ID)
+			decryptedLastMessage, err := s.encryption.DecryptMessageWithVersion(lastMessage, room.ID, room.LastMessageKeyVersion())
 			if err != nil {
-				logger.Error(ctx, "解密 last_message 失敗",
-					logger.WithRoomID(room.ID),
+				logger.Sampled(ctx, logger.SeverityError, "解密 last_message 失敗", room.ID,
 					logger.WithDetails(map[string]interface{}{"error": err.Error()}))
 				// 解密失敗，顯示通用訊息
 				lastMessage = messageText
@@ -409,6 +917,16 @@ func (s *Server) ListUserRooms(ctx context.Context, req *chat.ListUserRoomsReque
 			}
 		}
 
+		// 請求用戶自己的靜音和封存狀態（客戶端用來隱藏未讀標記）
+		self := room.FindMember(req.UserId)
+		muted, mutedUntil := memberMuteState(self, now)
+
+		// 可選：已靜音的聊天室未讀數量返回 0
+		unreadCount := unreadCounts[room.ID]
+		if req.ExcludeMuted && muted {
+			unreadCount = 0
+		}
+
 		grpcRooms[i] = &chat.ChatRoom{
 			Id:              room.ID,
 			Name:            room.Name,
@@ -419,6 +937,12 @@ func (s *Server) ListUserRooms(ctx context.Context, req *chat.ListUserRoomsReque
 			UpdatedAt:       room.UpdatedAt.Unix(),
 			LastMessage:     lastMessage,
 			LastMessageTime: lastMessageTime,
+			Muted:           muted,
+			MutedUntil:      mutedUntil,
+			Archived:        self != nil && self.Archived,
+			Pinned:          self != nil && self.Pinned,
+			Version:         room.Version,
+			UnreadCount:     int32(unreadCount), // #nosec G115 -- 單個聊天室的未讀數量遠小於 int32 上限
 		}
 	}
 
@@ -429,6 +953,7 @@ func (s *Server) ListUserRooms(ctx context.Context, req *chat.ListUserRoomsReque
 			"count":    len(grpcRooms),
 			"has_more": hasMore,
 			"cursor":   cursor,
+			"sort":     sort,
 		}))
 
 	return &chat.ListUserRoomsResponse{
@@ -440,17 +965,68 @@ func (s *Server) ListUserRooms(ctx context.Context, req *chat.ListUserRoomsReque
 	}, nil
 }
 
+// validRoomSort 是否為支持的聊天室列表排序方式
+func validRoomSort(sort string) bool {
+	switch sort {
+	case constants.RoomSortActivity, constants.RoomSortUnread, constants.RoomSortName:
+		return true
+	}
+	return false
+}
+
 // SendMessage 發送消息
 func (s *Server) SendMessage(ctx context.Context, req *chat.SendMessageRequest) (*chat.SendMessageResponse, error) {
+	// 未指定類型時視為文字訊息（兼容舊客戶端）
+	if req.Type == "" {
+		req.Type = messageTypeText
+	}
+	// 與 HTTP 層相同的內容檢查（長度、非空、NUL 字符），直接調用 gRPC 的客戶端不會經過 HTTP 驗證
+	if err := validateSendContent(req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateMessageMetadata(req.Type, req.Content, req.Metadata); err != nil {
+		return &chat.SendMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+	if err := validateMessageTTL(req.TtlSeconds); err != nil {
+		return &chat.SendMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+	if len(req.IdempotencyKey) > constants.MaxIdempotencyKeyLength {
+		return &chat.SendMessageResponse{Success: false, Message: fmt.Sprintf("冪等鍵不能超過 %d 個字符", constants.MaxIdempotencyKeyLength)}, nil
+	}
+
+	// 只有聊天室成員可以發送消息
+	if err := s.checkSenderMembership(ctx, req); err != nil {
+		return &chat.SendMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+
+	// 重試的請求：冪等鍵已使用過時返回第一次發送的訊息
+	if resp := s.replayIdempotentSend(ctx, req); resp != nil {
+		return resp, nil
+	}
+
+	if err := s.checkReplyTarget(ctx, req); err != nil {
+		return &chat.SendMessageResponse{Success: false, Message: err.Error()}, nil
+	}
+
 	// 加密並創建消息
-	message, encryptedContent, err := s.createEncryptedMessage(ctx, req)
+	message, encryptedContent, err := s.createEncryptedMessage(ctx, req, nil)
 	if err != nil {
+		// 同一冪等鍵的並發請求：另一個請求已經創建了訊息
+		if mongo.IsDuplicateKeyError(err) {
+			if resp := s.replayIdempotentSend(ctx, req); resp != nil {
+				return resp, nil
+			}
+		}
 		return &chat.SendMessageResponse{Success: false, Message: err.Error()}, nil
 	}
 
 	// 更新聊天室最後訊息
 	s.updateRoomLastMessage(ctx, req, &message)
 
+	// 通知被提及的用戶
+	s.notifyMentions(ctx, &message)
+	s.touchPresence(ctx, req.SenderId)
+
 	// 審計和日誌
 	s.audit.LogMessageSent(ctx, req.SenderId, req.RoomId, message.GetID(), req.Type)
 	logger.Info(ctx, "消息發送成功",
@@ -473,19 +1049,168 @@ func (s *Server) SendMessage(ctx context.Context, req *chat.SendMessageRequest)
 	}, nil
 }
 
-// GetMessages 獲取消息
-func (s *Server) GetMessages(ctx context.Context, req *chat.GetMessagesRequest) (*chat.GetMessagesResponse, error) {
-	// 從數據庫獲取消息（使用分頁參數）
-	messages, nextCursor, hasMore, err := s.repos.Message.GetByRoomID(ctx, req.RoomId, int(req.Limit), req.Cursor, nil, nil)
-	if err != nil {
-		logErrorWithRoom(ctx, "獲取消息失敗", req.RoomId, err)
-		return &chat.GetMessagesResponse{
-			Success: false,
-			Message: "獲取消息失敗: " + err.Error(),
-		}, nil
+// validateSendContent 驗證訊息內容；圖片、文件等訊息的說明文字可以為空
+func validateSendContent(req *chat.SendMessageRequest) error {
+	if req.Type != messageTypeText && req.Content == "" {
+		return nil
+	}
+	return middleware.ValidateMessageContent(req.Content)
+}
+
+// checkMessageReader 只有聊天室成員（啟用 JWT 時必須是認證用戶本人）可以讀取訊息
+func (s *Server) checkMessageReader(ctx context.Context, roomID, userID, action string) (bool, string) {
+	if userID == "" {
+		return false, "缺少 user_id"
+	}
+	if s.rejectImpersonation(ctx, userID, roomID, action) {
+		return false, "您不是此聊天室的成員"
+	}
+
+	isMember, err := s.repos.ChatRoom.IsMember(ctx, roomID, userID)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "檢查成員資格失敗", userID, roomID, err)
+		return false, "檢查成員資格失敗"
+	}
+	if !isMember {
+		s.audit.LogAccessDenied(ctx, userID, roomID, action+": not a room member")
+		return false, "您不是此聊天室的成員"
+	}
+	return true, ""
+}
+
+// GetMessages 獲取消息
+func (s *Server) GetMessages(ctx context.Context, req *chat.GetMessagesRequest) (*chat.GetMessagesResponse, error) {
+	if ok, message := s.checkMessageReader(ctx, req.RoomId, req.UserId, "get messages"); !ok {
+		return &chat.GetMessagesResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
+	// 從數據庫獲取消息（使用分頁參數）
+	messages, nextCursor, hasMore, err := s.repos.Message.GetByRoomID(ctx, req.RoomId, int(req.Limit), req.Cursor, nil, nil)
+	if err != nil {
+		logErrorWithRoom(ctx, "獲取消息失敗", req.RoomId, err)
+		return &chat.GetMessagesResponse{
+			Success: false,
+			Message: "獲取消息失敗: " + err.Error(),
+		}, nil
 	}
 
 	// 轉換為 gRPC 格式並解密
+	grpcMessages := s.convertMessagesToGRPC(ctx, messages)
+	s.applyAggregateStatus(ctx, req.RoomId, messages, grpcMessages)
+	s.applyReplyCounts(ctx, req.RoomId, grpcMessages)
+
+	logger.Info(ctx, "獲取消息成功",
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("get_messages"),
+		logger.WithDetails(map[string]interface{}{
+			"count":   len(grpcMessages),
+			"hasMore": hasMore,
+			"limit":   req.Limit,
+			"cursor":  req.Cursor,
+		}))
+
+	return &chat.GetMessagesResponse{
+		Success:    true,
+		Message:    "獲取消息成功",
+		Messages:   grpcMessages,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	}, nil
+}
+
+// GetMessagesAround 獲取指定訊息或游標前後的訊息
+// 用於「跳轉到訊息後向上、向下滾動」的場景，返回前後兩個方向的游標
+func (s *Server) GetMessagesAround(ctx context.Context, req *chat.GetMessagesAroundRequest) (*chat.GetMessagesAroundResponse, error) {
+	if ok, message := s.checkMessageReader(ctx, req.RoomId, req.UserId, "get messages around"); !ok {
+		return &chat.GetMessagesAroundResponse{
+			Success: false,
+			Message: message,
+		}, nil
+	}
+
+	before, after := normalizeWindowLimits(int(req.BeforeLimit), int(req.AfterLimit))
+
+	window, err := s.repos.Message.GetAround(ctx, req.RoomId, req.AnchorMessageId, req.Cursor, before, after)
+	if err != nil {
+		logErrorWithRoom(ctx, "獲取訊息窗口失敗", req.RoomId, err)
+		return &chat.GetMessagesAroundResponse{
+			Success: false,
+			Message: "獲取消息失敗: " + err.Error(),
+		}, nil
+	}
+
+	grpcMessages := s.convertMessagesToGRPC(ctx, window.Messages)
+	s.applyAggregateStatus(ctx, req.RoomId, window.Messages, grpcMessages)
+
+	logger.Info(ctx, "獲取訊息窗口成功",
+		logger.WithRoomID(req.RoomId),
+		logger.WithAction("get_messages_around"),
+		logger.WithDetails(map[string]interface{}{
+			"count":     len(grpcMessages),
+			"anchor":    req.AnchorMessageId,
+			"hasBefore": window.HasBefore,
+			"hasAfter":  window.HasAfter,
+		}))
+
+	return &chat.GetMessagesAroundResponse{
+		Success:      true,
+		Message:      "獲取消息成功",
+		Messages:     grpcMessages,
+		BeforeCursor: window.BeforeCursor,
+		HasBefore:    window.HasBefore,
+		AfterCursor:  window.AfterCursor,
+		HasAfter:     window.HasAfter,
+	}, nil
+}
+
+// normalizeWindowLimits 標準化訊息窗口前後的數量（負數視為 0，超出上限時截斷）
+// 兩個方向都未指定時，前後各取默認分頁大小的一半
+func normalizeWindowLimits(before, after int) (int, int) {
+	if before <= 0 && after <= 0 {
+		half := normalizePageSize(0) / 2
+		return half, half
+	}
+
+	if before < 0 {
+		before = 0
+	} else if before > 0 {
+		before = normalizePageSize(before)
+	}
+	if after < 0 {
+		after = 0
+	} else if after > 0 {
+		after = normalizePageSize(after)
+	}
+	return before, after
+}
+
+// normalizePageSize 標準化分頁大小
+func normalizePageSize(limit int) int {
+	defaultLimit := constants.DefaultPageSize
+	maxLimit := constants.DefaultMaxPageSize
+	if cfg := config.Get(); cfg != nil {
+		if cfg.Limits.Pagination.DefaultPageSize > 0 {
+			defaultLimit = cfg.Limits.Pagination.DefaultPageSize
+		}
+		if cfg.Limits.Pagination.MaxPageSize > 0 {
+			maxLimit = cfg.Limits.Pagination.MaxPageSize
+		}
+	}
+
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
+
+// convertMessagesToGRPC 解密訊息並轉換為 gRPC 格式
+func (s *Server) convertMessagesToGRPC(ctx context.Context, messages []*chatroom.Message) []*chat.ChatMessage {
 	grpcMessages := make([]*chat.ChatMessage, len(messages))
 	for i, msg := range messages {
 		// 系統訊息不需要解密（純文本）
@@ -493,11 +1218,10 @@ func (s *Server) GetMessages(ctx context.Context, req *chat.GetMessagesRequest)
 		if msg.Type != systemSenderID {
 			// 只解密非系統訊息
 			var err error
-			decryptedContent, err = s.encryption.DecryptMessage(msg.Content, msg.RoomID)
+			decryptedContent, err = s.encryption.DecryptMessageWithVersion(msg.Content, msg.RoomID, msg.KeyVersion())
 			if err != nil {
-				logger.Warning(ctx, "消息解密失敗",
+				logger.Sampled(ctx, logger.SeverityWarning, "消息解密失敗", msg.RoomID,
 					logger.WithMessageID(msg.GetID()),
-					logger.WithRoomID(msg.RoomID),
 					logger.WithDetails(map[string]interface{}{"error": err.Error()}))
 				decryptedContent = decryptFailedText
 			}
@@ -515,34 +1239,27 @@ func (s *Server) GetMessages(ctx context.Context, req *chat.GetMessagesRequest)
 		grpcReadBy := cleanReadBy(msg.ReadBy, msg.SenderID)
 
 		grpcMessages[i] = &chat.ChatMessage{
-			Id:        msg.GetID(),
-			RoomId:    msg.RoomID,
-			SenderId:  msg.SenderID,
-			Content:   decryptedContent, // 返回解密後的內容
-			Type:      msg.Type,
-			CreatedAt: msg.CreatedAt.Unix(),
-			UpdatedAt: msg.UpdatedAt.Unix(),
-			ReadBy:    grpcReadBy,
+			Id:               msg.GetID(),
+			RoomId:           msg.RoomID,
+			SenderId:         msg.SenderID,
+			SenderName:       senderNameFor(msg.SenderID),
+			Content:          decryptedContent, // 返回解密後的內容
+			Type:             msg.Type,
+			Metadata:         metadataToGRPC(&msg.Metadata),
+			CreatedAt:        msg.CreatedAt.Unix(),
+			UpdatedAt:        msg.UpdatedAt.Unix(),
+			ReadBy:           grpcReadBy,
+			ReadReceipts:     cleanReadReceipts(msg.ReadBy, msg.SenderID),
+			Status:           storedMessageStatus(msg),
+			DeliveredTo:      cleanDeliveredTo(msg.DeliveredTo, msg.SenderID),
+			Mentions:         msg.Mentions,
+			ForwardedFrom:    forwardedFromToGRPC(msg),
+			ExpiresAt:        expiresAtToGRPC(msg),
+			ReplyToMessageId: msg.ReplyToMessageID,
 		}
 	}
 
-	logger.Info(ctx, "獲取消息成功",
-		logger.WithRoomID(req.RoomId),
-		logger.WithAction("get_messages"),
-		logger.WithDetails(map[string]interface{}{
-			"count":   len(grpcMessages),
-			"hasMore": hasMore,
-			"limit":   req.Limit,
-			"cursor":  req.Cursor,
-		}))
-
-	return &chat.GetMessagesResponse{
-		Success:    true,
-		Message:    "獲取消息成功",
-		Messages:   grpcMessages,
-		NextCursor: nextCursor,
-		HasMore:    hasMore,
-	}, nil
+	return grpcMessages
 }
 
 // StreamMessages 流式獲取消息
@@ -553,37 +1270,63 @@ func (s *Server) StreamMessages(req *chat.StreamMessagesRequest, stream chat.Cha
 		logger.WithUserID(req.UserId),
 		logger.WithRoomID(req.RoomId))
 
-	// 初始化已見訊息集合
-	seenMessageIDs := s.initializeSeenMessages(ctx, req.RoomId)
-
-	// 持續監聽新訊息
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
+	if req.SinceCursor != "" {
+		if err := chatroom.ValidateMessageCursor(req.SinceCursor); err != nil {
+			return status.Error(codes.InvalidArgument, "無效的 since_cursor")
+		}
+	}
 
-	for {
-		select {
-		case <-ctx.Done():
-			logger.Info(ctx, "訊息流結束",
-				logger.WithUserID(req.UserId),
-				logger.WithRoomID(req.RoomId))
-			return nil
+	// 只有聊天室成員可以接收訊息
+	membership, err := s.verifyStreamMembership(ctx, req)
+	if err != nil {
+		return err
+	}
 
-		case <-ticker.C:
-			if err := s.fetchAndStreamNewMessages(ctx, req, stream, seenMessageIDs); err != nil {
-				return err
-			}
-		}
+	// 斷線續傳時補發的訊息可能也出現在第一批新訊息中，記錄已推送的 ID 避免重複
+	var seen *seenMessageSet
+	if req.SinceCursor != "" {
+		_, seenSetSize := streamSeenSetLimits()
+		seen = newSeenMessageSet(seenSetSize)
 	}
+
+	// 透過聊天室分發中心持續推送新訊息
+	err = s.streamRoomMessages(ctx, req, stream.Send, seen, membership)
+
+	logger.Info(ctx, "訊息流結束",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId))
+	s.flushReadReceipts(req.RoomId, req.UserId)
+	return err
 }
 
 // MarkAsRead 標記為已讀
 func (s *Server) MarkAsRead(ctx context.Context, req *chat.MarkAsReadRequest) (*chat.MarkAsReadResponse, error) {
-	// 標記消息為已讀
-	var messageID *string
-	if req.MessageId != "" {
-		messageID = &req.MessageId
+	if s.rejectImpersonation(ctx, req.UserId, req.RoomId, "mark as read") {
+		return &chat.MarkAsReadResponse{
+			Success: false,
+			Message: "只能標記自己的已讀狀態",
+		}, nil
+	}
+
+	var err error
+	if s.readReceipts != nil {
+		err = s.markAsReadBatched(ctx, req)
+	} else {
+		// 標記消息為已讀
+		var messageID *string
+		if req.MessageId != "" {
+			messageID = &req.MessageId
+		}
+		readAt := time.Now()
+		err = s.repos.Message.MarkAsRead(ctx, req.RoomId, req.UserId, messageID)
+		if err == nil {
+			// 同步推進 LastReadAt，GetUnreadCount 依賴它作為快速路徑
+			err = s.advanceLastReadAt(ctx, req.RoomId, req.UserId, req.MessageId, readAt)
+		}
+		if err == nil {
+			s.advanceMessageStatus(ctx, req.RoomId, optionalMessageIDs(req.MessageId))
+		}
 	}
-	err := s.repos.Message.MarkAsRead(ctx, req.RoomId, req.UserId, messageID)
 	if err != nil {
 		logErrorWithUserAndRoom(ctx, "標記已讀失敗", req.UserId, req.RoomId, err)
 		return &chat.MarkAsReadResponse{
@@ -598,6 +1341,7 @@ func (s *Server) MarkAsRead(ctx context.Context, req *chat.MarkAsReadRequest) (*
 		msgID = req.MessageId
 	}
 	s.audit.LogMessageRead(ctx, req.UserId, req.RoomId, msgID)
+	s.touchPresence(ctx, req.UserId)
 
 	logger.Info(ctx, "標記消息已讀成功",
 		logger.WithUserID(req.UserId),
@@ -610,12 +1354,81 @@ func (s *Server) MarkAsRead(ctx context.Context, req *chat.MarkAsReadRequest) (*
 	}, nil
 }
 
+// MarkAsDelivered 標記為已送達
+func (s *Server) MarkAsDelivered(ctx context.Context, req *chat.MarkAsDeliveredRequest) (*chat.MarkAsDeliveredResponse, error) {
+	if s.rejectImpersonation(ctx, req.UserId, req.RoomId, "mark as delivered") {
+		return &chat.MarkAsDeliveredResponse{
+			Success: false,
+			Message: "只能標記自己的送達狀態",
+		}, nil
+	}
+
+	var messageID *string
+	if req.MessageId != "" {
+		messageID = &req.MessageId
+	}
+
+	if err := s.repos.Message.MarkAsDelivered(ctx, req.RoomId, req.UserId, messageID); err != nil {
+		logErrorWithUserAndRoom(ctx, "標記送達失敗", req.UserId, req.RoomId, err)
+		return &chat.MarkAsDeliveredResponse{
+			Success: false,
+			Message: "標記送達失敗: " + err.Error(),
+		}, nil
+	}
+	s.advanceMessageStatus(ctx, req.RoomId, optionalMessageIDs(req.MessageId))
+
+	logger.Debug(ctx, "標記消息送達成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithMessageID(req.MessageId),
+		logger.WithAction("mark_as_delivered"))
+	return &chat.MarkAsDeliveredResponse{
+		Success: true,
+		Message: "標記送達成功",
+	}, nil
+}
+
+// markStreamDelivered 將已推送給用戶的訊息標記為已送達
+// 失敗時只記錄日誌，不中斷訊息流（客戶端也可以透過 MarkAsDelivered 補報）
+func (s *Server) markStreamDelivered(ctx context.Context, req *chat.StreamMessagesRequest, messageIDs []string) {
+	if req.UserId == "" || len(messageIDs) == 0 {
+		return
+	}
+
+	if err := s.repos.Message.MarkManyAsDelivered(ctx, req.RoomId, req.UserId, messageIDs); err != nil {
+		logger.Warning(ctx, "標記推送訊息送達失敗",
+			logger.WithUserID(req.UserId),
+			logger.WithRoomID(req.RoomId),
+			logger.WithDetails(map[string]interface{}{
+				"count": len(messageIDs),
+				"error": err.Error(),
+			}))
+		return
+	}
+	s.advanceMessageStatus(ctx, req.RoomId, messageIDs)
+}
+
 // GetUnreadCount 獲取未讀數量
 func (s *Server) GetUnreadCount(ctx context.Context, req *chat.GetUnreadCountRequest) (*chat.GetUnreadCountResponse, error) {
-	// 獲取該聊天室的所有訊息
-	messages, _, _, err := s.repos.Message.GetByRoomID(ctx, req.RoomId, 1000, "", nil, nil)
+	if s.rejectImpersonation(ctx, req.UserId, req.RoomId, "get unread count") {
+		return &chat.GetUnreadCountResponse{
+			Success: false,
+			Message: "只能查看自己的未讀數量",
+		}, nil
+	}
+
+	// 可選：已靜音的聊天室不計算未讀數量
+	if req.ExcludeMuted && req.RoomId != "" && s.isRoomMutedFor(ctx, req.RoomId, req.UserId) {
+		return &chat.GetUnreadCountResponse{
+			Success: true,
+			Message: "聊天室已靜音",
+			Count:   0,
+		}, nil
+	}
+
+	unreadCount, err := s.countUnread(ctx, req.RoomId, req.UserId)
 	if err != nil {
-		logger.Error(ctx, "獲取訊息失敗",
+		logger.Error(ctx, "獲取未讀數量失敗",
 			logger.WithUserID(req.UserId),
 			logger.WithRoomID(req.RoomId),
 			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
@@ -626,21 +1439,6 @@ func (s *Server) GetUnreadCount(ctx context.Context, req *chat.GetUnreadCountReq
 		}, nil
 	}
 
-	// 計算未讀數量（訊息的 read_by 中不包含該用戶）
-	unreadCount := int32(0)
-	for _, message := range messages {
-		isRead := false
-		for _, readBy := range message.ReadBy {
-			if readBy.UserID == req.UserId {
-				isRead = true
-				break
-			}
-		}
-		if !isRead && message.SenderID != req.UserId {
-			unreadCount++
-		}
-	}
-
 	logger.Info(ctx, "獲取未讀數量成功",
 		logger.WithUserID(req.UserId),
 		logger.WithRoomID(req.RoomId),
@@ -653,6 +1451,52 @@ func (s *Server) GetUnreadCount(ctx context.Context, req *chat.GetUnreadCountReq
 	}, nil
 }
 
+// countUnread 計算用戶在聊天室中的未讀數量
+// 快速模式按成員的 LastReadAt 做一次索引計數；精確模式或找不到成員時按 read_by 統計
+func (s *Server) countUnread(ctx context.Context, roomID, userID string) (int32, error) {
+	if unreadCountMode() == constants.UnreadCountFast {
+		lastReadAt, err := s.repos.ChatRoom.GetMemberLastReadAt(ctx, roomID, userID)
+		if err == nil {
+			count, err := s.repos.Message.CountUnreadSince(ctx, roomID, userID, lastReadAt)
+			return int32(count), err
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, err
+		}
+	}
+
+	count, err := s.repos.Message.CountUnreadByReceipts(ctx, roomID, userID)
+	return int32(count), err
+}
+
+// countUnreadInRooms 一次聚合計算用戶在多個聊天室中的未讀數量
+func (s *Server) countUnreadInRooms(ctx context.Context, userID string, rooms []*chatroom.ChatRoom) (map[string]int, error) {
+	sinceByRoom, receiptRooms := partitionUnreadRooms(rooms, userID, unreadCountMode())
+	return s.repos.Message.CountUnreadInRooms(ctx, userID, sinceByRoom, receiptRooms)
+}
+
+// partitionUnreadRooms 按計算方式拆分聊天室（與 countUnread 一致）
+// 快速模式下有成員記錄的聊天室按 LastReadAt 計數；精確模式或找不到成員時按 read_by 統計
+func partitionUnreadRooms(rooms []*chatroom.ChatRoom, userID, mode string) (sinceByRoom map[string]time.Time, receiptRooms []string) {
+	sinceByRoom = make(map[string]time.Time, len(rooms))
+	for _, room := range rooms {
+		if self := room.FindMember(userID); mode == constants.UnreadCountFast && self != nil {
+			sinceByRoom[room.ID] = self.LastReadAt
+			continue
+		}
+		receiptRooms = append(receiptRooms, room.ID)
+	}
+	return sinceByRoom, receiptRooms
+}
+
+// unreadCountMode 讀取未讀數量的計算方式（默認快速模式）
+func unreadCountMode() string {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.ReadReceipt.UnreadCountMode != "" {
+		return cfg.Limits.ReadReceipt.UnreadCountMode
+	}
+	return constants.UnreadCountFast
+}
+
 // logErrorWithUserAndRoom 記錄包含用戶和聊天室信息的錯誤日誌
 func logErrorWithUserAndRoom(ctx context.Context, message, userID, roomID string, err error) {
 	logger.Error(ctx, message,
@@ -677,15 +1521,19 @@ func logErrorWithRoom(ctx context.Context, message, roomID string, err error) {
 
 // convertMembersToGRPC 將成員列表轉換為 gRPC 格式
 func convertMembersToGRPC(members []chatroom.RoomMember) []*chat.RoomMember {
+	now := time.Now()
 	grpcMembers := make([]*chat.RoomMember, len(members))
 	for i := range members {
 		member := &members[i]
+		muted, mutedUntil := memberMuteState(member, now)
 		grpcMembers[i] = &chat.RoomMember{
-			UserId:   member.UserID,
-			Username: member.Username,
-			Role:     member.Role,
-			JoinedAt: member.JoinedAt.Unix(),
-			LastSeen: member.LastSeen.Unix(),
+			UserId:     member.UserID,
+			Username:   member.Username,
+			Role:       member.Role,
+			JoinedAt:   member.JoinedAt.Unix(),
+			LastSeen:   member.LastSeen.Unix(),
+			Muted:      muted,
+			MutedUntil: mutedUntil,
 		}
 	}
 	return grpcMembers
@@ -706,10 +1554,11 @@ func (s *Server) createSystemMessageAndUpdateRoom(ctx context.Context, roomID, c
 	} else {
 		// 更新聊天室的最後訊息
 		if updateErr := s.repos.ChatRoom.Update(ctx, roomID, map[string]interface{}{
-			"last_message":      systemMessage.Content,
-			"last_message_time": systemMessage.CreatedAt,
-			"last_message_at":   systemMessage.CreatedAt,
-			"updated_at":        systemMessage.CreatedAt,
+			"last_message":        systemMessage.Content,
+			"last_message_key_id": "", // 系統訊息不加密
+			"last_message_time":   systemMessage.CreatedAt,
+			"last_message_at":     systemMessage.CreatedAt,
+			"updated_at":          systemMessage.CreatedAt,
 		}); updateErr != nil {
 			logger.Warning(ctx, "更新聊天室最後訊息失敗（"+warningPrefix+"）",
 				logger.WithRoomID(roomID),
@@ -718,48 +1567,132 @@ func (s *Server) createSystemMessageAndUpdateRoom(ctx context.Context, roomID, c
 	}
 }
 
-// findExistingDirectChat 查找現有的私聊聊天室
-func (s *Server) findExistingDirectChat(ctx context.Context, ownerID string, memberIds []string) *chatroom.ChatRoom {
-	cfg := config.Get()
-	checkLimit := 100
-	if cfg != nil && cfg.Limits.MongoDB.MaxQueryLimit > 0 {
-		checkLimit = cfg.Limits.MongoDB.MaxQueryLimit
+// canManageRoom 檢查用戶是否可以管理聊天室（擁有者或管理員）
+func canManageRoom(room *chatroom.ChatRoom, userID string) bool {
+	if room.OwnerID == userID {
+		return true
+	}
+	for i := range room.Members {
+		if room.Members[i].UserID == userID {
+			return room.Members[i].Role == roleAdmin
+		}
 	}
+	return false
+}
 
-	existingRooms, _, _, err := s.repos.ChatRoom.ListUserRooms(ctx, ownerID, checkLimit, "")
-	if err != nil {
-		return nil
+// maxRoomMembers 聊天室成員數量的全局上限
+func maxRoomMembers() int {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Room.MaxMembers > 0 {
+		return cfg.Limits.Room.MaxMembers
 	}
+	return constants.DefaultMaxRoomMembers
+}
 
-	memberIDMap := make(map[string]bool, len(memberIds))
-	for _, id := range memberIds {
-		memberIDMap[id] = true
+// validateRoomUpdate 驗證聊天室更新請求中提供的字段
+func validateRoomUpdate(req *chat.UpdateRoomRequest, room *chatroom.ChatRoom) error {
+	if req.Name != nil {
+		if err := middleware.ValidateRoomName(*req.Name); err != nil {
+			return err
+		}
 	}
 
-	for _, room := range existingRooms {
-		if room.Type != roomTypeDirect || len(room.Members) != 2 {
-			continue
+	if req.Settings != nil && req.Settings.WelcomeMessage != nil {
+		if err := middleware.ValidateWelcomeMessage(*req.Settings.WelcomeMessage); err != nil {
+			return err
 		}
+	}
 
-		// 檢查是否包含相同的兩個成員
-		if s.hasSameMembers(room.Members, memberIDMap) {
-			return room
+	if req.Settings != nil && req.Settings.Locale != nil {
+		if err := middleware.ValidateLocale(*req.Settings.Locale); err != nil {
+			return err
 		}
 	}
+
+	if req.Settings != nil && req.Settings.MaxMembers != nil {
+		maxMembers := int(*req.Settings.MaxMembers)
+		limit := maxRoomMembers()
+		if room.Type == roomTypeDirect {
+			limit = middleware.DirectRoomMaxMembers
+		}
+		if maxMembers <= 0 || maxMembers > limit {
+			return fmt.Errorf("最大成員數必須在 1 到 %d 之間", limit)
+		}
+		if maxMembers < len(room.Members) {
+			return fmt.Errorf("最大成員數不能小於當前成員數 (%d)", len(room.Members))
+		}
+	}
+
 	return nil
 }
 
-// hasSameMembers 檢查聊天室成員是否與給定的成員ID匹配
-func (s *Server) hasSameMembers(members []chatroom.RoomMember, memberIDMap map[string]bool) bool {
-	if len(members) != len(memberIDMap) {
-		return false
+// buildRoomUpdate 根據請求中提供的字段構建更新內容和變更描述（變更描述使用 locale 語言）
+func buildRoomUpdate(req *chat.UpdateRoomRequest, locale string) (map[string]interface{}, []string) {
+	update := map[string]interface{}{}
+	changes := []string{}
+
+	if req.Name != nil {
+		update["name"] = strings.TrimSpace(*req.Name)
+		changes = append(changes, systemMessageText(locale, constants.SystemMessageRoomRenamed, map[string]string{"name": strings.TrimSpace(*req.Name)}))
 	}
-	for i := range members {
-		if !memberIDMap[members[i].UserID] {
-			return false
+
+	if req.AvatarUrl != nil {
+		update["avatar_url"] = *req.AvatarUrl
+		changes = append(changes, systemMessageText(locale, constants.SystemMessageAvatarChanged, nil))
+	}
+
+	if settings := req.Settings; settings != nil {
+		settingsCount := len(update)
+		if settings.AllowInvite != nil {
+			update["settings.allow_invite"] = *settings.AllowInvite
+		}
+		if settings.AllowEditMessages != nil {
+			update["settings.allow_edit_messages"] = *settings.AllowEditMessages
+		}
+		if settings.AllowDeleteMessages != nil {
+			update["settings.allow_delete_messages"] = *settings.AllowDeleteMessages
+		}
+		if settings.AllowPinMessages != nil {
+			update["settings.allow_pin_messages"] = *settings.AllowPinMessages
+		}
+		if settings.MaxMembers != nil {
+			update["settings.max_members"] = int(*settings.MaxMembers)
+		}
+		if settings.WelcomeMessage != nil {
+			update["settings.welcome_message"] = *settings.WelcomeMessage
+		}
+		if settings.Locale != nil {
+			update["settings.locale"] = strings.ToLower(*settings.Locale)
+		}
+		if len(update) > settingsCount {
+			changes = append(changes, systemMessageText(locale, constants.SystemMessageSettingsChanged, nil))
+		}
+	}
+
+	return update, changes
+}
+
+// roomNameUniqueness 讀取聊天室名稱唯一性模式（默認不限制）
+func roomNameUniqueness() string {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Room.NameUniqueness != "" {
+		return cfg.Limits.Room.NameUniqueness
+	}
+	return chatroom.NameUniquenessNone
+}
+
+// existingDirectRoomResponse 私聊已存在時的響應
+// 重新發起已封存的私聊時取消封存，讓聊天室回到列表中
+func (s *Server) existingDirectRoomResponse(ctx context.Context, ownerID string, room *chatroom.ChatRoom) *chat.CreateRoomResponse {
+	logger.Infof(ctx, "找到重複的私聊聊天室: %s", room.ID)
+	if member := room.FindMember(ownerID); member != nil && member.Archived {
+		if err := s.repos.ChatRoom.SetMemberArchived(ctx, room.ID, ownerID, false); err != nil {
+			logErrorWithUserAndRoom(ctx, "取消封存私聊失敗", ownerID, room.ID, err)
 		}
 	}
-	return true
+	return &chat.CreateRoomResponse{
+		Success: true,
+		Message: "聊天室已存在",
+		Room:    convertRoomToGRPC(room),
+	}
 }
 
 // ensureOwnerInMembers 確保創建者在成員列表中
@@ -797,16 +1730,52 @@ func convertRoomToGRPC(room *chatroom.ChatRoom) *chat.ChatRoom {
 		Name:      room.Name,
 		Type:      room.Type,
 		OwnerId:   room.OwnerID,
+		AvatarUrl: room.AvatarURL,
 		Members:   convertMembersToGRPC(room.Members),
+		Settings: &chat.RoomSettings{
+			AllowInvite:         room.Settings.AllowInvite,
+			AllowEditMessages:   room.Settings.AllowEditMessages,
+			AllowDeleteMessages: room.Settings.AllowDeleteMessages,
+			AllowPinMessages:    room.Settings.AllowPinMessages,
+			MaxMembers:          int32(room.Settings.MaxMembers), // #nosec G115 -- MaxMembers is from DB
+			WelcomeMessage:      room.Settings.WelcomeMessage,
+			Mode:                roomMode(room),
+			SlowmodeSeconds:     int32(room.Settings.SlowmodeSeconds), // #nosec G115 -- validated in SetRoomMode
+			Locale:              room.Settings.Locale,
+		},
 		CreatedAt: room.CreatedAt.Unix(),
 		UpdatedAt: room.UpdatedAt.Unix(),
+		Version:   room.Version,
 	}
 }
 
 // createEncryptedMessage 創建並加密消息
-func (s *Server) createEncryptedMessage(ctx context.Context, req *chat.SendMessageRequest) (chatroom.Message, string, error) {
+// forwardedFrom 非空時為轉發的訊息，記錄來源且不解析提及（避免在目標聊天室重新通知）
+func (s *Server) createEncryptedMessage(ctx context.Context, req *chat.SendMessageRequest, forwardedFrom []string) (chatroom.Message, string, error) {
+	message, encryptedContent, err := s.buildEncryptedMessage(ctx, req, forwardedFrom)
+	if err != nil {
+		return chatroom.Message{}, "", err
+	}
+
+	// 保存到數據庫
+	err = s.repos.Message.Create(ctx, &message)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "發送消息失敗", req.SenderId, req.RoomId, err)
+		return chatroom.Message{}, "", fmt.Errorf("發送消息失敗: %w", err)
+	}
+
+	return message, encryptedContent, nil
+}
+
+// buildEncryptedMessage 檢查聊天室模式並加密內容，返回尚未保存的消息和加密後的內容
+func (s *Server) buildEncryptedMessage(ctx context.Context, req *chat.SendMessageRequest, forwardedFrom []string) (chatroom.Message, string, error) {
+	// 檢查聊天室模式是否允許發言
+	if err := s.enforceRoomMode(ctx, req); err != nil {
+		return chatroom.Message{}, "", err
+	}
+
 	// 加密消息內容
-	encryptedContent, err := s.encryption.EncryptMessage(req.Content, req.RoomId)
+	encryptedContent, keyVersion, err := s.encryption.EncryptMessageWithVersion(req.Content, req.RoomId)
 	if err != nil {
 		logErrorWithUserAndRoom(ctx, "消息加密失敗", req.SenderId, req.RoomId, err)
 		return chatroom.Message{}, "", fmt.Errorf("消息加密失敗: %w", err)
@@ -818,12 +1787,18 @@ func (s *Server) createEncryptedMessage(ctx context.Context, req *chat.SendMessa
 	message.SenderID = req.SenderId
 	message.Content = encryptedContent
 	message.Type = req.Type
-
-	// 保存到數據庫
-	err = s.repos.Message.Create(ctx, &message)
-	if err != nil {
-		logErrorWithUserAndRoom(ctx, "發送消息失敗", req.SenderId, req.RoomId, err)
-		return chatroom.Message{}, "", fmt.Errorf("發送消息失敗: %w", err)
+	message.Metadata = metadataFromGRPC(req.Metadata)
+	message.IdempotencyKey = req.IdempotencyKey
+	message.ReplyToMessageID = req.ReplyToMessageId
+	message.SetKeyVersion(keyVersion)
+	if req.TtlSeconds > 0 {
+		expiresAt := message.CreatedAt.Add(time.Duration(req.TtlSeconds) * time.Second)
+		message.ExpiresAt = &expiresAt
+	}
+	if forwardedFrom != nil {
+		message.ForwardedFrom = forwardedFrom
+	} else {
+		message.Mentions = s.resolveMentions(ctx, req)
 	}
 
 	return message, encryptedContent, nil
@@ -832,7 +1807,7 @@ func (s *Server) createEncryptedMessage(ctx context.Context, req *chat.SendMessa
 // generateLastMessagePreview 生成最後訊息預覽
 func generateLastMessagePreview(msgType, content string) string {
 	switch msgType {
-	case "text":
+	case messageTypeText, systemSenderID:
 		if len(content) > 30 {
 			runes := []rune(content)
 			if len(runes) > 30 {
@@ -841,15 +1816,15 @@ func generateLastMessagePreview(msgType, content string) string {
 			return content
 		}
 		return content
-	case "image":
+	case messageTypeImage:
 		return "[圖片]"
-	case "file":
+	case messageTypeFile:
 		return "[文件]"
-	case "audio":
+	case messageTypeAudio:
 		return "[語音]"
-	case "video":
+	case messageTypeVideo:
 		return "[影片]"
-	case "location":
+	case messageTypeLocation:
 		return "[位置]"
 	default:
 		return messageText
@@ -860,11 +1835,16 @@ func generateLastMessagePreview(msgType, content string) string {
 func (s *Server) updateRoomLastMessage(ctx context.Context, req *chat.SendMessageRequest, message *chatroom.Message) {
 	// 生成預覽
 	lastMessagePreview := generateLastMessagePreview(req.Type, req.Content)
+	// 限時訊息過期後聊天室列表仍會保留預覽，因此不顯示內容
+	if message.ExpiresAt != nil {
+		lastMessagePreview = expiringMessageText
+	}
 
-	// 加密 last_message（系統訊息不加密）
+	// 加密 last_message（系統訊息不加密），同時記錄密鑰版本，密鑰輪換後仍可解密
 	encryptedLastMessage := lastMessagePreview
+	lastMessageKeyID := ""
 	if req.Type != systemSenderID {
-		encrypted, err := s.encryption.EncryptMessage(lastMessagePreview, req.RoomId)
+		encrypted, version, err := s.encryption.EncryptMessageWithVersion(lastMessagePreview, req.RoomId)
 		if err != nil {
 			logger.Error(ctx, "加密 last_message 失敗",
 				logger.WithRoomID(req.RoomId),
@@ -872,15 +1852,17 @@ func (s *Server) updateRoomLastMessage(ctx context.Context, req *chat.SendMessag
 			// 降級處理，使用明文
 		} else {
 			encryptedLastMessage = encrypted
+			lastMessageKeyID = chatroom.EncryptionKeyID(version)
 		}
 	}
 
-	// 更新聊天室
-	err := s.repos.ChatRoom.Update(ctx, req.RoomId, map[string]interface{}{
-		"last_message":      encryptedLastMessage,
-		"last_message_time": message.CreatedAt,
-		"last_message_at":   message.CreatedAt,
-		"updated_at":        message.CreatedAt,
+	// 更新聊天室（收到新訊息的封存聊天室會自動取消封存）
+	err := s.repos.ChatRoom.UpdateLastMessage(ctx, req.RoomId, map[string]interface{}{
+		"last_message":        encryptedLastMessage,
+		"last_message_key_id": lastMessageKeyID,
+		"last_message_time":   message.CreatedAt,
+		"last_message_at":     message.CreatedAt,
+		"updated_at":          message.CreatedAt,
 	})
 	if err != nil {
 		logger.Error(ctx, "更新聊天室最後訊息失敗",
@@ -897,11 +1879,10 @@ func (s *Server) buildMessageResponse(ctx context.Context, message *chatroom.Mes
 	// 解密內容
 	responseContent := message.Content
 	if message.Type != systemSenderID {
-		decrypted, err := s.encryption.DecryptMessage(message.Content, message.RoomID)
+		decrypted, err := s.encryption.DecryptMessageWithVersion(message.Content, message.RoomID, message.KeyVersion())
 		if err != nil {
-			logger.Warning(ctx, "返回消息時解密失敗",
+			logger.Sampled(ctx, logger.SeverityWarning, "返回消息時解密失敗", message.RoomID,
 				logger.WithMessageID(message.GetID()),
-				logger.WithRoomID(message.RoomID),
 				logger.WithDetails(map[string]interface{}{"error": err.Error()}))
 			responseContent = decryptFailedText
 		} else {
@@ -910,88 +1891,207 @@ func (s *Server) buildMessageResponse(ctx context.Context, message *chatroom.Mes
 	}
 
 	return &chat.ChatMessage{
-		Id:        message.GetID(),
-		RoomId:    message.RoomID,
-		SenderId:  message.SenderID,
-		Content:   responseContent,
-		Type:      message.Type,
-		CreatedAt: message.CreatedAt.Unix(),
-		UpdatedAt: message.UpdatedAt.Unix(),
-		ReadBy:    grpcReadBy,
+		Id:               message.GetID(),
+		RoomId:           message.RoomID,
+		SenderId:         message.SenderID,
+		SenderName:       senderNameFor(message.SenderID),
+		Content:          responseContent,
+		Type:             message.Type,
+		Metadata:         metadataToGRPC(&message.Metadata),
+		CreatedAt:        message.CreatedAt.Unix(),
+		UpdatedAt:        message.UpdatedAt.Unix(),
+		ReadBy:           grpcReadBy,
+		ReadReceipts:     cleanReadReceipts(message.ReadBy, message.SenderID),
+		Status:           storedMessageStatus(message),
+		DeliveredTo:      cleanDeliveredTo(message.DeliveredTo, message.SenderID),
+		Mentions:         message.Mentions,
+		ForwardedFrom:    forwardedFromToGRPC(message),
+		ExpiresAt:        expiresAtToGRPC(message),
+		ReplyToMessageId: message.ReplyToMessageID,
 	}
 }
 
-// initializeSeenMessages 初始化已見訊息集合
-func (s *Server) initializeSeenMessages(ctx context.Context, roomID string) map[string]bool {
-	seenMessageIDs := make(map[string]bool)
-
+// streamSeenSetLimits 讀取訊息流初始抓取數量及已見集合容量
+func streamSeenSetLimits() (initialFetchLimit, seenSetSize int) {
 	cfg := config.Get()
-	initialFetchLimit := 100
+	initialFetchLimit = 100
 	if cfg != nil && cfg.Limits.SSE.InitialMessageFetch > 0 {
 		initialFetchLimit = cfg.Limits.SSE.InitialMessageFetch
 	}
 
-	existingMessages, _, _, err := s.repos.Message.GetByRoomID(
-		ctx, roomID, initialFetchLimit, "", nil, nil,
-	)
-	if err == nil {
-		for _, msg := range existingMessages {
-			seenMessageIDs[msg.GetID()] = true
+	// 容量必須大於單次輪詢窗口，否則被淘汰的訊息會被重複推送
+	seenSetSize = constants.DefaultStreamSeenSetSize
+	if cfg != nil && cfg.Limits.SSE.SeenSetSize > 0 {
+		seenSetSize = cfg.Limits.SSE.SeenSetSize
+	}
+	if minSize := 2 * max(initialFetchLimit, constants.StreamFetchLimit); seenSetSize < minSize {
+		seenSetSize = minSize
+	}
+	return initialFetchLimit, seenSetSize
+}
+
+// keepaliveEnforcementPolicy 允許 HTTP 服務的 gRPC 客戶端在空閒連接上發送 keepalive ping
+// 默認策略要求 ping 間隔至少 5 分鐘，否則以 too_many_pings 斷開連接；這裡放寬到客戶端間隔的一半以容忍計時誤差
+func keepaliveEnforcementPolicy() keepalive.EnforcementPolicy {
+	seconds := constants.DefaultGRPCKeepaliveTimeSeconds
+	if cfg := config.Get(); cfg != nil && cfg.GRPC.Client.KeepaliveTimeSeconds > 0 {
+		seconds = cfg.GRPC.Client.KeepaliveTimeSeconds
+	}
+	return keepalive.EnforcementPolicy{
+		MinTime:             time.Duration(seconds) * time.Second / 2,
+		PermitWithoutStream: true,
+	}
+}
+
+// streamPollInterval 訊息流輪詢新訊息的間隔
+func streamPollInterval() time.Duration {
+	intervalMs := constants.DefaultStreamPollIntervalMs
+	if cfg := config.Get(); cfg != nil && cfg.Limits.SSE.PollIntervalMs > 0 {
+		intervalMs = max(cfg.Limits.SSE.PollIntervalMs, constants.MinStreamPollIntervalMs)
+	}
+	return time.Duration(intervalMs) * time.Millisecond
+}
+
+// streamRoomMessages 透過聊天室分發中心接收新訊息並推送，直到訊息流結束
+// seen 不為空時跳過其中的訊息（例如雙向聊天流中自己發送的訊息）
+func (s *Server) streamRoomMessages(
+	ctx context.Context,
+	req *chat.StreamMessagesRequest,
+	send func(*chat.ChatMessage) error,
+	seen *seenMessageSet,
+	membership *streamMembershipGuard,
+) error {
+	batches, unsubscribe := s.hub.Subscribe(req.RoomId)
+	defer unsubscribe()
+
+	// 記錄已推送的限時訊息，到期後推送過期事件
+	expiring := newExpiryTracker()
+	send = trackExpiring(expiring, send)
+
+	// 先訂閱再補發，補發期間產生的新訊息會在之後的批次中推送
+	if req.SinceCursor != "" {
+		if err := s.replayMessagesSince(ctx, req, send, seen); err != nil {
+			return err
 		}
-		logger.Info(ctx, "初始化訊息流，標記現有訊息",
-			logger.WithRoomID(roomID),
-			logger.WithDetails(map[string]interface{}{"existingCount": len(existingMessages)}))
 	}
 
-	return seenMessageIDs
+	// 成員資格檢查和在線狀態寫入都有自己的間隔，這裡只需要定期觸發
+	ticker := time.NewTicker(streamPollInterval())
+	defer ticker.Stop()
+
+	s.touchPresence(ctx, req.UserId)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+			if err := membership.Recheck(ctx); err != nil {
+				return err
+			}
+			s.touchPresence(ctx, req.UserId)
+			for _, event := range expiring.Due(time.Now()) {
+				if err := send(event); err != nil {
+					return err
+				}
+			}
+
+		case messages, ok := <-batches:
+			if !ok {
+				return status.Error(codes.Unavailable, "訊息流已中斷，請重新連接")
+			}
+			if err := s.sendMessageBatch(ctx, req, messages, send, seen); err != nil {
+				return err
+			}
+		}
+	}
 }
 
-// fetchAndStreamNewMessages 獲取並推送新訊息
-func (s *Server) fetchAndStreamNewMessages(
+// replayMessagesSince 補發游標之後的所有訊息（按時間升序），用於斷線重連
+func (s *Server) replayMessagesSince(
 	ctx context.Context,
 	req *chat.StreamMessagesRequest,
-	stream chat.ChatRoomService_StreamMessagesServer,
-	seenMessageIDs map[string]bool,
+	send func(*chat.ChatMessage) error,
+	seen *seenMessageSet,
 ) error {
-	messages, _, _, err := s.repos.Message.GetByRoomID(
-		ctx, req.RoomId, 100, "", nil, nil,
-	)
-	if err != nil {
-		logger.Error(ctx, "獲取新訊息失敗",
-			logger.WithRoomID(req.RoomId),
-			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
-		return nil // 不中斷流，繼續重試
+	cursor := req.SinceCursor
+	replayed := 0
+	for {
+		window, err := s.repos.Message.GetAround(ctx, req.RoomId, "", cursor, 0, constants.StreamFetchLimit)
+		if err != nil {
+			logErrorWithRoom(ctx, "補發斷線期間的訊息失敗", req.RoomId, err)
+			return status.Error(codes.Internal, "補發訊息失敗")
+		}
+		if err := s.sendMessageBatch(ctx, req, window.Messages, send, seen); err != nil {
+			return err
+		}
+		if seen != nil {
+			for _, msg := range window.Messages {
+				seen.Add(msg.GetID())
+			}
+		}
+		replayed += len(window.Messages)
+
+		if !window.HasAfter {
+			break
+		}
+		cursor = window.AfterCursor
 	}
 
-	newMessageCount := 0
+	logger.Info(ctx, "斷線續傳補發訊息",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithDetails(map[string]interface{}{"count": replayed}))
+	return nil
+}
+
+// sendMessageBatch 推送一批新訊息（按時間升序）並標記為已送達
+func (s *Server) sendMessageBatch(
+	ctx context.Context,
+	req *chat.StreamMessagesRequest,
+	messages []*chatroom.Message,
+	send func(*chat.ChatMessage) error,
+	seen *seenMessageSet,
+) error {
+	delivered := make([]string, 0, len(messages))
+	sent := 0
 	for _, msg := range messages {
-		if seenMessageIDs[msg.GetID()] {
+		if seen != nil && seen.Has(msg.GetID()) {
 			continue
 		}
-
-		seenMessageIDs[msg.GetID()] = true
-		newMessageCount++
-
-		if err := s.processAndSendMessage(ctx, msg, req.RoomId, stream); err != nil {
+		if err := s.processAndSendMessage(ctx, msg, req.RoomId, send); err != nil {
+			s.markStreamDelivered(ctx, req, delivered)
 			return err
 		}
+		sent++
+		if msg.SenderID != req.UserId {
+			delivered = append(delivered, msg.GetID())
+		}
 	}
+	s.markStreamDelivered(ctx, req, delivered)
 
-	if newMessageCount > 0 {
+	if sent > 0 {
 		logger.Info(ctx, "推送新訊息",
 			logger.WithRoomID(req.RoomId),
-			logger.WithDetails(map[string]interface{}{"count": newMessageCount}))
+			logger.WithDetails(map[string]interface{}{"count": sent}))
 	}
 
 	return nil
 }
 
+// fetchLatestMessages 獲取聊天室最新訊息（供分發中心輪詢）
+func (s *Server) fetchLatestMessages(ctx context.Context, roomID string, limit int) ([]*chatroom.Message, error) {
+	messages, _, _, err := s.repos.Message.GetByRoomID(ctx, roomID, limit, "", nil, nil)
+	return messages, err
+}
+
 // processAndSendMessage 處理並發送單個訊息
 func (s *Server) processAndSendMessage(
 	ctx context.Context,
 	msg *chatroom.Message,
 	roomID string,
-	stream chat.ChatRoomService_StreamMessagesServer,
+	send func(*chat.ChatMessage) error,
 ) error {
 	msgID := msg.GetID()
 
@@ -999,9 +2099,9 @@ func (s *Server) processAndSendMessage(
 	decryptedContent := msg.Content
 	if msg.Type != systemSenderID {
 		var err error
-		decryptedContent, err = s.encryption.DecryptMessage(msg.Content, roomID)
+		decryptedContent, err = s.encryption.DecryptMessageWithVersion(msg.Content, roomID, msg.KeyVersion())
 		if err != nil {
-			logger.Error(ctx, "解密訊息失敗",
+			logger.Sampled(ctx, logger.SeverityError, "解密訊息失敗", roomID,
 				logger.WithMessageID(msgID),
 				logger.WithDetails(map[string]interface{}{"error": err.Error()}))
 			decryptedContent = decryptFailedText
@@ -1016,25 +2116,32 @@ func (s *Server) processAndSendMessage(
 		decryptedContent = messageFormatErrorText
 	}
 
-	// 轉換 read_by
-	grpcReadBy := make([]string, len(msg.ReadBy))
-	for i, readBy := range msg.ReadBy {
-		grpcReadBy[i] = readBy.UserID
-	}
+	// 清理已讀信息（去重、排除發送者，與 GetMessages 一致）
+	grpcReadBy := cleanReadBy(msg.ReadBy, msg.SenderID)
 
 	// 構建並推送訊息
 	grpcMsg := &chat.ChatMessage{
-		Id:        msgID,
-		RoomId:    msg.RoomID,
-		SenderId:  msg.SenderID,
-		Content:   decryptedContent,
-		Type:      msg.Type,
-		CreatedAt: msg.CreatedAt.Unix(),
-		UpdatedAt: msg.UpdatedAt.Unix(),
-		ReadBy:    grpcReadBy,
+		Id:               msgID,
+		RoomId:           msg.RoomID,
+		SenderId:         msg.SenderID,
+		SenderName:       senderNameFor(msg.SenderID),
+		Content:          decryptedContent,
+		Type:             msg.Type,
+		Metadata:         metadataToGRPC(&msg.Metadata),
+		CreatedAt:        msg.CreatedAt.Unix(),
+		UpdatedAt:        msg.UpdatedAt.Unix(),
+		ReadBy:           grpcReadBy,
+		ReadReceipts:     cleanReadReceipts(msg.ReadBy, msg.SenderID),
+		Status:           storedMessageStatus(msg),
+		DeliveredTo:      cleanDeliveredTo(msg.DeliveredTo, msg.SenderID),
+		Mentions:         msg.Mentions,
+		ForwardedFrom:    forwardedFromToGRPC(msg),
+		ExpiresAt:        expiresAtToGRPC(msg),
+		Cursor:           chatroom.EncodeMessageCursor(msg.CreatedAt, msgID),
+		ReplyToMessageId: msg.ReplyToMessageID,
 	}
 
-	if err := stream.Send(grpcMsg); err != nil {
+	if err := send(grpcMsg); err != nil {
 		logger.Error(ctx, "推送訊息失敗",
 			logger.WithMessageID(msgID),
 			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
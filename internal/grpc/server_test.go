@@ -0,0 +1,299 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func stringPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool       { return &b }
+func int32Ptr(i int32) *int32    { return &i }
+
+func TestBuildRoomUpdate_OnlyProvidedFields(t *testing.T) {
+	req := &chat.UpdateRoomRequest{
+		RoomId: "room-1",
+		UserId: "owner",
+		Name:   stringPtr("  新名稱 "),
+		Settings: &chat.RoomSettingsUpdate{
+			AllowInvite: boolPtr(false),
+		},
+	}
+
+	update, changes := buildRoomUpdate(req, "")
+
+	if update["name"] != "新名稱" {
+		t.Errorf("name should be trimmed, got %v", update["name"])
+	}
+	if v, ok := update["settings.allow_invite"]; !ok || v != false {
+		t.Errorf("allow_invite should be set to false, got %v", v)
+	}
+	for _, key := range []string{"avatar_url", "settings", "settings.max_members", "settings.welcome_message"} {
+		if _, ok := update[key]; ok {
+			t.Errorf("omitted field %s should not be updated", key)
+		}
+	}
+	if len(update) != 2 {
+		t.Errorf("expected 2 updated fields, got %v", update)
+	}
+	if len(changes) != 2 || !strings.Contains(changes[0], "聊天室名稱已變更") {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+}
+
+func TestBuildRoomUpdate_Empty(t *testing.T) {
+	update, changes := buildRoomUpdate(&chat.UpdateRoomRequest{
+		RoomId:   "room-1",
+		Settings: &chat.RoomSettingsUpdate{},
+	}, "")
+	if len(update) != 0 || len(changes) != 0 {
+		t.Errorf("expected no changes, got %v %v", update, changes)
+	}
+}
+
+func TestBuildRoomUpdate_Locale(t *testing.T) {
+	update, changes := buildRoomUpdate(&chat.UpdateRoomRequest{
+		RoomId:   "room-1",
+		Name:     stringPtr("General"),
+		Settings: &chat.RoomSettingsUpdate{Locale: stringPtr("EN")},
+	}, "en")
+	if update["settings.locale"] != "en" {
+		t.Errorf("locale should be stored lowercased, got %v", update["settings.locale"])
+	}
+	if len(changes) != 2 || changes[0] != `Room name changed to "General"` {
+		t.Errorf("changes should use the room locale, got %v", changes)
+	}
+}
+
+func TestCanManageRoom(t *testing.T) {
+	room := &chatroom.ChatRoom{
+		OwnerID: "owner",
+		Members: []chatroom.RoomMember{
+			{UserID: "owner", Role: "member"},
+			{UserID: "admin", Role: roleAdmin},
+			{UserID: "member", Role: "member"},
+		},
+	}
+
+	tests := map[string]bool{
+		"owner":    true,
+		"admin":    true,
+		"member":   false,
+		"stranger": false,
+	}
+	for userID, want := range tests {
+		if got := canManageRoom(room, userID); got != want {
+			t.Errorf("canManageRoom(%s) = %v, want %v", userID, got, want)
+		}
+	}
+}
+
+func TestPartitionUnreadRooms(t *testing.T) {
+	lastRead := time.Unix(1700000000, 0)
+	rooms := []*chatroom.ChatRoom{
+		{ID: "room-1", Members: []chatroom.RoomMember{{UserID: "alice", LastReadAt: lastRead}}},
+		{ID: "room-2", Members: []chatroom.RoomMember{{UserID: "bob"}}},
+	}
+
+	sinceByRoom, receiptRooms := partitionUnreadRooms(rooms, "alice", constants.UnreadCountFast)
+	if len(sinceByRoom) != 1 || !sinceByRoom["room-1"].Equal(lastRead) {
+		t.Errorf("Expected room-1 counted from LastReadAt, got %v", sinceByRoom)
+	}
+	if len(receiptRooms) != 1 || receiptRooms[0] != "room-2" {
+		t.Errorf("Expected room without membership counted by receipts, got %v", receiptRooms)
+	}
+
+	sinceByRoom, receiptRooms = partitionUnreadRooms(rooms, "alice", constants.UnreadCountPrecise)
+	if len(sinceByRoom) != 0 || len(receiptRooms) != 2 {
+		t.Errorf("Expected precise mode to count every room by receipts, got %v / %v", sinceByRoom, receiptRooms)
+	}
+}
+
+func TestValidateRoomUpdate(t *testing.T) {
+	room := &chatroom.ChatRoom{
+		Members: []chatroom.RoomMember{{UserID: "a"}, {UserID: "b"}, {UserID: "c"}},
+	}
+
+	tests := []struct {
+		name    string
+		req     *chat.UpdateRoomRequest
+		wantErr bool
+	}{
+		{"no fields", &chat.UpdateRoomRequest{}, false},
+		{"valid name", &chat.UpdateRoomRequest{Name: stringPtr("general")}, false},
+		{"empty name", &chat.UpdateRoomRequest{Name: stringPtr("   ")}, true},
+		{"null character", &chat.UpdateRoomRequest{Name: stringPtr("bad\x00name")}, true},
+		{"valid max members", &chat.UpdateRoomRequest{Settings: &chat.RoomSettingsUpdate{MaxMembers: int32Ptr(10)}}, false},
+		{"zero max members", &chat.UpdateRoomRequest{Settings: &chat.RoomSettingsUpdate{MaxMembers: int32Ptr(0)}}, true},
+		{"below member count", &chat.UpdateRoomRequest{Settings: &chat.RoomSettingsUpdate{MaxMembers: int32Ptr(2)}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRoomUpdate(tt.req, room)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRoomUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeWindowLimits(t *testing.T) {
+	tests := []struct {
+		name                  string
+		before, after         int
+		wantBefore, wantAfter int
+	}{
+		{"defaults split evenly", 0, 0, 10, 10},
+		{"only older", 5, 0, 5, 0},
+		{"only newer", -1, 5, 0, 5},
+		{"capped at max page size", 1000, 1000, 100, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, after := normalizeWindowLimits(tt.before, tt.after)
+			if before != tt.wantBefore || after != tt.wantAfter {
+				t.Errorf("normalizeWindowLimits(%d, %d) = (%d, %d), want (%d, %d)",
+					tt.before, tt.after, before, after, tt.wantBefore, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestCleanDeliveredTo(t *testing.T) {
+	now := time.Now()
+	deliveredTo := []chatroom.MessageDeliveredTo{
+		{UserID: "sender", DeliveredAt: now},
+		{UserID: "user-1", DeliveredAt: now},
+		{UserID: "user-2", DeliveredAt: now},
+		{UserID: "user-1", DeliveredAt: now.Add(time.Second)}, // 重連後的重複記錄
+	}
+
+	got := cleanDeliveredTo(deliveredTo, "sender")
+	if len(got) != 2 || got[0] != "user-1" || got[1] != "user-2" {
+		t.Errorf("cleanDeliveredTo() = %v, want [user-1 user-2]", got)
+	}
+}
+
+func TestCleanReadReceipts(t *testing.T) {
+	first := time.Unix(1700000000, 0)
+	readBy := []chatroom.MessageReadBy{
+		{UserID: "sender", ReadAt: first},
+		{UserID: "user-1", ReadAt: first.Add(time.Minute)},
+		{UserID: "user-2", ReadAt: first},
+		{UserID: "user-1", ReadAt: first}, // 重複記錄保留最早的已讀時間
+		{UserID: "user-3"},                // 舊資料沒有已讀時間
+	}
+
+	got := cleanReadReceipts(readBy, "sender")
+	want := []*chat.ReadReceipt{
+		{UserId: "user-1", ReadAt: first.Unix()},
+		{UserId: "user-2", ReadAt: first.Unix()},
+		{UserId: "user-3", ReadAt: 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("cleanReadReceipts() returned %d receipts, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].UserId != want[i].UserId || got[i].ReadAt != want[i].ReadAt {
+			t.Errorf("receipt %d = {%s %d}, want {%s %d}", i, got[i].UserId, got[i].ReadAt, want[i].UserId, want[i].ReadAt)
+		}
+	}
+
+	// 與 read_by 保持一致
+	readByIDs := cleanReadBy(readBy, "sender")
+	for i, userID := range readByIDs {
+		if got[i].UserId != userID {
+			t.Errorf("read_by[%d] = %s, read_receipts[%d] = %s", i, userID, i, got[i].UserId)
+		}
+	}
+}
+
+func TestSendMessage_RejectsInvalidContent(t *testing.T) {
+	s := &Server{}
+
+	tests := []struct {
+		name    string
+		content string
+		msgType string
+	}{
+		{"empty text", "", ""},
+		{"whitespace text", "   ", messageTypeText},
+		{"too long", strings.Repeat("a", constants.DefaultMaxMessageLength+1), messageTypeText},
+		{"nul character", "hi\x00there", messageTypeText},
+		{"image caption with nul", "\x00", messageTypeImage},
+		{"invalid utf-8", "bad \xff bytes", messageTypeText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.SendMessage(context.Background(), &chat.SendMessageRequest{
+				RoomId:   "507f1f77bcf86cd799439011",
+				SenderId: "user_alice",
+				Content:  tt.content,
+				Type:     tt.msgType,
+			})
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("SendMessage() error = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestSendMessage_RejectsLongIdempotencyKey(t *testing.T) {
+	s := &Server{}
+
+	resp, err := s.SendMessage(context.Background(), &chat.SendMessageRequest{
+		RoomId:         "507f1f77bcf86cd799439011",
+		SenderId:       "user_alice",
+		Content:        "hello",
+		IdempotencyKey: strings.Repeat("k", constants.MaxIdempotencyKeyLength+1),
+	})
+	if err != nil || resp.Success {
+		t.Errorf("SendMessage() = %v, %v; want failure response", resp, err)
+	}
+}
+
+func TestValidateSendContent_MediaCaptionOptional(t *testing.T) {
+	req := &chat.SendMessageRequest{Type: messageTypeImage}
+	if err := validateSendContent(req); err != nil {
+		t.Errorf("validateSendContent() error = %v, want nil for image without caption", err)
+	}
+}
+
+func TestDeleteRoom_RejectsImpersonatedOwner(t *testing.T) {
+	// 認證用戶冒充擁有者：應在讀取聊天室之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.DeleteRoom(ctx, &chat.DeleteRoomRequest{
+		RoomId: "507f1f77bcf86cd799439011",
+		UserId: "owner",
+	})
+	if err != nil || resp.Success {
+		t.Errorf("DeleteRoom() = %+v, %v; want Success=false", resp, err)
+	}
+}
+
+func TestUpdateRoom_RejectsImpersonatedAdmin(t *testing.T) {
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.UpdateRoom(ctx, &chat.UpdateRoomRequest{
+		RoomId: "507f1f77bcf86cd799439011",
+		UserId: "owner",
+		Name:   stringPtr("renamed"),
+	})
+	if err != nil || resp.Success {
+		t.Errorf("UpdateRoom() = %+v, %v; want Success=false", resp, err)
+	}
+}
@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/proto/chat"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamMembershipGuard 訊息流的成員資格檢查
+// 建立訊息流時檢查一次，之後定期重新檢查，被移出聊天室的用戶會被斷開
+type streamMembershipGuard struct {
+	isMember  func(ctx context.Context, roomID, userID string) (bool, error)
+	roomID    string
+	userID    string
+	interval  time.Duration
+	lastCheck time.Time
+	now       func() time.Time
+}
+
+// newStreamMembershipGuard 創建訊息流成員資格檢查
+func (s *Server) newStreamMembershipGuard(roomID, userID string) *streamMembershipGuard {
+	return &streamMembershipGuard{
+		isMember: s.repos.ChatRoom.IsMember,
+		roomID:   roomID,
+		userID:   userID,
+		interval: streamMembershipCheckInterval(),
+		now:      time.Now,
+	}
+}
+
+// verifyStreamMembership 建立訊息流前檢查成員資格，返回用於定期重新檢查的 guard
+func (s *Server) verifyStreamMembership(ctx context.Context, req *chat.StreamMessagesRequest) (*streamMembershipGuard, error) {
+	// 啟用 JWT 時按認證用戶檢查成員資格，被移出的用戶不能借用其他成員的 ID 重新連接
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "stream messages: user is not the authenticated user")
+		return nil, status.Error(codes.PermissionDenied, "只能訂閱自己的訊息流")
+	}
+
+	guard := s.newStreamMembershipGuard(req.RoomId, req.UserId)
+	if err := guard.Verify(ctx); err != nil {
+		if status.Code(err) == codes.PermissionDenied {
+			s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "stream messages: not a room member")
+		}
+		return nil, err
+	}
+	return guard, nil
+}
+
+// streamMembershipCheckInterval 讀取成員資格重新檢查間隔
+func streamMembershipCheckInterval() time.Duration {
+	seconds := constants.DefaultStreamMembershipCheckIntervalSec
+	if cfg := config.Get(); cfg != nil && cfg.Limits.SSE.MembershipCheckInterval > 0 {
+		seconds = cfg.Limits.SSE.MembershipCheckInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Verify 建立訊息流時檢查成員資格，非成員或無法確認時拒絕
+func (g *streamMembershipGuard) Verify(ctx context.Context) error {
+	if g.userID == "" {
+		return status.Error(codes.InvalidArgument, "缺少 user_id")
+	}
+
+	isMember, err := g.isMember(ctx, g.roomID, g.userID)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "檢查訊息流成員資格失敗", g.userID, g.roomID, err)
+		return status.Error(codes.Internal, "檢查成員資格失敗")
+	}
+	if !isMember {
+		return status.Error(codes.PermissionDenied, "您不是此聊天室的成員")
+	}
+
+	g.lastCheck = g.now()
+	return nil
+}
+
+// Recheck 距離上次檢查超過間隔時重新檢查，用戶已被移出聊天室時返回錯誤
+// 查詢失敗時保留訊息流，等下一個間隔再檢查（避免資料庫短暫故障斷開所有訊息流）
+func (g *streamMembershipGuard) Recheck(ctx context.Context) error {
+	now := g.now()
+	if now.Sub(g.lastCheck) < g.interval {
+		return nil
+	}
+	g.lastCheck = now
+
+	isMember, err := g.isMember(ctx, g.roomID, g.userID)
+	if err != nil {
+		logger.Warning(ctx, "重新檢查訊息流成員資格失敗",
+			logger.WithUserID(g.userID),
+			logger.WithRoomID(g.roomID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return nil
+	}
+	if !isMember {
+		logger.Info(ctx, "用戶已不是聊天室成員，結束訊息流",
+			logger.WithUserID(g.userID),
+			logger.WithRoomID(g.roomID))
+		return status.Error(codes.PermissionDenied, "您已不是此聊天室的成員")
+	}
+
+	return nil
+}
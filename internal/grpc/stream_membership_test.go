@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/proto/chat"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeMembership 可控制的成員資格查詢
+type fakeMembership struct {
+	member bool
+	err    error
+	calls  int
+}
+
+func (f *fakeMembership) isMember(_ context.Context, _, _ string) (bool, error) {
+	f.calls++
+	return f.member, f.err
+}
+
+func newTestMembershipGuard(f *fakeMembership, now *time.Time) *streamMembershipGuard {
+	return &streamMembershipGuard{
+		isMember: f.isMember,
+		roomID:   "room-1",
+		userID:   "user-1",
+		interval: 30 * time.Second,
+		now:      func() time.Time { return *now },
+	}
+}
+
+func TestStreamMembershipGuard_RejectsNonMember(t *testing.T) {
+	now := time.Now()
+	guard := newTestMembershipGuard(&fakeMembership{member: false}, &now)
+
+	if err := guard.Verify(context.Background()); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied for non-member, got %v", err)
+	}
+}
+
+func TestStreamMembershipGuard_RejectsMissingUser(t *testing.T) {
+	now := time.Now()
+	f := &fakeMembership{member: true}
+	guard := newTestMembershipGuard(f, &now)
+	guard.userID = ""
+
+	if err := guard.Verify(context.Background()); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without user_id, got %v", err)
+	}
+	if f.calls != 0 {
+		t.Errorf("expected no membership lookup without user_id, got %d", f.calls)
+	}
+}
+
+func TestVerifyStreamMembership_RejectsImpersonatedMember(t *testing.T) {
+	// 被移出的用戶借用其他成員的 ID 重新連接：應在查詢成員資格之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	_, err := s.verifyStreamMembership(ctx, &chat.StreamMessagesRequest{RoomId: "room-1", UserId: "user-1"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied for impersonated member, got %v", err)
+	}
+}
+
+func TestStreamMembershipGuard_RemovedMidStream(t *testing.T) {
+	now := time.Now()
+	f := &fakeMembership{member: true}
+	guard := newTestMembershipGuard(f, &now)
+	ctx := context.Background()
+
+	if err := guard.Verify(ctx); err != nil {
+		t.Fatalf("Verify failed for member: %v", err)
+	}
+
+	// 成員被移出，但未到重新檢查間隔
+	f.member = false
+	now = now.Add(10 * time.Second)
+	if err := guard.Recheck(ctx); err != nil {
+		t.Errorf("expected no recheck before interval, got %v", err)
+	}
+	if f.calls != 1 {
+		t.Errorf("expected a single lookup before interval, got %d", f.calls)
+	}
+
+	// 到達間隔後重新檢查，訊息流被終止
+	now = now.Add(30 * time.Second)
+	if err := guard.Recheck(ctx); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied after removal, got %v", err)
+	}
+}
+
+func TestStreamMembershipGuard_TransientErrorKeepsStream(t *testing.T) {
+	now := time.Now()
+	f := &fakeMembership{member: true}
+	guard := newTestMembershipGuard(f, &now)
+	ctx := context.Background()
+
+	if err := guard.Verify(ctx); err != nil {
+		t.Fatalf("Verify failed for member: %v", err)
+	}
+
+	f.err = errors.New("db unavailable")
+	now = now.Add(time.Minute)
+	if err := guard.Recheck(ctx); err != nil {
+		t.Errorf("expected transient error to keep stream open, got %v", err)
+	}
+
+	// 建立訊息流時無法確認成員資格則拒絕
+	fresh := newTestMembershipGuard(f, &now)
+	if err := fresh.Verify(ctx); status.Code(err) != codes.Internal {
+		t.Errorf("expected Internal when membership cannot be verified, got %v", err)
+	}
+}
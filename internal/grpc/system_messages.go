@@ -0,0 +1,133 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+)
+
+// builtinSystemMessages 內置的系統訊息模板（語言 -> 模板鍵 -> 文字）
+// 其他語言或修改措辭通過 limits.system_message.templates 配置，缺少的鍵回退到默認語言
+var builtinSystemMessages = map[string]map[string]string{
+	"zh-tw": {
+		constants.SystemMessageMemberJoined:     "{user} 已加入群組",
+		constants.SystemMessageMemberLeft:       "{user} 已離開群組",
+		constants.SystemMessageMemberKicked:     "{user} 已被移出群組",
+		constants.SystemMessageOwnerChanged:     "{user} 已成為群組擁有者",
+		constants.SystemMessageRoomRenamed:      "聊天室名稱已變更為「{name}」",
+		constants.SystemMessageAvatarChanged:    "聊天室頭像已變更",
+		constants.SystemMessageSettingsChanged:  "聊天室設置已變更",
+		constants.SystemMessageChangeSeparator:  "，",
+		constants.SystemMessageModeNormal:       "聊天室已恢復普通模式",
+		constants.SystemMessageModeSlowmode:     "聊天室已開啟慢速模式（每 {seconds} 秒可發言一次）",
+		constants.SystemMessageModeAnnouncement: "聊天室已切換為公告模式，只有擁有者和管理員可以發言",
+		constants.SystemMessageModeReadOnly:     "聊天室已切換為只讀模式",
+	},
+	"en": {
+		constants.SystemMessageMemberJoined:     "{user} joined the group",
+		constants.SystemMessageMemberLeft:       "{user} left the group",
+		constants.SystemMessageMemberKicked:     "{user} was removed from the group",
+		constants.SystemMessageOwnerChanged:     "{user} is now the group owner",
+		constants.SystemMessageRoomRenamed:      "Room name changed to \"{name}\"",
+		constants.SystemMessageAvatarChanged:    "Room avatar changed",
+		constants.SystemMessageSettingsChanged:  "Room settings changed",
+		constants.SystemMessageChangeSeparator:  "; ",
+		constants.SystemMessageModeNormal:       "Room is back to normal mode",
+		constants.SystemMessageModeSlowmode:     "Slow mode is on (one message every {seconds} seconds)",
+		constants.SystemMessageModeAnnouncement: "Room switched to announcement mode, only the owner and admins can post",
+		constants.SystemMessageModeReadOnly:     "Room switched to read-only mode",
+	},
+}
+
+// systemMessageText 按語言渲染系統訊息，vars 替換模板中的 {佔位符}
+func systemMessageText(locale, key string, vars map[string]string) string {
+	text := systemMessageTemplate(locale, key)
+	if len(vars) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, len(vars)*2)
+	for name, value := range vars {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// systemMessageTemplate 查找模板：依次嘗試 localeFallbacks 中的語言，同一語言配置的模板優先於內置模板
+func systemMessageTemplate(locale, key string) string {
+	var templates map[string]map[string]string
+	if cfg := config.Get(); cfg != nil {
+		templates = cfg.Limits.SystemMessage.Templates
+	}
+	for _, candidate := range localeFallbacks(locale) {
+		if text, ok := templates[candidate][key]; ok {
+			return text
+		}
+		if text, ok := builtinSystemMessages[candidate][key]; ok {
+			return text
+		}
+	}
+	return builtinSystemMessages[constants.DefaultSystemMessageLocale][key]
+}
+
+// localeFallbacks 語言的回退順序：聊天室語言 → 其基礎語言（en-us → en）→ 配置的默認語言 → zh-tw
+// 語言標籤不區分大小寫（viper 讀取配置時會把 map 的鍵轉成小寫）
+func localeFallbacks(locale string) []string {
+	chain := make([]string, 0, 5)
+	add := func(candidate string) {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if candidate == "" {
+			return
+		}
+		for _, existing := range chain {
+			if existing == candidate {
+				return
+			}
+		}
+		chain = append(chain, candidate)
+	}
+	withBase := func(candidate string) {
+		add(candidate)
+		if base, _, found := strings.Cut(candidate, "-"); found {
+			add(base)
+		}
+	}
+
+	withBase(locale)
+	withBase(defaultSystemMessageLocale())
+	add(constants.DefaultSystemMessageLocale)
+	return chain
+}
+
+// defaultSystemMessageLocale 讀取配置的默認語言（默認 zh-tw）
+func defaultSystemMessageLocale() string {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.SystemMessage.DefaultLocale != "" {
+		return cfg.Limits.SystemMessage.DefaultLocale
+	}
+	return constants.DefaultSystemMessageLocale
+}
+
+// senderNameFor 訊息的發送者顯示名稱（只有系統訊息使用配置的名稱，其他發送者返回空）
+func senderNameFor(senderID string) string {
+	if senderID != systemSenderID {
+		return ""
+	}
+	if cfg := config.Get(); cfg != nil {
+		return cfg.Limits.SystemMessage.SenderName
+	}
+	return ""
+}
+
+// roomLocale 讀取聊天室的系統訊息語言，讀取失敗時返回空（使用默認語言）
+func (s *Server) roomLocale(ctx context.Context, roomID string) string {
+	settings, err := s.repos.ChatRoom.GetSettings(ctx, roomID)
+	if err != nil {
+		logger.Warning(ctx, "讀取聊天室語言失敗，使用默認語言",
+			logger.WithRoomID(roomID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return ""
+	}
+	return settings.Locale
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"reflect"
+	"testing"
+
+	"chat-gateway/internal/constants"
+)
+
+func TestLocaleFallbacks(t *testing.T) {
+	tests := map[string][]string{
+		"":      {"zh-tw", "zh"},
+		"en-US": {"en-us", "en", "zh-tw", "zh"},
+		"ZH-TW": {"zh-tw", "zh"},
+	}
+	for locale, want := range tests {
+		if got := localeFallbacks(locale); !reflect.DeepEqual(got, want) {
+			t.Errorf("localeFallbacks(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
+
+func TestSystemMessageText(t *testing.T) {
+	tests := []struct {
+		locale string
+		key    string
+		vars   map[string]string
+		want   string
+	}{
+		{"", constants.SystemMessageMemberJoined, map[string]string{"user": "alice"}, "alice 已加入群組"},
+		{"en-GB", constants.SystemMessageMemberLeft, map[string]string{"user": "bob"}, "bob left the group"},
+		{"en", constants.SystemMessageModeSlowmode, map[string]string{"seconds": "30"}, "Slow mode is on (one message every 30 seconds)"},
+		// 沒有內置模板的語言回退到默認語言
+		{"fr", constants.SystemMessageAvatarChanged, nil, "聊天室頭像已變更"},
+	}
+	for _, tt := range tests {
+		if got := systemMessageText(tt.locale, tt.key, tt.vars); got != tt.want {
+			t.Errorf("systemMessageText(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestBuiltinSystemMessages_CoverAllKeys(t *testing.T) {
+	zh := builtinSystemMessages[constants.DefaultSystemMessageLocale]
+	for locale, messages := range builtinSystemMessages {
+		if len(messages) != len(zh) {
+			t.Errorf("locale %s has %d templates, want %d", locale, len(messages), len(zh))
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/database"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// errReplyTargetNotFound 回覆的訊息不存在、已過期或不在同一聊天室
+var errReplyTargetNotFound = errors.New("回覆的訊息不存在")
+
+// checkReplyTarget 檢查回覆的訊息：必須是同一聊天室中未過期的訊息（未指定時不檢查）
+func (s *Server) checkReplyTarget(ctx context.Context, req *chat.SendMessageRequest) error {
+	if req.ReplyToMessageId == "" {
+		return nil
+	}
+	if err := database.ValidateObjectID(req.ReplyToMessageId); err != nil {
+		return errors.New("無效的回覆訊息 ID")
+	}
+
+	target, err := s.repos.Message.GetByID(ctx, req.ReplyToMessageId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return errReplyTargetNotFound
+	}
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取回覆的訊息失敗", req.SenderId, req.RoomId, err)
+		return errors.New("獲取回覆的訊息失敗")
+	}
+	if target.RoomID != req.RoomId || target.Expired(time.Now()) {
+		return errReplyTargetNotFound
+	}
+	return nil
+}
+
+// applyReplyCounts 填充訊息的直接回覆數量；統計失敗時回覆數量保持為 0
+func (s *Server) applyReplyCounts(ctx context.Context, roomID string, grpcMessages []*chat.ChatMessage) {
+	if len(grpcMessages) == 0 {
+		return
+	}
+
+	ids := make([]string, len(grpcMessages))
+	for i, msg := range grpcMessages {
+		ids[i] = msg.Id
+	}
+	counts, err := s.repos.Message.CountReplies(ctx, roomID, ids)
+	if err != nil {
+		logger.Warning(ctx, "統計回覆數量失敗",
+			logger.WithRoomID(roomID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	for _, msg := range grpcMessages {
+		msg.ReplyCount = int32(counts[msg.Id]) // #nosec G115 -- bounded by the page size
+	}
+}
+
+// GetThread 獲取討論串：回覆鏈最終指向根訊息的所有回覆（解密後按創建時間正序分頁）
+// 只有聊天室成員可以讀取；根訊息不存在、已過期或不在該聊天室時返回 not_found
+func (s *Server) GetThread(ctx context.Context, req *chat.GetThreadRequest) (*chat.GetThreadResponse, error) {
+	if err := middleware.ValidateRoomID(req.RoomId); err != nil {
+		return getThreadFailure(constants.ErrorCodeInvalidArgument, err.Error()), nil
+	}
+	if err := database.ValidateObjectID(req.RootMessageId); err != nil {
+		return getThreadFailure(constants.ErrorCodeInvalidArgument, "無效的訊息 ID"), nil
+	}
+	if err := middleware.ValidateUserID(req.UserId); err != nil {
+		return getThreadFailure(constants.ErrorCodeInvalidArgument, err.Error()), nil
+	}
+	if req.Cursor != "" {
+		if err := chatroom.ValidateMessageCursor(req.Cursor); err != nil {
+			return getThreadFailure(constants.ErrorCodeInvalidArgument, "無效的游標"), nil
+		}
+	}
+	// 啟用 JWT 時按認證用戶檢查成員資格，避免借用其他成員的 ID 讀取討論串
+	if !actingAsAuthenticatedUser(ctx, req.UserId) {
+		s.audit.LogAccessDenied(ctx, middleware.UserIDFromContext(ctx), req.RoomId, "get thread: user is not the authenticated user")
+		return getThreadFailure(constants.ErrorCodePermissionDenied, "您不是該聊天室的成員"), nil
+	}
+
+	isMember, err := s.repos.ChatRoom.IsMember(ctx, req.RoomId, req.UserId)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "檢查成員資格失敗", req.UserId, req.RoomId, err)
+		return getThreadFailure(constants.ErrorCodeInternal, "檢查成員資格失敗"), nil
+	}
+	if !isMember {
+		s.audit.LogAccessDenied(ctx, req.UserId, req.RoomId, "get thread: not a member")
+		return getThreadFailure(constants.ErrorCodePermissionDenied, "您不是該聊天室的成員"), nil
+	}
+
+	root, err := s.repos.Message.GetByID(ctx, req.RootMessageId)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && (root.RoomID != req.RoomId || root.Expired(time.Now()))) {
+		return getThreadFailure(constants.ErrorCodeNotFound, "訊息不存在"), nil
+	}
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取根訊息失敗", req.UserId, req.RoomId, err)
+		return getThreadFailure(constants.ErrorCodeInternal, "獲取討論串失敗"), nil
+	}
+
+	replies, nextCursor, hasMore, err := s.repos.Message.GetThread(ctx, req.RoomId, req.RootMessageId, int(req.Limit), req.Cursor)
+	if err != nil {
+		logErrorWithUserAndRoom(ctx, "獲取討論串失敗", req.UserId, req.RoomId, err)
+		return getThreadFailure(constants.ErrorCodeInternal, "獲取討論串失敗"), nil
+	}
+
+	// 第一頁同時返回根訊息，統計回覆數量時一併查詢
+	messages := replies
+	if req.Cursor == "" {
+		messages = append([]*chatroom.Message{root}, replies...)
+	}
+	grpcMessages := s.convertMessagesToGRPC(ctx, messages)
+	s.applyAggregateStatus(ctx, req.RoomId, messages, grpcMessages)
+	s.applyReplyCounts(ctx, req.RoomId, grpcMessages)
+
+	resp := &chat.GetThreadResponse{
+		Success:    true,
+		Message:    "獲取討論串成功",
+		Messages:   grpcMessages,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
+	}
+	if req.Cursor == "" {
+		resp.Root, resp.Messages = grpcMessages[0], grpcMessages[1:]
+	}
+
+	logger.Debug(ctx, "獲取討論串成功",
+		logger.WithUserID(req.UserId),
+		logger.WithRoomID(req.RoomId),
+		logger.WithMessageID(req.RootMessageId),
+		logger.WithAction("get_thread"),
+		logger.WithDetails(map[string]interface{}{
+			"count":    len(resp.Messages),
+			"has_more": hasMore,
+		}))
+
+	return resp, nil
+}
+
+// getThreadFailure 構建失敗響應
+func getThreadFailure(code, message string) *chat.GetThreadResponse {
+	return &chat.GetThreadResponse{
+		Success:   false,
+		Message:   message,
+		ErrorCode: code,
+	}
+}
@@ -0,0 +1,68 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
+	"chat-gateway/proto/chat"
+)
+
+func TestGetThread_InvalidRequest(t *testing.T) {
+	const (
+		roomID    = "507f1f77bcf86cd799439011"
+		messageID = "507f1f77bcf86cd799439012"
+	)
+	tests := []struct {
+		name string
+		req  *chat.GetThreadRequest
+	}{
+		{"missing room", &chat.GetThreadRequest{UserId: "alice", RootMessageId: messageID}},
+		{"invalid root message", &chat.GetThreadRequest{RoomId: roomID, UserId: "alice", RootMessageId: "not-an-id"}},
+		{"missing user", &chat.GetThreadRequest{RoomId: roomID, RootMessageId: messageID}},
+		{"invalid cursor", &chat.GetThreadRequest{RoomId: roomID, UserId: "alice", RootMessageId: messageID, Cursor: "%%%"}},
+	}
+
+	s := &Server{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.GetThread(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("GetThread() error = %v", err)
+			}
+			if resp.Success || resp.ErrorCode != constants.ErrorCodeInvalidArgument {
+				t.Errorf("GetThread() = %+v, want invalid_argument failure", resp)
+			}
+		})
+	}
+}
+
+func TestGetThread_RejectsImpersonatedMember(t *testing.T) {
+	// 認證用戶借用成員的 ID：應在檢查成員資格之前被拒絕
+	s := &Server{audit: audit.NewAuditService(false)}
+	ctx := middleware.ContextWithUserID(context.Background(), "mallory")
+	resp, err := s.GetThread(ctx, &chat.GetThreadRequest{
+		RoomId:        "507f1f77bcf86cd799439011",
+		RootMessageId: "507f1f77bcf86cd799439012",
+		UserId:        "user_alice",
+	})
+	if err != nil {
+		t.Fatalf("GetThread returned error: %v", err)
+	}
+	if resp.Success || resp.ErrorCode != constants.ErrorCodePermissionDenied {
+		t.Errorf("GetThread() = success %v, code %q, want permission_denied", resp.Success, resp.ErrorCode)
+	}
+}
+
+func TestCheckReplyTarget_Validation(t *testing.T) {
+	s := &Server{}
+
+	if err := s.checkReplyTarget(context.Background(), &chat.SendMessageRequest{RoomId: "room-1"}); err != nil {
+		t.Errorf("checkReplyTarget() without reply = %v, want nil", err)
+	}
+	if err := s.checkReplyTarget(context.Background(), &chat.SendMessageRequest{RoomId: "room-1", ReplyToMessageId: "not-an-id"}); err == nil {
+		t.Error("checkReplyTarget() with invalid ID = nil, want error")
+	}
+}
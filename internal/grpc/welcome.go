@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/storage/database/chatroom"
+	"chat-gateway/proto/chat"
+)
+
+// postWelcomeMessage 以系統訊息發送聊天室的歡迎訊息（私聊或未設置歡迎訊息時跳過）
+func (s *Server) postWelcomeMessage(ctx context.Context, roomID, roomType, welcome string) {
+	if roomType == roomTypeDirect || strings.TrimSpace(welcome) == "" {
+		return
+	}
+
+	message := chatroom.NewMessage()
+	message.RoomID = roomID
+	message.SenderID = systemSenderID
+	message.Content = welcome
+	message.Type = systemSenderID
+
+	if err := s.repos.Message.Create(ctx, &message); err != nil {
+		logger.Warning(ctx, "發送歡迎訊息失敗",
+			logger.WithRoomID(roomID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	s.updateRoomLastMessage(ctx, &chat.SendMessageRequest{
+		RoomId:   roomID,
+		SenderId: systemSenderID,
+		Content:  welcome,
+		Type:     systemSenderID,
+	}, &message)
+}
+
+// welcomeNewMember 新成員加入後再次發送歡迎訊息（welcome_message_mode 為 on_join 時）
+func (s *Server) welcomeNewMember(ctx context.Context, roomID string) {
+	if welcomeMessageMode() != constants.WelcomeMessageOnJoin {
+		return
+	}
+
+	room, err := s.repos.ChatRoom.GetByID(ctx, roomID)
+	if err != nil {
+		logger.Warning(ctx, "讀取聊天室歡迎訊息失敗",
+			logger.WithRoomID(roomID),
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return
+	}
+	s.postWelcomeMessage(ctx, roomID, room.Type, room.Settings.WelcomeMessage)
+}
+
+// welcomeMessageMode 讀取歡迎訊息的發送方式（默認只在創建聊天室時發送一次）
+func welcomeMessageMode() string {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Room.WelcomeMessageMode != "" {
+		return cfg.Limits.Room.WelcomeMessageMode
+	}
+	return constants.WelcomeMessageOnce
+}
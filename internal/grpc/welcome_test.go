@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"chat-gateway/internal/constants"
+)
+
+func TestPostWelcomeMessage_SkipsDirectAndEmpty(t *testing.T) {
+	// 沒有倉儲的 Server 一旦嘗試寫入就會 panic，跳過的情況不應觸及資料庫
+	s := &Server{}
+
+	s.postWelcomeMessage(context.Background(), "507f1f77bcf86cd799439011", roomTypeDirect, "歡迎")
+	s.postWelcomeMessage(context.Background(), "507f1f77bcf86cd799439011", "group", "")
+	s.postWelcomeMessage(context.Background(), "507f1f77bcf86cd799439011", "group", "   ")
+}
+
+func TestWelcomeNewMember_DefaultModeSkips(t *testing.T) {
+	if mode := welcomeMessageMode(); mode != constants.WelcomeMessageOnce {
+		t.Fatalf("welcomeMessageMode() = %q, want %q", mode, constants.WelcomeMessageOnce)
+	}
+	(&Server{}).welcomeNewMember(context.Background(), "507f1f77bcf86cd799439011")
+}
+
+func TestGenerateLastMessagePreview_SystemMessage(t *testing.T) {
+	if got := generateLastMessagePreview(systemSenderID, "歡迎加入"); got != "歡迎加入" {
+		t.Errorf("generateLastMessagePreview() = %q, want welcome text", got)
+	}
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
+	"chat-gateway/internal/constants"
 	"chat-gateway/internal/platform/config"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
 )
 
 var (
@@ -46,13 +50,14 @@ func GetConnection() (*grpc.ClientConn, error) {
 	address := fmt.Sprintf("%s:%s", cfg.GRPC.Host, cfg.GRPC.Port)
 
 	// 創建新連接
+	opts := dialOptions(cfg.GRPC.Client)
 	var err error
 	if cfg.Security.TLS.Enabled {
 		// 使用 TLS
-		conn, err = dialWithTLS(address, cfg.Security.TLS)
+		conn, err = dialWithTLS(address, cfg.Security.TLS, opts...)
 	} else {
 		// 開發環境：不使用 TLS
-		conn, err = dialInsecure(address)
+		conn, err = dialInsecure(address, opts...)
 	}
 
 	if err != nil {
@@ -62,8 +67,59 @@ func GetConnection() (*grpc.ClientConn, error) {
 	return conn, nil
 }
 
+// dialOptions 連接選項：keepalive、最大響應大小、斷線重連退避和 UNAVAILABLE 重試
+// 空閒連接會定期發送 ping，避免被中間設備靜默斷開後下一次調用才失敗（transport is closing）
+func dialOptions(client config.GRPCClientConfig) []grpc.DialOption {
+	keepaliveTime := positiveOr(client.KeepaliveTimeSeconds, constants.DefaultGRPCKeepaliveTimeSeconds)
+	keepaliveTimeout := positiveOr(client.KeepaliveTimeoutSeconds, constants.DefaultGRPCKeepaliveTimeoutSeconds)
+	maxRecvMsgSizeMB := positiveOr(client.MaxRecvMsgSizeMB, constants.DefaultGRPCMaxRecvMsgSizeMB)
+	reconnectMaxDelay := positiveOr(client.ReconnectMaxDelaySeconds, constants.DefaultGRPCReconnectMaxDelaySeconds)
+
+	reconnectBackoff := backoff.DefaultConfig
+	reconnectBackoff.MaxDelay = time.Duration(reconnectMaxDelay) * time.Second
+
+	opts := []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                time.Duration(keepaliveTime) * time.Second,
+			Timeout:             time.Duration(keepaliveTimeout) * time.Second,
+			PermitWithoutStream: true, // 沒有進行中的調用時也發送 ping，空閒連接才能保持
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxRecvMsgSizeMB * 1024 * 1024)),
+		grpc.WithConnectParams(grpc.ConnectParams{Backoff: reconnectBackoff}),
+	}
+	if attempts := positiveOr(client.MaxRetryAttempts, constants.DefaultGRPCMaxRetryAttempts); attempts > 1 {
+		opts = append(opts, grpc.WithDefaultServiceConfig(retryServiceConfig(attempts)))
+	}
+	return opts
+}
+
+// retryServiceConfig 返回 UNAVAILABLE（連接斷開、服務重啟）時自動重試的服務配置
+// 只重試 UNAVAILABLE：請求未到達服務端或服務端不可用，業務錯誤不會重試
+func retryServiceConfig(maxAttempts int) string {
+	return fmt.Sprintf(`{
+	"methodConfig": [{
+		"name": [{"service": "chat.ChatRoomService"}],
+		"retryPolicy": {
+			"maxAttempts": %d,
+			"initialBackoff": "0.1s",
+			"maxBackoff": "1s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`, maxAttempts)
+}
+
+// positiveOr 配置值大於 0 時使用配置值，否則使用默認值
+func positiveOr(value, defaultValue int) int {
+	if value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
 // dialWithTLS 使用 TLS 連接
-func dialWithTLS(address string, tlsConfig config.TLSConfig) (*grpc.ClientConn, error) {
+func dialWithTLS(address string, tlsConfig config.TLSConfig, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	var tlsCreds credentials.TransportCredentials
 
 	// 如果有客戶端證書（雙向 TLS）
@@ -99,13 +155,13 @@ func dialWithTLS(address string, tlsConfig config.TLSConfig) (*grpc.ClientConn,
 		})
 	}
 
-	return grpc.NewClient(address, grpc.WithTransportCredentials(tlsCreds))
+	return grpc.NewClient(address, append(opts, grpc.WithTransportCredentials(tlsCreds))...)
 }
 
 // dialInsecure 不使用 TLS 連接（僅開發環境）
-func dialInsecure(address string) (*grpc.ClientConn, error) {
+func dialInsecure(address string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	fmt.Println("[WARNING] gRPC 使用不安全連接（開發環境）")
-	return grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	return grpc.NewClient(address, append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
 }
 
 // CloseConnection 關閉 gRPC 連接
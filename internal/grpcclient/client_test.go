@@ -2,14 +2,20 @@ package grpcclient
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"chat-gateway/internal/platform/config"
 	"chat-gateway/proto/chat"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
 )
 
@@ -292,3 +298,98 @@ func TestStreamMessages(t *testing.T) {
 		t.Logf("收到流式消息: %s (發送者: %s)", msg.Content, msg.SenderId)
 	}
 }
+
+// flakyChatRoomService 前幾次調用返回 UNAVAILABLE，模擬連接中斷或服務重啟
+type flakyChatRoomService struct {
+	chat.UnimplementedChatRoomServiceServer
+	failures int
+	payload  int // 成功響應中聊天室名稱的長度
+	calls    atomic.Int32
+}
+
+func (s *flakyChatRoomService) GetRoomInfo(ctx context.Context, req *chat.GetRoomInfoRequest) (*chat.GetRoomInfoResponse, error) {
+	if int(s.calls.Add(1)) <= s.failures {
+		return nil, status.Error(codes.Unavailable, "transport is closing")
+	}
+	return &chat.GetRoomInfoResponse{
+		Success: true,
+		Room:    &chat.ChatRoom{Name: strings.Repeat("a", s.payload)},
+	}, nil
+}
+
+// dialFlakyServer 使用 dialOptions 連接只會失敗指定次數的 mock server
+func dialFlakyServer(t *testing.T, service *flakyChatRoomService, client config.GRPCClientConfig) *grpc.ClientConn {
+	listener := bufconn.Listen(bufSize)
+	s := grpc.NewServer()
+	chat.RegisterChatRoomServiceServer(s, service)
+	go func() {
+		_ = s.Serve(listener)
+	}()
+
+	opts := append(dialOptions(client),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.NewClient("passthrough://bufnet", opts...)
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		s.Stop()
+		listener.Close()
+	})
+	return conn
+}
+
+// TestDialOptions_RetriesUnavailable 測試 UNAVAILABLE 會自動重試
+func TestDialOptions_RetriesUnavailable(t *testing.T) {
+	service := &flakyChatRoomService{failures: 2}
+	conn := dialFlakyServer(t, service, config.GRPCClientConfig{MaxRetryAttempts: 3})
+
+	resp, err := chat.NewChatRoomServiceClient(conn).GetRoomInfo(context.Background(), &chat.GetRoomInfoRequest{RoomId: "room"})
+	if err != nil || !resp.Success {
+		t.Fatalf("GetRoomInfo() = %v, %v; want success after retries", resp, err)
+	}
+	if got := service.calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+// TestDialOptions_RetryDisabled 測試 max_retry_attempts 為 1 時不重試
+func TestDialOptions_RetryDisabled(t *testing.T) {
+	service := &flakyChatRoomService{failures: 1}
+	conn := dialFlakyServer(t, service, config.GRPCClientConfig{MaxRetryAttempts: 1})
+
+	_, err := chat.NewChatRoomServiceClient(conn).GetRoomInfo(context.Background(), &chat.GetRoomInfoRequest{RoomId: "room"})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("GetRoomInfo() error = %v, want Unavailable", err)
+	}
+	if got := service.calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+// TestDialOptions_MaxRecvMsgSize 測試超過最大響應大小的響應被拒絕
+func TestDialOptions_MaxRecvMsgSize(t *testing.T) {
+	conn := dialFlakyServer(t, &flakyChatRoomService{payload: 2 * 1024 * 1024}, config.GRPCClientConfig{MaxRecvMsgSizeMB: 1})
+
+	_, err := chat.NewChatRoomServiceClient(conn).GetRoomInfo(context.Background(), &chat.GetRoomInfoRequest{RoomId: "room"})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GetRoomInfo() error = %v, want ResourceExhausted", err)
+	}
+}
+
+// TestRetryServiceConfig 測試重試策略是有效的服務配置
+func TestRetryServiceConfig(t *testing.T) {
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(retryServiceConfig(3)), &parsed); err != nil {
+		t.Fatalf("retryServiceConfig() is not valid JSON: %v", err)
+	}
+	if _, err := grpc.NewClient("passthrough://bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(retryServiceConfig(3))); err != nil {
+		t.Errorf("grpc rejected service config: %v", err)
+	}
+}
@@ -119,6 +119,15 @@ func NotFoundError(c *gin.Context, message string) {
 	})
 }
 
+// GatewayTimeout 後端服務響應超時
+func GatewayTimeout(c *gin.Context) {
+	c.JSON(504, gin.H{
+		"error":      "後端服務響應超時，請稍後再試",
+		"success":    false,
+		"request_id": middleware.GetRequestID(c),
+	})
+}
+
 // RateLimitExceeded 速率限制超過
 func RateLimitExceeded(c *gin.Context) {
 	c.JSON(429, gin.H{
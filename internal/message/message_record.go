@@ -23,11 +23,12 @@ type MessageListRequest struct {
 
 // 消息類型常數.
 const (
-	MessageTypeText  = "text"
-	MessageTypeImage = "image"
-	MessageTypeFile  = "file"
-	MessageTypeAudio = "audio"
-	MessageTypeVideo = "video"
+	MessageTypeText     = "text"
+	MessageTypeImage    = "image"
+	MessageTypeFile     = "file"
+	MessageTypeAudio    = "audio"
+	MessageTypeVideo    = "video"
+	MessageTypeLocation = "location"
 )
 
 // 消息狀態常數.
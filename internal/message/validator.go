@@ -2,6 +2,7 @@ package message
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -39,6 +40,17 @@ func ValidateUpdateMessageRequest(req *UpdateMessageRequest) error {
 	return nil
 }
 
+// NormalizeMessageType 驗證消息類型，為空時返回 text.
+func NormalizeMessageType(messageType string) (string, error) {
+	if messageType == "" {
+		return MessageTypeText, nil
+	}
+	if !isValidMessageType(messageType) {
+		return "", fmt.Errorf("不支持的訊息類型: %q（支持 text, image, file, audio, video, location）", messageType)
+	}
+	return messageType, nil
+}
+
 // isValidMessageType 檢查消息類型是否有效.
 func isValidMessageType(messageType string) bool {
 	validTypes := []string{
@@ -47,6 +59,7 @@ func isValidMessageType(messageType string) bool {
 		MessageTypeFile,
 		MessageTypeAudio,
 		MessageTypeVideo,
+		MessageTypeLocation,
 	}
 
 	for _, validType := range validTypes {
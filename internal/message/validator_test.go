@@ -0,0 +1,29 @@
+package message
+
+import "testing"
+
+func TestNormalizeMessageType(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"", MessageTypeText, false},
+		{"text", MessageTypeText, false},
+		{"image", MessageTypeImage, false},
+		{"location", MessageTypeLocation, false},
+		{"system", "", true},
+		{"TEXT", "", true},
+		{"sticker", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeMessageType(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeMessageType(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeMessageType(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
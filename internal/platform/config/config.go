@@ -5,8 +5,13 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+
+	"chat-gateway/internal/constants"
 
 	"github.com/spf13/viper"
 )
@@ -20,6 +25,7 @@ type Config struct {
 	Log      LogConfig      `mapstructure:"log"`
 	Security SecurityConfig `mapstructure:"security"`
 	Limits   LimitsConfig   `mapstructure:"limits"`
+	Storage  StorageConfig  `mapstructure:"storage"`
 }
 
 // AppConfig 應用程式基本配置.
@@ -37,12 +43,29 @@ type ServerConfig struct {
 	UseHTTPS bool   `mapstructure:"use_https"`
 	CertPath string `mapstructure:"cert_path"`
 	KeyPath  string `mapstructure:"key_path"`
+
+	// TrustedProxyCount 服務前面受信任的反向代理層數，0 表示不信任 X-Forwarded-For / X-Real-IP
+	TrustedProxyCount int `mapstructure:"trusted_proxy_count"`
 }
 
 // GRPCConfig gRPC 配置.
 type GRPCConfig struct {
-	Host string `mapstructure:"host"`
-	Port string `mapstructure:"port"`
+	Host              string `mapstructure:"host"`
+	Port              string `mapstructure:"port"`
+	ChatAckEnabled    bool   `mapstructure:"chat_ack_enabled"`   // 雙向聊天流是否回傳訊息接收確認
+	ReflectionEnabled bool   `mapstructure:"reflection_enabled"` // 註冊 gRPC reflection（僅供開發工具使用，會暴露完整的服務定義）
+
+	Client GRPCClientConfig `mapstructure:"client"` // HTTP 服務調用 gRPC 服務的客戶端連接
+}
+
+// GRPCClientConfig gRPC 客戶端連接配置（0 表示使用默認值）.
+type GRPCClientConfig struct {
+	KeepaliveTimeSeconds     int `mapstructure:"keepalive_time_seconds"`      // 連接空閒多久後發送 keepalive ping（至少 10 秒）
+	KeepaliveTimeoutSeconds  int `mapstructure:"keepalive_timeout_seconds"`   // 等待 ping 回應的時間，超時後關閉連接並重新連接
+	MaxRecvMsgSizeMB         int `mapstructure:"max_recv_msg_size_mb"`        // 單個響應的最大大小（MB）
+	MaxRetryAttempts         int `mapstructure:"max_retry_attempts"`          // 返回 UNAVAILABLE 時的最多嘗試次數（包括第一次，1 表示不重試）
+	ReconnectMaxDelaySeconds int `mapstructure:"reconnect_max_delay_seconds"` // 斷線後重新連接的最長退避間隔
+	CallTimeoutSeconds       int `mapstructure:"call_timeout_seconds"`        // HTTP 處理器單次調用的超時時間（訊息流不受限制）
 }
 
 // DatabaseConfig 資料庫配置.
@@ -66,13 +89,43 @@ type MongoConfig struct {
 	TLSCertFile            string `mapstructure:"tls_cert_file"`
 	TLSKeyFile             string `mapstructure:"tls_key_file"`
 	TLSInsecureSkipVerify  bool   `mapstructure:"tls_insecure_skip_verify"`
+	MemberCollection       bool   `mapstructure:"member_collection"`       // 成員存放在獨立的 room_members 集合（大型聊天室）
+	SlowQueryThresholdMs   int    `mapstructure:"slow_query_threshold_ms"` // 超過此耗時的命令記錄為慢查詢（0 使用默認值）
+}
+
+// StorageConfig 文件存儲配置.
+type StorageConfig struct {
+	Avatar  AvatarStorageConfig `mapstructure:"avatar"`
+	Uploads UploadStorageConfig `mapstructure:"uploads"`
+}
+
+// AvatarStorageConfig 頭像上傳配置（大小上限為 limits.request.max_multipart_memory）.
+type AvatarStorageConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Backend  string `mapstructure:"backend"`   // 存儲後端，目前支持 local
+	LocalDir string `mapstructure:"local_dir"` // local 後端的保存目錄
+	BaseURL  string `mapstructure:"base_url"`  // 頭像 URL 前綴；以 / 開頭時由 HTTP 服務直接提供靜態訪問
+}
+
+// UploadStorageConfig 訊息附件上傳配置.
+type UploadStorageConfig struct {
+	Enabled      bool     `mapstructure:"enabled"`
+	Backend      string   `mapstructure:"backend"`        // 存儲後端，目前支持 local
+	LocalDir     string   `mapstructure:"local_dir"`      // local 後端的保存目錄
+	BaseURL      string   `mapstructure:"base_url"`       // 文件 URL 前綴；以 / 開頭時由 HTTP 服務直接提供靜態訪問
+	MaxSizeBytes int64    `mapstructure:"max_size_bytes"` // 單個文件大小上限，0 表示使用 limits.request.max_multipart_memory
+	AllowedTypes []string `mapstructure:"allowed_types"`  // 允許的 MIME 類型（按文件內容判斷），為空時使用默認列表
 }
 
 // LogConfig 日誌配置.
 type LogConfig struct {
-	RotationTimeHours int `mapstructure:"rotation_time_hours"` // 日誌輪轉時間 (小時).
-	MaxAgeDays        int `mapstructure:"max_age_days"`        // 日誌保留天數.
-	MaxSizeMB         int `mapstructure:"max_size_mb"`         // 單個日誌檔案最大大小 (MB).
+	RotationTimeHours int    `mapstructure:"rotation_time_hours"` // 日誌輪轉時間 (小時).
+	MaxAgeDays        int    `mapstructure:"max_age_days"`        // 日誌保留天數.
+	MaxSizeMB         int    `mapstructure:"max_size_mb"`         // 單個日誌檔案最大大小 (MB).
+	Level             string `mapstructure:"level"`               // 最低輸出級別（DEBUG、INFO、WARNING 等），為空時輸出所有日誌.
+	Async             bool   `mapstructure:"async"`               // 異步寫入日誌（由後台 goroutine 批量寫入文件和標準輸出）.
+	BufferSize        int    `mapstructure:"buffer_size"`         // 異步寫入時等待寫入的日誌條數上限，寫滿時丟棄並計數.
+	FlushIntervalMs   int    `mapstructure:"flush_interval_ms"`   // 異步寫入時定期刷新的間隔 (毫秒).
 }
 
 // SecurityConfig 安全配置.
@@ -81,6 +134,29 @@ type SecurityConfig struct {
 	Authentication AuthenticationConfig `mapstructure:"authentication"`
 	Encryption     EncryptionConfig     `mapstructure:"encryption"`
 	Audit          AuditConfig          `mapstructure:"audit"`
+	Headers        HeadersConfig        `mapstructure:"headers"`
+	DataProtection DataProtectionConfig `mapstructure:"data_protection"`
+}
+
+// DataProtectionConfig 數據保護配置（GDPR）.
+type DataProtectionConfig struct {
+	RightToErasure   bool     `mapstructure:"right_to_erasure"`   // 開放用戶數據刪除（被遺忘權）
+	ErasureAdminIDs  []string `mapstructure:"erasure_admin_ids"`  // 可以刪除任意用戶數據的管理員（用戶本人總是可以刪除自己的數據）
+	ErasureBatchSize int      `mapstructure:"erasure_batch_size"` // 每批匿名化/刪除的訊息數量
+}
+
+// HeadersConfig HTTP 安全標頭配置.
+type HeadersConfig struct {
+	ContentSecurityPolicy string     `mapstructure:"content_security_policy"` // 空字符串表示使用默認策略
+	HSTS                  HSTSConfig `mapstructure:"hsts"`
+}
+
+// HSTSConfig Strict-Transport-Security 配置（server.use_https 為 true 時自動啟用）.
+type HSTSConfig struct {
+	Enabled           bool `mapstructure:"enabled"`
+	MaxAgeSeconds     int  `mapstructure:"max_age_seconds"` // 0 表示使用默認值（一年）
+	IncludeSubdomains bool `mapstructure:"include_subdomains"`
+	Preload           bool `mapstructure:"preload"`
 }
 
 // TLSConfig TLS 配置.
@@ -93,16 +169,22 @@ type TLSConfig struct {
 
 // AuthenticationConfig 認證配置.
 type AuthenticationConfig struct {
-	JWTEnabled bool   `mapstructure:"jwt_enabled"`
-	JWTSecret  string `mapstructure:"jwt_secret"`
-	Expiration string `mapstructure:"expiration"`
+	JWTEnabled   bool     `mapstructure:"jwt_enabled"`
+	JWTSecret    string   `mapstructure:"jwt_secret"`
+	Expiration   string   `mapstructure:"expiration"`
+	AdminUserIDs []string `mapstructure:"admin_user_ids"` // 可以使用管理端點列出所有聊天室的用戶（需同時啟用 JWT 認證）
 }
 
 // EncryptionConfig 加密配置.
 type EncryptionConfig struct {
-	Enabled   bool   `mapstructure:"enabled"`
-	Algorithm string `mapstructure:"algorithm"`
-	KeyLength int    `mapstructure:"key_length"`
+	Enabled                  bool   `mapstructure:"enabled"`
+	Algorithm                string `mapstructure:"algorithm"`
+	KeyLength                int    `mapstructure:"key_length"`
+	ReencryptOnRotation      bool   `mapstructure:"reencrypt_on_rotation"`       // 密鑰輪換後在背景用新密鑰重新加密歷史訊息
+	ManualRotationEnabled    bool   `mapstructure:"manual_rotation_enabled"`     // 允許聊天室擁有者或管理員手動輪換密鑰
+	KeyDebugEnabled          bool   `mapstructure:"key_debug_enabled"`           // 開放密鑰狀態查詢端點（需同時啟用 JWT 認證）
+	DenyRevokedKeyDecryption bool   `mapstructure:"deny_revoked_key_decryption"` // 禁止用已撤銷的密鑰解密舊訊息
+	KeyWarmupRooms           int    `mapstructure:"key_warmup_rooms"`            // 啟動時為最近活躍的 N 個聊天室預加載密鑰（0 表示不預加載）
 }
 
 // AuditConfig 審計配置.
@@ -113,13 +195,18 @@ type AuditConfig struct {
 
 // LimitsConfig 限制配置.
 type LimitsConfig struct {
-	Request      RequestLimitsConfig    `mapstructure:"request"`
-	RateLimiting RateLimitingConfig     `mapstructure:"rate_limiting"`
-	SSE          SSELimitsConfig        `mapstructure:"sse"`
-	Pagination   PaginationLimitsConfig `mapstructure:"pagination"`
-	Room         RoomLimitsConfig       `mapstructure:"room"`
-	Message      MessageLimitsConfig    `mapstructure:"message"`
-	MongoDB      MongoDBLimitsConfig    `mapstructure:"mongodb"`
+	Request       RequestLimitsConfig       `mapstructure:"request"`
+	RateLimiting  RateLimitingConfig        `mapstructure:"rate_limiting"`
+	SSE           SSELimitsConfig           `mapstructure:"sse"`
+	Pagination    PaginationLimitsConfig    `mapstructure:"pagination"`
+	Room          RoomLimitsConfig          `mapstructure:"room"`
+	Message       MessageLimitsConfig       `mapstructure:"message"`
+	MongoDB       MongoDBLimitsConfig       `mapstructure:"mongodb"`
+	ReadReceipt   ReadReceiptLimitsConfig   `mapstructure:"read_receipt"`
+	Presence      PresenceLimitsConfig      `mapstructure:"presence"`
+	Retention     RetentionLimitsConfig     `mapstructure:"retention"`
+	Scheduled     ScheduledLimitsConfig     `mapstructure:"scheduled"`
+	SystemMessage SystemMessageLimitsConfig `mapstructure:"system_message"`
 }
 
 // RequestLimitsConfig 請求限制配置.
@@ -136,17 +223,33 @@ type RateLimitingConfig struct {
 	RoomsPerMin      int  `mapstructure:"rooms_per_minute"`
 	SSEPerMin        int  `mapstructure:"sse_per_minute"`
 	CleanupInterval  int  `mapstructure:"cleanup_interval_minutes"`
+
+	// KeyStrategy 默認計數維度：ip（默認）或 user（按已認證用戶，未認證時退回 IP）
+	KeyStrategy string `mapstructure:"key_strategy"`
+	// EndpointKeyStrategies 按端點路徑覆蓋計數維度，例如 /api/v1/messages: user
+	EndpointKeyStrategies map[string]string `mapstructure:"endpoint_key_strategies"`
+
+	// WarnThresholdPercent 已用配額達到此百分比時在響應中加上 X-RateLimit-Warning（0 表示不警告）
+	WarnThresholdPercent int `mapstructure:"warn_threshold_percent"`
+	// EndpointWarnThresholds 按端點路徑覆蓋警告閾值，例如 /api/v1/messages: 80（0 表示該端點不警告）
+	EndpointWarnThresholds map[string]int `mapstructure:"endpoint_warn_thresholds"`
 }
 
 // SSELimitsConfig SSE 限制配置.
 type SSELimitsConfig struct {
-	MaxConnectionsPerIP   int `mapstructure:"max_connections_per_ip"`
-	MaxTotalConnections   int `mapstructure:"max_total_connections"`
-	MinConnectionInterval int `mapstructure:"min_connection_interval_seconds"`
-	HeartbeatInterval     int `mapstructure:"heartbeat_interval_seconds"`
-	CleanupInterval       int `mapstructure:"cleanup_interval_minutes"`
-	InitialMessageFetch   int `mapstructure:"initial_message_fetch"`
-	MessageChannelBuffer  int `mapstructure:"message_channel_buffer"`
+	MaxConnectionsPerIP     int    `mapstructure:"max_connections_per_ip"`
+	MaxTotalConnections     int    `mapstructure:"max_total_connections"`
+	MinConnectionInterval   int    `mapstructure:"min_connection_interval_seconds"`
+	HeartbeatInterval       int    `mapstructure:"heartbeat_interval_seconds"`
+	MaxConnectionDuration   int    `mapstructure:"max_connection_duration_seconds"` // 連接最長存活時間，到期後發送 reconnect 事件並關閉
+	CleanupInterval         int    `mapstructure:"cleanup_interval_minutes"`
+	InitialMessageFetch     int    `mapstructure:"initial_message_fetch"`
+	MessageChannelBuffer    int    `mapstructure:"message_channel_buffer"`
+	SlowClientPolicy        string `mapstructure:"slow_client_policy"` // 訊息隊列已滿時的處理：drop_oldest（默認）或 disconnect
+	SeenSetSize             int    `mapstructure:"seen_set_size"`
+	MembershipCheckInterval int    `mapstructure:"membership_check_interval_seconds"`
+	PollIntervalMs          int    `mapstructure:"poll_interval_ms"`
+	StatsEnabled            bool   `mapstructure:"stats_enabled"` // 開放 GET /api/v1/admin/sse/stats（需要啟用 JWT 認證）
 }
 
 // PaginationLimitsConfig 分頁限制配置.
@@ -158,14 +261,65 @@ type PaginationLimitsConfig struct {
 
 // RoomLimitsConfig 聊天室限制配置.
 type RoomLimitsConfig struct {
-	MaxMembers    int `mapstructure:"max_members"`
-	MaxNameLength int `mapstructure:"max_name_length"`
+	MaxMembers     int    `mapstructure:"max_members"`
+	MaxNameLength  int    `mapstructure:"max_name_length"`
+	NameUniqueness string `mapstructure:"name_uniqueness"` // none / per_owner / global
+	// WelcomeMessageMode 歡迎訊息的發送方式：once（默認，只在創建時發送）/ on_join（每位新成員加入時再次發送）
+	WelcomeMessageMode string `mapstructure:"welcome_message_mode"`
+	// OwnerLeaveMode 擁有者離開聊天室時：transfer（默認，自動轉讓給最早加入的管理員或成員）/ block（必須先轉讓擁有權）
+	OwnerLeaveMode string `mapstructure:"owner_leave_mode"`
+
+	MaxConcurrentCreates     int `mapstructure:"max_concurrent_creates"`      // 每個擁有者同時進行中的創建請求數
+	CreateBurstLimit         int `mapstructure:"create_burst_limit"`          // 每個擁有者在短時間窗口內可創建的聊天室數
+	CreateBurstWindowSeconds int `mapstructure:"create_burst_window_seconds"` // 短時間窗口長度（秒）
 }
 
 // MessageLimitsConfig 訊息限制配置.
 type MessageLimitsConfig struct {
 	MaxLength     int `mapstructure:"max_length"`
 	ChannelBuffer int `mapstructure:"channel_buffer"`
+	MaxTTLSeconds int `mapstructure:"max_ttl_seconds"` // 限時訊息最長的存活時間
+
+	IdempotencyWindowSeconds int `mapstructure:"idempotency_window_seconds"` // 發送冪等鍵的有效期，超過後同一個鍵會創建新訊息
+}
+
+// ReadReceiptLimitsConfig 已讀回執批量寫入配置.
+type ReadReceiptLimitsConfig struct {
+	BatchEnabled    bool   `mapstructure:"batch_enabled"`     // 是否批量寫入 read_by 回執
+	FlushIntervalMs int    `mapstructure:"flush_interval_ms"` // 定期寫入間隔（毫秒）
+	FlushCount      int    `mapstructure:"flush_count"`       // 單個用戶累積多少次已讀後立即寫入
+	UnreadCountMode string `mapstructure:"unread_count_mode"` // fast / precise
+}
+
+// SystemMessageLimitsConfig 系統訊息（加入/離開、聊天室變更等）的語言和發送者顯示名稱.
+type SystemMessageLimitsConfig struct {
+	DefaultLocale string `mapstructure:"default_locale"` // 聊天室未設置語言時使用，默認 zh-tw
+	SenderName    string `mapstructure:"sender_name"`    // 系統訊息的發送者顯示名稱（sender_id 固定為 system）
+	// Templates 按語言覆蓋或補充內置模板：語言 -> 模板鍵 -> 文字（語言不區分大小寫）
+	Templates map[string]map[string]string `mapstructure:"templates"`
+}
+
+// PresenceLimitsConfig 成員在線狀態配置.
+type PresenceLimitsConfig struct {
+	UpdateIntervalSec int `mapstructure:"update_interval_seconds"` // 同一用戶兩次寫入 last_seen 的最小間隔
+	OnlineWindowSec   int `mapstructure:"online_window_seconds"`   // last_seen 在此窗口內視為在線
+}
+
+// RetentionLimitsConfig 訊息保留期限配置（到期訊息由背景任務分批刪除，置頂訊息除外）.
+type RetentionLimitsConfig struct {
+	Enabled         bool           `mapstructure:"enabled"`
+	Days            int            `mapstructure:"days"`             // 全局保留天數（0 表示永久保留）
+	RoomTypeDays    map[string]int `mapstructure:"room_type_days"`   // 按聊天室類型覆蓋保留天數，例如 direct: 90（0 表示永久保留）
+	IntervalMinutes int            `mapstructure:"interval_minutes"` // 清理間隔
+	BatchSize       int            `mapstructure:"batch_size"`       // 每批刪除的訊息數量
+}
+
+// ScheduledLimitsConfig 定時訊息配置（到期訊息由背景任務發送）.
+type ScheduledLimitsConfig struct {
+	Enabled             bool `mapstructure:"enabled"`
+	PollIntervalSeconds int  `mapstructure:"poll_interval_seconds"` // 檢查到期定時訊息的間隔
+	MaxDelayDays        int  `mapstructure:"max_delay_days"`        // 發送時間最多可設定在多少天之後
+	MaxPendingPerUser   int  `mapstructure:"max_pending_per_user"`  // 每個用戶待發送的定時訊息上限
 }
 
 // MongoDBLimitsConfig MongoDB 查詢限制配置.
@@ -178,25 +332,61 @@ type MongoDBLimitsConfig struct {
 }
 
 var (
-	config *Config
-	// ENV 當前環境變數.
+	config   *Config
+	configMu sync.RWMutex // 保護 config 和 ENV
+	loadMu   sync.Mutex   // 串行化 Load 和 Reload，避免並發讀取配置文件
+	// ENV 當前環境變數（請使用 GetEnv / SetEnv 訪問）.
 	ENV string = "local"
 )
 
 // Load 載入設定檔.
+// 已載入時直接返回（重複調用不會重新讀取），重新讀取配置文件請使用 Reload
 func Load(testCfg ...*Config) error {
 	// 如果直接傳入配置（主要用於測試），設定並驗證
 	if len(testCfg) > 0 && testCfg[0] != nil {
-		config = testCfg[0]
+		set(testCfg[0], GetEnv())
 		// 驗證配置
-		if err := validateConfig(config); err != nil {
+		if err := validateConfig(testCfg[0]); err != nil {
 			return fmt.Errorf("配置驗證失敗: %w", err)
 		}
 		return nil
 	}
 
+	loadMu.Lock()
+	defer loadMu.Unlock()
+	if Get() != nil {
+		return nil
+	}
+
+	cfg, env, err := read()
+	if err != nil {
+		return err
+	}
+	set(cfg, env)
+	return nil
+}
+
+// Reload 重新讀取並驗證配置文件，成功後替換當前配置；失敗時保留原配置
+// 返回修改了但需要重啟才能生效的配置項（這些組件只在啟動時讀取配置）
+func Reload() (restartRequired []string, err error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	cfg, env, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	previous := Get()
+	set(cfg, env)
+	return restartRequiredChanges(previous, cfg), nil
+}
+
+// read 讀取配置文件、應用環境變數覆蓋並驗證，返回配置和對應的環境名稱
+func read() (*Config, string, error) {
 	// 初始化 Viper
 	v := viper.New()
+	env := GetEnv()
 
 	// 檢查是否有 CONFIG_PATH 環境變數
 	if configPath := os.Getenv("CONFIG_PATH"); configPath != "" {
@@ -204,48 +394,105 @@ func Load(testCfg ...*Config) error {
 		v.SetConfigFile(configPath)
 		// 從檔案名稱推斷環境
 		baseName := filepath.Base(configPath)
-		ENV = strings.TrimSuffix(baseName, filepath.Ext(baseName))
+		env = strings.TrimSuffix(baseName, filepath.Ext(baseName))
 	} else {
 		// 使用預設的環境配置檔案
-		v.SetConfigName(ENV)
+		v.SetConfigName(env)
 		v.SetConfigType("yaml")
 		v.AddConfigPath("./configs")
 	}
 
 	// 讀取配置檔案
 	if err := v.ReadInConfig(); err != nil {
-		return fmt.Errorf("讀取配置檔案失敗: %w", err)
+		return nil, "", fmt.Errorf("讀取配置檔案失敗: %w", err)
 	}
 
 	// 將配置綁定到結構體
-	config = &Config{}
-	if err := v.Unmarshal(config); err != nil {
-		return fmt.Errorf("解析配置失敗: %w", err)
+	cfg := &Config{}
+	if err := v.Unmarshal(cfg); err != nil {
+		return nil, "", fmt.Errorf("解析配置失敗: %w", err)
 	}
 
 	// 從環境變數覆蓋 MongoDB 設定
-	overrideMongoConfigFromEnv(config)
+	overrideMongoConfigFromEnv(cfg)
+
+	// JWT 密鑰只從環境變數讀取，避免寫入配置文件
+	if jwtSecret := os.Getenv("JWT_SECRET"); jwtSecret != "" {
+		cfg.Security.Authentication.JWTSecret = jwtSecret
+	}
 
 	// 驗證配置
-	if err := validateConfig(config); err != nil {
-		return fmt.Errorf("配置驗證失敗: %w", err)
+	if err := validateConfig(cfg); err != nil {
+		return nil, "", fmt.Errorf("配置驗證失敗: %w", err)
 	}
 
-	return nil
+	return cfg, env, nil
+}
+
+// set 替換當前配置（已經通過 Get 取得舊配置的調用方繼續使用舊配置，不會看到修改了一半的配置）
+func set(cfg *Config, env string) {
+	configMu.Lock()
+	defer configMu.Unlock()
+	config = cfg
+	ENV = env
 }
 
 // Get 取得設定.
 func Get() *Config {
+	configMu.RLock()
+	defer configMu.RUnlock()
 	return config
 }
 
+// restartRequiredChanges 比較新舊配置，返回修改了但只在啟動時讀取的配置項
+// 端口、TLS、數據庫連接、日誌、認證和加密、安全 header、請求大小、速率限制、SSE 連接限制、存儲等組件在啟動時創建
+func restartRequiredChanges(previous, current *Config) []string {
+	if previous == nil || current == nil {
+		return nil
+	}
+
+	sections := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"server", previous.Server, current.Server},
+		{"grpc", previous.GRPC, current.GRPC},
+		{"database", previous.Database, current.Database},
+		{"log", previous.Log, current.Log},
+		{"security.tls", previous.Security.TLS, current.Security.TLS},
+		{"security.authentication", previous.Security.Authentication, current.Security.Authentication},
+		{"security.encryption", previous.Security.Encryption, current.Security.Encryption},
+		{"security.audit", previous.Security.Audit, current.Security.Audit},
+		{"security.headers", previous.Security.Headers, current.Security.Headers},
+		{"limits.request", previous.Limits.Request, current.Limits.Request},
+		{"limits.rate_limiting", previous.Limits.RateLimiting, current.Limits.RateLimiting},
+		{"limits.sse", previous.Limits.SSE, current.Limits.SSE},
+		{"limits.read_receipt", previous.Limits.ReadReceipt, current.Limits.ReadReceipt},
+		{"limits.retention", previous.Limits.Retention, current.Limits.Retention},
+		{"limits.scheduled", previous.Limits.Scheduled, current.Limits.Scheduled},
+		{"storage", previous.Storage, current.Storage},
+	}
+
+	changed := []string{}
+	for _, section := range sections {
+		if !reflect.DeepEqual(section.old, section.new) {
+			changed = append(changed, section.name)
+		}
+	}
+	return changed
+}
+
 // SetEnv 設定環境.
 func SetEnv(env string) {
+	configMu.Lock()
+	defer configMu.Unlock()
 	ENV = env
 }
 
 // GetEnv 取得當前環境.
 func GetEnv() string {
+	configMu.RLock()
+	defer configMu.RUnlock()
 	return ENV
 }
 
@@ -269,6 +516,14 @@ func validateConfig(cfg *Config) error {
 	if cfg.Server.Timeout <= 0 {
 		return fmt.Errorf("伺服器超時時間必須大於 0")
 	}
+	if cfg.Server.TrustedProxyCount < 0 {
+		return fmt.Errorf("受信任代理數量不能小於 0")
+	}
+
+	// 驗證 gRPC 客戶端配置
+	if err := validateGRPCClient(cfg.GRPC.Client); err != nil {
+		return err
+	}
 
 	// 驗證資料庫配置
 	if cfg.Database.Mongo.URL == "" {
@@ -283,6 +538,9 @@ func validateConfig(cfg *Config) error {
 	if cfg.Database.Mongo.MinPoolSize > cfg.Database.Mongo.MaxPoolSize {
 		return fmt.Errorf("MongoDB 最小連接池大小不能大於最大連接池大小")
 	}
+	if cfg.Database.Mongo.SlowQueryThresholdMs < 0 {
+		return fmt.Errorf("MongoDB 慢查詢閾值不能小於 0")
+	}
 
 	// 驗證日誌配置
 	if cfg.Log.RotationTimeHours <= 0 {
@@ -294,10 +552,274 @@ func validateConfig(cfg *Config) error {
 	if cfg.Log.MaxSizeMB <= 0 {
 		return fmt.Errorf("日誌檔案最大大小必須大於 0")
 	}
+	if cfg.Log.BufferSize < 0 || cfg.Log.FlushIntervalMs < 0 {
+		return fmt.Errorf("日誌緩衝區大小和刷新間隔不能小於 0")
+	}
+	if level := strings.ToUpper(cfg.Log.Level); level != "" && !slices.Contains(logLevels, level) {
+		return fmt.Errorf("無效的日誌級別: %s（支持 %s）", cfg.Log.Level, strings.Join(logLevels, ", "))
+	}
+
+	// 驗證訊息流配置（0 表示使用默認值）
+	if interval := cfg.Limits.SSE.PollIntervalMs; interval != 0 && interval < constants.MinStreamPollIntervalMs {
+		return fmt.Errorf("訊息流輪詢間隔不能小於 %d 毫秒", constants.MinStreamPollIntervalMs)
+	}
+	if cfg.Limits.SSE.MaxConnectionDuration < 0 {
+		return fmt.Errorf("SSE 連接最長存活時間不能小於 0")
+	}
+	switch cfg.Limits.SSE.SlowClientPolicy {
+	case "", constants.SSESlowClientDropOldest, constants.SSESlowClientDisconnect:
+	default:
+		return fmt.Errorf("無效的 SSE 慢客戶端策略: %q（可選 %s 或 %s）",
+			cfg.Limits.SSE.SlowClientPolicy, constants.SSESlowClientDropOldest, constants.SSESlowClientDisconnect)
+	}
+
+	// 驗證歡迎訊息發送方式
+	switch cfg.Limits.Room.WelcomeMessageMode {
+	case "", constants.WelcomeMessageOnce, constants.WelcomeMessageOnJoin:
+	default:
+		return fmt.Errorf("無效的歡迎訊息發送方式: %q（可選 %s 或 %s）",
+			cfg.Limits.Room.WelcomeMessageMode, constants.WelcomeMessageOnce, constants.WelcomeMessageOnJoin)
+	}
+
+	// 驗證擁有者離開聊天室的處理方式
+	switch cfg.Limits.Room.OwnerLeaveMode {
+	case "", constants.OwnerLeaveTransfer, constants.OwnerLeaveBlock:
+	default:
+		return fmt.Errorf("無效的擁有者離開方式: %q（可選 %s 或 %s）",
+			cfg.Limits.Room.OwnerLeaveMode, constants.OwnerLeaveTransfer, constants.OwnerLeaveBlock)
+	}
+
+	// 驗證未讀數量計算方式
+	switch cfg.Limits.ReadReceipt.UnreadCountMode {
+	case "", constants.UnreadCountFast, constants.UnreadCountPrecise:
+	default:
+		return fmt.Errorf("無效的未讀數量計算方式: %q（可選 %s 或 %s）",
+			cfg.Limits.ReadReceipt.UnreadCountMode, constants.UnreadCountFast, constants.UnreadCountPrecise)
+	}
+
+	// 驗證系統訊息模板
+	if err := validateSystemMessageTemplates(cfg.Limits.SystemMessage.Templates); err != nil {
+		return err
+	}
+
+	// 驗證 HSTS 配置（preload 列表要求 includeSubDomains 且 max-age 至少一年）
+	hsts := cfg.Security.Headers.HSTS
+	if hsts.MaxAgeSeconds < 0 {
+		return fmt.Errorf("HSTS max-age 不能小於 0")
+	}
+	if hsts.Preload && (!hsts.IncludeSubdomains || (hsts.MaxAgeSeconds != 0 && hsts.MaxAgeSeconds < constants.DefaultHSTSMaxAgeSeconds)) {
+		return fmt.Errorf("HSTS preload 需要啟用 include_subdomains 且 max-age 至少 %d 秒", constants.DefaultHSTSMaxAgeSeconds)
+	}
+
+	// 在線窗口不能短於寫入間隔，否則持續活躍的用戶也會被判定為離線
+	if err := validatePresence(cfg.Limits.Presence); err != nil {
+		return err
+	}
 
+	if cfg.Security.DataProtection.ErasureBatchSize < 0 {
+		return fmt.Errorf("用戶數據刪除的批量大小不能小於 0")
+	}
+
+	// 驗證訊息保留期限配置
+	if err := validateRetention(cfg.Limits.Retention); err != nil {
+		return err
+	}
+
+	if cfg.Limits.Message.MaxTTLSeconds < 0 {
+		return fmt.Errorf("限時訊息的最長存活時間不能小於 0")
+	}
+	if cfg.Limits.Message.IdempotencyWindowSeconds < 0 {
+		return fmt.Errorf("發送冪等鍵的有效期不能小於 0")
+	}
+
+	// 驗證定時訊息配置
+	if scheduled := cfg.Limits.Scheduled; scheduled.PollIntervalSeconds < 0 || scheduled.MaxDelayDays < 0 || scheduled.MaxPendingPerUser < 0 {
+		return fmt.Errorf("定時訊息的檢查間隔、最長延遲天數和待發送上限不能小於 0")
+	}
+
+	// 驗證頭像和附件存儲配置
+	if err := validateAvatarStorage(cfg.Storage.Avatar); err != nil {
+		return err
+	}
+	if err := validateUploadStorage(cfg.Storage.Uploads); err != nil {
+		return err
+	}
+
+	// 驗證 Rate Limiting 計數維度
+	if err := validateRateLimitKeyStrategy(cfg.Limits.RateLimiting.KeyStrategy); err != nil {
+		return err
+	}
+	for path, strategy := range cfg.Limits.RateLimiting.EndpointKeyStrategies {
+		if err := validateRateLimitKeyStrategy(strategy); err != nil {
+			return fmt.Errorf("端點 %s: %w", path, err)
+		}
+	}
+	if err := validateRateLimitWarnThreshold(cfg.Limits.RateLimiting.WarnThresholdPercent); err != nil {
+		return err
+	}
+	for path, percent := range cfg.Limits.RateLimiting.EndpointWarnThresholds {
+		if err := validateRateLimitWarnThreshold(percent); err != nil {
+			return fmt.Errorf("端點 %s: %w", path, err)
+		}
+	}
+
+	// 啟用 JWT 時必須提供足夠長度的 HS256 密鑰
+	if auth := cfg.Security.Authentication; auth.JWTEnabled && len(auth.JWTSecret) < constants.MinJWTSecretLength {
+		return fmt.Errorf("啟用 JWT 認證時 JWT_SECRET 至少需要 %d 個字符", constants.MinJWTSecretLength)
+	}
+
+	if rooms := cfg.Security.Encryption.KeyWarmupRooms; rooms < 0 || rooms > constants.MaxKeyWarmupRooms {
+		return fmt.Errorf("密鑰預加載的聊天室數量必須在 0 到 %d 之間", constants.MaxKeyWarmupRooms)
+	}
+
+	return nil
+}
+
+// validateGRPCClient 驗證 gRPC 客戶端配置（0 表示使用默認值）
+func validateGRPCClient(client GRPCClientConfig) error {
+	if client.KeepaliveTimeSeconds < 0 || client.KeepaliveTimeoutSeconds < 0 || client.MaxRecvMsgSizeMB < 0 ||
+		client.MaxRetryAttempts < 0 || client.ReconnectMaxDelaySeconds < 0 || client.CallTimeoutSeconds < 0 {
+		return fmt.Errorf("gRPC 客戶端的 keepalive、最大響應大小、重試次數、重連間隔和調用超時不能小於 0")
+	}
+	// gRPC 會把更短的 keepalive 間隔強制改為 10 秒，直接拒絕以免配置與實際行為不一致
+	if client.KeepaliveTimeSeconds != 0 && client.KeepaliveTimeSeconds < constants.MinGRPCKeepaliveTimeSeconds {
+		return fmt.Errorf("gRPC 客戶端的 keepalive 間隔不能小於 %d 秒", constants.MinGRPCKeepaliveTimeSeconds)
+	}
+	if client.MaxRetryAttempts > constants.MaxGRPCRetryAttempts {
+		return fmt.Errorf("gRPC 客戶端的最多嘗試次數不能超過 %d", constants.MaxGRPCRetryAttempts)
+	}
+	return nil
+}
+
+// validatePresence 驗證在線狀態配置（0 表示使用默認值）
+func validatePresence(presence PresenceLimitsConfig) error {
+	if presence.UpdateIntervalSec < 0 || presence.OnlineWindowSec < 0 {
+		return fmt.Errorf("在線狀態的寫入間隔和在線窗口不能小於 0")
+	}
+
+	interval := presence.UpdateIntervalSec
+	if interval == 0 {
+		interval = constants.DefaultPresenceUpdateIntervalSec
+	}
+	window := presence.OnlineWindowSec
+	if window == 0 {
+		window = constants.DefaultPresenceOnlineWindowSec
+	}
+	if window < interval {
+		return fmt.Errorf("在線窗口（%d 秒）不能小於 last_seen 寫入間隔（%d 秒）", window, interval)
+	}
 	return nil
 }
 
+// validateRetention 驗證訊息保留期限配置
+func validateRetention(retention RetentionLimitsConfig) error {
+	if retention.Days < 0 || retention.IntervalMinutes < 0 || retention.BatchSize < 0 {
+		return fmt.Errorf("訊息保留天數、清理間隔和批量大小不能小於 0")
+	}
+	for roomType, days := range retention.RoomTypeDays {
+		if days < 0 {
+			return fmt.Errorf("聊天室類型 %s 的訊息保留天數不能小於 0", roomType)
+		}
+	}
+	return nil
+}
+
+// validateAvatarStorage 驗證頭像存儲配置（未啟用時不檢查）
+func validateAvatarStorage(avatar AvatarStorageConfig) error {
+	if !avatar.Enabled {
+		return nil
+	}
+	if err := validateFileBackend(avatar.Backend, avatar.LocalDir, avatar.BaseURL); err != nil {
+		return fmt.Errorf("頭像上傳: %w", err)
+	}
+	return nil
+}
+
+// validateUploadStorage 驗證附件上傳配置（未啟用時不檢查）
+func validateUploadStorage(uploads UploadStorageConfig) error {
+	if !uploads.Enabled {
+		return nil
+	}
+	if err := validateFileBackend(uploads.Backend, uploads.LocalDir, uploads.BaseURL); err != nil {
+		return fmt.Errorf("附件上傳: %w", err)
+	}
+	if uploads.MaxSizeBytes < 0 {
+		return fmt.Errorf("附件上傳: max_size_bytes 不能小於 0")
+	}
+	for _, contentType := range uploads.AllowedTypes {
+		if !mimeTypePattern.MatchString(contentType) {
+			return fmt.Errorf("附件上傳: 無效的 MIME 類型 %q", contentType)
+		}
+	}
+	return nil
+}
+
+// logLevels 支持的日誌級別（GCP Cloud Logging 嚴重級別，由低到高）
+var logLevels = []string{"DEFAULT", "DEBUG", "INFO", "NOTICE", "WARNING", "ERROR", "CRITICAL", "ALERT", "EMERGENCY"}
+
+// mimeTypePattern 不帶參數的 MIME 類型（type/subtype）
+var mimeTypePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9!#$&^_.+-]*/[a-z0-9][a-z0-9!#$&^_.+-]*$`)
+
+// validateFileBackend 驗證文件存儲後端配置
+func validateFileBackend(backend, localDir, baseURL string) error {
+	if backend != "" && backend != "local" {
+		return fmt.Errorf("不支持的存儲後端: %s", backend)
+	}
+	if localDir == "" || baseURL == "" {
+		return fmt.Errorf("必須配置 local_dir 和 base_url")
+	}
+	return nil
+}
+
+// systemMessageTemplateKeys 可以覆蓋的系統訊息模板鍵
+var systemMessageTemplateKeys = []string{
+	constants.SystemMessageMemberJoined,
+	constants.SystemMessageMemberLeft,
+	constants.SystemMessageMemberKicked,
+	constants.SystemMessageOwnerChanged,
+	constants.SystemMessageRoomRenamed,
+	constants.SystemMessageAvatarChanged,
+	constants.SystemMessageSettingsChanged,
+	constants.SystemMessageChangeSeparator,
+	constants.SystemMessageModeNormal,
+	constants.SystemMessageModeSlowmode,
+	constants.SystemMessageModeAnnouncement,
+	constants.SystemMessageModeReadOnly,
+}
+
+// validateSystemMessageTemplates 檢查系統訊息模板的語言和模板鍵
+func validateSystemMessageTemplates(templates map[string]map[string]string) error {
+	for locale, messages := range templates {
+		if locale == "" || len(locale) > constants.MaxLocaleLength {
+			return fmt.Errorf("無效的系統訊息語言: %q", locale)
+		}
+		for key := range messages {
+			if !slices.Contains(systemMessageTemplateKeys, key) {
+				return fmt.Errorf("未知的系統訊息模板: %s.%s（支持 %s）", locale, key, strings.Join(systemMessageTemplateKeys, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// validateRateLimitWarnThreshold 驗證速率限制警告閾值（0 表示不警告）
+func validateRateLimitWarnThreshold(percent int) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("速率限制警告閾值必須在 0 到 100 之間: %d", percent)
+	}
+	return nil
+}
+
+// validateRateLimitKeyStrategy 驗證速率限制計數維度（空字符串表示使用默認值）
+func validateRateLimitKeyStrategy(strategy string) error {
+	switch strategy {
+	case "", constants.RateLimitKeyIP, constants.RateLimitKeyUser:
+		return nil
+	default:
+		return fmt.Errorf("無效的速率限制計數維度: %q（可選 %s 或 %s）", strategy, constants.RateLimitKeyIP, constants.RateLimitKeyUser)
+	}
+}
+
 // IsDebug 檢查是否為除錯模式
 func IsDebug() bool {
 	if config != nil {
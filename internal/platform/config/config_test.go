@@ -0,0 +1,642 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"chat-gateway/internal/constants"
+)
+
+// validTestConfig 通過驗證的最小配置
+func validTestConfig() *Config {
+	cfg := &Config{}
+	cfg.App.Name = "chat-gateway"
+	cfg.App.Version = "test"
+	cfg.Server.Host = "localhost"
+	cfg.Server.Port = "8080"
+	cfg.Server.Timeout = 30
+	cfg.Database.Mongo.URL = "mongodb://localhost:27017"
+	cfg.Database.Mongo.Database = "chat"
+	cfg.Database.Mongo.MaxPoolSize = 10
+	cfg.Log.RotationTimeHours = 24
+	cfg.Log.MaxAgeDays = 7
+	cfg.Log.MaxSizeMB = 100
+	return cfg
+}
+
+func TestValidateConfig_StreamPollInterval(t *testing.T) {
+	tests := []struct {
+		name       string
+		intervalMs int
+		wantErr    bool
+	}{
+		{"unset uses default", 0, false},
+		{"lower bound", constants.MinStreamPollIntervalMs, false},
+		{"slower polling", 5000, false},
+		{"below lower bound", constants.MinStreamPollIntervalMs - 1, true},
+		{"negative", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Limits.SSE.PollIntervalMs = tt.intervalMs
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_SSEMaxConnectionDuration(t *testing.T) {
+	cfg := validTestConfig()
+	cfg.Limits.SSE.MaxConnectionDuration = 0
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("unset duration: validateConfig() error = %v", err)
+	}
+	cfg.Limits.SSE.MaxConnectionDuration = 600
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("positive duration: validateConfig() error = %v", err)
+	}
+	cfg.Limits.SSE.MaxConnectionDuration = -1
+	if err := validateConfig(cfg); err == nil {
+		t.Error("negative duration: validateConfig() error = nil, want error")
+	}
+}
+
+func TestValidateConfig_SSESlowClientPolicy(t *testing.T) {
+	for policy, wantErr := range map[string]bool{
+		"":                                false,
+		constants.SSESlowClientDropOldest: false,
+		constants.SSESlowClientDisconnect: false,
+		"block":                           true,
+	} {
+		cfg := validTestConfig()
+		cfg.Limits.SSE.SlowClientPolicy = policy
+		if err := validateConfig(cfg); (err != nil) != wantErr {
+			t.Errorf("policy %q: validateConfig() error = %v, wantErr %v", policy, err, wantErr)
+		}
+	}
+}
+
+func TestValidateConfig_WelcomeMessageMode(t *testing.T) {
+	for mode, wantErr := range map[string]bool{
+		"":                             false,
+		constants.WelcomeMessageOnce:   false,
+		constants.WelcomeMessageOnJoin: false,
+		"always":                       true,
+	} {
+		cfg := validTestConfig()
+		cfg.Limits.Room.WelcomeMessageMode = mode
+		if err := validateConfig(cfg); (err != nil) != wantErr {
+			t.Errorf("mode %q: validateConfig() error = %v, wantErr %v", mode, err, wantErr)
+		}
+	}
+}
+
+func TestValidateConfig_OwnerLeaveMode(t *testing.T) {
+	for mode, wantErr := range map[string]bool{
+		"":                           false,
+		constants.OwnerLeaveTransfer: false,
+		constants.OwnerLeaveBlock:    false,
+		"delete":                     true,
+	} {
+		cfg := validTestConfig()
+		cfg.Limits.Room.OwnerLeaveMode = mode
+		if err := validateConfig(cfg); (err != nil) != wantErr {
+			t.Errorf("mode %q: validateConfig() error = %v, wantErr %v", mode, err, wantErr)
+		}
+	}
+}
+
+func TestValidateConfig_UnreadCountMode(t *testing.T) {
+	for mode, wantErr := range map[string]bool{
+		"":                           false,
+		constants.UnreadCountFast:    false,
+		constants.UnreadCountPrecise: false,
+		"exact":                      true,
+	} {
+		cfg := validTestConfig()
+		cfg.Limits.ReadReceipt.UnreadCountMode = mode
+		if err := validateConfig(cfg); (err != nil) != wantErr {
+			t.Errorf("mode %q: validateConfig() error = %v, wantErr %v", mode, err, wantErr)
+		}
+	}
+}
+
+func TestValidateConfig_SystemMessageTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates map[string]map[string]string
+		wantErr   bool
+	}{
+		{"none", nil, false},
+		{"known key", map[string]map[string]string{"ja": {constants.SystemMessageMemberJoined: "{user} が参加しました"}}, false},
+		{"unknown key", map[string]map[string]string{"en": {"member_banned": "{user} was banned"}}, true},
+		{"empty locale", map[string]map[string]string{"": {constants.SystemMessageMemberLeft: "bye"}}, true},
+	}
+	for _, tt := range tests {
+		cfg := validTestConfig()
+		cfg.Limits.SystemMessage.Templates = tt.templates
+		if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+			t.Errorf("%s: validateConfig() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateConfig_LogLevel(t *testing.T) {
+	tests := []struct {
+		level   string
+		wantErr bool
+	}{
+		{"", false},
+		{"INFO", false},
+		{"warning", false},
+		{"warn", true},
+	}
+
+	for _, tt := range tests {
+		cfg := validTestConfig()
+		cfg.Log.Level = tt.level
+		if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+			t.Errorf("validateConfig() with level %q error = %v, wantErr %v", tt.level, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateConfig_MongoSlowQueryThreshold(t *testing.T) {
+	tests := []struct {
+		threshold int
+		wantErr   bool
+	}{
+		{0, false},
+		{200, false},
+		{-1, true},
+	}
+
+	for _, tt := range tests {
+		cfg := validTestConfig()
+		cfg.Database.Mongo.SlowQueryThresholdMs = tt.threshold
+		if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+			t.Errorf("validateConfig() with slow query threshold %d error = %v, wantErr %v", tt.threshold, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateConfig_LogAsync(t *testing.T) {
+	tests := []struct {
+		name            string
+		bufferSize      int
+		flushIntervalMs int
+		wantErr         bool
+	}{
+		{"defaults", 0, 0, false},
+		{"custom", 10000, 200, false},
+		{"negative buffer", -1, 0, true},
+		{"negative interval", 0, -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Log.Async = true
+			cfg.Log.BufferSize = tt.bufferSize
+			cfg.Log.FlushIntervalMs = tt.flushIntervalMs
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_KeyWarmupRooms(t *testing.T) {
+	tests := []struct {
+		name    string
+		rooms   int
+		wantErr bool
+	}{
+		{"disabled", 0, false},
+		{"bounded", 500, false},
+		{"upper bound", constants.MaxKeyWarmupRooms, false},
+		{"above upper bound", constants.MaxKeyWarmupRooms + 1, true},
+		{"negative", -1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Security.Encryption.KeyWarmupRooms = tt.rooms
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_JWTSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		secret  string
+		wantErr bool
+	}{
+		{"disabled without secret", false, "", false},
+		{"enabled with secret", true, strings.Repeat("s", constants.MinJWTSecretLength), false},
+		{"enabled without secret", true, "", true},
+		{"enabled with short secret", true, strings.Repeat("s", constants.MinJWTSecretLength-1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Security.Authentication.JWTEnabled = tt.enabled
+			cfg.Security.Authentication.JWTSecret = tt.secret
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_RateLimitKeyStrategy(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  string
+		endpoints map[string]string
+		wantErr   bool
+	}{
+		{"default", "", nil, false},
+		{"ip", constants.RateLimitKeyIP, nil, false},
+		{"user with endpoint override", constants.RateLimitKeyUser, map[string]string{"/api/v1/messages": constants.RateLimitKeyIP}, false},
+		{"invalid default", "token", nil, true},
+		{"invalid endpoint", "", map[string]string{"/api/v1/messages": "session"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Limits.RateLimiting.KeyStrategy = tt.strategy
+			cfg.Limits.RateLimiting.EndpointKeyStrategies = tt.endpoints
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_RateLimitWarnThreshold(t *testing.T) {
+	tests := []struct {
+		name      string
+		percent   int
+		endpoints map[string]int
+		wantErr   bool
+	}{
+		{"disabled", 0, nil, false},
+		{"default with endpoint override", 80, map[string]int{"/api/v1/messages/stream": 0}, false},
+		{"negative", -1, nil, true},
+		{"over 100", 120, nil, true},
+		{"invalid endpoint", 80, map[string]int{"/api/v1/messages": 101}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Limits.RateLimiting.WarnThresholdPercent = tt.percent
+			cfg.Limits.RateLimiting.EndpointWarnThresholds = tt.endpoints
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_HSTS(t *testing.T) {
+	tests := []struct {
+		name    string
+		hsts    HSTSConfig
+		wantErr bool
+	}{
+		{"default", HSTSConfig{}, false},
+		{"negative max age", HSTSConfig{Enabled: true, MaxAgeSeconds: -1}, true},
+		{"preload with defaults", HSTSConfig{Enabled: true, IncludeSubdomains: true, Preload: true}, false},
+		{"preload without subdomains", HSTSConfig{Enabled: true, Preload: true}, true},
+		{"preload with short max age", HSTSConfig{Enabled: true, MaxAgeSeconds: 600, IncludeSubdomains: true, Preload: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Security.Headers.HSTS = tt.hsts
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_Presence(t *testing.T) {
+	tests := []struct {
+		name     string
+		presence PresenceLimitsConfig
+		wantErr  bool
+	}{
+		{"defaults", PresenceLimitsConfig{}, false},
+		{"custom", PresenceLimitsConfig{UpdateIntervalSec: 10, OnlineWindowSec: 60}, false},
+		{"negative", PresenceLimitsConfig{UpdateIntervalSec: -1}, true},
+		{"window shorter than interval", PresenceLimitsConfig{UpdateIntervalSec: 60, OnlineWindowSec: 30}, true},
+		{"window shorter than default interval", PresenceLimitsConfig{OnlineWindowSec: 10}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Limits.Presence = tt.presence
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_Retention(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention RetentionLimitsConfig
+		wantErr   bool
+	}{
+		{"disabled", RetentionLimitsConfig{}, false},
+		{"global", RetentionLimitsConfig{Enabled: true, Days: 365}, false},
+		{"per room type", RetentionLimitsConfig{Enabled: true, RoomTypeDays: map[string]int{"direct": 30, "group": 0}}, false},
+		{"negative days", RetentionLimitsConfig{Enabled: true, Days: -1}, true},
+		{"negative room type days", RetentionLimitsConfig{Enabled: true, RoomTypeDays: map[string]int{"direct": -30}}, true},
+		{"negative batch size", RetentionLimitsConfig{Enabled: true, Days: 30, BatchSize: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Limits.Retention = tt.retention
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_MessageMaxTTL(t *testing.T) {
+	cfg := validTestConfig()
+	cfg.Limits.Message.MaxTTLSeconds = 3600
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("validateConfig() error = %v, want nil", err)
+	}
+
+	cfg.Limits.Message.MaxTTLSeconds = -1
+	if err := validateConfig(cfg); err == nil {
+		t.Error("validateConfig() with negative max TTL should fail")
+	}
+}
+
+func TestValidateConfig_MessageIdempotencyWindow(t *testing.T) {
+	cfg := validTestConfig()
+	cfg.Limits.Message.IdempotencyWindowSeconds = 3600
+	if err := validateConfig(cfg); err != nil {
+		t.Errorf("validateConfig() error = %v, want nil", err)
+	}
+
+	cfg.Limits.Message.IdempotencyWindowSeconds = -1
+	if err := validateConfig(cfg); err == nil {
+		t.Error("validateConfig() with negative idempotency window should fail")
+	}
+}
+
+func TestValidateConfig_Scheduled(t *testing.T) {
+	tests := []struct {
+		name      string
+		scheduled ScheduledLimitsConfig
+		wantErr   bool
+	}{
+		{"disabled", ScheduledLimitsConfig{}, false},
+		{"custom", ScheduledLimitsConfig{Enabled: true, PollIntervalSeconds: 5, MaxDelayDays: 7, MaxPendingPerUser: 20}, false},
+		{"negative poll interval", ScheduledLimitsConfig{Enabled: true, PollIntervalSeconds: -1}, true},
+		{"negative max delay", ScheduledLimitsConfig{Enabled: true, MaxDelayDays: -1}, true},
+		{"negative max pending", ScheduledLimitsConfig{Enabled: true, MaxPendingPerUser: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Limits.Scheduled = tt.scheduled
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_GRPCClient(t *testing.T) {
+	tests := []struct {
+		name    string
+		client  GRPCClientConfig
+		wantErr bool
+	}{
+		{"defaults", GRPCClientConfig{}, false},
+		{"custom", GRPCClientConfig{KeepaliveTimeSeconds: 60, KeepaliveTimeoutSeconds: 5, MaxRecvMsgSizeMB: 16, MaxRetryAttempts: 5, ReconnectMaxDelaySeconds: 10}, false},
+		{"no retry", GRPCClientConfig{MaxRetryAttempts: 1}, false},
+		{"keepalive too short", GRPCClientConfig{KeepaliveTimeSeconds: 5}, true},
+		{"negative timeout", GRPCClientConfig{KeepaliveTimeoutSeconds: -1}, true},
+		{"negative message size", GRPCClientConfig{MaxRecvMsgSizeMB: -1}, true},
+		{"too many retries", GRPCClientConfig{MaxRetryAttempts: 6}, true},
+		{"negative reconnect delay", GRPCClientConfig{ReconnectMaxDelaySeconds: -1}, true},
+		{"custom call timeout", GRPCClientConfig{CallTimeoutSeconds: 30}, false},
+		{"negative call timeout", GRPCClientConfig{CallTimeoutSeconds: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.GRPC.Client = tt.client
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_AvatarStorage(t *testing.T) {
+	tests := []struct {
+		name    string
+		avatar  AvatarStorageConfig
+		wantErr bool
+	}{
+		{"disabled", AvatarStorageConfig{Backend: "s3"}, false},
+		{"local", AvatarStorageConfig{Enabled: true, Backend: "local", LocalDir: "./data/avatars", BaseURL: "/avatars"}, false},
+		{"default backend", AvatarStorageConfig{Enabled: true, LocalDir: "./data/avatars", BaseURL: "/avatars"}, false},
+		{"unknown backend", AvatarStorageConfig{Enabled: true, Backend: "s3", LocalDir: "./data/avatars", BaseURL: "/avatars"}, true},
+		{"missing dir", AvatarStorageConfig{Enabled: true, BaseURL: "/avatars"}, true},
+		{"missing base url", AvatarStorageConfig{Enabled: true, LocalDir: "./data/avatars"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Storage.Avatar = tt.avatar
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfig_UploadStorage(t *testing.T) {
+	tests := []struct {
+		name    string
+		uploads UploadStorageConfig
+		wantErr bool
+	}{
+		{"disabled", UploadStorageConfig{AllowedTypes: []string{"bad type"}}, false},
+		{"local", UploadStorageConfig{Enabled: true, LocalDir: "./data/uploads", BaseURL: "/files"}, false},
+		{"allowed types", UploadStorageConfig{Enabled: true, LocalDir: "./data/uploads", BaseURL: "/files", AllowedTypes: []string{"image/png", "application/vnd.ms-excel"}}, false},
+		{"invalid type", UploadStorageConfig{Enabled: true, LocalDir: "./data/uploads", BaseURL: "/files", AllowedTypes: []string{"image/*"}}, true},
+		{"type with params", UploadStorageConfig{Enabled: true, LocalDir: "./data/uploads", BaseURL: "/files", AllowedTypes: []string{"text/plain; charset=utf-8"}}, true},
+		{"negative size", UploadStorageConfig{Enabled: true, LocalDir: "./data/uploads", BaseURL: "/files", MaxSizeBytes: -1}, true},
+		{"missing dir", UploadStorageConfig{Enabled: true, BaseURL: "/files"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validTestConfig()
+			cfg.Storage.Uploads = tt.uploads
+			if err := validateConfig(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	const base = `app: {name: chat-gateway, version: test}
+server: {host: localhost, port: "%s", timeout: 30}
+database: {mongo: {url: "mongodb://localhost:27017", database: chat, max_pool_size: 10}}
+log: {rotation_time_hours: 24, max_age_days: 7, max_size_mb: 100}
+limits: {room: {max_members: %d}}
+`
+	path := filepath.Join(t.TempDir(), "reload.yaml")
+	writeConfig := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	resetConfig(t)
+	t.Setenv("CONFIG_PATH", path)
+
+	writeConfig(fmt.Sprintf(base, "8080", 100))
+	if err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	loaded := Get()
+
+	writeConfig(fmt.Sprintf(base, "9090", 200))
+	restartRequired, err := Reload()
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := Get().Limits.Room.MaxMembers; got != 200 {
+		t.Errorf("MaxMembers after reload = %d, want 200", got)
+	}
+	if loaded.Limits.Room.MaxMembers != 100 {
+		t.Errorf("previously loaded config was modified in place")
+	}
+	if !reflect.DeepEqual(restartRequired, []string{"server"}) {
+		t.Errorf("restartRequired = %v, want [server]", restartRequired)
+	}
+
+	// 驗證失敗時保留原配置
+	writeConfig(fmt.Sprintf(base, "", 300))
+	if _, err := Reload(); err == nil {
+		t.Fatal("Reload() with invalid config should fail")
+	}
+	if got := Get().Limits.Room.MaxMembers; got != 200 {
+		t.Errorf("MaxMembers after failed reload = %d, want 200", got)
+	}
+}
+
+func TestLoad_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "concurrent.yaml")
+	content := `app: {name: chat-gateway, version: test}
+server: {host: localhost, port: "8080", timeout: 30}
+database: {mongo: {url: "mongodb://localhost:27017", database: chat, max_pool_size: 10}}
+log: {rotation_time_hours: 24, max_age_days: 7, max_size_mb: 100}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	resetConfig(t)
+	t.Setenv("CONFIG_PATH", path)
+
+	// 多個 goroutine 同時載入配置，其他 goroutine 同時讀取和重新載入（配合 -race 運行）
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := Load(); err != nil {
+				errs <- err
+			}
+			if Get() == nil {
+				errs <- fmt.Errorf("Get() returned nil after Load")
+			}
+			_ = GetEnv()
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := Reload(); err != nil {
+				errs <- err
+			}
+			if cfg := Get(); cfg != nil && cfg.Server.Port != "8080" {
+				errs <- fmt.Errorf("Server.Port = %q", cfg.Server.Port)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+	if env := GetEnv(); env != "concurrent" {
+		t.Errorf("GetEnv() = %q, want concurrent", env)
+	}
+}
+
+func TestLoad_Idempotent(t *testing.T) {
+	resetConfig(t)
+	loaded := validTestConfig()
+	if err := Load(loaded); err != nil {
+		t.Fatalf("Load(cfg) error = %v", err)
+	}
+
+	// 已載入時不重新讀取配置文件
+	t.Setenv("CONFIG_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+	if err := Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if Get() != loaded {
+		t.Error("Load() replaced an already loaded config")
+	}
+}
+
+// resetConfig 清空已載入的配置，測試結束後恢復
+func resetConfig(t *testing.T) {
+	t.Helper()
+	previous, previousEnv := Get(), GetEnv()
+	set(nil, previousEnv)
+	t.Cleanup(func() { set(previous, previousEnv) })
+}
@@ -61,6 +61,7 @@ func InitMongo(cfg *config.MongoConfig) error {
 	clientOptions.SetMinPoolSize(cfg.MinPoolSize)
 	clientOptions.SetMaxConnIdleTime(time.Duration(cfg.MaxConnIdleTime) * time.Second)
 	clientOptions.SetServerSelectionTimeout(time.Duration(cfg.ServerSelectionTimeout) * time.Second)
+	clientOptions.SetMonitor(newCommandMonitor(slowQueryThreshold(cfg)).monitor())
 
 	// 連接到 MongoDB
 	client, err := mongo.Connect(clientOptions)
@@ -0,0 +1,181 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// slowQueryThreshold 慢查詢日誌的耗時閾值
+func slowQueryThreshold(cfg *config.MongoConfig) time.Duration {
+	ms := constants.DefaultMongoSlowQueryThresholdMs
+	if cfg.SlowQueryThresholdMs > 0 {
+		ms = cfg.SlowQueryThresholdMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// commandKey 唯一標識一次進行中的命令（RequestID 只在單個連接內唯一）
+type commandKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// startedCommand 命令開始時記錄的集合和查詢結構，完成時用於慢查詢日誌
+type startedCommand struct {
+	collection string
+	shape      string
+}
+
+// commandMonitor 記錄失敗的 MongoDB 命令和慢查詢
+// 回調收到的是發起操作的 context，日誌帶有 gRPC 攔截器寫入的 trace ID，可與同一請求的 HTTP、gRPC 日誌關聯
+type commandMonitor struct {
+	threshold time.Duration
+	started   sync.Map // commandKey -> startedCommand
+}
+
+func newCommandMonitor(threshold time.Duration) *commandMonitor {
+	return &commandMonitor{threshold: threshold}
+}
+
+// monitor 返回註冊到 MongoDB 客戶端的命令監聽器
+func (m *commandMonitor) monitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started:   m.onStarted,
+		Succeeded: m.onSucceeded,
+		Failed:    m.onFailed,
+	}
+}
+
+func (m *commandMonitor) onStarted(_ context.Context, evt *event.CommandStartedEvent) {
+	collection, filter := commandTarget(evt.CommandName, evt.Command)
+	m.started.Store(commandKey{evt.ConnectionID, evt.RequestID}, startedCommand{
+		collection: collection,
+		shape:      filter,
+	})
+}
+
+func (m *commandMonitor) onSucceeded(ctx context.Context, evt *event.CommandSucceededEvent) {
+	cmd := m.finish(&evt.CommandFinishedEvent)
+	if evt.Duration < m.threshold {
+		return
+	}
+	logger.Warning(ctx, "MongoDB 慢查詢",
+		logger.WithAction("mongo_"+evt.CommandName),
+		logger.WithDetails(commandDetails(&evt.CommandFinishedEvent, cmd)))
+}
+
+func (m *commandMonitor) onFailed(ctx context.Context, evt *event.CommandFailedEvent) {
+	cmd := m.finish(&evt.CommandFinishedEvent)
+	// 客戶端斷開或調用超時導致的取消已由上層記錄
+	if errors.Is(evt.Failure, context.Canceled) || errors.Is(evt.Failure, context.DeadlineExceeded) {
+		return
+	}
+	details := commandDetails(&evt.CommandFinishedEvent, cmd)
+	details["error"] = evt.Failure.Error()
+	logger.Warning(ctx, "MongoDB 命令失敗",
+		logger.WithAction("mongo_"+evt.CommandName),
+		logger.WithDetails(details))
+}
+
+// finish 取出並移除命令開始時記錄的信息
+func (m *commandMonitor) finish(evt *event.CommandFinishedEvent) startedCommand {
+	value, ok := m.started.LoadAndDelete(commandKey{evt.ConnectionID, evt.RequestID})
+	if !ok {
+		return startedCommand{}
+	}
+	return value.(startedCommand)
+}
+
+// commandDetails 命令的日誌字段（只包含查詢結構，不記錄訊息內容等敏感數據）
+func commandDetails(evt *event.CommandFinishedEvent, cmd startedCommand) map[string]interface{} {
+	details := map[string]interface{}{
+		"database":    evt.DatabaseName,
+		"command":     evt.CommandName,
+		"duration_ms": evt.Duration.Milliseconds(),
+	}
+	if cmd.collection != "" {
+		details["collection"] = cmd.collection
+	}
+	if cmd.shape != "" {
+		details["filter_shape"] = cmd.shape
+	}
+	return details
+}
+
+// commandTarget 從命令文檔中取出集合名稱和查詢條件的結構
+// 命令名稱對應的字段值即集合名稱；查詢條件所在字段因命令而異，寫入命令取第一條語句的條件
+func commandTarget(name string, command bson.Raw) (string, string) {
+	collection, _ := command.Lookup(name).StringValueOK()
+
+	var filter bson.RawValue
+	switch name {
+	case "find":
+		filter = command.Lookup("filter")
+	case "aggregate":
+		filter = command.Lookup("pipeline")
+	case "count", "distinct", "findAndModify":
+		filter = command.Lookup("query")
+	case "update":
+		filter = command.Lookup("updates", "0", "q")
+	case "delete":
+		filter = command.Lookup("deletes", "0", "q")
+	default:
+		return collection, ""
+	}
+	if filter.Type == 0 {
+		return collection, ""
+	}
+	return collection, queryShape(filter)
+}
+
+// queryShape 把查詢條件轉換為只包含字段名和運算符的結構，所有值替換為 ?
+// 例如 {room_id: "r1", created_at: {$lt: ...}} 轉換為 {room_id: ?, created_at: {$lt: ?}}
+func queryShape(value bson.RawValue) string {
+	var b strings.Builder
+	writeShape(&b, value)
+	return b.String()
+}
+
+func writeShape(b *strings.Builder, value bson.RawValue) {
+	switch value.Type {
+	case bson.TypeEmbeddedDocument:
+		elems, _ := value.Document().Elements()
+		b.WriteByte('{')
+		for i, elem := range elems {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(elem.Key())
+			b.WriteString(": ")
+			writeShape(b, elem.Value())
+		}
+		b.WriteByte('}')
+	case bson.TypeArray:
+		// 值數組（例如 $in 的參數）只保留一個 ?，避免長列表撐大日誌；文檔數組（$or、pipeline）保留每個元素的結構
+		values, _ := value.Array().Values()
+		b.WriteByte('[')
+		for i, v := range values {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if v.Type != bson.TypeEmbeddedDocument && v.Type != bson.TypeArray {
+				b.WriteByte('?')
+				break
+			}
+			writeShape(b, v)
+		}
+		b.WriteByte(']')
+	default:
+		b.WriteByte('?')
+	}
+}
@@ -0,0 +1,136 @@
+package driver
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/platform/config"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+func mustMarshal(t *testing.T, doc interface{}) bson.Raw {
+	t.Helper()
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	return raw
+}
+
+func TestCommandTarget(t *testing.T) {
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		command        string
+		doc            bson.D
+		wantCollection string
+		wantShape      string
+	}{
+		{
+			name:    "find",
+			command: "find",
+			doc: bson.D{
+				{Key: "find", Value: "messages"},
+				{Key: "filter", Value: bson.D{
+					{Key: "room_id", Value: "room-secret"},
+					{Key: "created_at", Value: bson.D{{Key: "$lt", Value: createdAt}}},
+				}},
+			},
+			wantCollection: "messages",
+			wantShape:      "{room_id: ?, created_at: {$lt: ?}}",
+		},
+		{
+			name:    "value arrays collapse",
+			command: "find",
+			doc: bson.D{
+				{Key: "find", Value: "chat_rooms"},
+				{Key: "filter", Value: bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: bson.A{"a", "b", "c"}}}}}},
+			},
+			wantCollection: "chat_rooms",
+			wantShape:      "{_id: {$in: [?]}}",
+		},
+		{
+			name:    "aggregate pipeline",
+			command: "aggregate",
+			doc: bson.D{
+				{Key: "aggregate", Value: "messages"},
+				{Key: "pipeline", Value: bson.A{
+					bson.D{{Key: "$match", Value: bson.D{{Key: "$or", Value: bson.A{
+						bson.D{{Key: "content", Value: "hello"}},
+						bson.D{{Key: "sender_id", Value: "u1"}},
+					}}}}},
+					bson.D{{Key: "$limit", Value: 20}},
+				}},
+			},
+			wantCollection: "messages",
+			wantShape:      "[{$match: {$or: [{content: ?}, {sender_id: ?}]}}, {$limit: ?}]",
+		},
+		{
+			name:    "update uses first statement",
+			command: "update",
+			doc: bson.D{
+				{Key: "update", Value: "chat_rooms"},
+				{Key: "updates", Value: bson.A{bson.D{
+					{Key: "q", Value: bson.D{{Key: "_id", Value: "r1"}}},
+					{Key: "u", Value: bson.D{{Key: "$set", Value: bson.D{{Key: "name", Value: "x"}}}}},
+				}}},
+			},
+			wantCollection: "chat_rooms",
+			wantShape:      "{_id: ?}",
+		},
+		{
+			name:           "insert has no filter",
+			command:        "insert",
+			doc:            bson.D{{Key: "insert", Value: "messages"}},
+			wantCollection: "messages",
+			wantShape:      "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collection, shape := commandTarget(tt.command, mustMarshal(t, tt.doc))
+			if collection != tt.wantCollection || shape != tt.wantShape {
+				t.Errorf("commandTarget() = %q, %q; want %q, %q", collection, shape, tt.wantCollection, tt.wantShape)
+			}
+			if strings.Contains(shape, "secret") {
+				t.Errorf("shape %q leaks filter values", shape)
+			}
+		})
+	}
+}
+
+func TestCommandMonitor_ForgetsFinishedCommands(t *testing.T) {
+	m := newCommandMonitor(time.Hour)
+	ctx := context.Background()
+	m.onStarted(ctx, &event.CommandStartedEvent{
+		Command:      mustMarshal(t, bson.D{{Key: "find", Value: "messages"}}),
+		CommandName:  "find",
+		RequestID:    1,
+		ConnectionID: "conn-1",
+	})
+
+	m.onSucceeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName:  "find",
+		RequestID:    1,
+		ConnectionID: "conn-1",
+	}})
+
+	m.started.Range(func(key, _ interface{}) bool {
+		t.Errorf("command %v still tracked after it finished", key)
+		return true
+	})
+}
+
+func TestSlowQueryThreshold(t *testing.T) {
+	if got := slowQueryThreshold(&config.MongoConfig{}); got != 500*time.Millisecond {
+		t.Errorf("default threshold = %v, want 500ms", got)
+	}
+	if got := slowQueryThreshold(&config.MongoConfig{SlowQueryThresholdMs: 50}); got != 50*time.Millisecond {
+		t.Errorf("threshold = %v, want 50ms", got)
+	}
+}
@@ -20,6 +20,7 @@ const (
 	statusHealthy   = "healthy"
 	statusUnhealthy = "unhealthy"
 	statusWarning   = "warning"
+	statusDisabled  = "disabled"
 
 	// 記憶體相關常數.
 	memoryMB        = 1024 * 1024
@@ -29,6 +30,27 @@ const (
 	dbTimeout = 5 * time.Second
 )
 
+// EncryptionChecker 加密密鑰存儲的健康檢查（由密鑰管理器實現）.
+type EncryptionChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// encryptionChecker 啟用加密時由 main 設置.
+var encryptionChecker EncryptionChecker
+
+// SetEncryptionChecker 設置加密密鑰存儲的健康檢查.
+func SetEncryptionChecker(checker EncryptionChecker) {
+	encryptionChecker = checker
+}
+
+// grpcReady 返回 gRPC 服務器是否已開始接受連接，由 main 設置.
+var grpcReady func() bool
+
+// SetGRPCReadiness 設置 gRPC 服務器的就緒檢查.
+func SetGRPCReadiness(ready func() bool) {
+	grpcReady = ready
+}
+
 // Handler 健康檢查處理器.
 type Handler struct{}
 
@@ -58,6 +80,9 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 		}
 	}
 
+	// 檢查加密密鑰存儲.
+	encryptionStatus, encryptionError := h.checkEncryption(cfg)
+
 	// 檢查系統資源.
 	systemStatus := h.checkSystemResources()
 
@@ -81,6 +106,10 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 			"error":   dbError,
 			"details": dbDetails,
 		},
+		"encryption": gin.H{
+			"status": encryptionStatus,
+			"error":  encryptionError,
+		},
 		"system": gin.H{
 			"status":  systemStatus.Status,
 			"details": systemStatus.Details,
@@ -88,8 +117,8 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 		},
 	}
 
-	// 如果資料庫不健康，將整體狀態設為 degraded.
-	if dbStatus == statusUnhealthy {
+	// 如果資料庫或加密不健康，將整體狀態設為 degraded.
+	if dbStatus == statusUnhealthy || encryptionStatus == statusUnhealthy {
 		response["status"] = "degraded"
 	}
 
@@ -98,6 +127,65 @@ func (h *Handler) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// Liveness 存活探針：進程在運行就返回 200.
+func (h *Handler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "alive",
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// DependencyStatus 依賴的就緒狀態.
+type DependencyStatus struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Readiness 就緒探針：MongoDB 不可用或 gRPC 服務器未啟動時返回 503.
+func (h *Handler) Readiness(c *gin.Context) {
+	dependencies := []DependencyStatus{
+		h.mongoReadiness(),
+		grpcReadiness(),
+	}
+
+	code := http.StatusOK
+	status := "ready"
+	for _, dep := range dependencies {
+		if dep.Status != statusHealthy {
+			code = http.StatusServiceUnavailable
+			status = "not_ready"
+			break
+		}
+	}
+
+	c.JSON(code, gin.H{
+		"status":       status,
+		"timestamp":    time.Now().Unix(),
+		"dependencies": dependencies,
+	})
+}
+
+// mongoReadiness 檢查 MongoDB 是否可用.
+func (h *Handler) mongoReadiness() DependencyStatus {
+	dep := DependencyStatus{Name: "mongodb", Status: statusHealthy}
+	if err := h.checkDatabase(); err != nil {
+		dep.Status = statusUnhealthy
+		dep.Error = err.Error()
+	}
+	return dep
+}
+
+// grpcReadiness 檢查 gRPC 服務器是否已啟動.
+func grpcReadiness() DependencyStatus {
+	dep := DependencyStatus{Name: "grpc", Status: statusHealthy}
+	if grpcReady == nil || !grpcReady() {
+		dep.Status = statusUnhealthy
+		dep.Error = "grpc server not started"
+	}
+	return dep
+}
+
 // SystemStatus 系統狀態.
 type SystemStatus struct {
 	Status  string                 `json:"status"`
@@ -136,8 +224,32 @@ func (h *Handler) checkSystemResources() SystemStatus {
 	}
 }
 
+// checkEncryption 檢查加密密鑰存儲（未啟用加密時返回 disabled）.
+func (h *Handler) checkEncryption(cfg *config.Config) (status, errMsg string) {
+	if cfg == nil || !cfg.Security.Encryption.Enabled {
+		return statusDisabled, ""
+	}
+	if encryptionChecker == nil {
+		return statusUnhealthy, "key manager not initialized"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	if err := encryptionChecker.HealthCheck(ctx); err != nil {
+		logger.LogErrorf("健康檢查 - 加密密鑰存儲異常: %v", err)
+		return statusUnhealthy, err.Error()
+	}
+	return statusHealthy, ""
+}
+
 // checkDatabase 檢查資料庫連線.
 func (h *Handler) checkDatabase() error {
+	return CheckDatabase()
+}
+
+// CheckDatabase 檢查資料庫連線（HTTP 與 gRPC 健康檢查共用）.
+func CheckDatabase() error {
 	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewHealthHandler()
+	r := gin.New()
+	r.GET("/health/live", h.Liveness)
+	r.GET("/health/ready", h.Readiness)
+	return r
+}
+
+func TestLiveness(t *testing.T) {
+	w := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestReadiness_NotReadyWithoutDependencies(t *testing.T) {
+	SetGRPCReadiness(func() bool { return true })
+	defer SetGRPCReadiness(nil)
+
+	w := httptest.NewRecorder()
+	newTestRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+	// 測試環境沒有 MongoDB 連接
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+
+	var body struct {
+		Status       string             `json:"status"`
+		Dependencies []DependencyStatus `json:"dependencies"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Status != "not_ready" || len(body.Dependencies) != 2 {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+
+	statuses := map[string]string{}
+	for _, dep := range body.Dependencies {
+		statuses[dep.Name] = dep.Status
+	}
+	if statuses["mongodb"] != statusUnhealthy || statuses["grpc"] != statusHealthy {
+		t.Errorf("dependency statuses = %v", statuses)
+	}
+}
+
+func TestGRPCReadiness(t *testing.T) {
+	SetGRPCReadiness(nil)
+	if dep := grpcReadiness(); dep.Status != statusUnhealthy {
+		t.Errorf("unregistered grpc server should not be ready: %+v", dep)
+	}
+
+	SetGRPCReadiness(func() bool { return false })
+	defer SetGRPCReadiness(nil)
+	if dep := grpcReadiness(); dep.Status != statusUnhealthy {
+		t.Errorf("grpc server that has not started should not be ready: %+v", dep)
+	}
+}
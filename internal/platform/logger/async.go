@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"chat-gateway/internal/constants"
+)
+
+// asyncWriter 異步日誌寫入：調用方只把序列化後的日誌放入隊列，由後台 goroutine 寫入緩衝區
+// 緩衝區寫滿或定期刷新時才寫入底層文件，隊列按 FIFO 處理，同一 goroutine 的日誌保持順序
+type asyncWriter struct {
+	mu      sync.RWMutex // 保護 closed，避免關閉後繼續寫入已關閉的隊列
+	closed  bool
+	lines   chan []byte
+	done    chan struct{}
+	dropped atomic.Int64 // 隊列已滿時丟棄的日誌條數
+}
+
+// newAsyncWriter 創建異步寫入器並啟動後台刷新
+func newAsyncWriter(out io.Writer, bufferSize int, flushInterval time.Duration) *asyncWriter {
+	w := &asyncWriter{
+		lines: make(chan []byte, bufferSize),
+		done:  make(chan struct{}),
+	}
+	go w.run(bufio.NewWriterSize(out, constants.LogWriteBufferBytes), flushInterval)
+	return w
+}
+
+// Write 把一行日誌放入隊列（不阻塞），隊列已滿或已關閉時丟棄並計數
+func (w *asyncWriter) Write(line []byte) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		w.dropped.Add(1)
+		return
+	}
+	select {
+	case w.lines <- line:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// Dropped 累計丟棄的日誌條數
+func (w *asyncWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Close 停止接收新日誌，寫完隊列中剩餘的日誌後返回
+func (w *asyncWriter) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.lines)
+	w.mu.Unlock()
+
+	<-w.done
+}
+
+// run 後台寫入隊列中的日誌，定期刷新緩衝區並報告新丟棄的條數
+func (w *asyncWriter) run(buf *bufio.Writer, flushInterval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var reported int64
+	for {
+		select {
+		case line, ok := <-w.lines:
+			if !ok {
+				reported = w.reportDropped(buf, reported)
+				_ = buf.Flush() // #nosec G104 -- log write errors are not critical
+				return
+			}
+			_, _ = buf.Write(line) // #nosec G104 -- log write errors are not critical
+		case <-ticker.C:
+			reported = w.reportDropped(buf, reported)
+			_ = buf.Flush() // #nosec G104 -- log write errors are not critical
+		}
+	}
+}
+
+// reportDropped 有新丟棄的日誌時寫入一條警告，返回已報告的累計條數
+func (w *asyncWriter) reportDropped(buf *bufio.Writer, reported int64) int64 {
+	dropped := w.dropped.Load()
+	if dropped == reported {
+		return reported
+	}
+
+	entry := &LogEntry{
+		Severity:  SeverityWarning,
+		Message:   "日誌隊列已滿，部分日誌被丟棄",
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Labels:    map[string]string{"service": serviceName},
+		Details:   map[string]interface{}{"dropped": dropped - reported, "dropped_total": dropped},
+	}
+	jsonData, err := json.Marshal(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to marshal log entry: %v\n", err)
+		return dropped
+	}
+	_, _ = buf.Write(append(jsonData, '\n')) // #nosec G104 -- log write errors are not critical
+	return dropped
+}
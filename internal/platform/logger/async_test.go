@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer 可以並發讀寫的緩衝區
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestAsyncWriter_CloseDrainsInOrder(t *testing.T) {
+	out := &syncBuffer{}
+	w := newAsyncWriter(out, 100, time.Hour)
+
+	for i := 0; i < 50; i++ {
+		w.Write([]byte(fmt.Sprintf("line %d\n", i)))
+	}
+	w.Close()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 50 {
+		t.Fatalf("written lines = %d, want 50", len(lines))
+	}
+	for i, line := range lines {
+		if want := fmt.Sprintf("line %d", i); line != want {
+			t.Fatalf("line %d = %q, want %q", i, line, want)
+		}
+	}
+
+	// 關閉後的寫入被丟棄，不會 panic
+	w.Write([]byte("late\n"))
+	if w.Dropped() != 1 {
+		t.Errorf("Dropped() after close = %d, want 1", w.Dropped())
+	}
+}
+
+func TestAsyncWriter_PeriodicFlush(t *testing.T) {
+	out := &syncBuffer{}
+	w := newAsyncWriter(out, 10, 10*time.Millisecond)
+	defer w.Close()
+
+	w.Write([]byte("hello\n"))
+	deadline := time.Now().Add(time.Second)
+	for out.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if out.String() != "hello\n" {
+		t.Errorf("output = %q, want flushed line", out.String())
+	}
+}
+
+func TestAsyncWriter_DropsWhenFull(t *testing.T) {
+	// 後台 goroutine 啟動前隊列只能容納一條
+	w := &asyncWriter{lines: make(chan []byte, 1), done: make(chan struct{})}
+	for i := 0; i < 3; i++ {
+		w.Write([]byte(fmt.Sprintf("line %d\n", i)))
+	}
+	if w.Dropped() != 2 {
+		t.Fatalf("Dropped() = %d, want 2", w.Dropped())
+	}
+
+	out := &syncBuffer{}
+	go w.run(bufio.NewWriter(out), time.Hour)
+	w.Close()
+
+	got := out.String()
+	if !strings.HasPrefix(got, "line 0\n") {
+		t.Errorf("output = %q, want queued line first", got)
+	}
+	if !strings.Contains(got, `"dropped":2`) {
+		t.Errorf("output = %q, want dropped warning", got)
+	}
+}
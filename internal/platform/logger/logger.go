@@ -8,8 +8,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"chat-gateway/internal/constants"
 	"chat-gateway/internal/platform/config"
 
 	"github.com/google/uuid"
@@ -31,6 +34,44 @@ const (
 	SeverityEmergency Severity = "EMERGENCY"
 )
 
+// severityRanks 嚴重級別的順序（與 GCP LogSeverity 的數值一致），用於過濾低於最低級別的日誌
+var severityRanks = map[Severity]int32{
+	SeverityDefault:   0,
+	SeverityDebug:     100,
+	SeverityInfo:      200,
+	SeverityNotice:    300,
+	SeverityWarning:   400,
+	SeverityError:     500,
+	SeverityCritical:  600,
+	SeverityAlert:     700,
+	SeverityEmergency: 800,
+}
+
+// minSeverityRank 最低輸出級別（默認輸出所有日誌）
+var minSeverityRank atomic.Int32
+
+// ParseSeverity 解析日誌級別名稱（不區分大小寫），空字符串表示 DEFAULT（輸出所有日誌）
+func ParseSeverity(level string) (Severity, error) {
+	if level == "" {
+		return SeverityDefault, nil
+	}
+	severity := Severity(strings.ToUpper(level))
+	if _, ok := severityRanks[severity]; !ok {
+		return "", fmt.Errorf("unknown log level: %s", level)
+	}
+	return severity, nil
+}
+
+// SetMinSeverity 設置最低輸出級別，低於該級別的日誌不寫入
+func SetMinSeverity(severity Severity) {
+	minSeverityRank.Store(severityRanks[severity])
+}
+
+// Enabled 該級別的日誌是否會被寫入
+func Enabled(severity Severity) bool {
+	return severityRanks[severity] >= minSeverityRank.Load()
+}
+
 // LogEntry GCP Cloud Logging 格式的日誌條目
 type LogEntry struct {
 	Severity       Severity          `json:"severity"`
@@ -83,6 +124,7 @@ type Operation struct {
 
 var (
 	logWriter   io.Writer
+	asyncLog    *asyncWriter // 啟用 log.async 時不為空，日誌由後台 goroutine 寫入
 	projectID   string
 	serviceName string
 )
@@ -124,6 +166,13 @@ func InitLogger() error {
 	if cfg != nil && cfg.Log.MaxSizeMB > 0 {
 		maxSize = cfg.Log.MaxSizeMB
 	}
+	if cfg != nil {
+		severity, err := ParseSeverity(cfg.Log.Level)
+		if err != nil {
+			return err
+		}
+		SetMinSeverity(severity)
+	}
 
 	// 設定日誌輪轉
 	logFileName := filepath.Join(logDir, "app.log")
@@ -140,11 +189,29 @@ func InitLogger() error {
 
 	logWriter = writer
 
+	if cfg != nil && cfg.Log.Async {
+		bufferSize := constants.DefaultLogBufferSize
+		if cfg.Log.BufferSize > 0 {
+			bufferSize = cfg.Log.BufferSize
+		}
+		flushInterval := constants.DefaultLogFlushIntervalMs
+		if cfg.Log.FlushIntervalMs > 0 {
+			flushInterval = cfg.Log.FlushIntervalMs
+		}
+		asyncLog = newAsyncWriter(io.MultiWriter(writer, os.Stdout), bufferSize, time.Duration(flushInterval)*time.Millisecond)
+	}
+
 	return nil
 }
 
-// CloseLogger 關閉日誌檔案
+// CloseLogger 關閉日誌檔案（異步寫入時先寫完隊列中的日誌）
 func CloseLogger() {
+	if asyncLog != nil {
+		asyncLog.Close()
+		if dropped := asyncLog.Dropped(); dropped > 0 {
+			fmt.Fprintf(os.Stderr, "Logger dropped %d entries because the buffer was full\n", dropped)
+		}
+	}
 	if logWriter != nil {
 		if closer, ok := logWriter.(io.Closer); ok {
 			if err := closer.Close(); err != nil {
@@ -163,6 +230,12 @@ func writeLog(entry *LogEntry) {
 		return
 	}
 
+	// 異步寫入時放入隊列，由後台 goroutine 寫入檔案和控制台
+	if asyncLog != nil {
+		asyncLog.Write(append(jsonData, '\n'))
+		return
+	}
+
 	// 寫入檔案和控制台
 	if logWriter != nil {
 		_, _ = logWriter.Write(append(jsonData, '\n')) // #nosec G104 -- log write errors are not critical
@@ -231,6 +304,11 @@ func WithTraceID(ctx context.Context, traceID string) context.Context {
 
 // Log 通用日誌方法
 func Log(ctx context.Context, severity Severity, message string, opts ...LogOption) {
+	// 低於最低級別的日誌直接丟棄，不構建日誌條目
+	if !Enabled(severity) {
+		return
+	}
+
 	entry := &LogEntry{
 		Severity:       severity,
 		Message:        message,
@@ -365,6 +443,17 @@ func Errorf(ctx context.Context, format string, args ...interface{}) {
 	Error(ctx, fmt.Sprintf(format, args...))
 }
 
+// exit 結束進程（測試時替換）
+var exit = os.Exit
+
+// Fatalf 記錄 CRITICAL 日誌，寫完並關閉日誌文件後以狀態碼 1 結束進程
+// 不會執行調用方的 defer，只用於無法恢復的錯誤（例如監聽端口失敗）
+func Fatalf(ctx context.Context, format string, args ...interface{}) {
+	Log(ctx, SeverityCritical, fmt.Sprintf(format, args...))
+	CloseLogger()
+	exit(1)
+}
+
 // 向後兼容的舊方法（會逐步廢棄）
 
 // LogInfof 舊版 INFO 日誌（向後兼容）
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tests := []struct {
+		level   string
+		want    Severity
+		wantErr bool
+	}{
+		{"", SeverityDefault, false},
+		{"debug", SeverityDebug, false},
+		{"WARNING", SeverityWarning, false},
+		{"warn", "", true},
+		{"verbose", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSeverity(tt.level)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSeverity(%q) error = %v, wantErr %v", tt.level, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseSeverity(%q) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	t.Cleanup(func() { SetMinSeverity(SeverityDefault) })
+
+	SetMinSeverity(SeverityInfo)
+	tests := []struct {
+		severity Severity
+		want     bool
+	}{
+		{SeverityDefault, false},
+		{SeverityDebug, false},
+		{SeverityInfo, true},
+		{SeverityWarning, true},
+		{SeverityEmergency, true},
+	}
+	for _, tt := range tests {
+		if got := Enabled(tt.severity); got != tt.want {
+			t.Errorf("Enabled(%s) with min INFO = %v, want %v", tt.severity, got, tt.want)
+		}
+	}
+
+	SetMinSeverity(SeverityDefault)
+	if !Enabled(SeverityDebug) {
+		t.Error("Enabled(DEBUG) with min DEFAULT = false, want true")
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	previous := projectID
+	projectID = "test-project"
+	t.Cleanup(func() { projectID = previous })
+
+	ctx := WithTraceID(context.Background(), "abc123")
+	if got, want := GetTraceID(ctx), "projects/test-project/traces/abc123"; got != want {
+		t.Errorf("GetTraceID() = %q, want %q", got, want)
+	}
+
+	// 使用相同字符串的其他 key 不會被當作 trace ID
+	type otherKey string
+	ctx = context.WithValue(context.Background(), otherKey("trace_id"), "abc123")
+	if got := GetTraceID(ctx); got != "" {
+		t.Errorf("GetTraceID() with foreign key = %q, want empty", got)
+	}
+	if got := GetTraceID(nil); got != "" { //nolint:staticcheck // 驗證 nil context 不會 panic
+		t.Errorf("GetTraceID(nil) = %q, want empty", got)
+	}
+}
+
+func TestFatalf(t *testing.T) {
+	out := &syncBuffer{}
+	asyncLog = newAsyncWriter(out, 10, time.Hour)
+	exitCode := -1
+	exit = func(code int) { exitCode = code }
+	t.Cleanup(func() {
+		asyncLog = nil
+		exit = os.Exit
+	})
+
+	Fatalf(context.Background(), "listen failed: %s", "address in use")
+
+	if exitCode != 1 {
+		t.Errorf("exit code = %d, want 1", exitCode)
+	}
+	// 退出前寫完異步隊列中的日誌
+	got := out.String()
+	if !strings.Contains(got, `"severity":"CRITICAL"`) || !strings.Contains(got, "listen failed: address in use") {
+		t.Errorf("output = %q, want flushed CRITICAL entry", got)
+	}
+}
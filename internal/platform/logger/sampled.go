@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sampleWindow 相同日誌合併的時間窗口
+const sampleWindow = time.Minute
+
+// sampleKey 合併相同日誌使用的鍵
+type sampleKey struct {
+	severity Severity
+	message  string
+	roomID   string
+}
+
+// sampledEntry 窗口內被合併的日誌
+type sampledEntry struct {
+	repeated int         // 窗口內第一條之後被合併的條數
+	opts     []LogOption // 最後一條的選項（窗口結束時的匯總使用）
+}
+
+// sampler 在時間窗口內合併相同的日誌：第一條立即寫入，之後的只計數，窗口結束時寫入一條帶次數的匯總
+type sampler struct {
+	mu      sync.Mutex
+	window  time.Duration
+	entries map[sampleKey]*sampledEntry
+	log     func(ctx context.Context, severity Severity, message string, opts ...LogOption)
+}
+
+func newSampler(window time.Duration, log func(ctx context.Context, severity Severity, message string, opts ...LogOption)) *sampler {
+	return &sampler{
+		window:  window,
+		entries: make(map[sampleKey]*sampledEntry),
+		log:     log,
+	}
+}
+
+var defaultSampler = newSampler(sampleWindow, Log)
+
+// Sampled 記錄可能大量重複的日誌（例如某個聊天室的訊息都無法解密）
+// 同一級別、訊息和聊天室的日誌在一分鐘內只寫入第一條，窗口結束時再寫入一條帶 repeated 次數的匯總
+func Sampled(ctx context.Context, severity Severity, message, roomID string, opts ...LogOption) {
+	if !Enabled(severity) {
+		return
+	}
+	opts = append(opts, WithRoomID(roomID), withSourceLocation(getSourceLocation(2)))
+	defaultSampler.record(ctx, sampleKey{severity: severity, message: message, roomID: roomID}, opts)
+}
+
+// record 記錄一條日誌，窗口內已有相同日誌時只計數
+func (s *sampler) record(ctx context.Context, key sampleKey, opts []LogOption) {
+	s.mu.Lock()
+	if entry, ok := s.entries[key]; ok {
+		entry.repeated++
+		entry.opts = opts
+		s.mu.Unlock()
+		return
+	}
+	s.entries[key] = &sampledEntry{}
+	s.mu.Unlock()
+
+	time.AfterFunc(s.window, func() { s.flush(key) })
+	s.log(ctx, key.severity, key.message, opts...)
+}
+
+// flush 窗口結束，有被合併的日誌時寫入匯總
+func (s *sampler) flush(key sampleKey) {
+	s.mu.Lock()
+	entry := s.entries[key]
+	delete(s.entries, key)
+	s.mu.Unlock()
+
+	if entry == nil || entry.repeated == 0 {
+		return
+	}
+	opts := append(entry.opts, withRepeated(entry.repeated, s.window))
+	s.log(context.Background(), key.severity, key.message, opts...)
+}
+
+// withRepeated 在詳情中加入合併的次數（保留原有的詳情）
+func withRepeated(repeated int, window time.Duration) LogOption {
+	return func(e *LogEntry) {
+		details := make(map[string]interface{}, len(e.Details)+2)
+		for k, v := range e.Details {
+			details[k] = v
+		}
+		details["repeated"] = repeated
+		details["window_seconds"] = int(window.Seconds())
+		e.Details = details
+	}
+}
+
+// withSourceLocation 使用調用 Sampled 的位置，而不是合併器內部的位置
+func withSourceLocation(location *SourceLocation) LogOption {
+	return func(e *LogEntry) {
+		e.SourceLocation = location
+	}
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordedLog 測試中記錄的日誌
+type recordedLog struct {
+	message string
+	entry   LogEntry
+}
+
+func TestSampler(t *testing.T) {
+	var mu sync.Mutex
+	var logs []recordedLog
+	s := newSampler(20*time.Millisecond, func(_ context.Context, severity Severity, message string, opts ...LogOption) {
+		entry := LogEntry{Severity: severity}
+		for _, opt := range opts {
+			opt(&entry)
+		}
+		mu.Lock()
+		logs = append(logs, recordedLog{message: message, entry: entry})
+		mu.Unlock()
+	})
+	recorded := func() []recordedLog {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recordedLog(nil), logs...)
+	}
+
+	key := sampleKey{severity: SeverityWarning, message: "消息解密失敗", roomID: "room-1"}
+	for i := 0; i < 5; i++ {
+		s.record(context.Background(), key, []LogOption{WithDetails(map[string]interface{}{"error": "bad key"})})
+	}
+	s.record(context.Background(), sampleKey{severity: SeverityWarning, message: "消息解密失敗", roomID: "room-2"}, nil)
+
+	// 每個聊天室的第一條立即寫入
+	if got := len(recorded()); got != 2 {
+		t.Fatalf("logs before window end = %d, want 2", got)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for len(recorded()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(30 * time.Millisecond) // 沒有重複的 room-2 不應寫入匯總
+
+	got := recorded()
+	if len(got) != 3 {
+		t.Fatalf("logs after window end = %d, want 3", len(got))
+	}
+	summary := got[2].entry
+	if summary.Details["repeated"] != 4 || summary.Details["error"] != "bad key" {
+		t.Errorf("summary details = %v, want repeated=4 and original error", summary.Details)
+	}
+
+	// 窗口結束後重新開始計數
+	s.record(context.Background(), key, nil)
+	if got := len(recorded()); got != 4 {
+		t.Errorf("logs after new window = %d, want 4", got)
+	}
+}
@@ -2,7 +2,15 @@ package middleware
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"strings"
+	"time"
+
+	"chat-gateway/internal/platform/config"
 
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
@@ -11,11 +19,30 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// JWTMiddleware JWT 驗證中間件（待整合 user 服務）
-// 目前不啟用，等待 user 服務實現後再啟用
+// UserIDKey gin.Context 中已認證用戶 ID 的鍵
+const UserIDKey = "user_id"
+
+// AccessTokenQueryParam 無法設置 header 的客戶端（瀏覽器 EventSource）傳入 token 的查詢參數
+const AccessTokenQueryParam = "access_token"
+
+const userIDContextKey contextKey = "user_id"
+
+// JWT 驗證錯誤
+var (
+	errMalformedToken   = errors.New("malformed token")
+	errUnsupportedAlg   = errors.New("unsupported signing algorithm")
+	errInvalidSignature = errors.New("invalid token signature")
+	errTokenExpired     = errors.New("token expired")
+	errTokenNotYetValid = errors.New("token not yet valid")
+	errMissingSubject   = errors.New("token has no subject")
+)
+
+// JWTMiddleware JWT 驗證中間件（HS256）
+// 未啟用時完全放行，不影響開發環境
 type JWTMiddleware struct {
 	secretKey string
 	enabled   bool
+	now       func() time.Time
 }
 
 // NewJWTMiddleware 創建 JWT 中間件
@@ -23,7 +50,31 @@ func NewJWTMiddleware(secretKey string, enabled bool) *JWTMiddleware {
 	return &JWTMiddleware{
 		secretKey: secretKey,
 		enabled:   enabled,
+		now:       time.Now,
+	}
+}
+
+// NewJWTMiddlewareFromConfig 根據 security.authentication 配置創建 JWT 中間件（未載入配置時不啟用）
+func NewJWTMiddlewareFromConfig() *JWTMiddleware {
+	cfg := config.Get()
+	if cfg == nil {
+		return NewJWTMiddleware("", false)
 	}
+	auth := cfg.Security.Authentication
+	return NewJWTMiddleware(auth.JWTSecret, auth.JWTEnabled)
+}
+
+// ContextWithUserID 將已認證的用戶 ID 存入 context
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDContextKey, userID)
+}
+
+// UserIDFromContext 從 context 獲取已認證的用戶 ID（未啟用認證時為空）
+func UserIDFromContext(ctx context.Context) string {
+	if userID, ok := ctx.Value(userIDContextKey).(string); ok {
+		return userID
+	}
+	return ""
 }
 
 // GinMiddleware Gin HTTP 中間件
@@ -52,26 +103,54 @@ func (m *JWTMiddleware) GinMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		token := parts[1]
+		userID, err := m.validateToken(parts[1])
+		if err != nil {
+			c.JSON(401, gin.H{"error": "認證失敗"})
+			c.Abort()
+			return
+		}
 
-		// TODO: 待 user 服務實現後，調用 user 服務驗證 token
-		// userID, err := m.validateToken(token)
-		// if err != nil {
-		//     c.JSON(401, gin.H{"error": "認證失敗"})
-		//     c.Abort()
-		//     return
-		// }
+		// 將用戶 ID 存入 context，並以驗證過的身份覆蓋請求元數據中的 user_id
+		c.Set(UserIDKey, userID)
+		GetRequestMetadataFromGin(c).UserID = userID
+		c.Request = c.Request.WithContext(ContextWithUserID(c.Request.Context(), userID))
 
-		// 將用戶 ID 存入 context
-		// c.Set("user_id", userID)
-		_ = token // 暫時避免 unused variable 警告
+		c.Next()
+	}
+}
+
+// QueryTokenMiddleware 把指定路徑的 access_token 查詢參數移到 Authorization header（已有 header 時不覆蓋）
+// 瀏覽器 EventSource 無法設置 header；需要在訪問日誌之前註冊，token 才不會出現在日誌中
+func QueryTokenMiddleware(paths ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		allowed[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if !allowed[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		query := c.Request.URL.Query()
+		token := query.Get(AccessTokenQueryParam)
+		if token == "" {
+			c.Next()
+			return
+		}
+		if c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		query.Del(AccessTokenQueryParam)
+		c.Request.URL.RawQuery = query.Encode()
 
 		c.Next()
 	}
 }
 
 // GRPCUnaryInterceptor gRPC 一元 RPC 攔截器
-// 使用方式：grpc.NewServer(grpc.UnaryInterceptor(jwtMiddleware.GRPCUnaryInterceptor()))
+// 使用方式：grpc.ChainUnaryInterceptor(middleware.GRPCLoggingUnaryInterceptor(), jwtMiddleware.GRPCUnaryInterceptor())
 func (m *JWTMiddleware) GRPCUnaryInterceptor() grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
@@ -80,36 +159,16 @@ func (m *JWTMiddleware) GRPCUnaryInterceptor() grpc.UnaryServerInterceptor {
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
 		// 如果未啟用，直接放行
-		if !m.enabled {
+		if !m.enabled || isGRPCHealthMethod(info.FullMethod) {
 			return handler(ctx, req)
 		}
 
-		// 從 metadata 獲取 token
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Errorf(codes.Unauthenticated, "未提供認證信息")
-		}
-
-		values := md.Get("authorization")
-		if len(values) == 0 {
-			return nil, status.Errorf(codes.Unauthenticated, "未提供認證 token")
+		userID, err := m.authenticateGRPC(ctx)
+		if err != nil {
+			return nil, err
 		}
 
-		token := values[0]
-		// 移除 "Bearer " 前綴
-		token = strings.TrimPrefix(token, "Bearer ")
-
-		// TODO: 待 user 服務實現後，調用 user 服務驗證 token
-		// userID, err := m.validateToken(token)
-		// if err != nil {
-		//     return nil, status.Errorf(codes.Unauthenticated, "認證失敗")
-		// }
-
-		// 將用戶 ID 存入 context
-		// ctx = context.WithValue(ctx, "user_id", userID)
-		_ = token // 暫時避免 unused variable 警告
-
-		return handler(ctx, req)
+		return handler(ContextWithUserID(ctx, userID), req)
 	}
 }
 
@@ -122,41 +181,116 @@ func (m *JWTMiddleware) GRPCStreamInterceptor() grpc.StreamServerInterceptor {
 		handler grpc.StreamHandler,
 	) error {
 		// 如果未啟用，直接放行
-		if !m.enabled {
+		if !m.enabled || isGRPCHealthMethod(info.FullMethod) {
 			return handler(srv, ss)
 		}
 
-		// 從 metadata 獲取 token
-		md, ok := metadata.FromIncomingContext(ss.Context())
-		if !ok {
-			return status.Errorf(codes.Unauthenticated, "未提供認證信息")
+		userID, err := m.authenticateGRPC(ss.Context())
+		if err != nil {
+			return err
 		}
 
-		values := md.Get("authorization")
-		if len(values) == 0 {
-			return status.Errorf(codes.Unauthenticated, "未提供認證 token")
-		}
+		ctx := ContextWithUserID(ss.Context(), userID)
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// isGRPCHealthMethod 健康檢查由負載均衡器調用，不需要認證
+func isGRPCHealthMethod(method string) bool {
+	return strings.HasPrefix(method, grpcHealthServicePrefix)
+}
+
+// authenticateGRPC 從 metadata 讀取並驗證 token，返回用戶 ID
+func (m *JWTMiddleware) authenticateGRPC(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Errorf(codes.Unauthenticated, "未提供認證信息")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Errorf(codes.Unauthenticated, "未提供認證 token")
+	}
+
+	// 移除 "Bearer " 前綴
+	token := strings.TrimPrefix(values[0], "Bearer ")
+
+	userID, err := m.validateToken(token)
+	if err != nil {
+		return "", status.Errorf(codes.Unauthenticated, "認證失敗")
+	}
+	return userID, nil
+}
 
-		token := values[0]
-		token = strings.TrimPrefix(token, "Bearer ")
+// jwtHeader JWT header
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
 
-		// TODO: 待 user 服務實現後，調用 user 服務驗證 token
-		// userID, err := m.validateToken(token)
-		// if err != nil {
-		//     return status.Errorf(codes.Unauthenticated, "認證失敗")
-		// }
+// jwtClaims 使用到的 JWT claims
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	UserID    string `json:"user_id"`
+	ExpiresAt *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+}
 
-		_ = token // 暫時避免 unused variable 警告
+// validateToken 驗證 HS256 簽名的 JWT，返回用戶 ID（sub，沒有時使用 user_id）
+// token 必須包含 exp，過期或尚未生效的 token 會被拒絕
+func (m *JWTMiddleware) validateToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errMalformedToken
+	}
+
+	var header jwtHeader
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return "", errMalformedToken
+	}
+	// 只接受 HS256，防止 alg=none 或算法混淆攻擊
+	if header.Alg != "HS256" {
+		return "", errUnsupportedAlg
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errMalformedToken
+	}
+	mac := hmac.New(sha256.New, []byte(m.secretKey))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errInvalidSignature
+	}
 
-		return handler(srv, ss)
+	var claims jwtClaims
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return "", errMalformedToken
 	}
+
+	now := m.now().Unix()
+	if claims.ExpiresAt == nil || now >= *claims.ExpiresAt {
+		return "", errTokenExpired
+	}
+	if claims.NotBefore != nil && now < *claims.NotBefore {
+		return "", errTokenNotYetValid
+	}
+
+	userID := claims.Subject
+	if userID == "" {
+		userID = claims.UserID
+	}
+	if userID == "" {
+		return "", errMissingSubject
+	}
+	return userID, nil
 }
 
-// validateToken 驗證 token（待實現）
-// func (m *JWTMiddleware) validateToken(token string) (string, error) {
-//     // TODO: 調用 user 服務的 gRPC API 驗證 token
-//     // 1. 連接到 user 服務
-//     // 2. 調用 ValidateToken RPC
-//     // 3. 返回用戶 ID
-//     return "", nil
-// }
+// decodeJWTSegment 解碼 base64url 編碼的 JWT 片段
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
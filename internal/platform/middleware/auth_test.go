@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// signTestToken 生成測試用 JWT
+func signTestToken(t *testing.T, secret string, header, claims map[string]interface{}) string {
+	t.Helper()
+
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signingInput := encode(header) + "." + encode(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestJWTMiddleware_ValidateToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	m := NewJWTMiddleware(testJWTSecret, true)
+	m.now = func() time.Time { return now }
+
+	hs256 := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+	valid := map[string]interface{}{"sub": "user-1", "exp": now.Add(time.Minute).Unix()}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantID  string
+		wantErr error
+	}{
+		{"valid", signTestToken(t, testJWTSecret, hs256, valid), "user-1", nil},
+		{"user_id claim", signTestToken(t, testJWTSecret, hs256, map[string]interface{}{
+			"user_id": "user-2", "exp": now.Add(time.Minute).Unix(),
+		}), "user-2", nil},
+		{"expired", signTestToken(t, testJWTSecret, hs256, map[string]interface{}{
+			"sub": "user-1", "exp": now.Add(-time.Second).Unix(),
+		}), "", errTokenExpired},
+		{"missing exp", signTestToken(t, testJWTSecret, hs256, map[string]interface{}{"sub": "user-1"}), "", errTokenExpired},
+		{"not yet valid", signTestToken(t, testJWTSecret, hs256, map[string]interface{}{
+			"sub": "user-1", "exp": now.Add(time.Hour).Unix(), "nbf": now.Add(time.Minute).Unix(),
+		}), "", errTokenNotYetValid},
+		{"wrong secret", signTestToken(t, "another-secret-another-secret-xx", hs256, valid), "", errInvalidSignature},
+		{"alg none", signTestToken(t, testJWTSecret, map[string]interface{}{"alg": "none"}, valid), "", errUnsupportedAlg},
+		{"missing subject", signTestToken(t, testJWTSecret, hs256, map[string]interface{}{
+			"exp": now.Add(time.Minute).Unix(),
+		}), "", errMissingSubject},
+		{"malformed", "not-a-jwt", "", errMalformedToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, err := m.validateToken(tt.token)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if userID != tt.wantID {
+				t.Errorf("userID = %q, want %q", userID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestJWTMiddleware_GinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token := signTestToken(t, testJWTSecret,
+		map[string]interface{}{"alg": "HS256", "typ": "JWT"},
+		map[string]interface{}{"sub": "user-1", "exp": time.Now().Add(time.Minute).Unix()})
+
+	tests := []struct {
+		name       string
+		enabled    bool
+		authHeader string
+		wantStatus int
+		wantUserID string
+	}{
+		{"disabled bypass", false, "", http.StatusOK, ""},
+		{"missing token", true, "", http.StatusUnauthorized, ""},
+		{"invalid scheme", true, "Basic " + token, http.StatusUnauthorized, ""},
+		{"invalid token", true, "Bearer " + token + "x", http.StatusUnauthorized, ""},
+		{"valid token", true, "Bearer " + token, http.StatusOK, "user-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID string
+			r := gin.New()
+			r.Use(NewJWTMiddleware(testJWTSecret, tt.enabled).GinMiddleware())
+			r.GET("/", func(c *gin.Context) {
+				gotUserID = UserIDFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if gotUserID != tt.wantUserID {
+				t.Errorf("user id = %q, want %q", gotUserID, tt.wantUserID)
+			}
+		})
+	}
+}
+
+func TestQueryTokenMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token := signTestToken(t, testJWTSecret,
+		map[string]interface{}{"alg": "HS256", "typ": "JWT"},
+		map[string]interface{}{"sub": "user-1", "exp": time.Now().Add(time.Minute).Unix()})
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantUserID string
+	}{
+		{"allowed path", "/stream", http.StatusOK, "user-1"},
+		{"other path", "/rooms", http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserID, gotQuery string
+			r := gin.New()
+			r.Use(QueryTokenMiddleware("/stream"), NewJWTMiddleware(testJWTSecret, true).GinMiddleware())
+			r.GET(tt.path, func(c *gin.Context) {
+				gotUserID = UserIDFromContext(c.Request.Context())
+				gotQuery = c.Request.URL.RawQuery
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path+"?room_id=r&"+AccessTokenQueryParam+"="+token, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if gotUserID != tt.wantUserID {
+				t.Errorf("user id = %q, want %q", gotUserID, tt.wantUserID)
+			}
+			if tt.wantUserID != "" && gotQuery != "room_id=r" {
+				t.Errorf("query = %q, want the token removed", gotQuery)
+			}
+		})
+	}
+}
+
+func TestJWTMiddleware_GRPCUnaryInterceptor(t *testing.T) {
+	token := signTestToken(t, testJWTSecret,
+		map[string]interface{}{"alg": "HS256", "typ": "JWT"},
+		map[string]interface{}{"sub": "user-1", "exp": time.Now().Add(time.Minute).Unix()})
+	interceptor := NewJWTMiddleware(testJWTSecret, true).GRPCUnaryInterceptor()
+
+	var gotUserID string
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		gotUserID = UserIDFromContext(ctx)
+		return "ok", nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	if _, err := interceptor(ctx, nil, testUnaryInfo, handler); err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	if gotUserID != "user-1" {
+		t.Errorf("user id = %q, want user-1", gotUserID)
+	}
+
+	if _, err := interceptor(context.Background(), nil, testUnaryInfo, handler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("missing token code = %v, want Unauthenticated", status.Code(err))
+	}
+
+	// 健康檢查不需要認證
+	healthInfo := &grpc.UnaryServerInfo{FullMethod: grpcHealthServicePrefix + "Check"}
+	if _, err := interceptor(context.Background(), nil, healthInfo, handler); err != nil {
+		t.Errorf("health check rejected: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"chat-gateway/internal/platform/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCTraceIDKey gRPC metadata 中的 trace ID（對應 HTTP 的 X-Request-ID）
+const GRPCTraceIDKey = "x-request-id"
+
+// grpcHealthServicePrefix 負載均衡器頻繁調用健康檢查，成功時不記錄日誌
+const grpcHealthServicePrefix = "/grpc.health.v1.Health/"
+
+// validTraceID 客戶端傳入的 trace ID 只允許安全字符，避免日誌注入
+var validTraceID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// GRPCLoggingUnaryInterceptor gRPC 一元 RPC 日誌攔截器
+// 生成或沿用 trace ID、記錄方法/耗時/狀態碼，並把 panic 轉為 Internal 錯誤
+// 應放在攔截器鏈的最前面，讓後續攔截器（例如 JWT 驗證）的拒絕也能被記錄：
+// grpc.ChainUnaryInterceptor(middleware.GRPCLoggingUnaryInterceptor(), jwtMiddleware.GRPCUnaryInterceptor())
+func GRPCLoggingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		ctx, traceID := grpcTraceContext(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(GRPCTraceIDKey, traceID)) // #nosec G104 -- header is best-effort
+
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				err = grpcPanicError(ctx, info.FullMethod, r)
+			}
+			logGRPCCall(ctx, info.FullMethod, start, err)
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// GRPCLoggingStreamInterceptor gRPC 流式 RPC 日誌攔截器（行為同 GRPCLoggingUnaryInterceptor）
+func GRPCLoggingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		ctx, traceID := grpcTraceContext(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(GRPCTraceIDKey, traceID)) // #nosec G104 -- header is best-effort
+
+		start := time.Now()
+		defer func() {
+			if r := recover(); r != nil {
+				err = grpcPanicError(ctx, info.FullMethod, r)
+			}
+			logGRPCCall(ctx, info.FullMethod, start, err)
+		}()
+
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// contextServerStream 替換 Context() 的 ServerStream（攜帶 trace ID、已認證用戶等）
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context 返回替換後的 context
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcTraceContext 從 metadata 讀取 trace ID（沒有或格式不合法時生成新的），並寫入 context
+func grpcTraceContext(ctx context.Context) (context.Context, string) {
+	traceID := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(GRPCTraceIDKey); len(values) > 0 && validTraceID.MatchString(values[0]) {
+			traceID = values[0]
+		}
+	}
+	if traceID == "" {
+		traceID = logger.NewTraceID()
+	}
+	return logger.WithTraceID(ctx, traceID), traceID
+}
+
+// grpcPanicError 記錄 panic 及堆棧，返回不洩露內部信息的 Internal 錯誤
+func grpcPanicError(ctx context.Context, method string, r interface{}) error {
+	logger.Critical(ctx, "gRPC 處理器 panic",
+		logger.WithAction(method),
+		logger.WithDetails(map[string]interface{}{
+			"panic": fmt.Sprint(r),
+			"stack": string(debug.Stack()),
+		}))
+	return status.Error(codes.Internal, "內部錯誤")
+}
+
+// logGRPCCall 記錄 gRPC 調用的方法、耗時和狀態碼
+func logGRPCCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	if code == codes.OK && strings.HasPrefix(method, grpcHealthServicePrefix) {
+		return
+	}
+
+	logger.Log(ctx, grpcLogSeverity(code), "gRPC 請求",
+		logger.WithAction(method),
+		logger.WithDetails(map[string]interface{}{
+			"grpc_method": method,
+			"grpc_code":   code.String(),
+			"duration_ms": time.Since(start).Milliseconds(),
+		}))
+}
+
+// grpcLogSeverity 根據狀態碼決定日誌級別：服務端錯誤為 ERROR，客戶端錯誤為 WARNING
+func grpcLogSeverity(code codes.Code) logger.Severity {
+	switch code {
+	case codes.OK:
+		return logger.SeverityInfo
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+		codes.Internal, codes.Unavailable, codes.DataLoss:
+		return logger.SeverityError
+	default:
+		return logger.SeverityWarning
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"chat-gateway/internal/platform/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testUnaryInfo = &grpc.UnaryServerInfo{FullMethod: "/chat.ChatRoomService/SendMessage"}
+
+func TestGRPCLoggingUnaryInterceptor_RecoversPanic(t *testing.T) {
+	interceptor := GRPCLoggingUnaryInterceptor()
+
+	resp, err := interceptor(context.Background(), nil, testUnaryInfo, func(context.Context, interface{}) (interface{}, error) {
+		panic("boom")
+	})
+	if resp != nil {
+		t.Errorf("resp = %v, want nil", resp)
+	}
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("code = %v, want Internal", status.Code(err))
+	}
+	if strings.Contains(err.Error(), "boom") {
+		t.Error("panic value should not leak to the client")
+	}
+}
+
+func TestGRPCLoggingUnaryInterceptor_PropagatesTraceID(t *testing.T) {
+	interceptor := GRPCLoggingUnaryInterceptor()
+
+	tests := []struct {
+		name     string
+		incoming string
+		wantSame bool
+	}{
+		{"client trace id", "trace-123", true},
+		{"invalid trace id", "bad id\nwith newline", false},
+		{"missing trace id", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.incoming != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(GRPCTraceIDKey, tt.incoming))
+			}
+
+			var got string
+			_, err := interceptor(ctx, nil, testUnaryInfo, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				got = logger.GetTraceID(ctx)
+				return "ok", nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got == "" {
+				t.Fatal("handler context has no trace id")
+			}
+			if same := strings.HasSuffix(got, "/"+tt.incoming); same != tt.wantSame {
+				t.Errorf("trace id = %q, reuse client id = %v, want %v", got, same, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestGRPCLogSeverity(t *testing.T) {
+	if grpcLogSeverity(codes.OK) != logger.SeverityInfo {
+		t.Error("OK should log at INFO")
+	}
+	if grpcLogSeverity(codes.InvalidArgument) != logger.SeverityWarning {
+		t.Error("client errors should log at WARNING")
+	}
+	if grpcLogSeverity(codes.Internal) != logger.SeverityError {
+		t.Error("server errors should log at ERROR")
+	}
+}
@@ -1,36 +1,124 @@
 package middleware
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"chat-gateway/internal/constants"
+
 	"github.com/gin-gonic/gin"
 )
 
-// RateLimiter 速率限制器
+// RateLimitKeyStrategy 速率限制的計數維度
+type RateLimitKeyStrategy string
+
+const (
+	// RateLimitKeyIP 按客戶端 IP 計數
+	RateLimitKeyIP RateLimitKeyStrategy = constants.RateLimitKeyIP
+	// RateLimitKeyUser 按已認證的用戶 ID 計數，未認證時退回按 IP 計數
+	RateLimitKeyUser RateLimitKeyStrategy = constants.RateLimitKeyUser
+)
+
+// rateLimitKey 根據計數維度生成限制器的 key
+// 只信任 JWT 中間件驗證過的用戶 ID，查詢參數中的 user_id 可被偽造，不作為計數依據
+func rateLimitKey(c *gin.Context, strategy RateLimitKeyStrategy) string {
+	if strategy == RateLimitKeyUser {
+		if userID := c.GetString(UserIDKey); userID != "" {
+			return "user:" + userID
+		}
+	}
+	return "ip:" + GetClientIP(c)
+}
+
+// RateLimiter 速率限制器（滑動窗口計數）
+// 用上一個窗口的計數按剩餘比例加權估算最近一個窗口內的請求數，
+// 避免固定窗口在邊界前後各打滿一次造成 2 倍突發
 type RateLimiter struct {
 	visitors map[string]*Visitor
 	mu       sync.RWMutex
 	rate     int           // 每個時間窗口允許的請求數
 	window   time.Duration // 時間窗口
+	strategy RateLimitKeyStrategy
+	now      func() time.Time
+
+	onExceeded RateLimitExceededFunc
 }
 
+// RateLimitExceededFunc 請求被速率限制拒絕時的回調，簽名與 AuditService.LogRateLimitExceeded 一致
+type RateLimitExceededFunc func(ctx context.Context, ipAddress, endpoint string)
+
 // Visitor 訪問者信息
 type Visitor struct {
-	lastSeen  time.Time
-	requests  int
-	resetTime time.Time
+	lastSeen     time.Time
+	windowStart  time.Time // 當前窗口的開始時間
+	requests     int       // 當前窗口的請求數
+	prevRequests int       // 上一個窗口的請求數
+	lastExceeded time.Time // 上次觸發拒絕回調的時間
+}
+
+// advance 將窗口推進到 now 所在的窗口
+func (v *Visitor) advance(now time.Time, window time.Duration) {
+	elapsed := now.Sub(v.windowStart)
+	if elapsed < window {
+		return
+	}
+
+	if elapsed < 2*window {
+		v.prevRequests = v.requests
+	} else {
+		v.prevRequests = 0
+	}
+	v.requests = 0
+	v.windowStart = v.windowStart.Add(elapsed / window * window)
+}
+
+// estimate 估算最近一個窗口長度內的請求數
+func (v *Visitor) estimate(now time.Time, window time.Duration) float64 {
+	prevWeight := 1 - float64(now.Sub(v.windowStart))/float64(window)
+	return float64(v.prevRequests)*prevWeight + float64(v.requests)
+}
+
+// nextAllowed 計算被拒絕後下一個請求可以通過的時間
+func (v *Visitor) nextAllowed(rate int, window time.Duration) time.Time {
+	windowEnd := v.windowStart.Add(window)
+
+	// 當前窗口的計數已經打滿，要等到下個窗口且本窗口計數的權重降到足夠低
+	if v.requests >= rate {
+		return windowEnd.Add(weightedWait(v.requests, rate-1, window))
+	}
+
+	// 只需等待上一個窗口的權重衰減
+	return v.windowStart.Add(weightedWait(v.prevRequests, rate-1-v.requests, window))
+}
+
+// weightedWait 計數 count 的權重衰減到不超過 budget 需要經過的時間
+func weightedWait(count, budget int, window time.Duration) time.Duration {
+	if count <= 0 || budget >= count {
+		return 0
+	}
+	return time.Duration(float64(window) * (1 - float64(budget)/float64(count)))
 }
 
 // NewRateLimiter 創建新的速率限制器
 // rate: 每個時間窗口允許的請求數
 // window: 時間窗口（例如：time.Minute）
 func NewRateLimiter(rate int, window time.Duration) *RateLimiter {
+	return NewRateLimiterWithStrategy(rate, window, RateLimitKeyIP)
+}
+
+// NewRateLimiterWithStrategy 創建指定計數維度的速率限制器
+func NewRateLimiterWithStrategy(rate int, window time.Duration, strategy RateLimitKeyStrategy) *RateLimiter {
 	rl := &RateLimiter{
 		visitors: make(map[string]*Visitor),
 		rate:     rate,
 		window:   window,
+		strategy: strategy,
+		now:      time.Now,
 	}
 
 	// 啟動清理 goroutine，定期清理過期的訪問者記錄
@@ -42,16 +130,7 @@ func NewRateLimiter(rate int, window time.Duration) *RateLimiter {
 // Middleware 返回 Gin 中間件
 func (rl *RateLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// 獲取客戶端 IP
-		ip := c.ClientIP()
-
-		// 檢查是否超過速率限制
-		if !rl.allowRequest(ip) {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":   "請求過於頻繁，請稍後再試",
-				"success": false,
-			})
-			c.Abort()
+		if !rl.limit(c, 0) {
 			return
 		}
 
@@ -59,43 +138,106 @@ func (rl *RateLimiter) Middleware() gin.HandlerFunc {
 	}
 }
 
-// allowRequest 檢查是否允許請求
-func (rl *RateLimiter) allowRequest(ip string) bool {
+// limit 檢查請求並寫入速率限制頭部，超過限制時返回 429 並中止請求
+// warnPercent 大於 0 時，放行的請求在已用配額達到該百分比後加上 X-RateLimit-Warning
+func (rl *RateLimiter) limit(c *gin.Context, warnPercent int) bool {
+	key := rateLimitKey(c, rl.strategy)
+	allowed, remaining, resetTime := rl.allowRequest(key)
+	setRateLimitHeaders(c, rl.rate, remaining, resetTime, rl.now())
+	if allowed {
+		setRateLimitWarning(c, rl.rate, remaining, warnPercent)
+	}
+
+	if !allowed {
+		if rl.onExceeded != nil && rl.shouldReportExceeded(key) {
+			rl.onExceeded(c.Request.Context(), GetClientIP(c), c.Request.URL.Path)
+		}
+
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"error":   "請求過於頻繁，請稍後再試",
+			"success": false,
+		})
+		c.Abort()
+		return false
+	}
+	return true
+}
+
+// shouldReportExceeded 每個訪問者每個窗口只回調一次，避免持續被拒絕的客戶端刷爆審計日誌
+func (rl *RateLimiter) shouldReportExceeded(key string) bool {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	now := time.Now()
-	visitor, exists := rl.visitors[ip]
-
+	visitor, exists := rl.visitors[key]
 	if !exists {
-		// 新訪問者
-		rl.visitors[ip] = &Visitor{
-			lastSeen:  now,
-			requests:  1,
-			resetTime: now.Add(rl.window),
-		}
 		return true
 	}
 
-	// 檢查時間窗口是否已過期
-	if now.After(visitor.resetTime) {
-		// 重置計數器
-		visitor.requests = 1
-		visitor.resetTime = now.Add(rl.window)
-		visitor.lastSeen = now
-		return true
+	now := rl.now()
+	if !visitor.lastExceeded.IsZero() && now.Sub(visitor.lastExceeded) < rl.window {
+		return false
 	}
+	visitor.lastExceeded = now
+	return true
+}
+
+// setRateLimitHeaders 寫入 X-RateLimit-Limit、X-RateLimit-Remaining 和 Retry-After（距離 resetTime 的秒數）
+func setRateLimitHeaders(c *gin.Context, limit, remaining int, resetTime, now time.Time) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(resetTime, now)))
+}
+
+// setRateLimitWarning 已用配額達到 warnPercent% 時寫入 X-RateLimit-Warning（例如 "85% of rate limit used"）
+// 讓客戶端在被 429 拒絕前主動降低請求頻率
+func setRateLimitWarning(c *gin.Context, limit, remaining, warnPercent int) {
+	if warnPercent <= 0 || limit <= 0 {
+		return
+	}
+	used := limit - remaining
+	if used*100 < limit*warnPercent {
+		return
+	}
+	c.Header("X-RateLimit-Warning", fmt.Sprintf("%d%% of rate limit used", used*100/limit))
+}
+
+// retryAfterSeconds 距離 resetTime 的秒數（向上取整，至少 1 秒）
+func retryAfterSeconds(resetTime, now time.Time) int {
+	seconds := int(math.Ceil(resetTime.Sub(now).Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+// allowRequest 檢查是否允許請求，同時返回剩餘的請求數和重置時間
+// 放行時 resetTime 為當前窗口結束時間，拒絕時為下一個請求可以通過的時間
+func (rl *RateLimiter) allowRequest(key string) (allowed bool, remaining int, resetTime time.Time) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	visitor, exists := rl.visitors[key]
+
+	if !exists {
+		// 新訪問者
+		visitor = &Visitor{windowStart: now}
+		rl.visitors[key] = visitor
+	}
+
+	visitor.advance(now, rl.window)
+	visitor.lastSeen = now
 
 	// 檢查是否超過速率限制
-	if visitor.requests >= rl.rate {
-		visitor.lastSeen = now
-		return false
+	estimate := visitor.estimate(now, rl.window)
+	if estimate+1 > float64(rl.rate) {
+		return false, 0, visitor.nextAllowed(rl.rate, rl.window)
 	}
 
 	// 增加請求計數
 	visitor.requests++
-	visitor.lastSeen = now
-	return true
+	remaining = int(float64(rl.rate) - estimate - 1)
+	return true, remaining, visitor.windowStart.Add(rl.window)
 }
 
 // cleanupVisitors 定期清理過期的訪問者記錄
@@ -105,12 +247,18 @@ func (rl *RateLimiter) cleanupVisitors() {
 
 	for range ticker.C {
 		rl.mu.Lock()
-		now := time.Now()
+		now := rl.now()
 
-		for ip, visitor := range rl.visitors {
-			// 如果訪問者超過 10 分鐘沒有活動，刪除記錄
-			if now.Sub(visitor.lastSeen) > 10*time.Minute {
-				delete(rl.visitors, ip)
+		// 超過兩個窗口沒有活動的訪問者已不影響估算，至少保留 10 分鐘
+		idle := 10 * time.Minute
+		if 2*rl.window > idle {
+			idle = 2 * rl.window
+		}
+
+		for key, visitor := range rl.visitors {
+			// 如果訪問者長時間沒有活動，刪除記錄
+			if now.Sub(visitor.lastSeen) > idle {
+				delete(rl.visitors, key)
 			}
 		}
 
@@ -122,19 +270,65 @@ func (rl *RateLimiter) cleanupVisitors() {
 type PerEndpointRateLimiter struct {
 	limiters map[string]*RateLimiter
 	default_ *RateLimiter
+
+	onExceeded RateLimitExceededFunc
+
+	warnPercent    int            // 默認的警告閾值（已用配額百分比，0 表示不警告）
+	warnThresholds map[string]int // 按端點路徑覆蓋的警告閾值
 }
 
 // NewPerEndpointRateLimiter 創建端點級速率限制器
 func NewPerEndpointRateLimiter(defaultRate int, defaultWindow time.Duration) *PerEndpointRateLimiter {
 	return &PerEndpointRateLimiter{
-		limiters: make(map[string]*RateLimiter),
-		default_: NewRateLimiter(defaultRate, defaultWindow),
+		limiters:       make(map[string]*RateLimiter),
+		default_:       NewRateLimiter(defaultRate, defaultWindow),
+		warnThresholds: make(map[string]int),
 	}
 }
 
-// SetLimit 為特定端點設置限制
+// SetWarnThreshold 設置默認的警告閾值（已用配額百分比，0 表示不警告）
+func (p *PerEndpointRateLimiter) SetWarnThreshold(percent int) {
+	p.warnPercent = percent
+}
+
+// SetEndpointWarnThreshold 為特定端點設置警告閾值，優先於默認閾值（0 表示該端點不警告）
+func (p *PerEndpointRateLimiter) SetEndpointWarnThreshold(path string, percent int) {
+	p.warnThresholds[path] = percent
+}
+
+// warnThreshold 端點的警告閾值
+func (p *PerEndpointRateLimiter) warnThreshold(path string) int {
+	if percent, ok := p.warnThresholds[path]; ok {
+		return percent
+	}
+	return p.warnPercent
+}
+
+// SetDefaultStrategy 設置默認限制器的計數維度
+func (p *PerEndpointRateLimiter) SetDefaultStrategy(strategy RateLimitKeyStrategy) {
+	p.default_.strategy = strategy
+}
+
+// SetLimit 為特定端點設置限制（按 IP 計數）
 func (p *PerEndpointRateLimiter) SetLimit(path string, rate int, window time.Duration) {
-	p.limiters[path] = NewRateLimiter(rate, window)
+	p.SetLimitWithStrategy(path, rate, window, RateLimitKeyIP)
+}
+
+// SetLimitWithStrategy 為特定端點設置限制並指定計數維度
+func (p *PerEndpointRateLimiter) SetLimitWithStrategy(path string, rate int, window time.Duration, strategy RateLimitKeyStrategy) {
+	limiter := NewRateLimiterWithStrategy(rate, window, strategy)
+	limiter.onExceeded = p.onExceeded
+	p.limiters[path] = limiter
+}
+
+// SetOnLimitExceeded 設置請求被拒絕時的回調（例如審計日誌），對默認和所有端點限制器生效
+// 傳入 nil 表示不回調
+func (p *PerEndpointRateLimiter) SetOnLimitExceeded(fn RateLimitExceededFunc) {
+	p.onExceeded = fn
+	p.default_.onExceeded = fn
+	for _, limiter := range p.limiters {
+		limiter.onExceeded = fn
+	}
 }
 
 // Middleware 返回 Gin 中間件
@@ -142,28 +336,13 @@ func (p *PerEndpointRateLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		path := c.Request.URL.Path
 
-		// 檢查是否有特定端點的限制器
-		if limiter, exists := p.limiters[path]; exists {
-			ip := c.ClientIP()
-			if !limiter.allowRequest(ip) {
-				c.JSON(http.StatusTooManyRequests, gin.H{
-					"error":   "請求過於頻繁，請稍後再試",
-					"success": false,
-				})
-				c.Abort()
-				return
-			}
-		} else {
-			// 使用默認限制器
-			ip := c.ClientIP()
-			if !p.default_.allowRequest(ip) {
-				c.JSON(http.StatusTooManyRequests, gin.H{
-					"error":   "請求過於頻繁，請稍後再試",
-					"success": false,
-				})
-				c.Abort()
-				return
-			}
+		// 檢查是否有特定端點的限制器，沒有則使用默認限制器
+		limiter, exists := p.limiters[path]
+		if !exists {
+			limiter = p.default_
+		}
+		if !limiter.limit(c, p.warnThreshold(path)) {
+			return
 		}
 
 		c.Next()
@@ -0,0 +1,323 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPerEndpointRateLimiter_KeyStrategy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	token := func(userID string) string {
+		return "Bearer " + signTestToken(t, testJWTSecret,
+			map[string]interface{}{"alg": "HS256", "typ": "JWT"},
+			map[string]interface{}{"sub": userID, "exp": time.Now().Add(time.Minute).Unix()})
+	}
+
+	tests := []struct {
+		name     string
+		strategy RateLimitKeyStrategy
+		jwt      bool
+		requests []string // 每個請求的 Authorization 頭部
+		want     []int
+	}{
+		{
+			name:     "ip strategy shares limit across users",
+			strategy: RateLimitKeyIP,
+			jwt:      true,
+			requests: []string{token("alice"), token("bob")},
+			want:     []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:     "user strategy limits each user separately",
+			strategy: RateLimitKeyUser,
+			jwt:      true,
+			requests: []string{token("alice"), token("bob"), token("alice")},
+			want:     []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:     "user strategy falls back to ip when unauthenticated",
+			strategy: RateLimitKeyUser,
+			jwt:      false,
+			requests: []string{"", ""},
+			want:     []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewPerEndpointRateLimiter(100, time.Minute)
+			limiter.SetLimitWithStrategy("/limited", 1, time.Minute, tt.strategy)
+
+			r := gin.New()
+			r.Use(RequestMetadataMiddleware())
+			r.GET("/limited", NewJWTMiddleware(testJWTSecret, tt.jwt).GinMiddleware(), limiter.Middleware(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			for i, auth := range tt.requests {
+				// 未認證時查詢參數中的 user_id 不能用來繞過限制
+				req := httptest.NewRequest(http.MethodGet, "/limited?user_id=spoofed-"+string(rune('a'+i)), nil)
+				if auth != "" {
+					req.Header.Set("Authorization", auth)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				if w.Code != tt.want[i] {
+					t.Fatalf("request %d status = %d, want %d", i, w.Code, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiter_Headers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1700000000, 0)
+	limiter := NewRateLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+	r := gin.New()
+	r.GET("/", limiter.Middleware(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		wantStatus     int
+		wantRemaining  string
+		wantRetryAfter string
+	}{
+		{http.StatusOK, "1", "60"},
+		{http.StatusOK, "0", "60"},
+		// 當前窗口已打滿：下個窗口開始後還要等本窗口計數的權重衰減一半
+		{http.StatusTooManyRequests, "0", "90"},
+	}
+
+	for i, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if w.Code != tt.wantStatus {
+			t.Fatalf("request %d status = %d, want %d", i, w.Code, tt.wantStatus)
+		}
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d X-RateLimit-Limit = %q, want 2", i, got)
+		}
+		if got := w.Header().Get("X-RateLimit-Remaining"); got != tt.wantRemaining {
+			t.Errorf("request %d X-RateLimit-Remaining = %q, want %s", i, got, tt.wantRemaining)
+		}
+		if got := w.Header().Get("Retry-After"); got != tt.wantRetryAfter {
+			t.Errorf("request %d Retry-After = %q, want %s", i, got, tt.wantRetryAfter)
+		}
+	}
+}
+
+func TestRateLimiter_SlidingWindowPreventsBoundaryBurst(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start
+	limiter := NewRateLimiter(10, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	allowedCount := func(n int) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			if allowed, _, _ := limiter.allowRequest("client"); allowed {
+				count++
+			}
+		}
+		return count
+	}
+
+	// 第一個請求開啟窗口，窗口結束前打滿剩餘額度
+	if got := allowedCount(1); got != 1 {
+		t.Fatalf("first request allowed = %d, want 1", got)
+	}
+	now = start.Add(59 * time.Second)
+	if got := allowedCount(9); got != 9 {
+		t.Fatalf("end of window allowed = %d, want 9", got)
+	}
+
+	// 固定窗口在這裡會再放行 10 個請求（兩秒內 20 個）
+	now = start.Add(61 * time.Second)
+	if got := allowedCount(10); got != 0 {
+		t.Errorf("right after window boundary allowed = %d, want 0", got)
+	}
+
+	// 上一個窗口過去一半後，額度按比例恢復
+	now = start.Add(90 * time.Second)
+	if got := allowedCount(10); got != 5 {
+		t.Errorf("half way through next window allowed = %d, want 5", got)
+	}
+
+	// 閒置超過兩個窗口後恢復全部額度
+	now = start.Add(5 * time.Minute)
+	if got := allowedCount(20); got != 10 {
+		t.Errorf("after idle allowed = %d, want 10", got)
+	}
+}
+
+func TestRateLimiter_RetryAfterIsAccurate(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	now := start
+	limiter := NewRateLimiter(4, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		limiter.allowRequest("client")
+	}
+	allowed, _, resetTime := limiter.allowRequest("client")
+	if allowed {
+		t.Fatal("request over the limit should be rejected")
+	}
+
+	// resetTime 之前仍然被拒絕，到達 resetTime 時放行
+	now = resetTime.Add(-time.Second)
+	if allowed, _, _ := limiter.allowRequest("client"); allowed {
+		t.Errorf("request before resetTime %v should be rejected", resetTime.Sub(start))
+	}
+	now = resetTime
+	if allowed, _, _ := limiter.allowRequest("client"); !allowed {
+		t.Errorf("request at resetTime %v should be allowed", resetTime.Sub(start))
+	}
+}
+
+func TestPerEndpointRateLimiter_OnLimitExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type exceeded struct{ ip, endpoint string }
+	var got []exceeded
+
+	now := time.Unix(1700000000, 0)
+	limiter := NewPerEndpointRateLimiter(100, time.Minute)
+	limiter.SetOnLimitExceeded(func(_ context.Context, ip, endpoint string) {
+		got = append(got, exceeded{ip, endpoint})
+	})
+	limiter.SetLimit("/limited", 1, time.Minute)
+	limiter.limiters["/limited"].now = func() time.Time { return now }
+
+	r := gin.New()
+	r.Use(limiter.Middleware())
+	r.GET("/limited", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 第一個請求放行，不回調；之後同一窗口內的拒絕只回調一次
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		if code := send(); code != want {
+			t.Fatalf("request %d status = %d, want %d", i, code, want)
+		}
+	}
+	if len(got) != 1 || got[0] != (exceeded{"192.0.2.1", "/limited"}) {
+		t.Fatalf("exceeded callbacks = %v, want one for 192.0.2.1 /limited", got)
+	}
+
+	// 一個窗口之後再次被拒絕時重新回調
+	now = now.Add(time.Minute)
+	if code := send(); code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", code)
+	}
+	if len(got) != 2 {
+		t.Errorf("exceeded callbacks = %d, want 2", len(got))
+	}
+}
+
+func TestPerEndpointRateLimiter_WarnThreshold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	now := time.Unix(1700000000, 0)
+	limiter := NewPerEndpointRateLimiter(100, time.Minute)
+	limiter.SetWarnThreshold(80)
+	limiter.SetLimit("/limited", 5, time.Minute)
+	limiter.SetLimit("/quiet", 5, time.Minute)
+	limiter.SetEndpointWarnThreshold("/quiet", 0)
+	for _, l := range limiter.limiters {
+		l.now = func() time.Time { return now }
+	}
+
+	r := gin.New()
+	r.Use(limiter.Middleware())
+	r.GET("/limited", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/quiet", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// 5 次配額：第 4 次（80%）開始警告，請求仍然放行；超過配額後返回 429，不帶警告
+	wantWarnings := []string{"", "", "", "80% of rate limit used", "100% of rate limit used"}
+	for i, want := range wantWarnings {
+		w := send("/limited")
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200", i, w.Code)
+		}
+		if got := w.Header().Get("X-RateLimit-Warning"); got != want {
+			t.Errorf("request %d X-RateLimit-Warning = %q, want %q", i, got, want)
+		}
+	}
+	if w := send("/limited"); w.Code != http.StatusTooManyRequests || w.Header().Get("X-RateLimit-Warning") != "" {
+		t.Errorf("rejected request status = %d, warning = %q", w.Code, w.Header().Get("X-RateLimit-Warning"))
+	}
+
+	// 端點覆蓋為 0 時不警告
+	for i := 0; i < 5; i++ {
+		if got := send("/quiet").Header().Get("X-RateLimit-Warning"); got != "" {
+			t.Errorf("/quiet request %d X-RateLimit-Warning = %q, want none", i, got)
+		}
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		reset time.Time
+		want  int
+	}{
+		{now.Add(30 * time.Second), 30},
+		{now.Add(1500 * time.Millisecond), 2},
+		{now, 1},
+		{now.Add(-time.Second), 1},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfterSeconds(tt.reset, now); got != tt.want {
+			t.Errorf("retryAfterSeconds(%v) = %d, want %d", tt.reset.Sub(now), got, tt.want)
+		}
+	}
+}
+
+func TestJWTMiddleware_OverridesRequestMetadataUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var got string
+	r := gin.New()
+	r.Use(RequestMetadataMiddleware())
+	r.GET("/", NewJWTMiddleware(testJWTSecret, true).GinMiddleware(), func(c *gin.Context) {
+		got = GetRequestMetadataFromGin(c).UserID
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/?user_id=mallory", nil)
+	req.Header.Set("Authorization", "Bearer "+signTestToken(t, testJWTSecret,
+		map[string]interface{}{"alg": "HS256", "typ": "JWT"},
+		map[string]interface{}{"sub": "alice", "exp": time.Now().Add(time.Minute).Unix()}))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got != "alice" {
+		t.Errorf("metadata user id = %q, want %q", got, "alice")
+	}
+}
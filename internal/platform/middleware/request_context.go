@@ -2,6 +2,11 @@ package middleware
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"chat-gateway/internal/platform/config"
 
 	"github.com/gin-gonic/gin"
 )
@@ -41,20 +46,49 @@ func RequestMetadataMiddleware() gin.HandlerFunc {
 }
 
 // GetClientIP 獲取客戶端真實 IP
+// 只有配置了受信任代理數量（server.trusted_proxy_count）時才解析代理頭部，否則直接使用連接的遠端地址，
+// 避免客戶端偽造 X-Forwarded-For 繞過速率限制或污染審計日誌
 func GetClientIP(c *gin.Context) string {
-	// 優先從 X-Forwarded-For 頭部獲取（反向代理）
-	if forwarded := c.Request.Header.Get("X-Forwarded-For"); forwarded != "" {
-		// X-Forwarded-For 可能包含多個 IP，取第一個
-		return forwarded
+	trustedProxies := 0
+	if cfg := config.Get(); cfg != nil {
+		trustedProxies = cfg.Server.TrustedProxyCount
+	}
+	return clientIP(c.Request, c.RemoteIP(), trustedProxies)
+}
+
+// clientIP 根據受信任代理數量解析客戶端 IP
+// X-Forwarded-For 中每經過一個代理會在右側追加它看到的對端地址，最右邊的 trustedProxies 個條目由受信任代理添加，
+// 其中最左的一個（從右數第 trustedProxies 個）是最外層受信任代理看到的客戶端地址；更左邊的條目可能由客戶端偽造，不予採用
+func clientIP(r *http.Request, remoteIP string, trustedProxies int) string {
+	if trustedProxies <= 0 {
+		return remoteIP
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		var hops []string
+		for _, value := range forwarded {
+			for _, hop := range strings.Split(value, ",") {
+				hops = append(hops, strings.TrimSpace(hop))
+			}
+		}
+
+		// 條目少於受信任代理數量時取最左邊的條目
+		index := len(hops) - trustedProxies
+		if index < 0 {
+			index = 0
+		}
+		if ip := net.ParseIP(hops[index]); ip != nil {
+			return ip.String()
+		}
+		return remoteIP
 	}
 
-	// 從 X-Real-IP 頭部獲取
-	if realIP := c.Request.Header.Get("X-Real-IP"); realIP != "" {
-		return realIP
+	// 從 X-Real-IP 頭部獲取（由受信任代理設置）
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
 	}
 
-	// 直接獲取遠程地址
-	return c.ClientIP()
+	return remoteIP
 }
 
 // GetRequestMetadata 從 context 獲取請求元數據
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	const remote = "10.0.0.1"
+
+	tests := []struct {
+		name           string
+		trustedProxies int
+		forwardedFor   []string
+		realIP         string
+		want           string
+	}{
+		{"no trusted proxy ignores headers", 0, []string{"1.2.3.4"}, "5.6.7.8", remote},
+		{"single proxy", 1, []string{"1.2.3.4"}, "", "1.2.3.4"},
+		{"spoofed entry left of trusted hop", 1, []string{"6.6.6.6, 1.2.3.4"}, "", "1.2.3.4"},
+		{"two proxies", 2, []string{"6.6.6.6, 1.2.3.4, 172.16.0.1"}, "", "1.2.3.4"},
+		{"multiple headers", 2, []string{"6.6.6.6, 1.2.3.4", "172.16.0.1"}, "", "1.2.3.4"},
+		{"fewer entries than proxies", 3, []string{"1.2.3.4, 172.16.0.1"}, "", "1.2.3.4"},
+		{"invalid entry", 1, []string{"1.2.3.4, not-an-ip"}, "", remote},
+		{"ipv6", 1, []string{" 2001:db8::1 "}, "", "2001:db8::1"},
+		{"real ip", 1, nil, "1.2.3.4", "1.2.3.4"},
+		{"invalid real ip", 1, nil, "1.2.3.4, 5.6.7.8", remote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := clientIP(req, remote, tt.trustedProxies); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"chat-gateway/internal/platform/logger"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -11,18 +13,21 @@ const (
 )
 
 // RequestIDMiddleware 為每個請求生成唯一 ID
+// Request ID 同時作為 trace ID：寫入請求 context 供 HTTP 端日誌使用，並通過 gRPC metadata 轉發給 gRPC 服務
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 優先使用客戶端提供的 Request ID（如果有的話）
+		// 格式與 gRPC 端接受的 trace ID 相同，否則 gRPC 端會另外生成，兩端日誌無法關聯
 		requestID := c.GetHeader(RequestIDHeader)
 
-		// 如果客戶端沒有提供，生成新的 UUID
-		if requestID == "" {
+		// 如果客戶端沒有提供或格式不合法，生成新的 UUID
+		if !validTraceID.MatchString(requestID) {
 			requestID = uuid.New().String()
 		}
 
 		// 將 Request ID 設置到 context
 		c.Set(RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(logger.WithTraceID(c.Request.Context(), requestID))
 
 		// 將 Request ID 添加到響應頭
 		c.Header(RequestIDHeader, requestID)
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"chat-gateway/internal/platform/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{"client id is kept", "req-123.abc_DEF", true},
+		{"missing id is generated", "", false},
+		{"invalid characters are replaced", "bad id\nforged", false},
+		{"overlong id is replaced", strings.Repeat("a", 65), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(RequestIDMiddleware())
+
+			var requestID, traceID string
+			r.GET("/", func(c *gin.Context) {
+				requestID = GetRequestID(c)
+				traceID = logger.GetTraceID(c.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if (requestID == tt.header) != tt.wantSame {
+				t.Errorf("request ID = %q, header %q, wantSame %v", requestID, tt.header, tt.wantSame)
+			}
+			if !validTraceID.MatchString(requestID) {
+				t.Errorf("request ID %q would be rejected by the gRPC server", requestID)
+			}
+			if got := w.Header().Get(RequestIDHeader); got != requestID {
+				t.Errorf("response header = %q, want %q", got, requestID)
+			}
+			if !strings.HasSuffix(traceID, "/traces/"+requestID) {
+				t.Errorf("trace ID = %q, want it to end with the request ID", traceID)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -40,10 +41,10 @@ func NewSSEConnectionLimiter(maxPerIP int, minInterval time.Duration, maxTotal i
 // Middleware SSE 連接限制中間件
 func (l *SSEConnectionLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
+		clientIP := GetClientIP(c)
 
-		// 檢查是否允許連接
-		if !l.allowConnection(clientIP) {
+		// 檢查並註冊連接（在同一把鎖內完成，並發請求不會同時通過上限檢查）
+		if !l.acquire(clientIP) {
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":   "SSE 連接數已達上限，請稍後再試",
 				"success": false,
@@ -52,31 +53,31 @@ func (l *SSEConnectionLimiter) Middleware() gin.HandlerFunc {
 			return
 		}
 
-		// 註冊連接
-		l.registerConnection(clientIP)
-
-		// 使用 defer 確保連接關閉時移除
+		// 處理器返回時釋放連接：無論是客戶端斷開、訊息流正常結束，還是建立 gRPC 流失敗提前返回，都只釋放一次
+		defer l.removeConnection(clientIP)
 		c.Writer.Header().Set("X-SSE-Connection-Registered", "true")
 
-		// 創建一個完成通道
-		done := make(chan struct{})
-
-		// 在連接關閉時清理
-		go func() {
-			<-c.Request.Context().Done()
-			close(done)
-			l.removeConnection(clientIP)
-		}()
-
 		c.Next()
 	}
 }
 
-// allowConnection 檢查是否允許建立新連接
-func (l *SSEConnectionLimiter) allowConnection(ip string) bool {
+// acquire 允許建立新連接時註冊連接並返回 true
+func (l *SSEConnectionLimiter) acquire(ip string) bool {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	if !l.allowConnection(ip) {
+		return false
+	}
+
+	l.connections[ip]++
+	l.currentTotalConns++
+	l.lastConnect[ip] = time.Now()
+	return true
+}
+
+// allowConnection 檢查是否允許建立新連接（調用方持有鎖）
+func (l *SSEConnectionLimiter) allowConnection(ip string) bool {
 	// 檢查全局連接數限制
 	if l.currentTotalConns >= l.maxTotalConns {
 		return false
@@ -97,16 +98,6 @@ func (l *SSEConnectionLimiter) allowConnection(ip string) bool {
 	return true
 }
 
-// registerConnection 註冊新連接
-func (l *SSEConnectionLimiter) registerConnection(ip string) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	l.connections[ip]++
-	l.currentTotalConns++
-	l.lastConnect[ip] = time.Now()
-}
-
 // removeConnection 移除連接
 func (l *SSEConnectionLimiter) removeConnection(ip string) {
 	l.mu.Lock()
@@ -156,3 +147,31 @@ func (l *SSEConnectionLimiter) Stats() map[string]interface{} {
 		"max_per_ip":        l.maxPerIP,
 	}
 }
+
+// SSEIPConnections 單個 IP 當前的 SSE 連接數
+type SSEIPConnections struct {
+	IP          string    `json:"ip"`
+	Connections int       `json:"connections"`
+	LastConnect time.Time `json:"last_connect"`
+}
+
+// ConnectionsByIP 返回連接數最多的 limit 個 IP（按連接數降序，相同時按 IP 排序），用於排查連接數耗盡
+func (l *SSEConnectionLimiter) ConnectionsByIP(limit int) []SSEIPConnections {
+	l.mu.RLock()
+	result := make([]SSEIPConnections, 0, len(l.connections))
+	for ip, count := range l.connections {
+		result = append(result, SSEIPConnections{IP: ip, Connections: count, LastConnect: l.lastConnect[ip]})
+	}
+	l.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Connections != result[j].Connections {
+			return result[i].Connections > result[j].Connections
+		}
+		return result[i].IP < result[j].IP
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
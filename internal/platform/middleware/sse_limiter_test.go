@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSSEConnectionLimiter_ConnectionsByIP(t *testing.T) {
+	l := NewSSEConnectionLimiter(10, 0, 100)
+	for _, ip := range []string{"10.0.0.2", "10.0.0.1", "10.0.0.1", "10.0.0.3", "10.0.0.3", "10.0.0.3"} {
+		l.acquire(ip)
+	}
+	l.removeConnection("10.0.0.2")
+
+	got := l.ConnectionsByIP(0)
+	want := []struct {
+		ip    string
+		count int
+	}{{"10.0.0.3", 3}, {"10.0.0.1", 2}}
+	if len(got) != len(want) {
+		t.Fatalf("ConnectionsByIP() = %+v, want %d entries", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].IP != w.ip || got[i].Connections != w.count {
+			t.Errorf("entry %d = %s:%d, want %s:%d", i, got[i].IP, got[i].Connections, w.ip, w.count)
+		}
+		if time.Since(got[i].LastConnect) > time.Minute {
+			t.Errorf("entry %d last connect = %v", i, got[i].LastConnect)
+		}
+	}
+
+	if limited := l.ConnectionsByIP(1); len(limited) != 1 || limited[0].IP != "10.0.0.3" {
+		t.Errorf("ConnectionsByIP(1) = %+v, want only 10.0.0.3", limited)
+	}
+	if stats := l.Stats(); stats["total_connections"] != 5 || stats["unique_ips"] != 2 {
+		t.Errorf("Stats() = %v", stats)
+	}
+}
+
+func TestSSEConnectionLimiter_ReleasesOnHandlerReturn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l := NewSSEConnectionLimiter(1, 0, 10)
+
+	r := gin.New()
+	r.GET("/stream", l.Middleware(), func(c *gin.Context) {
+		// 模擬建立 gRPC 流失敗，處理器在請求 context 結束前就返回
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "stream failed"})
+	})
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("request %d status = %d, want 500 (connection should have been released)", i, w.Code)
+		}
+	}
+	if stats := l.Stats(); stats["total_connections"] != 0 || stats["unique_ips"] != 0 {
+		t.Errorf("Stats() after handlers returned = %v, want no connections", stats)
+	}
+}
+
+func TestSSEConnectionLimiter_RejectedDoesNotRelease(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	l := NewSSEConnectionLimiter(1, 0, 10)
+	if !l.acquire("192.0.2.1") {
+		t.Fatal("acquire() = false, want true")
+	}
+
+	r := gin.New()
+	r.GET("/stream", l.Middleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	if stats := l.Stats(); stats["total_connections"] != 1 {
+		t.Errorf("total_connections = %v, want the existing connection to remain", stats["total_connections"])
+	}
+}
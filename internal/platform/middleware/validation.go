@@ -3,10 +3,13 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 
 	"chat-gateway/internal/constants"
 	"chat-gateway/internal/platform/config"
+	"chat-gateway/proto/chat"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +20,13 @@ type ValidationError struct {
 	Message string `json:"message"`
 }
 
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// DirectRoomMaxMembers 私聊固定為兩個成員
+const DirectRoomMaxMembers = 2
+
 // ValidateMessageContent 驗證訊息內容
 func ValidateMessageContent(content string) error {
 	if strings.TrimSpace(content) == "" {
@@ -33,6 +43,11 @@ func ValidateMessageContent(content string) error {
 		return fmt.Errorf("訊息內容超過最大長度限制 (%d 字符)", maxLength)
 	}
 
+	// 無效的 UTF-8 一旦加密存儲就無法再正常讀取，發送時直接拒絕
+	if !utf8.ValidString(content) {
+		return fmt.Errorf("訊息內容不是有效的 UTF-8 編碼")
+	}
+
 	// 防止 NULL 字符注入
 	if strings.Contains(content, "\x00") {
 		return fmt.Errorf("訊息內容包含非法字符")
@@ -106,6 +121,80 @@ func ValidateRoomID(roomID string) error {
 	return nil
 }
 
+// DefaultRoomSettings 創建聊天室時未提供設置使用的默認值（允許邀請、編輯和刪除訊息，人數上限按類型確定）
+func DefaultRoomSettings() *chat.RoomSettings {
+	return &chat.RoomSettings{
+		AllowInvite:         true,
+		AllowEditMessages:   true,
+		AllowDeleteMessages: true,
+	}
+}
+
+// ValidateRoomSettings 驗證並規範化創建聊天室時的設置（HTTP 和 gRPC 共用）
+// settings 為空時使用 DefaultRoomSettings；私聊最多 2 人，群聊最多為配置的上限，max_members 為 0 時使用該上限
+func ValidateRoomSettings(roomType string, settings *chat.RoomSettings) (*chat.RoomSettings, error) {
+	if settings == nil {
+		settings = DefaultRoomSettings()
+	}
+
+	limit := maxRoomMembers()
+	if roomType == "direct" {
+		limit = DirectRoomMaxMembers
+	}
+	switch {
+	case settings.MaxMembers == 0:
+		settings.MaxMembers = int32(limit) // #nosec G115 -- 上限來自配置，遠小於 int32 範圍
+	case settings.MaxMembers < 0 || int(settings.MaxMembers) > limit:
+		return nil, &ValidationError{Field: "max_members", Message: fmt.Sprintf("最大成員數必須在 1 到 %d 之間", limit)}
+	}
+
+	if err := ValidateWelcomeMessage(settings.WelcomeMessage); err != nil {
+		return nil, err
+	}
+	if err := ValidateLocale(settings.Locale); err != nil {
+		return nil, err
+	}
+	settings.Locale = strings.ToLower(settings.Locale)
+
+	return settings, nil
+}
+
+// localePattern BCP 47 語言標籤的簡化格式（如 en、zh-TW、pt-BR）
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+
+// ValidateLocale 驗證系統訊息語言（可以為空，表示使用默認語言）
+func ValidateLocale(locale string) error {
+	if locale == "" {
+		return nil
+	}
+	if len(locale) > constants.MaxLocaleLength || !localePattern.MatchString(locale) {
+		return &ValidationError{Field: "locale", Message: "無效的語言標籤（例如 en、zh-TW）"}
+	}
+	return nil
+}
+
+// ValidateWelcomeMessage 驗證歡迎訊息（可以為空）
+func ValidateWelcomeMessage(message string) error {
+	if len(message) > constants.MaxWelcomeMessageLength {
+		return &ValidationError{
+			Field:   "welcome_message",
+			Message: fmt.Sprintf("歡迎訊息超過最大長度限制 (%d 字符)", constants.MaxWelcomeMessageLength),
+		}
+	}
+	if strings.Contains(message, "\x00") {
+		return &ValidationError{Field: "welcome_message", Message: "歡迎訊息包含非法字符"}
+	}
+	return nil
+}
+
+// maxRoomMembers 群聊最大成員數（配置未設置時使用默認值）
+func maxRoomMembers() int {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Room.MaxMembers > 0 {
+		return cfg.Limits.Room.MaxMembers
+	}
+	return constants.DefaultMaxRoomMembers
+}
+
 // SanitizeInput 消毒輸入（移除危險字符）
 func SanitizeInput(input string) string {
 	// 移除 NULL 字符
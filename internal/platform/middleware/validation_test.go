@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/proto/chat"
+)
+
+func TestValidateRoomSettings(t *testing.T) {
+	tests := []struct {
+		name           string
+		roomType       string
+		settings       *chat.RoomSettings
+		wantMaxMembers int32
+		wantField      string
+	}{
+		{"nil group uses defaults", "group", nil, constants.DefaultMaxRoomMembers, ""},
+		{"nil direct capped to two", "direct", nil, DirectRoomMaxMembers, ""},
+		{"group custom limit", "group", &chat.RoomSettings{MaxMembers: 50}, 50, ""},
+		{"group over limit", "group", &chat.RoomSettings{MaxMembers: constants.DefaultMaxRoomMembers + 1}, 0, "max_members"},
+		{"negative limit", "group", &chat.RoomSettings{MaxMembers: -1}, 0, "max_members"},
+		{"direct over two", "direct", &chat.RoomSettings{MaxMembers: 3}, 0, "max_members"},
+		{"welcome too long", "group", &chat.RoomSettings{WelcomeMessage: strings.Repeat("a", constants.MaxWelcomeMessageLength+1)}, 0, "welcome_message"},
+		{"welcome with null", "group", &chat.RoomSettings{WelcomeMessage: "hi\x00"}, 0, "welcome_message"},
+		{"valid locale", "group", &chat.RoomSettings{Locale: "zh-TW"}, constants.DefaultMaxRoomMembers, ""},
+		{"invalid locale", "group", &chat.RoomSettings{Locale: "english (us)"}, 0, "locale"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateRoomSettings(tt.roomType, tt.settings)
+			if tt.wantField != "" {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+					t.Fatalf("ValidateRoomSettings() error = %v, want field %q", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateRoomSettings() error = %v", err)
+			}
+			if got.MaxMembers != tt.wantMaxMembers {
+				t.Errorf("MaxMembers = %d, want %d", got.MaxMembers, tt.wantMaxMembers)
+			}
+		})
+	}
+}
+
+func TestDefaultRoomSettings(t *testing.T) {
+	settings := DefaultRoomSettings()
+	if !settings.AllowInvite || !settings.AllowEditMessages || !settings.AllowDeleteMessages || settings.AllowPinMessages {
+		t.Errorf("DefaultRoomSettings() = %+v", settings)
+	}
+}
+
+func TestValidateMessageContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"plain text", "hello", false},
+		{"multibyte", "你好 👋", false},
+		{"empty", "", true},
+		{"too long", strings.Repeat("a", constants.DefaultMaxMessageLength+1), true},
+		{"nul character", "a\x00b", true},
+		{"invalid utf-8", "bad \xff\xfe bytes", true},
+		{"truncated multibyte", "你"[:2], true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateMessageContent(tt.content); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMessageContent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package server
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/grpcclient"
+	"chat-gateway/internal/httputil"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/proto/chat"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerAdminRoutes 在 /api/v1 下註冊管理端點（api 分組已掛載 JWT 中間件）
+// 密鑰狀態、SSE 連接統計和聊天室列表需要分別在配置中開啟，且必須同時啟用 JWT 認證，避免公開暴露
+func registerAdminRoutes(api *gin.RouterGroup, sseLimiter *middleware.SSEConnectionLimiter) {
+	cfg := config.Get()
+	if cfg == nil {
+		return
+	}
+	keyDebug := cfg.Security.Encryption.KeyDebugEnabled
+	sseStats := cfg.Limits.SSE.StatsEnabled
+	roomAdmin := len(cfg.Security.Authentication.AdminUserIDs) > 0
+	if !keyDebug && !sseStats && !roomAdmin {
+		return
+	}
+
+	if !cfg.Security.Authentication.JWTEnabled {
+		logger.Warning(context.Background(), "已開啟管理端點但未啟用 JWT 認證，不註冊管理端點")
+		return
+	}
+
+	admin := api.Group("/admin")
+	if keyDebug {
+		admin.GET("/keys/stats", getKeyStats)
+		admin.GET("/keys/:room_id", getRoomKeyInfo)
+	}
+	if sseStats {
+		admin.GET("/sse/stats", getSSEStats(sseLimiter))
+	}
+	if roomAdmin {
+		admin.GET("/rooms", listRooms)
+		admin.GET("/rooms/:room_id/messages", getMessagesPaged)
+		admin.POST("/import/rooms", importRooms)
+		admin.POST("/import/messages", importMessages)
+	}
+}
+
+// sseStatsTopIPs SSE 連接統計中返回的 IP 數量上限
+const sseStatsTopIPs = 100
+
+// isAdminUser 已認證的用戶是否在 admin_user_ids 中（必須啟用 JWT，與 gRPC 服務的管理員判斷一致）
+func isAdminUser(cfg *config.Config, authUserID string) bool {
+	if cfg == nil || !cfg.Security.Authentication.JWTEnabled || authUserID == "" {
+		return false
+	}
+	return slices.Contains(cfg.Security.Authentication.AdminUserIDs, authUserID)
+}
+
+// 獲取 SSE 連接統計（包含連接數最多的 IP，用於排查「無法連接」是否因為達到連接上限）
+// 統計包含客戶端 IP，只允許管理員查看
+func getSSEStats(sseLimiter *middleware.SSEConnectionLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isAdminUser(config.Get(), c.GetString(middleware.UserIDKey)) {
+			httputil.Forbidden(c, "只有管理員可以查看 SSE 連接統計")
+			return
+		}
+		c.JSON(200, gin.H{
+			"success": true,
+			"data": gin.H{
+				"stats":       sseLimiter.Stats(),
+				"by_ip":       sseLimiter.ConnectionsByIP(sseStatsTopIPs),
+				"by_ip_limit": sseStatsTopIPs,
+				// 因客戶端處理過慢而丟棄的訊息總數（drop_oldest 策略）
+				"dropped_messages": sseDroppedMessages.Load(),
+			},
+		})
+	}
+}
+
+// 獲取密鑰管理器統計
+func getKeyStats(c *gin.Context) {
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.GetKeyStatsResponse, error) {
+		return client.GetKeyStats(ctx, &chat.GetKeyStatsRequest{})
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"data": gin.H{
+			"total_keys":    resp.TotalKeys,
+			"active_keys":   resp.ActiveKeys,
+			"archived_keys": resp.ArchivedKeys,
+			"revoked_keys":  resp.RevokedKeys,
+			"cache_hits":    resp.CacheHits,
+			"cache_misses":  resp.CacheMisses,
+		},
+	})
+}
+
+// 獲取聊天室密鑰狀態（不包含密鑰值）
+func getRoomKeyInfo(c *gin.Context) {
+	roomID := c.Param("room_id")
+	if err := middleware.ValidateRoomID(roomID); err != nil {
+		httputil.ValidationError(c, "room_id", err.Error())
+		return
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.GetRoomKeyInfoResponse, error) {
+		return client.GetRoomKeyInfo(ctx, &chat.GetRoomKeyInfoRequest{RoomId: roomID})
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"data": gin.H{
+			"room_id":           resp.RoomId,
+			"key_version":       resp.KeyVersion,
+			"status":            resp.Status,
+			"created_at":        resp.CreatedAt,
+			"rotated_at":        resp.RotatedAt,
+			"age_seconds":       resp.AgeSeconds,
+			"cached":            resp.Cached,
+			"archived_versions": resp.ArchivedVersions,
+			"stored_versions":   resp.StoredVersions,
+		},
+	})
+}
+
+// 列出和搜索所有聊天室（調用者是否為管理員由 gRPC 服務根據 JWT 用戶判斷）
+func listRooms(c *gin.Context) {
+	req := &chat.ListRoomsRequest{
+		Type:         c.Query("type"),
+		OwnerId:      c.Query("owner_id"),
+		NamePrefix:   c.Query("name_prefix"),
+		NameContains: c.Query("name_contains"),
+		Cursor:       c.Query("cursor"),
+	}
+
+	var err error
+	if req.CreatedAfter, err = queryUnixSeconds(c, "created_after"); err != nil {
+		httputil.ValidationError(c, "created_after", "必須是 Unix 秒")
+		return
+	}
+	if req.CreatedBefore, err = queryUnixSeconds(c, "created_before"); err != nil {
+		httputil.ValidationError(c, "created_before", "必須是 Unix 秒")
+		return
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.ParseInt(limitStr, 10, 32); err == nil {
+			req.Limit = int32(parsedLimit)
+		}
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.ListRoomsResponse, error) {
+		return client.ListRooms(ctx, req)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	switch resp.ErrorCode {
+	case constants.ErrorCodeInvalidArgument:
+		httputil.BadRequest(c, resp.Message)
+		return
+	case constants.ErrorCodePermissionDenied:
+		httputil.Forbidden(c, resp.Message)
+		return
+	}
+
+	rooms := make([]gin.H, len(resp.Rooms))
+	for i, room := range resp.Rooms {
+		rooms[i] = gin.H{
+			"id":           room.Id,
+			"name":         room.Name,
+			"type":         room.Type,
+			"owner_id":     room.OwnerId,
+			"avatar_url":   room.AvatarUrl,
+			"member_count": room.MemberCount,
+			"created_at":   room.CreatedAt,
+			"updated_at":   room.UpdatedAt,
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"success":  resp.Success,
+		"message":  resp.Message,
+		"data":     rooms,
+		"cursor":   resp.Cursor,
+		"has_more": resp.HasMore,
+	})
+}
+
+// 按頁碼瀏覽聊天室訊息（管理工具使用，skip 越大越慢；客戶端請使用 GET /messages 的游標分頁）
+func getMessagesPaged(c *gin.Context) {
+	req := &chat.GetMessagesPagedRequest{RoomId: c.Param("room_id")}
+
+	for field, target := range map[string]*int32{"limit": &req.Limit, "skip": &req.Skip} {
+		value := c.Query(field)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || parsed < 0 {
+			httputil.ValidationError(c, field, "必須是非負整數")
+			return
+		}
+		*target = int32(parsed)
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.GetMessagesPagedResponse, error) {
+		return client.GetMessagesPaged(ctx, req)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	switch resp.ErrorCode {
+	case constants.ErrorCodeInvalidArgument:
+		httputil.BadRequest(c, resp.Message)
+		return
+	case constants.ErrorCodePermissionDenied:
+		httputil.Forbidden(c, resp.Message)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success":     resp.Success,
+		"message":     resp.Message,
+		"data":        resp.Messages,
+		"total_count": resp.TotalCount,
+		"limit":       resp.Limit,
+		"skip":        resp.Skip,
+	})
+}
+
+// queryUnixSeconds 解析可選的 Unix 秒查詢參數（未提供時返回 0）
+func queryUnixSeconds(c *gin.Context, field string) (int64, error) {
+	value := c.Query(field)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
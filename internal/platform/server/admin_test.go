@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIsAdminUser(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Security.Authentication.AdminUserIDs = []string{"admin"}
+
+	if isAdminUser(cfg, "admin") {
+		t.Error("Expected admin to be rejected when JWT is disabled")
+	}
+
+	cfg.Security.Authentication.JWTEnabled = true
+	if !isAdminUser(cfg, "admin") {
+		t.Error("Expected configured admin to be accepted")
+	}
+	if isAdminUser(cfg, "alice") {
+		t.Error("Expected non-admin user to be rejected")
+	}
+	if isAdminUser(cfg, "") {
+		t.Error("Expected unauthenticated request to be rejected")
+	}
+	if isAdminUser(nil, "admin") {
+		t.Error("Expected nil config to be rejected")
+	}
+}
+
+func TestGetSSEStats_RejectsNonAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/admin/sse/stats", nil)
+	c.Set(middleware.UserIDKey, "alice")
+
+	getSSEStats(middleware.NewSSEConnectionLimiter(10, 0, 100))(c)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/grpcclient"
+	"chat-gateway/internal/httputil"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/avatar"
+	"chat-gateway/internal/storage/filestore"
+	"chat-gateway/proto/chat"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerAvatarRoutes 註冊頭像上傳端點（需要在配置中開啟）
+// 本地存儲且 URL 前綴為路徑時，同時由 HTTP 服務提供頭像的靜態訪問
+func registerAvatarRoutes(r *gin.Engine, api *gin.RouterGroup) {
+	store, err := avatar.NewStoreFromConfig()
+	if err != nil {
+		logger.Error(context.Background(), "創建頭像存儲失敗，不註冊頭像上傳端點",
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return
+	}
+	if store == nil {
+		return
+	}
+
+	serveLocalFiles(r, store)
+	api.POST("/rooms/:room_id/avatar", uploadRoomAvatar(store))
+}
+
+// maxAvatarSize 頭像大小上限（與 multipart 內存上限一致）
+func maxAvatarSize() int64 {
+	if cfg := config.Get(); cfg != nil && cfg.Limits.Request.MaxMultipartMemory > 0 {
+		return cfg.Limits.Request.MaxMultipartMemory
+	}
+	return constants.DefaultMaxMultipartMemory
+}
+
+// 上傳聊天室頭像（僅限擁有者或管理員，權限由 UpdateRoom 檢查）
+func uploadRoomAvatar(store filestore.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roomID := c.Param("room_id")
+		if err := middleware.ValidateRoomID(roomID); err != nil {
+			httputil.BadRequest(c, err.Error())
+			return
+		}
+
+		maxSize := maxAvatarSize()
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+		data, _, err := readUploadedFile(c, "avatar", maxSize)
+		if err != nil {
+			if isFileTooLarge(err) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "頭像文件過大"})
+				return
+			}
+			httputil.BadRequest(c, "缺少頭像文件")
+			return
+		}
+
+		userID := actingUserID(c, c.PostForm("user_id"))
+		if userID == "" {
+			c.JSON(400, gin.H{"error": "缺少 user_id 參數"})
+			return
+		}
+
+		contentType, ext, err := avatar.DetectImageType(data)
+		if err != nil {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "只支持 PNG、JPEG、GIF 和 WebP 圖片"})
+			return
+		}
+
+		key, err := avatar.RoomAvatarKey(roomID, ext)
+		if err != nil {
+			httputil.InternalServerError(c, err)
+			return
+		}
+		avatarURL, err := store.Save(c.Request.Context(), key, contentType, data)
+		if err != nil {
+			httputil.InternalServerError(c, err)
+			return
+		}
+
+		// 調用 gRPC 服務更新頭像（權限檢查和系統訊息由 UpdateRoom 處理）
+		conn, err := grpcclient.GetConnection()
+		if err != nil {
+			deleteStoredFile(c, store, key)
+			httputil.InternalServerError(c, err)
+			return
+		}
+
+		client := chat.NewChatRoomServiceClient(conn)
+		ctx, cancel := grpcContext(c)
+		defer cancel()
+		resp, err := client.UpdateRoom(ctx, &chat.UpdateRoomRequest{
+			RoomId:    roomID,
+			UserId:    userID,
+			AvatarUrl: &avatarURL,
+		})
+		if err != nil {
+			deleteStoredFile(c, store, key)
+			grpcCallFailed(c, err)
+			return
+		}
+		if !resp.Success {
+			deleteStoredFile(c, store, key)
+		}
+
+		c.JSON(200, gin.H{
+			"success":    resp.Success,
+			"message":    resp.Message,
+			"avatar_url": resp.GetRoom().GetAvatarUrl(),
+			"room":       resp.Room,
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/httputil"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/middleware"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// statusClientClosedRequest 客戶端在響應前斷開連接（與 nginx 相同使用 499 記錄）
+const statusClientClosedRequest = 499
+
+// grpcCallTimeout HTTP 處理器單次調用 gRPC 服務的超時時間
+func grpcCallTimeout() time.Duration {
+	seconds := constants.DefaultGRPCCallTimeoutSeconds
+	if cfg := config.Get(); cfg != nil && cfg.GRPC.Client.CallTimeoutSeconds > 0 {
+		seconds = cfg.GRPC.Client.CallTimeoutSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// grpcContext 創建單次調用內部 gRPC 服務的 context
+// 從 HTTP 請求的 context 派生並設置超時：客戶端斷開或後端卡住時調用會被取消，不會一直佔用處理器
+func grpcContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(grpcStreamContext(c), grpcCallTimeout())
+}
+
+// grpcStreamContext 創建 gRPC 訊息流的 context（長連接不設置超時，客戶端斷開時取消）
+// 轉發 Authorization 和 Request ID，讓 gRPC 端的 JWT 驗證和日誌與 HTTP 請求保持一致
+func grpcStreamContext(c *gin.Context) context.Context {
+	md := metadata.MD{}
+	if auth := c.GetHeader("Authorization"); auth != "" {
+		md.Set("authorization", auth)
+	}
+	if requestID := middleware.GetRequestID(c); requestID != "" {
+		md.Set(middleware.GRPCTraceIDKey, requestID)
+	}
+	return metadata.NewOutgoingContext(c.Request.Context(), md)
+}
+
+// actingUserID 操作者的用戶 ID：啟用 JWT 時為認證用戶，只有未啟用 JWT 時才使用請求中的 user_id
+func actingUserID(c *gin.Context, requested string) string {
+	if authUserID := c.GetString(middleware.UserIDKey); authUserID != "" {
+		return authUserID
+	}
+	return requested
+}
+
+// grpcCallFailed 返回 gRPC 調用失敗的響應：超時返回 504，客戶端已斷開時只記錄 499，其他錯誤返回 500
+func grpcCallFailed(c *gin.Context, err error) {
+	switch status.Code(err) {
+	case codes.DeadlineExceeded:
+		httputil.GatewayTimeout(c)
+	case codes.Canceled:
+		c.AbortWithStatus(statusClientClosedRequest)
+	default:
+		httputil.InternalServerError(c, err)
+	}
+}
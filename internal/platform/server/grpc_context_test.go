@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/middleware"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCContext_DeadlineAndMetadata(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	reqCtx, cancelReq := context.WithCancel(context.Background())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/rooms", nil).WithContext(reqCtx)
+	c.Request.Header.Set("Authorization", "Bearer token")
+	c.Set(middleware.RequestIDKey, "req-123")
+
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("grpcContext() has no deadline")
+	}
+	want := time.Duration(constants.DefaultGRPCCallTimeoutSeconds) * time.Second
+	if remaining := time.Until(deadline); remaining <= 0 || remaining > want {
+		t.Errorf("remaining = %v, want within %v", remaining, want)
+	}
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if got := md.Get("authorization"); len(got) != 1 || got[0] != "Bearer token" {
+		t.Errorf("authorization metadata = %v", got)
+	}
+	if got := md.Get(middleware.GRPCTraceIDKey); len(got) != 1 || got[0] != "req-123" {
+		t.Errorf("trace ID metadata = %v, want the HTTP request ID", got)
+	}
+
+	// 客戶端斷開時調用隨之取消
+	cancelReq()
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Error("grpcContext() was not canceled with the request context")
+	}
+}
+
+func TestGRPCStreamContext_NoDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/rooms/r1/stream", nil)
+
+	if _, ok := grpcStreamContext(c).Deadline(); ok {
+		t.Error("grpcStreamContext() should not have a deadline")
+	}
+}
+
+func TestGRPCCallFailed(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "timeout"), http.StatusGatewayTimeout},
+		{"client canceled", status.Error(codes.Canceled, "canceled"), statusClientClosedRequest},
+		{"unavailable", status.Error(codes.Unavailable, "down"), http.StatusInternalServerError},
+		{"plain error", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/rooms", nil)
+
+			grpcCallFailed(c, tt.err)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestActingUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if got := actingUserID(c, "alice"); got != "alice" {
+		t.Errorf("actingUserID() without JWT = %q, want %q", got, "alice")
+	}
+
+	c.Set(middleware.UserIDKey, "bob")
+	if got := actingUserID(c, "alice"); got != "bob" {
+		t.Errorf("actingUserID() with JWT = %q, want %q", got, "bob")
+	}
+}
@@ -2,61 +2,41 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
+	"chat-gateway/internal/constants"
 	"chat-gateway/internal/grpcclient"
 	"chat-gateway/internal/httputil"
+	"chat-gateway/internal/message"
 	"chat-gateway/internal/platform/config"
 	"chat-gateway/internal/platform/health"
 	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/security/audit"
 	"chat-gateway/proto/chat"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// securityHeadersMiddleware 添加安全標頭
-func securityHeadersMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// 防止點擊劫持
-		c.Header("X-Frame-Options", "DENY")
-
-		// 防止 MIME 類型嗅探
-		c.Header("X-Content-Type-Options", "nosniff")
-
-		// 啟用 XSS 保護
-		c.Header("X-XSS-Protection", "1; mode=block")
-
-		// 內容安全策略
-		csp := "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; " +
-			"img-src 'self' data: https:; font-src 'self'; connect-src 'self'; frame-ancestors 'none';"
-		c.Header("Content-Security-Policy", csp)
-
-		// 強制 HTTPS（生產環境）
-		// c.Header("Strict-Transport-Security", "max-age=31536000; includeSubDomains; preload")
-
-		// 推薦政策
-		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
-
-		// 權限政策
-		c.Header("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
-
-		c.Next()
-	}
-}
-
 // Router 設定路由 - 簡化版本，只保留健康檢查
 func Router() *gin.Engine {
-	r := gin.Default()
+	r := gin.New()
+	// 瀏覽器 EventSource 無法設置 Authorization header，訊息流改用 access_token 查詢參數；
+	// 在訪問日誌之前移到 header，token 不會寫入日誌
+	r.Use(middleware.QueryTokenMiddleware("/api/v1"+streamMessagesPath), gin.Logger(), gin.Recovery())
 
 	setupMiddleware(r)
 
 	rateLimiter := setupRateLimiter()
-	r.Use(rateLimiter.Middleware())
 
 	sseLimiter := setupSSELimiter()
 
-	registerRoutes(r, sseLimiter)
+	registerRoutes(r, rateLimiter, sseLimiter)
 
 	return r
 }
@@ -98,7 +78,8 @@ func corsMiddleware() gin.HandlerFunc {
 		}
 
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, Idempotency-Key")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Warning, Retry-After")
 		c.Header("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == "OPTIONS" {
@@ -121,21 +102,48 @@ func setupRateLimiter() *middleware.PerEndpointRateLimiter {
 
 	rateLimiter := middleware.NewPerEndpointRateLimiter(defaultLimit, time.Minute)
 
+	// 被拒絕的請求記入審計日誌（ISO 27001 安全事件）
+	if cfg != nil && cfg.Security.Audit.Enabled {
+		rateLimiter.SetOnLimitExceeded(audit.NewAuditService(true).LogRateLimitExceeded)
+	}
+
 	if cfg != nil && cfg.Limits.RateLimiting.Enabled {
-		if cfg.Limits.RateLimiting.MessagesPerMin > 0 {
-			rateLimiter.SetLimit("/api/v1/messages", cfg.Limits.RateLimiting.MessagesPerMin, time.Minute)
+		rateLimiting := cfg.Limits.RateLimiting
+		rateLimiter.SetDefaultStrategy(rateLimitStrategy(rateLimiting, ""))
+		rateLimiter.SetWarnThreshold(rateLimiting.WarnThresholdPercent)
+		for path, percent := range rateLimiting.EndpointWarnThresholds {
+			rateLimiter.SetEndpointWarnThreshold(path, percent)
+		}
+
+		if rateLimiting.MessagesPerMin > 0 {
+			path := "/api/v1/messages"
+			rateLimiter.SetLimitWithStrategy(path, rateLimiting.MessagesPerMin, time.Minute, rateLimitStrategy(rateLimiting, path))
 		}
-		if cfg.Limits.RateLimiting.RoomsPerMin > 0 {
-			rateLimiter.SetLimit("/api/v1/rooms", cfg.Limits.RateLimiting.RoomsPerMin, time.Minute)
+		if rateLimiting.RoomsPerMin > 0 {
+			path := "/api/v1/rooms"
+			rateLimiter.SetLimitWithStrategy(path, rateLimiting.RoomsPerMin, time.Minute, rateLimitStrategy(rateLimiting, path))
 		}
-		if cfg.Limits.RateLimiting.SSEPerMin > 0 {
-			rateLimiter.SetLimit("/api/v1/messages/stream", cfg.Limits.RateLimiting.SSEPerMin, time.Minute)
+		if rateLimiting.SSEPerMin > 0 {
+			path := "/api/v1/messages/stream"
+			rateLimiter.SetLimitWithStrategy(path, rateLimiting.SSEPerMin, time.Minute, rateLimitStrategy(rateLimiting, path))
 		}
 	}
 
 	return rateLimiter
 }
 
+// rateLimitStrategy 返回端點的計數維度：端點配置優先，其次是全局配置，默認按 IP
+// path 為空時返回全局配置
+func rateLimitStrategy(cfg config.RateLimitingConfig, path string) middleware.RateLimitKeyStrategy {
+	if strategy := cfg.EndpointKeyStrategies[path]; path != "" && strategy != "" {
+		return middleware.RateLimitKeyStrategy(strategy)
+	}
+	if cfg.KeyStrategy != "" {
+		return middleware.RateLimitKeyStrategy(cfg.KeyStrategy)
+	}
+	return middleware.RateLimitKeyIP
+}
+
 // setupSSELimiter 設置 SSE 連接限制器
 func setupSSELimiter() *middleware.SSEConnectionLimiter {
 	cfg := config.Get()
@@ -162,19 +170,54 @@ func setupSSELimiter() *middleware.SSEConnectionLimiter {
 }
 
 // registerRoutes 註冊所有路由
-func registerRoutes(r *gin.Engine, sseLimiter *middleware.SSEConnectionLimiter) {
+func registerRoutes(r *gin.Engine, rateLimiter *middleware.PerEndpointRateLimiter, sseLimiter *middleware.SSEConnectionLimiter) {
 	healthHandler := health.NewHealthHandler()
-	r.GET("/health", healthHandler.HealthCheck)
+	r.GET("/health", rateLimiter.Middleware(), healthHandler.HealthCheck)
+	r.GET("/health/live", rateLimiter.Middleware(), healthHandler.Liveness)
+	r.GET("/health/ready", rateLimiter.Middleware(), healthHandler.Readiness)
+
+	// 業務 API 需要 JWT 認證（未啟用時直接放行）
+	// 速率限制放在認證之後，按用戶計數的端點才能拿到已驗證的用戶 ID
+	api := r.Group("/api/v1", middleware.NewJWTMiddlewareFromConfig().GinMiddleware(), rateLimiter.Middleware())
+	api.POST("/rooms", createRoom)
+	api.GET("/rooms", listUserRooms)
+	api.POST("/rooms/:room_id/members", addRoomMember)
+	api.DELETE("/rooms/:room_id/members/:user_id", removeRoomMember)
+	api.POST("/rooms/:room_id/owner", transferRoomOwnership)
+	api.POST("/rooms/:room_id/mute", muteRoom)
+	api.DELETE("/rooms/:room_id/mute", unmuteRoom)
+	api.POST("/rooms/:room_id/archive", archiveRoom)
+	api.DELETE("/rooms/:room_id/archive", unarchiveRoom)
+	api.POST("/rooms/:room_id/pin", pinRoom)
+	api.DELETE("/rooms/:room_id/pin", unpinRoom)
+	api.GET("/rooms/:room_id/online", getOnlineMembers)
+	api.GET("/rooms/:room_id/members", listRoomMembers)
+	api.GET("/rooms/:room_id/messages/count", getRoomMessageCount)
+	api.POST("/messages", sendMessage)
+	api.POST("/messages/forward", forwardMessage)
+	api.GET("/messages", getMessages)
+	api.GET("/messages/:message_id", getMessage)
+	api.GET("/messages/:message_id/thread", getThread)
+	api.POST("/messages/read", markAsRead)
+	api.POST("/messages/read-all", markAllAsRead)
+	api.POST("/messages/delivered", markAsDelivered)
+
+	// 手動密鑰輪換需要在配置中明確開啟
+	if cfg := config.Get(); cfg != nil && cfg.Security.Encryption.ManualRotationEnabled {
+		api.POST("/rooms/:room_id/rotate-key", rotateRoomKey)
+	}
+
+	// 用戶數據刪除（GDPR 被遺忘權）需要在配置中明確開啟
+	if cfg := config.Get(); cfg != nil && cfg.Security.DataProtection.RightToErasure {
+		api.POST("/users/:user_id/erase", deleteUserData)
+	}
 
-	r.POST("/api/v1/rooms", createRoom)
-	r.GET("/api/v1/rooms", listUserRooms)
-	r.POST("/api/v1/rooms/:room_id/members", addRoomMember)
-	r.DELETE("/api/v1/rooms/:room_id/members/:user_id", removeRoomMember)
-	r.POST("/api/v1/messages", sendMessage)
-	r.GET("/api/v1/messages", getMessages)
-	r.POST("/api/v1/messages/read", markAsRead)
+	registerAdminRoutes(api, sseLimiter)
+	registerAvatarRoutes(r, api)
+	registerUploadRoutes(r, api)
+	registerScheduledRoutes(api)
 
-	r.GET("/api/v1/messages/stream", sseLimiter.Middleware(), streamMessages)
+	api.GET(streamMessagesPath, sseLimiter.Middleware(), streamMessages)
 }
 
 // 創建聊天室
@@ -187,12 +230,22 @@ func createRoom(c *gin.Context) {
 			UserID string `json:"user_id"`
 			Role   string `json:"role"`
 		} `json:"members"`
+		Settings *struct {
+			AllowInvite         *bool  `json:"allow_invite"`
+			AllowEditMessages   *bool  `json:"allow_edit_messages"`
+			AllowDeleteMessages *bool  `json:"allow_delete_messages"`
+			AllowPinMessages    *bool  `json:"allow_pin_messages"`
+			MaxMembers          int32  `json:"max_members"`
+			WelcomeMessage      string `json:"welcome_message"`
+			Locale              string `json:"locale"`
+		} `json:"settings,omitempty"` // 未提供的開關使用默認值
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(400, gin.H{"error": "無效的請求格式"})
 		return
 	}
+	req.OwnerID = actingUserID(c, req.OwnerID)
 
 	// 驗證聊天室名稱
 	if err := middleware.ValidateRoomName(req.Name); err != nil {
@@ -206,13 +259,28 @@ func createRoom(c *gin.Context) {
 		return
 	}
 
-	// 驗證成員數量
-	cfg := config.Get()
-	maxMembers := 1000 // 默認
-	if cfg != nil && cfg.Limits.Room.MaxMembers > 0 {
-		maxMembers = cfg.Limits.Room.MaxMembers
+	// 驗證設置和成員數量（私聊最多 2 人，群聊不超過配置的上限）
+	settings := middleware.DefaultRoomSettings()
+	if req.Settings != nil {
+		setIfPresent(&settings.AllowInvite, req.Settings.AllowInvite)
+		setIfPresent(&settings.AllowEditMessages, req.Settings.AllowEditMessages)
+		setIfPresent(&settings.AllowDeleteMessages, req.Settings.AllowDeleteMessages)
+		setIfPresent(&settings.AllowPinMessages, req.Settings.AllowPinMessages)
+		settings.MaxMembers = req.Settings.MaxMembers
+		settings.WelcomeMessage = middleware.SanitizeInput(req.Settings.WelcomeMessage)
+		settings.Locale = req.Settings.Locale
 	}
-	if len(req.Members) > maxMembers {
+	settings, err := middleware.ValidateRoomSettings(req.Type, settings)
+	if err != nil {
+		var validationErr *middleware.ValidationError
+		if errors.As(err, &validationErr) {
+			httputil.ValidationError(c, validationErr.Field, validationErr.Message)
+			return
+		}
+		httputil.BadRequest(c, err.Error())
+		return
+	}
+	if len(req.Members) > int(settings.MaxMembers) {
 		c.JSON(400, gin.H{"error": "成員數量超過限制"})
 		return
 	}
@@ -235,12 +303,7 @@ func createRoom(c *gin.Context) {
 		Type:      req.Type,
 		OwnerId:   req.OwnerID,
 		MemberIds: memberIDs,
-		Settings: &chat.RoomSettings{
-			AllowInvite:         true,
-			AllowEditMessages:   true,
-			AllowDeleteMessages: true,
-			MaxMembers:          int32(maxMembers), // #nosec G115 -- maxMembers is validated above
-		},
+		Settings:  settings,
 	}
 
 	// 調用 gRPC 服務
@@ -251,9 +314,15 @@ func createRoom(c *gin.Context) {
 	}
 
 	client := chat.NewChatRoomServiceClient(conn)
-	resp, err := client.CreateRoom(context.Background(), grpcReq)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.CreateRoom(ctx, grpcReq)
 	if err != nil {
-		httputil.InternalServerError(c, err)
+		if status.Code(err) == codes.ResourceExhausted {
+			httputil.RateLimitExceeded(c)
+			return
+		}
+		grpcCallFailed(c, err)
 		return
 	}
 
@@ -270,29 +339,59 @@ func createRoom(c *gin.Context) {
 	})
 }
 
+// setIfPresent 請求中提供了開關時覆蓋默認值
+func setIfPresent(dst *bool, value *bool) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
 // 列出用戶聊天室
 func listUserRooms(c *gin.Context) {
-	userID := c.Query("user_id")
+	userID := actingUserID(c, c.Query("user_id"))
 	if userID == "" {
 		c.JSON(400, gin.H{"error": "缺少 user_id 參數"})
 		return
 	}
 
-	// 獲取分頁參數
+	// 獲取分頁參數，limit 超過配置的最大分頁大小時截斷
 	cfg := config.Get()
 	limit := 10 // 默認
-	if cfg != nil && cfg.Limits.Pagination.DefaultPageSize > 0 {
-		limit = cfg.Limits.Pagination.DefaultPageSize
+	maxLimit := constants.DefaultMaxPageSize
+	if cfg != nil {
+		if cfg.Limits.Pagination.DefaultPageSize > 0 {
+			limit = cfg.Limits.Pagination.DefaultPageSize
+		}
+		if cfg.Limits.Pagination.MaxPageSize > 0 {
+			maxLimit = cfg.Limits.Pagination.MaxPageSize
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit <= 0 {
+			httputil.ValidationError(c, "limit", "必須是正整數")
+			return
+		}
+		limit = min(parsedLimit, maxLimit)
 	}
 	cursor := c.Query("cursor")
 
-	// 可選：解析 limit 參數（目前使用默認值）
-	_ = c.Query("limit") // TODO: 未來可以實現自定義 limit
+	// 排序方式：activity（默認）/ unread / name
+	sort := c.DefaultQuery("sort", constants.RoomSortActivity)
+	switch sort {
+	case constants.RoomSortActivity, constants.RoomSortUnread, constants.RoomSortName:
+	default:
+		httputil.ValidationError(c, "sort", "sort 必須是 activity、unread 或 name")
+		return
+	}
 
 	grpcReq := &chat.ListUserRoomsRequest{
-		UserId: userID,
-		Limit:  int32(limit), // #nosec G115 -- limit is validated above
-		Cursor: cursor,
+		UserId:          userID,
+		Limit:           int32(limit), // #nosec G115 -- limit is validated above
+		Cursor:          cursor,
+		IncludeArchived: c.Query("include_archived") == "true",
+		Sort:            sort,
+		ExcludeMuted:    c.Query("exclude_muted") == "true", // 可選：已靜音的聊天室未讀數量返回 0
 	}
 
 	// 調用 gRPC 服務
@@ -303,27 +402,17 @@ func listUserRooms(c *gin.Context) {
 	}
 
 	client := chat.NewChatRoomServiceClient(conn)
-	resp, err := client.ListUserRooms(context.Background(), grpcReq)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.ListUserRoomsResponse, error) {
+		return client.ListUserRooms(ctx, grpcReq)
+	})
 	if err != nil {
-		httputil.InternalServerError(c, err)
+		grpcCallFailed(c, err)
 		return
 	}
-	messageClient := chat.NewChatRoomServiceClient(conn)
 
-	// 轉換響應，包含最後訊息和未讀數量
+	// 轉換響應，包含最後訊息、未讀數量和靜音狀態（未讀數量由 ListUserRooms 一次計算）
 	rooms := make([]map[string]interface{}, len(resp.Rooms))
 	for i, room := range resp.Rooms {
-		// 獲取未讀數量
-		unreadResp, _ := messageClient.GetUnreadCount(context.Background(), &chat.GetUnreadCountRequest{
-			UserId: userID,
-			RoomId: room.Id,
-		})
-
-		unreadCount := int32(0)
-		if unreadResp != nil && unreadResp.Success {
-			unreadCount = unreadResp.Count
-		}
-
 		rooms[i] = map[string]interface{}{
 			"id":                room.Id,
 			"name":              room.Name,
@@ -334,7 +423,11 @@ func listUserRooms(c *gin.Context) {
 			"members":           room.Members,
 			"last_message":      room.LastMessage,
 			"last_message_time": room.LastMessageTime,
-			"unread_count":      unreadCount,
+			"unread_count":      room.UnreadCount,
+			"muted":             room.Muted,
+			"muted_until":       room.MutedUntil,
+			"archived":          room.Archived,
+			"pinned":            room.Pinned,
 		}
 	}
 
@@ -350,10 +443,15 @@ func listUserRooms(c *gin.Context) {
 // 發送消息
 func sendMessage(c *gin.Context) {
 	var req struct {
-		RoomID   string `json:"room_id"`
-		SenderID string `json:"sender_id"`
-		Content  string `json:"content"`
-		Type     string `json:"type"`
+		RoomID         string                `json:"room_id"`
+		SenderID       string                `json:"sender_id"`
+		Content        string                `json:"content"`
+		Type           string                `json:"type"`
+		Metadata       *chat.MessageMetadata `json:"metadata,omitempty"` // 圖片、文件、位置等訊息的元數據
+		Mentions       []string              `json:"mentions,omitempty"`
+		TTL            int32                 `json:"ttl_seconds,omitempty"`     // 限時訊息：發送後多少秒過期
+		IdempotencyKey string                `json:"idempotency_key,omitempty"` // 也可以通過 Idempotency-Key header 提供
+		ReplyTo        string                `json:"reply_to_message_id,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -367,13 +465,41 @@ func sendMessage(c *gin.Context) {
 		return
 	}
 
+	// 發送者為已認證的用戶；只有未啟用 JWT 時才使用請求中的 sender_id
+	if authUserID := c.GetString(middleware.UserIDKey); authUserID != "" {
+		req.SenderID = authUserID
+	}
+
 	if err := middleware.ValidateUserID(req.SenderID); err != nil {
 		httputil.BadRequest(c, err.Error())
 		return
 	}
 
-	if err := middleware.ValidateMessageContent(req.Content); err != nil {
-		httputil.BadRequest(c, err.Error())
+	msgType, err := message.NormalizeMessageType(req.Type)
+	if err != nil {
+		httputil.ValidationError(c, "type", err.Error())
+		return
+	}
+
+	// 圖片、文件等訊息的內容（說明文字）可以為空，元數據由 gRPC 服務驗證
+	if msgType == message.MessageTypeText || req.Content != "" {
+		if err := middleware.ValidateMessageContent(req.Content); err != nil {
+			httputil.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	// 冪等鍵：客戶端重試同一條訊息時使用同一個鍵，服務端只會創建一次
+	// 未提供時生成一個，保證下面對 gRPC 的自動重試不會重複發送
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if idempotencyKey == "" {
+		idempotencyKey = req.IdempotencyKey
+	}
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.New().String()
+	}
+	if len(idempotencyKey) > constants.MaxIdempotencyKeyLength {
+		httputil.ValidationError(c, "idempotency_key", fmt.Sprintf("不能超過 %d 個字符", constants.MaxIdempotencyKeyLength))
 		return
 	}
 
@@ -381,10 +507,15 @@ func sendMessage(c *gin.Context) {
 	sanitizedContent := middleware.SanitizeInput(req.Content)
 
 	grpcReq := &chat.SendMessageRequest{
-		RoomId:   req.RoomID,
-		SenderId: req.SenderID,
-		Content:  sanitizedContent,
-		Type:     req.Type,
+		RoomId:           req.RoomID,
+		SenderId:         req.SenderID,
+		Content:          sanitizedContent,
+		Type:             msgType,
+		Metadata:         req.Metadata,
+		Mentions:         req.Mentions,
+		TtlSeconds:       req.TTL,
+		IdempotencyKey:   idempotencyKey,
+		ReplyToMessageId: req.ReplyTo,
 	}
 
 	// 調用 gRPC 服務
@@ -395,9 +526,15 @@ func sendMessage(c *gin.Context) {
 	}
 
 	client := chat.NewChatRoomServiceClient(conn)
-	resp, err := client.SendMessage(context.Background(), grpcReq)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.SendMessageResponse, error) {
+		return client.SendMessage(ctx, grpcReq)
+	})
 	if err != nil {
-		httputil.InternalServerError(c, err)
+		if status.Code(err) == codes.InvalidArgument {
+			httputil.BadRequest(c, status.Convert(err).Message())
+			return
+		}
+		grpcCallFailed(c, err)
 		return
 	}
 
@@ -411,23 +548,85 @@ func sendMessage(c *gin.Context) {
 	}
 
 	c.JSON(200, gin.H{
-		"success": resp.Success,
-		"message": resp.Message,
+		"success":  resp.Success,
+		"message":  resp.Message,
+		"replayed": resp.Replayed,
 		"data": gin.H{
-			"id":         resp.ChatMessage.Id,
-			"room_id":    resp.ChatMessage.RoomId,
-			"sender_id":  resp.ChatMessage.SenderId,
-			"content":    resp.ChatMessage.Content,
-			"type":       resp.ChatMessage.Type,
-			"created_at": resp.ChatMessage.CreatedAt,
+			"id":                  resp.ChatMessage.Id,
+			"room_id":             resp.ChatMessage.RoomId,
+			"sender_id":           resp.ChatMessage.SenderId,
+			"content":             resp.ChatMessage.Content,
+			"type":                resp.ChatMessage.Type,
+			"metadata":            resp.ChatMessage.Metadata,
+			"created_at":          resp.ChatMessage.CreatedAt,
+			"mentions":            resp.ChatMessage.Mentions,
+			"expires_at":          resp.ChatMessage.ExpiresAt,
+			"reply_to_message_id": resp.ChatMessage.ReplyToMessageId,
 		},
 	})
 }
 
+// 轉發消息到另一個聊天室
+func forwardMessage(c *gin.Context) {
+	var req struct {
+		UserID       string `json:"user_id"`
+		SourceRoomID string `json:"source_room_id"`
+		MessageID    string `json:"message_id"`
+		TargetRoomID string `json:"target_room_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "無效的請求格式"})
+		return
+	}
+
+	// 轉發者為已認證的用戶；只有未啟用 JWT 時才使用請求中的 user_id
+	req.UserID = actingUserID(c, req.UserID)
+	if err := middleware.ValidateUserID(req.UserID); err != nil {
+		httputil.BadRequest(c, err.Error())
+		return
+	}
+	for _, roomID := range []string{req.SourceRoomID, req.TargetRoomID} {
+		if err := middleware.ValidateRoomID(roomID); err != nil {
+			httputil.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	grpcReq := &chat.ForwardMessageRequest{
+		UserId:       req.UserID,
+		SourceRoomId: req.SourceRoomID,
+		MessageId:    req.MessageID,
+		TargetRoomId: req.TargetRoomID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.ForwardMessage(ctx, grpcReq)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"data":    resp.ChatMessage,
+	})
+}
+
 // 獲取消息
 func getMessages(c *gin.Context) {
 	roomID := c.Query("room_id")
-	userID := c.Query("user_id")
+	userID := actingUserID(c, c.Query("user_id"))
 	limitStr := c.Query("limit")
 	cursor := c.Query("cursor")
 
@@ -466,15 +665,120 @@ func getMessages(c *gin.Context) {
 	}
 
 	client := chat.NewChatRoomServiceClient(conn)
-	resp, err := client.GetMessages(context.Background(), grpcReq)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.GetMessagesResponse, error) {
+		return client.GetMessages(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success":     resp.Success,
+		"message":     resp.Message,
+		"data":        resp.Messages,
+		"next_cursor": resp.NextCursor,
+		"has_more":    resp.HasMore,
+	})
+}
+
+// 獲取單條訊息
+func getMessage(c *gin.Context) {
+	messageID := c.Param("message_id")
+	userID := actingUserID(c, c.Query("user_id"))
+
+	if err := middleware.ValidateUserID(userID); err != nil {
+		httputil.ValidationError(c, "user_id", err.Error())
+		return
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.GetMessageResponse, error) {
+		return client.GetMessage(ctx, &chat.GetMessageRequest{
+			MessageId: messageID,
+			UserId:    userID,
+		})
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	switch resp.ErrorCode {
+	case constants.ErrorCodeInvalidArgument:
+		httputil.BadRequest(c, resp.Message)
+		return
+	case constants.ErrorCodeNotFound:
+		httputil.NotFoundError(c, resp.Message)
+		return
+	case constants.ErrorCodePermissionDenied:
+		httputil.Forbidden(c, resp.Message)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"data":    resp.ChatMessage,
+	})
+}
+
+// 獲取討論串（根訊息只在第一頁返回）
+func getThread(c *gin.Context) {
+	req := &chat.GetThreadRequest{
+		RoomId:        c.Query("room_id"),
+		UserId:        actingUserID(c, c.Query("user_id")),
+		RootMessageId: c.Param("message_id"),
+		Cursor:        c.Query("cursor"),
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil {
+			httputil.ValidationError(c, "limit", "必須是整數")
+			return
+		}
+		req.Limit = int32(limit)
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
 	if err != nil {
 		httputil.InternalServerError(c, err)
 		return
 	}
 
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.GetThreadResponse, error) {
+		return client.GetThread(ctx, req)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	switch resp.ErrorCode {
+	case constants.ErrorCodeInvalidArgument:
+		httputil.BadRequest(c, resp.Message)
+		return
+	case constants.ErrorCodeNotFound:
+		httputil.NotFoundError(c, resp.Message)
+		return
+	case constants.ErrorCodePermissionDenied:
+		httputil.Forbidden(c, resp.Message)
+		return
+	}
+
 	c.JSON(200, gin.H{
 		"success":     resp.Success,
 		"message":     resp.Message,
+		"root":        resp.Root,
 		"data":        resp.Messages,
 		"next_cursor": resp.NextCursor,
 		"has_more":    resp.HasMore,
@@ -493,6 +797,7 @@ func markAsRead(c *gin.Context) {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	req.UserID = actingUserID(c, req.UserID)
 
 	grpcReq := &chat.MarkAsReadRequest{
 		RoomId:    req.RoomID,
@@ -508,9 +813,11 @@ func markAsRead(c *gin.Context) {
 	}
 
 	client := chat.NewChatRoomServiceClient(conn)
-	resp, err := client.MarkAsRead(context.Background(), grpcReq)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.MarkAsReadResponse, error) {
+		return client.MarkAsRead(ctx, grpcReq)
+	})
 	if err != nil {
-		httputil.InternalServerError(c, err)
+		grpcCallFailed(c, err)
 		return
 	}
 
@@ -520,10 +827,8 @@ func markAsRead(c *gin.Context) {
 	})
 }
 
-// 添加群組成員
-func addRoomMember(c *gin.Context) {
-	roomID := c.Param("room_id")
-
+// 標記所有聊天室的消息為已讀
+func markAllAsRead(c *gin.Context) {
 	var req struct {
 		UserID string `json:"user_id"`
 	}
@@ -532,9 +837,13 @@ func addRoomMember(c *gin.Context) {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	req.UserID = actingUserID(c, req.UserID)
+	if req.UserID == "" {
+		c.JSON(400, gin.H{"error": "缺少 user_id 參數"})
+		return
+	}
 
-	grpcReq := &chat.JoinRoomRequest{
-		RoomId: roomID,
+	grpcReq := &chat.MarkAllAsReadRequest{
 		UserId: req.UserID,
 	}
 
@@ -546,26 +855,76 @@ func addRoomMember(c *gin.Context) {
 	}
 
 	client := chat.NewChatRoomServiceClient(conn)
-	resp, err := client.JoinRoom(context.Background(), grpcReq)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.MarkAllAsReadResponse, error) {
+		return client.MarkAllAsRead(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success":           resp.Success,
+		"message":           resp.Message,
+		"rooms_affected":    resp.RoomsAffected,
+		"messages_affected": resp.MessagesAffected,
+	})
+}
+
+// 標記消息已送達
+func markAsDelivered(c *gin.Context) {
+	var req struct {
+		RoomID    string `json:"room_id"`
+		UserID    string `json:"user_id"`
+		MessageID string `json:"message_id,omitempty"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.UserID = actingUserID(c, req.UserID)
+
+	grpcReq := &chat.MarkAsDeliveredRequest{
+		RoomId:    req.RoomID,
+		UserId:    req.UserID,
+		MessageId: req.MessageID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
 	if err != nil {
 		httputil.InternalServerError(c, err)
 		return
 	}
 
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.MarkAsDeliveredResponse, error) {
+		return client.MarkAsDelivered(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
 	c.JSON(200, gin.H{
 		"success": resp.Success,
 		"message": resp.Message,
 	})
 }
 
-// 移除群組成員
-func removeRoomMember(c *gin.Context) {
+// 手動輪換聊天室加密密鑰
+func rotateRoomKey(c *gin.Context) {
 	roomID := c.Param("room_id")
-	userID := c.Param("user_id")
+	if err := middleware.ValidateRoomID(roomID); err != nil {
+		httputil.ValidationError(c, "room_id", err.Error())
+		return
+	}
 
-	grpcReq := &chat.LeaveRoomRequest{
+	// 操作者由 gRPC 服務從轉發的 JWT 中讀取（必須在 admin_user_ids 中）
+	grpcReq := &chat.RotateRoomKeyRequest{
 		RoomId: roomID,
-		UserId: userID,
+		UserId: c.GetString(middleware.UserIDKey),
 	}
 
 	// 調用 gRPC 服務
@@ -576,12 +935,614 @@ func removeRoomMember(c *gin.Context) {
 	}
 
 	client := chat.NewChatRoomServiceClient(conn)
-	resp, err := client.LeaveRoom(context.Background(), grpcReq)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.RotateRoomKey(ctx, grpcReq)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success":     resp.Success,
+		"message":     resp.Message,
+		"key_version": resp.KeyVersion,
+		"rotated_at":  resp.RotatedAt,
+		"created":     resp.Created,
+	})
+}
+
+// 添加群組成員
+func addRoomMember(c *gin.Context) {
+	roomID := c.Param("room_id")
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	req.UserID = actingUserID(c, req.UserID)
+
+	grpcReq := &chat.JoinRoomRequest{
+		RoomId: roomID,
+		UserId: req.UserID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
 	if err != nil {
 		httputil.InternalServerError(c, err)
 		return
 	}
 
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.JoinRoom(ctx, grpcReq)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
+
+// 靜音聊天室（只影響請求的用戶）
+func muteRoom(c *gin.Context) {
+	roomID := c.Param("room_id")
+
+	var req struct {
+		UserID          string `json:"user_id"`
+		DurationSeconds int64  `json:"duration_seconds"` // 0 表示直到手動取消
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "無效的請求格式"})
+		return
+	}
+	req.UserID = actingUserID(c, req.UserID)
+
+	grpcReq := &chat.MuteRoomRequest{
+		RoomId:          roomID,
+		UserId:          req.UserID,
+		DurationSeconds: req.DurationSeconds,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.MuteRoomResponse, error) {
+		return client.MuteRoom(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success":     resp.Success,
+		"message":     resp.Message,
+		"muted_until": resp.MutedUntil,
+	})
+}
+
+// 取消靜音聊天室
+func unmuteRoom(c *gin.Context) {
+	roomID := c.Param("room_id")
+	userID := actingUserID(c, c.Query("user_id"))
+	if userID == "" {
+		c.JSON(400, gin.H{"error": "缺少 user_id 參數"})
+		return
+	}
+
+	grpcReq := &chat.UnmuteRoomRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.UnmuteRoomResponse, error) {
+		return client.UnmuteRoom(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
+
+// 獲取最近在線的聊天室成員
+func getOnlineMembers(c *gin.Context) {
+	roomID := c.Param("room_id")
+	userID := actingUserID(c, c.Query("user_id"))
+	if userID == "" {
+		c.JSON(400, gin.H{"error": "缺少 user_id 參數"})
+		return
+	}
+
+	grpcReq := &chat.GetOnlineMembersRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.GetOnlineMembersResponse, error) {
+		return client.GetOnlineMembers(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success":               resp.Success,
+		"message":               resp.Message,
+		"members":               resp.Members,
+		"online_window_seconds": resp.OnlineWindowSeconds,
+	})
+}
+
+// 分頁列出聊天室成員
+func listRoomMembers(c *gin.Context) {
+	roomID := c.Param("room_id")
+	userID := actingUserID(c, c.Query("user_id"))
+	if userID == "" {
+		c.JSON(400, gin.H{"error": "缺少 user_id 參數"})
+		return
+	}
+
+	var limit int32
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.ParseInt(limitStr, 10, 32); err == nil {
+			limit = int32(parsedLimit)
+		}
+	}
+
+	grpcReq := &chat.ListRoomMembersRequest{
+		RoomId: roomID,
+		UserId: userID,
+		Limit:  limit,
+		Cursor: c.Query("cursor"),
+		Role:   c.Query("role"),
+		Search: c.Query("search"),
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.ListRoomMembersResponse, error) {
+		return client.ListRoomMembers(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success":  resp.Success,
+		"message":  resp.Message,
+		"members":  resp.Members,
+		"cursor":   resp.Cursor,
+		"has_more": resp.HasMore,
+	})
+}
+
+// 獲取聊天室訊息總數（user_id 必須是成員，JWT 管理員可以省略）
+func getRoomMessageCount(c *gin.Context) {
+	req := &chat.GetRoomMessageCountRequest{
+		RoomId: c.Param("room_id"),
+		UserId: actingUserID(c, c.Query("user_id")),
+		Type:   c.Query("type"),
+	}
+
+	var err error
+	if req.Since, err = queryUnixSeconds(c, "since"); err != nil {
+		httputil.ValidationError(c, "since", "必須是 Unix 秒")
+		return
+	}
+	if req.Until, err = queryUnixSeconds(c, "until"); err != nil {
+		httputil.ValidationError(c, "until", "必須是 Unix 秒")
+		return
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.GetRoomMessageCountResponse, error) {
+		return client.GetRoomMessageCount(ctx, req)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	switch resp.ErrorCode {
+	case constants.ErrorCodeInvalidArgument:
+		httputil.BadRequest(c, resp.Message)
+		return
+	case constants.ErrorCodePermissionDenied:
+		httputil.Forbidden(c, resp.Message)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"count":   resp.Count,
+	})
+}
+
+// 刪除用戶數據（GDPR 被遺忘權，dry_run 時只返回將受影響的數量）
+func deleteUserData(c *gin.Context) {
+	userID := c.Param("user_id")
+
+	var req struct {
+		RequesterID       string `json:"requester_id"`
+		DryRun            bool   `json:"dry_run"`
+		DeleteDirectRooms bool   `json:"delete_direct_rooms"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "無效的請求格式"})
+		return
+	}
+	if req.RequesterID == "" {
+		c.JSON(400, gin.H{"error": "缺少 requester_id 參數"})
+		return
+	}
+
+	grpcReq := &chat.DeleteUserDataRequest{
+		RequesterId:       req.RequesterID,
+		UserId:            userID,
+		DryRun:            req.DryRun,
+		DeleteDirectRooms: req.DeleteDirectRooms,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.DeleteUserData(ctx, grpcReq)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success":              resp.Success,
+		"message":              resp.Message,
+		"dry_run":              resp.DryRun,
+		"messages_anonymized":  resp.MessagesAnonymized,
+		"references_removed":   resp.ReferencesRemoved,
+		"rooms_left":           resp.RoomsLeft,
+		"direct_rooms_deleted": resp.DirectRoomsDeleted,
+	})
+}
+
+// 封存聊天室（只影響請求的用戶）
+func archiveRoom(c *gin.Context) {
+	roomID := c.Param("room_id")
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "無效的請求格式"})
+		return
+	}
+	req.UserID = actingUserID(c, req.UserID)
+
+	grpcReq := &chat.ArchiveRoomRequest{
+		RoomId: roomID,
+		UserId: req.UserID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.ArchiveRoomResponse, error) {
+		return client.ArchiveRoom(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
+
+// 取消封存聊天室
+func unarchiveRoom(c *gin.Context) {
+	roomID := c.Param("room_id")
+	userID := actingUserID(c, c.Query("user_id"))
+	if userID == "" {
+		c.JSON(400, gin.H{"error": "缺少 user_id 參數"})
+		return
+	}
+
+	grpcReq := &chat.UnarchiveRoomRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.UnarchiveRoomResponse, error) {
+		return client.UnarchiveRoom(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
+
+// 置頂聊天室（只影響請求的用戶，已封存的聊天室會同時取消封存）
+func pinRoom(c *gin.Context) {
+	roomID := c.Param("room_id")
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "無效的請求格式"})
+		return
+	}
+	req.UserID = actingUserID(c, req.UserID)
+
+	grpcReq := &chat.PinRoomRequest{
+		RoomId: roomID,
+		UserId: req.UserID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.PinRoomResponse, error) {
+		return client.PinRoom(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
+
+// 取消置頂聊天室
+func unpinRoom(c *gin.Context) {
+	roomID := c.Param("room_id")
+	userID := actingUserID(c, c.Query("user_id"))
+	if userID == "" {
+		c.JSON(400, gin.H{"error": "缺少 user_id 參數"})
+		return
+	}
+
+	grpcReq := &chat.UnpinRoomRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.UnpinRoomResponse, error) {
+		return client.UnpinRoom(ctx, grpcReq)
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
+
+// 移除群組成員
+// 帶有 operator_id 且不是成員本人時為移出成員（KickMember，需要擁有者或管理員），否則為成員自己離開（LeaveRoom）
+func removeRoomMember(c *gin.Context) {
+	roomID := c.Param("room_id")
+	userID := c.Param("user_id")
+
+	// 操作者為已認證的用戶；只有未啟用 JWT 時才使用 operator_id 參數
+	operatorID := c.GetString(middleware.UserIDKey)
+	if operatorID == "" {
+		operatorID = c.Query("operator_id")
+	}
+
+	if operatorID != "" && operatorID != userID {
+		if err := middleware.ValidateUserID(operatorID); err != nil {
+			httputil.ValidationError(c, "operator_id", err.Error())
+			return
+		}
+		kickRoomMember(c, &chat.KickMemberRequest{
+			RoomId:   roomID,
+			UserId:   operatorID,
+			MemberId: userID,
+		})
+		return
+	}
+
+	grpcReq := &chat.LeaveRoomRequest{
+		RoomId: roomID,
+		UserId: userID,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.LeaveRoom(ctx, grpcReq)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
+
+// 轉讓聊天室擁有權
+func transferRoomOwnership(c *gin.Context) {
+	roomID := c.Param("room_id")
+
+	var req struct {
+		UserID          string `json:"user_id"`
+		NewOwnerID      string `json:"new_owner_id"`
+		FormerOwnerRole string `json:"former_owner_role"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "無效的請求格式"})
+		return
+	}
+	// 操作者為已認證的用戶；只有未啟用 JWT 時才使用請求中的 user_id
+	if authUserID := c.GetString(middleware.UserIDKey); authUserID != "" {
+		req.UserID = authUserID
+	}
+	if err := middleware.ValidateUserID(req.UserID); err != nil {
+		httputil.ValidationError(c, "user_id", err.Error())
+		return
+	}
+	if err := middleware.ValidateUserID(req.NewOwnerID); err != nil {
+		httputil.ValidationError(c, "new_owner_id", err.Error())
+		return
+	}
+
+	grpcReq := &chat.TransferOwnershipRequest{
+		RoomId:          roomID,
+		UserId:          req.UserID,
+		NewOwnerId:      req.NewOwnerID,
+		FormerOwnerRole: req.FormerOwnerRole,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.TransferOwnership(ctx, grpcReq)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
+
+// kickRoomMember 由擁有者或管理員將成員移出聊天室
+func kickRoomMember(c *gin.Context, grpcReq *chat.KickMemberRequest) {
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.KickMember(ctx, grpcReq)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
 	c.JSON(200, gin.H{
 		"success": resp.Success,
 		"message": resp.Message,
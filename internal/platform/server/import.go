@@ -0,0 +1,93 @@
+package server
+
+import (
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/grpcclient"
+	"chat-gateway/internal/httputil"
+	"chat-gateway/proto/chat"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 批量導入聊天室（調用者是否為管理員由 gRPC 服務根據 JWT 用戶判斷）
+// 導入不是冪等操作，失敗時不自動重試
+func importRooms(c *gin.Context) {
+	var req chat.ImportRoomsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.BadRequest(c, "無效的請求格式")
+		return
+	}
+
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.ImportRooms(ctx, &req)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	respondImport(c, resp.Success, resp.Message, resp.ErrorCode, resp.Results, resp.Imported, resp.Failed)
+}
+
+// 批量導入歷史訊息（聊天室必須已存在，內容為明文，由 gRPC 服務加密）
+func importMessages(c *gin.Context) {
+	var req chat.ImportMessagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.BadRequest(c, "無效的請求格式")
+		return
+	}
+
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.ImportMessages(ctx, &req)
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	respondImport(c, resp.Success, resp.Message, resp.ErrorCode, resp.Results, resp.Imported, resp.Failed)
+}
+
+// respondImport 返回導入結果；部分項目失敗時仍返回 200，由 results 逐項說明
+func respondImport(c *gin.Context, success bool, message, errorCode string, results []*chat.ImportResult, imported, failed int32) {
+	switch errorCode {
+	case constants.ErrorCodeInvalidArgument:
+		httputil.BadRequest(c, message)
+		return
+	case constants.ErrorCodePermissionDenied:
+		httputil.Forbidden(c, message)
+		return
+	}
+
+	items := make([]gin.H, len(results))
+	for i, result := range results {
+		items[i] = gin.H{
+			"index":   result.Index,
+			"success": result.Success,
+			"id":      result.Id,
+			"error":   result.Error,
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"success":  success,
+		"message":  message,
+		"imported": imported,
+		"failed":   failed,
+		"results":  items,
+	})
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"chat-gateway/internal/constants"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// grpcRetryBaseDelay 第一次重試前的等待時間（測試中可以縮短）
+var grpcRetryBaseDelay = constants.HTTPGRPCRetryBaseDelayMs * time.Millisecond
+
+// isRetryableGRPCError 是否為暫時性錯誤（gRPC 服務重啟或滾動部署時返回，稍後重試通常可以成功）
+func isRetryableGRPCError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// grpcRetryDelay 第 retry 次重試前的等待時間（指數增長，不超過上限）
+func grpcRetryDelay(retry int) time.Duration {
+	delay := grpcRetryBaseDelay << (retry - 1)
+	if maxDelay := constants.HTTPGRPCRetryMaxDelayMs * time.Millisecond; delay > maxDelay || delay <= 0 {
+		return maxDelay
+	}
+	return delay
+}
+
+// withGRPCRetry 調用 gRPC 服務，遇到暫時性錯誤時以指數退避重試（最多 HTTPGRPCRetryAttempts 次）
+// 只能用於冪等的調用：讀取、設置狀態，或帶冪等鍵的 SendMessage；客戶端斷開時停止重試
+func withGRPCRetry[T any](c *gin.Context, call func(ctx context.Context) (T, error)) (T, error) {
+	resp, err := callWithTimeout(c, call)
+	for retry := 1; retry < constants.HTTPGRPCRetryAttempts && isRetryableGRPCError(err); retry++ {
+		select {
+		case <-c.Request.Context().Done():
+			return resp, err
+		case <-time.After(grpcRetryDelay(retry)):
+		}
+		resp, err = callWithTimeout(c, call)
+	}
+	return resp, err
+}
+
+// callWithTimeout 每次嘗試使用獨立的超時，避免前一次等待耗盡後續重試的時間
+func callWithTimeout[T any](c *gin.Context, call func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	return call(ctx)
+}
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newRetryTestContext 創建測試用的 gin context，並縮短重試等待時間
+func newRetryTestContext(t *testing.T) (*gin.Context, context.CancelFunc) {
+	t.Helper()
+	original := grpcRetryBaseDelay
+	grpcRetryBaseDelay = time.Millisecond
+	t.Cleanup(func() { grpcRetryBaseDelay = original })
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/rooms", nil).WithContext(ctx)
+	return c, cancel
+}
+
+func TestWithGRPCRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		code      codes.Code
+		wantCalls int
+		wantErr   bool
+	}{
+		{"success", 0, codes.OK, 1, false},
+		{"recovers from unavailable", 2, codes.Unavailable, 3, false},
+		{"recovers from deadline exceeded", 1, codes.DeadlineExceeded, 2, false},
+		{"gives up after max attempts", 10, codes.Unavailable, constants.HTTPGRPCRetryAttempts, true},
+		{"does not retry invalid argument", 10, codes.InvalidArgument, 1, true},
+		{"does not retry internal", 10, codes.Internal, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, cancel := newRetryTestContext(t)
+			defer cancel()
+
+			calls := 0
+			resp, err := withGRPCRetry(c, func(context.Context) (string, error) {
+				calls++
+				if calls <= tt.failures {
+					return "", status.Error(tt.code, "failed")
+				}
+				return "ok", nil
+			})
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if (err != nil) != tt.wantErr || (err == nil && resp != "ok") {
+				t.Errorf("withGRPCRetry() = %q, %v; wantErr %v", resp, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithGRPCRetry_StopsWhenClientDisconnects(t *testing.T) {
+	c, cancel := newRetryTestContext(t)
+	grpcRetryBaseDelay = time.Hour
+	cancel()
+
+	calls := 0
+	_, err := withGRPCRetry(c, func(context.Context) (struct{}, error) {
+		calls++
+		return struct{}{}, status.Error(codes.Unavailable, "restarting")
+	})
+	if calls != 1 || status.Code(err) != codes.Unavailable {
+		t.Errorf("calls = %d, err = %v; want a single call returning Unavailable", calls, err)
+	}
+}
+
+func TestGRPCRetryDelay(t *testing.T) {
+	base := time.Duration(constants.HTTPGRPCRetryBaseDelayMs) * time.Millisecond
+	maxDelay := time.Duration(constants.HTTPGRPCRetryMaxDelayMs) * time.Millisecond
+
+	if got := grpcRetryDelay(1); got != base {
+		t.Errorf("grpcRetryDelay(1) = %v, want %v", got, base)
+	}
+	if got := grpcRetryDelay(2); got != 2*base {
+		t.Errorf("grpcRetryDelay(2) = %v, want %v", got, 2*base)
+	}
+	if got := grpcRetryDelay(100); got != maxDelay {
+		t.Errorf("grpcRetryDelay(100) = %v, want %v", got, maxDelay)
+	}
+}
+
+func TestIsRetryableGRPCError(t *testing.T) {
+	if isRetryableGRPCError(nil) {
+		t.Error("nil error should not be retried")
+	}
+	if isRetryableGRPCError(errors.New("plain error")) {
+		t.Error("non-gRPC error should not be retried")
+	}
+	if !isRetryableGRPCError(status.Error(codes.Unavailable, "transport is closing")) {
+		t.Error("Unavailable should be retried")
+	}
+}
+
+func TestWithGRPCRetry_FreshDeadlinePerAttempt(t *testing.T) {
+	c, cancel := newRetryTestContext(t)
+	defer cancel()
+
+	var deadlines []time.Time
+	_, _ = withGRPCRetry(c, func(ctx context.Context) (struct{}, error) {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("attempt context has no deadline")
+		}
+		deadlines = append(deadlines, deadline)
+		return struct{}{}, status.Error(codes.DeadlineExceeded, "slow backend")
+	})
+	if len(deadlines) != constants.HTTPGRPCRetryAttempts {
+		t.Fatalf("attempts = %d, want %d", len(deadlines), constants.HTTPGRPCRetryAttempts)
+	}
+	if !deadlines[len(deadlines)-1].After(deadlines[0]) {
+		t.Error("retries should get a fresh deadline")
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+
+	"chat-gateway/internal/grpcclient"
+	"chat-gateway/internal/httputil"
+	"chat-gateway/internal/message"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/proto/chat"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// registerScheduledRoutes 註冊定時訊息端點（需要在配置中開啟）
+func registerScheduledRoutes(api *gin.RouterGroup) {
+	if cfg := config.Get(); cfg == nil || !cfg.Limits.Scheduled.Enabled {
+		return
+	}
+
+	api.POST("/messages/scheduled", scheduleMessage)
+	api.GET("/messages/scheduled", listScheduledMessages)
+	api.DELETE("/messages/scheduled/:id", cancelScheduledMessage)
+}
+
+// 創建定時訊息
+func scheduleMessage(c *gin.Context) {
+	var req struct {
+		RoomID   string                `json:"room_id"`
+		SenderID string                `json:"sender_id"`
+		Content  string                `json:"content"`
+		Type     string                `json:"type"`
+		Metadata *chat.MessageMetadata `json:"metadata,omitempty"`
+		Mentions []string              `json:"mentions,omitempty"`
+		TTL      int32                 `json:"ttl_seconds,omitempty"` // 限時訊息：從發送時開始計算
+		SendAt   int64                 `json:"send_at"`               // 發送時間（Unix 秒）
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(400, gin.H{"error": "無效的請求格式"})
+		return
+	}
+
+	if err := middleware.ValidateRoomID(req.RoomID); err != nil {
+		httputil.BadRequest(c, err.Error())
+		return
+	}
+	// 發送者為已認證的用戶；只有未啟用 JWT 時才使用請求中的 sender_id
+	if authUserID := c.GetString(middleware.UserIDKey); authUserID != "" {
+		req.SenderID = authUserID
+	}
+	if err := middleware.ValidateUserID(req.SenderID); err != nil {
+		httputil.BadRequest(c, err.Error())
+		return
+	}
+	if req.SendAt <= 0 {
+		httputil.ValidationError(c, "send_at", "缺少發送時間")
+		return
+	}
+
+	msgType, err := message.NormalizeMessageType(req.Type)
+	if err != nil {
+		httputil.ValidationError(c, "type", err.Error())
+		return
+	}
+	if msgType == message.MessageTypeText || req.Content != "" {
+		if err := middleware.ValidateMessageContent(req.Content); err != nil {
+			httputil.BadRequest(c, err.Error())
+			return
+		}
+	}
+
+	grpcReq := &chat.ScheduleMessageRequest{
+		Message: &chat.SendMessageRequest{
+			RoomId:     req.RoomID,
+			SenderId:   req.SenderID,
+			Content:    middleware.SanitizeInput(req.Content),
+			Type:       msgType,
+			Metadata:   req.Metadata,
+			Mentions:   req.Mentions,
+			TtlSeconds: req.TTL,
+		},
+		SendAt: req.SendAt,
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.ScheduleMessage(ctx, grpcReq)
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			httputil.BadRequest(c, status.Convert(err).Message())
+			return
+		}
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"data":    resp.ScheduledMessage,
+	})
+}
+
+// 列出待發送的定時訊息
+func listScheduledMessages(c *gin.Context) {
+	userID := actingUserID(c, c.Query("user_id"))
+	roomID := c.Query("room_id")
+
+	if err := middleware.ValidateUserID(userID); err != nil {
+		httputil.ValidationError(c, "user_id", err.Error())
+		return
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	resp, err := withGRPCRetry(c, func(ctx context.Context) (*chat.ListScheduledMessagesResponse, error) {
+		return client.ListScheduledMessages(ctx, &chat.ListScheduledMessagesRequest{
+			UserId: userID,
+			RoomId: roomID,
+		})
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+		"data":    resp.ScheduledMessages,
+	})
+}
+
+// 取消定時訊息
+func cancelScheduledMessage(c *gin.Context) {
+	id := c.Param("id")
+	userID := actingUserID(c, c.Query("user_id"))
+
+	if err := middleware.ValidateUserID(userID); err != nil {
+		httputil.ValidationError(c, "user_id", err.Error())
+		return
+	}
+
+	// 調用 gRPC 服務
+	conn, err := grpcclient.GetConnection()
+	if err != nil {
+		httputil.InternalServerError(c, err)
+		return
+	}
+
+	client := chat.NewChatRoomServiceClient(conn)
+	ctx, cancel := grpcContext(c)
+	defer cancel()
+	resp, err := client.CancelScheduledMessage(ctx, &chat.CancelScheduledMessageRequest{
+		Id:     id,
+		UserId: userID,
+	})
+	if err != nil {
+		grpcCallFailed(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"success": resp.Success,
+		"message": resp.Message,
+	})
+}
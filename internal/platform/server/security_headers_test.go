@@ -0,0 +1,30 @@
+package server
+
+import (
+	"testing"
+
+	"chat-gateway/internal/platform/config"
+)
+
+func TestHSTSHeaderValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.HSTSConfig
+		useHTTPS bool
+		want     string
+	}{
+		{"disabled over http", config.HSTSConfig{}, false, ""},
+		{"automatic over https", config.HSTSConfig{}, true, "max-age=31536000"},
+		{"custom max age", config.HSTSConfig{Enabled: true, MaxAgeSeconds: 600}, false, "max-age=600"},
+		{"preload", config.HSTSConfig{Enabled: true, IncludeSubdomains: true, Preload: true}, false,
+			"max-age=31536000; includeSubDomains; preload"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hstsHeaderValue(tt.cfg, tt.useHTTPS); got != tt.want {
+				t.Errorf("hstsHeaderValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,42 +9,14 @@ import (
 	"time"
 
 	"chat-gateway/internal/platform/config"
-	"chat-gateway/internal/platform/driver"
 	"chat-gateway/internal/platform/logger"
 	"chat-gateway/internal/storage/database"
 )
 
 // Start 啟動伺服器.
-func Start(repos *database.Repositories) error {
-	// 初始化日誌系統
-	if err := logger.InitLogger(); err != nil {
-		return err
-	}
-	defer logger.CloseLogger()
-
-	logger.LogInfof("正在啟動 ChatGateway API 伺服器...")
-
-	// 載入設定
-	if err := config.Load(); err != nil {
-		logger.LogErrorf("載入設定失敗: %v", err)
-		return err
-	}
-
-	cfg := config.Get()
-	logger.LogInfof("設定載入成功，環境: %s", config.GetEnv())
-
-	// connect db
-	if err := driver.ConnectMongo(); err != nil {
-		logger.LogErrorf("資料庫連接失敗: %v", err)
-		return err
-	}
-	defer func() {
-		if err := driver.CloseMongo(); err != nil {
-			logger.LogErrorf("關閉 MongoDB 連接失敗: %v", err)
-		}
-	}()
-
-	logger.LogInfof("儲存庫集合初始化完成")
+// 配置、日誌和數據庫連接由調用方（cmd/api）初始化並負責關閉，這裡不重複初始化
+func Start(cfg *config.Config, repos *database.Repositories) error {
+	logger.LogInfof("正在啟動 ChatGateway API 伺服器，環境: %s", config.GetEnv())
 
 	// setting router
 	router := Router()
@@ -62,8 +34,7 @@ func Start(repos *database.Repositories) error {
 	go func() {
 		logger.LogInfof("伺服器正在監聽埠口: %s", cfg.Server.Port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.LogErrorf("伺服器啟動失敗: %v", err)
-			os.Exit(1)
+			logger.Fatalf(context.Background(), "伺服器啟動失敗: %v", err)
 		}
 	}()
 
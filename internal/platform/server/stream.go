@@ -2,17 +2,26 @@ package server
 
 import (
 	"context"
+	"errors"
 	"io"
+	"sync/atomic"
 	"time"
 
 	"chat-gateway/internal/constants"
 	"chat-gateway/internal/grpcclient"
 	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/platform/logger"
 	"chat-gateway/proto/chat"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// streamMessagesPath SSE 訊息流的路徑（相對於 /api/v1）
+const streamMessagesPath = "/messages/stream"
+
 // streamMessages 使用 SSE 流式推送訊息
 func streamMessages(c *gin.Context) {
 	roomID, userID, ok := validateStreamParams(c)
@@ -22,19 +31,24 @@ func streamMessages(c *gin.Context) {
 
 	setupSSEHeaders(c)
 
-	stream, ok := createGRPCStream(c, roomID, userID)
+	// 處理器返回時取消 gRPC stream，接收訊息的 goroutine 隨之結束（長連接不設置調用超時）
+	ctx, cancel := context.WithCancel(grpcStreamContext(c))
+	defer cancel()
+
+	stream, ok := createGRPCStream(ctx, c, roomID, userID)
 	if !ok {
 		return
 	}
 
-	msgChan, errChan := setupMessageChannels(stream)
-	handleSSELoop(c, msgChan, errChan)
+	queue := setupMessageQueue(ctx, stream, roomID)
+	handleSSELoop(c, queue)
 }
 
 // validateStreamParams 驗證流參數
 func validateStreamParams(c *gin.Context) (roomID, userID string, ok bool) {
 	roomID = c.Query("room_id")
-	userID = c.Query("user_id")
+	// 訂閱者為已認證的用戶；只有未啟用 JWT 時才使用請求中的 user_id
+	userID = actingUserID(c, c.Query("user_id"))
 
 	if roomID == "" || userID == "" {
 		c.JSON(400, gin.H{"error": "缺少 room_id 或 user_id 參數"})
@@ -55,8 +69,17 @@ func setupSSEHeaders(c *gin.Context) {
 	c.Writer.Flush()
 }
 
+// resumeCursor 斷線續傳的起點：瀏覽器自動重連時帶上的 Last-Event-ID，
+// 重新創建 EventSource 時無法設置 header，可改用 since_cursor 查詢參數
+func resumeCursor(c *gin.Context) string {
+	if id := c.GetHeader("Last-Event-ID"); id != "" {
+		return id
+	}
+	return c.Query("since_cursor")
+}
+
 // createGRPCStream 創建 gRPC stream
-func createGRPCStream(c *gin.Context, roomID, userID string) (chat.ChatRoomService_StreamMessagesClient, bool) {
+func createGRPCStream(ctx context.Context, c *gin.Context, roomID, userID string) (chat.ChatRoomService_StreamMessagesClient, bool) {
 	conn, err := grpcclient.GetConnection()
 	if err != nil {
 		c.SSEvent("error", gin.H{"message": "連接 gRPC 服務失敗"})
@@ -65,9 +88,10 @@ func createGRPCStream(c *gin.Context, roomID, userID string) (chat.ChatRoomServi
 	}
 
 	client := chat.NewChatRoomServiceClient(conn)
-	stream, err := client.StreamMessages(context.Background(), &chat.StreamMessagesRequest{
-		RoomId: roomID,
-		UserId: userID,
+	stream, err := client.StreamMessages(ctx, &chat.StreamMessagesRequest{
+		RoomId:      roomID,
+		UserId:      userID,
+		SinceCursor: resumeCursor(c),
 	})
 	if err != nil {
 		c.SSEvent("error", gin.H{"message": "建立訊息流失敗: " + err.Error()})
@@ -78,71 +102,185 @@ func createGRPCStream(c *gin.Context, roomID, userID string) (chat.ChatRoomServi
 	return stream, true
 }
 
-// setupMessageChannels 設置訊息通道
-func setupMessageChannels(stream chat.ChatRoomService_StreamMessagesClient) (msgChan chan *chat.ChatMessage, errChan chan error) {
+// errSlowClient 客戶端處理過慢、訊息隊列已滿（disconnect 策略）
+var errSlowClient = errors.New("客戶端處理過慢，訊息隊列已滿")
+
+// sseDroppedMessages 所有 SSE 連接因客戶端過慢而丟棄的訊息總數
+var sseDroppedMessages atomic.Int64
+
+// sseMessageQueue gRPC 訊息流和 SSE 連接之間的緩衝隊列
+// 接收方從不阻塞：隊列已滿時按策略丟棄最舊的訊息或斷開連接，避免慢客戶端卡住接收 goroutine
+type sseMessageQueue struct {
+	messages chan *chat.ChatMessage
+	errs     chan error
+	policy   string
+	skipped  atomic.Int64 // 已丟棄但尚未通知客戶端的訊息數
+}
+
+// newSSEMessageQueue 創建訊息隊列
+func newSSEMessageQueue(buffer int, policy string) *sseMessageQueue {
+	return &sseMessageQueue{
+		messages: make(chan *chat.ChatMessage, buffer),
+		errs:     make(chan error, 1),
+		policy:   policy,
+	}
+}
+
+// setupMessageQueue 設置訊息隊列並在後台接收訊息
+func setupMessageQueue(ctx context.Context, stream chat.ChatRoomService_StreamMessagesClient, roomID string) *sseMessageQueue {
 	cfg := config.Get()
 	channelBuffer := constants.MessageChannelBuffer
-	if cfg != nil && cfg.Limits.SSE.MessageChannelBuffer > 0 {
-		channelBuffer = cfg.Limits.SSE.MessageChannelBuffer
+	policy := constants.SSESlowClientDropOldest
+	if cfg != nil {
+		if cfg.Limits.SSE.MessageChannelBuffer > 0 {
+			channelBuffer = cfg.Limits.SSE.MessageChannelBuffer
+		}
+		if cfg.Limits.SSE.SlowClientPolicy != "" {
+			policy = cfg.Limits.SSE.SlowClientPolicy
+		}
 	}
 
-	msgChan = make(chan *chat.ChatMessage, channelBuffer)
-	errChan = make(chan error, 1)
-
+	queue := newSSEMessageQueue(channelBuffer, policy)
 	go func() {
 		for {
 			msg, err := stream.Recv()
 			if err != nil {
-				errChan <- err
+				queue.errs <- err
+				return
+			}
+			if !queue.push(msg) {
+				logger.Warning(ctx, "SSE 客戶端處理過慢，斷開連接", logger.WithRoomID(roomID))
+				queue.errs <- errSlowClient
 				return
 			}
-			msgChan <- msg
 		}
 	}()
 
-	return msgChan, errChan
+	return queue
+}
+
+// push 放入一條訊息（不阻塞）
+// 隊列已滿時：drop_oldest 策略丟棄最舊的訊息並計數；disconnect 策略返回 false，由調用方斷開連接
+func (q *sseMessageQueue) push(msg *chat.ChatMessage) bool {
+	for {
+		select {
+		case q.messages <- msg:
+			return true
+		default:
+		}
+
+		if q.policy == constants.SSESlowClientDisconnect {
+			return false
+		}
+		select {
+		case <-q.messages:
+			q.skipped.Add(1)
+			sseDroppedMessages.Add(1)
+		default:
+			// 隊列剛被消費，重試放入
+		}
+	}
 }
 
 // handleSSELoop 處理 SSE 循環
-func handleSSELoop(c *gin.Context, msgChan chan *chat.ChatMessage, errChan chan error) {
+func handleSSELoop(c *gin.Context, queue *sseMessageQueue) {
 	cfg := config.Get()
-	heartbeatInterval := 15
-	if cfg != nil && cfg.Limits.SSE.HeartbeatInterval > 0 {
-		heartbeatInterval = cfg.Limits.SSE.HeartbeatInterval
+	heartbeatInterval := constants.DefaultSSEHeartbeatInterval
+	maxDuration := constants.DefaultSSEMaxConnectionDuration
+	if cfg != nil {
+		if cfg.Limits.SSE.HeartbeatInterval > 0 {
+			heartbeatInterval = cfg.Limits.SSE.HeartbeatInterval
+		}
+		if cfg.Limits.SSE.MaxConnectionDuration > 0 {
+			maxDuration = cfg.Limits.SSE.MaxConnectionDuration
+		}
 	}
 
-	ticker := time.NewTicker(time.Duration(heartbeatInterval) * time.Second)
+	runSSELoop(c, queue, time.Duration(heartbeatInterval)*time.Second, time.Duration(maxDuration)*time.Second)
+}
+
+// runSSELoop 推送訊息和心跳，連接達到最長存活時間後發送 reconnect 事件並關閉，
+// 讓客戶端重新建立連接，避免半斷開的客戶端一直佔用連接名額
+func runSSELoop(c *gin.Context, queue *sseMessageQueue, heartbeatInterval, maxDuration time.Duration) {
+	ticker := time.NewTicker(heartbeatInterval)
 	defer ticker.Stop()
 
+	lifetime := time.NewTimer(maxDuration)
+	defer lifetime.Stop()
+
 	for {
 		select {
 		case <-c.Request.Context().Done():
 			return
 
+		case <-lifetime.C:
+			c.SSEvent("reconnect", gin.H{"reason": "max_connection_duration"})
+			c.Writer.Flush()
+			return
+
 		case <-ticker.C:
 			c.SSEvent("ping", gin.H{"timestamp": time.Now().Unix()})
 			c.Writer.Flush()
 
-		case msg := <-msgChan:
-			c.SSEvent("message", gin.H{
-				"id":         msg.Id,
-				"room_id":    msg.RoomId,
-				"sender_id":  msg.SenderId,
-				"content":    msg.Content,
-				"type":       msg.Type,
-				"created_at": msg.CreatedAt,
-				"updated_at": msg.UpdatedAt,
-				"read_by":    msg.ReadBy,
-			})
+		case msg := <-queue.messages:
+			// 先通知客戶端有訊息被跳過，客戶端可通過歷史訊息接口補回
+			if skipped := queue.skipped.Swap(0); skipped > 0 {
+				c.SSEvent("skipped", gin.H{"count": skipped})
+			}
+			writeMessageEvent(c, msg)
 			c.Writer.Flush()
 
-		case err := <-errChan:
+		case err := <-queue.errs:
 			if err == io.EOF {
 				return
 			}
+			if errors.Is(err, errSlowClient) {
+				c.SSEvent("error", gin.H{"message": err.Error(), "code": "slow_client"})
+				c.Writer.Flush()
+				return
+			}
+			if st, ok := status.FromError(err); ok && st.Code() == codes.PermissionDenied {
+				// 非成員或已被移出聊天室
+				c.SSEvent("error", gin.H{"message": st.Message(), "code": "permission_denied"})
+				c.Writer.Flush()
+				return
+			}
 			c.SSEvent("error", gin.H{"message": "接收訊息失敗: " + err.Error()})
 			c.Writer.Flush()
 			return
 		}
 	}
 }
+
+// writeMessageEvent 推送一條訊息，事件 ID 使用訊息游標（按 created_at + _id 單調遞增），
+// 瀏覽器重連時會以 Last-Event-ID 帶回，用於補發斷線期間的訊息
+// 限時訊息到期時推送 expired 事件（不帶事件 ID，不影響斷線續傳的位置）
+func writeMessageEvent(c *gin.Context, msg *chat.ChatMessage) {
+	if msg.Expired {
+		c.SSEvent("expired", gin.H{"id": msg.Id, "room_id": msg.RoomId})
+		return
+	}
+
+	c.Render(-1, sse.Event{
+		Id:    msg.Cursor,
+		Event: "message",
+		Data: gin.H{
+			"id":             msg.Id,
+			"room_id":        msg.RoomId,
+			"sender_id":      msg.SenderId,
+			"sender_name":    msg.SenderName,
+			"content":        msg.Content,
+			"type":           msg.Type,
+			"metadata":       msg.Metadata,
+			"created_at":     msg.CreatedAt,
+			"updated_at":     msg.UpdatedAt,
+			"read_by":        msg.ReadBy,
+			"read_receipts":  msg.ReadReceipts,
+			"status":         msg.Status,
+			"delivered_to":   msg.DeliveredTo,
+			"mentions":       msg.Mentions,
+			"forwarded_from": msg.ForwardedFrom,
+			"expires_at":     msg.ExpiresAt,
+		},
+	})
+}
@@ -0,0 +1,188 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/proto/chat"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRunSSELoop_ReconnectsAfterMaxDuration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/messages/stream", nil)
+
+	done := make(chan struct{})
+	go func() {
+		runSSELoop(c, newSSEMessageQueue(1, constants.SSESlowClientDropOldest), time.Hour, 20*time.Millisecond)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("runSSELoop did not return after max connection duration")
+	}
+	if body := w.Body.String(); !strings.Contains(body, "event:reconnect") {
+		t.Errorf("body = %q, want reconnect event", body)
+	}
+}
+
+func TestRunSSELoop_HeartbeatBeforeMaxDuration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/messages/stream", nil)
+
+	runSSELoop(c, newSSEMessageQueue(1, constants.SSESlowClientDropOldest), 10*time.Millisecond, 100*time.Millisecond)
+
+	body := w.Body.String()
+	ping := strings.Index(body, "event:ping")
+	reconnect := strings.Index(body, "event:reconnect")
+	if ping < 0 || reconnect < ping {
+		t.Errorf("body = %q, want ping events before the reconnect event", body)
+	}
+}
+
+func TestResumeCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name   string
+		header string
+		url    string
+		want   string
+	}{
+		{"none", "", "/stream?room_id=r", ""},
+		{"last event id header", "cursor-a", "/stream?room_id=r", "cursor-a"},
+		{"query fallback", "", "/stream?room_id=r&since_cursor=cursor-b", "cursor-b"},
+		{"header wins", "cursor-a", "/stream?room_id=r&since_cursor=cursor-b", "cursor-a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.header != "" {
+				c.Request.Header.Set("Last-Event-ID", tt.header)
+			}
+			if got := resumeCursor(c); got != tt.want {
+				t.Errorf("resumeCursor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStreamParams_UsesAuthenticatedUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/stream?room_id=r&user_id=alice", nil)
+	c.Set(middleware.UserIDKey, "mallory")
+
+	_, userID, ok := validateStreamParams(c)
+	if !ok || userID != "mallory" {
+		t.Errorf("validateStreamParams() = %q, %v; want the authenticated user", userID, ok)
+	}
+}
+
+func TestWriteMessageEvent_UsesCursorAsEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	writeMessageEvent(c, &chat.ChatMessage{Id: "m1", RoomId: "r1", Content: "hi", Cursor: "cursor-1"})
+
+	body := w.Body.String()
+	if !strings.Contains(body, "id:cursor-1\n") || !strings.Contains(body, "event:message\n") {
+		t.Errorf("body = %q, want message event with cursor id", body)
+	}
+	if !strings.Contains(body, `"id":"m1"`) {
+		t.Errorf("body = %q, want message payload", body)
+	}
+}
+
+func TestWriteMessageEvent_Expired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	writeMessageEvent(c, &chat.ChatMessage{Id: "m1", RoomId: "r1", ExpiresAt: 1700000000, Expired: true})
+
+	body := w.Body.String()
+	if !strings.Contains(body, "event:expired\n") || strings.Contains(body, "id:") {
+		t.Errorf("body = %q, want expired event without event id", body)
+	}
+	if !strings.Contains(body, `"id":"m1"`) || !strings.Contains(body, `"room_id":"r1"`) {
+		t.Errorf("body = %q, want message and room id", body)
+	}
+}
+
+func TestSSEMessageQueue_DropOldest(t *testing.T) {
+	queue := newSSEMessageQueue(2, constants.SSESlowClientDropOldest)
+	for _, id := range []string{"m1", "m2", "m3"} {
+		if !queue.push(&chat.ChatMessage{Id: id}) {
+			t.Fatalf("push(%s) = false, want true with drop_oldest", id)
+		}
+	}
+
+	if got := queue.skipped.Load(); got != 1 {
+		t.Errorf("skipped = %d, want 1", got)
+	}
+	if first := <-queue.messages; first.Id != "m2" {
+		t.Errorf("oldest remaining message = %s, want m2", first.Id)
+	}
+}
+
+func TestSSEMessageQueue_Disconnect(t *testing.T) {
+	queue := newSSEMessageQueue(1, constants.SSESlowClientDisconnect)
+	if !queue.push(&chat.ChatMessage{Id: "m1"}) {
+		t.Fatal("push into empty queue = false, want true")
+	}
+	if queue.push(&chat.ChatMessage{Id: "m2"}) {
+		t.Error("push into full queue = true, want false with disconnect")
+	}
+	if got := queue.skipped.Load(); got != 0 {
+		t.Errorf("skipped = %d, want 0", got)
+	}
+}
+
+func TestRunSSELoop_ReportsSkippedMessages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/messages/stream", nil)
+
+	queue := newSSEMessageQueue(1, constants.SSESlowClientDropOldest)
+	queue.push(&chat.ChatMessage{Id: "m1"})
+	queue.push(&chat.ChatMessage{Id: "m2"})
+	runSSELoop(c, queue, time.Hour, 50*time.Millisecond)
+
+	body := w.Body.String()
+	skipped := strings.Index(body, "event:skipped")
+	message := strings.Index(body, `"id":"m2"`)
+	if skipped < 0 || message < skipped {
+		t.Errorf("body = %q, want skipped event before the next message", body)
+	}
+}
+
+func TestRunSSELoop_SlowClientError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/messages/stream", nil)
+
+	queue := newSSEMessageQueue(1, constants.SSESlowClientDisconnect)
+	queue.errs <- errSlowClient
+	runSSELoop(c, queue, time.Hour, time.Hour)
+
+	if body := w.Body.String(); !strings.Contains(body, "slow_client") {
+		t.Errorf("body = %q, want slow_client error event", body)
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"chat-gateway/internal/httputil"
+	"chat-gateway/internal/platform/logger"
+	"chat-gateway/internal/platform/middleware"
+	"chat-gateway/internal/storage/attachment"
+	"chat-gateway/internal/storage/filestore"
+	"chat-gateway/proto/chat"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead multipart 請求中表單字段和分隔符允許的額外大小
+const multipartOverhead = 64 << 10
+
+// errFileTooLarge 上傳的文件超過大小上限
+var errFileTooLarge = errors.New("file too large")
+
+// registerUploadRoutes 註冊附件上傳端點（需要在配置中開啟）
+func registerUploadRoutes(r *gin.Engine, api *gin.RouterGroup) {
+	store, err := attachment.NewStoreFromConfig()
+	if err != nil {
+		logger.Error(context.Background(), "創建附件存儲失敗，不註冊附件上傳端點",
+			logger.WithDetails(map[string]interface{}{"error": err.Error()}))
+		return
+	}
+	if store == nil {
+		return
+	}
+
+	serveLocalFiles(r, store)
+	api.POST("/uploads", uploadFile(store))
+}
+
+// serveLocalFiles 本地存儲且 URL 前綴為路徑時，由 HTTP 服務提供文件的靜態訪問
+// 頭像和附件可以共用同一個前綴，已註冊的前綴不重複註冊
+func serveLocalFiles(r *gin.Engine, store filestore.Store) {
+	local, ok := store.(*filestore.LocalStore)
+	if !ok || !strings.HasPrefix(local.BaseURL(), "/") {
+		return
+	}
+
+	pattern := local.BaseURL() + "/*filepath"
+	for _, route := range r.Routes() {
+		if route.Method == http.MethodGet && route.Path == pattern {
+			return
+		}
+	}
+	r.Static(local.BaseURL(), local.Dir())
+}
+
+// 上傳訊息附件，返回的 metadata 可直接作為 SendMessage 的 metadata
+func uploadFile(store filestore.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maxSize := attachment.MaxSize()
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+		data, header, err := readUploadedFile(c, "file", maxSize)
+		if err != nil {
+			if isFileTooLarge(err) {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "文件過大"})
+				return
+			}
+			httputil.BadRequest(c, "缺少上傳文件")
+			return
+		}
+
+		userID := c.PostForm("user_id")
+		if err := middleware.ValidateUserID(userID); err != nil {
+			httputil.ValidationError(c, "user_id", err.Error())
+			return
+		}
+
+		info, err := attachment.Inspect(header.Filename, data, attachment.AllowedTypes())
+		if err != nil {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "不支持的文件類型"})
+			return
+		}
+
+		key, err := attachment.Key(time.Now(), info.Extension())
+		if err != nil {
+			httputil.InternalServerError(c, err)
+			return
+		}
+		fileURL, err := store.Save(c.Request.Context(), key, info.ContentType, data)
+		if err != nil {
+			httputil.InternalServerError(c, err)
+			return
+		}
+		fileURL = absoluteFileURL(c, fileURL)
+
+		logger.Info(c.Request.Context(), "附件上傳成功",
+			logger.WithUserID(userID),
+			logger.WithAction("upload_file"),
+			logger.WithDetails(map[string]interface{}{
+				"key":          key,
+				"content_type": info.ContentType,
+				"size":         info.Size,
+			}))
+
+		c.JSON(200, gin.H{
+			"success":  true,
+			"url":      fileURL,
+			"metadata": attachmentMetadata(info, fileURL),
+		})
+	}
+}
+
+// attachmentMetadata 附件對應的訊息元數據（圖片同時填寫 image_url 和尺寸）
+func attachmentMetadata(info attachment.Info, fileURL string) *chat.MessageMetadata {
+	metadata := &chat.MessageMetadata{
+		FileName: info.FileName,
+		FileSize: strconv.FormatInt(info.Size, 10),
+		FileType: info.ContentType,
+		FileUrl:  fileURL,
+	}
+	if info.IsImage() {
+		metadata.ImageUrl = fileURL
+		metadata.ImageWidth = int32(info.Width)   // #nosec G115 -- 圖片尺寸由解碼器限制在 int32 範圍內
+		metadata.ImageHeight = int32(info.Height) // #nosec G115 -- 同上
+	}
+	return metadata
+}
+
+// absoluteFileURL 把路徑形式的 URL 補全為當前請求的 scheme 和 host（SendMessage 只接受 http/https 的完整 URL）
+func absoluteFileURL(c *gin.Context, fileURL string) string {
+	if !strings.HasPrefix(fileURL, "/") {
+		return fileURL
+	}
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host + fileURL
+}
+
+// readUploadedFile 讀取表單中的文件（超過 maxSize 時返回 errFileTooLarge）
+func readUploadedFile(c *gin.Context, field string, maxSize int64) ([]byte, *multipart.FileHeader, error) {
+	header, err := c.FormFile(field)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.Size > maxSize {
+		return nil, nil, errFileTooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, nil, errFileTooLarge
+	}
+	return data, header, nil
+}
+
+// isFileTooLarge 是否為請求體或文件超過大小上限的錯誤
+func isFileTooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge) || errors.Is(err, errFileTooLarge)
+}
+
+// deleteStoredFile 刪除沒有被使用的文件（例如更新聊天室失敗時的頭像）
+func deleteStoredFile(c *gin.Context, store filestore.Store, key string) {
+	if err := store.Delete(c.Request.Context(), key); err != nil {
+		logger.Warning(c.Request.Context(), "刪除未使用的文件失敗",
+			logger.WithDetails(map[string]interface{}{"key": key, "error": err.Error()}))
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"testing"
+
+	"chat-gateway/internal/storage/attachment"
+)
+
+func TestAttachmentMetadata(t *testing.T) {
+	const fileURL = "https://cdn.example.com/uploads/2026/03/a.png"
+
+	image := attachmentMetadata(attachment.Info{
+		FileName: "a.png", Size: 1024, ContentType: "image/png", Width: 800, Height: 600,
+	}, fileURL)
+	if image.FileSize != "1024" || image.FileType != "image/png" || image.FileUrl != fileURL {
+		t.Errorf("file fields = %q, %q, %q", image.FileSize, image.FileType, image.FileUrl)
+	}
+	if image.ImageUrl != fileURL || image.ImageWidth != 800 || image.ImageHeight != 600 {
+		t.Errorf("image fields = %q, %d, %d", image.ImageUrl, image.ImageWidth, image.ImageHeight)
+	}
+
+	pdf := attachmentMetadata(attachment.Info{FileName: "a.pdf", Size: 10, ContentType: "application/pdf"}, fileURL)
+	if pdf.ImageUrl != "" || pdf.ImageWidth != 0 {
+		t.Errorf("non-image metadata has image fields: %+v", pdf)
+	}
+}
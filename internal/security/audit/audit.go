@@ -108,6 +108,14 @@ func (a *AuditService) LogRoomLeave(ctx context.Context, userID, roomID string)
 	a.logSimpleEvent("room_leave", userID, roomID, "leave_room", "success", nil)
 }
 
+// LogRoomDeletion 記錄刪除聊天室
+func (a *AuditService) LogRoomDeletion(ctx context.Context, userID, roomID string, deletedMessages, deletedKeys int64) {
+	a.logSimpleEvent("room_deletion", userID, roomID, "delete_room", "success", map[string]interface{}{
+		"deleted_messages": deletedMessages,
+		"deleted_keys":     deletedKeys,
+	})
+}
+
 // LogMemberAdded 記錄添加成員
 func (a *AuditService) LogMemberAdded(ctx context.Context, operatorID, roomID, memberID string) {
 	a.logSimpleEvent("member_added", operatorID, roomID, "add_member", "success", map[string]interface{}{
@@ -122,6 +130,13 @@ func (a *AuditService) LogMemberRemoved(ctx context.Context, operatorID, roomID,
 	})
 }
 
+// LogOwnershipTransferred 記錄轉讓聊天室擁有權
+func (a *AuditService) LogOwnershipTransferred(ctx context.Context, formerOwnerID, roomID, newOwnerID string) {
+	a.logSimpleEvent("ownership_transferred", formerOwnerID, roomID, "transfer_ownership", "success", map[string]interface{}{
+		"new_owner_id": newOwnerID,
+	})
+}
+
 // LogAuthenticationFailure 記錄認證失敗
 func (a *AuditService) LogAuthenticationFailure(ctx context.Context, userID, reason string) {
 	if !a.enabled {
@@ -3,26 +3,45 @@ package encryption
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
 )
 
 const (
-	aes256CTRPrefix = "aes256ctr:"
-	plaintextPrefix = "plaintext:"
-	encryptedPrefix = "encrypted:"
+	aes256CTRPrefix   = "aes256ctr:"   // 舊格式（無完整性保護，僅用於解密）
+	aes256CTRV2Prefix = "aes256ctrv2:" // 新格式（Encrypt-then-MAC）
+	plaintextPrefix   = "plaintext:"
+	encryptedPrefix   = "encrypted:"
+
+	// macSize HMAC-SHA256 標籤長度
+	macSize = sha256.Size
+	// macKeyInfo HKDF 派生 MAC 密鑰時使用的 info
+	macKeyInfo = "aes256ctrv2-hmac"
 )
 
+// ErrTampered 密文完整性驗證失敗（被竄改或使用了錯誤的密鑰）
+var ErrTampered = errors.New("ciphertext authentication failed: message has been tampered with")
+
 // AESCTREncryption AES-256-CTR 加密實現
 // CTR 模式特點：
 // - 將塊密碼轉換為流密碼
 // - 可並行加密/解密
 // - 不需要填充
 // - 適合大數據加密
+//
+// 新格式使用 Encrypt-then-MAC：HMAC-SHA256 覆蓋 IV + 密文，
+// MAC 密鑰由 room key 經 HKDF 派生，與加密密鑰分離
 type AESCTREncryption struct {
-	key []byte // 256-bit (32 bytes) key
+	key    []byte // 256-bit (32 bytes) key
+	macKey []byte // HKDF 派生的 HMAC 密鑰
 }
 
 // NewAESCTREncryption 創建 AES-256-CTR 加密實例
@@ -36,24 +55,32 @@ func NewAESCTREncryption(key []byte) (*AESCTREncryption, error) {
 	keyCopy := make([]byte, len(key))
 	copy(keyCopy, key)
 
+	// 派生 MAC 密鑰
+	macKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, keyCopy, nil, []byte(macKeyInfo)), macKey); err != nil {
+		return nil, fmt.Errorf("failed to derive mac key: %w", err)
+	}
+
 	return &AESCTREncryption{
-		key: keyCopy,
+		key:    keyCopy,
+		macKey: macKey,
 	}, nil
 }
 
+// computeMAC 計算 IV + 密文的 HMAC-SHA256
+func (e *AESCTREncryption) computeMAC(data []byte) []byte {
+	mac := hmac.New(sha256.New, e.macKey)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
 // Encrypt 加密數據
-// 格式: "aes256ctr:" + base64(IV + ciphertext)
+// 格式: "aes256ctrv2:" + base64(IV + ciphertext + HMAC)
 func (e *AESCTREncryption) Encrypt(plaintext string) (string, error) {
 	if plaintext == "" {
 		return "", fmt.Errorf("plaintext cannot be empty")
 	}
 
-	// 創建 AES cipher block
-	block, err := aes.NewCipher(e.key)
-	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
-	}
-
 	// 將明文轉為字節
 	plaintextBytes := []byte(plaintext)
 
@@ -64,58 +91,45 @@ func (e *AESCTREncryption) Encrypt(plaintext string) (string, error) {
 		}
 	}()
 
-	// 創建密文緩衝區（與明文同樣大小）
-	ciphertext := make([]byte, len(plaintextBytes))
-
-	// 生成隨機 IV (Initialization Vector)
-	// CTR 模式 IV 長度等於 block size (16 bytes for AES)
-	iv := make([]byte, aes.BlockSize)
-	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
-		return "", fmt.Errorf("failed to generate IV: %w", err)
+	result, err := e.EncryptBytes(plaintextBytes)
+	if err != nil {
+		return "", err
 	}
 
-	// 創建 CTR 模式加密器
-	// #nosec G407 -- IV is dynamically generated from crypto/rand, not hardcoded
-	stream := cipher.NewCTR(block, iv)
-
-	// 加密數據
-	stream.XORKeyStream(ciphertext, plaintextBytes)
-
-	// 將 IV 和密文組合（IV 在前）
-	result := make([]byte, aes.BlockSize+len(ciphertext))
-	copy(result[:aes.BlockSize], iv)
-	copy(result[aes.BlockSize:], ciphertext)
-
 	// 使用完後清零臨時緩衝區（安全增強）
 	defer func() {
 		for i := range result {
 			result[i] = 0
 		}
-		for i := range ciphertext {
-			ciphertext[i] = 0
-		}
 	}()
 
 	// Base64 編碼以便存儲和傳輸
 	encoded := base64.StdEncoding.EncodeToString(result)
 
-	return aes256CTRPrefix + encoded, nil
+	return aes256CTRV2Prefix + encoded, nil
 }
 
 // Decrypt 解密數據
+// 同時支持新格式（aes256ctrv2:，驗證 HMAC）和舊格式（aes256ctr:，無驗證）
 func (e *AESCTREncryption) Decrypt(encryptedText string) (string, error) {
 	if encryptedText == "" {
 		return "", fmt.Errorf("encrypted text cannot be empty")
 	}
 
 	// 檢查格式前綴
-	prefix := aes256CTRPrefix
-	if len(encryptedText) < len(prefix) || encryptedText[:len(prefix)] != prefix {
-		return "", fmt.Errorf("invalid ciphertext format: missing '%s' prefix", prefix)
+	var encoded string
+	authenticated := false
+	switch {
+	case strings.HasPrefix(encryptedText, aes256CTRV2Prefix):
+		encoded = encryptedText[len(aes256CTRV2Prefix):]
+		authenticated = true
+	case strings.HasPrefix(encryptedText, aes256CTRPrefix):
+		encoded = encryptedText[len(aes256CTRPrefix):]
+	default:
+		return "", fmt.Errorf("invalid ciphertext format: missing '%s' prefix", aes256CTRV2Prefix)
 	}
 
-	// 移除前綴並 Base64 解碼
-	encoded := encryptedText[len(prefix):]
+	// Base64 解碼
 	data, err := base64.StdEncoding.DecodeString(encoded)
 	if err != nil {
 		return "", fmt.Errorf("failed to decode base64: %w", err)
@@ -128,87 +142,97 @@ func (e *AESCTREncryption) Decrypt(encryptedText string) (string, error) {
 		}
 	}()
 
-	// 檢查數據長度（至少要有 IV）
-	if len(data) < aes.BlockSize {
-		return "", fmt.Errorf("ciphertext too short: must be at least %d bytes", aes.BlockSize)
+	// 新格式：先驗證 HMAC 再解密
+	var plaintext []byte
+	if authenticated {
+		plaintext, err = e.DecryptBytes(data)
+	} else {
+		plaintext, err = e.decryptPayload(data)
 	}
-
-	// 創建 AES cipher block
-	block, err := aes.NewCipher(e.key)
 	if err != nil {
-		return "", fmt.Errorf("failed to create cipher: %w", err)
+		return "", err
 	}
 
-	// 提取 IV 和密文
-	iv := data[:aes.BlockSize]
-	ciphertext := data[aes.BlockSize:]
-
-	// 創建明文緩衝區
-	plaintext := make([]byte, len(ciphertext))
-
-	// 創建 CTR 模式解密器
-	// #nosec G407 -- IV is extracted from encrypted data, not hardcoded
-	stream := cipher.NewCTR(block, iv)
-
-	// 解密數據
-	stream.XORKeyStream(plaintext, ciphertext)
-
 	return string(plaintext), nil
 }
 
 // EncryptBytes 加密字節數據（用於文件等）
+// 與 Encrypt 使用相同的 Encrypt-then-MAC 格式，只是不加前綴、不做 Base64: IV + ciphertext + HMAC
 func (e *AESCTREncryption) EncryptBytes(plaintext []byte) ([]byte, error) {
 	if len(plaintext) == 0 {
 		return nil, fmt.Errorf("plaintext cannot be empty")
 	}
 
+	// 創建 AES cipher block
 	block, err := aes.NewCipher(e.key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cipher: %w", err)
 	}
 
-	ciphertext := make([]byte, len(plaintext))
-
+	// 生成隨機 IV (Initialization Vector)
+	// CTR 模式 IV 長度等於 block size (16 bytes for AES)
 	iv := make([]byte, aes.BlockSize)
 	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
 		return nil, fmt.Errorf("failed to generate IV: %w", err)
 	}
 
+	// 創建 CTR 模式加密器
 	// #nosec G407 -- IV is dynamically generated from crypto/rand, not hardcoded
 	stream := cipher.NewCTR(block, iv)
-	stream.XORKeyStream(ciphertext, plaintext)
 
-	result := make([]byte, aes.BlockSize+len(ciphertext))
+	// IV 在前，密文緊隨其後，再附加 HMAC 標籤
+	result := make([]byte, aes.BlockSize+len(plaintext), aes.BlockSize+len(plaintext)+macSize)
 	copy(result[:aes.BlockSize], iv)
-	copy(result[aes.BlockSize:], ciphertext)
+	stream.XORKeyStream(result[aes.BlockSize:], plaintext)
 
-	return result, nil
+	return append(result, e.computeMAC(result)...), nil
 }
 
-// DecryptBytes 解密字節數據
+// DecryptBytes 解密 EncryptBytes 產生的字節數據（先驗證 HMAC 再解密）
 func (e *AESCTREncryption) DecryptBytes(encryptedData []byte) ([]byte, error) {
-	if len(encryptedData) < aes.BlockSize {
-		return nil, fmt.Errorf("encrypted data too short")
+	if len(encryptedData) < aes.BlockSize+macSize {
+		return nil, fmt.Errorf("ciphertext too short: must be at least %d bytes", aes.BlockSize+macSize)
+	}
+
+	payload := encryptedData[:len(encryptedData)-macSize]
+	if !hmac.Equal(encryptedData[len(encryptedData)-macSize:], e.computeMAC(payload)) {
+		return nil, ErrTampered
+	}
+
+	return e.decryptPayload(payload)
+}
+
+// decryptPayload 解密 IV + 密文（不驗證完整性，調用方負責先驗證 HMAC 或明確處理舊格式）
+func (e *AESCTREncryption) decryptPayload(payload []byte) ([]byte, error) {
+	// 檢查數據長度（至少要有 IV）
+	if len(payload) < aes.BlockSize {
+		return nil, fmt.Errorf("ciphertext too short: must be at least %d bytes", aes.BlockSize)
 	}
 
+	// 創建 AES cipher block
 	block, err := aes.NewCipher(e.key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cipher: %w", err)
 	}
 
-	iv := encryptedData[:aes.BlockSize]
-	ciphertext := encryptedData[aes.BlockSize:]
+	// 提取 IV 和密文
+	iv := payload[:aes.BlockSize]
+	ciphertext := payload[aes.BlockSize:]
 
+	// 創建明文緩衝區
 	plaintext := make([]byte, len(ciphertext))
 
+	// 創建 CTR 模式解密器
 	// #nosec G407 -- IV is extracted from encrypted data, not hardcoded
 	stream := cipher.NewCTR(block, iv)
+
+	// 解密數據
 	stream.XORKeyStream(plaintext, ciphertext)
 
 	return plaintext, nil
 }
 
-// IsEncrypted 檢查文本是否已加密
+// IsEncrypted 檢查文本是否已加密（新舊格式皆可）
 func (e *AESCTREncryption) IsEncrypted(text string) bool {
-	return len(text) >= len(aes256CTRPrefix) && text[:len(aes256CTRPrefix)] == aes256CTRPrefix
+	return strings.HasPrefix(text, aes256CTRV2Prefix) || strings.HasPrefix(text, aes256CTRPrefix)
 }
@@ -2,7 +2,11 @@ package encryption
 
 import (
 	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -43,7 +47,7 @@ func TestAESCTREncryption(t *testing.T) {
 			}
 
 			// 驗證格式
-			if !strings.HasPrefix(ciphertext, "aes256ctr:") {
+			if !strings.HasPrefix(ciphertext, "aes256ctrv2:") {
 				t.Errorf("Invalid ciphertext format: missing prefix")
 			}
 
@@ -106,15 +110,65 @@ func TestAESCTREncryption_WrongKey(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// 用 key2 解密（錯誤的密鑰）
-	decrypted, err := enc2.Decrypt(ciphertext)
+	// 用 key2 解密（錯誤的密鑰）應該無法通過 HMAC 驗證
+	_, err = enc2.Decrypt(ciphertext)
+	if !errors.Is(err, ErrTampered) {
+		t.Errorf("Expected ErrTampered for wrong key, got: %v", err)
+	}
+}
+
+func TestAESCTREncryption_Tampered(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	enc, _ := NewAESCTREncryption(key)
+
+	ciphertext, err := enc.Encrypt("Secret message")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, "aes256ctrv2:"))
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// 解密應該得到亂碼（不應該等於原文）
-	if decrypted == plaintext {
-		t.Errorf("Wrong key should not decrypt to original plaintext")
+	// 分別翻轉 IV、密文和 MAC 中的一個位元
+	for _, idx := range []int{0, aes.BlockSize, len(data) - 1} {
+		tampered := make([]byte, len(data))
+		copy(tampered, data)
+		tampered[idx] ^= 0x01
+
+		_, err := enc.Decrypt("aes256ctrv2:" + base64.StdEncoding.EncodeToString(tampered))
+		if !errors.Is(err, ErrTampered) {
+			t.Errorf("Expected ErrTampered when flipping byte %d, got: %v", idx, err)
+		}
+	}
+}
+
+func TestAESCTREncryption_LegacyFormat(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	enc, _ := NewAESCTREncryption(key)
+
+	// 以舊格式（無 HMAC）構造密文
+	plaintext := "Legacy message"
+	iv := make([]byte, aes.BlockSize)
+	_, _ = rand.Read(iv)
+	block, _ := aes.NewCipher(key)
+	ct := make([]byte, len(plaintext))
+	cipher.NewCTR(block, iv).XORKeyStream(ct, []byte(plaintext))
+	legacy := "aes256ctr:" + base64.StdEncoding.EncodeToString(append(iv, ct...))
+
+	if !enc.IsEncrypted(legacy) {
+		t.Error("Should recognize legacy format as encrypted")
+	}
+
+	decrypted, err := enc.Decrypt(legacy)
+	if err != nil {
+		t.Fatalf("Legacy decryption failed: %v", err)
+	}
+	if decrypted != plaintext {
+		t.Errorf("Legacy decryption mismatch: got %q, want %q", decrypted, plaintext)
 	}
 }
 
@@ -147,6 +201,8 @@ func TestAESCTREncryption_InvalidFormat(t *testing.T) {
 		"aes256ctr:",           // 只有前綴
 		"aes256ctr:invalid!!!", // 無效 base64
 		"aes256ctr:AA==",       // base64 有效但數據太短
+		"aes256ctrv2:",         // 只有前綴
+		"aes256ctrv2:AA==",     // 缺少 MAC
 	}
 
 	for _, tc := range testCases {
@@ -205,6 +261,44 @@ func TestAESCTREncryption_Bytes(t *testing.T) {
 	if !bytes.Equal(decrypted, plaintext) {
 		t.Error("Byte encryption/decryption mismatch")
 	}
+
+	// 字節格式同樣帶 HMAC：IV + 密文 + 標籤
+	if len(ciphertext) != aes.BlockSize+len(plaintext)+macSize {
+		t.Errorf("Ciphertext length = %d, want %d", len(ciphertext), aes.BlockSize+len(plaintext)+macSize)
+	}
+}
+
+func TestAESCTREncryption_BytesTamperDetection(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	enc, _ := NewAESCTREncryption(key)
+
+	ciphertext, err := enc.EncryptBytes([]byte("file contents"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 翻轉 IV、密文和 HMAC 中的任意一個字節都必須被檢測到
+	for _, idx := range []int{0, aes.BlockSize, len(ciphertext) - 1} {
+		tampered := bytes.Clone(ciphertext)
+		tampered[idx] ^= 0x01
+		if _, err := enc.DecryptBytes(tampered); !errors.Is(err, ErrTampered) {
+			t.Errorf("Expected ErrTampered when flipping byte %d, got: %v", idx, err)
+		}
+	}
+
+	// 其他密鑰無法通過驗證
+	otherKey := make([]byte, 32)
+	_, _ = rand.Read(otherKey)
+	other, _ := NewAESCTREncryption(otherKey)
+	if _, err := other.DecryptBytes(ciphertext); !errors.Is(err, ErrTampered) {
+		t.Errorf("Expected ErrTampered for wrong key, got: %v", err)
+	}
+
+	// 截斷到不足 IV + HMAC 長度
+	if _, err := enc.DecryptBytes(ciphertext[:aes.BlockSize]); err == nil {
+		t.Error("Expected error for truncated ciphertext")
+	}
 }
 
 func TestAESCTREncryption_IsEncrypted(t *testing.T) {
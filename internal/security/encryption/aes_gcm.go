@@ -0,0 +1,135 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const aes256GCMPrefix = "aes256gcm:"
+
+// AESGCMEncryption AES-256-GCM 加密實現
+// GCM 模式特點：
+// - 認證加密（AEAD），自帶完整性校驗
+// - 12 bytes nonce，每次加密隨機生成
+// - 密文被竄改時解密直接失敗
+type AESGCMEncryption struct {
+	key []byte // 256-bit (32 bytes) key
+}
+
+// NewAESGCMEncryption 創建 AES-256-GCM 加密實例
+func NewAESGCMEncryption(key []byte) (*AESGCMEncryption, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid key size: must be 32 bytes (256 bits), got %d bytes", len(key))
+	}
+
+	// 複製密鑰以防止外部修改
+	keyCopy := make([]byte, len(key))
+	copy(keyCopy, key)
+
+	return &AESGCMEncryption{
+		key: keyCopy,
+	}, nil
+}
+
+// newGCM 創建 GCM 實例
+func (e *AESGCMEncryption) newGCM() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(e.key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aesGCM, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return aesGCM, nil
+}
+
+// Encrypt 加密數據
+// 格式: "aes256gcm:" + base64(nonce + ciphertext + tag)
+func (e *AESGCMEncryption) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", fmt.Errorf("plaintext cannot be empty")
+	}
+
+	result, err := e.EncryptBytes([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+
+	return aes256GCMPrefix + base64.StdEncoding.EncodeToString(result), nil
+}
+
+// Decrypt 解密數據
+func (e *AESGCMEncryption) Decrypt(encryptedText string) (string, error) {
+	if encryptedText == "" {
+		return "", fmt.Errorf("encrypted text cannot be empty")
+	}
+
+	if !strings.HasPrefix(encryptedText, aes256GCMPrefix) {
+		return "", fmt.Errorf("invalid ciphertext format: missing '%s' prefix", aes256GCMPrefix)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encryptedText[len(aes256GCMPrefix):])
+	if err != nil {
+		return "", fmt.Errorf("failed to decode base64: %w", err)
+	}
+
+	plaintext, err := e.DecryptBytes(data)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// EncryptBytes 加密字節數據（nonce + ciphertext + tag，無前綴）
+func (e *AESGCMEncryption) EncryptBytes(plaintext []byte) ([]byte, error) {
+	if len(plaintext) == 0 {
+		return nil, fmt.Errorf("plaintext cannot be empty")
+	}
+
+	aesGCM, err := e.newGCM()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	// Seal 會把密文追加到 nonce 後面
+	return aesGCM.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// DecryptBytes 解密字節數據
+func (e *AESGCMEncryption) DecryptBytes(encryptedData []byte) ([]byte, error) {
+	aesGCM, err := e.newGCM()
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aesGCM.NonceSize()
+	if len(encryptedData) < nonceSize+aesGCM.Overhead() {
+		return nil, fmt.Errorf("encrypted data too short")
+	}
+
+	plaintext, err := aesGCM.Open(nil, encryptedData[:nonceSize], encryptedData[nonceSize:], nil)
+	if err != nil {
+		return nil, ErrTampered
+	}
+
+	return plaintext, nil
+}
+
+// IsEncrypted 檢查文本是否已加密
+func (e *AESGCMEncryption) IsEncrypted(text string) bool {
+	return strings.HasPrefix(text, aes256GCMPrefix)
+}
@@ -0,0 +1,203 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAESGCMEncryption(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := NewAESGCMEncryption(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name      string
+		plaintext string
+	}{
+		{"Simple text", "Hello, World!"},
+		{"Unicode", "你好世界！🔐"},
+		{"Long text", strings.Repeat("This is a long message. ", 100)},
+		{"Special chars", "!@#$%^&*()_+-=[]{}|;':\",./<>?"},
+		{"Newlines", "Line 1\nLine 2\nLine 3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ciphertext, err := enc.Encrypt(tc.plaintext)
+			if err != nil {
+				t.Fatalf("Encryption failed: %v", err)
+			}
+
+			// 驗證格式
+			if !strings.HasPrefix(ciphertext, "aes256gcm:") {
+				t.Errorf("Invalid ciphertext format: missing prefix")
+			}
+
+			decrypted, err := enc.Decrypt(ciphertext)
+			if err != nil {
+				t.Fatalf("Decryption failed: %v", err)
+			}
+
+			if decrypted != tc.plaintext {
+				t.Errorf("Decryption mismatch.\nWant: %s\nGot: %s", tc.plaintext, decrypted)
+			}
+		})
+	}
+}
+
+func TestAESGCMEncryption_InvalidKey(t *testing.T) {
+	for _, size := range []int{0, 16, 24, 48} {
+		key := make([]byte, size)
+		if _, err := NewAESGCMEncryption(key); err == nil {
+			t.Errorf("Expected error for %d-byte key", size)
+		}
+	}
+}
+
+func TestAESGCMEncryption_WrongKey(t *testing.T) {
+	key1 := make([]byte, 32)
+	key2 := make([]byte, 32)
+	_, _ = rand.Read(key1)
+	_, _ = rand.Read(key2)
+
+	enc1, _ := NewAESGCMEncryption(key1)
+	enc2, _ := NewAESGCMEncryption(key2)
+
+	ciphertext, err := enc1.Encrypt("Secret message")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// GCM 認證失敗，錯誤的密鑰無法解密
+	_, err = enc2.Decrypt(ciphertext)
+	if !errors.Is(err, ErrTampered) {
+		t.Errorf("Expected ErrTampered for wrong key, got: %v", err)
+	}
+}
+
+func TestAESGCMEncryption_Tampered(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	enc, _ := NewAESGCMEncryption(key)
+
+	ciphertext, _ := enc.Encrypt("Secret message")
+	data, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, "aes256gcm:"))
+	data[len(data)/2] ^= 0x01
+
+	_, err := enc.Decrypt("aes256gcm:" + base64.StdEncoding.EncodeToString(data))
+	if !errors.Is(err, ErrTampered) {
+		t.Errorf("Expected ErrTampered, got: %v", err)
+	}
+}
+
+func TestAESGCMEncryption_InvalidFormat(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	enc, _ := NewAESGCMEncryption(key)
+
+	testCases := []string{
+		"",
+		"no_prefix",
+		"aes256ctr:AAAA",       // CTR 密文
+		"aes256gcm:",           // 只有前綴
+		"aes256gcm:invalid!!!", // 無效 base64
+		"aes256gcm:AA==",       // 數據太短
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			if _, err := enc.Decrypt(tc); err == nil {
+				t.Errorf("Expected error for invalid format: %s", tc)
+			}
+		})
+	}
+}
+
+func TestAESGCMEncryption_Bytes(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	enc, _ := NewAESGCMEncryption(key)
+
+	plaintext := []byte("Binary data: \x00\x01\x02\xff\xfe")
+
+	ciphertext, err := enc.EncryptBytes(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := enc.DecryptBytes(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Error("Byte encryption/decryption mismatch")
+	}
+}
+
+func TestAESGCMEncryption_IsEncrypted(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	enc, _ := NewAESGCMEncryption(key)
+
+	ciphertext, _ := enc.Encrypt("Test message")
+
+	if !enc.IsEncrypted(ciphertext) {
+		t.Error("Should recognize encrypted text")
+	}
+
+	if enc.IsEncrypted("Test message") {
+		t.Error("Should not recognize plaintext as encrypted")
+	}
+}
+
+// TestCipherSelection_LegacyCTR 切換到 GCM 後舊的 CTR 密文仍可解密
+func TestCipherSelection_LegacyCTR(t *testing.T) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+
+	ctr, _ := NewAESCTREncryption(key)
+	ctrCiphertext, _ := ctr.Encrypt("Old CTR message")
+
+	gcm, _ := newCipher(AlgorithmAES256GCM, key)
+	gcmCiphertext, _ := gcm.Encrypt("New GCM message")
+
+	for ciphertext, want := range map[string]string{
+		ctrCiphertext: "Old CTR message",
+		gcmCiphertext: "New GCM message",
+	} {
+		c, err := newCipher(algorithmForCiphertext(ciphertext), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := c.Decrypt(ciphertext)
+		if err != nil {
+			t.Fatalf("Decryption failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Decryption mismatch: got %q, want %q", got, want)
+		}
+	}
+}
+
+func BenchmarkAESGCMEncryption_Encrypt(b *testing.B) {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	enc, _ := NewAESGCMEncryption(key)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = enc.Encrypt(benchmarkTestMessage)
+	}
+}
@@ -0,0 +1,69 @@
+package encryption
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// cipherCacheKey 加密器緩存鍵（同一聊天室可能同時存在 CTR / GCM 密文及多個密鑰版本）
+type cipherCacheKey struct {
+	roomID    string
+	algorithm string
+	version   int // 0 表示當前活躍密鑰
+}
+
+// cachedCipher 緩存的加密器及其密鑰版本
+type cachedCipher struct {
+	cipher  messageCipher
+	version int
+}
+
+// cipherCache 每個聊天室的加密器緩存
+// 讀取路徑無鎖（sync.Map），避免每條消息都經過 KeyManager 的 RLock/Lock
+// 密鑰輪換時通過 invalidateRoom 失效
+type cipherCache struct {
+	ciphers sync.Map // cipherCacheKey -> cachedCipher
+
+	mu  sync.Mutex    // 串行化寫入與失效
+	gen atomic.Uint64 // 失效世代，防止輪換期間寫回舊密鑰的加密器
+}
+
+// get 讀取緩存的加密器
+func (c *cipherCache) get(key cipherCacheKey) (cachedCipher, bool) {
+	v, ok := c.ciphers.Load(key)
+	if !ok {
+		return cachedCipher{}, false
+	}
+	return v.(cachedCipher), true
+}
+
+// generation 當前失效世代（在獲取密鑰之前讀取）
+func (c *cipherCache) generation() uint64 {
+	return c.gen.Load()
+}
+
+// store 寫入加密器
+// 如果獲取密鑰後發生過失效，則不寫入（下一次會重新獲取新密鑰）
+func (c *cipherCache) store(key cipherCacheKey, ci cachedCipher, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.gen.Load() != gen {
+		return
+	}
+	c.ciphers.Store(key, ci)
+}
+
+// invalidateRoom 失效指定聊天室的所有加密器
+func (c *cipherCache) invalidateRoom(roomID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen.Add(1)
+	c.ciphers.Range(func(k, _ interface{}) bool {
+		if k.(cipherCacheKey).roomID == roomID {
+			c.ciphers.Delete(k)
+		}
+		return true
+	})
+}
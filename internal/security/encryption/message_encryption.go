@@ -3,65 +3,177 @@ package encryption
 import (
 	"fmt"
 	"log"
+	"strings"
 
+	"chat-gateway/internal/platform/config"
 	"chat-gateway/internal/security/keymanager"
 )
 
+// 支持的消息加密算法（對應 security.encryption.algorithm 配置）
+const (
+	AlgorithmAES256CTR = "AES-256-CTR"
+	AlgorithmAES256GCM = "AES-256-GCM"
+)
+
+// messageCipher 消息加密器（AES-CTR / AES-GCM 共用）
+type messageCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(encryptedText string) (string, error)
+}
+
+// roomKeyProvider 聊天室密鑰來源（由 KeyManagerWithPersistence 實現）
+type roomKeyProvider interface {
+	GetOrCreateRoomKeyWithVersion(roomID string) ([]byte, int, error)
+	GetRoomKeyByVersion(roomID string, version int) ([]byte, error)
+	GetKeyInfo(roomID string) (*keymanager.KeyInfo, error)
+}
+
 // MessageEncryption 消息加密服務
-// 使用 AES-256-CTR 加密模式 + 密鑰管理器
+// 使用 AES-256-CTR 或 AES-256-GCM 加密模式 + 密鑰管理器
 type MessageEncryption struct {
 	enabled    bool
-	keyManager *keymanager.KeyManagerWithPersistence
+	algorithm  string
+	keyManager roomKeyProvider
+	ciphers    cipherCache // 每個聊天室的加密器緩存（密鑰輪換時失效）
 }
 
 // NewMessageEncryption 創建消息加密服務
+// 加密算法從 security.encryption.algorithm 讀取，未配置時使用 AES-256-CTR
 func NewMessageEncryption(enabled bool, km *keymanager.KeyManagerWithPersistence) *MessageEncryption {
 	if km == nil {
 		log.Println("[WARNING] KeyManager is nil. Encryption will be disabled.")
 		enabled = false
 	}
 
-	return &MessageEncryption{
-		enabled:    enabled,
-		keyManager: km,
+	algorithm := AlgorithmAES256CTR
+	if cfg := config.Get(); cfg != nil && cfg.Security.Encryption.Algorithm != "" {
+		algorithm = cfg.Security.Encryption.Algorithm
+	}
+
+	switch algorithm {
+	case AlgorithmAES256CTR, AlgorithmAES256GCM:
+	default:
+		log.Printf("[WARNING] Unsupported encryption algorithm %q, falling back to %s", algorithm, AlgorithmAES256CTR)
+		algorithm = AlgorithmAES256CTR
+	}
+
+	m := &MessageEncryption{
+		enabled:   enabled,
+		algorithm: algorithm,
+	}
+
+	if km != nil {
+		m.keyManager = km
+		// 密鑰輪換後失效該聊天室的加密器緩存
+		km.OnKeyRotated(m.ciphers.invalidateRoom)
+	}
+
+	return m
+}
+
+// roomCipher 獲取聊天室的加密器（優先使用緩存）
+// version <= 0 表示使用當前活躍密鑰，返回值包含實際使用的密鑰版本
+func (m *MessageEncryption) roomCipher(roomID, algorithm string, version int) (messageCipher, int, error) {
+	if version < 0 {
+		version = 0
+	}
+
+	cacheKey := cipherCacheKey{roomID: roomID, algorithm: algorithm, version: version}
+	if c, ok := m.ciphers.get(cacheKey); ok {
+		return c.cipher, c.version, nil
+	}
+
+	gen := m.ciphers.generation()
+
+	var (
+		key        []byte
+		keyVersion = version
+		err        error
+	)
+	if version == 0 {
+		// 獲取或創建聊天室當前密鑰
+		key, keyVersion, err = m.keyManager.GetOrCreateRoomKeyWithVersion(roomID)
+	} else {
+		// 獲取指定版本的歷史密鑰
+		key, err = m.keyManager.GetRoomKeyByVersion(roomID, version)
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get room key: %w", err)
+	}
+
+	c, err := newCipher(algorithm, key)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	m.ciphers.store(cacheKey, cachedCipher{cipher: c, version: keyVersion}, gen)
+	return c, keyVersion, nil
+}
+
+// InvalidateRoom 失效聊天室的加密器緩存（例如聊天室密鑰被刪除後）
+func (m *MessageEncryption) InvalidateRoom(roomID string) {
+	m.ciphers.invalidateRoom(roomID)
+}
+
+// newCipher 根據算法創建加密器
+func newCipher(algorithm string, key []byte) (messageCipher, error) {
+	if algorithm == AlgorithmAES256GCM {
+		return NewAESGCMEncryption(key)
+	}
+	return NewAESCTREncryption(key)
+}
+
+// algorithmForCiphertext 根據密文前綴判斷解密算法
+// 切換算法後舊的 aes256ctr 密文仍可解密
+func algorithmForCiphertext(content string) string {
+	if strings.HasPrefix(content, aes256GCMPrefix) {
+		return AlgorithmAES256GCM
 	}
+	return AlgorithmAES256CTR
 }
 
 // EncryptMessage 加密消息
-// 使用 AES-256-CTR 加密模式
+// 使用配置的加密算法（AES-256-CTR 或 AES-256-GCM）
 func (m *MessageEncryption) EncryptMessage(content, roomID string) (string, error) {
+	encrypted, _, err := m.EncryptMessageWithVersion(content, roomID)
+	return encrypted, err
+}
+
+// EncryptMessageWithVersion 加密消息，同時返回使用的密鑰版本
+// 未啟用加密時版本為 0
+func (m *MessageEncryption) EncryptMessageWithVersion(content, roomID string) (string, int, error) {
 	if !m.enabled {
 		log.Println("[WARNING] Message encryption is DISABLED. Messages are stored in PLAIN TEXT!")
-		return plaintextPrefix + content, nil
+		return plaintextPrefix + content, 0, nil
 	}
 
 	if m.keyManager == nil {
-		return "", fmt.Errorf("key manager not initialized")
-	}
-
-	// 獲取或創建聊天室密鑰
-	key, err := m.keyManager.GetOrCreateRoomKey(roomID)
-	if err != nil {
-		return "", fmt.Errorf("failed to get room key: %w", err)
+		return "", 0, fmt.Errorf("key manager not initialized")
 	}
 
-	// 創建 AES-256-CTR 加密器
-	aesCTR, err := NewAESCTREncryption(key)
+	// 獲取聊天室加密器（當前活躍密鑰）
+	c, version, err := m.roomCipher(roomID, m.algorithm, 0)
 	if err != nil {
-		return "", fmt.Errorf("failed to create encryptor: %w", err)
+		return "", 0, err
 	}
 
 	// 加密訊息
-	encrypted, err := aesCTR.Encrypt(content)
+	encrypted, err := c.Encrypt(content)
 	if err != nil {
-		return "", fmt.Errorf("encryption failed: %w", err)
+		return "", 0, fmt.Errorf("encryption failed: %w", err)
 	}
 
-	return encrypted, nil
+	return encrypted, version, nil
 }
 
-// DecryptMessage 解密消息
+// DecryptMessage 解密消息（使用當前活躍密鑰）
 func (m *MessageEncryption) DecryptMessage(encryptedContent, roomID string) (string, error) {
+	return m.DecryptMessageWithVersion(encryptedContent, roomID, 0)
+}
+
+// DecryptMessageWithVersion 使用指定版本的密鑰解密消息
+// version <= 0 表示未記錄版本，使用當前活躍密鑰
+func (m *MessageEncryption) DecryptMessageWithVersion(encryptedContent, roomID string, version int) (string, error) {
 	if !m.enabled {
 		// 檢查是否有 plaintext 前綴
 		if len(encryptedContent) > len(plaintextPrefix) && encryptedContent[:len(plaintextPrefix)] == plaintextPrefix {
@@ -87,20 +199,14 @@ func (m *MessageEncryption) DecryptMessage(encryptedContent, roomID string) (str
 		}
 	}
 
-	// 獲取聊天室密鑰
-	key, err := m.keyManager.GetOrCreateRoomKey(roomID)
+	// 根據密文格式和密鑰版本獲取解密器
+	c, _, err := m.roomCipher(roomID, algorithmForCiphertext(encryptedContent), version)
 	if err != nil {
-		return "", fmt.Errorf("failed to get room key: %w", err)
-	}
-
-	// 創建 AES-256-CTR 解密器
-	aesCTR, err := NewAESCTREncryption(key)
-	if err != nil {
-		return "", fmt.Errorf("failed to create decryptor: %w", err)
+		return "", err
 	}
 
 	// 解密訊息
-	decrypted, err := aesCTR.Decrypt(encryptedContent)
+	decrypted, err := c.Decrypt(encryptedContent)
 	if err != nil {
 		return "", fmt.Errorf("decryption failed: %w", err)
 	}
@@ -108,18 +214,39 @@ func (m *MessageEncryption) DecryptMessage(encryptedContent, roomID string) (str
 	return decrypted, nil
 }
 
+// CurrentKeyVersion 獲取聊天室當前活躍密鑰的版本
+func (m *MessageEncryption) CurrentKeyVersion(roomID string) (int, error) {
+	if !m.enabled || m.keyManager == nil {
+		return 0, nil
+	}
+
+	_, version, err := m.roomCipher(roomID, m.algorithm, 0)
+	return version, err
+}
+
+// Enabled 是否啟用消息加密
+func (m *MessageEncryption) Enabled() bool {
+	return m.enabled
+}
+
 // IsEncrypted 檢查消息是否已加密
 func (m *MessageEncryption) IsEncrypted(content string) bool {
 	if len(content) < 10 {
 		return false
 	}
 
-	prefix := content[:10]
-	// 支持 AES-256-CTR 格式
-	if prefix == "aes256ctr:" {
+	// 支持 AES-256-CTR 格式（含 HMAC 的 v2 與舊格式）
+	if strings.HasPrefix(content, aes256CTRV2Prefix) || strings.HasPrefix(content, aes256CTRPrefix) {
 		return true
 	}
 
+	// 支持 AES-256-GCM 格式
+	if strings.HasPrefix(content, aes256GCMPrefix) {
+		return true
+	}
+
+	prefix := content[:10]
+
 	// 舊格式
 	if prefix == encryptedPrefix || prefix == plaintextPrefix {
 		return false
@@ -0,0 +1,235 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"chat-gateway/internal/security/keymanager"
+	"chat-gateway/internal/storage/database/chatroom"
+)
+
+// fakeKeyProvider 記錄密鑰獲取次數的測試用密鑰來源（支持多版本）
+type fakeKeyProvider struct {
+	mu      sync.RWMutex
+	keys    map[string][][]byte // roomID -> 各版本密鑰（索引 0 為版本 1）
+	fetches atomic.Int64
+}
+
+func newFakeKeyProvider() *fakeKeyProvider {
+	return &fakeKeyProvider{keys: make(map[string][][]byte)}
+}
+
+func (p *fakeKeyProvider) GetOrCreateRoomKeyWithVersion(roomID string) ([]byte, int, error) {
+	p.fetches.Add(1)
+
+	p.mu.RLock()
+	versions, ok := p.keys[roomID]
+	p.mu.RUnlock()
+	if ok {
+		return versions[len(versions)-1], len(versions), nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, 0, err
+	}
+	p.keys[roomID] = [][]byte{key}
+	return key, 1, nil
+}
+
+func (p *fakeKeyProvider) GetRoomKeyByVersion(roomID string, version int) ([]byte, error) {
+	p.fetches.Add(1)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	versions := p.keys[roomID]
+	if version < 1 || version > len(versions) {
+		return nil, fmt.Errorf("key version %d not found", version)
+	}
+	return versions[version-1], nil
+}
+
+func (p *fakeKeyProvider) GetKeyInfo(roomID string) (*keymanager.KeyInfo, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+// rotate 模擬密鑰輪換
+func (p *fakeKeyProvider) rotate(roomID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	p.keys[roomID] = append(p.keys[roomID], key)
+}
+
+func newTestMessageEncryption(algorithm string) (*MessageEncryption, *fakeKeyProvider) {
+	provider := newFakeKeyProvider()
+	return &MessageEncryption{
+		enabled:    true,
+		algorithm:  algorithm,
+		keyManager: provider,
+	}, provider
+}
+
+func TestMessageEncryption_CipherCache(t *testing.T) {
+	m, provider := newTestMessageEncryption(AlgorithmAES256CTR)
+	roomID := "room-1"
+
+	encrypted, err := m.EncryptMessage("hello", roomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		decrypted, err := m.DecryptMessage(encrypted, roomID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decrypted != "hello" {
+			t.Fatalf("Decryption mismatch: got %q", decrypted)
+		}
+	}
+
+	// 加密和解密共用同一個緩存的加密器，只獲取一次密鑰
+	if got := provider.fetches.Load(); got != 1 {
+		t.Errorf("Expected 1 key fetch, got %d", got)
+	}
+}
+
+func TestMessageEncryption_CipherCacheInvalidatedOnRotation(t *testing.T) {
+	m, provider := newTestMessageEncryption(AlgorithmAES256CTR)
+	roomID := "room-1"
+
+	if _, err := m.EncryptMessage("before", roomID); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.rotate(roomID)
+	m.ciphers.invalidateRoom(roomID)
+
+	encrypted, err := m.EncryptMessage("after", roomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := provider.fetches.Load(); got != 2 {
+		t.Errorf("Expected key to be re-fetched after rotation, got %d fetches", got)
+	}
+
+	// 新密文必須用新密鑰加密
+	key, _, _ := provider.GetOrCreateRoomKeyWithVersion(roomID)
+	enc, _ := NewAESCTREncryption(key)
+	if decrypted, err := enc.Decrypt(encrypted); err != nil || decrypted != "after" {
+		t.Errorf("Message should be encrypted with rotated key: %q, %v", decrypted, err)
+	}
+}
+
+func TestMessageEncryption_DecryptWithHistoricalKeyVersion(t *testing.T) {
+	m, provider := newTestMessageEncryption(AlgorithmAES256CTR)
+	roomID := "room-1"
+
+	oldEncrypted, oldVersion, err := m.EncryptMessageWithVersion("old message", roomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldVersion != 1 {
+		t.Fatalf("Expected key version 1, got %d", oldVersion)
+	}
+
+	provider.rotate(roomID)
+	m.ciphers.invalidateRoom(roomID)
+
+	newEncrypted, newVersion, err := m.EncryptMessageWithVersion("new message", roomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newVersion != 2 {
+		t.Fatalf("Expected key version 2 after rotation, got %d", newVersion)
+	}
+
+	// 使用記錄的版本解密舊訊息
+	if got, err := m.DecryptMessageWithVersion(oldEncrypted, roomID, oldVersion); err != nil || got != "old message" {
+		t.Errorf("Old message should decrypt with its key version: %q, %v", got, err)
+	}
+
+	// 使用當前密鑰解密舊訊息會失敗（HMAC 驗證不通過）
+	if _, err := m.DecryptMessage(oldEncrypted, roomID); err == nil {
+		t.Error("Old message should not decrypt with the rotated key")
+	}
+
+	if got, err := m.DecryptMessageWithVersion(newEncrypted, roomID, newVersion); err != nil || got != "new message" {
+		t.Errorf("New message should decrypt with current key: %q, %v", got, err)
+	}
+}
+
+func TestMessageEncryption_LegacyPreviewAfterRotation(t *testing.T) {
+	m, provider := newTestMessageEncryption(AlgorithmAES256CTR)
+	roomID := "room-1"
+
+	// 記錄密鑰版本之前寫入的預覽：沒有 last_message_key_id
+	preview, err := m.EncryptMessage("legacy preview", roomID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	room := &chatroom.ChatRoom{LastMessage: preview}
+
+	provider.rotate(roomID)
+	m.ciphers.invalidateRoom(roomID)
+
+	got, err := m.DecryptMessageWithVersion(room.LastMessage, roomID, room.LastMessageKeyVersion())
+	if err != nil || got != "legacy preview" {
+		t.Errorf("Legacy preview should decrypt with the first key version after rotation: %q, %v", got, err)
+	}
+}
+
+// BenchmarkMessageEncryption_StreamDecrypt 模擬繁忙聊天室的流式解密
+// keyfetch/op 表示每條消息需要經過密鑰管理器（及其鎖）的次數
+func BenchmarkMessageEncryption_StreamDecrypt(b *testing.B) {
+	const roomID = "busy-room"
+	const streamSize = 1000
+
+	m, provider := newTestMessageEncryption(AlgorithmAES256CTR)
+	messages := make([]string, streamSize)
+	for i := range messages {
+		encrypted, err := m.EncryptMessage(fmt.Sprintf("message %d", i), roomID)
+		if err != nil {
+			b.Fatal(err)
+		}
+		messages[i] = encrypted
+	}
+
+	b.Run("cached", func(b *testing.B) {
+		provider.fetches.Store(0)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := m.DecryptMessage(messages[i%streamSize], roomID); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(provider.fetches.Load())/float64(b.N), "keyfetch/op")
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		provider.fetches.Store(0)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			// 舊路徑：每條消息都重新獲取密鑰並創建解密器
+			key, _, err := provider.GetOrCreateRoomKeyWithVersion(roomID)
+			if err != nil {
+				b.Fatal(err)
+			}
+			c, err := newCipher(AlgorithmAES256CTR, key)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := c.Decrypt(messages[i%streamSize]); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(provider.fetches.Load())/float64(b.N), "keyfetch/op")
+	})
+}
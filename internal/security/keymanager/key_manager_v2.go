@@ -1,19 +1,25 @@
 package keymanager
 
 import (
+	"bytes"
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/mongo"
 )
 
+// ErrKeyRevoked 所需的密鑰版本已被撤銷（且配置禁止用已撤銷的密鑰解密）
+var ErrKeyRevoked = errors.New("key has been revoked")
+
 // KeyManagerWithPersistence 帶持久化的密鑰管理器
 type KeyManagerWithPersistence struct {
 	mu             sync.RWMutex
@@ -24,6 +30,17 @@ type KeyManagerWithPersistence struct {
 	rotationPolicy RotationPolicy
 	stopChan       chan struct{}
 	running        bool
+
+	revokedKeyDecryption bool // 是否允許用已撤銷的密鑰解密舊訊息
+
+	// generation 每次輪換、撤銷或刪除密鑰時遞增，用於判斷鎖外加載的結果是否已過期
+	generation uint64
+
+	cacheHits   atomic.Int64 // 當前密鑰緩存命中次數
+	cacheMisses atomic.Int64 // 當前密鑰緩存未命中次數（需要查詢數據庫）
+
+	listenersMu       sync.RWMutex
+	rotationListeners []func(roomID string) // 密鑰輪換後的回調（用於失效外部緩存）
 }
 
 // NewKeyManagerWithPersistence 創建帶持久化的密鑰管理器
@@ -47,6 +64,7 @@ func NewKeyManagerWithPersistence(masterKey []byte, db *mongo.Database) (*KeyMan
 			MaxKeyAge:        30 * 24 * time.Hour,
 			KeepOldKeys:      5,
 		},
+		revokedKeyDecryption: true,
 	}
 
 	// 啟動時清理過期密鑰
@@ -63,58 +81,225 @@ func NewKeyManagerWithPersistence(masterKey []byte, db *mongo.Database) (*KeyMan
 // GetOrCreateRoomKey 獲取或創建聊天室密鑰（帶 DB 持久化）
 // 使用 Double-Check Locking 防止並發創建
 func (km *KeyManagerWithPersistence) GetOrCreateRoomKey(roomID string) ([]byte, error) {
+	key, _, err := km.GetOrCreateRoomKeyWithVersion(roomID)
+	return key, err
+}
+
+// GetOrCreateRoomKeyWithVersion 獲取或創建聊天室密鑰，同時返回密鑰版本
+func (km *KeyManagerWithPersistence) GetOrCreateRoomKeyWithVersion(roomID string) ([]byte, int, error) {
 	if roomID == "" {
-		return nil, fmt.Errorf("roomID cannot be empty")
+		return nil, 0, fmt.Errorf("roomID cannot be empty")
 	}
 
 	// 第一次檢查：使用讀鎖（快速路徑）
 	km.mu.RLock()
 	key, exists := km.keys[roomID]
+	generation := km.generation
 	km.mu.RUnlock()
 
 	if exists && key.Status == KeyStatusActive {
-		return key.Value, nil
+		km.cacheHits.Add(1)
+		return key.Value, key.Version, nil
+	}
+	km.cacheMisses.Add(1)
+
+	// 在鎖外從數據庫加載並解密，避免查詢數據庫時阻塞其他聊天室
+	ctx := context.Background()
+	loaded, err := km.loadActiveKey(ctx, roomID)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// 獲取寫鎖以進行創建或加載（慢速路徑）
+	// 獲取寫鎖以寫入緩存或創建密鑰（慢速路徑）
 	km.mu.Lock()
 	defer km.mu.Unlock()
 
-	// 第二次檢查：其他協程可能已經創建了密鑰
+	// 第二次檢查：其他協程可能已經加載或創建了密鑰
 	if key, exists := km.keys[roomID]; exists && key.Status == KeyStatusActive {
-		return key.Value, nil
+		return key.Value, key.Version, nil
 	}
 
-	// 從數據庫加載（在鎖內執行，確保只有一個協程執行）
-	ctx := context.Background()
+	// 加載期間密鑰被輪換、撤銷或刪除，鎖外的加載結果可能已過期，在鎖內重新加載
+	if km.generation != generation {
+		if loaded, err = km.loadActiveKey(ctx, roomID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if loaded != nil {
+		km.keys[roomID] = loaded
+		return loaded.Value, loaded.Version, nil
+	}
+
+	// 密鑰不存在，創建新密鑰（已持有寫鎖，安全）
+	roomKey, err := km.createRoomKeyUnsafe(roomID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return roomKey, km.keys[roomID].Version, nil
+}
+
+// loadActiveKey 從數據庫加載並解密聊天室的活躍密鑰，沒有活躍密鑰時返回 nil
+// 不訪問緩存，調用者不需要持有 km.mu
+func (km *KeyManagerWithPersistence) loadActiveKey(ctx context.Context, roomID string) (*Key, error) {
 	keyDoc, err := km.store.GetActiveKey(ctx, roomID)
 	if err != nil {
 		return nil, fmt.Errorf("key loading error")
 	}
+	if keyDoc == nil {
+		return nil, nil
+	}
 
-	if keyDoc != nil {
-		// 解密密鑰
-		roomKey, err := km.decryptRoomKey(keyDoc.EncryptedKey)
+	roomKey, err := km.decryptRoomKey(keyDoc.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("key decryption error")
+	}
+
+	return &Key{
+		ID:        roomID,
+		Value:     roomKey,
+		CreatedAt: keyDoc.CreatedAt,
+		RotatedAt: keyDoc.RotatedAt,
+		Version:   keyDoc.KeyVersion,
+		Status:    KeyStatusActive,
+	}, nil
+}
+
+// WarmUp 預加載聊天室的活躍密鑰到緩存（啟動時使用），返回新加載的密鑰數量
+// 已緩存或沒有密鑰的聊天室會被跳過；單個聊天室加載失敗不影響其他聊天室，返回第一個錯誤
+func (km *KeyManagerWithPersistence) WarmUp(ctx context.Context, roomIDs []string) (int, error) {
+	loaded := 0
+	var firstErr error
+
+	for _, roomID := range roomIDs {
+		if err := ctx.Err(); err != nil {
+			return loaded, err
+		}
+
+		km.mu.RLock()
+		_, cached := km.keys[roomID]
+		generation := km.generation
+		km.mu.RUnlock()
+		if cached {
+			continue
+		}
+
+		key, err := km.loadActiveKey(ctx, roomID)
 		if err != nil {
-			return nil, fmt.Errorf("key decryption error")
+			if firstErr == nil {
+				firstErr = fmt.Errorf("room %s: %w", roomID, err)
+			}
+			continue
+		}
+		if key == nil {
+			continue
 		}
 
-		// 加載到緩存
-		key := &Key{
-			ID:        roomID,
-			Value:     roomKey,
-			CreatedAt: keyDoc.CreatedAt,
-			RotatedAt: keyDoc.RotatedAt,
-			Version:   keyDoc.KeyVersion,
-			Status:    KeyStatusActive,
+		km.mu.Lock()
+		if _, exists := km.keys[roomID]; !exists && km.generation == generation {
+			km.keys[roomID] = key
+			loaded++
+		}
+		km.mu.Unlock()
+	}
+
+	return loaded, firstErr
+}
+
+// GetRoomKeyByVersion 獲取指定版本的聊天室密鑰（用於解密輪換前的舊訊息）
+// 先查緩存（當前密鑰及歸檔密鑰），未命中時從數據庫加載
+func (km *KeyManagerWithPersistence) GetRoomKeyByVersion(roomID string, version int) ([]byte, error) {
+	if roomID == "" {
+		return nil, fmt.Errorf("roomID cannot be empty")
+	}
+
+	km.mu.RLock()
+	if key := km.findKeyByVersionUnsafe(roomID, version); key != nil {
+		err := km.checkDecryptableUnsafe(key)
+		km.mu.RUnlock()
+		if err != nil {
+			return nil, err
+		}
+		return key.Value, nil
+	}
+	km.mu.RUnlock()
+
+	km.mu.Lock()
+	defer km.mu.Unlock()
+
+	if key := km.findKeyByVersionUnsafe(roomID, version); key != nil {
+		if err := km.checkDecryptableUnsafe(key); err != nil {
+			return nil, err
 		}
-		km.keys[roomID] = key
+		return key.Value, nil
+	}
 
-		return roomKey, nil
+	keyDoc, err := km.store.GetKeyByVersion(context.Background(), roomID, version)
+	if err != nil {
+		return nil, fmt.Errorf("key loading error")
+	}
+	if keyDoc == nil {
+		return nil, fmt.Errorf("key version %d not found for room %s", version, roomID)
 	}
 
-	// 密鑰不存在，創建新密鑰（已持有寫鎖，安全）
-	return km.createRoomKeyUnsafe(roomID)
+	status := keyDoc.keyStatus()
+	if status == KeyStatusRevoked && !km.revokedKeyDecryption {
+		return nil, revokedKeyError(roomID, version)
+	}
+
+	roomKey, err := km.decryptRoomKey(keyDoc.EncryptedKey)
+	if err != nil {
+		return nil, fmt.Errorf("key decryption error")
+	}
+
+	if status != KeyStatusRevoked {
+		status = KeyStatusArchived
+	}
+	key := &Key{
+		ID:        roomID,
+		Value:     roomKey,
+		CreatedAt: keyDoc.CreatedAt,
+		RotatedAt: keyDoc.RotatedAt,
+		Version:   keyDoc.KeyVersion,
+		Status:    status,
+	}
+
+	// 活躍密鑰由 GetOrCreateRoomKeyWithVersion 負責加載，這裡只緩存歷史密鑰
+	if !keyDoc.IsActive {
+		km.oldKeys[roomID] = append(km.oldKeys[roomID], key)
+		km.cleanupOldKeys(roomID)
+	}
+
+	return roomKey, nil
+}
+
+// findKeyByVersionUnsafe 在緩存中查找指定版本的密鑰
+// 調用者必須已經持有 km.mu 讀鎖或寫鎖
+func (km *KeyManagerWithPersistence) findKeyByVersionUnsafe(roomID string, version int) *Key {
+	if key, exists := km.keys[roomID]; exists && key.Version == version {
+		return key
+	}
+	for _, key := range km.oldKeys[roomID] {
+		if key.Version == version {
+			return key
+		}
+	}
+	return nil
+}
+
+// checkDecryptableUnsafe 檢查密鑰是否可用於解密
+// 已撤銷的密鑰默認仍可解密舊訊息，除非配置禁止
+// 調用者必須已經持有 km.mu 讀鎖或寫鎖
+func (km *KeyManagerWithPersistence) checkDecryptableUnsafe(key *Key) error {
+	if key.Status == KeyStatusRevoked && !km.revokedKeyDecryption {
+		return revokedKeyError(key.ID, key.Version)
+	}
+	return nil
+}
+
+// revokedKeyError 構造密鑰已撤銷的錯誤
+func revokedKeyError(roomID string, version int) error {
+	return fmt.Errorf("%w: room %s key version %d", ErrKeyRevoked, roomID, version)
 }
 
 // createRoomKeyUnsafe 創建新的聊天室密鑰（不加鎖版本）
@@ -142,13 +327,21 @@ func (km *KeyManagerWithPersistence) createRoomKeyUnsafe(roomID string) ([]byte,
 	keyValueForCache := make([]byte, 32)
 	copy(keyValueForCache, keyValue)
 
+	// 之前的密鑰可能已被撤銷，新密鑰使用下一個版本號，避免覆蓋舊密鑰
+	ctx := context.Background()
+	latestVersion, err := km.store.GetLatestVersion(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("key loading error")
+	}
+	version := latestVersion + 1
+
 	now := time.Now()
 	key := &Key{
 		ID:        roomID,
 		Value:     keyValueForCache, // 使用副本，不會被清零
 		CreatedAt: now,
 		RotatedAt: now,
-		Version:   1,
+		Version:   version,
 		Status:    KeyStatusActive,
 	}
 
@@ -161,15 +354,15 @@ func (km *KeyManagerWithPersistence) createRoomKeyUnsafe(roomID string) ([]byte,
 	// 保存到數據庫
 	keyDoc := &KeyDocument{
 		RoomID:       roomID,
-		KeyVersion:   1,
+		KeyVersion:   version,
 		EncryptedKey: encryptedKey,
 		CreatedAt:    now,
 		RotatedAt:    now,
 		IsActive:     true,
 		ExpiresAt:    now.Add(km.rotationPolicy.MaxKeyAge),
+		Status:       KeyStatusActive,
 	}
 
-	ctx := context.Background()
 	if err := km.store.SaveKey(ctx, keyDoc); err != nil {
 		return nil, fmt.Errorf("key persistence error")
 	}
@@ -207,13 +400,17 @@ func (km *KeyManagerWithPersistence) rotateKey(roomID string) error {
 		}
 	}()
 
+	// 為緩存創建獨立的副本（避免被 defer 清零）
+	newKeyValueForCache := make([]byte, len(newKeyValue))
+	copy(newKeyValueForCache, newKeyValue)
+
 	now := time.Now()
 	newVersion := oldKey.Version + 1
 
 	// 創建新密鑰
 	newKey := &Key{
 		ID:        roomID,
-		Value:     newKeyValue,
+		Value:     newKeyValueForCache, // 使用副本，不會被清零
 		CreatedAt: oldKey.CreatedAt,
 		RotatedAt: now,
 		Version:   newVersion,
@@ -235,6 +432,7 @@ func (km *KeyManagerWithPersistence) rotateKey(roomID string) error {
 		RotatedAt:    now,
 		IsActive:     true,
 		ExpiresAt:    now.Add(km.rotationPolicy.MaxKeyAge),
+		Status:       KeyStatusActive,
 	}
 
 	ctx := context.Background()
@@ -242,12 +440,7 @@ func (km *KeyManagerWithPersistence) rotateKey(roomID string) error {
 		return fmt.Errorf("key persistence error")
 	}
 
-	// 清零舊密鑰值（安全增強）
-	for i := range oldKey.Value {
-		oldKey.Value[i] = 0
-	}
-
-	// 歸檔舊密鑰
+	// 歸檔舊密鑰（保留密鑰值，用於解密輪換前的訊息）
 	oldKey.Status = KeyStatusArchived
 	if km.oldKeys[roomID] == nil {
 		km.oldKeys[roomID] = make([]*Key, 0)
@@ -259,13 +452,24 @@ func (km *KeyManagerWithPersistence) rotateKey(roomID string) error {
 
 	// 更新當前密鑰
 	km.keys[roomID] = newKey
+	km.generation++
 
 	return nil
 }
 
 // encryptRoomKey 用 Master Key 加密 Room Key
 func (km *KeyManagerWithPersistence) encryptRoomKey(roomKey []byte) (string, error) {
-	block, err := aes.NewCipher(km.masterKey)
+	return encryptWithMasterKey(km.masterKey, roomKey)
+}
+
+// decryptRoomKey 用 Master Key 解密 Room Key
+func (km *KeyManagerWithPersistence) decryptRoomKey(encryptedKey string) ([]byte, error) {
+	return decryptWithMasterKey(km.masterKey, encryptedKey)
+}
+
+// encryptWithMasterKey 用指定的 Master Key 加密 Room Key
+func encryptWithMasterKey(masterKey, roomKey []byte) (string, error) {
+	block, err := aes.NewCipher(masterKey)
 	if err != nil {
 		return "", fmt.Errorf("failed to create cipher: %w", err)
 	}
@@ -286,8 +490,8 @@ func (km *KeyManagerWithPersistence) encryptRoomKey(roomKey []byte) (string, err
 	return base64.StdEncoding.EncodeToString(ciphertext), nil
 }
 
-// decryptRoomKey 用 Master Key 解密 Room Key
-func (km *KeyManagerWithPersistence) decryptRoomKey(encryptedKey string) ([]byte, error) {
+// decryptWithMasterKey 用指定的 Master Key 解密 Room Key
+func decryptWithMasterKey(masterKey []byte, encryptedKey string) ([]byte, error) {
 	// Base64 解碼
 	ciphertext, err := base64.StdEncoding.DecodeString(encryptedKey)
 	if err != nil {
@@ -305,7 +509,7 @@ func (km *KeyManagerWithPersistence) decryptRoomKey(encryptedKey string) ([]byte
 		return nil, fmt.Errorf("invalid encrypted data")
 	}
 
-	block, err := aes.NewCipher(km.masterKey)
+	block, err := aes.NewCipher(masterKey)
 	if err != nil {
 		return nil, fmt.Errorf("decryption error")
 	}
@@ -354,8 +558,8 @@ func (km *KeyManagerWithPersistence) LoadAllKeys(ctx context.Context, roomID str
 			// 活躍密鑰
 			km.keys[roomID] = key
 		} else {
-			// 歷史密鑰
-			key.Status = KeyStatusArchived
+			// 歷史密鑰（包括已撤銷的密鑰）
+			key.Status = keyDoc.keyStatus()
 			if km.oldKeys[roomID] == nil {
 				km.oldKeys[roomID] = make([]*Key, 0)
 			}
@@ -422,6 +626,58 @@ func (km *KeyManagerWithPersistence) GetKeyInfo(roomID string) (*KeyInfo, error)
 	}, nil
 }
 
+// GetRoomKeyStatus 獲取聊天室密鑰狀態（內存緩存與數據庫），不返回密鑰值
+func (km *KeyManagerWithPersistence) GetRoomKeyStatus(ctx context.Context, roomID string) (*RoomKeyStatus, error) {
+	docs, err := km.store.GetAllKeys(ctx, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("key loading error")
+	}
+
+	status := &RoomKeyStatus{
+		ArchivedVersions: []int{},
+		StoredVersions:   make([]int, 0, len(docs)),
+	}
+	for _, doc := range docs {
+		status.StoredVersions = append(status.StoredVersions, doc.KeyVersion)
+	}
+
+	km.mu.RLock()
+	if key, exists := km.keys[roomID]; exists {
+		status.Cached = true
+		status.Current = &KeyInfo{
+			RoomID:    key.ID,
+			Version:   key.Version,
+			CreatedAt: key.CreatedAt,
+			RotatedAt: key.RotatedAt,
+			Status:    key.Status,
+			Age:       time.Since(key.CreatedAt),
+		}
+	}
+	for _, key := range km.oldKeys[roomID] {
+		status.ArchivedVersions = append(status.ArchivedVersions, key.Version)
+	}
+	km.mu.RUnlock()
+
+	// 未加載到內存時使用數據庫中的活躍密鑰
+	if status.Current == nil {
+		for _, doc := range docs {
+			if doc.IsActive {
+				status.Current = &KeyInfo{
+					RoomID:    doc.RoomID,
+					Version:   doc.KeyVersion,
+					CreatedAt: doc.CreatedAt,
+					RotatedAt: doc.RotatedAt,
+					Status:    KeyStatusActive,
+					Age:       time.Since(doc.CreatedAt),
+				}
+				break
+			}
+		}
+	}
+
+	return status, nil
+}
+
 // SetRotationPolicy 設置密鑰輪換策略
 func (km *KeyManagerWithPersistence) SetRotationPolicy(policy RotationPolicy) {
 	km.mu.Lock()
@@ -489,11 +745,36 @@ func (km *KeyManagerWithPersistence) checkAndRotateKeys() {
 		if err := km.rotateKey(roomID); err != nil {
 			fmt.Printf("Failed to rotate key for room %s: %v\n", roomID, err)
 		} else {
+			km.notifyKeyRotated(roomID)
 			fmt.Printf("Successfully rotated key for room %s\n", roomID)
 		}
 	}
 }
 
+// OnKeyRotated 註冊密鑰輪換回調
+// 回調在密鑰輪換完成後（已釋放鎖）調用，用於失效依賴該密鑰的緩存
+func (km *KeyManagerWithPersistence) OnKeyRotated(fn func(roomID string)) {
+	if fn == nil {
+		return
+	}
+
+	km.listenersMu.Lock()
+	defer km.listenersMu.Unlock()
+	km.rotationListeners = append(km.rotationListeners, fn)
+}
+
+// notifyKeyRotated 通知所有輪換回調
+func (km *KeyManagerWithPersistence) notifyKeyRotated(roomID string) {
+	km.listenersMu.RLock()
+	listeners := make([]func(string), len(km.rotationListeners))
+	copy(listeners, km.rotationListeners)
+	km.listenersMu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(roomID)
+	}
+}
+
 // ForceRotateKey 強制輪換指定聊天室的密鑰
 func (km *KeyManagerWithPersistence) ForceRotateKey(roomID string) error {
 	km.mu.RLock()
@@ -504,7 +785,135 @@ func (km *KeyManagerWithPersistence) ForceRotateKey(roomID string) error {
 		return fmt.Errorf("key not found for room %s", roomID)
 	}
 
-	return km.rotateKey(roomID)
+	if err := km.rotateKey(roomID); err != nil {
+		return err
+	}
+
+	km.notifyKeyRotated(roomID)
+	return nil
+}
+
+// RotateOrCreateRoomKey 手動輪換聊天室密鑰（例如懷疑密鑰洩露時）
+// 聊天室還沒有密鑰時創建新密鑰（created 為 true），不需要再輪換
+func (km *KeyManagerWithPersistence) RotateOrCreateRoomKey(ctx context.Context, roomID string) (info *KeyInfo, created bool, err error) {
+	km.mu.RLock()
+	_, existed := km.keys[roomID]
+	km.mu.RUnlock()
+
+	// 緩存未命中時檢查數據庫（服務重啟後密鑰可能尚未加載）
+	if !existed {
+		keyDoc, err := km.store.GetActiveKey(ctx, roomID)
+		if err != nil {
+			return nil, false, fmt.Errorf("key loading error")
+		}
+		existed = keyDoc != nil
+	}
+
+	// 加載當前密鑰到緩存，或創建新密鑰
+	if _, _, err := km.GetOrCreateRoomKeyWithVersion(roomID); err != nil {
+		return nil, false, err
+	}
+
+	if existed {
+		if err := km.ForceRotateKey(roomID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	info, err = km.GetKeyInfo(roomID)
+	if err != nil {
+		return nil, false, err
+	}
+	return info, !existed, nil
+}
+
+// DeleteRoomKeys 刪除聊天室的所有密鑰（內存緩存與持久化存儲）
+// ctx 可以是事務上下文，以便與聊天室刪除一起提交
+func (km *KeyManagerWithPersistence) DeleteRoomKeys(ctx context.Context, roomID string) (int64, error) {
+	km.mu.Lock()
+	delete(km.keys, roomID)
+	delete(km.oldKeys, roomID)
+	km.generation++
+	km.mu.Unlock()
+
+	return km.store.DeleteRoomKeys(ctx, roomID)
+}
+
+// RevokeKey 撤銷聊天室當前密鑰（例如懷疑密鑰洩露時），撤銷狀態持久化到數據庫
+// 已撤銷的密鑰不再用於加密，下次加密時會創建新版本的密鑰；
+// 默認仍可用於解密舊訊息，除非通過 SetRevokedKeyDecryption 禁止
+func (km *KeyManagerWithPersistence) RevokeKey(roomID string) error {
+	if roomID == "" {
+		return fmt.Errorf("roomID cannot be empty")
+	}
+
+	km.mu.Lock()
+	keyDoc, err := km.store.RevokeActiveKey(context.Background(), roomID, time.Now())
+	if err != nil {
+		km.mu.Unlock()
+		return fmt.Errorf("key persistence error")
+	}
+	if keyDoc == nil {
+		km.mu.Unlock()
+		return fmt.Errorf("key not found for room %s", roomID)
+	}
+
+	km.generation++
+
+	// 從當前密鑰移到歷史密鑰，保留密鑰值用於解密舊訊息
+	if key, exists := km.keys[roomID]; exists {
+		delete(km.keys, roomID)
+		if key.Version == keyDoc.KeyVersion {
+			key.Status = KeyStatusRevoked
+			km.oldKeys[roomID] = append(km.oldKeys[roomID], key)
+			km.cleanupOldKeys(roomID)
+		}
+	}
+	km.mu.Unlock()
+
+	// 失效依賴該密鑰的緩存（例如加密器緩存）
+	km.notifyKeyRotated(roomID)
+	return nil
+}
+
+// SetRevokedKeyDecryption 設置是否允許用已撤銷的密鑰解密舊訊息（默認允許）
+// 禁止時解密返回 ErrKeyRevoked
+func (km *KeyManagerWithPersistence) SetRevokedKeyDecryption(allowed bool) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	km.revokedKeyDecryption = allowed
+}
+
+// HealthCheck 檢查密鑰管理器是否可用：密鑰集合可訪問，且主密鑰可以加解密
+func (km *KeyManagerWithPersistence) HealthCheck(ctx context.Context) error {
+	if err := km.store.Ping(ctx); err != nil {
+		return err
+	}
+	return km.checkMasterKeyRoundTrip()
+}
+
+// checkMasterKeyRoundTrip 用主密鑰加密再解密一個臨時值，確認結果一致
+func (km *KeyManagerWithPersistence) checkMasterKeyRoundTrip() error {
+	probe := make([]byte, 32)
+	if _, err := rand.Read(probe); err != nil {
+		return fmt.Errorf("key generation error")
+	}
+	defer zeroBytes(probe)
+
+	encrypted, err := km.encryptRoomKey(probe)
+	if err != nil {
+		return fmt.Errorf("master key encryption failed: %w", err)
+	}
+	decrypted, err := km.decryptRoomKey(encrypted)
+	if err != nil {
+		return fmt.Errorf("master key decryption failed: %w", err)
+	}
+	defer zeroBytes(decrypted)
+
+	if !bytes.Equal(probe, decrypted) {
+		return fmt.Errorf("master key round trip mismatch")
+	}
+	return nil
 }
 
 // Stats 獲取統計信息
@@ -517,6 +926,8 @@ func (km *KeyManagerWithPersistence) Stats() KeyManagerStats {
 		ArchivedKeys: 0,
 		ActiveKeys:   0,
 		RevokedKeys:  0,
+		CacheHits:    km.cacheHits.Load(),
+		CacheMisses:  km.cacheMisses.Load(),
 	}
 
 	for _, key := range km.keys {
@@ -529,7 +940,13 @@ func (km *KeyManagerWithPersistence) Stats() KeyManagerStats {
 	}
 
 	for _, keyList := range km.oldKeys {
-		stats.ArchivedKeys += len(keyList)
+		for _, key := range keyList {
+			if key.Status == KeyStatusRevoked {
+				stats.RevokedKeys++
+			} else {
+				stats.ArchivedKeys++
+			}
+		}
 	}
 
 	return stats
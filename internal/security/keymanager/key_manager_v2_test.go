@@ -0,0 +1,123 @@
+package keymanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGetRoomKeyByVersion_RevokedKey(t *testing.T) {
+	roomID := "507f1f77bcf86cd799439011"
+	km := &KeyManagerWithPersistence{
+		keys: map[string]*Key{},
+		oldKeys: map[string][]*Key{
+			roomID: {{ID: roomID, Value: []byte("old-key"), Version: 1, Status: KeyStatusRevoked}},
+		},
+		revokedKeyDecryption: true,
+	}
+
+	key, err := km.GetRoomKeyByVersion(roomID, 1)
+	if err != nil {
+		t.Fatalf("revoked key should still decrypt by default: %v", err)
+	}
+	if string(key) != "old-key" {
+		t.Errorf("key = %q, want old-key", key)
+	}
+
+	km.SetRevokedKeyDecryption(false)
+	if _, err := km.GetRoomKeyByVersion(roomID, 1); !errors.Is(err, ErrKeyRevoked) {
+		t.Errorf("expected ErrKeyRevoked, got %v", err)
+	}
+}
+
+func TestKeyDocumentStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  KeyDocument
+		want KeyStatus
+	}{
+		{"legacy active", KeyDocument{IsActive: true}, KeyStatusActive},
+		{"legacy inactive", KeyDocument{IsActive: false}, KeyStatusArchived},
+		{"archived", KeyDocument{Status: KeyStatusArchived}, KeyStatusArchived},
+		{"revoked", KeyDocument{Status: KeyStatusRevoked}, KeyStatusRevoked},
+	}
+	for _, tt := range tests {
+		if got := tt.doc.keyStatus(); got != tt.want {
+			t.Errorf("%s: keyStatus() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStats_CountsRevokedKeys(t *testing.T) {
+	km := &KeyManagerWithPersistence{
+		keys: map[string]*Key{
+			"room-a": {Status: KeyStatusActive},
+		},
+		oldKeys: map[string][]*Key{
+			"room-a": {{Status: KeyStatusArchived}, {Status: KeyStatusRevoked}},
+		},
+	}
+
+	stats := km.Stats()
+	if stats.ActiveKeys != 1 || stats.ArchivedKeys != 1 || stats.RevokedKeys != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestGetOrCreateRoomKeyWithVersion_CountsCacheHits(t *testing.T) {
+	roomID := "507f1f77bcf86cd799439011"
+	km := &KeyManagerWithPersistence{
+		keys: map[string]*Key{
+			roomID: {ID: roomID, Value: []byte("current"), Version: 2, Status: KeyStatusActive},
+		},
+		oldKeys: map[string][]*Key{},
+	}
+
+	for i := 0; i < 3; i++ {
+		key, version, err := km.GetOrCreateRoomKeyWithVersion(roomID)
+		if err != nil {
+			t.Fatalf("GetOrCreateRoomKeyWithVersion: %v", err)
+		}
+		if string(key) != "current" || version != 2 {
+			t.Fatalf("got key %q version %d", key, version)
+		}
+	}
+
+	stats := km.Stats()
+	if stats.CacheHits != 3 || stats.CacheMisses != 0 {
+		t.Errorf("hits/misses = %d/%d, want 3/0", stats.CacheHits, stats.CacheMisses)
+	}
+}
+
+func TestWarmUp_SkipsCachedRoomsAndStopsOnCancel(t *testing.T) {
+	km := &KeyManagerWithPersistence{
+		keys: map[string]*Key{
+			"room-a": {Status: KeyStatusActive},
+			"room-b": {Status: KeyStatusActive},
+		},
+		oldKeys: map[string][]*Key{},
+	}
+
+	loaded, err := km.WarmUp(context.Background(), []string{"room-a", "room-b"})
+	if err != nil || loaded != 0 {
+		t.Errorf("WarmUp cached rooms = %d, %v; want 0, nil", loaded, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := km.WarmUp(ctx, []string{"room-c"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCheckMasterKeyRoundTrip(t *testing.T) {
+	km := &KeyManagerWithPersistence{masterKey: make([]byte, 32)}
+	if err := km.checkMasterKeyRoundTrip(); err != nil {
+		t.Errorf("valid master key: %v", err)
+	}
+
+	km = &KeyManagerWithPersistence{masterKey: make([]byte, 7)}
+	if err := km.checkMasterKeyRoundTrip(); err == nil {
+		t.Error("expected error for invalid master key")
+	}
+}
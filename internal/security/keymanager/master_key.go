@@ -0,0 +1,114 @@
+package keymanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"chat-gateway/internal/constants"
+)
+
+// MasterKeyProvider 主密鑰來源
+// 目前支持環境變量和文件，KMS / Vault 等後端實現此接口並加入 masterKeySources 即可
+type MasterKeyProvider interface {
+	// Name 來源描述（用於日誌，不能包含密鑰內容）
+	Name() string
+	// Load 讀取主密鑰
+	Load(ctx context.Context) ([]byte, error)
+}
+
+// masterKeySource 通過環境變量啟用的主密鑰來源
+type masterKeySource struct {
+	envVar      string
+	newProvider func(value string) MasterKeyProvider
+}
+
+// masterKeySources 所有主密鑰來源（同時只能設置一個）
+var masterKeySources = []masterKeySource{
+	{envVar: "MASTER_KEY", newProvider: func(value string) MasterKeyProvider { return envMasterKeyProvider{value: value} }},
+	{envVar: "MASTER_KEY_FILE", newProvider: func(value string) MasterKeyProvider { return fileMasterKeyProvider{path: value} }},
+}
+
+// ResolveMasterKeyProvider 根據環境變量選擇主密鑰來源
+// 沒有設置任何來源時返回 nil；設置了多個來源時返回錯誤，避免使用了非預期的密鑰
+func ResolveMasterKeyProvider(getenv func(string) string) (MasterKeyProvider, error) {
+	var (
+		provider MasterKeyProvider
+		set      []string
+	)
+	for _, source := range masterKeySources {
+		value := getenv(source.envVar)
+		if value == "" {
+			continue
+		}
+		set = append(set, source.envVar)
+		provider = source.newProvider(value)
+	}
+
+	if len(set) > 1 {
+		return nil, fmt.Errorf("multiple master key sources are set (%s), only one is allowed", strings.Join(set, ", "))
+	}
+	return provider, nil
+}
+
+// LoadMasterKey 從來源讀取主密鑰並驗證長度
+func LoadMasterKey(ctx context.Context, provider MasterKeyProvider) ([]byte, error) {
+	key, err := provider.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", provider.Name(), err)
+	}
+	if len(key) != constants.MasterKeyLength {
+		return nil, fmt.Errorf("%s: master key must be %d bytes, got %d", provider.Name(), constants.MasterKeyLength, len(key))
+	}
+	return key, nil
+}
+
+// envMasterKeyProvider 從環境變量讀取 base64 編碼的主密鑰
+type envMasterKeyProvider struct {
+	value string
+}
+
+func (p envMasterKeyProvider) Name() string {
+	return "MASTER_KEY environment variable"
+}
+
+func (p envMasterKeyProvider) Load(_ context.Context) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(p.value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 master key")
+	}
+	return key, nil
+}
+
+// fileMasterKeyProvider 從文件讀取主密鑰（例如容器掛載的 secret）
+// 文件內容可以是 32 bytes 原始密鑰，或 base64 編碼的密鑰（允許結尾換行）
+type fileMasterKeyProvider struct {
+	path string
+}
+
+func (p fileMasterKeyProvider) Name() string {
+	return "MASTER_KEY_FILE " + p.path
+}
+
+func (p fileMasterKeyProvider) Load(_ context.Context) ([]byte, error) {
+	data, err := os.ReadFile(p.path) // #nosec G304 -- path comes from operator-controlled MASTER_KEY_FILE
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key file: %w", err)
+	}
+	defer zeroBytes(data)
+
+	if len(data) == constants.MasterKeyLength {
+		key := make([]byte, constants.MasterKeyLength)
+		copy(key, data)
+		return key, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	if err != nil {
+		return nil, fmt.Errorf("master key file must contain %d raw bytes or base64", constants.MasterKeyLength)
+	}
+	return key, nil
+}
@@ -0,0 +1,74 @@
+package keymanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func envFrom(values map[string]string) func(string) string {
+	return func(name string) string { return values[name] }
+}
+
+func TestResolveMasterKeyProvider(t *testing.T) {
+	provider, err := ResolveMasterKeyProvider(envFrom(nil))
+	if err != nil || provider != nil {
+		t.Errorf("no source: provider = %v, err = %v; want nil, nil", provider, err)
+	}
+
+	provider, err = ResolveMasterKeyProvider(envFrom(map[string]string{"MASTER_KEY_FILE": "/run/secrets/master_key"}))
+	if err != nil {
+		t.Fatalf("file source: %v", err)
+	}
+	if _, ok := provider.(fileMasterKeyProvider); !ok {
+		t.Errorf("file source: provider = %T, want fileMasterKeyProvider", provider)
+	}
+
+	_, err = ResolveMasterKeyProvider(envFrom(map[string]string{
+		"MASTER_KEY":      "a2V5",
+		"MASTER_KEY_FILE": "/run/secrets/master_key",
+	}))
+	if err == nil {
+		t.Error("expected error when more than one source is set")
+	}
+}
+
+func TestLoadMasterKey_File(t *testing.T) {
+	want := bytes.Repeat([]byte{0x0a}, 32) // 全部是換行字符，確保原始密鑰不會被去除空白
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content []byte
+		wantErr bool
+	}{
+		{"raw bytes", want, false},
+		{"base64 with newline", []byte(base64.StdEncoding.EncodeToString(want) + "\n"), false},
+		{"wrong length", []byte(base64.StdEncoding.EncodeToString(want[:16])), true},
+		{"garbage", []byte("not a key"), true},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i)))
+			if err := os.WriteFile(path, tt.content, 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadMasterKey(context.Background(), fileMasterKeyProvider{path: path})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadMasterKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, want) {
+				t.Errorf("LoadMasterKey() = %x, want %x", got, want)
+			}
+		})
+	}
+
+	if _, err := LoadMasterKey(context.Background(), fileMasterKeyProvider{path: filepath.Join(dir, "missing")}); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
@@ -12,13 +12,26 @@ import (
 
 // KeyDocument MongoDB 中存儲的密鑰文檔
 type KeyDocument struct {
-	RoomID       string    `bson:"room_id"`       // 聊天室 ID
-	KeyVersion   int       `bson:"key_version"`   // 密鑰版本
-	EncryptedKey string    `bson:"encrypted_key"` // 用 Master Key 加密的 Room Key
-	CreatedAt    time.Time `bson:"created_at"`    // 創建時間
-	RotatedAt    time.Time `bson:"rotated_at"`    // 上次輪替時間
-	IsActive     bool      `bson:"is_active"`     // 是否為活躍密鑰
-	ExpiresAt    time.Time `bson:"expires_at"`    // 過期時間
+	RoomID       string    `bson:"room_id"`              // 聊天室 ID
+	KeyVersion   int       `bson:"key_version"`          // 密鑰版本
+	EncryptedKey string    `bson:"encrypted_key"`        // 用 Master Key 加密的 Room Key
+	CreatedAt    time.Time `bson:"created_at"`           // 創建時間
+	RotatedAt    time.Time `bson:"rotated_at"`           // 上次輪替時間
+	IsActive     bool      `bson:"is_active"`            // 是否為活躍密鑰
+	ExpiresAt    time.Time `bson:"expires_at"`           // 過期時間
+	Status       KeyStatus `bson:"status,omitempty"`     // 密鑰狀態（舊文檔沒有此字段）
+	RevokedAt    time.Time `bson:"revoked_at,omitempty"` // 撤銷時間
+}
+
+// keyStatus 獲取密鑰文檔狀態（兼容沒有 status 字段的舊文檔）
+func (doc *KeyDocument) keyStatus() KeyStatus {
+	if doc.Status == KeyStatusRevoked {
+		return KeyStatusRevoked
+	}
+	if doc.IsActive {
+		return KeyStatusActive
+	}
+	return KeyStatusArchived
 }
 
 // KeyStore 密鑰持久化存儲
@@ -96,7 +109,7 @@ func (ks *KeyStore) saveKeyWithContext(ctx context.Context, doc *KeyDocument) er
 			"key_version": bson.M{"$ne": doc.KeyVersion},
 		}
 		update := bson.M{
-			"$set": bson.M{"is_active": false},
+			"$set": bson.M{"is_active": false, "status": KeyStatusArchived},
 		}
 		_, err := ks.collection.UpdateMany(ctx, filter, update)
 		if err != nil {
@@ -120,6 +133,14 @@ func (ks *KeyStore) saveKeyWithContext(ctx context.Context, doc *KeyDocument) er
 	return nil
 }
 
+// Ping 檢查密鑰集合是否可訪問（用於健康檢查）
+func (ks *KeyStore) Ping(ctx context.Context) error {
+	if _, err := ks.collection.EstimatedDocumentCount(ctx); err != nil {
+		return fmt.Errorf("encryption_keys collection unreachable: %w", err)
+	}
+	return nil
+}
+
 // GetActiveKey 獲取活躍的密鑰
 func (ks *KeyStore) GetActiveKey(ctx context.Context, roomID string) (*KeyDocument, error) {
 	filter := bson.M{
@@ -138,6 +159,48 @@ func (ks *KeyStore) GetKeyByVersion(ctx context.Context, roomID string, version
 	return ks.findOneKey(ctx, filter, "failed to get key by version")
 }
 
+// GetLatestVersion 獲取聊天室最新的密鑰版本（包括已撤銷的密鑰），沒有密鑰時返回 0
+func (ks *KeyStore) GetLatestVersion(ctx context.Context, roomID string) (int, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "key_version", Value: -1}})
+
+	var doc KeyDocument
+	err := ks.collection.FindOne(ctx, bson.M{"room_id": roomID}, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest key version: %w", err)
+	}
+	return doc.KeyVersion, nil
+}
+
+// RevokeActiveKey 將聊天室的活躍密鑰標記為已撤銷，返回被撤銷的密鑰
+// 沒有活躍密鑰時返回 nil
+func (ks *KeyStore) RevokeActiveKey(ctx context.Context, roomID string, revokedAt time.Time) (*KeyDocument, error) {
+	filter := bson.M{
+		"room_id":   roomID,
+		"is_active": true,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"is_active":  false,
+			"status":     KeyStatusRevoked,
+			"revoked_at": revokedAt,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var doc KeyDocument
+	err := ks.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke active key: %w", err)
+	}
+	return &doc, nil
+}
+
 // findOneKey 是查詢單個密鑰的輔助函數
 func (ks *KeyStore) findOneKey(ctx context.Context, filter bson.M, errMsg string) (*KeyDocument, error) {
 	var doc KeyDocument
@@ -185,6 +248,16 @@ func (ks *KeyStore) DeleteExpiredKeys(ctx context.Context) (int64, error) {
 	return result.DeletedCount, nil
 }
 
+// DeleteRoomKeys 刪除聊天室的所有密鑰（包括歷史版本）
+func (ks *KeyStore) DeleteRoomKeys(ctx context.Context, roomID string) (int64, error) {
+	result, err := ks.collection.DeleteMany(ctx, bson.M{"room_id": roomID})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete room keys: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
 // GetKeysToRotate 獲取需要輪替的密鑰
 func (ks *KeyStore) GetKeysToRotate(ctx context.Context, rotationInterval time.Duration) ([]*KeyDocument, error) {
 	filter := bson.M{
@@ -0,0 +1,133 @@
+package keymanager
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// RewrapResult 主密鑰重新包裝結果
+type RewrapResult struct {
+	Total     int  // 數據庫中的密鑰數量
+	Rewrapped int  // 重新包裝的密鑰數量（dry-run 時為將會變更的數量）
+	DryRun    bool // 是否為 dry-run（未寫入數據庫）
+}
+
+// rewrappedKey 重新包裝後待寫入的密鑰
+type rewrappedKey struct {
+	doc          *KeyDocument
+	encryptedKey string
+}
+
+// RewrapMasterKey 用新的 Master Key 重新包裝所有聊天室密鑰（主密鑰輪換或洩露後恢復）
+// 每個密鑰先用舊主密鑰解密、再用新主密鑰加密，並用新主密鑰解密驗證後才寫入；
+// 所有更新在同一個事務中提交（需要 MongoDB 副本集），任何一個失敗都不會寫入。
+// dryRun 時只驗證並統計，不修改數據庫。
+//
+// 注意：密鑰包裝使用 AES-CTR，沒有完整性校驗，無法檢測舊主密鑰是否正確，
+// 執行前請先備份 encryption_keys 並用 dry-run 確認。
+func (ks *KeyStore) RewrapMasterKey(ctx context.Context, oldMasterKey, newMasterKey []byte, dryRun bool) (*RewrapResult, error) {
+	if len(oldMasterKey) != 32 || len(newMasterKey) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes (256 bits)")
+	}
+	if bytes.Equal(oldMasterKey, newMasterKey) {
+		return nil, fmt.Errorf("new master key must differ from the old one")
+	}
+
+	cursor, err := ks.collection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []*KeyDocument
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode keys: %w", err)
+	}
+
+	pending := make([]rewrappedKey, 0, len(docs))
+	for _, doc := range docs {
+		encryptedKey, err := rewrapRoomKey(oldMasterKey, newMasterKey, doc.EncryptedKey)
+		if err != nil {
+			return nil, fmt.Errorf("room %s key version %d: %w", doc.RoomID, doc.KeyVersion, err)
+		}
+		pending = append(pending, rewrappedKey{doc: doc, encryptedKey: encryptedKey})
+	}
+
+	result := &RewrapResult{
+		Total:     len(docs),
+		Rewrapped: len(pending),
+		DryRun:    dryRun,
+	}
+	if dryRun || len(pending) == 0 {
+		return result, nil
+	}
+
+	session, err := ks.collection.Database().Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc context.Context) (interface{}, error) {
+		for _, key := range pending {
+			// 以原密文作為條件，避免覆蓋執行期間被修改的密鑰
+			filter := bson.M{
+				"room_id":       key.doc.RoomID,
+				"key_version":   key.doc.KeyVersion,
+				"encrypted_key": key.doc.EncryptedKey,
+			}
+			update := bson.M{
+				"$set": bson.M{"encrypted_key": key.encryptedKey},
+			}
+
+			res, err := ks.collection.UpdateOne(sc, filter, update)
+			if err != nil {
+				return nil, fmt.Errorf("failed to update room %s key version %d: %w", key.doc.RoomID, key.doc.KeyVersion, err)
+			}
+			if res.MatchedCount != 1 {
+				return nil, fmt.Errorf("room %s key version %d changed during rewrap", key.doc.RoomID, key.doc.KeyVersion)
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rewrap transaction failed (requires a replica set): %w", err)
+	}
+
+	return result, nil
+}
+
+// rewrapRoomKey 將用舊主密鑰加密的 Room Key 改用新主密鑰加密，並驗證新密文可以還原
+func rewrapRoomKey(oldMasterKey, newMasterKey []byte, encryptedKey string) (string, error) {
+	roomKey, err := decryptWithMasterKey(oldMasterKey, encryptedKey)
+	if err != nil {
+		return "", err
+	}
+	defer zeroBytes(roomKey)
+
+	rewrapped, err := encryptWithMasterKey(newMasterKey, roomKey)
+	if err != nil {
+		return "", err
+	}
+
+	verified, err := decryptWithMasterKey(newMasterKey, rewrapped)
+	if err != nil {
+		return "", fmt.Errorf("verification failed: %w", err)
+	}
+	defer zeroBytes(verified)
+
+	if !bytes.Equal(verified, roomKey) {
+		return "", fmt.Errorf("verification failed: rewrapped key does not match")
+	}
+	return rewrapped, nil
+}
+
+// zeroBytes 清零密鑰數據（安全增強）
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
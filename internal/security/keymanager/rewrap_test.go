@@ -0,0 +1,50 @@
+package keymanager
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestRewrapRoomKey(t *testing.T) {
+	oldMasterKey := bytes.Repeat([]byte{1}, 32)
+	newMasterKey := bytes.Repeat([]byte{2}, 32)
+	roomKey := bytes.Repeat([]byte{7}, 32)
+
+	encrypted, err := encryptWithMasterKey(oldMasterKey, roomKey)
+	if err != nil {
+		t.Fatalf("encryptWithMasterKey: %v", err)
+	}
+
+	rewrapped, err := rewrapRoomKey(oldMasterKey, newMasterKey, encrypted)
+	if err != nil {
+		t.Fatalf("rewrapRoomKey: %v", err)
+	}
+	if rewrapped == encrypted {
+		t.Fatal("rewrapped key should differ from the original ciphertext")
+	}
+
+	got, err := decryptWithMasterKey(newMasterKey, rewrapped)
+	if err != nil {
+		t.Fatalf("decryptWithMasterKey: %v", err)
+	}
+	if !bytes.Equal(got, roomKey) {
+		t.Error("rewrapped key does not decrypt to the original room key with the new master key")
+	}
+
+	if _, err := rewrapRoomKey(oldMasterKey, newMasterKey, "not-base64!"); err == nil {
+		t.Error("expected error for malformed encrypted key")
+	}
+}
+
+func TestRewrapMasterKey_RejectsInvalidKeys(t *testing.T) {
+	ks := &KeyStore{}
+	key := bytes.Repeat([]byte{1}, 32)
+
+	if _, err := ks.RewrapMasterKey(context.Background(), key[:16], key, true); err == nil {
+		t.Error("expected error for short master key")
+	}
+	if _, err := ks.RewrapMasterKey(context.Background(), key, key, true); err == nil {
+		t.Error("expected error when old and new master keys are equal")
+	}
+}
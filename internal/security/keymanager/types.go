@@ -39,10 +39,20 @@ type KeyInfo struct {
 	Age       time.Duration
 }
 
+// RoomKeyStatus 聊天室密鑰狀態（用於排查解密問題，不包含密鑰值）
+type RoomKeyStatus struct {
+	Current          *KeyInfo // 當前密鑰（沒有密鑰時為 nil）
+	Cached           bool     // 當前密鑰是否已加載到內存
+	ArchivedVersions []int    // 內存中的歷史密鑰版本
+	StoredVersions   []int    // 數據庫中的所有密鑰版本（最新的在前）
+}
+
 // KeyManagerStats 密鑰管理器統計信息
 type KeyManagerStats struct {
 	TotalKeys    int
 	ActiveKeys   int
 	ArchivedKeys int
 	RevokedKeys  int
+	CacheHits    int64 // 獲取當前密鑰時的緩存命中次數
+	CacheMisses  int64 // 獲取當前密鑰時的緩存未命中次數（需要查詢數據庫）
 }
@@ -0,0 +1,137 @@
+// Package attachment 訊息附件的類型校驗和元數據
+package attachment
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // 註冊 GIF 解碼器，用於讀取圖片尺寸
+	_ "image/jpeg" // 註冊 JPEG 解碼器
+	_ "image/png"  // 註冊 PNG 解碼器
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"chat-gateway/internal/constants"
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/storage/filestore"
+)
+
+// ErrTypeNotAllowed 文件類型不在允許列表中
+var ErrTypeNotAllowed = errors.New("file type not allowed")
+
+// defaultAllowedTypes 未配置 allowed_types 時允許的類型
+// 不包含 HTML 和 SVG：即使設置了 nosniff，瀏覽器直接打開這些文件時仍會執行其中的腳本
+var defaultAllowedTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"application/zip",
+	"text/plain",
+	"audio/mpeg",
+	"video/mp4",
+}
+
+// extensions 常見類型的擴展名（mime 包返回的擴展名因系統而異）
+var extensions = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"application/zip": ".zip",
+	"text/plain":      ".txt",
+	"audio/mpeg":      ".mp3",
+	"video/mp4":       ".mp4",
+}
+
+// Info 上傳文件的元數據
+type Info struct {
+	FileName    string
+	Size        int64
+	ContentType string // 按文件內容判斷的類型（不帶參數）
+	Width       int    // 圖片寬度（非圖片或無法解碼時為 0）
+	Height      int    // 圖片高度
+}
+
+// IsImage 是否為圖片
+func (i Info) IsImage() bool {
+	return strings.HasPrefix(i.ContentType, "image/")
+}
+
+// Extension 存儲文件使用的擴展名
+func (i Info) Extension() string {
+	if ext, ok := extensions[i.ContentType]; ok {
+		return ext
+	}
+	if exts, err := mime.ExtensionsByType(i.ContentType); err == nil && len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
+
+// Inspect 按文件內容判斷類型（不信任客戶端提供的 Content-Type），類型不在 allowedTypes 中時返回 ErrTypeNotAllowed
+// 圖片會讀取寬高（只解析文件頭，不解碼整張圖片）
+func Inspect(fileName string, data []byte, allowedTypes []string) (Info, error) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return Info{}, err
+	}
+	if !slices.Contains(allowedTypes, contentType) {
+		return Info{}, fmt.Errorf("%w: %s", ErrTypeNotAllowed, contentType)
+	}
+
+	info := Info{
+		FileName:    path.Base(strings.ReplaceAll(fileName, "\\", "/")),
+		Size:        int64(len(data)),
+		ContentType: contentType,
+	}
+	if info.IsImage() {
+		if cfg, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+			info.Width, info.Height = cfg.Width, cfg.Height
+		}
+	}
+	return info, nil
+}
+
+// Key 附件的存儲鍵（按月份分目錄，文件名隨機）
+func Key(now time.Time, ext string) (string, error) {
+	return filestore.RandomKey(path.Join("uploads", now.UTC().Format("2006/01")), ext)
+}
+
+// AllowedTypes 配置的允許類型，未配置時使用默認列表
+func AllowedTypes() []string {
+	if cfg := config.Get(); cfg != nil && len(cfg.Storage.Uploads.AllowedTypes) > 0 {
+		return cfg.Storage.Uploads.AllowedTypes
+	}
+	return defaultAllowedTypes
+}
+
+// MaxSize 單個附件的大小上限
+func MaxSize() int64 {
+	cfg := config.Get()
+	if cfg != nil && cfg.Storage.Uploads.MaxSizeBytes > 0 {
+		return cfg.Storage.Uploads.MaxSizeBytes
+	}
+	if cfg != nil && cfg.Limits.Request.MaxMultipartMemory > 0 {
+		return cfg.Limits.Request.MaxMultipartMemory
+	}
+	return constants.DefaultMaxMultipartMemory
+}
+
+// NewStoreFromConfig 根據配置創建附件存儲，未啟用時返回 nil
+func NewStoreFromConfig() (filestore.Store, error) {
+	cfg := config.Get()
+	if cfg == nil || !cfg.Storage.Uploads.Enabled {
+		return nil, nil
+	}
+
+	uploads := cfg.Storage.Uploads
+	return filestore.New(uploads.Backend, uploads.LocalDir, uploads.BaseURL)
+}
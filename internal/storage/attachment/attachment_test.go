@@ -0,0 +1,111 @@
+package attachment
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestInspect(t *testing.T) {
+	pngData := encodePNG(t, 3, 2)
+
+	tests := []struct {
+		name     string
+		fileName string
+		data     []byte
+		allowed  []string
+		want     Info
+		wantErr  bool
+	}{
+		{
+			name:     "png with size",
+			fileName: "photo.png",
+			data:     pngData,
+			allowed:  defaultAllowedTypes,
+			want:     Info{FileName: "photo.png", Size: int64(len(pngData)), ContentType: "image/png", Width: 3, Height: 2},
+		},
+		{
+			name:     "text strips charset",
+			fileName: "notes.txt",
+			data:     []byte("hello"),
+			allowed:  defaultAllowedTypes,
+			want:     Info{FileName: "notes.txt", Size: 5, ContentType: "text/plain"},
+		},
+		{
+			name:     "client path removed",
+			fileName: `C:\Users\me\report.pdf`,
+			data:     []byte("%PDF-1.7\n"),
+			allowed:  defaultAllowedTypes,
+			want:     Info{FileName: "report.pdf", Size: 9, ContentType: "application/pdf"},
+		},
+		{
+			name:     "html sniffed despite extension",
+			fileName: "image.png",
+			data:     []byte("<html><script>alert(1)</script></html>"),
+			allowed:  defaultAllowedTypes,
+			wantErr:  true,
+		},
+		{
+			name:     "type not in configured list",
+			fileName: "photo.png",
+			data:     pngData,
+			allowed:  []string{"application/pdf"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Inspect(tt.fileName, tt.data, tt.allowed)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Inspect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrTypeNotAllowed) {
+				t.Errorf("error = %v, want ErrTypeNotAllowed", err)
+			}
+			if got != tt.want {
+				t.Errorf("Inspect() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInfoExtension(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{"image/jpeg", ".jpg"},
+		{"application/pdf", ".pdf"},
+		{"application/x-unknown-type", ""},
+	}
+
+	for _, tt := range tests {
+		if got := (Info{ContentType: tt.contentType}).Extension(); got != tt.want {
+			t.Errorf("Extension(%q) = %q, want %q", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	key, err := Key(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC), ".pdf")
+	if err != nil {
+		t.Fatalf("Key() error = %v", err)
+	}
+	if !strings.HasPrefix(key, "uploads/2026/03/") || !strings.HasSuffix(key, ".pdf") {
+		t.Errorf("Key() = %q, want uploads/2026/03/<random>.pdf", key)
+	}
+}
@@ -0,0 +1,50 @@
+// Package avatar 頭像圖片的校驗和存儲
+package avatar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"chat-gateway/internal/platform/config"
+	"chat-gateway/internal/storage/filestore"
+)
+
+// ErrUnsupportedType 不是支持的圖片格式
+var ErrUnsupportedType = errors.New("unsupported image type")
+
+// imageExtensions 允許上傳的圖片格式（按文件內容判斷，不信任客戶端提供的 Content-Type）
+// SVG 可以包含腳本，不在允許範圍內
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// DetectImageType 根據文件內容判斷圖片格式，返回 Content-Type 和擴展名
+func DetectImageType(data []byte) (contentType, ext string, err error) {
+	contentType = http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+	return contentType, ext, nil
+}
+
+// RoomAvatarKey 聊天室頭像的存儲鍵（每次上傳使用新的隨機文件名）
+func RoomAvatarKey(roomID, ext string) (string, error) {
+	return filestore.RandomKey(path.Join("rooms", roomID), ext)
+}
+
+// NewStoreFromConfig 根據配置創建頭像存儲，未啟用時返回 nil
+func NewStoreFromConfig() (filestore.Store, error) {
+	cfg := config.Get()
+	if cfg == nil || !cfg.Storage.Avatar.Enabled {
+		return nil, nil
+	}
+
+	avatarCfg := cfg.Storage.Avatar
+	return filestore.New(avatarCfg.Backend, avatarCfg.LocalDir, avatarCfg.BaseURL)
+}
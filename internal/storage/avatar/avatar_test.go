@@ -0,0 +1,57 @@
+package avatar
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// pngHeader PNG 文件簽名和 IHDR 塊開頭
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestDetectImageType(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantExt string
+		wantErr bool
+	}{
+		{"png", pngHeader, "image/png", ".png", false},
+		{"jpeg", []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00"), "image/jpeg", ".jpg", false},
+		{"gif", []byte("GIF89a\x01\x00\x01\x00"), "image/gif", ".gif", false},
+		{"svg", []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`), "", "", true},
+		{"html", []byte("<html><body>hi</body></html>"), "", "", true},
+		{"text", []byte("hello"), "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ext, err := DetectImageType(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectImageType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("error = %v, want ErrUnsupportedType", err)
+			}
+			if got != tt.want || ext != tt.wantExt {
+				t.Errorf("DetectImageType() = %q, %q, want %q, %q", got, ext, tt.want, tt.wantExt)
+			}
+		})
+	}
+}
+
+func TestRoomAvatarKey(t *testing.T) {
+	a, err := RoomAvatarKey("room-1", ".png")
+	if err != nil {
+		t.Fatalf("RoomAvatarKey() error = %v", err)
+	}
+	b, _ := RoomAvatarKey("room-1", ".png")
+
+	if !strings.HasPrefix(a, "rooms/room-1/") || !strings.HasSuffix(a, ".png") {
+		t.Errorf("RoomAvatarKey() = %q", a)
+	}
+	if a == b {
+		t.Error("expected a new key for each upload")
+	}
+}
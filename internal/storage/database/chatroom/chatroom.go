@@ -2,7 +2,9 @@ package chatroom
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -13,33 +15,78 @@ import (
 // ChatRoomRepository 聊天室倉儲接口
 type ChatRoomRepository interface {
 	Create(ctx context.Context, room *ChatRoom) error
+	InsertMany(ctx context.Context, rooms []*ChatRoom) (map[int]error, error)
 	GetByID(ctx context.Context, id string) (*ChatRoom, error)
+	GetSettings(ctx context.Context, id string) (*RoomSettings, error)
 	Update(ctx context.Context, id string, update map[string]interface{}) error
+	UpdateIf(ctx context.Context, id string, expectedVersion int64, update map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
-	ListUserRooms(ctx context.Context, userID string, limit int, cursor string) ([]*ChatRoom, string, bool, error)
+	ListUserRooms(ctx context.Context, userID string, limit int, cursor string, includeArchived bool, sort string) ([]*ChatRoom, string, bool, error)
 	IsMember(ctx context.Context, roomID, userID string) (bool, error)
-	AddMember(ctx context.Context, member *RoomMember) error
+	AddMember(ctx context.Context, roomID string, member *RoomMember) error
+	AddMemberWithLimit(ctx context.Context, roomID string, member *RoomMember, maxMembers int) error
 	RemoveMember(ctx context.Context, roomID, userID string) error
 	GetMembers(ctx context.Context, roomID string) ([]RoomMember, error)
+	ListMembers(ctx context.Context, roomID string, query MemberQuery) ([]RoomMember, string, bool, error)
 	GetMemberCount(ctx context.Context, roomID string) (int, error)
+	GetMemberLastReadAt(ctx context.Context, roomID, userID string) (time.Time, error)
+	ListRooms(ctx context.Context, query RoomQuery) ([]*ChatRoom, string, bool, error)
 }
 
+// 聊天室名稱唯一性模式（對應 limits.room.name_uniqueness 配置）
+const (
+	NameUniquenessNone     = "none"      // 不限制
+	NameUniquenessPerOwner = "per_owner" // 同一擁有者的聊天室名稱唯一
+	NameUniquenessGlobal   = "global"    // 所有聊天室名稱唯一
+
+	roomTypeDirect = "direct"
+
+	// directMaxMembers 私聊固定最多兩個成員，不受 settings.max_members 影響
+	directMaxMembers = 2
+)
+
+var (
+	// ErrRoomFull 聊天室成員已達上限
+	ErrRoomFull = errors.New("room is full")
+	// ErrAlreadyMember 用戶已經是聊天室成員
+	ErrAlreadyMember = errors.New("user is already a member")
+	// ErrVersionConflict 聊天室在讀取後已被修改（版本號不一致）
+	ErrVersionConflict = errors.New("room version conflict")
+)
+
+// incVersion 遞增聊天室版本號的更新操作
+var incVersion = bson.M{"version": int64(1)}
+
+// 聊天室模式（RoomSettings.Mode）
+const (
+	RoomModeNormal       = "normal"       // 所有成員都可以發言
+	RoomModeSlowmode     = "slowmode"     // 每個成員兩次發言之間需要間隔
+	RoomModeAnnouncement = "announcement" // 只有擁有者和管理員可以發言
+	RoomModeReadOnly     = "read_only"    // 任何人都不能發言
+)
+
 // ChatRoom 聊天室數據模型
 type ChatRoom struct {
-	_ID             interface{}            `bson:"_id" form:"_id"`
-	ID              string                 `json:"id,omitempty" bson:"id" form:"id"`
-	Name            string                 `bson:"name" json:"name"`
-	AvatarURL       string                 `bson:"avatar_url" json:"avatar_url"`
-	Type            string                 `bson:"type" json:"type"`
-	OwnerID         string                 `bson:"owner_id" json:"owner_id"`
-	Settings        RoomSettings           `bson:"settings" json:"settings"`
-	CreatedAt       time.Time              `bson:"created_at" json:"created_at"`
-	UpdatedAt       time.Time              `bson:"updated_at" json:"updated_at"`
-	LastMessageAt   time.Time              `bson:"last_message_at" json:"last_message_at"`
-	LastMessage     string                 `bson:"last_message" json:"last_message"`
-	LastMessageTime time.Time              `bson:"last_message_time" json:"last_message_time"`
-	Members         []RoomMember           `bson:"members,omitempty" json:"members,omitempty"`
-	Metadata        map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	_ID              interface{}            `bson:"_id" form:"_id"`
+	ID               string                 `json:"id,omitempty" bson:"id" form:"id"`
+	Name             string                 `bson:"name" json:"name"`
+	AvatarURL        string                 `bson:"avatar_url" json:"avatar_url"`
+	Type             string                 `bson:"type" json:"type"`
+	DirectKey        string                 `bson:"direct_key,omitempty" json:"-"` // 私聊的兩個成員（唯一索引，防止重複創建）
+	OwnerID          string                 `bson:"owner_id" json:"owner_id"`
+	Settings         RoomSettings           `bson:"settings" json:"settings"`
+	CreatedAt        time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time              `bson:"updated_at" json:"updated_at"`
+	LastMessageAt    time.Time              `bson:"last_message_at" json:"last_message_at"`
+	LastMessage      string                 `bson:"last_message" json:"last_message"`
+	LastMessageKeyID string                 `bson:"last_message_key_id,omitempty" json:"-"` // 加密最後訊息預覽的密鑰版本
+	LastMessageTime  time.Time              `bson:"last_message_time" json:"last_message_time"`
+	Members          []RoomMember           `bson:"members,omitempty" json:"members,omitempty"`
+	MemberCount      int                    `bson:"member_count,omitempty" json:"-"` // 成員存放在獨立集合時的成員數量
+	HasUnread        bool                   `bson:"has_unread,omitempty" json:"-"`   // 按未讀排序時計算：請求用戶是否有未讀訊息（不存儲）
+	Pinned           bool                   `bson:"pinned,omitempty" json:"-"`       // 列出用戶聊天室時計算：請求用戶是否置頂（不存儲）
+	Metadata         map[string]interface{} `bson:"metadata,omitempty" json:"metadata,omitempty"`
+	Version          int64                  `bson:"version" json:"version"` // 聊天室信息或成員變更時遞增，用於檢測並發修改
 }
 
 // NewChatRoom 創建新的 ChatRoom 實例
@@ -51,15 +98,34 @@ func NewChatRoom() ChatRoom {
 
 // RoomMember 聊天室成員數據模型
 type RoomMember struct {
-	UserID      string    `bson:"user_id" json:"user_id"`
-	Username    string    `bson:"username" json:"username"`
-	DisplayName string    `bson:"display_name" json:"display_name"`
-	AvatarURL   string    `bson:"avatar_url" json:"avatar_url"`
-	Role        string    `bson:"role" json:"role"`
-	Status      string    `bson:"status" json:"status"`
-	JoinedAt    time.Time `bson:"joined_at" json:"joined_at"`
-	LastSeen    time.Time `bson:"last_seen" json:"last_seen"`
-	LastReadAt  time.Time `bson:"last_read_at" json:"last_read_at"`
+	UserID      string     `bson:"user_id" json:"user_id"`
+	Username    string     `bson:"username" json:"username"`
+	DisplayName string     `bson:"display_name" json:"display_name"`
+	AvatarURL   string     `bson:"avatar_url" json:"avatar_url"`
+	Role        string     `bson:"role" json:"role"`
+	Status      string     `bson:"status" json:"status"`
+	JoinedAt    time.Time  `bson:"joined_at" json:"joined_at"`
+	LastSeen    time.Time  `bson:"last_seen" json:"last_seen"`
+	LastReadAt  time.Time  `bson:"last_read_at" json:"last_read_at"`
+	Muted       bool       `bson:"muted,omitempty" json:"muted,omitempty"`             // 靜音聊天室（被提及時仍會通知）
+	MutedUntil  *time.Time `bson:"muted_until,omitempty" json:"muted_until,omitempty"` // 限時靜音的結束時間（Muted 為 true 時表示永久靜音）
+	Archived    bool       `bson:"archived,omitempty" json:"archived,omitempty"`       // 已封存（不出現在聊天室列表，收到新訊息時自動取消）
+	Pinned      bool       `bson:"pinned,omitempty" json:"pinned,omitempty"`           // 置頂（聊天室列表中排在最前，與封存互斥）
+}
+
+// IsMuted 成員在指定時間是否處於靜音狀態
+func (m *RoomMember) IsMuted(now time.Time) bool {
+	return m.Muted || (m.MutedUntil != nil && now.Before(*m.MutedUntil))
+}
+
+// FindMember 查找聊天室成員，不是成員時返回 nil
+func (r *ChatRoom) FindMember(userID string) *RoomMember {
+	for i := range r.Members {
+		if r.Members[i].UserID == userID {
+			return &r.Members[i]
+		}
+	}
+	return nil
 }
 
 // RoomSettings 聊天室設置數據模型
@@ -70,13 +136,20 @@ type RoomSettings struct {
 	AllowPinMessages    bool   `bson:"allow_pin_messages" json:"allow_pin_messages"`
 	MaxMembers          int    `bson:"max_members" json:"max_members"`
 	WelcomeMessage      string `bson:"welcome_message" json:"welcome_message"`
+	Mode                string `bson:"mode,omitempty" json:"mode,omitempty"`                         // 聊天室模式，為空表示普通模式
+	SlowmodeSeconds     int    `bson:"slowmode_seconds,omitempty" json:"slowmode_seconds,omitempty"` // 慢速模式的發言間隔（秒）
+	Locale              string `bson:"locale,omitempty" json:"locale,omitempty"`                     // 系統訊息的語言（小寫 BCP 47 標籤），為空時使用配置的默認語言
 }
 
 // ChatRoomStore 聊天室存儲實作
 type ChatRoomStore struct {
 	collection *mongo.Collection
+	members    *mongo.Collection // 獨立成員集合（nil 表示成員內嵌在聊天室文檔中）
 }
 
+// 編譯期檢查存儲實作滿足倉儲接口，避免簽名不一致
+var _ ChatRoomRepository = (*ChatRoomStore)(nil)
+
 // NewChatRoomStore 創建新的聊天室存儲
 func NewChatRoomStore(db *mongo.Database) *ChatRoomStore {
 	return &ChatRoomStore{
@@ -93,8 +166,17 @@ func (s *ChatRoomStore) Create(ctx context.Context, room *ChatRoom) error {
 	room.UpdatedAt = time.Now()
 	room.LastMessageAt = time.Now()
 
-	_, err := s.collection.InsertOne(ctx, room)
-	return err
+	if s.members == nil {
+		_, err := s.collection.InsertOne(ctx, room)
+		return err
+	}
+
+	members := detachMembers(room)
+	defer func() { room.Members = members }()
+	if _, err := s.collection.InsertOne(ctx, room); err != nil {
+		return err
+	}
+	return s.insertMemberDocs(ctx, room.ID, members)
 }
 
 // GetByID 根據 ID 獲取聊天室
@@ -109,13 +191,112 @@ func (s *ChatRoomStore) GetByID(ctx context.Context, id string) (*ChatRoom, erro
 	if err != nil {
 		return nil, err
 	}
+	if s.members != nil {
+		if room.Members, err = s.loadMembers(ctx, room.ID); err != nil {
+			return nil, err
+		}
+	}
 	return &room, nil
 }
 
+// GetSettings 只讀取聊天室設置（不加載成員）
+func (s *ChatRoomStore) GetSettings(ctx context.Context, id string) (*RoomSettings, error) {
+	var room ChatRoom
+	opts := options.FindOne().SetProjection(bson.M{"settings": 1})
+	if err := s.collection.FindOne(ctx, bson.M{"id": id}, opts).Decode(&room); err != nil {
+		return nil, err
+	}
+	return &room.Settings, nil
+}
+
 // Update 更新聊天室
 func (s *ChatRoomStore) Update(ctx context.Context, id string, update map[string]interface{}) error {
 	update["updated_at"] = time.Now()
-	_, err := s.collection.UpdateOne(ctx, bson.M{"id": id}, bson.M{"$set": update})
+	_, err := s.collection.UpdateOne(ctx, bson.M{"id": id}, bson.M{"$set": update, "$inc": incVersion})
+	return err
+}
+
+// UpdateIf 聊天室版本號等於 expectedVersion 時才更新（樂觀並發控制）
+// 版本號不一致時返回 ErrVersionConflict，調用方應重新讀取聊天室後再決定是否重試
+func (s *ChatRoomStore) UpdateIf(ctx context.Context, id string, expectedVersion int64, update map[string]interface{}) error {
+	update["updated_at"] = time.Now()
+	result, err := s.collection.UpdateOne(ctx, VersionFilter(id, expectedVersion), bson.M{"$set": update, "$inc": incVersion})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	return s.versionMismatch(ctx, id)
+}
+
+// versionMismatch 條件更新沒有匹配時區分聊天室不存在和版本衝突
+func (s *ChatRoomStore) versionMismatch(ctx context.Context, id string) error {
+	count, err := s.collection.CountDocuments(ctx, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("room not found: %s", id)
+	}
+	return ErrVersionConflict
+}
+
+// VersionFilter 指定版本的聊天室（沒有 version 字段的舊聊天室視為版本 0）
+func VersionFilter(id string, version int64) bson.M {
+	if version == 0 {
+		return bson.M{"id": id, "version": bson.M{"$in": bson.A{int64(0), nil}}}
+	}
+	return bson.M{"id": id, "version": version}
+}
+
+// LastMessageKeyVersion 獲取加密最後訊息預覽的密鑰版本
+// 與 Message.KeyVersion 一致：未記錄時視為舊數據，返回 legacyKeyVersion；格式錯誤時返回 0，表示使用當前活躍密鑰
+func (r *ChatRoom) LastMessageKeyVersion() int {
+	if r.LastMessageKeyID == "" {
+		return legacyKeyVersion
+	}
+	version, err := strconv.Atoi(r.LastMessageKeyID)
+	if err != nil || version < 0 {
+		return 0
+	}
+	return version
+}
+
+// ReplaceLastMessage 以新密鑰重新加密後替換最後訊息預覽
+// 只在預覽仍是 previous 時寫入，避免覆蓋期間收到的新訊息；返回是否已替換
+func (s *ChatRoomStore) ReplaceLastMessage(ctx context.Context, id, previous, lastMessage, keyID string) (bool, error) {
+	result, err := s.collection.UpdateOne(ctx,
+		bson.M{"id": id, "last_message": previous},
+		bson.M{"$set": bson.M{
+			"last_message":        lastMessage,
+			"last_message_key_id": keyID,
+		}})
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// UpdateLastMessage 更新聊天室最後訊息，並取消所有成員的封存
+// 兩者在同一次更新中完成，封存的聊天室收到新訊息後會重新出現在列表中
+func (s *ChatRoomStore) UpdateLastMessage(ctx context.Context, id string, update map[string]interface{}) error {
+	if s.members != nil {
+		// 成員在獨立集合中時分兩次更新：先更新最後訊息，再取消成員的封存
+		if _, err := s.collection.UpdateOne(ctx, bson.M{"id": id}, bson.M{"$set": update}); err != nil {
+			return err
+		}
+		_, err := s.members.UpdateMany(ctx, bson.M{"room_id": id, "archived": true}, bson.M{"$set": bson.M{"archived": false}})
+		return err
+	}
+
+	set := bson.M{"members.$[archived].archived": false}
+	for key, value := range update {
+		set[key] = value
+	}
+
+	opts := options.UpdateOne().SetArrayFilters([]any{bson.M{"archived.archived": true}})
+	_, err := s.collection.UpdateOne(ctx, bson.M{"id": id}, bson.M{"$set": set}, opts)
 	return err
 }
 
@@ -129,46 +310,131 @@ func (s *ChatRoomStore) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+// DeleteCascade 刪除聊天室及其所有消息和成員文檔（優先使用事務，失敗則降級）
+// cleanup 與刪除操作使用同一上下文執行，用於清理其他集合中的關聯數據（例如加密密鑰）
+func (s *ChatRoomStore) DeleteCascade(
+	ctx context.Context,
+	roomID string,
+	messages *MessageStore,
+	cleanup func(ctx context.Context) error,
+) (int64, error) {
+	var deletedMessages int64
+
+	// 嘗試使用事務（需要 MongoDB 副本集）
+	session, err := s.collection.Database().Client().StartSession()
+	if err == nil {
+		defer session.EndSession(ctx)
+
+		_, err = session.WithTransaction(ctx, func(sc context.Context) (interface{}, error) {
+			count, err := s.deleteCascadeWithContext(sc, roomID, messages, cleanup)
+			deletedMessages = count
+			return nil, err
+		})
+
+		// 事務成功
+		if err == nil {
+			return deletedMessages, nil
+		}
+
+		// 事務失敗，降級為非事務版本（開發環境單節點 MongoDB）
+	}
+
+	// 非事務版本（不保證原子性，但可用於開發環境）
+	return s.deleteCascadeWithContext(ctx, roomID, messages, cleanup)
+}
+
+// deleteCascadeWithContext 執行實際的級聯刪除（可在事務或非事務上下文中使用）
+func (s *ChatRoomStore) deleteCascadeWithContext(
+	ctx context.Context,
+	roomID string,
+	messages *MessageStore,
+	cleanup func(ctx context.Context) error,
+) (int64, error) {
+	deletedMessages, err := messages.DeleteByRoomID(ctx, roomID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete room messages: %w", err)
+	}
+
+	if cleanup != nil {
+		if err := cleanup(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, collection := range s.cascadeCollections() {
+		if _, err := collection.DeleteMany(ctx, bson.M{"room_id": roomID}); err != nil {
+			return 0, fmt.Errorf("failed to delete room data from %s: %w", collection.Name(), err)
+		}
+	}
+
+	if err := s.Delete(ctx, roomID); err != nil {
+		return 0, fmt.Errorf("failed to delete room: %w", err)
+	}
+
+	return deletedMessages, nil
+}
+
+// cascadeCollections 刪除聊天室時需要按 room_id 一併清理的集合（消息由 MessageStore 刪除並返回數量）
+// 啟用獨立成員集合時包含 room_members，否則成員內嵌在聊天室文檔中，隨文檔一起刪除
+func (s *ChatRoomStore) cascadeCollections() []*mongo.Collection {
+	if s.members == nil {
+		return nil
+	}
+	return []*mongo.Collection{s.members}
+}
+
 // parseObjectID 是轉換字符串 ID 為 ObjectID 的輔助函數
 func parseObjectID(id string) (bson.ObjectID, error) {
 	return bson.ObjectIDFromHex(id)
 }
 
-// ListUserRooms 列出用戶的聊天室.
+// UserRoomsFilter 構建用戶聊天室列表的查詢條件
+// 封存狀態在查詢中過濾（而不是取回後再過濾），確保分頁數量和游標正確
+func UserRoomsFilter(userID string, includeArchived bool) bson.M {
+	if includeArchived {
+		return bson.M{"members.user_id": userID}
+	}
+	return bson.M{
+		"members": bson.M{"$elemMatch": bson.M{
+			"user_id":  userID,
+			"archived": bson.M{"$ne": true},
+		}},
+	}
+}
+
+// ListUserRooms 列出用戶的聊天室（includeArchived 為 false 時不包含用戶已封存的聊天室）.
+// 用戶置頂的聊天室排在最前，其餘按 sort（constants.RoomSort* 之一）排序；
+// 游標記錄置頂狀態和對應的排序字段，與排序方式不符的游標被忽略（從第一頁開始）
 func (s *ChatRoomStore) ListUserRooms(
-	ctx context.Context, userID string, limit int, cursor string,
+	ctx context.Context, userID string, limit int, cursor string, includeArchived bool, sort string,
 ) (
 	rooms []*ChatRoom, nextCursor string, hasMore bool, err error,
 ) {
-	filter := bson.M{
-		"members.user_id": userID,
-	}
+	filter := UserRoomsFilter(userID, includeArchived)
 
-	opts := options.Find()
-	opts.SetLimit(int64(limit + 1)) // 多取一個用於判斷是否有更多
-	opts.SetSort(bson.D{{Key: "last_message_at", Value: -1}})
-
-	// 如果有游標，添加游標條件
-	if cursor != "" {
-		cursorTime, parseErr := time.Parse(time.RFC3339, cursor)
-		if parseErr == nil {
-			filter["last_message_at"] = bson.M{"$lt": cursorTime}
+	var memberships map[string]RoomMember
+	if s.members != nil {
+		if memberships, err = s.userMemberships(ctx, userID, includeArchived); err != nil {
+			return nil, "", false, err
 		}
+		roomIDs := make([]string, 0, len(memberships))
+		for roomID := range memberships {
+			roomIDs = append(roomIDs, roomID)
+		}
+		filter = bson.M{"id": bson.M{"$in": roomIDs}}
 	}
 
-	cursorResult, err := s.collection.Find(ctx, filter, opts)
+	// 置頂狀態和未讀狀態都屬於請求用戶，用聚合管道在數據庫中計算後再排序分頁
+	pipeline := UserRoomsPipeline(filter, userID, sort, s.members != nil, cursor, limit, time.Now())
+	cursorResult, err := s.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, "", false, err
 	}
 	defer cursorResult.Close(ctx)
 
 	rooms = []*ChatRoom{}
-	for cursorResult.Next(ctx) {
-		var room ChatRoom
-		if err := cursorResult.Decode(&room); err != nil {
-			return nil, "", false, err
-		}
-		rooms = append(rooms, &room)
+	if err := cursorResult.All(ctx, &rooms); err != nil {
+		return nil, "", false, err
 	}
 
 	// 檢查是否有更多數據
@@ -179,14 +445,139 @@ func (s *ChatRoomStore) ListUserRooms(
 
 	// 生成下一個游標
 	if hasMore && len(rooms) > 0 {
-		nextCursor = rooms[len(rooms)-1].LastMessageAt.Format(time.RFC3339)
+		nextCursor = roomListCursorFor(sort, rooms[len(rooms)-1])
+	}
+
+	if s.members != nil {
+		if err := s.hydrateUserRooms(ctx, rooms, memberships); err != nil {
+			return nil, "", false, err
+		}
 	}
 
 	return rooms, nextCursor, hasMore, nil
 }
 
+// RoomNameFilter 構建名稱唯一性檢查的查詢條件
+// 私聊不參與名稱唯一性檢查；mode 為 none 或未知值時返回 nil，表示不需要檢查
+func RoomNameFilter(mode, name, ownerID string) bson.M {
+	filter := bson.M{
+		"name": name,
+		"type": bson.M{"$ne": roomTypeDirect},
+	}
+
+	switch mode {
+	case NameUniquenessGlobal:
+		return filter
+	case NameUniquenessPerOwner:
+		filter["owner_id"] = ownerID
+		return filter
+	default:
+		return nil
+	}
+}
+
+// NameExists 檢查聊天室名稱是否已被使用
+func (s *ChatRoomStore) NameExists(ctx context.Context, mode, name, ownerID string) (bool, error) {
+	filter := RoomNameFilter(mode, name, ownerID)
+	if filter == nil {
+		return false, nil
+	}
+
+	count, err := s.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// ListRecentlyActiveRoomIDs 按最後訊息時間倒序列出最近活躍的聊天室 ID
+func (s *ChatRoomStore) ListRecentlyActiveRoomIDs(ctx context.Context, limit int) ([]string, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "last_message_at", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"id": 1})
+
+	cursor, err := s.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rooms []struct {
+		ID string `bson:"id"`
+	}
+	if err := cursor.All(ctx, &rooms); err != nil {
+		return nil, err
+	}
+
+	roomIDs := make([]string, 0, len(rooms))
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	return roomIDs, nil
+}
+
+// ListUserRoomIDs 列出用戶所屬的全部聊天室 ID（包括已封存的聊天室）
+func (s *ChatRoomStore) ListUserRoomIDs(ctx context.Context, userID string) ([]string, error) {
+	if s.members != nil {
+		return s.userRoomIDsInCollection(ctx, userID)
+	}
+
+	opts := options.Find().SetProjection(bson.M{"id": 1})
+
+	cursor, err := s.collection.Find(ctx, bson.M{"members.user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rooms []struct {
+		ID string `bson:"id"`
+	}
+	if err := cursor.All(ctx, &rooms); err != nil {
+		return nil, err
+	}
+
+	roomIDs := make([]string, 0, len(rooms))
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	return roomIDs, nil
+}
+
+// ListRoomTypes 獲取所有聊天室的類型（room_id -> type）
+func (s *ChatRoomStore) ListRoomTypes(ctx context.Context) (map[string]string, error) {
+	opts := options.Find().SetProjection(bson.M{"id": 1, "type": 1})
+
+	cursor, err := s.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rooms []struct {
+		ID   string `bson:"id"`
+		Type string `bson:"type"`
+	}
+	if err := cursor.All(ctx, &rooms); err != nil {
+		return nil, err
+	}
+
+	types := make(map[string]string, len(rooms))
+	for _, room := range rooms {
+		types[room.ID] = room.Type
+	}
+	return types, nil
+}
+
 // IsMember 檢查用戶是否是聊天室成員
 func (s *ChatRoomStore) IsMember(ctx context.Context, roomID, userID string) (bool, error) {
+	if s.members != nil {
+		count, err := s.members.CountDocuments(ctx, bson.M{"room_id": roomID, "user_id": userID})
+		return count > 0, err
+	}
+
 	count, err := s.collection.CountDocuments(ctx, bson.M{
 		"id":              roomID,
 		"members.user_id": userID,
@@ -204,9 +595,14 @@ func (s *ChatRoomStore) AddMember(ctx context.Context, roomID string, member *Ro
 	member.LastSeen = time.Now()
 	member.LastReadAt = time.Now()
 
+	if s.members != nil {
+		return s.addMemberToCollection(ctx, roomID, member, nil)
+	}
+
 	result, err := s.collection.UpdateOne(ctx, bson.M{"id": roomID}, bson.M{
 		"$push": bson.M{"members": member},
 		"$set":  bson.M{"updated_at": time.Now()},
+		"$inc":  incVersion,
 	})
 
 	if err != nil {
@@ -224,17 +620,245 @@ func (s *ChatRoomStore) AddMember(ctx context.Context, roomID string, member *Ro
 	return nil
 }
 
+// AddMemberWithLimit 在成員數量未達上限時添加成員
+// 檢查和添加在同一個更新中完成，並發加入也不會超過上限；maxMembers 為全局上限，
+// 聊天室設置了更小的 settings.max_members 時以聊天室設置為準，私聊固定最多兩個成員
+func (s *ChatRoomStore) AddMemberWithLimit(ctx context.Context, roomID string, member *RoomMember, maxMembers int) error {
+	now := time.Now()
+	member.JoinedAt = now
+	member.LastSeen = now
+	member.LastReadAt = now
+
+	if s.members != nil {
+		return s.addMemberToCollection(ctx, roomID, member, MemberCountLimitFilter(roomID, maxMembers))
+	}
+
+	result, err := s.collection.UpdateOne(ctx, MemberLimitFilter(roomID, member.UserID, maxMembers), bson.M{
+		"$push": bson.M{"members": member},
+		"$set":  bson.M{"updated_at": now},
+		"$inc":  incVersion,
+	})
+	if err != nil {
+		return fmt.Errorf("update failed: %v", err)
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+
+	// 沒有匹配時區分聊天室不存在、已經是成員和成員已滿
+	var room ChatRoom
+	err = s.collection.FindOne(ctx, bson.M{"id": roomID}, options.FindOne().SetProjection(bson.M{"members.user_id": 1})).Decode(&room)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("room not found: %s", roomID)
+	}
+	if err != nil {
+		return err
+	}
+	for i := range room.Members {
+		if room.Members[i].UserID == member.UserID {
+			return ErrAlreadyMember
+		}
+	}
+	return ErrRoomFull
+}
+
+// MemberLimitFilter 聊天室存在、用戶還不是成員且成員數量未達上限
+func MemberLimitFilter(roomID, userID string, maxMembers int) bson.M {
+	memberCount := bson.M{"$size": bson.M{"$ifNull": bson.A{"$members", bson.A{}}}}
+	return bson.M{
+		"id":              roomID,
+		"members.user_id": bson.M{"$ne": userID},
+		"$expr":           bson.M{"$lt": bson.A{memberCount, memberLimitExpr(maxMembers)}},
+	}
+}
+
+// memberLimitExpr 聊天室成員上限的聚合表達式：私聊固定為 2，其他聊天室取聊天室設置和全局上限中較小的一個
+func memberLimitExpr(maxMembers int) bson.M {
+	roomMax := bson.M{"$ifNull": bson.A{"$settings.max_members", 0}}
+	return bson.M{"$cond": bson.A{
+		bson.M{"$eq": bson.A{"$type", roomTypeDirect}},
+		directMaxMembers,
+		bson.M{"$cond": bson.A{
+			bson.M{"$gt": bson.A{roomMax, 0}},
+			bson.M{"$min": bson.A{roomMax, maxMembers}},
+			maxMembers,
+		}},
+	}}
+}
+
 // RemoveMember 移除成員
 func (s *ChatRoomStore) RemoveMember(ctx context.Context, roomID, userID string) error {
+	if s.members != nil {
+		return s.removeMemberFromCollection(ctx, roomID, userID)
+	}
+
 	_, err := s.collection.UpdateOne(ctx, bson.M{"id": roomID}, bson.M{
-		"$pull": bson.M{"members": bson.M{"user_id": userID}},
-		"$set":  bson.M{"updated_at": time.Now()},
+		"$pull":  bson.M{"members": bson.M{"user_id": userID}},
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$unset": bson.M{"direct_key": ""}, // 私聊不再是原來的兩個成員，之後可以重新創建
+		"$inc":   incVersion,
 	})
 	return err
 }
 
+// TransferOwnership 把擁有權從 fromUserID 轉讓給 toUserID，並把原擁有者的角色設為 fromRole（為空時不修改，用於原擁有者離開）
+// 內嵌成員時在同一次更新中確認新擁有者是成員；版本不一致或擁有者已變更時返回 ErrVersionConflict
+func (s *ChatRoomStore) TransferOwnership(ctx context.Context, roomID string, expectedVersion int64, fromUserID, toUserID, fromRole string) error {
+	filter := VersionFilter(roomID, expectedVersion)
+	filter["owner_id"] = fromUserID
+	set := bson.M{"owner_id": toUserID, "updated_at": time.Now()}
+	opts := options.UpdateOne()
+	if s.members == nil {
+		filter["members.user_id"] = toUserID
+		if fromRole != "" {
+			set["members.$[former].role"] = fromRole
+			opts.SetArrayFilters([]any{bson.M{"former.user_id": fromUserID}})
+		}
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, bson.M{"$set": set, "$inc": incVersion}, opts)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return s.versionMismatch(ctx, roomID)
+	}
+
+	if s.members != nil && fromRole != "" {
+		collection, memberFilter, _ := s.memberTarget(roomID, fromUserID)
+		if _, err := collection.UpdateOne(ctx, memberFilter, bson.M{"$set": bson.M{"role": fromRole}}); err != nil {
+			return fmt.Errorf("update former owner role failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateLastReadAt 推進成員的已讀位置（只會向前移動）
+func (s *ChatRoomStore) UpdateLastReadAt(ctx context.Context, roomID, userID string, readAt time.Time) error {
+	collection, filter, prefix := s.memberTarget(roomID, userID)
+	_, err := collection.UpdateOne(ctx, filter, bson.M{
+		"$max": bson.M{prefix + "last_read_at": readAt},
+	})
+	return err
+}
+
+// GetMemberLastReadAt 獲取成員的已讀位置，用戶不是成員時返回 mongo.ErrNoDocuments
+func (s *ChatRoomStore) GetMemberLastReadAt(ctx context.Context, roomID, userID string) (time.Time, error) {
+	collection, filter, _ := s.memberTarget(roomID, userID)
+	if s.members != nil {
+		var member RoomMember
+		opts := options.FindOne().SetProjection(bson.M{"last_read_at": 1})
+		if err := collection.FindOne(ctx, filter, opts).Decode(&member); err != nil {
+			return time.Time{}, err
+		}
+		return member.LastReadAt, nil
+	}
+
+	// 內嵌成員時只投影匹配的成員，避免讀取整個成員數組
+	var room ChatRoom
+	opts := options.FindOne().SetProjection(bson.M{"members.$": 1})
+	if err := collection.FindOne(ctx, filter, opts).Decode(&room); err != nil {
+		return time.Time{}, err
+	}
+	if len(room.Members) == 0 {
+		return time.Time{}, mongo.ErrNoDocuments
+	}
+	return room.Members[0].LastReadAt, nil
+}
+
+// UpdateAllLastReadAt 推進用戶在所有所屬聊天室中的已讀位置（只會向前移動）
+func (s *ChatRoomStore) UpdateAllLastReadAt(ctx context.Context, userID string, readAt time.Time) error {
+	if s.members != nil {
+		_, err := s.members.UpdateMany(ctx, bson.M{"user_id": userID}, bson.M{"$max": bson.M{"last_read_at": readAt}})
+		return err
+	}
+
+	opts := options.UpdateMany().SetArrayFilters([]any{bson.M{"member.user_id": userID}})
+	_, err := s.collection.UpdateMany(ctx, bson.M{"members.user_id": userID}, bson.M{
+		"$max": bson.M{"members.$[member].last_read_at": readAt},
+	}, opts)
+	return err
+}
+
+// TouchMemberLastSeen 更新用戶在所有所屬聊天室中的最後在線時間（只會向前移動）
+func (s *ChatRoomStore) TouchMemberLastSeen(ctx context.Context, userID string, seenAt time.Time) error {
+	if s.members != nil {
+		_, err := s.members.UpdateMany(ctx, bson.M{"user_id": userID}, bson.M{"$max": bson.M{"last_seen": seenAt}})
+		return err
+	}
+
+	opts := options.UpdateMany().SetArrayFilters([]any{bson.M{"member.user_id": userID}})
+	_, err := s.collection.UpdateMany(ctx, bson.M{"members.user_id": userID}, bson.M{
+		"$max": bson.M{"members.$[member].last_seen": seenAt},
+	}, opts)
+	return err
+}
+
+// SetMemberMute 設置成員的靜音狀態，只影響該成員
+// muted 為 true 表示永久靜音；mutedUntil 非 nil 表示靜音到指定時間；兩者都為空表示取消靜音
+func (s *ChatRoomStore) SetMemberMute(ctx context.Context, roomID, userID string, muted bool, mutedUntil *time.Time) error {
+	collection, filter, prefix := s.memberTarget(roomID, userID)
+
+	set := bson.M{prefix + "muted": muted}
+	update := bson.M{"$set": set}
+	if mutedUntil != nil {
+		set[prefix+"muted_until"] = *mutedUntil
+	} else {
+		update["$unset"] = bson.M{prefix + "muted_until": ""}
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("update failed: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("member not found: %s in room %s", userID, roomID)
+	}
+	return nil
+}
+
+// SetMemberArchived 設置成員的封存狀態，只影響該成員
+// 置頂和封存互斥：封存時同時取消置頂
+func (s *ChatRoomStore) SetMemberArchived(ctx context.Context, roomID, userID string, archived bool) error {
+	collection, filter, prefix := s.memberTarget(roomID, userID)
+	set := bson.M{prefix + "archived": archived}
+	if archived {
+		set[prefix+"pinned"] = false
+	}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("update failed: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("member not found: %s in room %s", userID, roomID)
+	}
+	return nil
+}
+
+// SetMemberPinned 設置成員的置頂狀態，只影響該成員
+// 置頂和封存互斥：置頂時同時取消封存，讓聊天室回到列表中
+func (s *ChatRoomStore) SetMemberPinned(ctx context.Context, roomID, userID string, pinned bool) error {
+	collection, filter, prefix := s.memberTarget(roomID, userID)
+	set := bson.M{prefix + "pinned": pinned}
+	if pinned {
+		set[prefix+"archived"] = false
+	}
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return fmt.Errorf("update failed: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("member not found: %s in room %s", userID, roomID)
+	}
+	return nil
+}
+
 // GetMembers 獲取聊天室成員
 func (s *ChatRoomStore) GetMembers(ctx context.Context, roomID string) ([]RoomMember, error) {
+	if s.members != nil {
+		return s.loadMembers(ctx, roomID)
+	}
+
 	objectID, err := bson.ObjectIDFromHex(roomID)
 	if err != nil {
 		return nil, err
@@ -251,6 +875,11 @@ func (s *ChatRoomStore) GetMembers(ctx context.Context, roomID string) ([]RoomMe
 
 // GetMemberCount 獲取聊天室成員數量
 func (s *ChatRoomStore) GetMemberCount(ctx context.Context, roomID string) (int, error) {
+	if s.members != nil {
+		count, err := s.members.CountDocuments(ctx, bson.M{"room_id": roomID})
+		return int(count), err
+	}
+
 	objectID, err := bson.ObjectIDFromHex(roomID)
 	if err != nil {
 		return 0, err
@@ -0,0 +1,269 @@
+package chatroom
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// matchesRoomNameFilter 在內存中模擬 RoomNameFilter 的查詢語義
+func matchesRoomNameFilter(filter bson.M, room *ChatRoom) bool {
+	if room.Name != filter["name"] {
+		return false
+	}
+	if typeCond, ok := filter["type"].(bson.M); ok && room.Type == typeCond["$ne"] {
+		return false
+	}
+	if ownerID, ok := filter["owner_id"]; ok && room.OwnerID != ownerID {
+		return false
+	}
+	return true
+}
+
+func TestRoomNameFilter(t *testing.T) {
+	existing := []*ChatRoom{
+		{Name: "general", Type: "group", OwnerID: "alice"},
+		{Name: "alice-bob", Type: "direct", OwnerID: "alice"},
+	}
+
+	tests := []struct {
+		name     string
+		mode     string
+		roomName string
+		ownerID  string
+		conflict bool
+	}{
+		{"none allows duplicates", NameUniquenessNone, "general", "alice", false},
+		{"unknown mode behaves like none", "strict", "general", "alice", false},
+		{"per owner conflict", NameUniquenessPerOwner, "general", "alice", true},
+		{"per owner allows other owner", NameUniquenessPerOwner, "general", "bob", false},
+		{"global conflict across owners", NameUniquenessGlobal, "general", "bob", true},
+		{"global allows new name", NameUniquenessGlobal, "random", "bob", false},
+		{"direct chats are exempt", NameUniquenessGlobal, "alice-bob", "carol", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := RoomNameFilter(tt.mode, tt.roomName, tt.ownerID)
+
+			conflict := false
+			if filter != nil {
+				for _, room := range existing {
+					if matchesRoomNameFilter(filter, room) {
+						conflict = true
+						break
+					}
+				}
+			}
+
+			if conflict != tt.conflict {
+				t.Errorf("conflict = %v, want %v (filter %v)", conflict, tt.conflict, filter)
+			}
+		})
+	}
+}
+
+// matchesUserRoomsFilter 在內存中模擬 UserRoomsFilter 的查詢語義
+func matchesUserRoomsFilter(filter bson.M, room *ChatRoom) bool {
+	if userID, ok := filter["members.user_id"]; ok {
+		return room.FindMember(userID.(string)) != nil
+	}
+
+	elem := filter["members"].(bson.M)["$elemMatch"].(bson.M)
+	member := room.FindMember(elem["user_id"].(string))
+	return member != nil && member.Archived != elem["archived"].(bson.M)["$ne"]
+}
+
+func TestUserRoomsFilter(t *testing.T) {
+	rooms := []*ChatRoom{
+		{ID: "active", Members: []RoomMember{{UserID: "alice"}, {UserID: "bob", Archived: true}}},
+		{ID: "archived", Members: []RoomMember{{UserID: "alice", Archived: true}, {UserID: "bob"}}},
+		{ID: "other", Members: []RoomMember{{UserID: "carol"}}},
+	}
+
+	tests := []struct {
+		name            string
+		userID          string
+		includeArchived bool
+		want            []string
+	}{
+		{"excludes own archived rooms", "alice", false, []string{"active"}},
+		{"includes archived rooms", "alice", true, []string{"active", "archived"}},
+		{"archive is per member", "bob", false, []string{"archived"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := UserRoomsFilter(tt.userID, tt.includeArchived)
+
+			var got []string
+			for _, room := range rooms {
+				if matchesUserRoomsFilter(filter, room) {
+					got = append(got, room.ID)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rooms = %v, want %v (filter %v)", got, tt.want, filter)
+			}
+		})
+	}
+}
+
+func TestDirectKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		memberIDs []string
+		want      string
+	}{
+		{"sorted", []string{"alice", "bob"}, "5:alice|bob"},
+		{"order independent", []string{"bob", "alice"}, "5:alice|bob"},
+		{"duplicates removed", []string{"bob", "alice", "bob"}, "5:alice|bob"},
+		{"separator in id is unambiguous", []string{"a|b", "c"}, "3:a|b|c"},
+		{"single member", []string{"alice", "alice"}, ""},
+		{"group", []string{"alice", "bob", "carol"}, ""},
+		{"empty id", []string{"", "bob"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DirectKey(tt.memberIDs); got != tt.want {
+				t.Errorf("DirectKey(%v) = %q, want %q", tt.memberIDs, got, tt.want)
+			}
+		})
+	}
+
+	if DirectKey([]string{"a|b", "c"}) == DirectKey([]string{"a", "b|c"}) {
+		t.Error("expected different keys for different member pairs")
+	}
+}
+
+func TestMemberLimitFilter(t *testing.T) {
+	filter := MemberLimitFilter("room-1", "alice", 100)
+
+	if filter["id"] != "room-1" {
+		t.Errorf("id = %v, want room-1", filter["id"])
+	}
+	if got := filter["members.user_id"]; !reflect.DeepEqual(got, bson.M{"$ne": "alice"}) {
+		t.Errorf("members.user_id = %v, want $ne alice", got)
+	}
+
+	// 私聊固定上限為 2，其他聊天室取聊天室設置和全局上限中較小的一個
+	cond := filter["$expr"].(bson.M)["$lt"].(bson.A)[1].(bson.M)["$cond"].(bson.A)
+	if cond[1] != directMaxMembers {
+		t.Errorf("direct limit = %v, want %d", cond[1], directMaxMembers)
+	}
+	roomCond := cond[2].(bson.M)["$cond"].(bson.A)
+	if got := roomCond[1].(bson.M)["$min"].(bson.A)[1]; got != 100 {
+		t.Errorf("room limit cap = %v, want 100", got)
+	}
+	if roomCond[2] != 100 {
+		t.Errorf("default room limit = %v, want 100", roomCond[2])
+	}
+}
+
+func TestVersionFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int64
+		want    bson.M
+	}{
+		{"legacy rooms without version match zero", 0, bson.M{"id": "room-1", "version": bson.M{"$in": bson.A{int64(0), nil}}}},
+		{"exact version", 3, bson.M{"id": "room-1", "version": int64(3)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VersionFilter("room-1", tt.version); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("VersionFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemberCountLimitFilter(t *testing.T) {
+	filter := MemberCountLimitFilter("room-1", 50)
+
+	if filter["id"] != "room-1" {
+		t.Errorf("id = %v, want room-1", filter["id"])
+	}
+	cmp := filter["$expr"].(bson.M)["$lt"].(bson.A)
+	if want := (bson.M{"$ifNull": bson.A{"$member_count", 0}}); !reflect.DeepEqual(cmp[0], want) {
+		t.Errorf("member count = %v, want %v", cmp[0], want)
+	}
+	if want := memberLimitExpr(50); !reflect.DeepEqual(cmp[1], want) {
+		t.Errorf("limit = %v, want %v", cmp[1], want)
+	}
+}
+
+func TestDetachMembers(t *testing.T) {
+	room := &ChatRoom{Members: []RoomMember{{UserID: "alice"}, {UserID: "bob"}}}
+
+	members := detachMembers(room)
+	if len(members) != 2 || room.Members != nil || room.MemberCount != 2 {
+		t.Errorf("detachMembers() = %v, room members %v, count %d", members, room.Members, room.MemberCount)
+	}
+
+	// 成員存放在獨立集合時，聊天室文檔中不能再寫入成員數組
+	raw, err := bson.Marshal(room)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if _, err := bson.Raw(raw).LookupErr("members"); err == nil {
+		t.Error("detached room still marshals members")
+	}
+}
+
+func TestCascadeCollections(t *testing.T) {
+	// 客戶端只在執行操作時才連接數據庫，這裡不需要可用的 MongoDB
+	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() { _ = client.Disconnect(context.Background()) }()
+
+	store := NewChatRoomStore(client.Database("chat_test"))
+	if got := store.cascadeCollections(); len(got) != 0 {
+		t.Errorf("cascadeCollections() with embedded members = %d collections, want 0", len(got))
+	}
+
+	store.UseMemberCollection()
+	got := store.cascadeCollections()
+	if len(got) != 1 || got[0].Name() != memberCollectionName {
+		t.Errorf("cascadeCollections() with member collection = %v, want [%s]", got, memberCollectionName)
+	}
+}
+
+func TestChatRoom_LastMessageKeyVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		keyID string
+		want  int
+	}{
+		{"legacy preview without key id", "", legacyKeyVersion},
+		{"recorded version", "3", 3},
+		{"malformed key id", "not-a-version", 0},
+		{"negative key id", "-1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := &ChatRoom{LastMessageKeyID: tt.keyID}
+			if got := room.LastMessageKeyVersion(); got != tt.want {
+				t.Errorf("LastMessageKeyVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncryptionKeyID(t *testing.T) {
+	if got := EncryptionKeyID(2); got != "2" {
+		t.Errorf("EncryptionKeyID(2) = %q, want \"2\"", got)
+	}
+	if got := EncryptionKeyID(0); got != "" {
+		t.Errorf("EncryptionKeyID(0) = %q, want empty", got)
+	}
+}
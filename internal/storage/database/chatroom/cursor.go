@@ -0,0 +1,112 @@
+package chatroom
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// messageCursor 分頁游標（排序時間 + _id）
+// 訊息和管理員聊天室列表使用 created_at；用戶聊天室列表早期也用它記錄 last_message_at（見 roomListCursor）
+// 只用時間做游標時，同一時間戳的訊息會在翻頁時被跳過或重複，因此用 _id 作為第二排序鍵
+type messageCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id,omitempty"` // 為空表示舊格式的純時間游標
+}
+
+// EncodeMessageCursor 將訊息位置編碼為游標
+func EncodeMessageCursor(createdAt time.Time, id string) string {
+	data, _ := json.Marshal(messageCursor{CreatedAt: createdAt.UTC(), ID: id}) // #nosec G104 -- marshaling a fixed struct cannot fail
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// messageCursorFor 訊息本身的游標
+func messageCursorFor(msg *Message) string {
+	return EncodeMessageCursor(msg.CreatedAt, msg.GetID())
+}
+
+// ValidateMessageCursor 檢查游標格式是否有效
+func ValidateMessageCursor(cursor string) error {
+	_, err := decodeMessageCursor(cursor)
+	return err
+}
+
+// decodeMessageCursor 解析游標，兼容已發出的 RFC3339 純時間游標
+func decodeMessageCursor(cursor string) (messageCursor, error) {
+	if t, err := time.Parse(time.RFC3339, cursor); err == nil {
+		return messageCursor{CreatedAt: t}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return messageCursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var c messageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return messageCursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	if c.ID != "" {
+		if _, err := bson.ObjectIDFromHex(c.ID); err != nil {
+			return messageCursor{}, fmt.Errorf("invalid cursor id: %w", err)
+		}
+	}
+
+	return c, nil
+}
+
+// olderThan 構建早於游標位置的過濾條件：created_at < t OR (created_at == t AND _id < id)
+func (c messageCursor) olderThan() bson.M {
+	return c.compare("created_at", "$lt")
+}
+
+// newerThan 構建晚於游標位置的過濾條件：created_at > t OR (created_at == t AND _id > id)
+func (c messageCursor) newerThan() bson.M {
+	return c.compare("created_at", "$gt")
+}
+
+// compare 構建複合比較條件：field op t OR (field == t AND _id op id)
+func (c messageCursor) compare(field, op string) bson.M {
+	if c.ID == "" {
+		// 舊格式游標只有時間
+		return bson.M{field: bson.M{op: c.CreatedAt}}
+	}
+
+	objectID, _ := bson.ObjectIDFromHex(c.ID) // #nosec G104 -- validated in decodeMessageCursor
+	return bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: c.CreatedAt}},
+		bson.M{field: c.CreatedAt, "_id": bson.M{op: objectID}},
+	}}
+}
+
+// applyOlderThanCursor 將「早於游標」的條件加入過濾條件
+// 使用 $and 組合，避免覆蓋已有的時間範圍或 $or 條件；無法解析的游標被忽略（從第一頁開始）
+func applyOlderThanCursor(filter bson.M, field, cursor string) {
+	if cursor == "" {
+		return
+	}
+	c, err := decodeMessageCursor(cursor)
+	if err != nil {
+		return
+	}
+
+	conditions, _ := filter["$and"].(bson.A)
+	filter["$and"] = append(conditions, c.compare(field, "$lt"))
+}
+
+// applyNewerThanCursor 將「晚於游標」的條件加入過濾條件（按創建時間正序翻頁時使用）
+func applyNewerThanCursor(filter bson.M, field, cursor string) {
+	if cursor == "" {
+		return
+	}
+	c, err := decodeMessageCursor(cursor)
+	if err != nil {
+		return
+	}
+
+	conditions, _ := filter["$and"].(bson.A)
+	filter["$and"] = append(conditions, c.compare(field, "$gt"))
+}
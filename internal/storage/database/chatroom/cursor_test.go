@@ -0,0 +1,117 @@
+package chatroom
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMessageCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 123000000, time.UTC)
+	id := bson.NewObjectID().Hex()
+
+	c, err := decodeMessageCursor(EncodeMessageCursor(createdAt, id))
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if !c.CreatedAt.Equal(createdAt) || c.ID != id {
+		t.Errorf("round trip mismatch: got %v %s", c.CreatedAt, c.ID)
+	}
+}
+
+func TestMessageCursor_LegacyTimestamp(t *testing.T) {
+	c, err := decodeMessageCursor("2025-01-02T03:04:05Z")
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if c.ID != "" {
+		t.Errorf("legacy cursor should not carry an id, got %s", c.ID)
+	}
+
+	filter := c.olderThan()
+	if _, ok := filter["$or"]; ok {
+		t.Error("legacy cursor should use a plain time comparison")
+	}
+	if cond, ok := filter["created_at"].(bson.M); !ok || cond["$lt"] == nil {
+		t.Errorf("unexpected legacy filter: %v", filter)
+	}
+}
+
+func TestMessageCursor_Invalid(t *testing.T) {
+	for _, cursor := range []string{"not a cursor", EncodeMessageCursor(time.Now(), "not-an-object-id")} {
+		if _, err := decodeMessageCursor(cursor); err == nil {
+			t.Errorf("expected error for cursor %q", cursor)
+		}
+	}
+}
+
+func TestMessageCursor_CompoundComparison(t *testing.T) {
+	createdAt := time.Now().UTC()
+	id := bson.NewObjectID()
+	c := messageCursor{CreatedAt: createdAt, ID: id.Hex()}
+
+	tests := map[string]struct {
+		filter bson.M
+		op     string
+	}{
+		"older": {c.olderThan(), "$lt"},
+		"newer": {c.newerThan(), "$gt"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			or, ok := tt.filter["$or"].(bson.A)
+			if !ok || len(or) != 2 {
+				t.Fatalf("expected two $or branches, got %v", tt.filter)
+			}
+
+			// created_at op t
+			timeBranch := or[0].(bson.M)["created_at"].(bson.M)
+			if !timeBranch[tt.op].(time.Time).Equal(createdAt) {
+				t.Errorf("unexpected time branch: %v", or[0])
+			}
+
+			// created_at == t AND _id op id（同一時間戳用 _id 排序）
+			tieBranch := or[1].(bson.M)
+			if !tieBranch["created_at"].(time.Time).Equal(createdAt) {
+				t.Errorf("tiebreaker should match the same timestamp: %v", tieBranch)
+			}
+			if tieBranch["_id"].(bson.M)[tt.op] != id {
+				t.Errorf("tiebreaker should compare _id with %s: %v", tt.op, tieBranch)
+			}
+		})
+	}
+}
+
+func TestApplyOlderThanCursor_KeepsExistingConditions(t *testing.T) {
+	since := time.Now().Add(-time.Hour)
+	filter := bson.M{
+		"room_id":    "room-1",
+		"created_at": bson.M{"$gte": since},
+	}
+
+	applyOlderThanCursor(filter, "created_at", EncodeMessageCursor(time.Now(), bson.NewObjectID().Hex()))
+
+	// 游標條件不應覆蓋時間範圍
+	if cond, ok := filter["created_at"].(bson.M); !ok || cond["$gte"] == nil {
+		t.Errorf("time range should be preserved: %v", filter)
+	}
+	and, ok := filter["$and"].(bson.A)
+	if !ok || len(and) != 1 {
+		t.Fatalf("expected one $and condition, got %v", filter["$and"])
+	}
+	if _, ok := and[0].(bson.M)["$or"]; !ok {
+		t.Errorf("expected compound cursor condition, got %v", and[0])
+	}
+}
+
+func TestApplyOlderThanCursor_IgnoresEmptyAndInvalid(t *testing.T) {
+	for _, cursor := range []string{"", "not a cursor"} {
+		filter := bson.M{"room_id": "room-1"}
+		applyOlderThanCursor(filter, "created_at", cursor)
+		if _, ok := filter["$and"]; ok {
+			t.Errorf("cursor %q should not add conditions: %v", cursor, filter)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package chatroom
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DirectKey 私聊的唯一鍵：兩個成員 ID 排序後連接（第一個 ID 帶長度前綴，避免 ID 中含有分隔符時產生歧義）
+// 成員去重後不是兩個人時返回空字符串（不是一對一私聊）
+func DirectKey(memberIDs []string) string {
+	ids := slices.Clone(memberIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+	if len(ids) != 2 || ids[0] == "" {
+		return ""
+	}
+	return strconv.Itoa(len(ids[0])) + ":" + ids[0] + "|" + ids[1]
+}
+
+// CreateDirect 創建私聊，相同兩個成員的私聊已存在時返回現有聊天室（created 為 false）
+// 依賴 direct_key 唯一索引和 upsert，並發創建也只會產生一個聊天室
+func (s *ChatRoomStore) CreateDirect(ctx context.Context, room *ChatRoom) (*ChatRoom, bool, error) {
+	_id := bson.NewObjectID()
+	now := time.Now()
+	room._ID = _id
+	room.ID = _id.Hex()
+	room.CreatedAt = now
+	room.UpdatedAt = now
+	room.LastMessageAt = now
+
+	var members []RoomMember
+	if s.members != nil {
+		members = detachMembers(room)
+		defer func() { room.Members = members }()
+	}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result ChatRoom
+	err := s.collection.FindOneAndUpdate(ctx, bson.M{"direct_key": room.DirectKey}, bson.M{"$setOnInsert": room}, opts).Decode(&result)
+	if mongo.IsDuplicateKeyError(err) {
+		// 兩個 upsert 同時插入時其中一個會違反唯一索引，此時另一個已經創建成功
+		err = s.collection.FindOne(ctx, bson.M{"direct_key": room.DirectKey}).Decode(&result)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	created := result.ID == room.ID
+	if s.members != nil {
+		if created {
+			err = s.insertMemberDocs(ctx, result.ID, members)
+			result.Members = members
+		} else {
+			result.Members, err = s.loadMembers(ctx, result.ID)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return &result, created, nil
+}
+
+// BackfillDirectKeys 為舊的私聊補上 direct_key
+// 已經存在相同成員的私聊（歷史上的重複私聊）時保留先補上的一個，返回被跳過的聊天室 ID
+func (s *ChatRoomStore) BackfillDirectKeys(ctx context.Context) (updated int, duplicates []string, err error) {
+	opts := options.Find().
+		SetProjection(bson.M{"id": 1, "members.user_id": 1}).
+		SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := s.collection.Find(ctx, bson.M{
+		"type":       roomTypeDirect,
+		"direct_key": bson.M{"$exists": false},
+	}, opts)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var room ChatRoom
+		if err := cursor.Decode(&room); err != nil {
+			return updated, duplicates, err
+		}
+
+		if s.members != nil && len(room.Members) == 0 {
+			if room.Members, err = s.loadMembers(ctx, room.ID); err != nil {
+				return updated, duplicates, err
+			}
+		}
+
+		memberIDs := make([]string, len(room.Members))
+		for i := range room.Members {
+			memberIDs[i] = room.Members[i].UserID
+		}
+		key := DirectKey(memberIDs)
+		if key == "" || len(room.Members) != 2 {
+			continue
+		}
+
+		_, err := s.collection.UpdateOne(ctx, bson.M{"id": room.ID}, bson.M{"$set": bson.M{"direct_key": key}})
+		switch {
+		case mongo.IsDuplicateKeyError(err):
+			duplicates = append(duplicates, room.ID)
+		case err != nil:
+			return updated, duplicates, err
+		default:
+			updated++
+		}
+	}
+	return updated, duplicates, cursor.Err()
+}
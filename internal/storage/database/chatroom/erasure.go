@@ -0,0 +1,189 @@
+package chatroom
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// 用戶數據刪除（GDPR 被遺忘權）後使用的佔位值
+const (
+	ErasedUserID         = "deleted_user" // 替換訊息發送者、轉發來源和聊天室擁有者
+	ErasedMessageContent = "[訊息已刪除]"      // 替換被刪除用戶的訊息內容
+)
+
+// CountUserMessages 統計用戶發送的訊息數量
+func (s *MessageStore) CountUserMessages(ctx context.Context, userID string) (int64, error) {
+	return s.collection.CountDocuments(ctx, UserMessagesFilter(userID))
+}
+
+// CountUserReferences 統計含有用戶已讀/送達回執、提及或轉發來源的訊息數量
+func (s *MessageStore) CountUserReferences(ctx context.Context, userID string) (int64, error) {
+	return s.collection.CountDocuments(ctx, bson.M{"$or": bson.A{
+		UserReceiptsFilter(userID),
+		UserForwardSourceFilter(userID),
+	}})
+}
+
+// AnonymizeUserMessages 分批把用戶發送的訊息替換為佔位訊息（保留訊息位置，清除內容、附件和加密信息）
+func (s *MessageStore) AnonymizeUserMessages(ctx context.Context, userID string, batchSize int) (int64, error) {
+	return s.updateInBatches(ctx, UserMessagesFilter(userID), bson.M{
+		"$set": bson.M{
+			"sender_id":  ErasedUserID,
+			"content":    ErasedMessageContent,
+			"type":       "system",
+			"metadata":   MessageMetadata{},
+			"updated_at": time.Now(),
+		},
+		"$unset": bson.M{
+			"encryption_key_id": "",
+			"encrypted_content": "",
+			"signature":         "",
+			"mentions":          "",
+			"custom_data":       "",
+		},
+	}, batchSize)
+}
+
+// RemoveUserReferences 分批刪除用戶的已讀/送達回執和提及，並匿名化以用戶訊息為來源的轉發
+// 返回修改的訊息數量
+func (s *MessageStore) RemoveUserReferences(ctx context.Context, userID string, batchSize int) (int64, error) {
+	receipts, err := s.updateInBatches(ctx, UserReceiptsFilter(userID), bson.M{
+		"$pull": bson.M{
+			"read_by":      bson.M{"user_id": userID},
+			"delivered_to": bson.M{"user_id": userID},
+			"mentions":     userID,
+		},
+		"$set": bson.M{"updated_at": time.Now()},
+	}, batchSize)
+	if err != nil {
+		return receipts, err
+	}
+
+	forwards, err := s.updateInBatches(ctx, UserForwardSourceFilter(userID), bson.M{
+		"$set": bson.M{"forwarded_from.2": ErasedUserID, "updated_at": time.Now()},
+	}, batchSize)
+	return receipts + forwards, err
+}
+
+// updateInBatches 按批更新符合條件的訊息，直到沒有剩餘
+// update 必須讓訊息不再符合 filter，否則會重複處理同一批
+func (s *MessageStore) updateInBatches(ctx context.Context, filter, update bson.M, batchSize int) (int64, error) {
+	var modified int64
+	for {
+		ids, err := s.findBatchIDs(ctx, filter, batchSize)
+		if err != nil || len(ids) == 0 {
+			return modified, err
+		}
+
+		result, err := s.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, update)
+		if err != nil {
+			return modified, err
+		}
+		modified += result.ModifiedCount
+
+		if len(ids) < batchSize {
+			return modified, nil
+		}
+	}
+}
+
+// UserMessagesFilter 用戶發送的訊息
+func UserMessagesFilter(userID string) bson.M {
+	return bson.M{"sender_id": userID}
+}
+
+// UserReceiptsFilter 含有用戶已讀/送達回執或提及的訊息
+func UserReceiptsFilter(userID string) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"read_by.user_id": userID},
+		bson.M{"delivered_to.user_id": userID},
+		bson.M{"mentions": userID},
+	}}
+}
+
+// UserForwardSourceFilter 轉發自用戶訊息的訊息（forwarded_from 為 [room_id, message_id, sender_id]）
+func UserForwardSourceFilter(userID string) bson.M {
+	return bson.M{"forwarded_from.2": userID}
+}
+
+// ListSoleMemberDirectRooms 獲取只剩該用戶一個成員的私聊
+func (s *ChatRoomStore) ListSoleMemberDirectRooms(ctx context.Context, userID string) ([]string, error) {
+	filter := bson.M{
+		"type":            roomTypeDirect,
+		"members":         bson.M{"$size": 1},
+		"members.user_id": userID,
+	}
+	if s.members != nil {
+		roomIDs, err := s.userRoomIDsInCollection(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		filter = bson.M{
+			"id":           bson.M{"$in": roomIDs},
+			"type":         roomTypeDirect,
+			"member_count": 1,
+		}
+	}
+
+	cursor, err := s.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rooms []ChatRoom
+	if err := cursor.All(ctx, &rooms); err != nil {
+		return nil, err
+	}
+
+	roomIDs := make([]string, 0, len(rooms))
+	for i := range rooms {
+		roomIDs = append(roomIDs, rooms[i].ID)
+	}
+	return roomIDs, nil
+}
+
+// RemoveUserFromAllRooms 把用戶從所有聊天室的成員列表中移除，並匿名化其擁有的聊天室
+// 返回移除成員的聊天室數量
+func (s *ChatRoomStore) RemoveUserFromAllRooms(ctx context.Context, userID string) (int64, error) {
+	now := time.Now()
+
+	var removed int64
+	if s.members != nil {
+		roomIDs, err := s.userRoomIDsInCollection(ctx, userID)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := s.members.DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+			return 0, err
+		}
+		result, err := s.collection.UpdateMany(ctx, bson.M{"id": bson.M{"$in": roomIDs}}, bson.M{
+			"$inc":   bson.M{"member_count": -1, "version": int64(1)},
+			"$set":   bson.M{"updated_at": now},
+			"$unset": bson.M{"direct_key": ""},
+		})
+		if err != nil {
+			return 0, err
+		}
+		removed = result.ModifiedCount
+	} else {
+		result, err := s.collection.UpdateMany(ctx, bson.M{"members.user_id": userID}, bson.M{
+			"$pull":  bson.M{"members": bson.M{"user_id": userID}},
+			"$set":   bson.M{"updated_at": now},
+			"$unset": bson.M{"direct_key": ""},
+			"$inc":   incVersion,
+		})
+		if err != nil {
+			return 0, err
+		}
+		removed = result.ModifiedCount
+	}
+
+	_, err := s.collection.UpdateMany(ctx, bson.M{"owner_id": userID}, bson.M{
+		"$set": bson.M{"owner_id": ErasedUserID, "updated_at": now},
+		"$inc": incVersion,
+	})
+	return removed, err
+}
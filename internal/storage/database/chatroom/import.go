@@ -0,0 +1,138 @@
+package chatroom
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// NewMessageAt 創建指定時間的 Message 實例（導入歷史訊息時保留原始發送時間）
+// ID 的時間戳部分與 created_at 一致，分頁時同一時間的訊息仍按 ID 排序
+func NewMessageAt(createdAt time.Time) Message {
+	_id := bson.NewObjectIDFromTimestamp(createdAt)
+	createdAt = createdAt.UTC()
+	return Message{_ID: _id, ID: _id.Hex(), CreatedAt: createdAt, UpdatedAt: createdAt}
+}
+
+// insertManyFailures 把 InsertMany（unordered）的錯誤轉換為每個文檔的失敗原因，key 為文檔在批次中的下標
+// 返回的 error 不為 nil 時表示整批寫入失敗（例如連接中斷或 write concern 錯誤），無法確定單個文檔的結果
+func insertManyFailures(err error) (map[int]error, error) {
+	if err == nil {
+		return nil, nil
+	}
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil || len(bulkErr.WriteErrors) == 0 {
+		return nil, err
+	}
+	failures := make(map[int]error, len(bulkErr.WriteErrors))
+	for _, writeErr := range bulkErr.WriteErrors {
+		failures[writeErr.Index] = writeErr
+	}
+	return failures, nil
+}
+
+// InsertMany 批量寫入導入的訊息（保留調用方設置的 ID 和時間）
+// 使用 unordered 寫入，單個文檔失敗不影響其他文檔，失敗的文檔按下標返回
+func (s *MessageStore) InsertMany(ctx context.Context, messages []*Message) (map[int]error, error) {
+	docs := make([]any, len(messages))
+	for i, message := range messages {
+		if message.Status == "" {
+			message.Status = MessageStatusSent
+		}
+		if message.ReadBy == nil {
+			message.ReadBy = []MessageReadBy{}
+		}
+		if message.DeliveredTo == nil {
+			message.DeliveredTo = []MessageDeliveredTo{}
+		}
+		docs[i] = message
+	}
+
+	_, err := s.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	return insertManyFailures(err)
+}
+
+// InsertMany 批量寫入導入的聊天室（保留調用方設置的創建時間）
+// 使用 unordered 寫入，單個聊天室失敗（例如私聊重複）不影響其他聊天室，失敗的聊天室按下標返回；
+// 成員存放在獨立集合時，成員寫入失敗的聊天室會被刪除並記為失敗
+func (s *ChatRoomStore) InsertMany(ctx context.Context, rooms []*ChatRoom) (map[int]error, error) {
+	docs := make([]any, len(rooms))
+	members := make([][]RoomMember, len(rooms))
+	for i, room := range rooms {
+		_id := bson.NewObjectID()
+		room._ID = _id
+		room.ID = _id.Hex()
+		if s.members != nil {
+			members[i] = detachMembers(room)
+		}
+		docs[i] = room
+	}
+	if s.members != nil {
+		defer func() {
+			for i, room := range rooms {
+				room.Members = members[i]
+			}
+		}()
+	}
+
+	_, err := s.collection.InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	failures, err := insertManyFailures(err)
+	if err != nil || s.members == nil {
+		return failures, err
+	}
+
+	for i, room := range rooms {
+		if _, failed := failures[i]; failed {
+			continue
+		}
+		if err := s.insertMemberDocs(ctx, room.ID, members[i]); err != nil {
+			_, _ = s.collection.DeleteOne(ctx, bson.M{"id": room.ID}) // #nosec G104 -- best-effort cleanup, reported as failed
+			if failures == nil {
+				failures = make(map[int]error)
+			}
+			failures[i] = err
+		}
+	}
+	return failures, nil
+}
+
+// ExistingRoomIDs 返回給定 ID 中存在的聊天室
+func (s *ChatRoomStore) ExistingRoomIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	cursor, err := s.collection.Find(ctx,
+		bson.M{"id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	existing := make(map[string]bool, len(ids))
+	for cursor.Next(ctx) {
+		var room struct {
+			ID string `bson:"id"`
+		}
+		if err := cursor.Decode(&room); err != nil {
+			return nil, err
+		}
+		existing[room.ID] = true
+	}
+	return existing, cursor.Err()
+}
+
+// AdvanceLastMessage 導入歷史訊息後更新聊天室最後訊息（只在比現有的最後訊息更新時覆蓋）
+// 與 UpdateLastMessage 不同，導入不會取消成員的封存；keyID 為加密預覽的密鑰版本
+func (s *ChatRoomStore) AdvanceLastMessage(ctx context.Context, id string, at time.Time, lastMessage, keyID string) error {
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"id": id, "last_message_at": bson.M{"$lt": at}},
+		bson.M{"$set": bson.M{
+			"last_message":        lastMessage,
+			"last_message_key_id": keyID,
+			"last_message_time":   at,
+			"last_message_at":     at,
+		}})
+	return err
+}
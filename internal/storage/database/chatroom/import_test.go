@@ -0,0 +1,54 @@
+package chatroom
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestNewMessageAt(t *testing.T) {
+	createdAt := time.Date(2020, 3, 4, 5, 6, 7, 0, time.FixedZone("UTC+8", 8*3600))
+	m := NewMessageAt(createdAt)
+
+	if !m.CreatedAt.Equal(createdAt) || m.CreatedAt.Location() != time.UTC {
+		t.Errorf("CreatedAt = %v, want %v in UTC", m.CreatedAt, createdAt)
+	}
+	id, err := bson.ObjectIDFromHex(m.ID)
+	if err != nil {
+		t.Fatalf("ID %q is not an ObjectID: %v", m.ID, err)
+	}
+	if !id.Timestamp().Equal(createdAt) {
+		t.Errorf("ID timestamp = %v, want %v", id.Timestamp(), createdAt)
+	}
+}
+
+func TestInsertManyFailures(t *testing.T) {
+	failures, err := insertManyFailures(nil)
+	if failures != nil || err != nil {
+		t.Errorf("insertManyFailures(nil) = %v, %v", failures, err)
+	}
+
+	bulkErr := mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{
+		{WriteError: mongo.WriteError{Index: 2, Code: 11000, Message: "duplicate key"}},
+		{WriteError: mongo.WriteError{Index: 5, Code: 121, Message: "validation failed"}},
+	}}
+	failures, err = insertManyFailures(bulkErr)
+	if err != nil {
+		t.Fatalf("insertManyFailures() error = %v", err)
+	}
+	if len(failures) != 2 || !mongo.IsDuplicateKeyError(failures[2]) || failures[5] == nil {
+		t.Errorf("failures = %v, want indexes 2 and 5", failures)
+	}
+
+	// write concern 錯誤或連接錯誤無法確定單個文檔的結果，整批失敗
+	wcErr := mongo.BulkWriteException{WriteConcernError: &mongo.WriteConcernError{Code: 64}}
+	if _, err := insertManyFailures(wcErr); err == nil {
+		t.Error("write concern error should fail the whole batch")
+	}
+	if _, err := insertManyFailures(errors.New("connection reset")); err == nil {
+		t.Error("plain error should fail the whole batch")
+	}
+}
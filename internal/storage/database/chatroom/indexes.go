@@ -2,17 +2,98 @@ package chatroom
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
-// CreateIndexes 創建數據庫索引以優化查詢性能
-func CreateIndexes(ctx context.Context, db *mongo.Database) error {
-	// 消息集合索引
-	messagesCollection := db.Collection("messages")
+// IndexResult 單個索引的創建結果
+type IndexResult struct {
+	Collection string
+	Name       string
+	Created    bool  // false 表示索引已存在
+	Err        error // 創建失敗的原因
+}
+
+// CreateIndexes 創建數據庫索引以優化查詢性能（冪等：已存在的索引會跳過）
+// 每個索引單獨創建，一個索引衝突不會影響其他索引；返回每個索引的結果，任何索引失敗時返回合併的錯誤
+func CreateIndexes(ctx context.Context, db *mongo.Database) ([]IndexResult, error) {
+	var results []IndexResult
+	results = append(results, ensureIndexes(ctx, db.Collection("messages"), messageIndexModels())...)
+	results = append(results, ensureIndexes(ctx, db.Collection("chat_rooms"), roomIndexModels())...)
+	results = append(results, ensureIndexes(ctx, db.Collection(memberCollectionName), memberIndexModels())...)
+	results = append(results, ensureIndexes(ctx, db.Collection(scheduledCollectionName), scheduledIndexModels())...)
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", r.Collection, r.Name, r.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// ensureIndexes 創建集合中尚不存在的索引（按名稱判斷）
+func ensureIndexes(ctx context.Context, collection *mongo.Collection, models []mongo.IndexModel) []IndexResult {
+	results := make([]IndexResult, 0, len(models))
+
+	existing, err := indexNames(ctx, collection)
+	for _, model := range models {
+		result := IndexResult{Collection: collection.Name(), Name: indexModelName(model)}
+		switch {
+		case err != nil:
+			result.Err = err
+		case existing[result.Name]:
+		default:
+			if _, result.Err = collection.Indexes().CreateOne(ctx, model); result.Err == nil {
+				result.Created = true
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// indexNames 獲取集合中已存在的索引名稱
+func indexNames(ctx context.Context, collection *mongo.Collection) (map[string]bool, error) {
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
 
+	var indexes []struct {
+		Name string `bson:"name"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(indexes))
+	for _, index := range indexes {
+		names[index.Name] = true
+	}
+	return names, nil
+}
+
+// indexModelName 索引模型的名稱（所有索引都明確設置了名稱）
+func indexModelName(model mongo.IndexModel) string {
+	if model.Options != nil {
+		var opts options.IndexOptions
+		for _, apply := range model.Options.List() {
+			_ = apply(&opts)
+		}
+		if opts.Name != nil {
+			return *opts.Name
+		}
+	}
+	return ""
+}
+
+// messageIndexModels 消息集合索引
+func messageIndexModels() []mongo.IndexModel {
 	// 1. 聊天室 ID + 創建時間複合索引（最重要的索引）
 	roomTimeIndex := mongo.IndexModel{
 		Keys: bson.D{
@@ -56,23 +137,57 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 		Options: options.Index().SetName("read_status_idx"),
 	}
 
-	// 創建消息索引
-	messageIndexes := []mongo.IndexModel{
+	// 6. 創建時間索引（保留期限清理按時間查找最舊的訊息）
+	messageCreatedAtIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "created_at", Value: 1},
+		},
+		Options: options.Index().SetName("message_created_at_idx"),
+	}
+
+	// 7. 限時訊息 TTL 索引（到達 expires_at 後由 MongoDB 自動刪除，沒有 expires_at 的訊息不受影響）
+	messageExpiresAtIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "expires_at", Value: 1},
+		},
+		Options: options.Index().SetName("message_expires_at_ttl_idx").SetExpireAfterSeconds(0),
+	}
+
+	// 8. 發送冪等鍵唯一索引（只索引帶冪等鍵的訊息，同一發送者重試時不會創建重複訊息）
+	idempotencyKeyIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "sender_id", Value: 1},
+			{Key: "idempotency_key", Value: 1},
+		},
+		Options: options.Index().
+			SetName("message_idempotency_key_unique_idx").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"idempotency_key": bson.M{"$type": "string"}}),
+	}
+
+	// 9. 回覆索引（展開討論串、統計回覆數量；稀疏索引，只索引回覆訊息）
+	replyToIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "reply_to_message_id", Value: 1},
+		},
+		Options: options.Index().SetName("message_reply_to_idx").SetSparse(true),
+	}
+
+	return []mongo.IndexModel{
 		roomTimeIndex,
 		senderTimeIndex,
 		messageTypeIndex,
 		textSearchIndex,
 		readStatusIndex,
+		messageCreatedAtIndex,
+		messageExpiresAtIndex,
+		idempotencyKeyIndex,
+		replyToIndex,
 	}
+}
 
-	_, err := messagesCollection.Indexes().CreateMany(ctx, messageIndexes)
-	if err != nil {
-		return err
-	}
-
-	// 聊天室集合索引
-	chatRoomsCollection := db.Collection("chat_rooms")
-
+// roomIndexModels 聊天室集合索引
+func roomIndexModels() []mongo.IndexModel {
 	// 1. 聊天室類型索引
 	roomTypeIndex := mongo.IndexModel{
 		Keys: bson.D{
@@ -113,21 +228,51 @@ func CreateIndexes(ctx context.Context, db *mongo.Database) error {
 		Options: options.Index().SetName("created_at_idx"),
 	}
 
-	// 創建聊天室索引
-	roomIndexes := []mongo.IndexModel{
+	// 6. 私聊唯一鍵索引（只索引有 direct_key 的私聊，保證相同兩個成員只有一個私聊）
+	directKeyIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "direct_key", Value: 1},
+		},
+		Options: options.Index().
+			SetName("direct_key_unique_idx").
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"direct_key": bson.M{"$type": "string"}}),
+	}
+
+	return []mongo.IndexModel{
 		roomTypeIndex,
 		ownerIndex,
 		memberIndex,
 		lastMessageIndex,
 		createdAtIndex,
+		directKeyIndex,
 	}
+}
 
-	_, err = chatRoomsCollection.Indexes().CreateMany(ctx, roomIndexes)
-	if err != nil {
-		return err
+// memberIndexModels 獨立成員集合索引（未啟用獨立成員集合時集合為空，索引不佔空間）
+func memberIndexModels() []mongo.IndexModel {
+	// 1. 聊天室成員唯一索引（成員檢查、分頁列出成員，並防止並發加入產生重複成員）
+	roomMemberIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "room_id", Value: 1},
+			{Key: "user_id", Value: 1},
+		},
+		Options: options.Index().SetName("room_member_unique_idx").SetUnique(true),
 	}
 
-	return nil
+	// 2. 用戶聊天室索引（用戶的聊天室列表、已讀和在線時間更新）
+	userRoomsIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "user_id", Value: 1},
+			{Key: "archived", Value: 1},
+		},
+		Options: options.Index().SetName("member_user_rooms_idx"),
+	}
+
+	return []mongo.IndexModel{
+		roomMemberIndex,
+		userRoomsIndex,
+	}
 }
 
 // GetIndexStats 獲取索引統計信息
@@ -0,0 +1,32 @@
+package chatroom
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestIndexModels_HaveUniqueNames(t *testing.T) {
+	collections := map[string][]mongo.IndexModel{
+		"messages":     messageIndexModels(),
+		"chat_rooms":   roomIndexModels(),
+		"room_members": memberIndexModels(),
+		"scheduled":    scheduledIndexModels(),
+	}
+
+	// 冪等創建按名稱判斷索引是否已存在，每個索引都必須有唯一的名稱
+	for collection, models := range collections {
+		seen := make(map[string]bool)
+		for i, model := range models {
+			name := indexModelName(model)
+			if name == "" {
+				t.Errorf("%s index %d has no name", collection, i)
+				continue
+			}
+			if seen[name] {
+				t.Errorf("%s index name %q is duplicated", collection, name)
+			}
+			seen[name] = true
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package chatroom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// memberCollectionName 獨立成員集合名稱
+const memberCollectionName = "room_members"
+
+// memberDoc 獨立成員集合中的文檔（每個聊天室成員一條，room_id + user_id 唯一）
+type memberDoc struct {
+	RoomID     string `bson:"room_id"`
+	RoomMember `bson:",inline"`
+}
+
+// UseMemberCollection 把成員存放在獨立的 room_members 集合中
+// 大型聊天室的成員數組不會再撐大聊天室文檔（MongoDB 單個文檔上限 16MB），
+// 成員變更也只需要寫入一條成員文檔；舊聊天室需要先用 MigrateMembersToCollection 遷移
+func (s *ChatRoomStore) UseMemberCollection() {
+	s.members = s.collection.Database().Collection(memberCollectionName)
+}
+
+// MemberCollectionEnabled 是否使用獨立成員集合
+func (s *ChatRoomStore) MemberCollectionEnabled() bool {
+	return s.members != nil
+}
+
+// memberTarget 定位單個成員的集合、查詢條件和字段前綴（內嵌時使用位置操作符 members.$）
+func (s *ChatRoomStore) memberTarget(roomID, userID string) (*mongo.Collection, bson.M, string) {
+	if s.members != nil {
+		return s.members, bson.M{"room_id": roomID, "user_id": userID}, ""
+	}
+	return s.collection, bson.M{"id": roomID, "members.user_id": userID}, "members.$."
+}
+
+// insertMemberDocs 寫入聊天室的成員文檔
+func (s *ChatRoomStore) insertMemberDocs(ctx context.Context, roomID string, members []RoomMember) error {
+	if len(members) == 0 {
+		return nil
+	}
+	docs := make([]any, len(members))
+	for i := range members {
+		docs[i] = memberDoc{RoomID: roomID, RoomMember: members[i]}
+	}
+	_, err := s.members.InsertMany(ctx, docs)
+	return err
+}
+
+// detachMembers 把成員從聊天室文檔中取出（寫入獨立集合前調用），返回取出的成員
+func detachMembers(room *ChatRoom) []RoomMember {
+	members := room.Members
+	room.Members = nil
+	room.MemberCount = len(members)
+	return members
+}
+
+// findMemberDocs 查詢成員文檔
+func (s *ChatRoomStore) findMemberDocs(ctx context.Context, filter bson.M, opts ...options.Lister[options.FindOptions]) ([]RoomMember, error) {
+	cursor, err := s.members.Find(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []memberDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	members := make([]RoomMember, len(docs))
+	for i := range docs {
+		members[i] = docs[i].RoomMember
+	}
+	return members, nil
+}
+
+// loadMembers 從獨立集合讀取聊天室的全部成員
+func (s *ChatRoomStore) loadMembers(ctx context.Context, roomID string) ([]RoomMember, error) {
+	return s.findMemberDocs(ctx, bson.M{"room_id": roomID}, options.Find().SetSort(bson.D{{Key: "joined_at", Value: 1}}))
+}
+
+// userMemberships 用戶在各聊天室中的成員信息（includeArchived 為 false 時不包含已封存的聊天室）
+func (s *ChatRoomStore) userMemberships(ctx context.Context, userID string, includeArchived bool) (map[string]RoomMember, error) {
+	filter := bson.M{"user_id": userID}
+	if !includeArchived {
+		filter["archived"] = bson.M{"$ne": true}
+	}
+
+	cursor, err := s.members.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []memberDoc
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	memberships := make(map[string]RoomMember, len(docs))
+	for i := range docs {
+		memberships[docs[i].RoomID] = docs[i].RoomMember
+	}
+	return memberships, nil
+}
+
+// userRoomIDsInCollection 用戶所屬的全部聊天室 ID
+func (s *ChatRoomStore) userRoomIDsInCollection(ctx context.Context, userID string) ([]string, error) {
+	memberships, err := s.userMemberships(ctx, userID, true)
+	if err != nil {
+		return nil, err
+	}
+	roomIDs := make([]string, 0, len(memberships))
+	for roomID := range memberships {
+		roomIDs = append(roomIDs, roomID)
+	}
+	return roomIDs, nil
+}
+
+// hydrateUserRooms 為聊天室列表填充成員：私聊填充全部成員（客戶端需要顯示對方），
+// 群組只填充請求用戶自己（完整成員列表使用 ListMembers 分頁獲取）
+func (s *ChatRoomStore) hydrateUserRooms(ctx context.Context, rooms []*ChatRoom, memberships map[string]RoomMember) error {
+	for _, room := range rooms {
+		if room.Type != roomTypeDirect {
+			room.Members = []RoomMember{memberships[room.ID]}
+			continue
+		}
+		members, err := s.loadMembers(ctx, room.ID)
+		if err != nil {
+			return err
+		}
+		room.Members = members
+	}
+	return nil
+}
+
+// addMemberToCollection 添加成員文檔，並更新聊天室的成員數量
+// 成員數量先在聊天室文檔上原子地預留（limitFilter 為 nil 時不限制），寫入成員文檔失敗時歸還
+func (s *ChatRoomStore) addMemberToCollection(ctx context.Context, roomID string, member *RoomMember, limitFilter bson.M) error {
+	isMember, err := s.IsMember(ctx, roomID, member.UserID)
+	if err != nil {
+		return err
+	}
+	if isMember {
+		return ErrAlreadyMember
+	}
+
+	filter := limitFilter
+	if filter == nil {
+		filter = bson.M{"id": roomID}
+	}
+	result, err := s.collection.UpdateOne(ctx, filter, bson.M{
+		"$inc": bson.M{"member_count": 1, "version": int64(1)},
+		"$set": bson.M{"updated_at": time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("update failed: %v", err)
+	}
+	if result.MatchedCount == 0 {
+		count, err := s.collection.CountDocuments(ctx, bson.M{"id": roomID})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("room not found: %s", roomID)
+		}
+		return ErrRoomFull
+	}
+
+	_, err = s.members.InsertOne(ctx, memberDoc{RoomID: roomID, RoomMember: *member})
+	if err == nil {
+		return nil
+	}
+
+	// 歸還預留的成員數量；並發加入時唯一索引保證只有一條成員文檔
+	if _, undoErr := s.collection.UpdateOne(ctx, bson.M{"id": roomID}, bson.M{"$inc": bson.M{"member_count": -1}}); undoErr != nil {
+		err = errors.Join(err, undoErr)
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrAlreadyMember
+	}
+	return err
+}
+
+// MemberCountLimitFilter 成員存放在獨立集合時，聊天室存在且成員數量（member_count）未達上限
+func MemberCountLimitFilter(roomID string, maxMembers int) bson.M {
+	memberCount := bson.M{"$ifNull": bson.A{"$member_count", 0}}
+	return bson.M{
+		"id":    roomID,
+		"$expr": bson.M{"$lt": bson.A{memberCount, memberLimitExpr(maxMembers)}},
+	}
+}
+
+// removeMemberFromCollection 刪除成員文檔，並更新聊天室的成員數量
+func (s *ChatRoomStore) removeMemberFromCollection(ctx context.Context, roomID, userID string) error {
+	result, err := s.members.DeleteOne(ctx, bson.M{"room_id": roomID, "user_id": userID})
+	if err != nil || result.DeletedCount == 0 {
+		return err
+	}
+
+	_, err = s.collection.UpdateOne(ctx, bson.M{"id": roomID}, bson.M{
+		"$inc":   bson.M{"member_count": -1, "version": int64(1)},
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$unset": bson.M{"direct_key": ""}, // 私聊不再是原來的兩個成員，之後可以重新創建
+	})
+	return err
+}
+
+// MigrateMembersToCollection 把聊天室文檔中內嵌的成員遷移到獨立集合（冪等，可以重複執行）
+// 每個聊天室先寫入成員文檔（已存在的跳過），再按新寫入的數量增加 member_count 並移除內嵌數組
+func (s *ChatRoomStore) MigrateMembersToCollection(ctx context.Context) (migrated int, err error) {
+	if s.members == nil {
+		return 0, errors.New("member collection is not enabled")
+	}
+
+	opts := options.Find().SetProjection(bson.M{"id": 1, "members": 1})
+	cursor, err := s.collection.Find(ctx, bson.M{"members.0": bson.M{"$exists": true}}, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var room ChatRoom
+		if err := cursor.Decode(&room); err != nil {
+			return migrated, err
+		}
+
+		models := make([]mongo.WriteModel, len(room.Members))
+		for i := range room.Members {
+			models[i] = mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"room_id": room.ID, "user_id": room.Members[i].UserID}).
+				SetUpdate(bson.M{"$setOnInsert": memberDoc{RoomID: room.ID, RoomMember: room.Members[i]}}).
+				SetUpsert(true)
+		}
+		result, err := s.members.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return migrated, err
+		}
+
+		_, err = s.collection.UpdateOne(ctx, bson.M{"id": room.ID}, bson.M{
+			"$inc":   bson.M{"member_count": result.UpsertedCount},
+			"$unset": bson.M{"members": ""},
+		})
+		if err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, cursor.Err()
+}
@@ -0,0 +1,123 @@
+package chatroom
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// MemberQuery 分頁查詢聊天室成員的條件
+type MemberQuery struct {
+	Limit          int    // 每頁數量
+	Cursor         string // 上一頁返回的游標
+	Role           string // 只返回指定角色（為空時不過濾）
+	UsernameFilter bson.M // 用戶名條件，由調用方使用 database.SafePrefixRegexQuery 構建（為空時不過濾）
+}
+
+// EncodeMemberCursor 將成員位置編碼為游標（成員按 user_id 排序）
+func EncodeMemberCursor(userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID))
+}
+
+// decodeMemberCursor 解析成員游標，返回上一頁最後一個成員的 user_id
+func decodeMemberCursor(cursor string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(data) == 0 {
+		return "", fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	return string(data), nil
+}
+
+// MemberPipeline 構建分頁查詢聊天室成員的聚合管道
+// 在數據庫中展開成員數組並過濾、排序和截取，只返回一頁成員；多取一個用於判斷是否有更多
+func MemberPipeline(roomID string, query MemberQuery) (mongo.Pipeline, error) {
+	match, err := memberQueryFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"id": roomID}}},
+		{{Key: "$unwind", Value: "$members"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$members"}}},
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "user_id", Value: 1}}}},
+		{{Key: "$limit", Value: int64(query.Limit + 1)}},
+	}, nil
+}
+
+// memberQueryFilter 成員的過濾條件（游標、角色和用戶名）
+func memberQueryFilter(query MemberQuery) (bson.M, error) {
+	filter := bson.M{}
+	if query.Cursor != "" {
+		afterUserID, err := decodeMemberCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter["user_id"] = bson.M{"$gt": afterUserID}
+	}
+	if query.Role != "" {
+		filter["role"] = query.Role
+	}
+	if query.UsernameFilter != nil {
+		filter["username"] = query.UsernameFilter
+	}
+	return filter, nil
+}
+
+// ListMembers 分頁列出聊天室成員，支持按角色和用戶名前綴過濾
+func (s *ChatRoomStore) ListMembers(ctx context.Context, roomID string, query MemberQuery) (members []RoomMember, nextCursor string, hasMore bool, err error) {
+	if s.members != nil {
+		members, err = s.listMembersInCollection(ctx, roomID, query)
+	} else {
+		members, err = s.listEmbeddedMembers(ctx, roomID, query)
+	}
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	hasMore = len(members) > query.Limit
+	if hasMore {
+		members = members[:query.Limit]
+		nextCursor = EncodeMemberCursor(members[len(members)-1].UserID)
+	}
+	return members, nextCursor, hasMore, nil
+}
+
+// listEmbeddedMembers 在聚合中展開聊天室文檔的成員數組並分頁
+func (s *ChatRoomStore) listEmbeddedMembers(ctx context.Context, roomID string, query MemberQuery) ([]RoomMember, error) {
+	pipeline, err := MemberPipeline(roomID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	members := []RoomMember{}
+	if err := cursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// listMembersInCollection 在獨立成員集合中分頁（使用 room_id + user_id 索引）
+func (s *ChatRoomStore) listMembersInCollection(ctx context.Context, roomID string, query MemberQuery) ([]RoomMember, error) {
+	filter, err := memberQueryFilter(query)
+	if err != nil {
+		return nil, err
+	}
+	filter["room_id"] = roomID
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "user_id", Value: 1}}).
+		SetLimit(int64(query.Limit + 1))
+	return s.findMemberDocs(ctx, filter, opts)
+}
@@ -0,0 +1,52 @@
+package chatroom
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMemberCursor(t *testing.T) {
+	for _, userID := range []string{"alice", "user|with/odd+chars", "用戶"} {
+		got, err := decodeMemberCursor(EncodeMemberCursor(userID))
+		if err != nil || got != userID {
+			t.Errorf("round trip %q = %q, %v", userID, got, err)
+		}
+	}
+
+	for _, cursor := range []string{"", "!!!"} {
+		if _, err := decodeMemberCursor(cursor); err == nil {
+			t.Errorf("decodeMemberCursor(%q) expected error", cursor)
+		}
+	}
+}
+
+func TestMemberPipeline(t *testing.T) {
+	pipeline, err := MemberPipeline("room-1", MemberQuery{
+		Limit:          20,
+		Cursor:         EncodeMemberCursor("bob"),
+		Role:           "admin",
+		UsernameFilter: bson.M{"$regex": `^a\.b`, "$options": "i"},
+	})
+	if err != nil {
+		t.Fatalf("MemberPipeline() error = %v", err)
+	}
+
+	match := pipeline[3][0].Value.(bson.M)
+	if got := match["user_id"]; got.(bson.M)["$gt"] != "bob" {
+		t.Errorf("user_id = %v, want $gt bob", got)
+	}
+	if match["role"] != "admin" {
+		t.Errorf("role = %v, want admin", match["role"])
+	}
+	if got := match["username"].(bson.M)["$regex"]; got != `^a\.b` {
+		t.Errorf("username regex = %v, want ^a\\.b", got)
+	}
+	if got := pipeline[5][0].Value; got != int64(21) {
+		t.Errorf("limit = %v, want 21", got)
+	}
+
+	if _, err := MemberPipeline("room-1", MemberQuery{Limit: 20, Cursor: "!!!"}); err == nil {
+		t.Error("expected error for invalid cursor")
+	}
+}
@@ -2,6 +2,9 @@ package chatroom
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"chat-gateway/internal/platform/config"
@@ -14,13 +17,19 @@ import (
 // MessageRepository 消息倉儲接口
 type MessageRepository interface {
 	Create(ctx context.Context, message *Message) error
+	InsertMany(ctx context.Context, messages []*Message) (map[int]error, error)
 	GetByID(ctx context.Context, id string) (*Message, error)
+	GetByIdempotencyKey(ctx context.Context, senderID, key string) (*Message, error)
+	ReleaseIdempotencyKey(ctx context.Context, id, key string) error
 	GetByRoomID(ctx context.Context, roomID string, limit int, cursor string, since, until *time.Time) ([]*Message, string, bool, error)
 	Update(ctx context.Context, id string, update map[string]interface{}) error
 	Delete(ctx context.Context, id string) error
 	MarkAsRead(ctx context.Context, roomID, userID string, messageID *string) error
 	MarkAsDelivered(ctx context.Context, roomID, userID string, messageID *string) error
 	GetUnreadCount(ctx context.Context, userID string, roomID *string) (int, error)
+	CountUnreadSince(ctx context.Context, roomID, userID string, since time.Time) (int, error)
+	CountUnreadByReceipts(ctx context.Context, roomID, userID string) (int, error)
+	CountUnreadInRooms(ctx context.Context, userID string, sinceByRoom map[string]time.Time, receiptRooms []string) (map[string]int, error)
 	Search(
 		ctx context.Context,
 		roomID, query string,
@@ -49,7 +58,11 @@ type Message struct {
 	EncryptedContent string                 `bson:"encrypted_content,omitempty" json:"encrypted_content,omitempty"`
 	Signature        string                 `bson:"signature,omitempty" json:"signature,omitempty"`
 	ReplyToMessageID string                 `bson:"reply_to_message_id,omitempty" json:"reply_to_message_id,omitempty"`
-	ForwardedFrom    []string               `bson:"forwarded_from,omitempty" json:"forwarded_from,omitempty"`
+	Mentions         []string               `bson:"mentions,omitempty" json:"mentions,omitempty"`
+	ForwardedFrom    []string               `bson:"forwarded_from,omitempty" json:"forwarded_from,omitempty"` // [room_id, message_id, sender_id]
+	Pinned           bool                   `bson:"pinned,omitempty" json:"pinned,omitempty"`                 // 置頂訊息不會被保留期限清理刪除
+	ExpiresAt        *time.Time             `bson:"expires_at,omitempty" json:"expires_at,omitempty"`         // 限時訊息的過期時間，到期後由 TTL 索引刪除
+	IdempotencyKey   string                 `bson:"idempotency_key,omitempty" json:"-"`                       // 發送者提供的冪等鍵（同一發送者唯一）
 	ReadBy           []MessageReadBy        `bson:"read_by,omitempty" json:"read_by,omitempty"`
 	DeliveredTo      []MessageDeliveredTo   `bson:"delivered_to,omitempty" json:"delivered_to,omitempty"`
 	CustomData       map[string]interface{} `bson:"custom_data,omitempty" json:"custom_data,omitempty"`
@@ -60,6 +73,58 @@ func (m *Message) GetID() string {
 	return m.ID
 }
 
+// legacyKeyVersion 記錄密鑰版本之前寫入的消息所使用的密鑰版本
+const legacyKeyVersion = 1
+
+// KeyVersion 獲取加密此消息的密鑰版本
+// 未記錄時視為舊數據，返回 legacyKeyVersion；格式錯誤時返回 0，表示使用當前活躍密鑰
+func (m *Message) KeyVersion() int {
+	if m.EncryptionKeyID == "" {
+		return legacyKeyVersion
+	}
+	version, err := strconv.Atoi(m.EncryptionKeyID)
+	if err != nil || version < 0 {
+		return 0
+	}
+	return version
+}
+
+// Expired 限時訊息是否已過期（TTL 索引刪除有延遲，過期後到刪除前的訊息不應再返回）
+func (m *Message) Expired(now time.Time) bool {
+	return m.ExpiresAt != nil && !m.ExpiresAt.After(now)
+}
+
+// notExpiredFilter 排除已過期的限時訊息（沒有 expires_at 的訊息不會過期）
+func notExpiredFilter(now time.Time) bson.M {
+	return bson.M{"$not": bson.M{"$lte": now}}
+}
+
+// SetForwardedFrom 記錄轉發來源（原始聊天室、消息和發送者）
+func (m *Message) SetForwardedFrom(roomID, messageID, senderID string) {
+	m.ForwardedFrom = []string{roomID, messageID, senderID}
+}
+
+// ForwardSource 獲取轉發來源，不是轉發的消息時 ok 為 false
+func (m *Message) ForwardSource() (roomID, messageID, senderID string, ok bool) {
+	if len(m.ForwardedFrom) != 3 {
+		return "", "", "", false
+	}
+	return m.ForwardedFrom[0], m.ForwardedFrom[1], m.ForwardedFrom[2], true
+}
+
+// SetKeyVersion 記錄加密此消息的密鑰版本（0 表示未加密）
+func (m *Message) SetKeyVersion(version int) {
+	m.EncryptionKeyID = EncryptionKeyID(version)
+}
+
+// EncryptionKeyID 密鑰版本的存儲形式（0 表示未加密，存為空字符串）
+func EncryptionKeyID(version int) string {
+	if version <= 0 {
+		return ""
+	}
+	return strconv.Itoa(version)
+}
+
 // NewMessage 創建新的 Message 實例
 func NewMessage() Message {
 	_id := bson.NewObjectID()
@@ -101,6 +166,9 @@ type MessageStore struct {
 	collection *mongo.Collection
 }
 
+// 編譯期檢查存儲實作滿足倉儲接口，避免簽名不一致
+var _ MessageRepository = (*MessageStore)(nil)
+
 // NewMessageStore 創建新的消息存儲
 func NewMessageStore(db *mongo.Database) *MessageStore {
 	return &MessageStore{
@@ -108,6 +176,52 @@ func NewMessageStore(db *mongo.Database) *MessageStore {
 	}
 }
 
+// 訊息狀態（所有接收者中最低的狀態：全部送達才是 delivered，全部已讀才是 read）
+const (
+	MessageStatusSent      = "sent"
+	MessageStatusDelivered = "delivered"
+	MessageStatusRead      = "read"
+)
+
+// AggregateStatus 根據聊天室成員計算訊息狀態（發送者不計入接收者）
+// 已讀視為已送達；沒有其他成員時保持 sent
+func (m *Message) AggregateStatus(memberIDs []string) string {
+	read := make(map[string]bool, len(m.ReadBy))
+	for _, r := range m.ReadBy {
+		read[r.UserID] = true
+	}
+	delivered := make(map[string]bool, len(m.DeliveredTo))
+	for _, d := range m.DeliveredTo {
+		delivered[d.UserID] = true
+	}
+
+	recipients := 0
+	allRead, allDelivered := true, true
+	for _, userID := range memberIDs {
+		if userID == m.SenderID {
+			continue
+		}
+		recipients++
+		if !read[userID] {
+			allRead = false
+			if !delivered[userID] {
+				allDelivered = false
+			}
+		}
+	}
+
+	switch {
+	case recipients == 0:
+		return MessageStatusSent
+	case allRead:
+		return MessageStatusRead
+	case allDelivered:
+		return MessageStatusDelivered
+	default:
+		return MessageStatusSent
+	}
+}
+
 // Create 創建消息
 func (s *MessageStore) Create(ctx context.Context, message *Message) error {
 	_id := bson.NewObjectID()
@@ -115,7 +229,7 @@ func (s *MessageStore) Create(ctx context.Context, message *Message) error {
 	message.ID = _id.Hex()
 	message.CreatedAt = time.Now()
 	message.UpdatedAt = time.Now()
-	message.Status = "sent"
+	message.Status = MessageStatusSent
 
 	// 初始化已讀和送達列表
 	if message.ReadBy == nil {
@@ -129,6 +243,39 @@ func (s *MessageStore) Create(ctx context.Context, message *Message) error {
 	return err
 }
 
+// CreateWithID 使用預先分配的 ID 創建消息（例如定時訊息），ID 已存在時返回主鍵衝突錯誤
+func (s *MessageStore) CreateWithID(ctx context.Context, id string, message *Message) error {
+	objectID, err := parseObjectID(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	message._ID = objectID
+	message.ID = id
+	message.CreatedAt = now
+	message.UpdatedAt = now
+	message.Status = MessageStatusSent
+	if message.ReadBy == nil {
+		message.ReadBy = []MessageReadBy{}
+	}
+	if message.DeliveredTo == nil {
+		message.DeliveredTo = []MessageDeliveredTo{}
+	}
+
+	_, err = s.collection.InsertOne(ctx, message)
+	return err
+}
+
+// DeleteByRoomID 刪除聊天室的所有消息，返回刪除數量
+func (s *MessageStore) DeleteByRoomID(ctx context.Context, roomID string) (int64, error) {
+	result, err := s.collection.DeleteMany(ctx, bson.M{"room_id": roomID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
 // GetByID 根據 ID 獲取消息
 func (s *MessageStore) GetByID(ctx context.Context, id string) (*Message, error) {
 	objectID, err := parseObjectID(id)
@@ -144,6 +291,46 @@ func (s *MessageStore) GetByID(ctx context.Context, id string) (*Message, error)
 	return &message, nil
 }
 
+// GetByIdempotencyKey 根據發送者和冪等鍵獲取消息（用於重試的發送請求返回第一次創建的消息）
+func (s *MessageStore) GetByIdempotencyKey(ctx context.Context, senderID, key string) (*Message, error) {
+	var message Message
+	err := s.collection.FindOne(ctx, bson.M{"sender_id": senderID, "idempotency_key": key}).Decode(&message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// ReleaseIdempotencyKey 移除消息上已過有效期的冪等鍵，讓同一個鍵可以用於新消息
+func (s *MessageStore) ReleaseIdempotencyKey(ctx context.Context, id, key string) error {
+	objectID, err := parseObjectID(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "idempotency_key": key},
+		bson.M{"$unset": bson.M{"idempotency_key": ""}})
+	return err
+}
+
+// GetLastSentAt 獲取用戶在聊天室最後一次發送消息的時間（沒有發送過時返回零值）
+func (s *MessageStore) GetLastSentAt(ctx context.Context, roomID, senderID string) (time.Time, error) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetProjection(bson.M{"created_at": 1})
+
+	var message Message
+	err := s.collection.FindOne(ctx, bson.M{"room_id": roomID, "sender_id": senderID}, opts).Decode(&message)
+	if err == mongo.ErrNoDocuments {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return message.CreatedAt, nil
+}
+
 // GetByRoomID 根據聊天室 ID 獲取消息
 func (s *MessageStore) GetByRoomID(
 	ctx context.Context,
@@ -173,6 +360,158 @@ func (s *MessageStore) GetByRoomID(
 	return messages, nextCursor, hasMore, nil
 }
 
+// GetPaged 按絕對偏移量分頁獲取訊息（按創建時間倒序），同時返回未過期訊息的總數
+// 只供管理工具使用：skip 需要掃描並丟棄前面的訊息，偏移量越大越慢；limit 和 skip 由調用方按 database.ValidateLimit / ValidateSkip 限制
+func (s *MessageStore) GetPaged(ctx context.Context, roomID string, limit, skip int) ([]*Message, int64, error) {
+	filter := buildMessageFilter(roomID, "", nil, nil)
+
+	total, err := s.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 按頁碼分頁不需要多取一個判斷是否有更多，覆蓋 buildMessageFindOptions 中的 limit
+	opts := buildMessageFindOptions(limit).SetLimit(int64(limit)).SetSkip(int64(skip))
+	messages, err := s.executeMessageQuery(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// MessageCountQuery 統計聊天室訊息數量的條件
+type MessageCountQuery struct {
+	RoomID string
+	Type   string     // 只統計指定類型（為空時不過濾）
+	Since  *time.Time // 創建時間下限（包含）
+	Until  *time.Time // 創建時間上限（包含）
+}
+
+// MessageCountFilter 統計訊息數量的過濾條件（與訊息列表相同，不包含已過期的訊息）
+func MessageCountFilter(query MessageCountQuery) bson.M {
+	filter := buildMessageFilter(query.RoomID, "", query.Since, query.Until)
+	if query.Type != "" {
+		filter["type"] = query.Type
+	}
+	return filter
+}
+
+// CountMessages 統計聊天室中符合條件的訊息數量（只計數，不讀取訊息）
+// 訊息目前是硬刪除，已刪除的訊息不在集合中；之後引入軟刪除時需要在 MessageCountFilter 中排除
+func (s *MessageStore) CountMessages(ctx context.Context, query MessageCountQuery) (int64, error) {
+	return s.collection.CountDocuments(ctx, MessageCountFilter(query))
+}
+
+// MessageWindow 某個位置前後的訊息窗口
+type MessageWindow struct {
+	Messages     []*Message // 按時間升序排列
+	BeforeCursor string     // 窗口中最舊訊息的游標（繼續向舊訊息翻頁）
+	HasBefore    bool
+	AfterCursor  string // 窗口中最新訊息的游標（繼續向新訊息翻頁）
+	HasAfter     bool
+}
+
+// GetAround 獲取指定位置前後的訊息
+// anchorID 不為空時以該訊息為錨點（窗口包含錨點本身）；否則以 cursor 為位置（不包含游標指向的訊息）
+func (s *MessageStore) GetAround(
+	ctx context.Context,
+	roomID, anchorID, cursor string,
+	before, after int,
+) (*MessageWindow, error) {
+	var (
+		point  messageCursor
+		anchor *Message
+	)
+
+	switch {
+	case anchorID != "":
+		msg, err := s.GetByID(ctx, anchorID)
+		if err != nil {
+			return nil, err
+		}
+		if msg.RoomID != roomID {
+			return nil, fmt.Errorf("message %s does not belong to room %s", anchorID, roomID)
+		}
+		if msg.Expired(time.Now()) {
+			return nil, mongo.ErrNoDocuments
+		}
+		anchor = msg
+		point = messageCursor{CreatedAt: msg.CreatedAt, ID: msg.GetID()}
+	case cursor != "":
+		c, err := decodeMessageCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		point = c
+	default:
+		return nil, fmt.Errorf("anchor message id or cursor is required")
+	}
+
+	window := &MessageWindow{}
+
+	// 較舊的訊息：倒序查詢，多取一個用於判斷是否還有更多
+	var older []*Message
+	if before > 0 {
+		filter := point.olderThan()
+		filter["room_id"] = roomID
+		filter["expires_at"] = notExpiredFilter(time.Now())
+		opts := buildMessageFindOptions(before).
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+		result, err := s.executeMessageQuery(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		window.HasBefore = len(result) > before
+		if window.HasBefore {
+			result = result[:before]
+		}
+		older = result
+	}
+
+	// 較新的訊息：正序查詢
+	var newer []*Message
+	if after > 0 {
+		filter := point.newerThan()
+		filter["room_id"] = roomID
+		filter["expires_at"] = notExpiredFilter(time.Now())
+		opts := buildMessageFindOptions(after).
+			SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+		result, err := s.executeMessageQuery(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		window.HasAfter = len(result) > after
+		if window.HasAfter {
+			result = result[:after]
+		}
+		newer = result
+	}
+
+	// 組合為升序窗口：較舊訊息（反轉）+ 錨點 + 較新訊息
+	messages := make([]*Message, 0, len(older)+len(newer)+1)
+	for i := len(older) - 1; i >= 0; i-- {
+		messages = append(messages, older[i])
+	}
+	if anchor != nil {
+		messages = append(messages, anchor)
+	}
+	messages = append(messages, newer...)
+	window.Messages = messages
+
+	if len(messages) > 0 {
+		window.BeforeCursor = messageCursorFor(messages[0])
+		window.AfterCursor = messageCursorFor(messages[len(messages)-1])
+	} else {
+		// 空窗口時保持原位置，客戶端可以稍後從同一位置繼續
+		window.BeforeCursor = EncodeMessageCursor(point.CreatedAt, point.ID)
+		window.AfterCursor = window.BeforeCursor
+	}
+
+	return window, nil
+}
+
 // GetHistoryMessages 獲取歷史消息（優化版本）
 func (s *MessageStore) GetHistoryMessages(
 	ctx context.Context, roomID string, limit int, cursor string,
@@ -199,13 +538,14 @@ func (s *MessageStore) GetHistoryMessages(
 	}
 
 	filter := bson.M{
-		"room_id": roomID,
-		"type":    bson.M{"$ne": "system"}, // 排除系統消息
+		"room_id":    roomID,
+		"type":       bson.M{"$ne": "system"}, // 排除系統消息
+		"expires_at": notExpiredFilter(time.Now()),
 	}
 
 	opts := options.Find()
 	opts.SetLimit(int64(limit + 1))
-	opts.SetSort(bson.D{{Key: "created_at", Value: 1}}) // 按創建時間正序排列（舊消息在上，新消息在下）
+	opts.SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}) // 按創建時間正序排列（舊消息在上，新消息在下）
 
 	// 只選擇必要字段，提高查詢性能
 	opts.SetProjection(bson.M{
@@ -219,15 +559,14 @@ func (s *MessageStore) GetHistoryMessages(
 		"created_at":          1,
 		"metadata":            1,
 		"reply_to_message_id": 1,
+		"forwarded_from":      1,
+		"mentions":            1,
+		"encryption_key_id":   1,
+		"expires_at":          1,
 	})
 
 	// 處理游標
-	if cursor != "" {
-		cursorTime, err := time.Parse(time.RFC3339, cursor)
-		if err == nil {
-			filter["created_at"] = bson.M{"$lt": cursorTime}
-		}
-	}
+	applyOlderThanCursor(filter, "created_at", cursor)
 
 	cursorResult, err := s.collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -252,7 +591,7 @@ func (s *MessageStore) GetHistoryMessages(
 
 	// 生成下一個游標
 	if hasMore && len(messages) > 0 {
-		nextCursor = messages[len(messages)-1].CreatedAt.Format(time.RFC3339)
+		nextCursor = messageCursorFor(messages[len(messages)-1])
 	}
 
 	return messages, nextCursor, hasMore, nil
@@ -283,6 +622,7 @@ func (s *MessageStore) GetRecentMessages(ctx context.Context, roomID string, sin
 	filter := bson.M{
 		"room_id":    roomID,
 		"created_at": bson.M{"$gt": since},
+		"expires_at": notExpiredFilter(time.Now()),
 	}
 
 	opts := options.Find()
@@ -319,6 +659,39 @@ func (s *MessageStore) Update(ctx context.Context, id string, update map[string]
 	return err
 }
 
+// GetForReencryption 獲取需要重新加密的消息（密鑰版本不是當前版本）
+// 按 _id 升序分批掃描，afterID 為上一批最後一條消息的 ID
+func (s *MessageStore) GetForReencryption(
+	ctx context.Context, roomID, currentKeyID, afterID string, limit int,
+) ([]*Message, error) {
+	filter := bson.M{
+		"room_id":           roomID,
+		"type":              bson.M{"$ne": "system"},
+		"encryption_key_id": bson.M{"$ne": currentKeyID},
+	}
+	if afterID != "" {
+		objectID, err := bson.ObjectIDFromHex(afterID)
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = bson.M{"$gt": objectID}
+	}
+
+	opts := options.Find().
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetProjection(bson.M{
+			"_id":               1,
+			"id":                1,
+			"room_id":           1,
+			"content":           1,
+			"type":              1,
+			"encryption_key_id": 1,
+		})
+
+	return s.executeMessageQuery(ctx, filter, opts)
+}
+
 // Delete 刪除消息
 func (s *MessageStore) Delete(ctx context.Context, id string) error {
 	objectID, err := parseObjectID(id)
@@ -362,35 +735,280 @@ func (s *MessageStore) MarkAsRead(ctx context.Context, roomID, userID string, me
 	return err
 }
 
-// MarkAsDelivered 標記消息為已送達
-func (s *MessageStore) MarkAsDelivered(ctx context.Context, roomID, userID string, messageID *string) error {
-	filter := bson.M{"room_id": roomID}
+// MarkManyAsRead 批量寫入已讀回執
+// messageIDs 為空時標記聊天室中 readAt 之前的所有訊息（避免把之後到達的訊息標為已讀）
+func (s *MessageStore) MarkManyAsRead(ctx context.Context, roomID, userID string, messageIDs []string, readAt time.Time) error {
+	filter := bson.M{
+		"room_id":         roomID,
+		"read_by.user_id": bson.M{"$ne": userID},
+	}
 
-	if messageID != nil {
-		objectID, err := bson.ObjectIDFromHex(*messageID)
+	if len(messageIDs) > 0 {
+		objectIDs := make([]bson.ObjectID, 0, len(messageIDs))
+		for _, id := range messageIDs {
+			objectID, err := bson.ObjectIDFromHex(id)
+			if err != nil {
+				return err
+			}
+			objectIDs = append(objectIDs, objectID)
+		}
+		filter["_id"] = bson.M{"$in": objectIDs}
+	} else {
+		filter["created_at"] = bson.M{"$lte": readAt}
+	}
+
+	update := bson.M{
+		"$push": bson.M{"read_by": MessageReadBy{UserID: userID, ReadAt: readAt}},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	_, err := s.collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// AdvanceStatus 根據當前成員推進訊息的存儲狀態（sent → delivered → read，不會倒退）
+// messageIDs 為空時檢查聊天室中所有未讀完的訊息
+func (s *MessageStore) AdvanceStatus(ctx context.Context, roomID string, memberIDs, messageIDs []string) error {
+	var objectIDs []bson.ObjectID
+	for _, id := range messageIDs {
+		objectID, err := bson.ObjectIDFromHex(id)
 		if err != nil {
 			return err
 		}
-		filter["_id"] = objectID
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	now := time.Now()
+	for _, status := range []string{MessageStatusRead, MessageStatusDelivered} {
+		_, err := s.collection.UpdateMany(ctx, AdvanceStatusFilter(roomID, memberIDs, objectIDs, status), bson.M{
+			"$set": bson.M{"status": status, "updated_at": now},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AdvanceStatusFilter 可以推進到 status 的訊息：除發送者外的所有成員都已讀（read）或已送達/已讀（delivered）
+// 與 Message.AggregateStatus 的規則一致
+func AdvanceStatusFilter(roomID string, memberIDs []string, objectIDs []bson.ObjectID, status string) bson.M {
+	readBy := bson.M{"$ifNull": bson.A{"$read_by.user_id", bson.A{}}}
+	reached := readBy
+	lower := bson.A{MessageStatusSent, MessageStatusDelivered}
+	if status == MessageStatusDelivered {
+		reached = bson.M{"$setUnion": bson.A{readBy, bson.M{"$ifNull": bson.A{"$delivered_to.user_id", bson.A{}}}}}
+		lower = bson.A{MessageStatusSent}
+	}
+
+	recipients := bson.M{"$setDifference": bson.A{memberIDs, bson.A{"$sender_id"}}}
+	filter := bson.M{
+		"room_id": roomID,
+		"status":  bson.M{"$in": lower},
+		"$expr": bson.M{"$and": bson.A{
+			// 沒有其他成員的訊息保持 sent
+			bson.M{"$gt": bson.A{bson.M{"$size": recipients}, 0}},
+			bson.M{"$setIsSubset": bson.A{recipients, reached}},
+		}},
+	}
+	if len(objectIDs) > 0 {
+		filter["_id"] = bson.M{"$in": objectIDs}
+	}
+	return filter
+}
+
+// markRoomsReadBatchSize 每次 UpdateMany 最多覆蓋的聊天室數量，避免 $in 列表過大
+const markRoomsReadBatchSize = 500
+
+// MarkRoomsAsRead 把多個聊天室中 readAt 之前、其他人發送的未讀訊息全部標記為已讀
+// 每批聊天室先用 Distinct 找出有未讀訊息的聊天室，再用一次 UpdateMany 寫入，
+// 返回受影響的聊天室 ID 和訊息數量
+func (s *MessageStore) MarkRoomsAsRead(ctx context.Context, roomIDs []string, userID string, readAt time.Time) (affectedRooms []string, messages int64, err error) {
+	for start := 0; start < len(roomIDs); start += markRoomsReadBatchSize {
+		end := min(start+markRoomsReadBatchSize, len(roomIDs))
+
+		filter := MarkRoomsAsReadFilter(roomIDs[start:end], userID, readAt)
+		var unreadRooms []string
+		if err := s.collection.Distinct(ctx, "room_id", filter).Decode(&unreadRooms); err != nil {
+			return affectedRooms, messages, err
+		}
+		if len(unreadRooms) == 0 {
+			continue
+		}
+
+		result, err := s.collection.UpdateMany(ctx, filter, bson.M{
+			"$push": bson.M{"read_by": MessageReadBy{UserID: userID, ReadAt: readAt}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		})
+		if err != nil {
+			return affectedRooms, messages, err
+		}
+
+		affectedRooms = append(affectedRooms, unreadRooms...)
+		messages += result.ModifiedCount
+	}
+	return affectedRooms, messages, nil
+}
+
+// MarkRoomsAsReadFilter 多個聊天室中用戶未讀、且不是自己發送的訊息
+func MarkRoomsAsReadFilter(roomIDs []string, userID string, readAt time.Time) bson.M {
+	return bson.M{
+		"room_id":         bson.M{"$in": roomIDs},
+		"sender_id":       bson.M{"$ne": userID},
+		"read_by.user_id": bson.M{"$ne": userID},
+		"created_at":      bson.M{"$lte": readAt},
+	}
+}
+
+// DeleteExpired 分批刪除 before 之前創建的訊息（已讀/送達記錄內嵌在訊息中一併刪除），置頂訊息除外
+// roomIDs 為 nil 時不限聊天室；每批先查出最舊的 batchSize 條訊息 ID 再按 ID 刪除，避免單次刪除耗時過長
+func (s *MessageStore) DeleteExpired(ctx context.Context, roomIDs []string, before time.Time, batchSize int) (int64, error) {
+	if roomIDs == nil {
+		return s.deleteExpiredBatches(ctx, ExpiredMessagesFilter(nil, before), batchSize)
+	}
+
+	var deleted int64
+	for start := 0; start < len(roomIDs); start += markRoomsReadBatchSize {
+		end := min(start+markRoomsReadBatchSize, len(roomIDs))
+		n, err := s.deleteExpiredBatches(ctx, ExpiredMessagesFilter(roomIDs[start:end], before), batchSize)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// deleteExpiredBatches 按批刪除符合條件的訊息，直到沒有剩餘
+func (s *MessageStore) deleteExpiredBatches(ctx context.Context, filter bson.M, batchSize int) (int64, error) {
+	var deleted int64
+	for {
+		ids, err := s.findBatchIDs(ctx, filter, batchSize)
+		if err != nil || len(ids) == 0 {
+			return deleted, err
+		}
+
+		result, err := s.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += result.DeletedCount
+
+		if len(ids) < batchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// findBatchIDs 按創建時間從舊到新查出最多 batchSize 條符合條件的訊息 ID
+func (s *MessageStore) findBatchIDs(ctx context.Context, filter bson.M, batchSize int) ([]bson.ObjectID, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetSort(bson.D{{Key: "created_at", Value: 1}}).
+		SetLimit(int64(batchSize))
+
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID bson.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]bson.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
+
+// ExpiredMessagesFilter before 之前創建、未置頂的訊息（roomIDs 為 nil 時不限聊天室）
+func ExpiredMessagesFilter(roomIDs []string, before time.Time) bson.M {
+	filter := bson.M{
+		"created_at": bson.M{"$lt": before},
+		"pinned":     bson.M{"$ne": true},
+	}
+	if roomIDs != nil {
+		filter["room_id"] = bson.M{"$in": roomIDs}
 	}
+	return filter
+}
+
+// MarkAsDelivered 標記消息為已送達
+func (s *MessageStore) MarkAsDelivered(ctx context.Context, roomID, userID string, messageID *string) error {
+	var messageIDs []string
+	if messageID != nil {
+		messageIDs = []string{*messageID}
+	}
+	return s.MarkManyAsDelivered(ctx, roomID, userID, messageIDs)
+}
 
-	// 添加已送達記錄
+// MarkManyAsDelivered 批量標記消息為已送達
+// messageIDs 為空時標記聊天室中所有訊息
+func (s *MessageStore) MarkManyAsDelivered(ctx context.Context, roomID, userID string, messageIDs []string) error {
+	filter, err := buildDeliveredFilter(roomID, userID, messageIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
 	deliveredTo := MessageDeliveredTo{
 		UserID:      userID,
-		DeliveredAt: time.Now(),
+		DeliveredAt: now,
 	}
 
-	_, err := s.collection.UpdateMany(ctx, filter, bson.M{
+	// delivered_at 每次都不同，$addToSet 無法單獨去重，
+	// 因此 filter 已排除送達過的訊息，重連的客戶端不會產生重複記錄
+	_, err = s.collection.UpdateMany(ctx, filter, bson.M{
 		"$addToSet": bson.M{"delivered_to": deliveredTo},
-		"$set":      bson.M{"updated_at": time.Now()},
+		"$set":      bson.M{"updated_at": now},
 	})
 	return err
 }
 
+// buildDeliveredFilter 構建送達標記的過濾條件（排除已送達給該用戶的訊息和用戶自己發送的訊息）
+func buildDeliveredFilter(roomID, userID string, messageIDs []string) (bson.M, error) {
+	filter := bson.M{
+		"room_id":              roomID,
+		"sender_id":            bson.M{"$ne": userID},
+		"delivered_to.user_id": bson.M{"$ne": userID},
+	}
+
+	switch len(messageIDs) {
+	case 0:
+	case 1:
+		objectID, err := bson.ObjectIDFromHex(messageIDs[0])
+		if err != nil {
+			return nil, err
+		}
+		filter["_id"] = objectID
+	default:
+		objectIDs := make([]bson.ObjectID, 0, len(messageIDs))
+		for _, id := range messageIDs {
+			objectID, err := bson.ObjectIDFromHex(id)
+			if err != nil {
+				return nil, err
+			}
+			objectIDs = append(objectIDs, objectID)
+		}
+		filter["_id"] = bson.M{"$in": objectIDs}
+	}
+
+	return filter, nil
+}
+
 // GetUnreadCount 獲取未讀消息數量
 func (s *MessageStore) GetUnreadCount(ctx context.Context, userID string, roomID *string) (int, error) {
 	filter := bson.M{
 		"read_by.user_id": bson.M{"$ne": userID},
+		"expires_at":      notExpiredFilter(time.Now()),
 	}
 
 	if roomID != nil {
@@ -401,6 +1019,107 @@ func (s *MessageStore) GetUnreadCount(ctx context.Context, userID string, roomID
 	return int(count), err
 }
 
+// CountUnreadSince 統計 since（成員的 LastReadAt）之後其他人發送的訊息數量
+// 只按 room_id + created_at 範圍計數（room_time_idx 索引），不檢查 read_by
+func (s *MessageStore) CountUnreadSince(ctx context.Context, roomID, userID string, since time.Time) (int, error) {
+	count, err := s.collection.CountDocuments(ctx, UnreadSinceFilter(roomID, userID, since, time.Now()))
+	return int(count), err
+}
+
+// CountUnreadByReceipts 按 read_by 精確統計用戶在聊天室中的未讀訊息數量
+func (s *MessageStore) CountUnreadByReceipts(ctx context.Context, roomID, userID string) (int, error) {
+	count, err := s.collection.CountDocuments(ctx, UnreadByReceiptsFilter(roomID, userID, time.Now()))
+	return int(count), err
+}
+
+// UnreadSinceFilter 聊天室中 since 之後、不是自己發送的未過期訊息
+func UnreadSinceFilter(roomID, userID string, since, now time.Time) bson.M {
+	return bson.M{
+		"room_id":    roomID,
+		"created_at": bson.M{"$gt": since},
+		"sender_id":  bson.M{"$ne": userID},
+		"expires_at": notExpiredFilter(now),
+	}
+}
+
+// UnreadByReceiptsFilter 聊天室中 read_by 不包含該用戶、且不是自己發送的未過期訊息
+func UnreadByReceiptsFilter(roomID, userID string, now time.Time) bson.M {
+	return bson.M{
+		"room_id":         roomID,
+		"sender_id":       bson.M{"$ne": userID},
+		"read_by.user_id": bson.M{"$ne": userID},
+		"expires_at":      notExpiredFilter(now),
+	}
+}
+
+// CountUnreadInRooms 一次聚合統計用戶在多個聊天室中的未讀數量（沒有未讀的聊天室不在結果中）
+// sinceByRoom 中的聊天室按成員的 LastReadAt 計數，receiptRooms 中的聊天室按 read_by 計數
+func (s *MessageStore) CountUnreadInRooms(ctx context.Context, userID string, sinceByRoom map[string]time.Time, receiptRooms []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(sinceByRoom) == 0 && len(receiptRooms) == 0 {
+		return counts, nil
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, UnreadInRoomsPipeline(userID, sinceByRoom, receiptRooms, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID    string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		counts[result.ID] = result.Count
+	}
+	return counts, nil
+}
+
+// UnreadInRoomsPipeline 按聊天室分組統計未讀訊息數量
+func UnreadInRoomsPipeline(userID string, sinceByRoom map[string]time.Time, receiptRooms []string, now time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: UnreadInRoomsFilter(userID, sinceByRoom, receiptRooms, now)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$room_id",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+}
+
+// UnreadInRoomsFilter 多個聊天室中不是自己發送的未過期未讀訊息
+// 每個 sinceByRoom 聊天室一個 created_at 範圍條件（按聊天室 ID 排序，保證條件穩定），receiptRooms 合併為一個 read_by 條件
+func UnreadInRoomsFilter(userID string, sinceByRoom map[string]time.Time, receiptRooms []string, now time.Time) bson.M {
+	roomIDs := make([]string, 0, len(sinceByRoom))
+	for roomID := range sinceByRoom {
+		roomIDs = append(roomIDs, roomID)
+	}
+	sort.Strings(roomIDs)
+
+	conditions := make(bson.A, 0, len(roomIDs)+1)
+	for _, roomID := range roomIDs {
+		conditions = append(conditions, bson.M{
+			"room_id":    roomID,
+			"created_at": bson.M{"$gt": sinceByRoom[roomID]},
+		})
+	}
+	if len(receiptRooms) > 0 {
+		conditions = append(conditions, bson.M{
+			"room_id":         bson.M{"$in": receiptRooms},
+			"read_by.user_id": bson.M{"$ne": userID},
+		})
+	}
+
+	return bson.M{
+		"$or":        conditions,
+		"sender_id":  bson.M{"$ne": userID},
+		"expires_at": notExpiredFilter(now),
+	}
+}
+
 // Search 搜索消息
 func (s *MessageStore) Search(
 	ctx context.Context,
@@ -411,8 +1130,9 @@ func (s *MessageStore) Search(
 	cursor string,
 ) (messages []*Message, nextCursor string, hasMore bool, totalCount int, err error) {
 	filter := bson.M{
-		"room_id": roomID,
-		"$text":   bson.M{"$search": query},
+		"room_id":    roomID,
+		"$text":      bson.M{"$search": query},
+		"expires_at": notExpiredFilter(time.Now()),
 	}
 
 	// 添加用戶過濾
@@ -439,15 +1159,10 @@ func (s *MessageStore) Search(
 
 	opts := options.Find()
 	opts.SetLimit(int64(limit + 1)) // 多取一個用於判斷是否有更多
-	opts.SetSort(bson.D{{Key: "created_at", Value: -1}})
+	opts.SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
 
 	// 如果有游標，添加游標條件
-	if cursor != "" {
-		cursorTime, err := time.Parse(time.RFC3339, cursor)
-		if err == nil {
-			filter["created_at"] = bson.M{"$lt": cursorTime}
-		}
-	}
+	applyOlderThanCursor(filter, "created_at", cursor)
 
 	cursorResult, err := s.collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -472,7 +1187,7 @@ func (s *MessageStore) Search(
 
 	// 生成下一個游標
 	if hasMore && len(messages) > 0 {
-		nextCursor = messages[len(messages)-1].CreatedAt.Format(time.RFC3339)
+		nextCursor = messageCursorFor(messages[len(messages)-1])
 	}
 
 	// 獲取總數
@@ -510,7 +1225,10 @@ func normalizePaginationLimit(limit int) int {
 
 // buildMessageFilter 構建消息查詢過濾條件
 func buildMessageFilter(roomID, cursor string, since, until *time.Time) bson.M {
-	filter := bson.M{"room_id": roomID}
+	filter := bson.M{
+		"room_id":    roomID,
+		"expires_at": notExpiredFilter(time.Now()),
+	}
 
 	// 添加時間範圍過濾
 	if since != nil {
@@ -525,12 +1243,7 @@ func buildMessageFilter(roomID, cursor string, since, until *time.Time) bson.M {
 	}
 
 	// 如果有游標，添加游標條件
-	if cursor != "" {
-		cursorTime, err := time.Parse(time.RFC3339, cursor)
-		if err == nil {
-			filter["created_at"] = bson.M{"$lt": cursorTime}
-		}
-	}
+	applyOlderThanCursor(filter, "created_at", cursor)
 
 	return filter
 }
@@ -538,8 +1251,8 @@ func buildMessageFilter(roomID, cursor string, since, until *time.Time) bson.M {
 // buildMessageFindOptions 構建消息查詢選項
 func buildMessageFindOptions(limit int) *options.FindOptionsBuilder {
 	return options.Find().
-		SetLimit(int64(limit + 1)).                      // 多取一個用於判斷是否有更多
-		SetSort(bson.D{{Key: "created_at", Value: -1}}). // 按創建時間倒序排列
+		SetLimit(int64(limit + 1)).                                               // 多取一個用於判斷是否有更多
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}). // 按創建時間倒序排列
 		SetProjection(bson.M{
 			"_id":                 1,
 			"id":                  1,
@@ -555,6 +1268,9 @@ func buildMessageFindOptions(limit int) *options.FindOptionsBuilder {
 			"metadata":            1,
 			"reply_to_message_id": 1,
 			"forwarded_from":      1,
+			"mentions":            1,
+			"encryption_key_id":   1,
+			"expires_at":          1,
 		})
 }
 
@@ -588,7 +1304,7 @@ func (s *MessageStore) processPaginationResult(
 	}
 
 	if hasMore && len(messages) > 0 {
-		nextCursor = messages[len(messages)-1].CreatedAt.Format(time.RFC3339)
+		nextCursor = messageCursorFor(messages[len(messages)-1])
 	}
 
 	resultMessages = messages
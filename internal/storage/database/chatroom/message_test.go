@@ -0,0 +1,331 @@
+package chatroom
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMessage_KeyVersion(t *testing.T) {
+	tests := []struct {
+		name  string
+		keyID string
+		want  int
+	}{
+		{"legacy message without key id", "", legacyKeyVersion},
+		{"recorded version", "3", 3},
+		{"malformed key id", "not-a-version", 0},
+		{"negative key id", "-1", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &Message{EncryptionKeyID: tt.keyID}
+			if got := msg.KeyVersion(); got != tt.want {
+				t.Errorf("KeyVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessage_SetKeyVersion(t *testing.T) {
+	msg := &Message{}
+
+	msg.SetKeyVersion(2)
+	if msg.EncryptionKeyID != "2" || msg.KeyVersion() != 2 {
+		t.Errorf("SetKeyVersion(2) stored %q", msg.EncryptionKeyID)
+	}
+
+	msg.SetKeyVersion(0)
+	if msg.EncryptionKeyID != "" {
+		t.Errorf("SetKeyVersion(0) should clear key id, got %q", msg.EncryptionKeyID)
+	}
+}
+
+func TestBuildDeliveredFilter(t *testing.T) {
+	id1 := bson.NewObjectID()
+	id2 := bson.NewObjectID()
+
+	filter, err := buildDeliveredFilter("room-1", "user-1", []string{id1.Hex(), id2.Hex()})
+	if err != nil {
+		t.Fatalf("buildDeliveredFilter failed: %v", err)
+	}
+
+	// 已送達過的訊息不再更新，避免重連的客戶端產生重複記錄
+	if cond, ok := filter["delivered_to.user_id"].(bson.M); !ok || cond["$ne"] != "user-1" {
+		t.Errorf("expected already-delivered messages to be excluded: %v", filter)
+	}
+	if cond, ok := filter["sender_id"].(bson.M); !ok || cond["$ne"] != "user-1" {
+		t.Errorf("expected own messages to be excluded: %v", filter)
+	}
+	ids, ok := filter["_id"].(bson.M)["$in"].([]bson.ObjectID)
+	if !ok || len(ids) != 2 || ids[0] != id1 || ids[1] != id2 {
+		t.Errorf("unexpected _id condition: %v", filter["_id"])
+	}
+
+	single, err := buildDeliveredFilter("room-1", "user-1", []string{id1.Hex()})
+	if err != nil || single["_id"] != id1 {
+		t.Errorf("expected single _id match, got %v (err %v)", single["_id"], err)
+	}
+
+	all, err := buildDeliveredFilter("room-1", "user-1", nil)
+	if err != nil {
+		t.Fatalf("buildDeliveredFilter failed: %v", err)
+	}
+	if _, ok := all["_id"]; ok {
+		t.Errorf("expected whole-room filter without _id, got %v", all)
+	}
+
+	if _, err := buildDeliveredFilter("room-1", "user-1", []string{"bad-id"}); err == nil {
+		t.Error("expected error for invalid message id")
+	}
+}
+
+func TestMessage_ForwardSource(t *testing.T) {
+	var m Message
+	if _, _, _, ok := m.ForwardSource(); ok {
+		t.Error("expected regular message not to be forwarded")
+	}
+
+	m.SetForwardedFrom("room-1", "msg-1", "alice")
+	roomID, messageID, senderID, ok := m.ForwardSource()
+	if !ok || roomID != "room-1" || messageID != "msg-1" || senderID != "alice" {
+		t.Errorf("ForwardSource() = %q, %q, %q, %v", roomID, messageID, senderID, ok)
+	}
+
+	// 格式錯誤的舊數據視為非轉發
+	m.ForwardedFrom = []string{"room-1"}
+	if _, _, _, ok := m.ForwardSource(); ok {
+		t.Error("expected malformed forwarded_from to be ignored")
+	}
+}
+
+func TestMarkRoomsAsReadFilter(t *testing.T) {
+	readAt := time.Unix(1700000000, 0)
+	filter := MarkRoomsAsReadFilter([]string{"room-1", "room-2"}, "alice", readAt)
+
+	want := bson.M{
+		"room_id":         bson.M{"$in": []string{"room-1", "room-2"}},
+		"sender_id":       bson.M{"$ne": "alice"},
+		"read_by.user_id": bson.M{"$ne": "alice"},
+		"created_at":      bson.M{"$lte": readAt},
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("MarkRoomsAsReadFilter() = %v, want %v", filter, want)
+	}
+}
+
+func TestUnreadSinceFilter(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+	now := since.Add(time.Hour)
+	filter := UnreadSinceFilter("room-1", "alice", since, now)
+
+	want := bson.M{
+		"room_id":    "room-1",
+		"created_at": bson.M{"$gt": since},
+		"sender_id":  bson.M{"$ne": "alice"},
+		"expires_at": notExpiredFilter(now),
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("UnreadSinceFilter() = %v, want %v", filter, want)
+	}
+}
+
+func TestUnreadByReceiptsFilter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	filter := UnreadByReceiptsFilter("room-1", "alice", now)
+
+	want := bson.M{
+		"room_id":         "room-1",
+		"sender_id":       bson.M{"$ne": "alice"},
+		"read_by.user_id": bson.M{"$ne": "alice"},
+		"expires_at":      notExpiredFilter(now),
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("UnreadByReceiptsFilter() = %v, want %v", filter, want)
+	}
+}
+
+func TestUnreadInRoomsFilter(t *testing.T) {
+	since := time.Unix(1700000000, 0)
+	now := since.Add(time.Hour)
+	sinceByRoom := map[string]time.Time{
+		"room-2": since.Add(time.Minute),
+		"room-1": since,
+	}
+	filter := UnreadInRoomsFilter("alice", sinceByRoom, []string{"room-3"}, now)
+
+	want := bson.M{
+		"$or": bson.A{
+			bson.M{"room_id": "room-1", "created_at": bson.M{"$gt": since}},
+			bson.M{"room_id": "room-2", "created_at": bson.M{"$gt": since.Add(time.Minute)}},
+			bson.M{"room_id": bson.M{"$in": []string{"room-3"}}, "read_by.user_id": bson.M{"$ne": "alice"}},
+		},
+		"sender_id":  bson.M{"$ne": "alice"},
+		"expires_at": notExpiredFilter(now),
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("UnreadInRoomsFilter() = %v, want %v", filter, want)
+	}
+}
+
+func TestUnreadInRoomsPipeline_GroupsByRoom(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	pipeline := UnreadInRoomsPipeline("alice", nil, []string{"room-1"}, now)
+
+	if len(pipeline) != 2 {
+		t.Fatalf("Expected $match and $group stages, got %v", pipeline)
+	}
+	group, ok := pipeline[1][0].Value.(bson.M)
+	if pipeline[1][0].Key != "$group" || !ok || group["_id"] != "$room_id" {
+		t.Errorf("Expected grouping by room_id, got %v", pipeline[1])
+	}
+}
+
+func TestMessage_AggregateStatus(t *testing.T) {
+	members := []string{"alice", "bob", "carol"}
+
+	tests := []struct {
+		name      string
+		delivered []string
+		read      []string
+		members   []string
+		want      string
+	}{
+		{"nobody received", nil, nil, members, MessageStatusSent},
+		{"partially delivered", []string{"bob"}, nil, members, MessageStatusSent},
+		{"all delivered", []string{"bob", "carol"}, nil, members, MessageStatusDelivered},
+		{"read counts as delivered", []string{"bob"}, []string{"carol"}, members, MessageStatusDelivered},
+		{"partially read", []string{"bob", "carol"}, []string{"bob"}, members, MessageStatusDelivered},
+		{"all read", nil, []string{"bob", "carol"}, members, MessageStatusRead},
+		{"sender receipt ignored", []string{"alice", "bob"}, []string{"alice"}, members, MessageStatusSent},
+		{"receipts of former members ignored", nil, []string{"bob", "dave"}, []string{"alice", "bob"}, MessageStatusRead},
+		{"no recipients", nil, nil, []string{"alice"}, MessageStatusSent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Message{SenderID: "alice"}
+			for _, userID := range tt.delivered {
+				m.DeliveredTo = append(m.DeliveredTo, MessageDeliveredTo{UserID: userID})
+			}
+			for _, userID := range tt.read {
+				m.ReadBy = append(m.ReadBy, MessageReadBy{UserID: userID})
+			}
+			if got := m.AggregateStatus(tt.members); got != tt.want {
+				t.Errorf("AggregateStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdvanceStatusFilter(t *testing.T) {
+	objectID := bson.NewObjectID()
+
+	read := AdvanceStatusFilter("room-1", []string{"alice", "bob"}, []bson.ObjectID{objectID}, MessageStatusRead)
+	if got, want := read["status"], (bson.M{"$in": bson.A{MessageStatusSent, MessageStatusDelivered}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("read filter status = %v, want %v", got, want)
+	}
+	if got, want := read["_id"], (bson.M{"$in": []bson.ObjectID{objectID}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("read filter _id = %v, want %v", got, want)
+	}
+
+	// 狀態只能前進：delivered 只更新仍是 sent 的訊息
+	delivered := AdvanceStatusFilter("room-1", []string{"alice", "bob"}, nil, MessageStatusDelivered)
+	if got, want := delivered["status"], (bson.M{"$in": bson.A{MessageStatusSent}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered filter status = %v, want %v", got, want)
+	}
+	if _, ok := delivered["_id"]; ok {
+		t.Error("expected no _id condition when message IDs are empty")
+	}
+	if delivered["room_id"] != "room-1" {
+		t.Errorf("delivered filter room_id = %v, want room-1", delivered["room_id"])
+	}
+}
+
+func TestExpiredMessagesFilter(t *testing.T) {
+	before := time.Unix(1700000000, 0)
+
+	want := bson.M{
+		"created_at": bson.M{"$lt": before},
+		"pinned":     bson.M{"$ne": true},
+	}
+	if got := ExpiredMessagesFilter(nil, before); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpiredMessagesFilter(nil) = %v, want %v", got, want)
+	}
+
+	want["room_id"] = bson.M{"$in": []string{"room-1"}}
+	if got := ExpiredMessagesFilter([]string{"room-1"}, before); !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpiredMessagesFilter(room-1) = %v, want %v", got, want)
+	}
+}
+
+func TestUserDataFilters(t *testing.T) {
+	if got, want := UserMessagesFilter("alice"), (bson.M{"sender_id": "alice"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("UserMessagesFilter() = %v, want %v", got, want)
+	}
+
+	want := bson.M{"$or": bson.A{
+		bson.M{"read_by.user_id": "alice"},
+		bson.M{"delivered_to.user_id": "alice"},
+		bson.M{"mentions": "alice"},
+	}}
+	if got := UserReceiptsFilter("alice"); !reflect.DeepEqual(got, want) {
+		t.Errorf("UserReceiptsFilter() = %v, want %v", got, want)
+	}
+
+	// forwarded_from 的第三個元素是原始發送者
+	if got, want := UserForwardSourceFilter("alice"), (bson.M{"forwarded_from.2": "alice"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("UserForwardSourceFilter() = %v, want %v", got, want)
+	}
+}
+
+func TestMessage_Expired(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	past := now.Add(-time.Second)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      bool
+	}{
+		{"no expiry", nil, false},
+		{"expired", &past, true},
+		{"expires now", &now, true},
+		{"not yet expired", &future, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Message{ExpiresAt: tt.expiresAt}
+			if got := m.Expired(now); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMessageFilter_ExcludesExpired(t *testing.T) {
+	filter := buildMessageFilter("room-1", "", nil, nil)
+	if _, ok := filter["expires_at"]; !ok {
+		t.Errorf("buildMessageFilter() = %v, want expires_at condition", filter)
+	}
+}
+
+func TestMessageCountFilter(t *testing.T) {
+	filter := MessageCountFilter(MessageCountQuery{RoomID: "room-1"})
+	if _, ok := filter["type"]; ok {
+		t.Errorf("MessageCountFilter() = %v, want no type condition", filter)
+	}
+	if _, ok := filter["expires_at"]; !ok {
+		t.Errorf("MessageCountFilter() = %v, want expires_at condition", filter)
+	}
+
+	filter = MessageCountFilter(MessageCountQuery{RoomID: "room-1", Type: "image"})
+	if filter["type"] != "image" || filter["room_id"] != "room-1" {
+		t.Errorf("MessageCountFilter() = %v, want room_id and type conditions", filter)
+	}
+}
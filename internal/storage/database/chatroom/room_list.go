@@ -0,0 +1,84 @@
+package chatroom
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// RoomQuery 管理員列出聊天室的條件（為空的條件不過濾）
+type RoomQuery struct {
+	Type          string
+	OwnerID       string
+	NameFilter    bson.M    // 名稱條件，由調用方使用 database.SafePrefixRegexQuery / SafeRegexQuery 構建
+	CreatedAfter  time.Time // 包含
+	CreatedBefore time.Time // 不包含
+	Limit         int
+	Cursor        string // 上一頁返回的游標（按創建時間倒序）
+}
+
+// RoomListPipeline 構建管理員列出聊天室的聚合管道
+// 按創建時間倒序分頁，在數據庫中計算成員數量後移除成員數組；多取一個用於判斷是否有更多
+func RoomListPipeline(query RoomQuery, memberCollection bool) mongo.Pipeline {
+	filter := bson.M{}
+	if query.Type != "" {
+		filter["type"] = query.Type
+	}
+	if query.OwnerID != "" {
+		filter["owner_id"] = query.OwnerID
+	}
+	if query.NameFilter != nil {
+		filter["name"] = query.NameFilter
+	}
+	createdAt := bson.M{}
+	if !query.CreatedAfter.IsZero() {
+		createdAt["$gte"] = query.CreatedAfter
+	}
+	if !query.CreatedBefore.IsZero() {
+		createdAt["$lt"] = query.CreatedBefore
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+	applyOlderThanCursor(filter, "created_at", query.Cursor)
+
+	// 成員存放在獨立集合時使用聊天室文檔上維護的 member_count，否則計算內嵌成員數組的長度
+	var memberCount interface{} = bson.M{"$size": bson.M{"$ifNull": bson.A{"$members", bson.A{}}}}
+	if memberCollection {
+		memberCount = bson.M{"$ifNull": bson.A{"$member_count", 0}}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}}},
+		{{Key: "$limit", Value: int64(query.Limit + 1)}},
+		{{Key: "$set", Value: bson.M{"member_count": memberCount}}},
+		{{Key: "$unset", Value: "members"}},
+	}
+}
+
+// ListRooms 分頁列出所有聊天室（管理員使用），成員數量填入 MemberCount，不加載成員列表
+func (s *ChatRoomStore) ListRooms(ctx context.Context, query RoomQuery) (rooms []*ChatRoom, nextCursor string, hasMore bool, err error) {
+	query.Limit = normalizePaginationLimit(query.Limit)
+
+	cursor, err := s.collection.Aggregate(ctx, RoomListPipeline(query, s.members != nil))
+	if err != nil {
+		return nil, "", false, err
+	}
+	defer cursor.Close(ctx)
+
+	rooms = []*ChatRoom{}
+	if err := cursor.All(ctx, &rooms); err != nil {
+		return nil, "", false, err
+	}
+
+	hasMore = len(rooms) > query.Limit
+	if hasMore {
+		rooms = rooms[:query.Limit]
+		last := rooms[len(rooms)-1]
+		nextCursor = EncodeMessageCursor(last.CreatedAt, last.ID)
+	}
+	return rooms, nextCursor, hasMore, nil
+}
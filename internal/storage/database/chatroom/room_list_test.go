@@ -0,0 +1,75 @@
+package chatroom
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestRoomListPipeline_Filters(t *testing.T) {
+	after := time.Unix(1700000000, 0).UTC()
+	before := time.Unix(1700086400, 0).UTC()
+	nameFilter := bson.M{"$regex": "^team", "$options": "i"}
+
+	pipeline := RoomListPipeline(RoomQuery{
+		Type:          "group",
+		OwnerID:       "owner-1",
+		NameFilter:    nameFilter,
+		CreatedAfter:  after,
+		CreatedBefore: before,
+		Limit:         20,
+		Cursor:        EncodeMessageCursor(before, bson.NewObjectID().Hex()),
+	}, false)
+
+	match := pipeline[0][0].Value.(bson.M)
+	if match["type"] != "group" || match["owner_id"] != "owner-1" {
+		t.Errorf("match = %v, want type and owner filters", match)
+	}
+	if !reflect.DeepEqual(match["name"], nameFilter) {
+		t.Errorf("name = %v, want %v", match["name"], nameFilter)
+	}
+	if want := (bson.M{"$gte": after, "$lt": before}); !reflect.DeepEqual(match["created_at"], want) {
+		t.Errorf("created_at = %v, want %v", match["created_at"], want)
+	}
+	if conditions, _ := match["$and"].(bson.A); len(conditions) != 1 {
+		t.Errorf("$and = %v, want cursor condition", match["$and"])
+	}
+
+	// 多取一個用於判斷是否有更多
+	if limit := pipeline[2][0].Value; limit != int64(21) {
+		t.Errorf("$limit = %v, want 21", limit)
+	}
+	// 不返回成員列表
+	if unset := pipeline[4][0]; unset.Key != "$unset" || unset.Value != "members" {
+		t.Errorf("last stage = %v, want $unset members", unset)
+	}
+}
+
+func TestRoomListPipeline_EmptyQuery(t *testing.T) {
+	match := RoomListPipeline(RoomQuery{Limit: 10}, false)[0][0].Value.(bson.M)
+	if len(match) != 0 {
+		t.Errorf("match = %v, want empty filter", match)
+	}
+}
+
+func TestRoomListPipeline_MemberCount(t *testing.T) {
+	tests := []struct {
+		name             string
+		memberCollection bool
+		want             interface{}
+	}{
+		{"embedded members", false, bson.M{"$size": bson.M{"$ifNull": bson.A{"$members", bson.A{}}}}},
+		{"member collection", true, bson.M{"$ifNull": bson.A{"$member_count", 0}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := RoomListPipeline(RoomQuery{Limit: 10}, tt.memberCollection)[3][0].Value.(bson.M)
+			if !reflect.DeepEqual(set["member_count"], tt.want) {
+				t.Errorf("member_count = %v, want %v", set["member_count"], tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package chatroom
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"chat-gateway/internal/constants"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// roomListCursor 用戶聊天室列表游標（排序方式 + 置頂狀態 + 排序字段 + _id）
+type roomListCursor struct {
+	Sort          string    `json:"s"`
+	Pinned        bool      `json:"p,omitempty"`
+	Name          string    `json:"n,omitempty"`
+	HasUnread     bool      `json:"u,omitempty"`
+	LastMessageAt time.Time `json:"t"`
+	ID            string    `json:"id"`
+}
+
+// roomListCursorFor 聊天室列表中最後一個聊天室的游標
+func roomListCursorFor(sort string, room *ChatRoom) string {
+	data, _ := json.Marshal(roomListCursor{ // #nosec G104 -- marshaling a fixed struct cannot fail
+		Sort:          sort,
+		Pinned:        room.Pinned,
+		Name:          room.Name,
+		HasUnread:     room.HasUnread,
+		LastMessageAt: room.LastMessageAt.UTC(),
+		ID:            room.ID,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeRoomListCursor 解析聊天室列表游標，排序方式不符時返回錯誤
+// 按最近活動排序時兼容已發出的 messageCursor 游標（沒有置頂狀態，視為未置頂）
+func decodeRoomListCursor(cursor, sort string) (roomListCursor, error) {
+	var c roomListCursor
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if (err != nil || c.Sort == "") && sort == constants.RoomSortActivity {
+		legacy, legacyErr := decodeMessageCursor(cursor)
+		if legacyErr != nil {
+			return roomListCursor{}, legacyErr
+		}
+		return roomListCursor{Sort: sort, LastMessageAt: legacy.CreatedAt, ID: legacy.ID}, nil
+	}
+	if err != nil {
+		return roomListCursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if c.Sort != sort {
+		return roomListCursor{}, fmt.Errorf("cursor was issued for sort %q, not %q", c.Sort, sort)
+	}
+	if _, err := bson.ObjectIDFromHex(c.ID); err != nil {
+		return roomListCursor{}, fmt.Errorf("invalid cursor id: %w", err)
+	}
+	return c, nil
+}
+
+// after 構建排在游標之後的過濾條件：pinned < p OR (pinned == p AND 排序字段排在游標之後)
+// 名稱升序：name > n OR (name == n AND _id > id)
+// 最近活動：last_message_at < t OR (last_message_at == t AND _id < id)
+// 未讀優先：has_unread < u OR (has_unread == u AND 最近活動條件)
+func (c roomListCursor) after() bson.M {
+	activity := messageCursor{CreatedAt: c.LastMessageAt, ID: c.ID}.compare("last_message_at", "$lt")
+
+	var sorted bson.M
+	switch c.Sort {
+	case constants.RoomSortName:
+		objectID, _ := bson.ObjectIDFromHex(c.ID) // #nosec G104 -- validated in decodeRoomListCursor
+		sorted = bson.M{"$or": bson.A{
+			bson.M{"name": bson.M{"$gt": c.Name}},
+			bson.M{"name": c.Name, "_id": bson.M{"$gt": objectID}},
+		}}
+	case constants.RoomSortUnread:
+		sorted = bson.M{"$or": bson.A{
+			bson.M{"has_unread": bson.M{"$lt": c.HasUnread}},
+			bson.M{"has_unread": c.HasUnread, "$and": bson.A{activity}},
+		}}
+	default:
+		sorted = activity
+	}
+
+	return bson.M{"$or": bson.A{
+		bson.M{"pinned": bson.M{"$lt": c.Pinned}},
+		bson.M{"pinned": c.Pinned, "$and": bson.A{sorted}},
+	}}
+}
+
+// userRoomsSort 用戶聊天室列表的排序字段（置頂的聊天室總是排在最前）
+func userRoomsSort(sort string) bson.D {
+	keys := bson.D{{Key: "pinned", Value: -1}}
+	switch sort {
+	case constants.RoomSortName:
+		return append(keys, bson.E{Key: "name", Value: 1}, bson.E{Key: "_id", Value: 1})
+	case constants.RoomSortUnread:
+		keys = append(keys, bson.E{Key: "has_unread", Value: -1})
+	}
+	return append(keys, bson.E{Key: "last_message_at", Value: -1}, bson.E{Key: "_id", Value: -1})
+}
+
+// UserRoomsPipeline 構建用戶聊天室列表的聚合管道
+// 先取出請求用戶的成員信息得到 pinned；按未讀排序時再用 last_read_at 查找一條之後其他人發送的訊息
+// （room_time_idx 索引，最多一條）得到 has_unread，最後排序分頁；多取一個用於判斷是否有更多
+func UserRoomsPipeline(filter bson.M, userID, sort string, memberCollection bool, cursor string, limit int, now time.Time) mongo.Pipeline {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: filter}}}
+
+	if memberCollection {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from": memberCollectionName,
+				"let":  bson.M{"room_id": "$id"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"user_id": userID, "$expr": bson.M{"$eq": bson.A{"$room_id", "$$room_id"}}}},
+					bson.M{"$project": bson.M{"_id": 0, "pinned": 1, "last_read_at": 1}},
+				},
+				"as": "_self",
+			}}},
+			bson.D{{Key: "$set", Value: bson.M{"_self": bson.M{"$arrayElemAt": bson.A{"$_self", 0}}}}},
+		)
+	} else {
+		pipeline = append(pipeline, bson.D{{Key: "$set", Value: bson.M{"_self": bson.M{"$arrayElemAt": bson.A{
+			bson.M{"$filter": bson.M{
+				"input": "$members",
+				"as":    "member",
+				"cond":  bson.M{"$eq": bson.A{"$$member.user_id", userID}},
+			}},
+			0,
+		}}}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$set", Value: bson.M{"pinned": bson.M{"$eq": bson.A{"$_self.pinned", true}}}}})
+
+	if sort == constants.RoomSortUnread {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{
+				"from": "messages",
+				"let":  bson.M{"room_id": "$id", "since": "$_self.last_read_at"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{
+						"sender_id":  bson.M{"$ne": userID},
+						"expires_at": notExpiredFilter(now),
+						"$expr": bson.M{"$and": bson.A{
+							bson.M{"$eq": bson.A{"$room_id", "$$room_id"}},
+							bson.M{"$gt": bson.A{"$created_at", "$$since"}},
+						}},
+					}},
+					bson.M{"$limit": 1},
+					bson.M{"$project": bson.M{"_id": 1}},
+				},
+				"as": "_unread",
+			}}},
+			bson.D{{Key: "$set", Value: bson.M{"has_unread": bson.M{"$gt": bson.A{bson.M{"$size": "$_unread"}, 0}}}}},
+		)
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$unset", Value: bson.A{"_self", "_unread"}}})
+
+	if cursor != "" {
+		if c, err := decodeRoomListCursor(cursor, sort); err == nil {
+			pipeline = append(pipeline, bson.D{{Key: "$match", Value: c.after()}})
+		}
+	}
+
+	return append(pipeline,
+		bson.D{{Key: "$sort", Value: userRoomsSort(sort)}},
+		bson.D{{Key: "$limit", Value: int64(limit + 1)}},
+	)
+}
@@ -0,0 +1,162 @@
+package chatroom
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"chat-gateway/internal/constants"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestRoomListCursor_RoundTrip(t *testing.T) {
+	room := NewChatRoom()
+	room.Name = "general"
+	room.Pinned = true
+	room.HasUnread = true
+	room.LastMessageAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, sort := range []string{constants.RoomSortActivity, constants.RoomSortName, constants.RoomSortUnread} {
+		c, err := decodeRoomListCursor(roomListCursorFor(sort, &room), sort)
+		if err != nil {
+			t.Fatalf("%s: decode failed: %v", sort, err)
+		}
+		if !c.Pinned || c.Name != room.Name || c.HasUnread != room.HasUnread || !c.LastMessageAt.Equal(room.LastMessageAt) || c.ID != room.ID {
+			t.Errorf("%s: round trip mismatch: %+v", sort, c)
+		}
+	}
+}
+
+func TestRoomListCursor_AcceptsLegacyActivityCursor(t *testing.T) {
+	room := NewChatRoom()
+	room.LastMessageAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	c, err := decodeRoomListCursor(EncodeMessageCursor(room.LastMessageAt, room.ID), constants.RoomSortActivity)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if c.Pinned || !c.LastMessageAt.Equal(room.LastMessageAt) || c.ID != room.ID {
+		t.Errorf("unexpected legacy cursor: %+v", c)
+	}
+
+	if _, err := decodeRoomListCursor(EncodeMessageCursor(room.LastMessageAt, room.ID), constants.RoomSortName); err == nil {
+		t.Error("legacy cursor should only be accepted for activity sort")
+	}
+}
+
+func TestRoomListCursor_RejectsOtherSort(t *testing.T) {
+	room := NewChatRoom()
+	cursor := roomListCursorFor(constants.RoomSortName, &room)
+
+	for _, sort := range []string{constants.RoomSortUnread, constants.RoomSortActivity} {
+		if _, err := decodeRoomListCursor(cursor, sort); err == nil {
+			t.Errorf("name cursor should not be accepted for %s sort", sort)
+		}
+	}
+}
+
+func TestRoomListCursor_After(t *testing.T) {
+	room := NewChatRoom()
+	room.Name = "general"
+	room.LastMessageAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	objectID, _ := bson.ObjectIDFromHex(room.ID)
+	activity := bson.M{"$or": bson.A{
+		bson.M{"last_message_at": bson.M{"$lt": room.LastMessageAt}},
+		bson.M{"last_message_at": room.LastMessageAt, "_id": bson.M{"$lt": objectID}},
+	}}
+	pinnedFirst := func(sorted bson.M) bson.M {
+		return bson.M{"$or": bson.A{
+			bson.M{"pinned": bson.M{"$lt": false}},
+			bson.M{"pinned": false, "$and": bson.A{sorted}},
+		}}
+	}
+
+	tests := []struct {
+		sort string
+		want bson.M
+	}{
+		{constants.RoomSortActivity, pinnedFirst(activity)},
+		{constants.RoomSortName, pinnedFirst(bson.M{"$or": bson.A{
+			bson.M{"name": bson.M{"$gt": "general"}},
+			bson.M{"name": "general", "_id": bson.M{"$gt": objectID}},
+		}})},
+		{constants.RoomSortUnread, pinnedFirst(bson.M{"$or": bson.A{
+			bson.M{"has_unread": bson.M{"$lt": false}},
+			bson.M{"has_unread": false, "$and": bson.A{activity}},
+		}})},
+	}
+	for _, tt := range tests {
+		c, err := decodeRoomListCursor(roomListCursorFor(tt.sort, &room), tt.sort)
+		if err != nil {
+			t.Fatalf("%s: decode failed: %v", tt.sort, err)
+		}
+		if got := c.after(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: after() = %v, want %v", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestUserRoomsSort(t *testing.T) {
+	keys := func(d bson.D) []string {
+		names := make([]string, len(d))
+		for i, e := range d {
+			names[i] = e.Key
+		}
+		return names
+	}
+
+	tests := map[string][]string{
+		constants.RoomSortActivity: {"pinned", "last_message_at", "_id"},
+		constants.RoomSortName:     {"pinned", "name", "_id"},
+		constants.RoomSortUnread:   {"pinned", "has_unread", "last_message_at", "_id"},
+	}
+	for sort, want := range tests {
+		if got := keys(userRoomsSort(sort)); !reflect.DeepEqual(got, want) {
+			t.Errorf("userRoomsSort(%s) = %v, want %v", sort, got, want)
+		}
+	}
+}
+
+func TestUserRoomsPipeline(t *testing.T) {
+	room := NewChatRoom()
+	cursor := roomListCursorFor(constants.RoomSortUnread, &room)
+	now := time.Now()
+
+	embedded := UserRoomsPipeline(bson.M{}, "alice", constants.RoomSortUnread, false, cursor, 20, now)
+	if stage := embedded[1][0]; stage.Key != "$set" {
+		t.Errorf("embedded members should be read from the room document, got %s", stage.Key)
+	}
+
+	separate := UserRoomsPipeline(bson.M{}, "alice", constants.RoomSortActivity, true, "", 20, now)
+	lookup := separate[1][0]
+	if lookup.Key != "$lookup" || lookup.Value.(bson.M)["from"] != memberCollectionName {
+		t.Errorf("member collection should be joined for the user's membership, got %v", lookup)
+	}
+
+	// 只有按未讀排序時才查找訊息
+	hasMessageLookup := func(pipeline []bson.D) bool {
+		for _, stage := range pipeline {
+			if stage[0].Key == "$lookup" && stage[0].Value.(bson.M)["from"] == "messages" {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasMessageLookup(embedded) || hasMessageLookup(separate) {
+		t.Error("messages should only be looked up for the unread sort")
+	}
+
+	// 游標條件要在計算出 pinned / has_unread 之後、排序之前應用
+	if stage := embedded[len(embedded)-3][0]; stage.Key != "$match" {
+		t.Errorf("cursor stage = %s, want $match before $sort", stage.Key)
+	}
+	if stage := separate[len(separate)-3][0]; stage.Key != "$unset" {
+		t.Errorf("stage before $sort without cursor = %s, want $unset", stage.Key)
+	}
+
+	last := embedded[len(embedded)-1][0]
+	if last.Key != "$limit" || last.Value != int64(21) {
+		t.Errorf("last stage = %v, want $limit 21", last)
+	}
+}
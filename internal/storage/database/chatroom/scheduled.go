@@ -0,0 +1,207 @@
+package chatroom
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// scheduledCollectionName 定時訊息集合
+const scheduledCollectionName = "scheduled_messages"
+
+// 定時訊息狀態
+const (
+	ScheduledStatusPending    = "pending"    // 等待發送
+	ScheduledStatusProcessing = "processing" // 已被發送任務領取
+	ScheduledStatusSent       = "sent"       // 已發送到 messages 集合
+	ScheduledStatusCancelled  = "cancelled"  // 已被發送者取消
+	ScheduledStatusFailed     = "failed"     // 發送失敗（例如發送者已不是成員）
+)
+
+// ErrScheduledNotFound 定時訊息不存在、不屬於該用戶或已不是待發送狀態
+var ErrScheduledNotFound = errors.New("scheduled message not found or no longer pending")
+
+// ScheduledMessage 定時訊息
+// 內容在創建時以聊天室密鑰加密存儲，發送時解密後按當前密鑰重新加密寫入 messages 集合
+// MessageID 在創建時預先分配，發送任務重啟後重複處理時寫入會因主鍵衝突失敗，不會重複發送
+type ScheduledMessage struct {
+	ID         bson.ObjectID   `bson:"_id"`
+	RoomID     string          `bson:"room_id"`
+	SenderID   string          `bson:"sender_id"`
+	Content    string          `bson:"content"`
+	KeyVersion int             `bson:"key_version,omitempty"` // 加密內容所用的密鑰版本（0 表示未加密）
+	Type       string          `bson:"type"`
+	Metadata   MessageMetadata `bson:"metadata"`
+	Mentions   []string        `bson:"mentions,omitempty"`    // 請求中指定的提及，發送時重新解析
+	TTLSeconds int32           `bson:"ttl_seconds,omitempty"` // 限時訊息的存活時間，從實際發送時開始計算
+	SendAt     time.Time       `bson:"send_at"`
+	Status     string          `bson:"status"`
+	MessageID  string          `bson:"message_id"`
+	Error      string          `bson:"error,omitempty"`
+	ClaimedAt  *time.Time      `bson:"claimed_at,omitempty"`
+	CreatedAt  time.Time       `bson:"created_at"`
+	UpdatedAt  time.Time       `bson:"updated_at"`
+}
+
+// ScheduledMessageStore 定時訊息存儲
+type ScheduledMessageStore struct {
+	collection *mongo.Collection
+}
+
+// NewScheduledMessageStore 創建定時訊息存儲
+func NewScheduledMessageStore(db *mongo.Database) *ScheduledMessageStore {
+	return &ScheduledMessageStore{
+		collection: db.Collection(scheduledCollectionName),
+	}
+}
+
+// Create 保存待發送的定時訊息，同時預先分配發送後的訊息 ID
+func (s *ScheduledMessageStore) Create(ctx context.Context, message *ScheduledMessage) error {
+	now := time.Now()
+	message.ID = bson.NewObjectID()
+	message.MessageID = bson.NewObjectID().Hex()
+	message.Status = ScheduledStatusPending
+	message.CreatedAt = now
+	message.UpdatedAt = now
+
+	_, err := s.collection.InsertOne(ctx, message)
+	return err
+}
+
+// CountPending 統計用戶待發送的定時訊息數量
+func (s *ScheduledMessageStore) CountPending(ctx context.Context, senderID string) (int64, error) {
+	return s.collection.CountDocuments(ctx, bson.M{
+		"sender_id": senderID,
+		"status":    ScheduledStatusPending,
+	})
+}
+
+// ListPending 列出用戶待發送的定時訊息（按發送時間升序），roomID 為空時不限聊天室
+func (s *ScheduledMessageStore) ListPending(ctx context.Context, senderID, roomID string, limit int) ([]*ScheduledMessage, error) {
+	filter := bson.M{
+		"sender_id": senderID,
+		"status":    ScheduledStatusPending,
+	}
+	if roomID != "" {
+		filter["room_id"] = roomID
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "send_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := s.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = cursor.Close(ctx) }()
+
+	var messages []*ScheduledMessage
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// Cancel 取消待發送的定時訊息；已被領取、已發送或不屬於該用戶時返回 ErrScheduledNotFound
+func (s *ScheduledMessageStore) Cancel(ctx context.Context, id, senderID string) error {
+	objectID, err := parseObjectID(id)
+	if err != nil {
+		return ErrScheduledNotFound
+	}
+
+	result, err := s.collection.UpdateOne(ctx, bson.M{
+		"_id":       objectID,
+		"sender_id": senderID,
+		"status":    ScheduledStatusPending,
+	}, bson.M{"$set": bson.M{
+		"status":     ScheduledStatusCancelled,
+		"updated_at": time.Now(),
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrScheduledNotFound
+	}
+	return nil
+}
+
+// ClaimDue 原子地領取一條已到發送時間的定時訊息，沒有時返回 nil
+// 領取超過 staleBefore 仍未完成的訊息（例如發送任務中途重啟）會被重新領取
+func (s *ScheduledMessageStore) ClaimDue(ctx context.Context, now, staleBefore time.Time) (*ScheduledMessage, error) {
+	filter := DueScheduledFilter(now, staleBefore)
+	update := bson.M{"$set": bson.M{
+		"status":     ScheduledStatusProcessing,
+		"claimed_at": now,
+		"updated_at": now,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "send_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var message ScheduledMessage
+	err := s.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// DueScheduledFilter 待領取的定時訊息：已到發送時間的待發送訊息，或領取後超時未完成的訊息
+func DueScheduledFilter(now, staleBefore time.Time) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"status": ScheduledStatusPending, "send_at": bson.M{"$lte": now}},
+		bson.M{"status": ScheduledStatusProcessing, "claimed_at": bson.M{"$lt": staleBefore}},
+	}}
+}
+
+// MarkSent 標記定時訊息已發送
+func (s *ScheduledMessageStore) MarkSent(ctx context.Context, id bson.ObjectID) error {
+	return s.finish(ctx, id, bson.M{"status": ScheduledStatusSent})
+}
+
+// MarkFailed 標記定時訊息發送失敗並記錄原因
+func (s *ScheduledMessageStore) MarkFailed(ctx context.Context, id bson.ObjectID, reason string) error {
+	return s.finish(ctx, id, bson.M{"status": ScheduledStatusFailed, "error": reason})
+}
+
+// finish 結束處理中的定時訊息
+func (s *ScheduledMessageStore) finish(ctx context.Context, id bson.ObjectID, set bson.M) error {
+	set["updated_at"] = time.Now()
+	_, err := s.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "status": ScheduledStatusProcessing},
+		bson.M{"$set": set})
+	return err
+}
+
+// DeleteBySender 刪除用戶的所有定時訊息（GDPR 刪除用戶數據時使用），返回刪除數量
+func (s *ScheduledMessageStore) DeleteBySender(ctx context.Context, senderID string) (int64, error) {
+	result, err := s.collection.DeleteMany(ctx, bson.M{"sender_id": senderID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// scheduledIndexModels 定時訊息集合索引
+func scheduledIndexModels() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		// 發送任務按狀態和發送時間領取
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "send_at", Value: 1}},
+			Options: options.Index().SetName("status_send_at_idx"),
+		},
+		// 用戶列出待發送的定時訊息
+		{
+			Keys:    bson.D{{Key: "sender_id", Value: 1}, {Key: "status", Value: 1}, {Key: "send_at", Value: 1}},
+			Options: options.Index().SetName("sender_status_send_at_idx"),
+		},
+	}
+}
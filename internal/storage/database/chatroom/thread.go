@@ -0,0 +1,142 @@
+package chatroom
+
+import (
+	"context"
+	"time"
+
+	"chat-gateway/internal/constants"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ThreadRepliesFilter 直接回覆 parentIDs 中任一訊息的未過期訊息（使用 reply_to_message_id 索引）
+func ThreadRepliesFilter(roomID string, parentIDs []string, now time.Time) bson.M {
+	return bson.M{
+		"room_id":             roomID,
+		"reply_to_message_id": bson.M{"$in": parentIDs},
+		"expires_at":          notExpiredFilter(now),
+	}
+}
+
+// ThreadPageFilter 討論串中晚於游標的訊息（討論串按創建時間正序翻頁）
+func ThreadPageFilter(ids []bson.ObjectID, cursor string, now time.Time) bson.M {
+	filter := bson.M{
+		"_id":        bson.M{"$in": ids},
+		"expires_at": notExpiredFilter(now),
+	}
+	applyNewerThanCursor(filter, "created_at", cursor)
+	return filter
+}
+
+// ReplyCountPipeline 統計每條訊息的直接回覆數量（沒有回覆的訊息不會出現在結果中）
+func ReplyCountPipeline(roomID string, messageIDs []string, now time.Time) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: ThreadRepliesFilter(roomID, messageIDs, now)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$reply_to_message_id",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+}
+
+// ThreadReplyIDs 沿回覆鏈逐層展開，返回回覆鏈最終指向 rootID 的所有訊息 ID（不包含根訊息）
+// 每層按創建時間正序查詢；超過 MaxThreadMessages 條或 MaxThreadDepth 層的回覆不會返回
+func (s *MessageStore) ThreadReplyIDs(ctx context.Context, roomID, rootID string) ([]string, error) {
+	now := time.Now()
+	seen := map[string]bool{rootID: true}
+	ids := []string{}
+	frontier := []string{rootID}
+
+	for depth := 0; depth < constants.MaxThreadDepth && len(frontier) > 0 && len(ids) < constants.MaxThreadMessages; depth++ {
+		opts := options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+			SetLimit(int64(constants.MaxThreadMessages - len(ids))).
+			SetProjection(bson.M{"id": 1})
+
+		cursor, err := s.collection.Find(ctx, ThreadRepliesFilter(roomID, frontier, now), opts)
+		if err != nil {
+			return nil, err
+		}
+		var replies []struct {
+			ID string `bson:"id"`
+		}
+		if err := cursor.All(ctx, &replies); err != nil {
+			return nil, err
+		}
+
+		next := make([]string, 0, len(replies))
+		for _, reply := range replies {
+			if reply.ID == "" || seen[reply.ID] {
+				continue
+			}
+			seen[reply.ID] = true
+			ids = append(ids, reply.ID)
+			next = append(next, reply.ID)
+		}
+		frontier = next
+	}
+	return ids, nil
+}
+
+// GetThread 分頁獲取討論串中的回覆（按創建時間正序，舊回覆在前），游標格式與 GetByRoomID 相同
+func (s *MessageStore) GetThread(
+	ctx context.Context,
+	roomID, rootID string,
+	limit int,
+	cursor string,
+) (messages []*Message, nextCursor string, hasMore bool, err error) {
+	limit = normalizePaginationLimit(limit)
+
+	replyIDs, err := s.ThreadReplyIDs(ctx, roomID, rootID)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if len(replyIDs) == 0 {
+		return []*Message{}, "", false, nil
+	}
+
+	objectIDs := make([]bson.ObjectID, 0, len(replyIDs))
+	for _, id := range replyIDs {
+		if objectID, err := parseObjectID(id); err == nil {
+			objectIDs = append(objectIDs, objectID)
+		}
+	}
+
+	opts := buildMessageFindOptions(limit).
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	messages, err = s.executeMessageQuery(ctx, ThreadPageFilter(objectIDs, cursor, time.Now()), opts)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	messages, hasMore, nextCursor = s.processPaginationResult(messages, limit)
+	return messages, nextCursor, hasMore, nil
+}
+
+// CountReplies 統計訊息的直接回覆數量（訊息 ID -> 回覆數，沒有回覆的訊息不在結果中）
+func (s *MessageStore) CountReplies(ctx context.Context, roomID string, messageIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(messageIDs) == 0 {
+		return counts, nil
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, ReplyCountPipeline(roomID, messageIDs, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		ID    string `bson:"_id"`
+		Count int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		counts[result.ID] = result.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,62 @@
+package chatroom
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestThreadRepliesFilter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	filter := ThreadRepliesFilter("room-1", []string{"a", "b"}, now)
+
+	want := bson.M{
+		"room_id":             "room-1",
+		"reply_to_message_id": bson.M{"$in": []string{"a", "b"}},
+		"expires_at":          notExpiredFilter(now),
+	}
+	if !reflect.DeepEqual(filter, want) {
+		t.Errorf("ThreadRepliesFilter() = %v, want %v", filter, want)
+	}
+}
+
+func TestThreadPageFilter(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	ids := []bson.ObjectID{bson.NewObjectID()}
+
+	filter := ThreadPageFilter(ids, "", now)
+	if _, ok := filter["$and"]; ok {
+		t.Errorf("ThreadPageFilter() without cursor = %v, want no cursor condition", filter)
+	}
+
+	cursorID := bson.NewObjectID()
+	cursor := EncodeMessageCursor(now, cursorID.Hex())
+	filter = ThreadPageFilter(ids, cursor, now)
+
+	want := bson.A{bson.M{"$or": bson.A{
+		bson.M{"created_at": bson.M{"$gt": now.UTC()}},
+		bson.M{"created_at": now.UTC(), "_id": bson.M{"$gt": cursorID}},
+	}}}
+	if !reflect.DeepEqual(filter["$and"], want) {
+		t.Errorf("ThreadPageFilter() $and = %v, want %v", filter["$and"], want)
+	}
+}
+
+func TestReplyCountPipeline(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	pipeline := ReplyCountPipeline("room-1", []string{"a"}, now)
+
+	want := mongo.Pipeline{
+		{{Key: "$match", Value: ThreadRepliesFilter("room-1", []string{"a"}, now)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$reply_to_message_id",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+	if !reflect.DeepEqual(pipeline, want) {
+		t.Errorf("ReplyCountPipeline() = %v, want %v", pipeline, want)
+	}
+}
@@ -1,8 +1,6 @@
 package database
 
 import (
-	"context"
-
 	"chat-gateway/internal/platform/config"
 	"chat-gateway/internal/storage/database/chatroom"
 
@@ -11,8 +9,9 @@ import (
 
 // Repositories 倉儲集合.
 type Repositories struct {
-	ChatRoom *chatroom.ChatRoomStore
-	Message  *chatroom.MessageStore
+	ChatRoom  *chatroom.ChatRoomStore
+	Message   *chatroom.MessageStore
+	Scheduled *chatroom.ScheduledMessageStore
 }
 
 // NewRepositories 創建倉儲集合.
@@ -25,16 +24,15 @@ func NewRepositories(cfg *config.Config) *Repositories {
 		return nil
 	}
 
-	// 創建索引以優化查詢性能
-	ctx := context.Background()
-	if err := chatroom.CreateIndexes(ctx, db); err != nil {
-		// 索引創建失敗不影響服務啟動，繼續運行
-		_ = err // 忽略索引創建錯誤
+	chatRooms := chatroom.NewChatRoomStore(db)
+	if cfg != nil && cfg.Database.Mongo.MemberCollection {
+		chatRooms.UseMemberCollection()
 	}
 
 	return &Repositories{
-		ChatRoom: chatroom.NewChatRoomStore(db),
-		Message:  chatroom.NewMessageStore(db),
+		ChatRoom:  chatRooms,
+		Message:   chatroom.NewMessageStore(db),
+		Scheduled: chatroom.NewScheduledMessageStore(db),
 	}
 }
 
@@ -45,6 +45,14 @@ func SafeRegexQuery(pattern string) bson.M {
 	}
 }
 
+// SafePrefixRegexQuery 創建安全的前綴匹配查詢（轉義特殊字符，不區分大小寫）
+func SafePrefixRegexQuery(prefix string) bson.M {
+	return bson.M{
+		"$regex":   "^" + regexp.QuoteMeta(prefix),
+		"$options": "i",
+	}
+}
+
 // ValidateQueryOperators 驗證查詢中不包含危險的操作符
 func ValidateQueryOperators(query interface{}) error {
 	switch v := query.(type) {
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// compileQuery 按 MongoDB 的 $options: "i" 語義編譯查詢中的正則
+func compileQuery(t *testing.T, query bson.M) *regexp.Regexp {
+	t.Helper()
+	if query["$options"] != "i" {
+		t.Fatalf("$options = %v, want i", query["$options"])
+	}
+	re, err := regexp.Compile("(?i)" + query["$regex"].(string))
+	if err != nil {
+		t.Fatalf("regex %q does not compile: %v", query["$regex"], err)
+	}
+	return re
+}
+
+func TestSafeRegexQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		input   string
+		want    bool
+	}{
+		{"substring", "team", "The Team Room", true},
+		{"case insensitive", "TEAM", "my team", true},
+		{"no match", "team", "family", false},
+		{"dot is literal", "a.c", "abc", false},
+		{"dot matches itself", "a.c", "xa.cx", true},
+		{"wildcard is literal", ".*", "anything", false},
+		{"alternation is literal", "a|b", "b", false},
+		{"anchor is literal", "^room$", "room", false},
+		{"brackets are literal", "[abc]", "a", false},
+		{"brackets match themselves", "[abc]", "x[abc]y", true},
+		{"chinese", "聊天", "我的聊天室", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := compileQuery(t, SafeRegexQuery(tt.pattern))
+			if got := re.MatchString(tt.input); got != tt.want {
+				t.Errorf("SafeRegexQuery(%q) matching %q = %v, want %v", tt.pattern, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafePrefixRegexQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		input  string
+		want   bool
+	}{
+		{"prefix", "team", "Team Alpha", true},
+		{"not a prefix", "alpha", "Team Alpha", false},
+		{"dot is literal", "a.", "ab", false},
+		{"plus is literal", "c++", "c++ fans", true},
+		{"group is literal", "(a)", "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := compileQuery(t, SafePrefixRegexQuery(tt.prefix))
+			if got := re.MatchString(tt.input); got != tt.want {
+				t.Errorf("SafePrefixRegexQuery(%q) matching %q = %v, want %v", tt.prefix, tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeRegexQuery_CatastrophicPattern(t *testing.T) {
+	// 未轉義時 (a+)+$ 在不匹配的長輸入上會指數級回溯；轉義後只是普通字符串
+	query := SafeRegexQuery("(a+)+$")
+	if got := query["$regex"]; got != `\(a\+\)\+\$` {
+		t.Errorf("$regex = %v, want escaped pattern", got)
+	}
+
+	re := compileQuery(t, query)
+	input := make([]byte, 10000)
+	for i := range input {
+		input[i] = 'a'
+	}
+	start := time.Now()
+	if re.Match(append(input, '!')) {
+		t.Error("escaped pattern should not match plain input")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("matching took %v", elapsed)
+	}
+}
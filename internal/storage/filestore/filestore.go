@@ -0,0 +1,42 @@
+// Package filestore 上傳文件的存儲（頭像、訊息附件）
+package filestore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"path"
+)
+
+// Store 文件存儲（本地磁盤或對象存儲）
+type Store interface {
+	// Save 保存文件並返回可公開訪問的 URL
+	Save(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Delete 刪除 Save 保存的文件（不存在時不報錯）
+	Delete(ctx context.Context, key string) error
+}
+
+// 支持的存儲後端
+const (
+	BackendLocal = "local" // 本地磁盤，由 HTTP 服務提供靜態訪問
+)
+
+// New 創建指定後端的文件存儲（backend 為空時使用本地磁盤）
+func New(backend, localDir, baseURL string) (Store, error) {
+	switch backend {
+	case "", BackendLocal:
+		return NewLocalStore(localDir, baseURL), nil
+	default:
+		return nil, fmt.Errorf("unsupported file storage backend: %s", backend)
+	}
+}
+
+// RandomKey 在 prefix 下生成隨機文件名的存儲鍵（每次上傳使用新的文件名，避免 CDN 和瀏覽器緩存舊文件）
+func RandomKey(prefix, ext string) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return path.Join(prefix, hex.EncodeToString(id)+ext), nil
+}
@@ -0,0 +1,84 @@
+package filestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore 把文件保存在本地目錄，URL 為 baseURL + 存儲鍵
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStore 創建本地文件存儲
+func NewLocalStore(dir, baseURL string) *LocalStore {
+	return &LocalStore{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// Dir 文件保存目錄
+func (s *LocalStore) Dir() string {
+	return s.dir
+}
+
+// BaseURL 文件 URL 前綴
+func (s *LocalStore) BaseURL() string {
+	return s.baseURL
+}
+
+// Save 保存文件（先寫入臨時文件再重命名，讀取方不會看到寫了一半的文件）
+func (s *LocalStore) Save(_ context.Context, key, _ string, data []byte) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name()) // #nosec G104 -- 重命名成功後臨時文件已不存在
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() // #nosec G104 -- 寫入已失敗
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil { // #nosec G302 -- 文件需要被靜態文件服務讀取
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+
+	return s.baseURL + "/" + filepath.ToSlash(key), nil
+}
+
+// Delete 刪除文件
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path 存儲鍵對應的文件路徑（拒絕跳出保存目錄的鍵）
+func (s *LocalStore) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("invalid file key: %s", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,52 @@
+package filestore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	data := []byte("file content")
+	dir := t.TempDir()
+	store := NewLocalStore(dir, "/avatars/")
+	ctx := context.Background()
+
+	url, err := store.Save(ctx, "rooms/room-1/a.png", "image/png", data)
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if url != "/avatars/rooms/room-1/a.png" {
+		t.Errorf("Save() url = %q", url)
+	}
+
+	path := filepath.Join(dir, "rooms", "room-1", "a.png")
+	if data, err := os.ReadFile(path); err != nil || string(data) != string(data) {
+		t.Fatalf("saved file = %q, %v", data, err)
+	}
+
+	if err := store.Delete(ctx, "rooms/room-1/a.png"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("file still exists after Delete(): %v", err)
+	}
+	if err := store.Delete(ctx, "rooms/room-1/a.png"); err != nil {
+		t.Errorf("Delete() of missing file error = %v", err)
+	}
+
+	// 存儲鍵不能跳出保存目錄
+	if _, err := store.Save(ctx, "../escape.png", "image/png", data); err == nil {
+		t.Error("expected error for key outside the store directory")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("", t.TempDir(), "/files"); err != nil {
+		t.Errorf("New(default) error = %v", err)
+	}
+	if _, err := New("s3", t.TempDir(), "/files"); err == nil {
+		t.Error("expected error for unsupported backend")
+	}
+}
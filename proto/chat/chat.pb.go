@@ -37,6 +37,14 @@ type ChatRoom struct {
 	LastMessageAt   int64                  `protobuf:"varint,9,opt,name=last_message_at,json=lastMessageAt,proto3" json:"last_message_at,omitempty"`
 	LastMessage     string                 `protobuf:"bytes,10,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
 	LastMessageTime int64                  `protobuf:"varint,11,opt,name=last_message_time,json=lastMessageTime,proto3" json:"last_message_time,omitempty"`
+	AvatarUrl       string                 `protobuf:"bytes,12,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Muted           bool                   `protobuf:"varint,13,opt,name=muted,proto3" json:"muted,omitempty"`                                // 請求用戶是否已靜音此聊天室（僅 ListUserRooms 填充）
+	MutedUntil      int64                  `protobuf:"varint,14,opt,name=muted_until,json=mutedUntil,proto3" json:"muted_until,omitempty"`    // 限時靜音的結束時間（0 表示永久靜音或未靜音）
+	Archived        bool                   `protobuf:"varint,15,opt,name=archived,proto3" json:"archived,omitempty"`                          // 請求用戶是否已封存此聊天室（僅 ListUserRooms 填充）
+	Version         int64                  `protobuf:"varint,16,opt,name=version,proto3" json:"version,omitempty"`                            // 聊天室信息或成員變更時遞增，更新時可作為 expected_version
+	MemberCount     int32                  `protobuf:"varint,17,opt,name=member_count,json=memberCount,proto3" json:"member_count,omitempty"` // 成員數量（僅 ListRooms 填充，此時不返回成員列表）
+	Pinned          bool                   `protobuf:"varint,18,opt,name=pinned,proto3" json:"pinned,omitempty"`                              // 請求用戶是否已置頂此聊天室（僅 ListUserRooms 填充）
+	UnreadCount     int32                  `protobuf:"varint,19,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"` // 請求用戶的未讀數量（僅 ListUserRooms 填充）
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -148,6 +156,62 @@ func (x *ChatRoom) GetLastMessageTime() int64 {
 	return 0
 }
 
+func (x *ChatRoom) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *ChatRoom) GetMuted() bool {
+	if x != nil {
+		return x.Muted
+	}
+	return false
+}
+
+func (x *ChatRoom) GetMutedUntil() int64 {
+	if x != nil {
+		return x.MutedUntil
+	}
+	return 0
+}
+
+func (x *ChatRoom) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *ChatRoom) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ChatRoom) GetMemberCount() int32 {
+	if x != nil {
+		return x.MemberCount
+	}
+	return 0
+}
+
+func (x *ChatRoom) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *ChatRoom) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
 // 聊天室成員
 type RoomMember struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -158,6 +222,8 @@ type RoomMember struct {
 	JoinedAt      int64                  `protobuf:"varint,5,opt,name=joined_at,json=joinedAt,proto3" json:"joined_at,omitempty"`
 	LastSeen      int64                  `protobuf:"varint,6,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"`
 	LastReadAt    int64                  `protobuf:"varint,7,opt,name=last_read_at,json=lastReadAt,proto3" json:"last_read_at,omitempty"`
+	Muted         bool                   `protobuf:"varint,8,opt,name=muted,proto3" json:"muted,omitempty"`                             // 當前是否處於靜音狀態
+	MutedUntil    int64                  `protobuf:"varint,9,opt,name=muted_until,json=mutedUntil,proto3" json:"muted_until,omitempty"` // 限時靜音的結束時間（0 表示永久靜音或未靜音）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -241,6 +307,20 @@ func (x *RoomMember) GetLastReadAt() int64 {
 	return 0
 }
 
+func (x *RoomMember) GetMuted() bool {
+	if x != nil {
+		return x.Muted
+	}
+	return false
+}
+
+func (x *RoomMember) GetMutedUntil() int64 {
+	if x != nil {
+		return x.MutedUntil
+	}
+	return 0
+}
+
 // 聊天室設置
 type RoomSettings struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
@@ -250,6 +330,9 @@ type RoomSettings struct {
 	AllowPinMessages    bool                   `protobuf:"varint,4,opt,name=allow_pin_messages,json=allowPinMessages,proto3" json:"allow_pin_messages,omitempty"`
 	MaxMembers          int32                  `protobuf:"varint,5,opt,name=max_members,json=maxMembers,proto3" json:"max_members,omitempty"`
 	WelcomeMessage      string                 `protobuf:"bytes,6,opt,name=welcome_message,json=welcomeMessage,proto3" json:"welcome_message,omitempty"`
+	Mode                string                 `protobuf:"bytes,7,opt,name=mode,proto3" json:"mode,omitempty"`                                               // normal, slowmode, announcement, read_only
+	SlowmodeSeconds     int32                  `protobuf:"varint,8,opt,name=slowmode_seconds,json=slowmodeSeconds,proto3" json:"slowmode_seconds,omitempty"` // 慢速模式下每個成員兩次發言的最小間隔
+	Locale              string                 `protobuf:"bytes,9,opt,name=locale,proto3" json:"locale,omitempty"`                                           // 系統訊息的語言（如 en、zh-tw），為空時使用 limits.system_message.default_locale
 	unknownFields       protoimpl.UnknownFields
 	sizeCache           protoimpl.SizeCache
 }
@@ -326,21 +409,52 @@ func (x *RoomSettings) GetWelcomeMessage() string {
 	return ""
 }
 
+func (x *RoomSettings) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *RoomSettings) GetSlowmodeSeconds() int32 {
+	if x != nil {
+		return x.SlowmodeSeconds
+	}
+	return 0
+}
+
+func (x *RoomSettings) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
 // 聊天消息
 type ChatMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	SenderId      string                 `protobuf:"bytes,3,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
-	Type          string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"` // text, image, file, etc.
-	Metadata      *MessageMetadata       `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     int64                  `protobuf:"varint,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	ReadBy        []string               `protobuf:"bytes,9,rep,name=read_by,json=readBy,proto3" json:"read_by,omitempty"`
-	DeliveredTo   []string               `protobuf:"bytes,10,rep,name=delivered_to,json=deliveredTo,proto3" json:"delivered_to,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RoomId           string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	SenderId         string                 `protobuf:"bytes,3,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	Content          string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	Type             string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"` // text, image, file, etc.
+	Metadata         *MessageMetadata       `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	CreatedAt        int64                  `protobuf:"varint,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        int64                  `protobuf:"varint,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ReadBy           []string               `protobuf:"bytes,9,rep,name=read_by,json=readBy,proto3" json:"read_by,omitempty"`
+	DeliveredTo      []string               `protobuf:"bytes,10,rep,name=delivered_to,json=deliveredTo,proto3" json:"delivered_to,omitempty"`
+	Mentions         []string               `protobuf:"bytes,11,rep,name=mentions,proto3" json:"mentions,omitempty"`                                             // 被提及的用戶 ID
+	ForwardedFrom    *ForwardedFrom         `protobuf:"bytes,12,opt,name=forwarded_from,json=forwardedFrom,proto3" json:"forwarded_from,omitempty"`              // 轉發來源（非轉發訊息時為空）
+	ReadReceipts     []*ReadReceipt         `protobuf:"bytes,13,rep,name=read_receipts,json=readReceipts,proto3" json:"read_receipts,omitempty"`                 // 已讀回執（含已讀時間）；read_by 保留用於兼容舊客戶端
+	Status           string                 `protobuf:"bytes,14,opt,name=status,proto3" json:"status,omitempty"`                                                 // sent / delivered / read（所有接收者中最低的狀態）
+	Cursor           string                 `protobuf:"bytes,15,opt,name=cursor,proto3" json:"cursor,omitempty"`                                                 // 訊息位置游標（僅訊息流推送時填充），可作為 since_cursor 斷線續傳
+	ExpiresAt        int64                  `protobuf:"varint,16,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`                         // 限時訊息的過期時間（Unix 秒），0 表示不會過期
+	Expired          bool                   `protobuf:"varint,17,opt,name=expired,proto3" json:"expired,omitempty"`                                              // 訊息流推送的過期事件：訊息已過期，客戶端應從畫面移除（只填充 id 和 room_id）
+	SenderName       string                 `protobuf:"bytes,18,opt,name=sender_name,json=senderName,proto3" json:"sender_name,omitempty"`                       // 發送者顯示名稱（目前只有系統訊息填充，來自 limits.system_message.sender_name）
+	ReplyToMessageId string                 `protobuf:"bytes,19,opt,name=reply_to_message_id,json=replyToMessageId,proto3" json:"reply_to_message_id,omitempty"` // 回覆的訊息 ID（非回覆訊息時為空）
+	ReplyCount       int32                  `protobuf:"varint,20,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`                      // 直接回覆數量（只有 GetMessages 和 GetThread 填充）
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *ChatMessage) Reset() {
@@ -443,6 +557,190 @@ func (x *ChatMessage) GetDeliveredTo() []string {
 	return nil
 }
 
+func (x *ChatMessage) GetMentions() []string {
+	if x != nil {
+		return x.Mentions
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetForwardedFrom() *ForwardedFrom {
+	if x != nil {
+		return x.ForwardedFrom
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetReadReceipts() []*ReadReceipt {
+	if x != nil {
+		return x.ReadReceipts
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ChatMessage) GetExpired() bool {
+	if x != nil {
+		return x.Expired
+	}
+	return false
+}
+
+func (x *ChatMessage) GetSenderName() string {
+	if x != nil {
+		return x.SenderName
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetReplyToMessageId() string {
+	if x != nil {
+		return x.ReplyToMessageId
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetReplyCount() int32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+// 已讀回執
+type ReadReceipt struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ReadAt        int64                  `protobuf:"varint,2,opt,name=read_at,json=readAt,proto3" json:"read_at,omitempty"` // 首次已讀時間（Unix 秒）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadReceipt) Reset() {
+	*x = ReadReceipt{}
+	mi := &file_proto_chat_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadReceipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadReceipt) ProtoMessage() {}
+
+func (x *ReadReceipt) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadReceipt.ProtoReflect.Descriptor instead.
+func (*ReadReceipt) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReadReceipt) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReadReceipt) GetReadAt() int64 {
+	if x != nil {
+		return x.ReadAt
+	}
+	return 0
+}
+
+// 轉發來源
+type ForwardedFrom struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	SenderId      string                 `protobuf:"bytes,3,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"` // 原始發送者
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardedFrom) Reset() {
+	*x = ForwardedFrom{}
+	mi := &file_proto_chat_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardedFrom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardedFrom) ProtoMessage() {}
+
+func (x *ForwardedFrom) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardedFrom.ProtoReflect.Descriptor instead.
+func (*ForwardedFrom) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ForwardedFrom) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ForwardedFrom) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ForwardedFrom) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
 // 消息元數據
 type MessageMetadata struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
@@ -463,7 +761,7 @@ type MessageMetadata struct {
 
 func (x *MessageMetadata) Reset() {
 	*x = MessageMetadata{}
-	mi := &file_proto_chat_proto_msgTypes[4]
+	mi := &file_proto_chat_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -475,7 +773,7 @@ func (x *MessageMetadata) String() string {
 func (*MessageMetadata) ProtoMessage() {}
 
 func (x *MessageMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[4]
+	mi := &file_proto_chat_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -488,7 +786,7 @@ func (x *MessageMetadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageMetadata.ProtoReflect.Descriptor instead.
 func (*MessageMetadata) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{4}
+	return file_proto_chat_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *MessageMetadata) GetFileName() string {
@@ -582,7 +880,7 @@ type CreateRoomRequest struct {
 
 func (x *CreateRoomRequest) Reset() {
 	*x = CreateRoomRequest{}
-	mi := &file_proto_chat_proto_msgTypes[5]
+	mi := &file_proto_chat_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -594,7 +892,7 @@ func (x *CreateRoomRequest) String() string {
 func (*CreateRoomRequest) ProtoMessage() {}
 
 func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[5]
+	mi := &file_proto_chat_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -607,7 +905,7 @@ func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoomRequest.ProtoReflect.Descriptor instead.
 func (*CreateRoomRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{5}
+	return file_proto_chat_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CreateRoomRequest) GetName() string {
@@ -656,7 +954,7 @@ type CreateRoomResponse struct {
 
 func (x *CreateRoomResponse) Reset() {
 	*x = CreateRoomResponse{}
-	mi := &file_proto_chat_proto_msgTypes[6]
+	mi := &file_proto_chat_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -668,7 +966,7 @@ func (x *CreateRoomResponse) String() string {
 func (*CreateRoomResponse) ProtoMessage() {}
 
 func (x *CreateRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[6]
+	mi := &file_proto_chat_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -681,7 +979,7 @@ func (x *CreateRoomResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoomResponse.ProtoReflect.Descriptor instead.
 func (*CreateRoomResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{6}
+	return file_proto_chat_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *CreateRoomResponse) GetSuccess() bool {
@@ -715,7 +1013,7 @@ type JoinRoomRequest struct {
 
 func (x *JoinRoomRequest) Reset() {
 	*x = JoinRoomRequest{}
-	mi := &file_proto_chat_proto_msgTypes[7]
+	mi := &file_proto_chat_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -727,7 +1025,7 @@ func (x *JoinRoomRequest) String() string {
 func (*JoinRoomRequest) ProtoMessage() {}
 
 func (x *JoinRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[7]
+	mi := &file_proto_chat_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -740,7 +1038,7 @@ func (x *JoinRoomRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinRoomRequest.ProtoReflect.Descriptor instead.
 func (*JoinRoomRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{7}
+	return file_proto_chat_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *JoinRoomRequest) GetRoomId() string {
@@ -767,7 +1065,7 @@ type JoinRoomResponse struct {
 
 func (x *JoinRoomResponse) Reset() {
 	*x = JoinRoomResponse{}
-	mi := &file_proto_chat_proto_msgTypes[8]
+	mi := &file_proto_chat_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -779,7 +1077,7 @@ func (x *JoinRoomResponse) String() string {
 func (*JoinRoomResponse) ProtoMessage() {}
 
 func (x *JoinRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[8]
+	mi := &file_proto_chat_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -792,7 +1090,7 @@ func (x *JoinRoomResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinRoomResponse.ProtoReflect.Descriptor instead.
 func (*JoinRoomResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{8}
+	return file_proto_chat_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *JoinRoomResponse) GetSuccess() bool {
@@ -819,7 +1117,7 @@ type LeaveRoomRequest struct {
 
 func (x *LeaveRoomRequest) Reset() {
 	*x = LeaveRoomRequest{}
-	mi := &file_proto_chat_proto_msgTypes[9]
+	mi := &file_proto_chat_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -831,7 +1129,7 @@ func (x *LeaveRoomRequest) String() string {
 func (*LeaveRoomRequest) ProtoMessage() {}
 
 func (x *LeaveRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[9]
+	mi := &file_proto_chat_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -844,7 +1142,7 @@ func (x *LeaveRoomRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LeaveRoomRequest.ProtoReflect.Descriptor instead.
 func (*LeaveRoomRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{9}
+	return file_proto_chat_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *LeaveRoomRequest) GetRoomId() string {
@@ -871,7 +1169,7 @@ type LeaveRoomResponse struct {
 
 func (x *LeaveRoomResponse) Reset() {
 	*x = LeaveRoomResponse{}
-	mi := &file_proto_chat_proto_msgTypes[10]
+	mi := &file_proto_chat_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -883,7 +1181,7 @@ func (x *LeaveRoomResponse) String() string {
 func (*LeaveRoomResponse) ProtoMessage() {}
 
 func (x *LeaveRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[10]
+	mi := &file_proto_chat_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -896,7 +1194,7 @@ func (x *LeaveRoomResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LeaveRoomResponse.ProtoReflect.Descriptor instead.
 func (*LeaveRoomResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{10}
+	return file_proto_chat_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *LeaveRoomResponse) GetSuccess() bool {
@@ -913,29 +1211,30 @@ func (x *LeaveRoomResponse) GetMessage() string {
 	return ""
 }
 
-type GetRoomInfoRequest struct {
+type KickMemberRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`       // 操作者，必須是擁有者或管理員
+	MemberId      string                 `protobuf:"bytes,3,opt,name=member_id,json=memberId,proto3" json:"member_id,omitempty"` // 被移出的成員
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRoomInfoRequest) Reset() {
-	*x = GetRoomInfoRequest{}
-	mi := &file_proto_chat_proto_msgTypes[11]
+func (x *KickMemberRequest) Reset() {
+	*x = KickMemberRequest{}
+	mi := &file_proto_chat_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRoomInfoRequest) String() string {
+func (x *KickMemberRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRoomInfoRequest) ProtoMessage() {}
+func (*KickMemberRequest) ProtoMessage() {}
 
-func (x *GetRoomInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[11]
+func (x *KickMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -946,49 +1245,55 @@ func (x *GetRoomInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRoomInfoRequest.ProtoReflect.Descriptor instead.
-func (*GetRoomInfoRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use KickMemberRequest.ProtoReflect.Descriptor instead.
+func (*KickMemberRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *GetRoomInfoRequest) GetRoomId() string {
+func (x *KickMemberRequest) GetRoomId() string {
 	if x != nil {
 		return x.RoomId
 	}
 	return ""
 }
 
-func (x *GetRoomInfoRequest) GetUserId() string {
+func (x *KickMemberRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-type GetRoomInfoResponse struct {
+func (x *KickMemberRequest) GetMemberId() string {
+	if x != nil {
+		return x.MemberId
+	}
+	return ""
+}
+
+type KickMemberResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Room          *ChatRoom              `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRoomInfoResponse) Reset() {
-	*x = GetRoomInfoResponse{}
-	mi := &file_proto_chat_proto_msgTypes[12]
+func (x *KickMemberResponse) Reset() {
+	*x = KickMemberResponse{}
+	mi := &file_proto_chat_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRoomInfoResponse) String() string {
+func (x *KickMemberResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRoomInfoResponse) ProtoMessage() {}
+func (*KickMemberResponse) ProtoMessage() {}
 
-func (x *GetRoomInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[12]
+func (x *KickMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -999,56 +1304,50 @@ func (x *GetRoomInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRoomInfoResponse.ProtoReflect.Descriptor instead.
-func (*GetRoomInfoResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use KickMemberResponse.ProtoReflect.Descriptor instead.
+func (*KickMemberResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GetRoomInfoResponse) GetSuccess() bool {
+func (x *KickMemberResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetRoomInfoResponse) GetMessage() string {
+func (x *KickMemberResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *GetRoomInfoResponse) GetRoom() *ChatRoom {
-	if x != nil {
-		return x.Room
-	}
-	return nil
-}
-
-type ListUserRoomsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // 改用 cursor 分頁，而非 offset
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type TransferOwnershipRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RoomId          string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                              // 操作者，必須是當前擁有者
+	NewOwnerId      string                 `protobuf:"bytes,3,opt,name=new_owner_id,json=newOwnerId,proto3" json:"new_owner_id,omitempty"`                // 新擁有者，必須是聊天室成員
+	FormerOwnerRole string                 `protobuf:"bytes,4,opt,name=former_owner_role,json=formerOwnerRole,proto3" json:"former_owner_role,omitempty"` // 原擁有者的新角色：admin（默認）或 member
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *ListUserRoomsRequest) Reset() {
-	*x = ListUserRoomsRequest{}
-	mi := &file_proto_chat_proto_msgTypes[13]
+func (x *TransferOwnershipRequest) Reset() {
+	*x = TransferOwnershipRequest{}
+	mi := &file_proto_chat_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserRoomsRequest) String() string {
+func (x *TransferOwnershipRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserRoomsRequest) ProtoMessage() {}
+func (*TransferOwnershipRequest) ProtoMessage() {}
 
-func (x *ListUserRoomsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[13]
+func (x *TransferOwnershipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1059,58 +1358,62 @@ func (x *ListUserRoomsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserRoomsRequest.ProtoReflect.Descriptor instead.
-func (*ListUserRoomsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use TransferOwnershipRequest.ProtoReflect.Descriptor instead.
+func (*TransferOwnershipRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *ListUserRoomsRequest) GetUserId() string {
+func (x *TransferOwnershipRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *TransferOwnershipRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *ListUserRoomsRequest) GetLimit() int32 {
+func (x *TransferOwnershipRequest) GetNewOwnerId() string {
 	if x != nil {
-		return x.Limit
+		return x.NewOwnerId
 	}
-	return 0
+	return ""
 }
 
-func (x *ListUserRoomsRequest) GetCursor() string {
+func (x *TransferOwnershipRequest) GetFormerOwnerRole() string {
 	if x != nil {
-		return x.Cursor
+		return x.FormerOwnerRole
 	}
 	return ""
 }
 
-type ListUserRoomsResponse struct {
+type TransferOwnershipResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Rooms         []*ChatRoom            `protobuf:"bytes,3,rep,name=rooms,proto3" json:"rooms,omitempty"`
-	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`                   // 下一頁的 cursor
-	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"` // 是否還有更多數據
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListUserRoomsResponse) Reset() {
-	*x = ListUserRoomsResponse{}
-	mi := &file_proto_chat_proto_msgTypes[14]
+func (x *TransferOwnershipResponse) Reset() {
+	*x = TransferOwnershipResponse{}
+	mi := &file_proto_chat_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListUserRoomsResponse) String() string {
+func (x *TransferOwnershipResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListUserRoomsResponse) ProtoMessage() {}
+func (*TransferOwnershipResponse) ProtoMessage() {}
 
-func (x *ListUserRoomsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[14]
+func (x *TransferOwnershipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1121,72 +1424,48 @@ func (x *ListUserRoomsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListUserRoomsResponse.ProtoReflect.Descriptor instead.
-func (*ListUserRoomsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use TransferOwnershipResponse.ProtoReflect.Descriptor instead.
+func (*TransferOwnershipResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ListUserRoomsResponse) GetSuccess() bool {
+func (x *TransferOwnershipResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *ListUserRoomsResponse) GetMessage() string {
+func (x *TransferOwnershipResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *ListUserRoomsResponse) GetRooms() []*ChatRoom {
-	if x != nil {
-		return x.Rooms
-	}
-	return nil
-}
-
-func (x *ListUserRoomsResponse) GetCursor() string {
-	if x != nil {
-		return x.Cursor
-	}
-	return ""
-}
-
-func (x *ListUserRoomsResponse) GetHasMore() bool {
-	if x != nil {
-		return x.HasMore
-	}
-	return false
-}
-
-type SendMessageRequest struct {
+type DeleteRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	SenderId      string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
-	Metadata      *MessageMetadata       `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 操作者，必須是聊天室擁有者
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendMessageRequest) Reset() {
-	*x = SendMessageRequest{}
-	mi := &file_proto_chat_proto_msgTypes[15]
+func (x *DeleteRoomRequest) Reset() {
+	*x = DeleteRoomRequest{}
+	mi := &file_proto_chat_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendMessageRequest) String() string {
+func (x *DeleteRoomRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendMessageRequest) ProtoMessage() {}
+func (*DeleteRoomRequest) ProtoMessage() {}
 
-func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[15]
+func (x *DeleteRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1197,70 +1476,50 @@ func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
-func (*SendMessageRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use DeleteRoomRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRoomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *SendMessageRequest) GetRoomId() string {
+func (x *DeleteRoomRequest) GetRoomId() string {
 	if x != nil {
 		return x.RoomId
 	}
 	return ""
 }
 
-func (x *SendMessageRequest) GetSenderId() string {
-	if x != nil {
-		return x.SenderId
-	}
-	return ""
-}
-
-func (x *SendMessageRequest) GetContent() string {
-	if x != nil {
-		return x.Content
-	}
-	return ""
-}
-
-func (x *SendMessageRequest) GetType() string {
+func (x *DeleteRoomRequest) GetUserId() string {
 	if x != nil {
-		return x.Type
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *SendMessageRequest) GetMetadata() *MessageMetadata {
-	if x != nil {
-		return x.Metadata
-	}
-	return nil
-}
-
-type SendMessageResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	ChatMessage   *ChatMessage           `protobuf:"bytes,3,opt,name=chat_message,json=chatMessage,proto3" json:"chat_message,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type DeleteRoomResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	DeletedMessages int64                  `protobuf:"varint,3,opt,name=deleted_messages,json=deletedMessages,proto3" json:"deleted_messages,omitempty"` // 刪除的訊息數量
+	DeletedKeys     int64                  `protobuf:"varint,4,opt,name=deleted_keys,json=deletedKeys,proto3" json:"deleted_keys,omitempty"`             // 刪除的加密密鑰數量
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *SendMessageResponse) Reset() {
-	*x = SendMessageResponse{}
-	mi := &file_proto_chat_proto_msgTypes[16]
+func (x *DeleteRoomResponse) Reset() {
+	*x = DeleteRoomResponse{}
+	mi := &file_proto_chat_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendMessageResponse) String() string {
+func (x *DeleteRoomResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendMessageResponse) ProtoMessage() {}
+func (*DeleteRoomResponse) ProtoMessage() {}
 
-func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[16]
+func (x *DeleteRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1271,59 +1530,66 @@ func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
-func (*SendMessageResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use DeleteRoomResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *SendMessageResponse) GetSuccess() bool {
+func (x *DeleteRoomResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *SendMessageResponse) GetMessage() string {
+func (x *DeleteRoomResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *SendMessageResponse) GetChatMessage() *ChatMessage {
+func (x *DeleteRoomResponse) GetDeletedMessages() int64 {
 	if x != nil {
-		return x.ChatMessage
+		return x.DeletedMessages
 	}
-	return nil
+	return 0
 }
 
-type GetMessagesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
-	Since         int64                  `protobuf:"varint,5,opt,name=since,proto3" json:"since,omitempty"`
-	Until         int64                  `protobuf:"varint,6,opt,name=until,proto3" json:"until,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DeleteRoomResponse) GetDeletedKeys() int64 {
+	if x != nil {
+		return x.DeletedKeys
+	}
+	return 0
 }
 
-func (x *GetMessagesRequest) Reset() {
-	*x = GetMessagesRequest{}
-	mi := &file_proto_chat_proto_msgTypes[17]
+type UpdateRoomRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RoomId          string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                   // 操作者，必須是擁有者或管理員
+	Name            *string                `protobuf:"bytes,3,opt,name=name,proto3,oneof" json:"name,omitempty"`                                               // 未提供時不修改
+	AvatarUrl       *string                `protobuf:"bytes,4,opt,name=avatar_url,json=avatarUrl,proto3,oneof" json:"avatar_url,omitempty"`                    // 未提供時不修改
+	Settings        *RoomSettingsUpdate    `protobuf:"bytes,5,opt,name=settings,proto3" json:"settings,omitempty"`                                             // 只更新提供的設置項
+	ExpectedVersion *int64                 `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"` // 聊天室版本不一致時拒絕更新；未提供時使用讀取到的版本
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateRoomRequest) Reset() {
+	*x = UpdateRoomRequest{}
+	mi := &file_proto_chat_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMessagesRequest) String() string {
+func (x *UpdateRoomRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMessagesRequest) ProtoMessage() {}
+func (*UpdateRoomRequest) ProtoMessage() {}
 
-func (x *GetMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[17]
+func (x *UpdateRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1334,79 +1600,82 @@ func (x *GetMessagesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMessagesRequest.ProtoReflect.Descriptor instead.
-func (*GetMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use UpdateRoomRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRoomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *GetMessagesRequest) GetRoomId() string {
+func (x *UpdateRoomRequest) GetRoomId() string {
 	if x != nil {
 		return x.RoomId
 	}
 	return ""
 }
 
-func (x *GetMessagesRequest) GetUserId() string {
+func (x *UpdateRoomRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *GetMessagesRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
+func (x *UpdateRoomRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *GetMessagesRequest) GetCursor() string {
-	if x != nil {
-		return x.Cursor
+func (x *UpdateRoomRequest) GetAvatarUrl() string {
+	if x != nil && x.AvatarUrl != nil {
+		return *x.AvatarUrl
 	}
 	return ""
 }
 
-func (x *GetMessagesRequest) GetSince() int64 {
+func (x *UpdateRoomRequest) GetSettings() *RoomSettingsUpdate {
 	if x != nil {
-		return x.Since
+		return x.Settings
 	}
-	return 0
+	return nil
 }
 
-func (x *GetMessagesRequest) GetUntil() int64 {
-	if x != nil {
-		return x.Until
+func (x *UpdateRoomRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
 	}
 	return 0
 }
 
-type GetMessagesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Messages      []*ChatMessage         `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
-	NextCursor    string                 `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
-	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// 聊天室設置更新（未提供的字段保持不變）
+type RoomSettingsUpdate struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	AllowInvite         *bool                  `protobuf:"varint,1,opt,name=allow_invite,json=allowInvite,proto3,oneof" json:"allow_invite,omitempty"`
+	AllowEditMessages   *bool                  `protobuf:"varint,2,opt,name=allow_edit_messages,json=allowEditMessages,proto3,oneof" json:"allow_edit_messages,omitempty"`
+	AllowDeleteMessages *bool                  `protobuf:"varint,3,opt,name=allow_delete_messages,json=allowDeleteMessages,proto3,oneof" json:"allow_delete_messages,omitempty"`
+	AllowPinMessages    *bool                  `protobuf:"varint,4,opt,name=allow_pin_messages,json=allowPinMessages,proto3,oneof" json:"allow_pin_messages,omitempty"`
+	MaxMembers          *int32                 `protobuf:"varint,5,opt,name=max_members,json=maxMembers,proto3,oneof" json:"max_members,omitempty"`
+	WelcomeMessage      *string                `protobuf:"bytes,6,opt,name=welcome_message,json=welcomeMessage,proto3,oneof" json:"welcome_message,omitempty"`
+	Locale              *string                `protobuf:"bytes,7,opt,name=locale,proto3,oneof" json:"locale,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
-func (x *GetMessagesResponse) Reset() {
-	*x = GetMessagesResponse{}
-	mi := &file_proto_chat_proto_msgTypes[18]
+func (x *RoomSettingsUpdate) Reset() {
+	*x = RoomSettingsUpdate{}
+	mi := &file_proto_chat_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMessagesResponse) String() string {
+func (x *RoomSettingsUpdate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMessagesResponse) ProtoMessage() {}
+func (*RoomSettingsUpdate) ProtoMessage() {}
 
-func (x *GetMessagesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[18]
+func (x *RoomSettingsUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1417,69 +1686,211 @@ func (x *GetMessagesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMessagesResponse.ProtoReflect.Descriptor instead.
-func (*GetMessagesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use RoomSettingsUpdate.ProtoReflect.Descriptor instead.
+func (*RoomSettingsUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *GetMessagesResponse) GetSuccess() bool {
+func (x *RoomSettingsUpdate) GetAllowInvite() bool {
+	if x != nil && x.AllowInvite != nil {
+		return *x.AllowInvite
+	}
+	return false
+}
+
+func (x *RoomSettingsUpdate) GetAllowEditMessages() bool {
+	if x != nil && x.AllowEditMessages != nil {
+		return *x.AllowEditMessages
+	}
+	return false
+}
+
+func (x *RoomSettingsUpdate) GetAllowDeleteMessages() bool {
+	if x != nil && x.AllowDeleteMessages != nil {
+		return *x.AllowDeleteMessages
+	}
+	return false
+}
+
+func (x *RoomSettingsUpdate) GetAllowPinMessages() bool {
+	if x != nil && x.AllowPinMessages != nil {
+		return *x.AllowPinMessages
+	}
+	return false
+}
+
+func (x *RoomSettingsUpdate) GetMaxMembers() int32 {
+	if x != nil && x.MaxMembers != nil {
+		return *x.MaxMembers
+	}
+	return 0
+}
+
+func (x *RoomSettingsUpdate) GetWelcomeMessage() string {
+	if x != nil && x.WelcomeMessage != nil {
+		return *x.WelcomeMessage
+	}
+	return ""
+}
+
+func (x *RoomSettingsUpdate) GetLocale() string {
+	if x != nil && x.Locale != nil {
+		return *x.Locale
+	}
+	return ""
+}
+
+type UpdateRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Room          *ChatRoom              `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRoomResponse) Reset() {
+	*x = UpdateRoomResponse{}
+	mi := &file_proto_chat_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRoomResponse) ProtoMessage() {}
+
+func (x *UpdateRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRoomResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateRoomResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetMessagesResponse) GetMessage() string {
+func (x *UpdateRoomResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *GetMessagesResponse) GetMessages() []*ChatMessage {
+func (x *UpdateRoomResponse) GetRoom() *ChatRoom {
 	if x != nil {
-		return x.Messages
+		return x.Room
 	}
 	return nil
 }
 
-func (x *GetMessagesResponse) GetNextCursor() string {
+type SetRoomModeRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RoomId          string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                             // 操作者，必須是擁有者或管理員
+	Mode            string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`                                               // normal, slowmode, announcement, read_only
+	SlowmodeSeconds int32                  `protobuf:"varint,4,opt,name=slowmode_seconds,json=slowmodeSeconds,proto3" json:"slowmode_seconds,omitempty"` // 僅 slowmode 使用
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetRoomModeRequest) Reset() {
+	*x = SetRoomModeRequest{}
+	mi := &file_proto_chat_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRoomModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRoomModeRequest) ProtoMessage() {}
+
+func (x *SetRoomModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[22]
 	if x != nil {
-		return x.NextCursor
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRoomModeRequest.ProtoReflect.Descriptor instead.
+func (*SetRoomModeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetRoomModeRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
 	}
 	return ""
 }
 
-func (x *GetMessagesResponse) GetHasMore() bool {
+func (x *SetRoomModeRequest) GetUserId() string {
 	if x != nil {
-		return x.HasMore
+		return x.UserId
 	}
-	return false
+	return ""
 }
 
-type StreamMessagesRequest struct {
+func (x *SetRoomModeRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *SetRoomModeRequest) GetSlowmodeSeconds() int32 {
+	if x != nil {
+		return x.SlowmodeSeconds
+	}
+	return 0
+}
+
+type SetRoomModeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *StreamMessagesRequest) Reset() {
-	*x = StreamMessagesRequest{}
-	mi := &file_proto_chat_proto_msgTypes[19]
+func (x *SetRoomModeResponse) Reset() {
+	*x = SetRoomModeResponse{}
+	mi := &file_proto_chat_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *StreamMessagesRequest) String() string {
+func (x *SetRoomModeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*StreamMessagesRequest) ProtoMessage() {}
+func (*SetRoomModeResponse) ProtoMessage() {}
 
-func (x *StreamMessagesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[19]
+func (x *SetRoomModeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1490,49 +1901,4768 @@ func (x *StreamMessagesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use StreamMessagesRequest.ProtoReflect.Descriptor instead.
-func (*StreamMessagesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use SetRoomModeResponse.ProtoReflect.Descriptor instead.
+func (*SetRoomModeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *StreamMessagesRequest) GetRoomId() string {
+func (x *SetRoomModeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SetRoomModeResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type MuteRoomRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RoomId          string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                             // 靜音的成員（只影響自己）
+	DurationSeconds int64                  `protobuf:"varint,3,opt,name=duration_seconds,json=durationSeconds,proto3" json:"duration_seconds,omitempty"` // 靜音時長，0 表示直到手動取消
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MuteRoomRequest) Reset() {
+	*x = MuteRoomRequest{}
+	mi := &file_proto_chat_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MuteRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MuteRoomRequest) ProtoMessage() {}
+
+func (x *MuteRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MuteRoomRequest.ProtoReflect.Descriptor instead.
+func (*MuteRoomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *MuteRoomRequest) GetRoomId() string {
 	if x != nil {
 		return x.RoomId
 	}
 	return ""
 }
 
-func (x *StreamMessagesRequest) GetUserId() string {
+func (x *MuteRoomRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-type MarkAsReadRequest struct {
+func (x *MuteRoomRequest) GetDurationSeconds() int64 {
+	if x != nil {
+		return x.DurationSeconds
+	}
+	return 0
+}
+
+type MuteRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	MutedUntil    int64                  `protobuf:"varint,3,opt,name=muted_until,json=mutedUntil,proto3" json:"muted_until,omitempty"` // 限時靜音的結束時間（永久靜音時為 0）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MuteRoomResponse) Reset() {
+	*x = MuteRoomResponse{}
+	mi := &file_proto_chat_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MuteRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MuteRoomResponse) ProtoMessage() {}
+
+func (x *MuteRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MuteRoomResponse.ProtoReflect.Descriptor instead.
+func (*MuteRoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *MuteRoomResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MuteRoomResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MuteRoomResponse) GetMutedUntil() int64 {
+	if x != nil {
+		return x.MutedUntil
+	}
+	return 0
+}
+
+type UnmuteRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MarkAsReadRequest) Reset() {
-	*x = MarkAsReadRequest{}
-	mi := &file_proto_chat_proto_msgTypes[20]
+func (x *UnmuteRoomRequest) Reset() {
+	*x = UnmuteRoomRequest{}
+	mi := &file_proto_chat_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnmuteRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnmuteRoomRequest) ProtoMessage() {}
+
+func (x *UnmuteRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnmuteRoomRequest.ProtoReflect.Descriptor instead.
+func (*UnmuteRoomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UnmuteRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *UnmuteRoomRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type UnmuteRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnmuteRoomResponse) Reset() {
+	*x = UnmuteRoomResponse{}
+	mi := &file_proto_chat_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnmuteRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnmuteRoomResponse) ProtoMessage() {}
+
+func (x *UnmuteRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnmuteRoomResponse.ProtoReflect.Descriptor instead.
+func (*UnmuteRoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UnmuteRoomResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UnmuteRoomResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ArchiveRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 封存的成員（只影響自己）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveRoomRequest) Reset() {
+	*x = ArchiveRoomRequest{}
+	mi := &file_proto_chat_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveRoomRequest) ProtoMessage() {}
+
+func (x *ArchiveRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveRoomRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveRoomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ArchiveRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ArchiveRoomRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ArchiveRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveRoomResponse) Reset() {
+	*x = ArchiveRoomResponse{}
+	mi := &file_proto_chat_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveRoomResponse) ProtoMessage() {}
+
+func (x *ArchiveRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveRoomResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveRoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ArchiveRoomResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ArchiveRoomResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type UnarchiveRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnarchiveRoomRequest) Reset() {
+	*x = UnarchiveRoomRequest{}
+	mi := &file_proto_chat_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnarchiveRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnarchiveRoomRequest) ProtoMessage() {}
+
+func (x *UnarchiveRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnarchiveRoomRequest.ProtoReflect.Descriptor instead.
+func (*UnarchiveRoomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UnarchiveRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *UnarchiveRoomRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type UnarchiveRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnarchiveRoomResponse) Reset() {
+	*x = UnarchiveRoomResponse{}
+	mi := &file_proto_chat_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnarchiveRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnarchiveRoomResponse) ProtoMessage() {}
+
+func (x *UnarchiveRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnarchiveRoomResponse.ProtoReflect.Descriptor instead.
+func (*UnarchiveRoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *UnarchiveRoomResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UnarchiveRoomResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type PinRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 置頂的成員（只影響自己，已封存的聊天室會同時取消封存）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinRoomRequest) Reset() {
+	*x = PinRoomRequest{}
+	mi := &file_proto_chat_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinRoomRequest) ProtoMessage() {}
+
+func (x *PinRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinRoomRequest.ProtoReflect.Descriptor instead.
+func (*PinRoomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *PinRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *PinRoomRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type PinRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinRoomResponse) Reset() {
+	*x = PinRoomResponse{}
+	mi := &file_proto_chat_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinRoomResponse) ProtoMessage() {}
+
+func (x *PinRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinRoomResponse.ProtoReflect.Descriptor instead.
+func (*PinRoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *PinRoomResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PinRoomResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type UnpinRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnpinRoomRequest) Reset() {
+	*x = UnpinRoomRequest{}
+	mi := &file_proto_chat_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnpinRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnpinRoomRequest) ProtoMessage() {}
+
+func (x *UnpinRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnpinRoomRequest.ProtoReflect.Descriptor instead.
+func (*UnpinRoomRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *UnpinRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *UnpinRoomRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type UnpinRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnpinRoomResponse) Reset() {
+	*x = UnpinRoomResponse{}
+	mi := &file_proto_chat_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnpinRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnpinRoomResponse) ProtoMessage() {}
+
+func (x *UnpinRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnpinRoomResponse.ProtoReflect.Descriptor instead.
+func (*UnpinRoomResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UnpinRoomResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UnpinRoomResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RotateRoomKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 已棄用：操作者為 JWT 認證的用戶（必須在 admin_user_ids 中）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateRoomKeyRequest) Reset() {
+	*x = RotateRoomKeyRequest{}
+	mi := &file_proto_chat_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateRoomKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateRoomKeyRequest) ProtoMessage() {}
+
+func (x *RotateRoomKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateRoomKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateRoomKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *RotateRoomKeyRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RotateRoomKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RotateRoomKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	KeyVersion    int32                  `protobuf:"varint,3,opt,name=key_version,json=keyVersion,proto3" json:"key_version,omitempty"` // 新密鑰版本
+	RotatedAt     int64                  `protobuf:"varint,4,opt,name=rotated_at,json=rotatedAt,proto3" json:"rotated_at,omitempty"`    // 輪換時間（Unix 秒）
+	Created       bool                   `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"`                         // 聊天室原本沒有密鑰，本次新建
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateRoomKeyResponse) Reset() {
+	*x = RotateRoomKeyResponse{}
+	mi := &file_proto_chat_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateRoomKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateRoomKeyResponse) ProtoMessage() {}
+
+func (x *RotateRoomKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateRoomKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateRoomKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RotateRoomKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RotateRoomKeyResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RotateRoomKeyResponse) GetKeyVersion() int32 {
+	if x != nil {
+		return x.KeyVersion
+	}
+	return 0
+}
+
+func (x *RotateRoomKeyResponse) GetRotatedAt() int64 {
+	if x != nil {
+		return x.RotatedAt
+	}
+	return 0
+}
+
+func (x *RotateRoomKeyResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type GetKeyStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeyStatsRequest) Reset() {
+	*x = GetKeyStatsRequest{}
+	mi := &file_proto_chat_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeyStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyStatsRequest) ProtoMessage() {}
+
+func (x *GetKeyStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetKeyStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{38}
+}
+
+type GetKeyStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TotalKeys     int32                  `protobuf:"varint,3,opt,name=total_keys,json=totalKeys,proto3" json:"total_keys,omitempty"`
+	ActiveKeys    int32                  `protobuf:"varint,4,opt,name=active_keys,json=activeKeys,proto3" json:"active_keys,omitempty"`
+	ArchivedKeys  int32                  `protobuf:"varint,5,opt,name=archived_keys,json=archivedKeys,proto3" json:"archived_keys,omitempty"`
+	RevokedKeys   int32                  `protobuf:"varint,6,opt,name=revoked_keys,json=revokedKeys,proto3" json:"revoked_keys,omitempty"`
+	CacheHits     int64                  `protobuf:"varint,7,opt,name=cache_hits,json=cacheHits,proto3" json:"cache_hits,omitempty"`       // 獲取當前密鑰時的緩存命中次數
+	CacheMisses   int64                  `protobuf:"varint,8,opt,name=cache_misses,json=cacheMisses,proto3" json:"cache_misses,omitempty"` // 緩存未命中次數（需要查詢數據庫）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKeyStatsResponse) Reset() {
+	*x = GetKeyStatsResponse{}
+	mi := &file_proto_chat_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKeyStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKeyStatsResponse) ProtoMessage() {}
+
+func (x *GetKeyStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKeyStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetKeyStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetKeyStatsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetKeyStatsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetKeyStatsResponse) GetTotalKeys() int32 {
+	if x != nil {
+		return x.TotalKeys
+	}
+	return 0
+}
+
+func (x *GetKeyStatsResponse) GetActiveKeys() int32 {
+	if x != nil {
+		return x.ActiveKeys
+	}
+	return 0
+}
+
+func (x *GetKeyStatsResponse) GetArchivedKeys() int32 {
+	if x != nil {
+		return x.ArchivedKeys
+	}
+	return 0
+}
+
+func (x *GetKeyStatsResponse) GetRevokedKeys() int32 {
+	if x != nil {
+		return x.RevokedKeys
+	}
+	return 0
+}
+
+func (x *GetKeyStatsResponse) GetCacheHits() int64 {
+	if x != nil {
+		return x.CacheHits
+	}
+	return 0
+}
+
+func (x *GetKeyStatsResponse) GetCacheMisses() int64 {
+	if x != nil {
+		return x.CacheMisses
+	}
+	return 0
+}
+
+type GetRoomKeyInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomKeyInfoRequest) Reset() {
+	*x = GetRoomKeyInfoRequest{}
+	mi := &file_proto_chat_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomKeyInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomKeyInfoRequest) ProtoMessage() {}
+
+func (x *GetRoomKeyInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomKeyInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomKeyInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetRoomKeyInfoRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type GetRoomKeyInfoResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message          string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	RoomId           string                 `protobuf:"bytes,3,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	KeyVersion       int32                  `protobuf:"varint,4,opt,name=key_version,json=keyVersion,proto3" json:"key_version,omitempty"` // 當前密鑰版本
+	Status           string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                            // active, archived, revoked
+	CreatedAt        int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	RotatedAt        int64                  `protobuf:"varint,7,opt,name=rotated_at,json=rotatedAt,proto3" json:"rotated_at,omitempty"`
+	AgeSeconds       int64                  `protobuf:"varint,8,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	Cached           bool                   `protobuf:"varint,9,opt,name=cached,proto3" json:"cached,omitempty"`                                                     // 是否已加載到內存
+	ArchivedVersions []int32                `protobuf:"varint,10,rep,packed,name=archived_versions,json=archivedVersions,proto3" json:"archived_versions,omitempty"` // 內存中的歷史版本
+	StoredVersions   []int32                `protobuf:"varint,11,rep,packed,name=stored_versions,json=storedVersions,proto3" json:"stored_versions,omitempty"`       // 數據庫中的所有版本
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetRoomKeyInfoResponse) Reset() {
+	*x = GetRoomKeyInfoResponse{}
+	mi := &file_proto_chat_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomKeyInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomKeyInfoResponse) ProtoMessage() {}
+
+func (x *GetRoomKeyInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomKeyInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomKeyInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetRoomKeyInfoResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetRoomKeyInfoResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetRoomKeyInfoResponse) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetRoomKeyInfoResponse) GetKeyVersion() int32 {
+	if x != nil {
+		return x.KeyVersion
+	}
+	return 0
+}
+
+func (x *GetRoomKeyInfoResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetRoomKeyInfoResponse) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *GetRoomKeyInfoResponse) GetRotatedAt() int64 {
+	if x != nil {
+		return x.RotatedAt
+	}
+	return 0
+}
+
+func (x *GetRoomKeyInfoResponse) GetAgeSeconds() int64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+func (x *GetRoomKeyInfoResponse) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *GetRoomKeyInfoResponse) GetArchivedVersions() []int32 {
+	if x != nil {
+		return x.ArchivedVersions
+	}
+	return nil
+}
+
+func (x *GetRoomKeyInfoResponse) GetStoredVersions() []int32 {
+	if x != nil {
+		return x.StoredVersions
+	}
+	return nil
+}
+
+type GetRoomInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomInfoRequest) Reset() {
+	*x = GetRoomInfoRequest{}
+	mi := &file_proto_chat_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomInfoRequest) ProtoMessage() {}
+
+func (x *GetRoomInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetRoomInfoRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetRoomInfoRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetRoomInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Room          *ChatRoom              `protobuf:"bytes,3,opt,name=room,proto3" json:"room,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomInfoResponse) Reset() {
+	*x = GetRoomInfoResponse{}
+	mi := &file_proto_chat_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomInfoResponse) ProtoMessage() {}
+
+func (x *GetRoomInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomInfoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *GetRoomInfoResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetRoomInfoResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetRoomInfoResponse) GetRoom() *ChatRoom {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+type GetOnlineMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 請求者（必須是聊天室成員）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOnlineMembersRequest) Reset() {
+	*x = GetOnlineMembersRequest{}
+	mi := &file_proto_chat_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOnlineMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOnlineMembersRequest) ProtoMessage() {}
+
+func (x *GetOnlineMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOnlineMembersRequest.ProtoReflect.Descriptor instead.
+func (*GetOnlineMembersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetOnlineMembersRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetOnlineMembersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetOnlineMembersResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Success             bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message             string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Members             []*RoomMember          `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`                                                       // last_seen 在在線窗口內的成員
+	OnlineWindowSeconds int32                  `protobuf:"varint,4,opt,name=online_window_seconds,json=onlineWindowSeconds,proto3" json:"online_window_seconds,omitempty"` // 判定在線使用的窗口（秒）
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GetOnlineMembersResponse) Reset() {
+	*x = GetOnlineMembersResponse{}
+	mi := &file_proto_chat_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOnlineMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOnlineMembersResponse) ProtoMessage() {}
+
+func (x *GetOnlineMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOnlineMembersResponse.ProtoReflect.Descriptor instead.
+func (*GetOnlineMembersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetOnlineMembersResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetOnlineMembersResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetOnlineMembersResponse) GetMembers() []*RoomMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *GetOnlineMembersResponse) GetOnlineWindowSeconds() int32 {
+	if x != nil {
+		return x.OnlineWindowSeconds
+	}
+	return 0
+}
+
+type ListRoomMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 請求者（必須是聊天室成員）
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"` // 上一頁返回的 cursor
+	Role          string                 `protobuf:"bytes,5,opt,name=role,proto3" json:"role,omitempty"`     // 只返回指定角色（owner, admin, member），為空時不過濾
+	Search        string                 `protobuf:"bytes,6,opt,name=search,proto3" json:"search,omitempty"` // 用戶名前綴（不區分大小寫）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoomMembersRequest) Reset() {
+	*x = ListRoomMembersRequest{}
+	mi := &file_proto_chat_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoomMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoomMembersRequest) ProtoMessage() {}
+
+func (x *ListRoomMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoomMembersRequest.ProtoReflect.Descriptor instead.
+func (*ListRoomMembersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListRoomMembersRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ListRoomMembersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListRoomMembersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRoomMembersRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListRoomMembersRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ListRoomMembersRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+type ListRoomMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Members       []*RoomMember          `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`                 // 按 user_id 排序
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`                   // 下一頁的 cursor
+	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"` // 是否還有更多數據
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoomMembersResponse) Reset() {
+	*x = ListRoomMembersResponse{}
+	mi := &file_proto_chat_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoomMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoomMembersResponse) ProtoMessage() {}
+
+func (x *ListRoomMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoomMembersResponse.ProtoReflect.Descriptor instead.
+func (*ListRoomMembersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListRoomMembersResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListRoomMembersResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListRoomMembersResponse) GetMembers() []*RoomMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *ListRoomMembersResponse) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListRoomMembersResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type ListUserRoomsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit           int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor          string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`                                           // 改用 cursor 分頁，而非 offset
+	IncludeArchived bool                   `protobuf:"varint,4,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"` // 是否包含已封存的聊天室（默認不包含）
+	Sort            string                 `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`                                               // 排序方式：activity（默認，最近活動優先）/ unread（未讀優先）/ name（按名稱）
+	ExcludeMuted    bool                   `protobuf:"varint,6,opt,name=exclude_muted,json=excludeMuted,proto3" json:"exclude_muted,omitempty"`          // 已靜音的聊天室未讀數量返回 0
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListUserRoomsRequest) Reset() {
+	*x = ListUserRoomsRequest{}
+	mi := &file_proto_chat_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserRoomsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserRoomsRequest) ProtoMessage() {}
+
+func (x *ListUserRoomsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserRoomsRequest.ProtoReflect.Descriptor instead.
+func (*ListUserRoomsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ListUserRoomsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListUserRoomsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListUserRoomsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListUserRoomsRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+func (x *ListUserRoomsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListUserRoomsRequest) GetExcludeMuted() bool {
+	if x != nil {
+		return x.ExcludeMuted
+	}
+	return false
+}
+
+type ListUserRoomsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Rooms         []*ChatRoom            `protobuf:"bytes,3,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`                   // 下一頁的 cursor
+	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"` // 是否還有更多數據
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUserRoomsResponse) Reset() {
+	*x = ListUserRoomsResponse{}
+	mi := &file_proto_chat_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserRoomsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserRoomsResponse) ProtoMessage() {}
+
+func (x *ListUserRoomsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUserRoomsResponse.ProtoReflect.Descriptor instead.
+func (*ListUserRoomsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *ListUserRoomsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListUserRoomsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListUserRoomsResponse) GetRooms() []*ChatRoom {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+func (x *ListUserRoomsResponse) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListUserRoomsResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type ListRoomsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`                                         // 可選，按類型過濾（direct / group）
+	OwnerId       string                 `protobuf:"bytes,2,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`                    // 可選，按擁有者過濾
+	NamePrefix    string                 `protobuf:"bytes,3,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`           // 可選，名稱前綴（不區分大小寫）
+	CreatedAfter  int64                  `protobuf:"varint,4,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`    // 可選，創建時間下限（Unix 秒，包含）
+	CreatedBefore int64                  `protobuf:"varint,5,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"` // 可選，創建時間上限（Unix 秒，不包含）
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,7,opt,name=cursor,proto3" json:"cursor,omitempty"`                                 // 上一頁返回的 cursor（按創建時間倒序）
+	NameContains  string                 `protobuf:"bytes,8,opt,name=name_contains,json=nameContains,proto3" json:"name_contains,omitempty"` // 可選，名稱包含的文字（不區分大小寫，不能與 name_prefix 同時使用）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoomsRequest) Reset() {
+	*x = ListRoomsRequest{}
+	mi := &file_proto_chat_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoomsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoomsRequest) ProtoMessage() {}
+
+func (x *ListRoomsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoomsRequest.ProtoReflect.Descriptor instead.
+func (*ListRoomsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListRoomsRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListRoomsRequest) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *ListRoomsRequest) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *ListRoomsRequest) GetCreatedAfter() int64 {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return 0
+}
+
+func (x *ListRoomsRequest) GetCreatedBefore() int64 {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return 0
+}
+
+func (x *ListRoomsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRoomsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListRoomsRequest) GetNameContains() string {
+	if x != nil {
+		return x.NameContains
+	}
+	return ""
+}
+
+type ListRoomsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Rooms         []*ChatRoom            `protobuf:"bytes,3,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,6,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // 失敗原因：invalid_argument / permission_denied / internal
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRoomsResponse) Reset() {
+	*x = ListRoomsResponse{}
+	mi := &file_proto_chat_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRoomsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRoomsResponse) ProtoMessage() {}
+
+func (x *ListRoomsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRoomsResponse.ProtoReflect.Descriptor instead.
+func (*ListRoomsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ListRoomsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListRoomsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListRoomsResponse) GetRooms() []*ChatRoom {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+func (x *ListRoomsResponse) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *ListRoomsResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *ListRoomsResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+type SendMessageRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RoomId           string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	SenderId         string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	Content          string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Type             string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	Metadata         *MessageMetadata       `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Mentions         []string               `protobuf:"bytes,6,rep,name=mentions,proto3" json:"mentions,omitempty"`                                             // 明確指定的提及用戶（另外也會從內容中的 @user_id 解析）
+	TtlSeconds       int32                  `protobuf:"varint,7,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`                      // 限時訊息：發送後多少秒過期（0 表示不會過期）
+	IdempotencyKey   string                 `protobuf:"bytes,8,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`           // 可選，客戶端生成的冪等鍵；同一發送者重複使用時返回第一次發送的訊息，不會重複創建
+	ReplyToMessageId string                 `protobuf:"bytes,9,opt,name=reply_to_message_id,json=replyToMessageId,proto3" json:"reply_to_message_id,omitempty"` // 可選，回覆的訊息 ID（必須是同一聊天室中未過期的訊息）
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SendMessageRequest) Reset() {
+	*x = SendMessageRequest{}
+	mi := &file_proto_chat_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageRequest) ProtoMessage() {}
+
+func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SendMessageRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetMetadata() *MessageMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *SendMessageRequest) GetMentions() []string {
+	if x != nil {
+		return x.Mentions
+	}
+	return nil
+}
+
+func (x *SendMessageRequest) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *SendMessageRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *SendMessageRequest) GetReplyToMessageId() string {
+	if x != nil {
+		return x.ReplyToMessageId
+	}
+	return ""
+}
+
+type SendMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ChatMessage   *ChatMessage           `protobuf:"bytes,3,opt,name=chat_message,json=chatMessage,proto3" json:"chat_message,omitempty"`
+	Replayed      bool                   `protobuf:"varint,4,opt,name=replayed,proto3" json:"replayed,omitempty"` // 冪等鍵已使用過，返回的是第一次發送的訊息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendMessageResponse) Reset() {
+	*x = SendMessageResponse{}
+	mi := &file_proto_chat_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendMessageResponse) ProtoMessage() {}
+
+func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *SendMessageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SendMessageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SendMessageResponse) GetChatMessage() *ChatMessage {
+	if x != nil {
+		return x.ChatMessage
+	}
+	return nil
+}
+
+func (x *SendMessageResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+type ScheduleMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *SendMessageRequest    `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`              // 與 SendMessage 相同的內容和驗證規則
+	SendAt        int64                  `protobuf:"varint,2,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"` // 發送時間（Unix 秒），必須晚於當前時間
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleMessageRequest) Reset() {
+	*x = ScheduleMessageRequest{}
+	mi := &file_proto_chat_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMessageRequest) ProtoMessage() {}
+
+func (x *ScheduleMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMessageRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ScheduleMessageRequest) GetMessage() *SendMessageRequest {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *ScheduleMessageRequest) GetSendAt() int64 {
+	if x != nil {
+		return x.SendAt
+	}
+	return 0
+}
+
+// 定時訊息
+type ScheduledMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	SenderId      string                 `protobuf:"bytes,3,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	Content       string                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"` // 解密後的內容
+	Type          string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	Metadata      *MessageMetadata       `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Mentions      []string               `protobuf:"bytes,7,rep,name=mentions,proto3" json:"mentions,omitempty"`
+	SendAt        int64                  `protobuf:"varint,8,opt,name=send_at,json=sendAt,proto3" json:"send_at,omitempty"`
+	Status        string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`                         // pending / processing / sent / cancelled / failed
+	MessageId     string                 `protobuf:"bytes,10,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 發送後的訊息 ID
+	CreatedAt     int64                  `protobuf:"varint,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledMessage) Reset() {
+	*x = ScheduledMessage{}
+	mi := &file_proto_chat_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledMessage) ProtoMessage() {}
+
+func (x *ScheduledMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledMessage.ProtoReflect.Descriptor instead.
+func (*ScheduledMessage) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ScheduledMessage) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetMetadata() *MessageMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ScheduledMessage) GetMentions() []string {
+	if x != nil {
+		return x.Mentions
+	}
+	return nil
+}
+
+func (x *ScheduledMessage) GetSendAt() int64 {
+	if x != nil {
+		return x.SendAt
+	}
+	return 0
+}
+
+func (x *ScheduledMessage) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ScheduledMessage) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ScheduleMessageResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message          string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ScheduledMessage *ScheduledMessage      `protobuf:"bytes,3,opt,name=scheduled_message,json=scheduledMessage,proto3" json:"scheduled_message,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ScheduleMessageResponse) Reset() {
+	*x = ScheduleMessageResponse{}
+	mi := &file_proto_chat_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMessageResponse) ProtoMessage() {}
+
+func (x *ScheduleMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMessageResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ScheduleMessageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ScheduleMessageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ScheduleMessageResponse) GetScheduledMessage() *ScheduledMessage {
+	if x != nil {
+		return x.ScheduledMessage
+	}
+	return nil
+}
+
+type ListScheduledMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // 可選，只列出該聊天室的定時訊息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScheduledMessagesRequest) Reset() {
+	*x = ListScheduledMessagesRequest{}
+	mi := &file_proto_chat_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledMessagesRequest) ProtoMessage() {}
+
+func (x *ListScheduledMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledMessagesRequest.ProtoReflect.Descriptor instead.
+func (*ListScheduledMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ListScheduledMessagesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListScheduledMessagesRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type ListScheduledMessagesResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Success           bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message           string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ScheduledMessages []*ScheduledMessage    `protobuf:"bytes,3,rep,name=scheduled_messages,json=scheduledMessages,proto3" json:"scheduled_messages,omitempty"` // 待發送的定時訊息，按發送時間升序
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListScheduledMessagesResponse) Reset() {
+	*x = ListScheduledMessagesResponse{}
+	mi := &file_proto_chat_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScheduledMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScheduledMessagesResponse) ProtoMessage() {}
+
+func (x *ListScheduledMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScheduledMessagesResponse.ProtoReflect.Descriptor instead.
+func (*ListScheduledMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ListScheduledMessagesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ListScheduledMessagesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ListScheduledMessagesResponse) GetScheduledMessages() []*ScheduledMessage {
+	if x != nil {
+		return x.ScheduledMessages
+	}
+	return nil
+}
+
+type CancelScheduledMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 只有發送者可以取消
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledMessageRequest) Reset() {
+	*x = CancelScheduledMessageRequest{}
+	mi := &file_proto_chat_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledMessageRequest) ProtoMessage() {}
+
+func (x *CancelScheduledMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledMessageRequest.ProtoReflect.Descriptor instead.
+func (*CancelScheduledMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *CancelScheduledMessageRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelScheduledMessageRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type CancelScheduledMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledMessageResponse) Reset() {
+	*x = CancelScheduledMessageResponse{}
+	mi := &file_proto_chat_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledMessageResponse) ProtoMessage() {}
+
+func (x *CancelScheduledMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledMessageResponse.ProtoReflect.Descriptor instead.
+func (*CancelScheduledMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *CancelScheduledMessageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CancelScheduledMessageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ForwardMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 轉發者
+	SourceRoomId  string                 `protobuf:"bytes,2,opt,name=source_room_id,json=sourceRoomId,proto3" json:"source_room_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 來源訊息
+	TargetRoomId  string                 `protobuf:"bytes,4,opt,name=target_room_id,json=targetRoomId,proto3" json:"target_room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardMessageRequest) Reset() {
+	*x = ForwardMessageRequest{}
+	mi := &file_proto_chat_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardMessageRequest) ProtoMessage() {}
+
+func (x *ForwardMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardMessageRequest.ProtoReflect.Descriptor instead.
+func (*ForwardMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ForwardMessageRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ForwardMessageRequest) GetSourceRoomId() string {
+	if x != nil {
+		return x.SourceRoomId
+	}
+	return ""
+}
+
+func (x *ForwardMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ForwardMessageRequest) GetTargetRoomId() string {
+	if x != nil {
+		return x.TargetRoomId
+	}
+	return ""
+}
+
+type ForwardMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ChatMessage   *ChatMessage           `protobuf:"bytes,3,opt,name=chat_message,json=chatMessage,proto3" json:"chat_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardMessageResponse) Reset() {
+	*x = ForwardMessageResponse{}
+	mi := &file_proto_chat_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardMessageResponse) ProtoMessage() {}
+
+func (x *ForwardMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardMessageResponse.ProtoReflect.Descriptor instead.
+func (*ForwardMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ForwardMessageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ForwardMessageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ForwardMessageResponse) GetChatMessage() *ChatMessage {
+	if x != nil {
+		return x.ChatMessage
+	}
+	return nil
+}
+
+type GetMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Since         int64                  `protobuf:"varint,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until         int64                  `protobuf:"varint,6,opt,name=until,proto3" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMessagesRequest) Reset() {
+	*x = GetMessagesRequest{}
+	mi := &file_proto_chat_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessagesRequest) ProtoMessage() {}
+
+func (x *GetMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessagesRequest.ProtoReflect.Descriptor instead.
+func (*GetMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *GetMessagesRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetMessagesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetMessagesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetMessagesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetMessagesRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *GetMessagesRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+type GetMessagesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Messages      []*ChatMessage         `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,4,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMessagesResponse) Reset() {
+	*x = GetMessagesResponse{}
+	mi := &file_proto_chat_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessagesResponse) ProtoMessage() {}
+
+func (x *GetMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessagesResponse.ProtoReflect.Descriptor instead.
+func (*GetMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *GetMessagesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetMessagesResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetMessagesResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetMessagesResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *GetMessagesResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+type GetMessagesPagedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // 每頁數量（默認 20，最多 1000）
+	Skip          int32                  `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`   // 跳過的訊息數量（最多 100000），按創建時間倒序
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMessagesPagedRequest) Reset() {
+	*x = GetMessagesPagedRequest{}
+	mi := &file_proto_chat_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessagesPagedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessagesPagedRequest) ProtoMessage() {}
+
+func (x *GetMessagesPagedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessagesPagedRequest.ProtoReflect.Descriptor instead.
+func (*GetMessagesPagedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *GetMessagesPagedRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetMessagesPagedRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetMessagesPagedRequest) GetSkip() int32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+type GetMessagesPagedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Messages      []*ChatMessage         `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	TotalCount    int64                  `protobuf:"varint,4,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"` // 聊天室中未過期的訊息總數
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                             // 實際使用的每頁數量（超出上限時被截斷）
+	Skip          int32                  `protobuf:"varint,6,opt,name=skip,proto3" json:"skip,omitempty"`                               // 實際使用的跳過數量
+	ErrorCode     string                 `protobuf:"bytes,7,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`     // 失敗原因：invalid_argument / permission_denied / internal
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMessagesPagedResponse) Reset() {
+	*x = GetMessagesPagedResponse{}
+	mi := &file_proto_chat_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessagesPagedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessagesPagedResponse) ProtoMessage() {}
+
+func (x *GetMessagesPagedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessagesPagedResponse.ProtoReflect.Descriptor instead.
+func (*GetMessagesPagedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *GetMessagesPagedResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetMessagesPagedResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetMessagesPagedResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetMessagesPagedResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GetMessagesPagedResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetMessagesPagedResponse) GetSkip() int32 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetMessagesPagedResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+type GetMessagesAroundRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RoomId          string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	AnchorMessageId string                 `protobuf:"bytes,3,opt,name=anchor_message_id,json=anchorMessageId,proto3" json:"anchor_message_id,omitempty"` // 錨點訊息（包含在結果中），與 cursor 二選一
+	Cursor          string                 `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`                                            // 翻頁游標（不包含游標指向的訊息）
+	BeforeLimit     int32                  `protobuf:"varint,5,opt,name=before_limit,json=beforeLimit,proto3" json:"before_limit,omitempty"`              // 錨點之前（較舊）的訊息數量
+	AfterLimit      int32                  `protobuf:"varint,6,opt,name=after_limit,json=afterLimit,proto3" json:"after_limit,omitempty"`                 // 錨點之後（較新）的訊息數量
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetMessagesAroundRequest) Reset() {
+	*x = GetMessagesAroundRequest{}
+	mi := &file_proto_chat_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessagesAroundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessagesAroundRequest) ProtoMessage() {}
+
+func (x *GetMessagesAroundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessagesAroundRequest.ProtoReflect.Descriptor instead.
+func (*GetMessagesAroundRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *GetMessagesAroundRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetMessagesAroundRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetMessagesAroundRequest) GetAnchorMessageId() string {
+	if x != nil {
+		return x.AnchorMessageId
+	}
+	return ""
+}
+
+func (x *GetMessagesAroundRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetMessagesAroundRequest) GetBeforeLimit() int32 {
+	if x != nil {
+		return x.BeforeLimit
+	}
+	return 0
+}
+
+func (x *GetMessagesAroundRequest) GetAfterLimit() int32 {
+	if x != nil {
+		return x.AfterLimit
+	}
+	return 0
+}
+
+type GetMessagesAroundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Messages      []*ChatMessage         `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`                             // 按時間升序排列
+	BeforeCursor  string                 `protobuf:"bytes,4,opt,name=before_cursor,json=beforeCursor,proto3" json:"before_cursor,omitempty"` // 繼續載入較舊訊息的游標
+	HasBefore     bool                   `protobuf:"varint,5,opt,name=has_before,json=hasBefore,proto3" json:"has_before,omitempty"`
+	AfterCursor   string                 `protobuf:"bytes,6,opt,name=after_cursor,json=afterCursor,proto3" json:"after_cursor,omitempty"` // 繼續載入較新訊息的游標
+	HasAfter      bool                   `protobuf:"varint,7,opt,name=has_after,json=hasAfter,proto3" json:"has_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMessagesAroundResponse) Reset() {
+	*x = GetMessagesAroundResponse{}
+	mi := &file_proto_chat_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessagesAroundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessagesAroundResponse) ProtoMessage() {}
+
+func (x *GetMessagesAroundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessagesAroundResponse.ProtoReflect.Descriptor instead.
+func (*GetMessagesAroundResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *GetMessagesAroundResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetMessagesAroundResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetMessagesAroundResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetMessagesAroundResponse) GetBeforeCursor() string {
+	if x != nil {
+		return x.BeforeCursor
+	}
+	return ""
+}
+
+func (x *GetMessagesAroundResponse) GetHasBefore() bool {
+	if x != nil {
+		return x.HasBefore
+	}
+	return false
+}
+
+func (x *GetMessagesAroundResponse) GetAfterCursor() string {
+	if x != nil {
+		return x.AfterCursor
+	}
+	return ""
+}
+
+func (x *GetMessagesAroundResponse) GetHasAfter() bool {
+	if x != nil {
+		return x.HasAfter
+	}
+	return false
+}
+
+type GetMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 請求者，必須是訊息所在聊天室的成員
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMessageRequest) Reset() {
+	*x = GetMessageRequest{}
+	mi := &file_proto_chat_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessageRequest) ProtoMessage() {}
+
+func (x *GetMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessageRequest.ProtoReflect.Descriptor instead.
+func (*GetMessageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *GetMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *GetMessageRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ChatMessage   *ChatMessage           `protobuf:"bytes,3,opt,name=chat_message,json=chatMessage,proto3" json:"chat_message,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // 失敗原因：invalid_argument / not_found / permission_denied / internal
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMessageResponse) Reset() {
+	*x = GetMessageResponse{}
+	mi := &file_proto_chat_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMessageResponse) ProtoMessage() {}
+
+func (x *GetMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMessageResponse.ProtoReflect.Descriptor instead.
+func (*GetMessageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *GetMessageResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetMessageResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetMessageResponse) GetChatMessage() *ChatMessage {
+	if x != nil {
+		return x.ChatMessage
+	}
+	return nil
+}
+
+func (x *GetMessageResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+type GetThreadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                        // 請求者，必須是聊天室成員
+	RootMessageId string                 `protobuf:"bytes,3,opt,name=root_message_id,json=rootMessageId,proto3" json:"root_message_id,omitempty"` // 討論串的根訊息
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"` // 上一頁返回的 next_cursor，為空時從最早的回覆開始
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetThreadRequest) Reset() {
+	*x = GetThreadRequest{}
+	mi := &file_proto_chat_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetThreadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetThreadRequest) ProtoMessage() {}
+
+func (x *GetThreadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetThreadRequest.ProtoReflect.Descriptor instead.
+func (*GetThreadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetThreadRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetThreadRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetThreadRequest) GetRootMessageId() string {
+	if x != nil {
+		return x.RootMessageId
+	}
+	return ""
+}
+
+func (x *GetThreadRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetThreadRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetThreadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Root          *ChatMessage           `protobuf:"bytes,3,opt,name=root,proto3" json:"root,omitempty"`         // 根訊息（只在第一頁返回）
+	Messages      []*ChatMessage         `protobuf:"bytes,4,rep,name=messages,proto3" json:"messages,omitempty"` // 回覆（按創建時間正序）
+	NextCursor    string                 `protobuf:"bytes,5,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	HasMore       bool                   `protobuf:"varint,6,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,7,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // 失敗原因：invalid_argument / not_found / permission_denied / internal
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetThreadResponse) Reset() {
+	*x = GetThreadResponse{}
+	mi := &file_proto_chat_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetThreadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetThreadResponse) ProtoMessage() {}
+
+func (x *GetThreadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetThreadResponse.ProtoReflect.Descriptor instead.
+func (*GetThreadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetThreadResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetThreadResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetThreadResponse) GetRoot() *ChatMessage {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *GetThreadResponse) GetMessages() []*ChatMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetThreadResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *GetThreadResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *GetThreadResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+type StreamMessagesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SinceCursor   string                 `protobuf:"bytes,3,opt,name=since_cursor,json=sinceCursor,proto3" json:"since_cursor,omitempty"` // 斷線重連時傳入最後收到的訊息游標，先補發之後的訊息再推送新訊息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamMessagesRequest) Reset() {
+	*x = StreamMessagesRequest{}
+	mi := &file_proto_chat_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamMessagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamMessagesRequest) ProtoMessage() {}
+
+func (x *StreamMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamMessagesRequest.ProtoReflect.Descriptor instead.
+func (*StreamMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *StreamMessagesRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *StreamMessagesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StreamMessagesRequest) GetSinceCursor() string {
+	if x != nil {
+		return x.SinceCursor
+	}
+	return ""
+}
+
+type MarkAsReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkAsReadRequest) Reset() {
+	*x = MarkAsReadRequest{}
+	mi := &file_proto_chat_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAsReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAsReadRequest) ProtoMessage() {}
+
+func (x *MarkAsReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAsReadRequest.ProtoReflect.Descriptor instead.
+func (*MarkAsReadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *MarkAsReadRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *MarkAsReadRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MarkAsReadRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type MarkAsReadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkAsReadResponse) Reset() {
+	*x = MarkAsReadResponse{}
+	mi := &file_proto_chat_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAsReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAsReadResponse) ProtoMessage() {}
+
+func (x *MarkAsReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAsReadResponse.ProtoReflect.Descriptor instead.
+func (*MarkAsReadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *MarkAsReadResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MarkAsReadResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type MarkAllAsReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkAllAsReadRequest) Reset() {
+	*x = MarkAllAsReadRequest{}
+	mi := &file_proto_chat_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAllAsReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAllAsReadRequest) ProtoMessage() {}
+
+func (x *MarkAllAsReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAllAsReadRequest.ProtoReflect.Descriptor instead.
+func (*MarkAllAsReadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *MarkAllAsReadRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type MarkAllAsReadResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Success          bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message          string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	RoomsAffected    int32                  `protobuf:"varint,3,opt,name=rooms_affected,json=roomsAffected,proto3" json:"rooms_affected,omitempty"`          // 有訊息被標記為已讀的聊天室數量
+	MessagesAffected int64                  `protobuf:"varint,4,opt,name=messages_affected,json=messagesAffected,proto3" json:"messages_affected,omitempty"` // 被標記為已讀的訊息數量
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *MarkAllAsReadResponse) Reset() {
+	*x = MarkAllAsReadResponse{}
+	mi := &file_proto_chat_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAllAsReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAllAsReadResponse) ProtoMessage() {}
+
+func (x *MarkAllAsReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAllAsReadResponse.ProtoReflect.Descriptor instead.
+func (*MarkAllAsReadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *MarkAllAsReadResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MarkAllAsReadResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *MarkAllAsReadResponse) GetRoomsAffected() int32 {
+	if x != nil {
+		return x.RoomsAffected
+	}
+	return 0
+}
+
+func (x *MarkAllAsReadResponse) GetMessagesAffected() int64 {
+	if x != nil {
+		return x.MessagesAffected
+	}
+	return 0
+}
+
+type DeleteUserDataRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	RequesterId       string                 `protobuf:"bytes,1,opt,name=requester_id,json=requesterId,proto3" json:"requester_id,omitempty"`                      // 發起請求的用戶（本人或數據保護管理員）
+	UserId            string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`                                     // 要刪除數據的用戶
+	DryRun            bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                    // 只統計將受影響的數據，不執行刪除
+	DeleteDirectRooms bool                   `protobuf:"varint,4,opt,name=delete_direct_rooms,json=deleteDirectRooms,proto3" json:"delete_direct_rooms,omitempty"` // 同時刪除只剩該用戶一個成員的私聊
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DeleteUserDataRequest) Reset() {
+	*x = DeleteUserDataRequest{}
+	mi := &file_proto_chat_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserDataRequest) ProtoMessage() {}
+
+func (x *DeleteUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserDataRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *DeleteUserDataRequest) GetRequesterId() string {
+	if x != nil {
+		return x.RequesterId
+	}
+	return ""
+}
+
+func (x *DeleteUserDataRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteUserDataRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *DeleteUserDataRequest) GetDeleteDirectRooms() bool {
+	if x != nil {
+		return x.DeleteDirectRooms
+	}
+	return false
+}
+
+type DeleteUserDataResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Success            bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message            string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	DryRun             bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	MessagesAnonymized int64                  `protobuf:"varint,4,opt,name=messages_anonymized,json=messagesAnonymized,proto3" json:"messages_anonymized,omitempty"`   // 用戶發送的訊息（替換為佔位訊息）
+	ReferencesRemoved  int64                  `protobuf:"varint,5,opt,name=references_removed,json=referencesRemoved,proto3" json:"references_removed,omitempty"`      // 含有用戶已讀/送達回執、提及或轉發來源的訊息
+	RoomsLeft          int64                  `protobuf:"varint,6,opt,name=rooms_left,json=roomsLeft,proto3" json:"rooms_left,omitempty"`                              // 移除了該用戶的聊天室
+	DirectRoomsDeleted int64                  `protobuf:"varint,7,opt,name=direct_rooms_deleted,json=directRoomsDeleted,proto3" json:"direct_rooms_deleted,omitempty"` // 刪除的私聊
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DeleteUserDataResponse) Reset() {
+	*x = DeleteUserDataResponse{}
+	mi := &file_proto_chat_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserDataResponse) ProtoMessage() {}
+
+func (x *DeleteUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserDataResponse.ProtoReflect.Descriptor instead.
+func (*DeleteUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *DeleteUserDataResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteUserDataResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DeleteUserDataResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *DeleteUserDataResponse) GetMessagesAnonymized() int64 {
+	if x != nil {
+		return x.MessagesAnonymized
+	}
+	return 0
+}
+
+func (x *DeleteUserDataResponse) GetReferencesRemoved() int64 {
+	if x != nil {
+		return x.ReferencesRemoved
+	}
+	return 0
+}
+
+func (x *DeleteUserDataResponse) GetRoomsLeft() int64 {
+	if x != nil {
+		return x.RoomsLeft
+	}
+	return 0
+}
+
+func (x *DeleteUserDataResponse) GetDirectRoomsDeleted() int64 {
+	if x != nil {
+		return x.DirectRoomsDeleted
+	}
+	return 0
+}
+
+type MarkAsDeliveredRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 為空時標記聊天室中所有訊息
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkAsDeliveredRequest) Reset() {
+	*x = MarkAsDeliveredRequest{}
+	mi := &file_proto_chat_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAsDeliveredRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAsDeliveredRequest) ProtoMessage() {}
+
+func (x *MarkAsDeliveredRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAsDeliveredRequest.ProtoReflect.Descriptor instead.
+func (*MarkAsDeliveredRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *MarkAsDeliveredRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *MarkAsDeliveredRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MarkAsDeliveredRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type MarkAsDeliveredResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkAsDeliveredResponse) Reset() {
+	*x = MarkAsDeliveredResponse{}
+	mi := &file_proto_chat_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkAsDeliveredResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkAsDeliveredResponse) ProtoMessage() {}
+
+func (x *MarkAsDeliveredResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkAsDeliveredResponse.ProtoReflect.Descriptor instead.
+func (*MarkAsDeliveredResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *MarkAsDeliveredResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MarkAsDeliveredResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetUnreadCountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`                    // optional
+	ExcludeMuted  bool                   `protobuf:"varint,3,opt,name=exclude_muted,json=excludeMuted,proto3" json:"exclude_muted,omitempty"` // 聊天室已被該用戶靜音時返回 0
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUnreadCountRequest) Reset() {
+	*x = GetUnreadCountRequest{}
+	mi := &file_proto_chat_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUnreadCountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUnreadCountRequest) ProtoMessage() {}
+
+func (x *GetUnreadCountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUnreadCountRequest.ProtoReflect.Descriptor instead.
+func (*GetUnreadCountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *GetUnreadCountRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetUnreadCountRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetUnreadCountRequest) GetExcludeMuted() bool {
+	if x != nil {
+		return x.ExcludeMuted
+	}
+	return false
+}
+
+type GetUnreadCountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUnreadCountResponse) Reset() {
+	*x = GetUnreadCountResponse{}
+	mi := &file_proto_chat_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUnreadCountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUnreadCountResponse) ProtoMessage() {}
+
+func (x *GetUnreadCountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUnreadCountResponse.ProtoReflect.Descriptor instead.
+func (*GetUnreadCountResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *GetUnreadCountResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetUnreadCountResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetUnreadCountResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetRoomMessageCountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 請求者（必須是聊天室成員，JWT 管理員除外）
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`                   // 可選，只統計指定類型的訊息
+	Since         int64                  `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`                // 可選，創建時間下限（Unix 秒，包含）
+	Until         int64                  `protobuf:"varint,5,opt,name=until,proto3" json:"until,omitempty"`                // 可選，創建時間上限（Unix 秒，包含）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomMessageCountRequest) Reset() {
+	*x = GetRoomMessageCountRequest{}
+	mi := &file_proto_chat_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomMessageCountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomMessageCountRequest) ProtoMessage() {}
+
+func (x *GetRoomMessageCountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomMessageCountRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomMessageCountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *GetRoomMessageCountRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetRoomMessageCountRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRoomMessageCountRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GetRoomMessageCountRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+func (x *GetRoomMessageCountRequest) GetUntil() int64 {
+	if x != nil {
+		return x.Until
+	}
+	return 0
+}
+
+type GetRoomMessageCountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Count         int64                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // 失敗原因：invalid_argument / permission_denied / internal
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomMessageCountResponse) Reset() {
+	*x = GetRoomMessageCountResponse{}
+	mi := &file_proto_chat_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomMessageCountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomMessageCountResponse) ProtoMessage() {}
+
+func (x *GetRoomMessageCountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomMessageCountResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomMessageCountResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *GetRoomMessageCountResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetRoomMessageCountResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetRoomMessageCountResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GetRoomMessageCountResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+// 雙向聊天流：客戶端事件
+type ChatStreamRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ChatStreamRequest_Subscribe
+	//	*ChatStreamRequest_Send
+	Payload       isChatStreamRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatStreamRequest) Reset() {
+	*x = ChatStreamRequest{}
+	mi := &file_proto_chat_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatStreamRequest) ProtoMessage() {}
+
+func (x *ChatStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatStreamRequest.ProtoReflect.Descriptor instead.
+func (*ChatStreamRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ChatStreamRequest) GetPayload() isChatStreamRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ChatStreamRequest) GetSubscribe() *ChatSubscribe {
+	if x != nil {
+		if x, ok := x.Payload.(*ChatStreamRequest_Subscribe); ok {
+			return x.Subscribe
+		}
+	}
+	return nil
+}
+
+func (x *ChatStreamRequest) GetSend() *ChatSend {
+	if x != nil {
+		if x, ok := x.Payload.(*ChatStreamRequest_Send); ok {
+			return x.Send
+		}
+	}
+	return nil
+}
+
+type isChatStreamRequest_Payload interface {
+	isChatStreamRequest_Payload()
+}
+
+type ChatStreamRequest_Subscribe struct {
+	Subscribe *ChatSubscribe `protobuf:"bytes,1,opt,name=subscribe,proto3,oneof"` // 訂閱聊天室新訊息
+}
+
+type ChatStreamRequest_Send struct {
+	Send *ChatSend `protobuf:"bytes,2,opt,name=send,proto3,oneof"` // 發送訊息
+}
+
+func (*ChatStreamRequest_Subscribe) isChatStreamRequest_Payload() {}
+
+func (*ChatStreamRequest_Send) isChatStreamRequest_Payload() {}
+
+// 訂閱聊天室
+type ChatSubscribe struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatSubscribe) Reset() {
+	*x = ChatSubscribe{}
+	mi := &file_proto_chat_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatSubscribe) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatSubscribe) ProtoMessage() {}
+
+func (x *ChatSubscribe) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatSubscribe.ProtoReflect.Descriptor instead.
+func (*ChatSubscribe) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *ChatSubscribe) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ChatSubscribe) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// 透過聊天流發送訊息
+type ChatSend struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ClientMessageId string                 `protobuf:"bytes,1,opt,name=client_message_id,json=clientMessageId,proto3" json:"client_message_id,omitempty"` // 客戶端生成的 ID，用於對應確認
+	Message         *SendMessageRequest    `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChatSend) Reset() {
+	*x = ChatSend{}
+	mi := &file_proto_chat_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatSend) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatSend) ProtoMessage() {}
+
+func (x *ChatSend) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatSend.ProtoReflect.Descriptor instead.
+func (*ChatSend) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *ChatSend) GetClientMessageId() string {
+	if x != nil {
+		return x.ClientMessageId
+	}
+	return ""
+}
+
+func (x *ChatSend) GetMessage() *SendMessageRequest {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 雙向聊天流：服務端事件
+type ChatStreamResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ChatStreamResponse_Ack
+	//	*ChatStreamResponse_Message
+	Payload       isChatStreamResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatStreamResponse) Reset() {
+	*x = ChatStreamResponse{}
+	mi := &file_proto_chat_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatStreamResponse) ProtoMessage() {}
+
+func (x *ChatStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatStreamResponse.ProtoReflect.Descriptor instead.
+func (*ChatStreamResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *ChatStreamResponse) GetPayload() isChatStreamResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ChatStreamResponse) GetAck() *MessageAck {
+	if x != nil {
+		if x, ok := x.Payload.(*ChatStreamResponse_Ack); ok {
+			return x.Ack
+		}
+	}
+	return nil
+}
+
+func (x *ChatStreamResponse) GetMessage() *ChatMessage {
+	if x != nil {
+		if x, ok := x.Payload.(*ChatStreamResponse_Message); ok {
+			return x.Message
+		}
+	}
+	return nil
+}
+
+type isChatStreamResponse_Payload interface {
+	isChatStreamResponse_Payload()
+}
+
+type ChatStreamResponse_Ack struct {
+	Ack *MessageAck `protobuf:"bytes,1,opt,name=ack,proto3,oneof"` // 發送確認
+}
+
+type ChatStreamResponse_Message struct {
+	Message *ChatMessage `protobuf:"bytes,2,opt,name=message,proto3,oneof"` // 聊天室新訊息
+}
+
+func (*ChatStreamResponse_Ack) isChatStreamResponse_Payload() {}
+
+func (*ChatStreamResponse_Message) isChatStreamResponse_Payload() {}
+
+// 訊息已接收確認
+type MessageAck struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ClientMessageId string                 `protobuf:"bytes,1,opt,name=client_message_id,json=clientMessageId,proto3" json:"client_message_id,omitempty"`
+	MessageId       string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"` // 服務端分配的訊息 ID
+	CreatedAt       int64                  `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Sequence        int64                  `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"` // 本聊天流內的確認序號（從 1 開始遞增）
+	Success         bool                   `protobuf:"varint,5,opt,name=success,proto3" json:"success,omitempty"`
+	Message         string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"` // 失敗原因
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MessageAck) Reset() {
+	*x = MessageAck{}
+	mi := &file_proto_chat_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageAck) ProtoMessage() {}
+
+func (x *MessageAck) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageAck.ProtoReflect.Descriptor instead.
+func (*MessageAck) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *MessageAck) GetClientMessageId() string {
+	if x != nil {
+		return x.ClientMessageId
+	}
+	return ""
+}
+
+func (x *MessageAck) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *MessageAck) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *MessageAck) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *MessageAck) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *MessageAck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// 導入的聊天室
+type ImportRoom struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // group / direct
+	OwnerId       string                 `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	MemberIds     []string               `protobuf:"bytes,4,rep,name=member_ids,json=memberIds,proto3" json:"member_ids,omitempty"`
+	Settings      *RoomSettings          `protobuf:"bytes,5,opt,name=settings,proto3" json:"settings,omitempty"`                     // 可選，未提供時使用默認設置
+	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // 原始創建時間（Unix 秒），0 表示當前時間
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRoom) Reset() {
+	*x = ImportRoom{}
+	mi := &file_proto_chat_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRoom) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRoom) ProtoMessage() {}
+
+func (x *ImportRoom) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRoom.ProtoReflect.Descriptor instead.
+func (*ImportRoom) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *ImportRoom) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportRoom) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ImportRoom) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *ImportRoom) GetMemberIds() []string {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+func (x *ImportRoom) GetSettings() *RoomSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *ImportRoom) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ImportRoomsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rooms         []*ImportRoom          `protobuf:"bytes,1,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRoomsRequest) Reset() {
+	*x = ImportRoomsRequest{}
+	mi := &file_proto_chat_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRoomsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRoomsRequest) ProtoMessage() {}
+
+func (x *ImportRoomsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRoomsRequest.ProtoReflect.Descriptor instead.
+func (*ImportRoomsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ImportRoomsRequest) GetRooms() []*ImportRoom {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+// 單個導入項目的結果
+type ImportResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // 在請求中的下標
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Id            string                 `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`       // 成功時為創建的聊天室或訊息 ID
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // 失敗原因
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportResult) Reset() {
+	*x = ImportResult{}
+	mi := &file_proto_chat_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportResult) ProtoMessage() {}
+
+func (x *ImportResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportResult.ProtoReflect.Descriptor instead.
+func (*ImportResult) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *ImportResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ImportResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ImportResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ImportResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ImportRoomsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // 整個請求失敗的原因：invalid_argument / permission_denied
+	Results       []*ImportResult        `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`                      // 與請求中的聊天室一一對應
+	Imported      int32                  `protobuf:"varint,5,opt,name=imported,proto3" json:"imported,omitempty"`
+	Failed        int32                  `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRoomsResponse) Reset() {
+	*x = ImportRoomsResponse{}
+	mi := &file_proto_chat_proto_msgTypes[94]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MarkAsReadRequest) String() string {
+func (x *ImportRoomsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MarkAsReadRequest) ProtoMessage() {}
+func (*ImportRoomsResponse) ProtoMessage() {}
 
-func (x *MarkAsReadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[20]
+func (x *ImportRoomsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1543,55 +6673,81 @@ func (x *MarkAsReadRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MarkAsReadRequest.ProtoReflect.Descriptor instead.
-func (*MarkAsReadRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use ImportRoomsResponse.ProtoReflect.Descriptor instead.
+func (*ImportRoomsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{94}
 }
 
-func (x *MarkAsReadRequest) GetRoomId() string {
+func (x *ImportRoomsResponse) GetSuccess() bool {
 	if x != nil {
-		return x.RoomId
+		return x.Success
 	}
-	return ""
+	return false
 }
 
-func (x *MarkAsReadRequest) GetUserId() string {
+func (x *ImportRoomsResponse) GetMessage() string {
 	if x != nil {
-		return x.UserId
+		return x.Message
 	}
 	return ""
 }
 
-func (x *MarkAsReadRequest) GetMessageId() string {
+func (x *ImportRoomsResponse) GetErrorCode() string {
 	if x != nil {
-		return x.MessageId
+		return x.ErrorCode
 	}
 	return ""
 }
 
-type MarkAsReadResponse struct {
+func (x *ImportRoomsResponse) GetResults() []*ImportResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ImportRoomsResponse) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportRoomsResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+// 導入的訊息（內容為明文，導入時使用聊天室密鑰加密）
+type ImportMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	SenderId      string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"` // 為空時視為 text
+	Metadata      *MessageMetadata       `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // 原始發送時間（Unix 秒，必填）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *MarkAsReadResponse) Reset() {
-	*x = MarkAsReadResponse{}
-	mi := &file_proto_chat_proto_msgTypes[21]
+func (x *ImportMessage) Reset() {
+	*x = ImportMessage{}
+	mi := &file_proto_chat_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MarkAsReadResponse) String() string {
+func (x *ImportMessage) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MarkAsReadResponse) ProtoMessage() {}
+func (*ImportMessage) ProtoMessage() {}
 
-func (x *MarkAsReadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[21]
+func (x *ImportMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1602,48 +6758,75 @@ func (x *MarkAsReadResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MarkAsReadResponse.ProtoReflect.Descriptor instead.
-func (*MarkAsReadResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use ImportMessage.ProtoReflect.Descriptor instead.
+func (*ImportMessage) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{95}
 }
 
-func (x *MarkAsReadResponse) GetSuccess() bool {
+func (x *ImportMessage) GetRoomId() string {
 	if x != nil {
-		return x.Success
+		return x.RoomId
 	}
-	return false
+	return ""
 }
 
-func (x *MarkAsReadResponse) GetMessage() string {
+func (x *ImportMessage) GetSenderId() string {
 	if x != nil {
-		return x.Message
+		return x.SenderId
 	}
 	return ""
 }
 
-type GetUnreadCountRequest struct {
+func (x *ImportMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ImportMessage) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ImportMessage) GetMetadata() *MessageMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ImportMessage) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type ImportMessagesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // optional
+	Messages      []*ImportMessage       `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUnreadCountRequest) Reset() {
-	*x = GetUnreadCountRequest{}
-	mi := &file_proto_chat_proto_msgTypes[22]
+func (x *ImportMessagesRequest) Reset() {
+	*x = ImportMessagesRequest{}
+	mi := &file_proto_chat_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUnreadCountRequest) String() string {
+func (x *ImportMessagesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUnreadCountRequest) ProtoMessage() {}
+func (*ImportMessagesRequest) ProtoMessage() {}
 
-func (x *GetUnreadCountRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[22]
+func (x *ImportMessagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1654,49 +6837,45 @@ func (x *GetUnreadCountRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUnreadCountRequest.ProtoReflect.Descriptor instead.
-func (*GetUnreadCountRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{22}
-}
-
-func (x *GetUnreadCountRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use ImportMessagesRequest.ProtoReflect.Descriptor instead.
+func (*ImportMessagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{96}
 }
 
-func (x *GetUnreadCountRequest) GetRoomId() string {
+func (x *ImportMessagesRequest) GetMessages() []*ImportMessage {
 	if x != nil {
-		return x.RoomId
+		return x.Messages
 	}
-	return ""
+	return nil
 }
 
-type GetUnreadCountResponse struct {
+type ImportMessagesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,3,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"` // 整個請求失敗的原因：invalid_argument / permission_denied
+	Results       []*ImportResult        `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`                      // 與請求中的訊息一一對應
+	Imported      int32                  `protobuf:"varint,5,opt,name=imported,proto3" json:"imported,omitempty"`
+	Failed        int32                  `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUnreadCountResponse) Reset() {
-	*x = GetUnreadCountResponse{}
-	mi := &file_proto_chat_proto_msgTypes[23]
+func (x *ImportMessagesResponse) Reset() {
+	*x = ImportMessagesResponse{}
+	mi := &file_proto_chat_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUnreadCountResponse) String() string {
+func (x *ImportMessagesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUnreadCountResponse) ProtoMessage() {}
+func (*ImportMessagesResponse) ProtoMessage() {}
 
-func (x *GetUnreadCountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chat_proto_msgTypes[23]
+func (x *ImportMessagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chat_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1707,28 +6886,49 @@ func (x *GetUnreadCountResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUnreadCountResponse.ProtoReflect.Descriptor instead.
-func (*GetUnreadCountResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chat_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use ImportMessagesResponse.ProtoReflect.Descriptor instead.
+func (*ImportMessagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chat_proto_rawDescGZIP(), []int{97}
 }
 
-func (x *GetUnreadCountResponse) GetSuccess() bool {
+func (x *ImportMessagesResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetUnreadCountResponse) GetMessage() string {
+func (x *ImportMessagesResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *GetUnreadCountResponse) GetCount() int32 {
+func (x *ImportMessagesResponse) GetErrorCode() string {
 	if x != nil {
-		return x.Count
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *ImportMessagesResponse) GetResults() []*ImportResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ImportMessagesResponse) GetImported() int32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportMessagesResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
 	}
 	return 0
 }
@@ -1737,7 +6937,7 @@ var File_proto_chat_proto protoreflect.FileDescriptor
 
 const file_proto_chat_proto_rawDesc = "" +
 	"\n" +
-	"\x10proto/chat.proto\x12\x04chat\"\xee\x02\n" +
+	"\x10proto/chat.proto\x12\x04chat\"\xd8\x04\n" +
 	"\bChatRoom\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
@@ -1752,7 +6952,17 @@ const file_proto_chat_proto_rawDesc = "" +
 	"\x0flast_message_at\x18\t \x01(\x03R\rlastMessageAt\x12!\n" +
 	"\flast_message\x18\n" +
 	" \x01(\tR\vlastMessage\x12*\n" +
-	"\x11last_message_time\x18\v \x01(\x03R\x0flastMessageTime\"\xd4\x01\n" +
+	"\x11last_message_time\x18\v \x01(\x03R\x0flastMessageTime\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\f \x01(\tR\tavatarUrl\x12\x14\n" +
+	"\x05muted\x18\r \x01(\bR\x05muted\x12\x1f\n" +
+	"\vmuted_until\x18\x0e \x01(\x03R\n" +
+	"mutedUntil\x12\x1a\n" +
+	"\barchived\x18\x0f \x01(\bR\barchived\x12\x18\n" +
+	"\aversion\x18\x10 \x01(\x03R\aversion\x12!\n" +
+	"\fmember_count\x18\x11 \x01(\x05R\vmemberCount\x12\x16\n" +
+	"\x06pinned\x18\x12 \x01(\bR\x06pinned\x12!\n" +
+	"\funread_count\x18\x13 \x01(\x05R\vunreadCount\"\x8b\x02\n" +
 	"\n" +
 	"RoomMember\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
@@ -1762,7 +6972,10 @@ const file_proto_chat_proto_rawDesc = "" +
 	"\tjoined_at\x18\x05 \x01(\x03R\bjoinedAt\x12\x1b\n" +
 	"\tlast_seen\x18\x06 \x01(\x03R\blastSeen\x12 \n" +
 	"\flast_read_at\x18\a \x01(\x03R\n" +
-	"lastReadAt\"\x8d\x02\n" +
+	"lastReadAt\x12\x14\n" +
+	"\x05muted\x18\b \x01(\bR\x05muted\x12\x1f\n" +
+	"\vmuted_until\x18\t \x01(\x03R\n" +
+	"mutedUntil\"\xe4\x02\n" +
 	"\fRoomSettings\x12!\n" +
 	"\fallow_invite\x18\x01 \x01(\bR\vallowInvite\x12.\n" +
 	"\x13allow_edit_messages\x18\x02 \x01(\bR\x11allowEditMessages\x122\n" +
@@ -1770,7 +6983,10 @@ const file_proto_chat_proto_rawDesc = "" +
 	"\x12allow_pin_messages\x18\x04 \x01(\bR\x10allowPinMessages\x12\x1f\n" +
 	"\vmax_members\x18\x05 \x01(\x05R\n" +
 	"maxMembers\x12'\n" +
-	"\x0fwelcome_message\x18\x06 \x01(\tR\x0ewelcomeMessage\"\xae\x02\n" +
+	"\x0fwelcome_message\x18\x06 \x01(\tR\x0ewelcomeMessage\x12\x12\n" +
+	"\x04mode\x18\a \x01(\tR\x04mode\x12)\n" +
+	"\x10slowmode_seconds\x18\b \x01(\x05R\x0fslowmodeSeconds\x12\x16\n" +
+	"\x06locale\x18\t \x01(\tR\x06locale\"\x98\x05\n" +
 	"\vChatMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x1b\n" +
@@ -1784,7 +7000,28 @@ const file_proto_chat_proto_rawDesc = "" +
 	"updated_at\x18\b \x01(\x03R\tupdatedAt\x12\x17\n" +
 	"\aread_by\x18\t \x03(\tR\x06readBy\x12!\n" +
 	"\fdelivered_to\x18\n" +
-	" \x03(\tR\vdeliveredTo\"\xec\x02\n" +
+	" \x03(\tR\vdeliveredTo\x12\x1a\n" +
+	"\bmentions\x18\v \x03(\tR\bmentions\x12:\n" +
+	"\x0eforwarded_from\x18\f \x01(\v2\x13.chat.ForwardedFromR\rforwardedFrom\x126\n" +
+	"\rread_receipts\x18\r \x03(\v2\x11.chat.ReadReceiptR\freadReceipts\x12\x16\n" +
+	"\x06status\x18\x0e \x01(\tR\x06status\x12\x16\n" +
+	"\x06cursor\x18\x0f \x01(\tR\x06cursor\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x10 \x01(\x03R\texpiresAt\x12\x18\n" +
+	"\aexpired\x18\x11 \x01(\bR\aexpired\x12\x1f\n" +
+	"\vsender_name\x18\x12 \x01(\tR\n" +
+	"senderName\x12-\n" +
+	"\x13reply_to_message_id\x18\x13 \x01(\tR\x10replyToMessageId\x12\x1f\n" +
+	"\vreply_count\x18\x14 \x01(\x05R\n" +
+	"replyCount\"?\n" +
+	"\vReadReceipt\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\aread_at\x18\x02 \x01(\x03R\x06readAt\"d\n" +
+	"\rForwardedFrom\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x1b\n" +
+	"\tsender_id\x18\x03 \x01(\tR\bsenderId\"\xec\x02\n" +
 	"\x0fMessageMetadata\x12\x1b\n" +
 	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12\x1b\n" +
 	"\tfile_size\x18\x02 \x01(\tR\bfileSize\x12\x1b\n" +
@@ -1821,33 +7058,271 @@ const file_proto_chat_proto_rawDesc = "" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\"G\n" +
 	"\x11LeaveRoomResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"b\n" +
+	"\x11KickMemberRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tmember_id\x18\x03 \x01(\tR\bmemberId\"H\n" +
+	"\x12KickMemberResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x9a\x01\n" +
+	"\x18TransferOwnershipRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12 \n" +
+	"\fnew_owner_id\x18\x03 \x01(\tR\n" +
+	"newOwnerId\x12*\n" +
+	"\x11former_owner_role\x18\x04 \x01(\tR\x0fformerOwnerRole\"O\n" +
+	"\x19TransferOwnershipResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"E\n" +
+	"\x11DeleteRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x96\x01\n" +
+	"\x12DeleteRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12)\n" +
+	"\x10deleted_messages\x18\x03 \x01(\x03R\x0fdeletedMessages\x12!\n" +
+	"\fdeleted_keys\x18\x04 \x01(\x03R\vdeletedKeys\"\x95\x02\n" +
+	"\x11UpdateRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x17\n" +
+	"\x04name\x18\x03 \x01(\tH\x00R\x04name\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"avatar_url\x18\x04 \x01(\tH\x01R\tavatarUrl\x88\x01\x01\x124\n" +
+	"\bsettings\x18\x05 \x01(\v2\x18.chat.RoomSettingsUpdateR\bsettings\x12.\n" +
+	"\x10expected_version\x18\x06 \x01(\x03H\x02R\x0fexpectedVersion\x88\x01\x01B\a\n" +
+	"\x05_nameB\r\n" +
+	"\v_avatar_urlB\x13\n" +
+	"\x11_expected_version\"\xd7\x03\n" +
+	"\x12RoomSettingsUpdate\x12&\n" +
+	"\fallow_invite\x18\x01 \x01(\bH\x00R\vallowInvite\x88\x01\x01\x123\n" +
+	"\x13allow_edit_messages\x18\x02 \x01(\bH\x01R\x11allowEditMessages\x88\x01\x01\x127\n" +
+	"\x15allow_delete_messages\x18\x03 \x01(\bH\x02R\x13allowDeleteMessages\x88\x01\x01\x121\n" +
+	"\x12allow_pin_messages\x18\x04 \x01(\bH\x03R\x10allowPinMessages\x88\x01\x01\x12$\n" +
+	"\vmax_members\x18\x05 \x01(\x05H\x04R\n" +
+	"maxMembers\x88\x01\x01\x12,\n" +
+	"\x0fwelcome_message\x18\x06 \x01(\tH\x05R\x0ewelcomeMessage\x88\x01\x01\x12\x1b\n" +
+	"\x06locale\x18\a \x01(\tH\x06R\x06locale\x88\x01\x01B\x0f\n" +
+	"\r_allow_inviteB\x16\n" +
+	"\x14_allow_edit_messagesB\x18\n" +
+	"\x16_allow_delete_messagesB\x15\n" +
+	"\x13_allow_pin_messagesB\x0e\n" +
+	"\f_max_membersB\x12\n" +
+	"\x10_welcome_messageB\t\n" +
+	"\a_locale\"l\n" +
+	"\x12UpdateRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\"\n" +
+	"\x04room\x18\x03 \x01(\v2\x0e.chat.ChatRoomR\x04room\"\x85\x01\n" +
+	"\x12SetRoomModeRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12)\n" +
+	"\x10slowmode_seconds\x18\x04 \x01(\x05R\x0fslowmodeSeconds\"I\n" +
+	"\x13SetRoomModeResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"n\n" +
+	"\x0fMuteRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12)\n" +
+	"\x10duration_seconds\x18\x03 \x01(\x03R\x0fdurationSeconds\"g\n" +
+	"\x10MuteRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\vmuted_until\x18\x03 \x01(\x03R\n" +
+	"mutedUntil\"E\n" +
+	"\x11UnmuteRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"H\n" +
+	"\x12UnmuteRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\"F\n" +
+	"\x12ArchiveRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"I\n" +
+	"\x13ArchiveRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"H\n" +
+	"\x14UnarchiveRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"K\n" +
+	"\x15UnarchiveRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"B\n" +
+	"\x0ePinRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"E\n" +
+	"\x0fPinRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"D\n" +
+	"\x10UnpinRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"G\n" +
+	"\x11UnpinRoomResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"H\n" +
+	"\x14RotateRoomKeyRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xa5\x01\n" +
+	"\x15RotateRoomKeyResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1f\n" +
+	"\vkey_version\x18\x03 \x01(\x05R\n" +
+	"keyVersion\x12\x1d\n" +
+	"\n" +
+	"rotated_at\x18\x04 \x01(\x03R\trotatedAt\x12\x18\n" +
+	"\acreated\x18\x05 \x01(\bR\acreated\"\x14\n" +
+	"\x12GetKeyStatsRequest\"\x93\x02\n" +
+	"\x13GetKeyStatsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"total_keys\x18\x03 \x01(\x05R\ttotalKeys\x12\x1f\n" +
+	"\vactive_keys\x18\x04 \x01(\x05R\n" +
+	"activeKeys\x12#\n" +
+	"\rarchived_keys\x18\x05 \x01(\x05R\farchivedKeys\x12!\n" +
+	"\frevoked_keys\x18\x06 \x01(\x05R\vrevokedKeys\x12\x1d\n" +
+	"\n" +
+	"cache_hits\x18\a \x01(\x03R\tcacheHits\x12!\n" +
+	"\fcache_misses\x18\b \x01(\x03R\vcacheMisses\"0\n" +
+	"\x15GetRoomKeyInfoRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"\xeb\x02\n" +
+	"\x16GetRoomKeyInfoResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
+	"\aroom_id\x18\x03 \x01(\tR\x06roomId\x12\x1f\n" +
+	"\vkey_version\x18\x04 \x01(\x05R\n" +
+	"keyVersion\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"rotated_at\x18\a \x01(\x03R\trotatedAt\x12\x1f\n" +
+	"\vage_seconds\x18\b \x01(\x03R\n" +
+	"ageSeconds\x12\x16\n" +
+	"\x06cached\x18\t \x01(\bR\x06cached\x12+\n" +
+	"\x11archived_versions\x18\n" +
+	" \x03(\x05R\x10archivedVersions\x12'\n" +
+	"\x0fstored_versions\x18\v \x03(\x05R\x0estoredVersions\"F\n" +
 	"\x12GetRoomInfoRequest\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\"m\n" +
 	"\x13GetRoomInfoResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\"\n" +
-	"\x04room\x18\x03 \x01(\v2\x0e.chat.ChatRoomR\x04room\"]\n" +
+	"\x04room\x18\x03 \x01(\v2\x0e.chat.ChatRoomR\x04room\"K\n" +
+	"\x17GetOnlineMembersRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xae\x01\n" +
+	"\x18GetOnlineMembersResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\amembers\x18\x03 \x03(\v2\x10.chat.RoomMemberR\amembers\x122\n" +
+	"\x15online_window_seconds\x18\x04 \x01(\x05R\x13onlineWindowSeconds\"\xa4\x01\n" +
+	"\x16ListRoomMembersRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04role\x18\x05 \x01(\tR\x04role\x12\x16\n" +
+	"\x06search\x18\x06 \x01(\tR\x06search\"\xac\x01\n" +
+	"\x17ListRoomMembersResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12*\n" +
+	"\amembers\x18\x03 \x03(\v2\x10.chat.RoomMemberR\amembers\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x19\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"\xc1\x01\n" +
 	"\x14ListUserRoomsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\xa4\x01\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12)\n" +
+	"\x10include_archived\x18\x04 \x01(\bR\x0fincludeArchived\x12\x12\n" +
+	"\x04sort\x18\x05 \x01(\tR\x04sort\x12#\n" +
+	"\rexclude_muted\x18\x06 \x01(\bR\fexcludeMuted\"\xa4\x01\n" +
 	"\x15ListUserRoomsResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
 	"\x05rooms\x18\x03 \x03(\v2\x0e.chat.ChatRoomR\x05rooms\x12\x16\n" +
 	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x19\n" +
-	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"\xab\x01\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"\x81\x02\n" +
+	"\x10ListRoomsRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x19\n" +
+	"\bowner_id\x18\x02 \x01(\tR\aownerId\x12\x1f\n" +
+	"\vname_prefix\x18\x03 \x01(\tR\n" +
+	"namePrefix\x12#\n" +
+	"\rcreated_after\x18\x04 \x01(\x03R\fcreatedAfter\x12%\n" +
+	"\x0ecreated_before\x18\x05 \x01(\x03R\rcreatedBefore\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\a \x01(\tR\x06cursor\x12#\n" +
+	"\rname_contains\x18\b \x01(\tR\fnameContains\"\xbf\x01\n" +
+	"\x11ListRoomsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12$\n" +
+	"\x05rooms\x18\x03 \x03(\v2\x0e.chat.ChatRoomR\x05rooms\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12\x19\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x06 \x01(\tR\terrorCode\"\xc0\x02\n" +
 	"\x12SendMessageRequest\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1b\n" +
 	"\tsender_id\x18\x02 \x01(\tR\bsenderId\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12\x12\n" +
 	"\x04type\x18\x04 \x01(\tR\x04type\x121\n" +
-	"\bmetadata\x18\x05 \x01(\v2\x15.chat.MessageMetadataR\bmetadata\"\x7f\n" +
+	"\bmetadata\x18\x05 \x01(\v2\x15.chat.MessageMetadataR\bmetadata\x12\x1a\n" +
+	"\bmentions\x18\x06 \x03(\tR\bmentions\x12\x1f\n" +
+	"\vttl_seconds\x18\a \x01(\x05R\n" +
+	"ttlSeconds\x12'\n" +
+	"\x0fidempotency_key\x18\b \x01(\tR\x0eidempotencyKey\x12-\n" +
+	"\x13reply_to_message_id\x18\t \x01(\tR\x10replyToMessageId\"\x9b\x01\n" +
 	"\x13SendMessageResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
+	"\fchat_message\x18\x03 \x01(\v2\x11.chat.ChatMessageR\vchatMessage\x12\x1a\n" +
+	"\breplayed\x18\x04 \x01(\bR\breplayed\"e\n" +
+	"\x16ScheduleMessageRequest\x122\n" +
+	"\amessage\x18\x01 \x01(\v2\x18.chat.SendMessageRequestR\amessage\x12\x17\n" +
+	"\asend_at\x18\x02 \x01(\x03R\x06sendAt\"\xc4\x02\n" +
+	"\x10ScheduledMessage\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x1b\n" +
+	"\tsender_id\x18\x03 \x01(\tR\bsenderId\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\tR\acontent\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x121\n" +
+	"\bmetadata\x18\x06 \x01(\v2\x15.chat.MessageMetadataR\bmetadata\x12\x1a\n" +
+	"\bmentions\x18\a \x03(\tR\bmentions\x12\x17\n" +
+	"\asend_at\x18\b \x01(\x03R\x06sendAt\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\n" +
+	" \x01(\tR\tmessageId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\v \x01(\x03R\tcreatedAt\"\x92\x01\n" +
+	"\x17ScheduleMessageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12C\n" +
+	"\x11scheduled_message\x18\x03 \x01(\v2\x16.chat.ScheduledMessageR\x10scheduledMessage\"P\n" +
+	"\x1cListScheduledMessagesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\"\x9a\x01\n" +
+	"\x1dListScheduledMessagesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12E\n" +
+	"\x12scheduled_messages\x18\x03 \x03(\v2\x16.chat.ScheduledMessageR\x11scheduledMessages\"H\n" +
+	"\x1dCancelScheduledMessageRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"T\n" +
+	"\x1eCancelScheduledMessageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x9b\x01\n" +
+	"\x15ForwardMessageRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x0esource_room_id\x18\x02 \x01(\tR\fsourceRoomId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\x12$\n" +
+	"\x0etarget_room_id\x18\x04 \x01(\tR\ftargetRoomId\"\x82\x01\n" +
+	"\x16ForwardMessageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
 	"\fchat_message\x18\x03 \x01(\v2\x11.chat.ChatMessageR\vchatMessage\"\xa0\x01\n" +
 	"\x12GetMessagesRequest\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
@@ -1862,10 +7337,68 @@ const file_proto_chat_proto_rawDesc = "" +
 	"\bmessages\x18\x03 \x03(\v2\x11.chat.ChatMessageR\bmessages\x12\x1f\n" +
 	"\vnext_cursor\x18\x04 \x01(\tR\n" +
 	"nextCursor\x12\x19\n" +
-	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"I\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"\\\n" +
+	"\x17GetMessagesPagedRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04skip\x18\x03 \x01(\x05R\x04skip\"\xe7\x01\n" +
+	"\x18GetMessagesPagedResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\bmessages\x18\x03 \x03(\v2\x11.chat.ChatMessageR\bmessages\x12\x1f\n" +
+	"\vtotal_count\x18\x04 \x01(\x03R\n" +
+	"totalCount\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x12\n" +
+	"\x04skip\x18\x06 \x01(\x05R\x04skip\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\a \x01(\tR\terrorCode\"\xd4\x01\n" +
+	"\x18GetMessagesAroundRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12*\n" +
+	"\x11anchor_message_id\x18\x03 \x01(\tR\x0fanchorMessageId\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\x12!\n" +
+	"\fbefore_limit\x18\x05 \x01(\x05R\vbeforeLimit\x12\x1f\n" +
+	"\vafter_limit\x18\x06 \x01(\x05R\n" +
+	"afterLimit\"\x82\x02\n" +
+	"\x19GetMessagesAroundResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\bmessages\x18\x03 \x03(\v2\x11.chat.ChatMessageR\bmessages\x12#\n" +
+	"\rbefore_cursor\x18\x04 \x01(\tR\fbeforeCursor\x12\x1d\n" +
+	"\n" +
+	"has_before\x18\x05 \x01(\bR\thasBefore\x12!\n" +
+	"\fafter_cursor\x18\x06 \x01(\tR\vafterCursor\x12\x1b\n" +
+	"\thas_after\x18\a \x01(\bR\bhasAfter\"K\n" +
+	"\x11GetMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x9d\x01\n" +
+	"\x12GetMessageResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x124\n" +
+	"\fchat_message\x18\x03 \x01(\v2\x11.chat.ChatMessageR\vchatMessage\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x04 \x01(\tR\terrorCode\"\x9a\x01\n" +
+	"\x10GetThreadRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12&\n" +
+	"\x0froot_message_id\x18\x03 \x01(\tR\rrootMessageId\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"\xf8\x01\n" +
+	"\x11GetThreadResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x04root\x18\x03 \x01(\v2\x11.chat.ChatMessageR\x04root\x12-\n" +
+	"\bmessages\x18\x04 \x03(\v2\x11.chat.ChatMessageR\bmessages\x12\x1f\n" +
+	"\vnext_cursor\x18\x05 \x01(\tR\n" +
+	"nextCursor\x12\x19\n" +
+	"\bhas_more\x18\x06 \x01(\bR\ahasMore\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\a \x01(\tR\terrorCode\"l\n" +
 	"\x15StreamMessagesRequest\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"d\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12!\n" +
+	"\fsince_cursor\x18\x03 \x01(\tR\vsinceCursor\"d\n" +
 	"\x11MarkAsReadRequest\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
@@ -1873,27 +7406,173 @@ const file_proto_chat_proto_rawDesc = "" +
 	"message_id\x18\x03 \x01(\tR\tmessageId\"H\n" +
 	"\x12MarkAsReadResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"I\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"/\n" +
+	"\x14MarkAllAsReadRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\x9f\x01\n" +
+	"\x15MarkAllAsReadResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x0erooms_affected\x18\x03 \x01(\x05R\rroomsAffected\x12+\n" +
+	"\x11messages_affected\x18\x04 \x01(\x03R\x10messagesAffected\"\x9c\x01\n" +
+	"\x15DeleteUserDataRequest\x12!\n" +
+	"\frequester_id\x18\x01 \x01(\tR\vrequesterId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12.\n" +
+	"\x13delete_direct_rooms\x18\x04 \x01(\bR\x11deleteDirectRooms\"\x96\x02\n" +
+	"\x16DeleteUserDataResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12/\n" +
+	"\x13messages_anonymized\x18\x04 \x01(\x03R\x12messagesAnonymized\x12-\n" +
+	"\x12references_removed\x18\x05 \x01(\x03R\x11referencesRemoved\x12\x1d\n" +
+	"\n" +
+	"rooms_left\x18\x06 \x01(\x03R\troomsLeft\x120\n" +
+	"\x14direct_rooms_deleted\x18\a \x01(\x03R\x12directRoomsDeleted\"i\n" +
+	"\x16MarkAsDeliveredRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\"M\n" +
+	"\x17MarkAsDeliveredResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"n\n" +
 	"\x15GetUnreadCountRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
-	"\aroom_id\x18\x02 \x01(\tR\x06roomId\"b\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12#\n" +
+	"\rexclude_muted\x18\x03 \x01(\bR\fexcludeMuted\"b\n" +
 	"\x16GetUnreadCountResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
-	"\x05count\x18\x03 \x01(\x05R\x05count2\xb3\x05\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\"\x8e\x01\n" +
+	"\x1aGetRoomMessageCountRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x14\n" +
+	"\x05since\x18\x04 \x01(\x03R\x05since\x12\x14\n" +
+	"\x05until\x18\x05 \x01(\x03R\x05until\"\x86\x01\n" +
+	"\x1bGetRoomMessageCountResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x04 \x01(\tR\terrorCode\"y\n" +
+	"\x11ChatStreamRequest\x123\n" +
+	"\tsubscribe\x18\x01 \x01(\v2\x13.chat.ChatSubscribeH\x00R\tsubscribe\x12$\n" +
+	"\x04send\x18\x02 \x01(\v2\x0e.chat.ChatSendH\x00R\x04sendB\t\n" +
+	"\apayload\"A\n" +
+	"\rChatSubscribe\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"j\n" +
+	"\bChatSend\x12*\n" +
+	"\x11client_message_id\x18\x01 \x01(\tR\x0fclientMessageId\x122\n" +
+	"\amessage\x18\x02 \x01(\v2\x18.chat.SendMessageRequestR\amessage\"t\n" +
+	"\x12ChatStreamResponse\x12$\n" +
+	"\x03ack\x18\x01 \x01(\v2\x10.chat.MessageAckH\x00R\x03ack\x12-\n" +
+	"\amessage\x18\x02 \x01(\v2\x11.chat.ChatMessageH\x00R\amessageB\t\n" +
+	"\apayload\"\xc6\x01\n" +
+	"\n" +
+	"MessageAck\x12*\n" +
+	"\x11client_message_id\x18\x01 \x01(\tR\x0fclientMessageId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\x03R\tcreatedAt\x12\x1a\n" +
+	"\bsequence\x18\x04 \x01(\x03R\bsequence\x12\x18\n" +
+	"\asuccess\x18\x05 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\"\xbd\x01\n" +
+	"\n" +
+	"ImportRoom\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x19\n" +
+	"\bowner_id\x18\x03 \x01(\tR\aownerId\x12\x1d\n" +
+	"\n" +
+	"member_ids\x18\x04 \x03(\tR\tmemberIds\x12.\n" +
+	"\bsettings\x18\x05 \x01(\v2\x12.chat.RoomSettingsR\bsettings\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\"<\n" +
+	"\x12ImportRoomsRequest\x12&\n" +
+	"\x05rooms\x18\x01 \x03(\v2\x10.chat.ImportRoomR\x05rooms\"d\n" +
+	"\fImportResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x0e\n" +
+	"\x02id\x18\x03 \x01(\tR\x02id\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xca\x01\n" +
+	"\x13ImportRoomsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x03 \x01(\tR\terrorCode\x12,\n" +
+	"\aresults\x18\x04 \x03(\v2\x12.chat.ImportResultR\aresults\x12\x1a\n" +
+	"\bimported\x18\x05 \x01(\x05R\bimported\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\x05R\x06failed\"\xc5\x01\n" +
+	"\rImportMessage\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1b\n" +
+	"\tsender_id\x18\x02 \x01(\tR\bsenderId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x121\n" +
+	"\bmetadata\x18\x05 \x01(\v2\x15.chat.MessageMetadataR\bmetadata\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\"H\n" +
+	"\x15ImportMessagesRequest\x12/\n" +
+	"\bmessages\x18\x01 \x03(\v2\x13.chat.ImportMessageR\bmessages\"\xcd\x01\n" +
+	"\x16ImportMessagesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x03 \x01(\tR\terrorCode\x12,\n" +
+	"\aresults\x18\x04 \x03(\v2\x12.chat.ImportResultR\aresults\x12\x1a\n" +
+	"\bimported\x18\x05 \x01(\x05R\bimported\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\x05R\x06failed2\xeb\x17\n" +
 	"\x0fChatRoomService\x12?\n" +
 	"\n" +
 	"CreateRoom\x12\x17.chat.CreateRoomRequest\x1a\x18.chat.CreateRoomResponse\x129\n" +
 	"\bJoinRoom\x12\x15.chat.JoinRoomRequest\x1a\x16.chat.JoinRoomResponse\x12<\n" +
-	"\tLeaveRoom\x12\x16.chat.LeaveRoomRequest\x1a\x17.chat.LeaveRoomResponse\x12B\n" +
-	"\vGetRoomInfo\x12\x18.chat.GetRoomInfoRequest\x1a\x19.chat.GetRoomInfoResponse\x12H\n" +
-	"\rListUserRooms\x12\x1a.chat.ListUserRoomsRequest\x1a\x1b.chat.ListUserRoomsResponse\x12B\n" +
-	"\vSendMessage\x12\x18.chat.SendMessageRequest\x1a\x19.chat.SendMessageResponse\x12B\n" +
-	"\vGetMessages\x12\x18.chat.GetMessagesRequest\x1a\x19.chat.GetMessagesResponse\x12B\n" +
+	"\tLeaveRoom\x12\x16.chat.LeaveRoomRequest\x1a\x17.chat.LeaveRoomResponse\x12?\n" +
+	"\n" +
+	"KickMember\x12\x17.chat.KickMemberRequest\x1a\x18.chat.KickMemberResponse\x12T\n" +
+	"\x11TransferOwnership\x12\x1e.chat.TransferOwnershipRequest\x1a\x1f.chat.TransferOwnershipResponse\x12?\n" +
+	"\n" +
+	"DeleteRoom\x12\x17.chat.DeleteRoomRequest\x1a\x18.chat.DeleteRoomResponse\x12?\n" +
+	"\n" +
+	"UpdateRoom\x12\x17.chat.UpdateRoomRequest\x1a\x18.chat.UpdateRoomResponse\x12B\n" +
+	"\vSetRoomMode\x12\x18.chat.SetRoomModeRequest\x1a\x19.chat.SetRoomModeResponse\x129\n" +
+	"\bMuteRoom\x12\x15.chat.MuteRoomRequest\x1a\x16.chat.MuteRoomResponse\x12?\n" +
+	"\n" +
+	"UnmuteRoom\x12\x17.chat.UnmuteRoomRequest\x1a\x18.chat.UnmuteRoomResponse\x12B\n" +
+	"\vArchiveRoom\x12\x18.chat.ArchiveRoomRequest\x1a\x19.chat.ArchiveRoomResponse\x12H\n" +
+	"\rUnarchiveRoom\x12\x1a.chat.UnarchiveRoomRequest\x1a\x1b.chat.UnarchiveRoomResponse\x126\n" +
+	"\aPinRoom\x12\x14.chat.PinRoomRequest\x1a\x15.chat.PinRoomResponse\x12<\n" +
+	"\tUnpinRoom\x12\x16.chat.UnpinRoomRequest\x1a\x17.chat.UnpinRoomResponse\x12H\n" +
+	"\rRotateRoomKey\x12\x1a.chat.RotateRoomKeyRequest\x1a\x1b.chat.RotateRoomKeyResponse\x12B\n" +
+	"\vGetKeyStats\x12\x18.chat.GetKeyStatsRequest\x1a\x19.chat.GetKeyStatsResponse\x12K\n" +
+	"\x0eGetRoomKeyInfo\x12\x1b.chat.GetRoomKeyInfoRequest\x1a\x1c.chat.GetRoomKeyInfoResponse\x12B\n" +
+	"\vGetRoomInfo\x12\x18.chat.GetRoomInfoRequest\x1a\x19.chat.GetRoomInfoResponse\x12Q\n" +
+	"\x10GetOnlineMembers\x12\x1d.chat.GetOnlineMembersRequest\x1a\x1e.chat.GetOnlineMembersResponse\x12N\n" +
+	"\x0fListRoomMembers\x12\x1c.chat.ListRoomMembersRequest\x1a\x1d.chat.ListRoomMembersResponse\x12H\n" +
+	"\rListUserRooms\x12\x1a.chat.ListUserRoomsRequest\x1a\x1b.chat.ListUserRoomsResponse\x12<\n" +
+	"\tListRooms\x12\x16.chat.ListRoomsRequest\x1a\x17.chat.ListRoomsResponse\x12Q\n" +
+	"\x10GetMessagesPaged\x12\x1d.chat.GetMessagesPagedRequest\x1a\x1e.chat.GetMessagesPagedResponse\x12B\n" +
+	"\vSendMessage\x12\x18.chat.SendMessageRequest\x1a\x19.chat.SendMessageResponse\x12K\n" +
+	"\x0eForwardMessage\x12\x1b.chat.ForwardMessageRequest\x1a\x1c.chat.ForwardMessageResponse\x12N\n" +
+	"\x0fScheduleMessage\x12\x1c.chat.ScheduleMessageRequest\x1a\x1d.chat.ScheduleMessageResponse\x12`\n" +
+	"\x15ListScheduledMessages\x12\".chat.ListScheduledMessagesRequest\x1a#.chat.ListScheduledMessagesResponse\x12c\n" +
+	"\x16CancelScheduledMessage\x12#.chat.CancelScheduledMessageRequest\x1a$.chat.CancelScheduledMessageResponse\x12B\n" +
+	"\vGetMessages\x12\x18.chat.GetMessagesRequest\x1a\x19.chat.GetMessagesResponse\x12T\n" +
+	"\x11GetMessagesAround\x12\x1e.chat.GetMessagesAroundRequest\x1a\x1f.chat.GetMessagesAroundResponse\x12?\n" +
+	"\n" +
+	"GetMessage\x12\x17.chat.GetMessageRequest\x1a\x18.chat.GetMessageResponse\x12<\n" +
+	"\tGetThread\x12\x16.chat.GetThreadRequest\x1a\x17.chat.GetThreadResponse\x12B\n" +
 	"\x0eStreamMessages\x12\x1b.chat.StreamMessagesRequest\x1a\x11.chat.ChatMessage0\x01\x12?\n" +
 	"\n" +
-	"MarkAsRead\x12\x17.chat.MarkAsReadRequest\x1a\x18.chat.MarkAsReadResponse\x12K\n" +
-	"\x0eGetUnreadCount\x12\x1b.chat.GetUnreadCountRequest\x1a\x1c.chat.GetUnreadCountResponseB\x19Z\x17chat-gateway/proto/chatb\x06proto3"
+	"MarkAsRead\x12\x17.chat.MarkAsReadRequest\x1a\x18.chat.MarkAsReadResponse\x12H\n" +
+	"\rMarkAllAsRead\x12\x1a.chat.MarkAllAsReadRequest\x1a\x1b.chat.MarkAllAsReadResponse\x12N\n" +
+	"\x0fMarkAsDelivered\x12\x1c.chat.MarkAsDeliveredRequest\x1a\x1d.chat.MarkAsDeliveredResponse\x12K\n" +
+	"\x0eGetUnreadCount\x12\x1b.chat.GetUnreadCountRequest\x1a\x1c.chat.GetUnreadCountResponse\x12Z\n" +
+	"\x13GetRoomMessageCount\x12 .chat.GetRoomMessageCountRequest\x1a!.chat.GetRoomMessageCountResponse\x12=\n" +
+	"\x04Chat\x12\x17.chat.ChatStreamRequest\x1a\x18.chat.ChatStreamResponse(\x010\x01\x12K\n" +
+	"\x0eDeleteUserData\x12\x1b.chat.DeleteUserDataRequest\x1a\x1c.chat.DeleteUserDataResponse\x12B\n" +
+	"\vImportRooms\x12\x18.chat.ImportRoomsRequest\x1a\x19.chat.ImportRoomsResponse\x12K\n" +
+	"\x0eImportMessages\x12\x1b.chat.ImportMessagesRequest\x1a\x1c.chat.ImportMessagesResponseB\x19Z\x17chat-gateway/proto/chatb\x06proto3"
 
 var (
 	file_proto_chat_proto_rawDescOnce sync.Once
@@ -1907,69 +7586,235 @@ func file_proto_chat_proto_rawDescGZIP() []byte {
 	return file_proto_chat_proto_rawDescData
 }
 
-var file_proto_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_proto_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 98)
 var file_proto_chat_proto_goTypes = []any{
-	(*ChatRoom)(nil),               // 0: chat.ChatRoom
-	(*RoomMember)(nil),             // 1: chat.RoomMember
-	(*RoomSettings)(nil),           // 2: chat.RoomSettings
-	(*ChatMessage)(nil),            // 3: chat.ChatMessage
-	(*MessageMetadata)(nil),        // 4: chat.MessageMetadata
-	(*CreateRoomRequest)(nil),      // 5: chat.CreateRoomRequest
-	(*CreateRoomResponse)(nil),     // 6: chat.CreateRoomResponse
-	(*JoinRoomRequest)(nil),        // 7: chat.JoinRoomRequest
-	(*JoinRoomResponse)(nil),       // 8: chat.JoinRoomResponse
-	(*LeaveRoomRequest)(nil),       // 9: chat.LeaveRoomRequest
-	(*LeaveRoomResponse)(nil),      // 10: chat.LeaveRoomResponse
-	(*GetRoomInfoRequest)(nil),     // 11: chat.GetRoomInfoRequest
-	(*GetRoomInfoResponse)(nil),    // 12: chat.GetRoomInfoResponse
-	(*ListUserRoomsRequest)(nil),   // 13: chat.ListUserRoomsRequest
-	(*ListUserRoomsResponse)(nil),  // 14: chat.ListUserRoomsResponse
-	(*SendMessageRequest)(nil),     // 15: chat.SendMessageRequest
-	(*SendMessageResponse)(nil),    // 16: chat.SendMessageResponse
-	(*GetMessagesRequest)(nil),     // 17: chat.GetMessagesRequest
-	(*GetMessagesResponse)(nil),    // 18: chat.GetMessagesResponse
-	(*StreamMessagesRequest)(nil),  // 19: chat.StreamMessagesRequest
-	(*MarkAsReadRequest)(nil),      // 20: chat.MarkAsReadRequest
-	(*MarkAsReadResponse)(nil),     // 21: chat.MarkAsReadResponse
-	(*GetUnreadCountRequest)(nil),  // 22: chat.GetUnreadCountRequest
-	(*GetUnreadCountResponse)(nil), // 23: chat.GetUnreadCountResponse
+	(*ChatRoom)(nil),                       // 0: chat.ChatRoom
+	(*RoomMember)(nil),                     // 1: chat.RoomMember
+	(*RoomSettings)(nil),                   // 2: chat.RoomSettings
+	(*ChatMessage)(nil),                    // 3: chat.ChatMessage
+	(*ReadReceipt)(nil),                    // 4: chat.ReadReceipt
+	(*ForwardedFrom)(nil),                  // 5: chat.ForwardedFrom
+	(*MessageMetadata)(nil),                // 6: chat.MessageMetadata
+	(*CreateRoomRequest)(nil),              // 7: chat.CreateRoomRequest
+	(*CreateRoomResponse)(nil),             // 8: chat.CreateRoomResponse
+	(*JoinRoomRequest)(nil),                // 9: chat.JoinRoomRequest
+	(*JoinRoomResponse)(nil),               // 10: chat.JoinRoomResponse
+	(*LeaveRoomRequest)(nil),               // 11: chat.LeaveRoomRequest
+	(*LeaveRoomResponse)(nil),              // 12: chat.LeaveRoomResponse
+	(*KickMemberRequest)(nil),              // 13: chat.KickMemberRequest
+	(*KickMemberResponse)(nil),             // 14: chat.KickMemberResponse
+	(*TransferOwnershipRequest)(nil),       // 15: chat.TransferOwnershipRequest
+	(*TransferOwnershipResponse)(nil),      // 16: chat.TransferOwnershipResponse
+	(*DeleteRoomRequest)(nil),              // 17: chat.DeleteRoomRequest
+	(*DeleteRoomResponse)(nil),             // 18: chat.DeleteRoomResponse
+	(*UpdateRoomRequest)(nil),              // 19: chat.UpdateRoomRequest
+	(*RoomSettingsUpdate)(nil),             // 20: chat.RoomSettingsUpdate
+	(*UpdateRoomResponse)(nil),             // 21: chat.UpdateRoomResponse
+	(*SetRoomModeRequest)(nil),             // 22: chat.SetRoomModeRequest
+	(*SetRoomModeResponse)(nil),            // 23: chat.SetRoomModeResponse
+	(*MuteRoomRequest)(nil),                // 24: chat.MuteRoomRequest
+	(*MuteRoomResponse)(nil),               // 25: chat.MuteRoomResponse
+	(*UnmuteRoomRequest)(nil),              // 26: chat.UnmuteRoomRequest
+	(*UnmuteRoomResponse)(nil),             // 27: chat.UnmuteRoomResponse
+	(*ArchiveRoomRequest)(nil),             // 28: chat.ArchiveRoomRequest
+	(*ArchiveRoomResponse)(nil),            // 29: chat.ArchiveRoomResponse
+	(*UnarchiveRoomRequest)(nil),           // 30: chat.UnarchiveRoomRequest
+	(*UnarchiveRoomResponse)(nil),          // 31: chat.UnarchiveRoomResponse
+	(*PinRoomRequest)(nil),                 // 32: chat.PinRoomRequest
+	(*PinRoomResponse)(nil),                // 33: chat.PinRoomResponse
+	(*UnpinRoomRequest)(nil),               // 34: chat.UnpinRoomRequest
+	(*UnpinRoomResponse)(nil),              // 35: chat.UnpinRoomResponse
+	(*RotateRoomKeyRequest)(nil),           // 36: chat.RotateRoomKeyRequest
+	(*RotateRoomKeyResponse)(nil),          // 37: chat.RotateRoomKeyResponse
+	(*GetKeyStatsRequest)(nil),             // 38: chat.GetKeyStatsRequest
+	(*GetKeyStatsResponse)(nil),            // 39: chat.GetKeyStatsResponse
+	(*GetRoomKeyInfoRequest)(nil),          // 40: chat.GetRoomKeyInfoRequest
+	(*GetRoomKeyInfoResponse)(nil),         // 41: chat.GetRoomKeyInfoResponse
+	(*GetRoomInfoRequest)(nil),             // 42: chat.GetRoomInfoRequest
+	(*GetRoomInfoResponse)(nil),            // 43: chat.GetRoomInfoResponse
+	(*GetOnlineMembersRequest)(nil),        // 44: chat.GetOnlineMembersRequest
+	(*GetOnlineMembersResponse)(nil),       // 45: chat.GetOnlineMembersResponse
+	(*ListRoomMembersRequest)(nil),         // 46: chat.ListRoomMembersRequest
+	(*ListRoomMembersResponse)(nil),        // 47: chat.ListRoomMembersResponse
+	(*ListUserRoomsRequest)(nil),           // 48: chat.ListUserRoomsRequest
+	(*ListUserRoomsResponse)(nil),          // 49: chat.ListUserRoomsResponse
+	(*ListRoomsRequest)(nil),               // 50: chat.ListRoomsRequest
+	(*ListRoomsResponse)(nil),              // 51: chat.ListRoomsResponse
+	(*SendMessageRequest)(nil),             // 52: chat.SendMessageRequest
+	(*SendMessageResponse)(nil),            // 53: chat.SendMessageResponse
+	(*ScheduleMessageRequest)(nil),         // 54: chat.ScheduleMessageRequest
+	(*ScheduledMessage)(nil),               // 55: chat.ScheduledMessage
+	(*ScheduleMessageResponse)(nil),        // 56: chat.ScheduleMessageResponse
+	(*ListScheduledMessagesRequest)(nil),   // 57: chat.ListScheduledMessagesRequest
+	(*ListScheduledMessagesResponse)(nil),  // 58: chat.ListScheduledMessagesResponse
+	(*CancelScheduledMessageRequest)(nil),  // 59: chat.CancelScheduledMessageRequest
+	(*CancelScheduledMessageResponse)(nil), // 60: chat.CancelScheduledMessageResponse
+	(*ForwardMessageRequest)(nil),          // 61: chat.ForwardMessageRequest
+	(*ForwardMessageResponse)(nil),         // 62: chat.ForwardMessageResponse
+	(*GetMessagesRequest)(nil),             // 63: chat.GetMessagesRequest
+	(*GetMessagesResponse)(nil),            // 64: chat.GetMessagesResponse
+	(*GetMessagesPagedRequest)(nil),        // 65: chat.GetMessagesPagedRequest
+	(*GetMessagesPagedResponse)(nil),       // 66: chat.GetMessagesPagedResponse
+	(*GetMessagesAroundRequest)(nil),       // 67: chat.GetMessagesAroundRequest
+	(*GetMessagesAroundResponse)(nil),      // 68: chat.GetMessagesAroundResponse
+	(*GetMessageRequest)(nil),              // 69: chat.GetMessageRequest
+	(*GetMessageResponse)(nil),             // 70: chat.GetMessageResponse
+	(*GetThreadRequest)(nil),               // 71: chat.GetThreadRequest
+	(*GetThreadResponse)(nil),              // 72: chat.GetThreadResponse
+	(*StreamMessagesRequest)(nil),          // 73: chat.StreamMessagesRequest
+	(*MarkAsReadRequest)(nil),              // 74: chat.MarkAsReadRequest
+	(*MarkAsReadResponse)(nil),             // 75: chat.MarkAsReadResponse
+	(*MarkAllAsReadRequest)(nil),           // 76: chat.MarkAllAsReadRequest
+	(*MarkAllAsReadResponse)(nil),          // 77: chat.MarkAllAsReadResponse
+	(*DeleteUserDataRequest)(nil),          // 78: chat.DeleteUserDataRequest
+	(*DeleteUserDataResponse)(nil),         // 79: chat.DeleteUserDataResponse
+	(*MarkAsDeliveredRequest)(nil),         // 80: chat.MarkAsDeliveredRequest
+	(*MarkAsDeliveredResponse)(nil),        // 81: chat.MarkAsDeliveredResponse
+	(*GetUnreadCountRequest)(nil),          // 82: chat.GetUnreadCountRequest
+	(*GetUnreadCountResponse)(nil),         // 83: chat.GetUnreadCountResponse
+	(*GetRoomMessageCountRequest)(nil),     // 84: chat.GetRoomMessageCountRequest
+	(*GetRoomMessageCountResponse)(nil),    // 85: chat.GetRoomMessageCountResponse
+	(*ChatStreamRequest)(nil),              // 86: chat.ChatStreamRequest
+	(*ChatSubscribe)(nil),                  // 87: chat.ChatSubscribe
+	(*ChatSend)(nil),                       // 88: chat.ChatSend
+	(*ChatStreamResponse)(nil),             // 89: chat.ChatStreamResponse
+	(*MessageAck)(nil),                     // 90: chat.MessageAck
+	(*ImportRoom)(nil),                     // 91: chat.ImportRoom
+	(*ImportRoomsRequest)(nil),             // 92: chat.ImportRoomsRequest
+	(*ImportResult)(nil),                   // 93: chat.ImportResult
+	(*ImportRoomsResponse)(nil),            // 94: chat.ImportRoomsResponse
+	(*ImportMessage)(nil),                  // 95: chat.ImportMessage
+	(*ImportMessagesRequest)(nil),          // 96: chat.ImportMessagesRequest
+	(*ImportMessagesResponse)(nil),         // 97: chat.ImportMessagesResponse
 }
 var file_proto_chat_proto_depIdxs = []int32{
 	1,  // 0: chat.ChatRoom.members:type_name -> chat.RoomMember
 	2,  // 1: chat.ChatRoom.settings:type_name -> chat.RoomSettings
-	4,  // 2: chat.ChatMessage.metadata:type_name -> chat.MessageMetadata
-	2,  // 3: chat.CreateRoomRequest.settings:type_name -> chat.RoomSettings
-	0,  // 4: chat.CreateRoomResponse.room:type_name -> chat.ChatRoom
-	0,  // 5: chat.GetRoomInfoResponse.room:type_name -> chat.ChatRoom
-	0,  // 6: chat.ListUserRoomsResponse.rooms:type_name -> chat.ChatRoom
-	4,  // 7: chat.SendMessageRequest.metadata:type_name -> chat.MessageMetadata
-	3,  // 8: chat.SendMessageResponse.chat_message:type_name -> chat.ChatMessage
-	3,  // 9: chat.GetMessagesResponse.messages:type_name -> chat.ChatMessage
-	5,  // 10: chat.ChatRoomService.CreateRoom:input_type -> chat.CreateRoomRequest
-	7,  // 11: chat.ChatRoomService.JoinRoom:input_type -> chat.JoinRoomRequest
-	9,  // 12: chat.ChatRoomService.LeaveRoom:input_type -> chat.LeaveRoomRequest
-	11, // 13: chat.ChatRoomService.GetRoomInfo:input_type -> chat.GetRoomInfoRequest
-	13, // 14: chat.ChatRoomService.ListUserRooms:input_type -> chat.ListUserRoomsRequest
-	15, // 15: chat.ChatRoomService.SendMessage:input_type -> chat.SendMessageRequest
-	17, // 16: chat.ChatRoomService.GetMessages:input_type -> chat.GetMessagesRequest
-	19, // 17: chat.ChatRoomService.StreamMessages:input_type -> chat.StreamMessagesRequest
-	20, // 18: chat.ChatRoomService.MarkAsRead:input_type -> chat.MarkAsReadRequest
-	22, // 19: chat.ChatRoomService.GetUnreadCount:input_type -> chat.GetUnreadCountRequest
-	6,  // 20: chat.ChatRoomService.CreateRoom:output_type -> chat.CreateRoomResponse
-	8,  // 21: chat.ChatRoomService.JoinRoom:output_type -> chat.JoinRoomResponse
-	10, // 22: chat.ChatRoomService.LeaveRoom:output_type -> chat.LeaveRoomResponse
-	12, // 23: chat.ChatRoomService.GetRoomInfo:output_type -> chat.GetRoomInfoResponse
-	14, // 24: chat.ChatRoomService.ListUserRooms:output_type -> chat.ListUserRoomsResponse
-	16, // 25: chat.ChatRoomService.SendMessage:output_type -> chat.SendMessageResponse
-	18, // 26: chat.ChatRoomService.GetMessages:output_type -> chat.GetMessagesResponse
-	3,  // 27: chat.ChatRoomService.StreamMessages:output_type -> chat.ChatMessage
-	21, // 28: chat.ChatRoomService.MarkAsRead:output_type -> chat.MarkAsReadResponse
-	23, // 29: chat.ChatRoomService.GetUnreadCount:output_type -> chat.GetUnreadCountResponse
-	20, // [20:30] is the sub-list for method output_type
-	10, // [10:20] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	6,  // 2: chat.ChatMessage.metadata:type_name -> chat.MessageMetadata
+	5,  // 3: chat.ChatMessage.forwarded_from:type_name -> chat.ForwardedFrom
+	4,  // 4: chat.ChatMessage.read_receipts:type_name -> chat.ReadReceipt
+	2,  // 5: chat.CreateRoomRequest.settings:type_name -> chat.RoomSettings
+	0,  // 6: chat.CreateRoomResponse.room:type_name -> chat.ChatRoom
+	20, // 7: chat.UpdateRoomRequest.settings:type_name -> chat.RoomSettingsUpdate
+	0,  // 8: chat.UpdateRoomResponse.room:type_name -> chat.ChatRoom
+	0,  // 9: chat.GetRoomInfoResponse.room:type_name -> chat.ChatRoom
+	1,  // 10: chat.GetOnlineMembersResponse.members:type_name -> chat.RoomMember
+	1,  // 11: chat.ListRoomMembersResponse.members:type_name -> chat.RoomMember
+	0,  // 12: chat.ListUserRoomsResponse.rooms:type_name -> chat.ChatRoom
+	0,  // 13: chat.ListRoomsResponse.rooms:type_name -> chat.ChatRoom
+	6,  // 14: chat.SendMessageRequest.metadata:type_name -> chat.MessageMetadata
+	3,  // 15: chat.SendMessageResponse.chat_message:type_name -> chat.ChatMessage
+	52, // 16: chat.ScheduleMessageRequest.message:type_name -> chat.SendMessageRequest
+	6,  // 17: chat.ScheduledMessage.metadata:type_name -> chat.MessageMetadata
+	55, // 18: chat.ScheduleMessageResponse.scheduled_message:type_name -> chat.ScheduledMessage
+	55, // 19: chat.ListScheduledMessagesResponse.scheduled_messages:type_name -> chat.ScheduledMessage
+	3,  // 20: chat.ForwardMessageResponse.chat_message:type_name -> chat.ChatMessage
+	3,  // 21: chat.GetMessagesResponse.messages:type_name -> chat.ChatMessage
+	3,  // 22: chat.GetMessagesPagedResponse.messages:type_name -> chat.ChatMessage
+	3,  // 23: chat.GetMessagesAroundResponse.messages:type_name -> chat.ChatMessage
+	3,  // 24: chat.GetMessageResponse.chat_message:type_name -> chat.ChatMessage
+	3,  // 25: chat.GetThreadResponse.root:type_name -> chat.ChatMessage
+	3,  // 26: chat.GetThreadResponse.messages:type_name -> chat.ChatMessage
+	87, // 27: chat.ChatStreamRequest.subscribe:type_name -> chat.ChatSubscribe
+	88, // 28: chat.ChatStreamRequest.send:type_name -> chat.ChatSend
+	52, // 29: chat.ChatSend.message:type_name -> chat.SendMessageRequest
+	90, // 30: chat.ChatStreamResponse.ack:type_name -> chat.MessageAck
+	3,  // 31: chat.ChatStreamResponse.message:type_name -> chat.ChatMessage
+	2,  // 32: chat.ImportRoom.settings:type_name -> chat.RoomSettings
+	91, // 33: chat.ImportRoomsRequest.rooms:type_name -> chat.ImportRoom
+	93, // 34: chat.ImportRoomsResponse.results:type_name -> chat.ImportResult
+	6,  // 35: chat.ImportMessage.metadata:type_name -> chat.MessageMetadata
+	95, // 36: chat.ImportMessagesRequest.messages:type_name -> chat.ImportMessage
+	93, // 37: chat.ImportMessagesResponse.results:type_name -> chat.ImportResult
+	7,  // 38: chat.ChatRoomService.CreateRoom:input_type -> chat.CreateRoomRequest
+	9,  // 39: chat.ChatRoomService.JoinRoom:input_type -> chat.JoinRoomRequest
+	11, // 40: chat.ChatRoomService.LeaveRoom:input_type -> chat.LeaveRoomRequest
+	13, // 41: chat.ChatRoomService.KickMember:input_type -> chat.KickMemberRequest
+	15, // 42: chat.ChatRoomService.TransferOwnership:input_type -> chat.TransferOwnershipRequest
+	17, // 43: chat.ChatRoomService.DeleteRoom:input_type -> chat.DeleteRoomRequest
+	19, // 44: chat.ChatRoomService.UpdateRoom:input_type -> chat.UpdateRoomRequest
+	22, // 45: chat.ChatRoomService.SetRoomMode:input_type -> chat.SetRoomModeRequest
+	24, // 46: chat.ChatRoomService.MuteRoom:input_type -> chat.MuteRoomRequest
+	26, // 47: chat.ChatRoomService.UnmuteRoom:input_type -> chat.UnmuteRoomRequest
+	28, // 48: chat.ChatRoomService.ArchiveRoom:input_type -> chat.ArchiveRoomRequest
+	30, // 49: chat.ChatRoomService.UnarchiveRoom:input_type -> chat.UnarchiveRoomRequest
+	32, // 50: chat.ChatRoomService.PinRoom:input_type -> chat.PinRoomRequest
+	34, // 51: chat.ChatRoomService.UnpinRoom:input_type -> chat.UnpinRoomRequest
+	36, // 52: chat.ChatRoomService.RotateRoomKey:input_type -> chat.RotateRoomKeyRequest
+	38, // 53: chat.ChatRoomService.GetKeyStats:input_type -> chat.GetKeyStatsRequest
+	40, // 54: chat.ChatRoomService.GetRoomKeyInfo:input_type -> chat.GetRoomKeyInfoRequest
+	42, // 55: chat.ChatRoomService.GetRoomInfo:input_type -> chat.GetRoomInfoRequest
+	44, // 56: chat.ChatRoomService.GetOnlineMembers:input_type -> chat.GetOnlineMembersRequest
+	46, // 57: chat.ChatRoomService.ListRoomMembers:input_type -> chat.ListRoomMembersRequest
+	48, // 58: chat.ChatRoomService.ListUserRooms:input_type -> chat.ListUserRoomsRequest
+	50, // 59: chat.ChatRoomService.ListRooms:input_type -> chat.ListRoomsRequest
+	65, // 60: chat.ChatRoomService.GetMessagesPaged:input_type -> chat.GetMessagesPagedRequest
+	52, // 61: chat.ChatRoomService.SendMessage:input_type -> chat.SendMessageRequest
+	61, // 62: chat.ChatRoomService.ForwardMessage:input_type -> chat.ForwardMessageRequest
+	54, // 63: chat.ChatRoomService.ScheduleMessage:input_type -> chat.ScheduleMessageRequest
+	57, // 64: chat.ChatRoomService.ListScheduledMessages:input_type -> chat.ListScheduledMessagesRequest
+	59, // 65: chat.ChatRoomService.CancelScheduledMessage:input_type -> chat.CancelScheduledMessageRequest
+	63, // 66: chat.ChatRoomService.GetMessages:input_type -> chat.GetMessagesRequest
+	67, // 67: chat.ChatRoomService.GetMessagesAround:input_type -> chat.GetMessagesAroundRequest
+	69, // 68: chat.ChatRoomService.GetMessage:input_type -> chat.GetMessageRequest
+	71, // 69: chat.ChatRoomService.GetThread:input_type -> chat.GetThreadRequest
+	73, // 70: chat.ChatRoomService.StreamMessages:input_type -> chat.StreamMessagesRequest
+	74, // 71: chat.ChatRoomService.MarkAsRead:input_type -> chat.MarkAsReadRequest
+	76, // 72: chat.ChatRoomService.MarkAllAsRead:input_type -> chat.MarkAllAsReadRequest
+	80, // 73: chat.ChatRoomService.MarkAsDelivered:input_type -> chat.MarkAsDeliveredRequest
+	82, // 74: chat.ChatRoomService.GetUnreadCount:input_type -> chat.GetUnreadCountRequest
+	84, // 75: chat.ChatRoomService.GetRoomMessageCount:input_type -> chat.GetRoomMessageCountRequest
+	86, // 76: chat.ChatRoomService.Chat:input_type -> chat.ChatStreamRequest
+	78, // 77: chat.ChatRoomService.DeleteUserData:input_type -> chat.DeleteUserDataRequest
+	92, // 78: chat.ChatRoomService.ImportRooms:input_type -> chat.ImportRoomsRequest
+	96, // 79: chat.ChatRoomService.ImportMessages:input_type -> chat.ImportMessagesRequest
+	8,  // 80: chat.ChatRoomService.CreateRoom:output_type -> chat.CreateRoomResponse
+	10, // 81: chat.ChatRoomService.JoinRoom:output_type -> chat.JoinRoomResponse
+	12, // 82: chat.ChatRoomService.LeaveRoom:output_type -> chat.LeaveRoomResponse
+	14, // 83: chat.ChatRoomService.KickMember:output_type -> chat.KickMemberResponse
+	16, // 84: chat.ChatRoomService.TransferOwnership:output_type -> chat.TransferOwnershipResponse
+	18, // 85: chat.ChatRoomService.DeleteRoom:output_type -> chat.DeleteRoomResponse
+	21, // 86: chat.ChatRoomService.UpdateRoom:output_type -> chat.UpdateRoomResponse
+	23, // 87: chat.ChatRoomService.SetRoomMode:output_type -> chat.SetRoomModeResponse
+	25, // 88: chat.ChatRoomService.MuteRoom:output_type -> chat.MuteRoomResponse
+	27, // 89: chat.ChatRoomService.UnmuteRoom:output_type -> chat.UnmuteRoomResponse
+	29, // 90: chat.ChatRoomService.ArchiveRoom:output_type -> chat.ArchiveRoomResponse
+	31, // 91: chat.ChatRoomService.UnarchiveRoom:output_type -> chat.UnarchiveRoomResponse
+	33, // 92: chat.ChatRoomService.PinRoom:output_type -> chat.PinRoomResponse
+	35, // 93: chat.ChatRoomService.UnpinRoom:output_type -> chat.UnpinRoomResponse
+	37, // 94: chat.ChatRoomService.RotateRoomKey:output_type -> chat.RotateRoomKeyResponse
+	39, // 95: chat.ChatRoomService.GetKeyStats:output_type -> chat.GetKeyStatsResponse
+	41, // 96: chat.ChatRoomService.GetRoomKeyInfo:output_type -> chat.GetRoomKeyInfoResponse
+	43, // 97: chat.ChatRoomService.GetRoomInfo:output_type -> chat.GetRoomInfoResponse
+	45, // 98: chat.ChatRoomService.GetOnlineMembers:output_type -> chat.GetOnlineMembersResponse
+	47, // 99: chat.ChatRoomService.ListRoomMembers:output_type -> chat.ListRoomMembersResponse
+	49, // 100: chat.ChatRoomService.ListUserRooms:output_type -> chat.ListUserRoomsResponse
+	51, // 101: chat.ChatRoomService.ListRooms:output_type -> chat.ListRoomsResponse
+	66, // 102: chat.ChatRoomService.GetMessagesPaged:output_type -> chat.GetMessagesPagedResponse
+	53, // 103: chat.ChatRoomService.SendMessage:output_type -> chat.SendMessageResponse
+	62, // 104: chat.ChatRoomService.ForwardMessage:output_type -> chat.ForwardMessageResponse
+	56, // 105: chat.ChatRoomService.ScheduleMessage:output_type -> chat.ScheduleMessageResponse
+	58, // 106: chat.ChatRoomService.ListScheduledMessages:output_type -> chat.ListScheduledMessagesResponse
+	60, // 107: chat.ChatRoomService.CancelScheduledMessage:output_type -> chat.CancelScheduledMessageResponse
+	64, // 108: chat.ChatRoomService.GetMessages:output_type -> chat.GetMessagesResponse
+	68, // 109: chat.ChatRoomService.GetMessagesAround:output_type -> chat.GetMessagesAroundResponse
+	70, // 110: chat.ChatRoomService.GetMessage:output_type -> chat.GetMessageResponse
+	72, // 111: chat.ChatRoomService.GetThread:output_type -> chat.GetThreadResponse
+	3,  // 112: chat.ChatRoomService.StreamMessages:output_type -> chat.ChatMessage
+	75, // 113: chat.ChatRoomService.MarkAsRead:output_type -> chat.MarkAsReadResponse
+	77, // 114: chat.ChatRoomService.MarkAllAsRead:output_type -> chat.MarkAllAsReadResponse
+	81, // 115: chat.ChatRoomService.MarkAsDelivered:output_type -> chat.MarkAsDeliveredResponse
+	83, // 116: chat.ChatRoomService.GetUnreadCount:output_type -> chat.GetUnreadCountResponse
+	85, // 117: chat.ChatRoomService.GetRoomMessageCount:output_type -> chat.GetRoomMessageCountResponse
+	89, // 118: chat.ChatRoomService.Chat:output_type -> chat.ChatStreamResponse
+	79, // 119: chat.ChatRoomService.DeleteUserData:output_type -> chat.DeleteUserDataResponse
+	94, // 120: chat.ChatRoomService.ImportRooms:output_type -> chat.ImportRoomsResponse
+	97, // 121: chat.ChatRoomService.ImportMessages:output_type -> chat.ImportMessagesResponse
+	80, // [80:122] is the sub-list for method output_type
+	38, // [38:80] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_proto_chat_proto_init() }
@@ -1977,13 +7822,23 @@ func file_proto_chat_proto_init() {
 	if File_proto_chat_proto != nil {
 		return
 	}
+	file_proto_chat_proto_msgTypes[19].OneofWrappers = []any{}
+	file_proto_chat_proto_msgTypes[20].OneofWrappers = []any{}
+	file_proto_chat_proto_msgTypes[86].OneofWrappers = []any{
+		(*ChatStreamRequest_Subscribe)(nil),
+		(*ChatStreamRequest_Send)(nil),
+	}
+	file_proto_chat_proto_msgTypes[89].OneofWrappers = []any{
+		(*ChatStreamResponse_Ack)(nil),
+		(*ChatStreamResponse_Message)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_chat_proto_rawDesc), len(file_proto_chat_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   24,
+			NumMessages:   98,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,16 +19,48 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ChatRoomService_CreateRoom_FullMethodName     = "/chat.ChatRoomService/CreateRoom"
-	ChatRoomService_JoinRoom_FullMethodName       = "/chat.ChatRoomService/JoinRoom"
-	ChatRoomService_LeaveRoom_FullMethodName      = "/chat.ChatRoomService/LeaveRoom"
-	ChatRoomService_GetRoomInfo_FullMethodName    = "/chat.ChatRoomService/GetRoomInfo"
-	ChatRoomService_ListUserRooms_FullMethodName  = "/chat.ChatRoomService/ListUserRooms"
-	ChatRoomService_SendMessage_FullMethodName    = "/chat.ChatRoomService/SendMessage"
-	ChatRoomService_GetMessages_FullMethodName    = "/chat.ChatRoomService/GetMessages"
-	ChatRoomService_StreamMessages_FullMethodName = "/chat.ChatRoomService/StreamMessages"
-	ChatRoomService_MarkAsRead_FullMethodName     = "/chat.ChatRoomService/MarkAsRead"
-	ChatRoomService_GetUnreadCount_FullMethodName = "/chat.ChatRoomService/GetUnreadCount"
+	ChatRoomService_CreateRoom_FullMethodName             = "/chat.ChatRoomService/CreateRoom"
+	ChatRoomService_JoinRoom_FullMethodName               = "/chat.ChatRoomService/JoinRoom"
+	ChatRoomService_LeaveRoom_FullMethodName              = "/chat.ChatRoomService/LeaveRoom"
+	ChatRoomService_KickMember_FullMethodName             = "/chat.ChatRoomService/KickMember"
+	ChatRoomService_TransferOwnership_FullMethodName      = "/chat.ChatRoomService/TransferOwnership"
+	ChatRoomService_DeleteRoom_FullMethodName             = "/chat.ChatRoomService/DeleteRoom"
+	ChatRoomService_UpdateRoom_FullMethodName             = "/chat.ChatRoomService/UpdateRoom"
+	ChatRoomService_SetRoomMode_FullMethodName            = "/chat.ChatRoomService/SetRoomMode"
+	ChatRoomService_MuteRoom_FullMethodName               = "/chat.ChatRoomService/MuteRoom"
+	ChatRoomService_UnmuteRoom_FullMethodName             = "/chat.ChatRoomService/UnmuteRoom"
+	ChatRoomService_ArchiveRoom_FullMethodName            = "/chat.ChatRoomService/ArchiveRoom"
+	ChatRoomService_UnarchiveRoom_FullMethodName          = "/chat.ChatRoomService/UnarchiveRoom"
+	ChatRoomService_PinRoom_FullMethodName                = "/chat.ChatRoomService/PinRoom"
+	ChatRoomService_UnpinRoom_FullMethodName              = "/chat.ChatRoomService/UnpinRoom"
+	ChatRoomService_RotateRoomKey_FullMethodName          = "/chat.ChatRoomService/RotateRoomKey"
+	ChatRoomService_GetKeyStats_FullMethodName            = "/chat.ChatRoomService/GetKeyStats"
+	ChatRoomService_GetRoomKeyInfo_FullMethodName         = "/chat.ChatRoomService/GetRoomKeyInfo"
+	ChatRoomService_GetRoomInfo_FullMethodName            = "/chat.ChatRoomService/GetRoomInfo"
+	ChatRoomService_GetOnlineMembers_FullMethodName       = "/chat.ChatRoomService/GetOnlineMembers"
+	ChatRoomService_ListRoomMembers_FullMethodName        = "/chat.ChatRoomService/ListRoomMembers"
+	ChatRoomService_ListUserRooms_FullMethodName          = "/chat.ChatRoomService/ListUserRooms"
+	ChatRoomService_ListRooms_FullMethodName              = "/chat.ChatRoomService/ListRooms"
+	ChatRoomService_GetMessagesPaged_FullMethodName       = "/chat.ChatRoomService/GetMessagesPaged"
+	ChatRoomService_SendMessage_FullMethodName            = "/chat.ChatRoomService/SendMessage"
+	ChatRoomService_ForwardMessage_FullMethodName         = "/chat.ChatRoomService/ForwardMessage"
+	ChatRoomService_ScheduleMessage_FullMethodName        = "/chat.ChatRoomService/ScheduleMessage"
+	ChatRoomService_ListScheduledMessages_FullMethodName  = "/chat.ChatRoomService/ListScheduledMessages"
+	ChatRoomService_CancelScheduledMessage_FullMethodName = "/chat.ChatRoomService/CancelScheduledMessage"
+	ChatRoomService_GetMessages_FullMethodName            = "/chat.ChatRoomService/GetMessages"
+	ChatRoomService_GetMessagesAround_FullMethodName      = "/chat.ChatRoomService/GetMessagesAround"
+	ChatRoomService_GetMessage_FullMethodName             = "/chat.ChatRoomService/GetMessage"
+	ChatRoomService_GetThread_FullMethodName              = "/chat.ChatRoomService/GetThread"
+	ChatRoomService_StreamMessages_FullMethodName         = "/chat.ChatRoomService/StreamMessages"
+	ChatRoomService_MarkAsRead_FullMethodName             = "/chat.ChatRoomService/MarkAsRead"
+	ChatRoomService_MarkAllAsRead_FullMethodName          = "/chat.ChatRoomService/MarkAllAsRead"
+	ChatRoomService_MarkAsDelivered_FullMethodName        = "/chat.ChatRoomService/MarkAsDelivered"
+	ChatRoomService_GetUnreadCount_FullMethodName         = "/chat.ChatRoomService/GetUnreadCount"
+	ChatRoomService_GetRoomMessageCount_FullMethodName    = "/chat.ChatRoomService/GetRoomMessageCount"
+	ChatRoomService_Chat_FullMethodName                   = "/chat.ChatRoomService/Chat"
+	ChatRoomService_DeleteUserData_FullMethodName         = "/chat.ChatRoomService/DeleteUserData"
+	ChatRoomService_ImportRooms_FullMethodName            = "/chat.ChatRoomService/ImportRooms"
+	ChatRoomService_ImportMessages_FullMethodName         = "/chat.ChatRoomService/ImportMessages"
 )
 
 // ChatRoomServiceClient is the client API for ChatRoomService service.
@@ -41,22 +73,79 @@ type ChatRoomServiceClient interface {
 	CreateRoom(ctx context.Context, in *CreateRoomRequest, opts ...grpc.CallOption) (*CreateRoomResponse, error)
 	// 加入聊天室
 	JoinRoom(ctx context.Context, in *JoinRoomRequest, opts ...grpc.CallOption) (*JoinRoomResponse, error)
-	// 離開聊天室
+	// 離開聊天室（只能自己離開）
 	LeaveRoom(ctx context.Context, in *LeaveRoomRequest, opts ...grpc.CallOption) (*LeaveRoomResponse, error)
+	// 將成員移出聊天室（僅限擁有者或管理員，不能移出擁有者）
+	KickMember(ctx context.Context, in *KickMemberRequest, opts ...grpc.CallOption) (*KickMemberResponse, error)
+	// 轉讓聊天室擁有權（僅限當前擁有者，新擁有者必須是成員）
+	TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, opts ...grpc.CallOption) (*TransferOwnershipResponse, error)
+	// 刪除聊天室（僅限擁有者，同時刪除訊息與加密密鑰）
+	DeleteRoom(ctx context.Context, in *DeleteRoomRequest, opts ...grpc.CallOption) (*DeleteRoomResponse, error)
+	// 更新聊天室（名稱、頭像、設置）
+	UpdateRoom(ctx context.Context, in *UpdateRoomRequest, opts ...grpc.CallOption) (*UpdateRoomResponse, error)
+	// 設置聊天室模式（普通、慢速、僅公告、只讀）
+	SetRoomMode(ctx context.Context, in *SetRoomModeRequest, opts ...grpc.CallOption) (*SetRoomModeResponse, error)
+	// 靜音 / 取消靜音聊天室（只影響操作者自己）
+	MuteRoom(ctx context.Context, in *MuteRoomRequest, opts ...grpc.CallOption) (*MuteRoomResponse, error)
+	UnmuteRoom(ctx context.Context, in *UnmuteRoomRequest, opts ...grpc.CallOption) (*UnmuteRoomResponse, error)
+	// 封存 / 取消封存聊天室（只影響操作者自己，收到新訊息時自動取消封存，封存時取消置頂）
+	ArchiveRoom(ctx context.Context, in *ArchiveRoomRequest, opts ...grpc.CallOption) (*ArchiveRoomResponse, error)
+	UnarchiveRoom(ctx context.Context, in *UnarchiveRoomRequest, opts ...grpc.CallOption) (*UnarchiveRoomResponse, error)
+	// 置頂 / 取消置頂聊天室（只影響操作者自己，置頂的聊天室在列表中排在最前，與封存互斥）
+	PinRoom(ctx context.Context, in *PinRoomRequest, opts ...grpc.CallOption) (*PinRoomResponse, error)
+	UnpinRoom(ctx context.Context, in *UnpinRoomRequest, opts ...grpc.CallOption) (*UnpinRoomResponse, error)
+	// 手動輪換聊天室加密密鑰（僅限擁有者或管理員）
+	RotateRoomKey(ctx context.Context, in *RotateRoomKeyRequest, opts ...grpc.CallOption) (*RotateRoomKeyResponse, error)
+	// 密鑰管理器統計與聊天室密鑰狀態（管理端點，不返回密鑰值）
+	GetKeyStats(ctx context.Context, in *GetKeyStatsRequest, opts ...grpc.CallOption) (*GetKeyStatsResponse, error)
+	GetRoomKeyInfo(ctx context.Context, in *GetRoomKeyInfoRequest, opts ...grpc.CallOption) (*GetRoomKeyInfoResponse, error)
 	// 獲取聊天室信息
 	GetRoomInfo(ctx context.Context, in *GetRoomInfoRequest, opts ...grpc.CallOption) (*GetRoomInfoResponse, error)
+	// 獲取最近在線的聊天室成員
+	GetOnlineMembers(ctx context.Context, in *GetOnlineMembersRequest, opts ...grpc.CallOption) (*GetOnlineMembersResponse, error)
+	// 分頁列出聊天室成員（支持按角色和用戶名前綴過濾）
+	ListRoomMembers(ctx context.Context, in *ListRoomMembersRequest, opts ...grpc.CallOption) (*ListRoomMembersResponse, error)
 	// 列出用戶的聊天室
 	ListUserRooms(ctx context.Context, in *ListUserRoomsRequest, opts ...grpc.CallOption) (*ListUserRoomsResponse, error)
+	// 管理員列出和搜索所有聊天室（需要啟用 JWT 認證，且調用者在 admin_user_ids 中）
+	ListRooms(ctx context.Context, in *ListRoomsRequest, opts ...grpc.CallOption) (*ListRoomsResponse, error)
+	// 管理員按頁碼（skip/limit）瀏覽聊天室訊息，返回總數；只用於管理工具，客戶端請使用 GetMessages 的游標分頁
+	GetMessagesPaged(ctx context.Context, in *GetMessagesPagedRequest, opts ...grpc.CallOption) (*GetMessagesPagedResponse, error)
 	// 發送消息
 	SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse, error)
+	// 轉發消息到另一個聊天室（需要是來源和目標聊天室的成員）
+	ForwardMessage(ctx context.Context, in *ForwardMessageRequest, opts ...grpc.CallOption) (*ForwardMessageResponse, error)
+	// 定時訊息：到達發送時間後由後台任務發送
+	ScheduleMessage(ctx context.Context, in *ScheduleMessageRequest, opts ...grpc.CallOption) (*ScheduleMessageResponse, error)
+	ListScheduledMessages(ctx context.Context, in *ListScheduledMessagesRequest, opts ...grpc.CallOption) (*ListScheduledMessagesResponse, error)
+	CancelScheduledMessage(ctx context.Context, in *CancelScheduledMessageRequest, opts ...grpc.CallOption) (*CancelScheduledMessageResponse, error)
 	// 獲取消息
 	GetMessages(ctx context.Context, in *GetMessagesRequest, opts ...grpc.CallOption) (*GetMessagesResponse, error)
+	// 獲取指定訊息或游標前後的訊息（支持雙向翻頁）
+	GetMessagesAround(ctx context.Context, in *GetMessagesAroundRequest, opts ...grpc.CallOption) (*GetMessagesAroundResponse, error)
+	// 獲取單條訊息（深層連結、刷新被編輯的訊息）
+	GetMessage(ctx context.Context, in *GetMessageRequest, opts ...grpc.CallOption) (*GetMessageResponse, error)
+	// 獲取討論串（回覆鏈最終指向根訊息的所有回覆，按時間正序分頁）
+	GetThread(ctx context.Context, in *GetThreadRequest, opts ...grpc.CallOption) (*GetThreadResponse, error)
 	// 流式獲取消息
 	StreamMessages(ctx context.Context, in *StreamMessagesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatMessage], error)
 	// 標記為已讀
 	MarkAsRead(ctx context.Context, in *MarkAsReadRequest, opts ...grpc.CallOption) (*MarkAsReadResponse, error)
+	// 標記用戶所有聊天室的訊息為已讀
+	MarkAllAsRead(ctx context.Context, in *MarkAllAsReadRequest, opts ...grpc.CallOption) (*MarkAllAsReadResponse, error)
+	// 標記為已送達
+	MarkAsDelivered(ctx context.Context, in *MarkAsDeliveredRequest, opts ...grpc.CallOption) (*MarkAsDeliveredResponse, error)
 	// 獲取未讀數量
 	GetUnreadCount(ctx context.Context, in *GetUnreadCountRequest, opts ...grpc.CallOption) (*GetUnreadCountResponse, error)
+	// 獲取聊天室訊息總數（只計數，不返回訊息；成員或管理員可以查詢，結果短時間緩存）
+	GetRoomMessageCount(ctx context.Context, in *GetRoomMessageCountRequest, opts ...grpc.CallOption) (*GetRoomMessageCountResponse, error)
+	// 雙向聊天流（發送訊息並接收確認，同時接收聊天室新訊息）
+	Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatStreamRequest, ChatStreamResponse], error)
+	// 刪除用戶數據（GDPR 被遺忘權，本人或數據保護管理員）
+	DeleteUserData(ctx context.Context, in *DeleteUserDataRequest, opts ...grpc.CallOption) (*DeleteUserDataResponse, error)
+	// 管理員批量導入聊天室和訊息（從其他系統遷移，保留原始時間和發送者，逐項返回結果）
+	ImportRooms(ctx context.Context, in *ImportRoomsRequest, opts ...grpc.CallOption) (*ImportRoomsResponse, error)
+	ImportMessages(ctx context.Context, in *ImportMessagesRequest, opts ...grpc.CallOption) (*ImportMessagesResponse, error)
 }
 
 type chatRoomServiceClient struct {
@@ -97,6 +186,146 @@ func (c *chatRoomServiceClient) LeaveRoom(ctx context.Context, in *LeaveRoomRequ
 	return out, nil
 }
 
+func (c *chatRoomServiceClient) KickMember(ctx context.Context, in *KickMemberRequest, opts ...grpc.CallOption) (*KickMemberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KickMemberResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_KickMember_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) TransferOwnership(ctx context.Context, in *TransferOwnershipRequest, opts ...grpc.CallOption) (*TransferOwnershipResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferOwnershipResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_TransferOwnership_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) DeleteRoom(ctx context.Context, in *DeleteRoomRequest, opts ...grpc.CallOption) (*DeleteRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRoomResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_DeleteRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) UpdateRoom(ctx context.Context, in *UpdateRoomRequest, opts ...grpc.CallOption) (*UpdateRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateRoomResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_UpdateRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) SetRoomMode(ctx context.Context, in *SetRoomModeRequest, opts ...grpc.CallOption) (*SetRoomModeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetRoomModeResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_SetRoomMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) MuteRoom(ctx context.Context, in *MuteRoomRequest, opts ...grpc.CallOption) (*MuteRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MuteRoomResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_MuteRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) UnmuteRoom(ctx context.Context, in *UnmuteRoomRequest, opts ...grpc.CallOption) (*UnmuteRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnmuteRoomResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_UnmuteRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) ArchiveRoom(ctx context.Context, in *ArchiveRoomRequest, opts ...grpc.CallOption) (*ArchiveRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveRoomResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_ArchiveRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) UnarchiveRoom(ctx context.Context, in *UnarchiveRoomRequest, opts ...grpc.CallOption) (*UnarchiveRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnarchiveRoomResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_UnarchiveRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) PinRoom(ctx context.Context, in *PinRoomRequest, opts ...grpc.CallOption) (*PinRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinRoomResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_PinRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) UnpinRoom(ctx context.Context, in *UnpinRoomRequest, opts ...grpc.CallOption) (*UnpinRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnpinRoomResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_UnpinRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) RotateRoomKey(ctx context.Context, in *RotateRoomKeyRequest, opts ...grpc.CallOption) (*RotateRoomKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateRoomKeyResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_RotateRoomKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) GetKeyStats(ctx context.Context, in *GetKeyStatsRequest, opts ...grpc.CallOption) (*GetKeyStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetKeyStatsResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_GetKeyStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) GetRoomKeyInfo(ctx context.Context, in *GetRoomKeyInfoRequest, opts ...grpc.CallOption) (*GetRoomKeyInfoResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoomKeyInfoResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_GetRoomKeyInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatRoomServiceClient) GetRoomInfo(ctx context.Context, in *GetRoomInfoRequest, opts ...grpc.CallOption) (*GetRoomInfoResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetRoomInfoResponse)
@@ -107,6 +336,26 @@ func (c *chatRoomServiceClient) GetRoomInfo(ctx context.Context, in *GetRoomInfo
 	return out, nil
 }
 
+func (c *chatRoomServiceClient) GetOnlineMembers(ctx context.Context, in *GetOnlineMembersRequest, opts ...grpc.CallOption) (*GetOnlineMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOnlineMembersResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_GetOnlineMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) ListRoomMembers(ctx context.Context, in *ListRoomMembersRequest, opts ...grpc.CallOption) (*ListRoomMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoomMembersResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_ListRoomMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatRoomServiceClient) ListUserRooms(ctx context.Context, in *ListUserRoomsRequest, opts ...grpc.CallOption) (*ListUserRoomsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListUserRoomsResponse)
@@ -117,6 +366,26 @@ func (c *chatRoomServiceClient) ListUserRooms(ctx context.Context, in *ListUserR
 	return out, nil
 }
 
+func (c *chatRoomServiceClient) ListRooms(ctx context.Context, in *ListRoomsRequest, opts ...grpc.CallOption) (*ListRoomsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRoomsResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_ListRooms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) GetMessagesPaged(ctx context.Context, in *GetMessagesPagedRequest, opts ...grpc.CallOption) (*GetMessagesPagedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMessagesPagedResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_GetMessagesPaged_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatRoomServiceClient) SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*SendMessageResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(SendMessageResponse)
@@ -127,6 +396,46 @@ func (c *chatRoomServiceClient) SendMessage(ctx context.Context, in *SendMessage
 	return out, nil
 }
 
+func (c *chatRoomServiceClient) ForwardMessage(ctx context.Context, in *ForwardMessageRequest, opts ...grpc.CallOption) (*ForwardMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ForwardMessageResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_ForwardMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) ScheduleMessage(ctx context.Context, in *ScheduleMessageRequest, opts ...grpc.CallOption) (*ScheduleMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScheduleMessageResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_ScheduleMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) ListScheduledMessages(ctx context.Context, in *ListScheduledMessagesRequest, opts ...grpc.CallOption) (*ListScheduledMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListScheduledMessagesResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_ListScheduledMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) CancelScheduledMessage(ctx context.Context, in *CancelScheduledMessageRequest, opts ...grpc.CallOption) (*CancelScheduledMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelScheduledMessageResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_CancelScheduledMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatRoomServiceClient) GetMessages(ctx context.Context, in *GetMessagesRequest, opts ...grpc.CallOption) (*GetMessagesResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetMessagesResponse)
@@ -137,6 +446,36 @@ func (c *chatRoomServiceClient) GetMessages(ctx context.Context, in *GetMessages
 	return out, nil
 }
 
+func (c *chatRoomServiceClient) GetMessagesAround(ctx context.Context, in *GetMessagesAroundRequest, opts ...grpc.CallOption) (*GetMessagesAroundResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMessagesAroundResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_GetMessagesAround_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) GetMessage(ctx context.Context, in *GetMessageRequest, opts ...grpc.CallOption) (*GetMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMessageResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_GetMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) GetThread(ctx context.Context, in *GetThreadRequest, opts ...grpc.CallOption) (*GetThreadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetThreadResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_GetThread_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatRoomServiceClient) StreamMessages(ctx context.Context, in *StreamMessagesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatMessage], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &ChatRoomService_ServiceDesc.Streams[0], ChatRoomService_StreamMessages_FullMethodName, cOpts...)
@@ -166,6 +505,26 @@ func (c *chatRoomServiceClient) MarkAsRead(ctx context.Context, in *MarkAsReadRe
 	return out, nil
 }
 
+func (c *chatRoomServiceClient) MarkAllAsRead(ctx context.Context, in *MarkAllAsReadRequest, opts ...grpc.CallOption) (*MarkAllAsReadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkAllAsReadResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_MarkAllAsRead_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) MarkAsDelivered(ctx context.Context, in *MarkAsDeliveredRequest, opts ...grpc.CallOption) (*MarkAsDeliveredResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkAsDeliveredResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_MarkAsDelivered_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chatRoomServiceClient) GetUnreadCount(ctx context.Context, in *GetUnreadCountRequest, opts ...grpc.CallOption) (*GetUnreadCountResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetUnreadCountResponse)
@@ -176,6 +535,59 @@ func (c *chatRoomServiceClient) GetUnreadCount(ctx context.Context, in *GetUnrea
 	return out, nil
 }
 
+func (c *chatRoomServiceClient) GetRoomMessageCount(ctx context.Context, in *GetRoomMessageCountRequest, opts ...grpc.CallOption) (*GetRoomMessageCountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoomMessageCountResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_GetRoomMessageCount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatStreamRequest, ChatStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChatRoomService_ServiceDesc.Streams[1], ChatRoomService_Chat_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChatStreamRequest, ChatStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatRoomService_ChatClient = grpc.BidiStreamingClient[ChatStreamRequest, ChatStreamResponse]
+
+func (c *chatRoomServiceClient) DeleteUserData(ctx context.Context, in *DeleteUserDataRequest, opts ...grpc.CallOption) (*DeleteUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteUserDataResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_DeleteUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) ImportRooms(ctx context.Context, in *ImportRoomsRequest, opts ...grpc.CallOption) (*ImportRoomsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportRoomsResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_ImportRooms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chatRoomServiceClient) ImportMessages(ctx context.Context, in *ImportMessagesRequest, opts ...grpc.CallOption) (*ImportMessagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportMessagesResponse)
+	err := c.cc.Invoke(ctx, ChatRoomService_ImportMessages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ChatRoomServiceServer is the server API for ChatRoomService service.
 // All implementations must embed UnimplementedChatRoomServiceServer
 // for forward compatibility.
@@ -186,22 +598,79 @@ type ChatRoomServiceServer interface {
 	CreateRoom(context.Context, *CreateRoomRequest) (*CreateRoomResponse, error)
 	// 加入聊天室
 	JoinRoom(context.Context, *JoinRoomRequest) (*JoinRoomResponse, error)
-	// 離開聊天室
+	// 離開聊天室（只能自己離開）
 	LeaveRoom(context.Context, *LeaveRoomRequest) (*LeaveRoomResponse, error)
+	// 將成員移出聊天室（僅限擁有者或管理員，不能移出擁有者）
+	KickMember(context.Context, *KickMemberRequest) (*KickMemberResponse, error)
+	// 轉讓聊天室擁有權（僅限當前擁有者，新擁有者必須是成員）
+	TransferOwnership(context.Context, *TransferOwnershipRequest) (*TransferOwnershipResponse, error)
+	// 刪除聊天室（僅限擁有者，同時刪除訊息與加密密鑰）
+	DeleteRoom(context.Context, *DeleteRoomRequest) (*DeleteRoomResponse, error)
+	// 更新聊天室（名稱、頭像、設置）
+	UpdateRoom(context.Context, *UpdateRoomRequest) (*UpdateRoomResponse, error)
+	// 設置聊天室模式（普通、慢速、僅公告、只讀）
+	SetRoomMode(context.Context, *SetRoomModeRequest) (*SetRoomModeResponse, error)
+	// 靜音 / 取消靜音聊天室（只影響操作者自己）
+	MuteRoom(context.Context, *MuteRoomRequest) (*MuteRoomResponse, error)
+	UnmuteRoom(context.Context, *UnmuteRoomRequest) (*UnmuteRoomResponse, error)
+	// 封存 / 取消封存聊天室（只影響操作者自己，收到新訊息時自動取消封存，封存時取消置頂）
+	ArchiveRoom(context.Context, *ArchiveRoomRequest) (*ArchiveRoomResponse, error)
+	UnarchiveRoom(context.Context, *UnarchiveRoomRequest) (*UnarchiveRoomResponse, error)
+	// 置頂 / 取消置頂聊天室（只影響操作者自己，置頂的聊天室在列表中排在最前，與封存互斥）
+	PinRoom(context.Context, *PinRoomRequest) (*PinRoomResponse, error)
+	UnpinRoom(context.Context, *UnpinRoomRequest) (*UnpinRoomResponse, error)
+	// 手動輪換聊天室加密密鑰（僅限擁有者或管理員）
+	RotateRoomKey(context.Context, *RotateRoomKeyRequest) (*RotateRoomKeyResponse, error)
+	// 密鑰管理器統計與聊天室密鑰狀態（管理端點，不返回密鑰值）
+	GetKeyStats(context.Context, *GetKeyStatsRequest) (*GetKeyStatsResponse, error)
+	GetRoomKeyInfo(context.Context, *GetRoomKeyInfoRequest) (*GetRoomKeyInfoResponse, error)
 	// 獲取聊天室信息
 	GetRoomInfo(context.Context, *GetRoomInfoRequest) (*GetRoomInfoResponse, error)
+	// 獲取最近在線的聊天室成員
+	GetOnlineMembers(context.Context, *GetOnlineMembersRequest) (*GetOnlineMembersResponse, error)
+	// 分頁列出聊天室成員（支持按角色和用戶名前綴過濾）
+	ListRoomMembers(context.Context, *ListRoomMembersRequest) (*ListRoomMembersResponse, error)
 	// 列出用戶的聊天室
 	ListUserRooms(context.Context, *ListUserRoomsRequest) (*ListUserRoomsResponse, error)
+	// 管理員列出和搜索所有聊天室（需要啟用 JWT 認證，且調用者在 admin_user_ids 中）
+	ListRooms(context.Context, *ListRoomsRequest) (*ListRoomsResponse, error)
+	// 管理員按頁碼（skip/limit）瀏覽聊天室訊息，返回總數；只用於管理工具，客戶端請使用 GetMessages 的游標分頁
+	GetMessagesPaged(context.Context, *GetMessagesPagedRequest) (*GetMessagesPagedResponse, error)
 	// 發送消息
 	SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error)
+	// 轉發消息到另一個聊天室（需要是來源和目標聊天室的成員）
+	ForwardMessage(context.Context, *ForwardMessageRequest) (*ForwardMessageResponse, error)
+	// 定時訊息：到達發送時間後由後台任務發送
+	ScheduleMessage(context.Context, *ScheduleMessageRequest) (*ScheduleMessageResponse, error)
+	ListScheduledMessages(context.Context, *ListScheduledMessagesRequest) (*ListScheduledMessagesResponse, error)
+	CancelScheduledMessage(context.Context, *CancelScheduledMessageRequest) (*CancelScheduledMessageResponse, error)
 	// 獲取消息
 	GetMessages(context.Context, *GetMessagesRequest) (*GetMessagesResponse, error)
+	// 獲取指定訊息或游標前後的訊息（支持雙向翻頁）
+	GetMessagesAround(context.Context, *GetMessagesAroundRequest) (*GetMessagesAroundResponse, error)
+	// 獲取單條訊息（深層連結、刷新被編輯的訊息）
+	GetMessage(context.Context, *GetMessageRequest) (*GetMessageResponse, error)
+	// 獲取討論串（回覆鏈最終指向根訊息的所有回覆，按時間正序分頁）
+	GetThread(context.Context, *GetThreadRequest) (*GetThreadResponse, error)
 	// 流式獲取消息
 	StreamMessages(*StreamMessagesRequest, grpc.ServerStreamingServer[ChatMessage]) error
 	// 標記為已讀
 	MarkAsRead(context.Context, *MarkAsReadRequest) (*MarkAsReadResponse, error)
+	// 標記用戶所有聊天室的訊息為已讀
+	MarkAllAsRead(context.Context, *MarkAllAsReadRequest) (*MarkAllAsReadResponse, error)
+	// 標記為已送達
+	MarkAsDelivered(context.Context, *MarkAsDeliveredRequest) (*MarkAsDeliveredResponse, error)
 	// 獲取未讀數量
 	GetUnreadCount(context.Context, *GetUnreadCountRequest) (*GetUnreadCountResponse, error)
+	// 獲取聊天室訊息總數（只計數，不返回訊息；成員或管理員可以查詢，結果短時間緩存）
+	GetRoomMessageCount(context.Context, *GetRoomMessageCountRequest) (*GetRoomMessageCountResponse, error)
+	// 雙向聊天流（發送訊息並接收確認，同時接收聊天室新訊息）
+	Chat(grpc.BidiStreamingServer[ChatStreamRequest, ChatStreamResponse]) error
+	// 刪除用戶數據（GDPR 被遺忘權，本人或數據保護管理員）
+	DeleteUserData(context.Context, *DeleteUserDataRequest) (*DeleteUserDataResponse, error)
+	// 管理員批量導入聊天室和訊息（從其他系統遷移，保留原始時間和發送者，逐項返回結果）
+	ImportRooms(context.Context, *ImportRoomsRequest) (*ImportRoomsResponse, error)
+	ImportMessages(context.Context, *ImportMessagesRequest) (*ImportMessagesResponse, error)
 	mustEmbedUnimplementedChatRoomServiceServer()
 }
 
@@ -221,30 +690,126 @@ func (UnimplementedChatRoomServiceServer) JoinRoom(context.Context, *JoinRoomReq
 func (UnimplementedChatRoomServiceServer) LeaveRoom(context.Context, *LeaveRoomRequest) (*LeaveRoomResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method LeaveRoom not implemented")
 }
+func (UnimplementedChatRoomServiceServer) KickMember(context.Context, *KickMemberRequest) (*KickMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KickMember not implemented")
+}
+func (UnimplementedChatRoomServiceServer) TransferOwnership(context.Context, *TransferOwnershipRequest) (*TransferOwnershipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferOwnership not implemented")
+}
+func (UnimplementedChatRoomServiceServer) DeleteRoom(context.Context, *DeleteRoomRequest) (*DeleteRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRoom not implemented")
+}
+func (UnimplementedChatRoomServiceServer) UpdateRoom(context.Context, *UpdateRoomRequest) (*UpdateRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRoom not implemented")
+}
+func (UnimplementedChatRoomServiceServer) SetRoomMode(context.Context, *SetRoomModeRequest) (*SetRoomModeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRoomMode not implemented")
+}
+func (UnimplementedChatRoomServiceServer) MuteRoom(context.Context, *MuteRoomRequest) (*MuteRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MuteRoom not implemented")
+}
+func (UnimplementedChatRoomServiceServer) UnmuteRoom(context.Context, *UnmuteRoomRequest) (*UnmuteRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnmuteRoom not implemented")
+}
+func (UnimplementedChatRoomServiceServer) ArchiveRoom(context.Context, *ArchiveRoomRequest) (*ArchiveRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveRoom not implemented")
+}
+func (UnimplementedChatRoomServiceServer) UnarchiveRoom(context.Context, *UnarchiveRoomRequest) (*UnarchiveRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnarchiveRoom not implemented")
+}
+func (UnimplementedChatRoomServiceServer) PinRoom(context.Context, *PinRoomRequest) (*PinRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinRoom not implemented")
+}
+func (UnimplementedChatRoomServiceServer) UnpinRoom(context.Context, *UnpinRoomRequest) (*UnpinRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnpinRoom not implemented")
+}
+func (UnimplementedChatRoomServiceServer) RotateRoomKey(context.Context, *RotateRoomKeyRequest) (*RotateRoomKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateRoomKey not implemented")
+}
+func (UnimplementedChatRoomServiceServer) GetKeyStats(context.Context, *GetKeyStatsRequest) (*GetKeyStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeyStats not implemented")
+}
+func (UnimplementedChatRoomServiceServer) GetRoomKeyInfo(context.Context, *GetRoomKeyInfoRequest) (*GetRoomKeyInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomKeyInfo not implemented")
+}
 func (UnimplementedChatRoomServiceServer) GetRoomInfo(context.Context, *GetRoomInfoRequest) (*GetRoomInfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRoomInfo not implemented")
 }
+func (UnimplementedChatRoomServiceServer) GetOnlineMembers(context.Context, *GetOnlineMembersRequest) (*GetOnlineMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOnlineMembers not implemented")
+}
+func (UnimplementedChatRoomServiceServer) ListRoomMembers(context.Context, *ListRoomMembersRequest) (*ListRoomMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRoomMembers not implemented")
+}
 func (UnimplementedChatRoomServiceServer) ListUserRooms(context.Context, *ListUserRoomsRequest) (*ListUserRoomsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListUserRooms not implemented")
 }
+func (UnimplementedChatRoomServiceServer) ListRooms(context.Context, *ListRoomsRequest) (*ListRoomsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRooms not implemented")
+}
+func (UnimplementedChatRoomServiceServer) GetMessagesPaged(context.Context, *GetMessagesPagedRequest) (*GetMessagesPagedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMessagesPaged not implemented")
+}
 func (UnimplementedChatRoomServiceServer) SendMessage(context.Context, *SendMessageRequest) (*SendMessageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
 }
+func (UnimplementedChatRoomServiceServer) ForwardMessage(context.Context, *ForwardMessageRequest) (*ForwardMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForwardMessage not implemented")
+}
+func (UnimplementedChatRoomServiceServer) ScheduleMessage(context.Context, *ScheduleMessageRequest) (*ScheduleMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScheduleMessage not implemented")
+}
+func (UnimplementedChatRoomServiceServer) ListScheduledMessages(context.Context, *ListScheduledMessagesRequest) (*ListScheduledMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduledMessages not implemented")
+}
+func (UnimplementedChatRoomServiceServer) CancelScheduledMessage(context.Context, *CancelScheduledMessageRequest) (*CancelScheduledMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScheduledMessage not implemented")
+}
 func (UnimplementedChatRoomServiceServer) GetMessages(context.Context, *GetMessagesRequest) (*GetMessagesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMessages not implemented")
 }
+func (UnimplementedChatRoomServiceServer) GetMessagesAround(context.Context, *GetMessagesAroundRequest) (*GetMessagesAroundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMessagesAround not implemented")
+}
+func (UnimplementedChatRoomServiceServer) GetMessage(context.Context, *GetMessageRequest) (*GetMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMessage not implemented")
+}
+func (UnimplementedChatRoomServiceServer) GetThread(context.Context, *GetThreadRequest) (*GetThreadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetThread not implemented")
+}
 func (UnimplementedChatRoomServiceServer) StreamMessages(*StreamMessagesRequest, grpc.ServerStreamingServer[ChatMessage]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamMessages not implemented")
 }
 func (UnimplementedChatRoomServiceServer) MarkAsRead(context.Context, *MarkAsReadRequest) (*MarkAsReadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MarkAsRead not implemented")
 }
+func (UnimplementedChatRoomServiceServer) MarkAllAsRead(context.Context, *MarkAllAsReadRequest) (*MarkAllAsReadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkAllAsRead not implemented")
+}
+func (UnimplementedChatRoomServiceServer) MarkAsDelivered(context.Context, *MarkAsDeliveredRequest) (*MarkAsDeliveredResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkAsDelivered not implemented")
+}
 func (UnimplementedChatRoomServiceServer) GetUnreadCount(context.Context, *GetUnreadCountRequest) (*GetUnreadCountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUnreadCount not implemented")
 }
-func (UnimplementedChatRoomServiceServer) mustEmbedUnimplementedChatRoomServiceServer() {}
-func (UnimplementedChatRoomServiceServer) testEmbeddedByValue()                         {}
-
+func (UnimplementedChatRoomServiceServer) GetRoomMessageCount(context.Context, *GetRoomMessageCountRequest) (*GetRoomMessageCountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomMessageCount not implemented")
+}
+func (UnimplementedChatRoomServiceServer) Chat(grpc.BidiStreamingServer[ChatStreamRequest, ChatStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Chat not implemented")
+}
+func (UnimplementedChatRoomServiceServer) DeleteUserData(context.Context, *DeleteUserDataRequest) (*DeleteUserDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserData not implemented")
+}
+func (UnimplementedChatRoomServiceServer) ImportRooms(context.Context, *ImportRoomsRequest) (*ImportRoomsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportRooms not implemented")
+}
+func (UnimplementedChatRoomServiceServer) ImportMessages(context.Context, *ImportMessagesRequest) (*ImportMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMessages not implemented")
+}
+func (UnimplementedChatRoomServiceServer) mustEmbedUnimplementedChatRoomServiceServer() {}
+func (UnimplementedChatRoomServiceServer) testEmbeddedByValue()                         {}
+
 // UnsafeChatRoomServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to ChatRoomServiceServer will
 // result in compilation errors.
@@ -252,121 +817,517 @@ type UnsafeChatRoomServiceServer interface {
 	mustEmbedUnimplementedChatRoomServiceServer()
 }
 
-func RegisterChatRoomServiceServer(s grpc.ServiceRegistrar, srv ChatRoomServiceServer) {
-	// If the following call pancis, it indicates UnimplementedChatRoomServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+func RegisterChatRoomServiceServer(s grpc.ServiceRegistrar, srv ChatRoomServiceServer) {
+	// If the following call pancis, it indicates UnimplementedChatRoomServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChatRoomService_ServiceDesc, srv)
+}
+
+func _ChatRoomService_CreateRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).CreateRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_CreateRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).CreateRoom(ctx, req.(*CreateRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_JoinRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JoinRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).JoinRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_JoinRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).JoinRoom(ctx, req.(*JoinRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_LeaveRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LeaveRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).LeaveRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_LeaveRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).LeaveRoom(ctx, req.(*LeaveRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_KickMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).KickMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_KickMember_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).KickMember(ctx, req.(*KickMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_TransferOwnership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferOwnershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).TransferOwnership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_TransferOwnership_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).TransferOwnership(ctx, req.(*TransferOwnershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_DeleteRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).DeleteRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_DeleteRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).DeleteRoom(ctx, req.(*DeleteRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_UpdateRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).UpdateRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_UpdateRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).UpdateRoom(ctx, req.(*UpdateRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_SetRoomMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRoomModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).SetRoomMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_SetRoomMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).SetRoomMode(ctx, req.(*SetRoomModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_MuteRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MuteRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).MuteRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_MuteRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).MuteRoom(ctx, req.(*MuteRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_UnmuteRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnmuteRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).UnmuteRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_UnmuteRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).UnmuteRoom(ctx, req.(*UnmuteRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_ArchiveRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).ArchiveRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_ArchiveRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).ArchiveRoom(ctx, req.(*ArchiveRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_UnarchiveRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnarchiveRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).UnarchiveRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_UnarchiveRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).UnarchiveRoom(ctx, req.(*UnarchiveRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_PinRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).PinRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_PinRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).PinRoom(ctx, req.(*PinRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_UnpinRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnpinRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).UnpinRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_UnpinRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).UnpinRoom(ctx, req.(*UnpinRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_RotateRoomKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateRoomKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).RotateRoomKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_RotateRoomKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).RotateRoomKey(ctx, req.(*RotateRoomKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_GetKeyStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).GetKeyStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_GetKeyStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).GetKeyStats(ctx, req.(*GetKeyStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_GetRoomKeyInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomKeyInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).GetRoomKeyInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_GetRoomKeyInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).GetRoomKeyInfo(ctx, req.(*GetRoomKeyInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_GetRoomInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).GetRoomInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_GetRoomInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).GetRoomInfo(ctx, req.(*GetRoomInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_GetOnlineMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOnlineMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).GetOnlineMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_GetOnlineMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).GetOnlineMembers(ctx, req.(*GetOnlineMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_ListRoomMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoomMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).ListRoomMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_ListRoomMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).ListRoomMembers(ctx, req.(*ListRoomMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_ListUserRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserRoomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).ListUserRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_ListUserRooms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).ListUserRooms(ctx, req.(*ListUserRoomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_ListRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRoomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	s.RegisterService(&ChatRoomService_ServiceDesc, srv)
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).ListRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_ListRooms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).ListRooms(ctx, req.(*ListRoomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ChatRoomService_CreateRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateRoomRequest)
+func _ChatRoomService_GetMessagesPaged_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMessagesPagedRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatRoomServiceServer).CreateRoom(ctx, in)
+		return srv.(ChatRoomServiceServer).GetMessagesPaged(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChatRoomService_CreateRoom_FullMethodName,
+		FullMethod: ChatRoomService_GetMessagesPaged_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatRoomServiceServer).CreateRoom(ctx, req.(*CreateRoomRequest))
+		return srv.(ChatRoomServiceServer).GetMessagesPaged(ctx, req.(*GetMessagesPagedRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChatRoomService_JoinRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(JoinRoomRequest)
+func _ChatRoomService_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatRoomServiceServer).JoinRoom(ctx, in)
+		return srv.(ChatRoomServiceServer).SendMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChatRoomService_JoinRoom_FullMethodName,
+		FullMethod: ChatRoomService_SendMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatRoomServiceServer).JoinRoom(ctx, req.(*JoinRoomRequest))
+		return srv.(ChatRoomServiceServer).SendMessage(ctx, req.(*SendMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChatRoomService_LeaveRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LeaveRoomRequest)
+func _ChatRoomService_ForwardMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatRoomServiceServer).LeaveRoom(ctx, in)
+		return srv.(ChatRoomServiceServer).ForwardMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChatRoomService_LeaveRoom_FullMethodName,
+		FullMethod: ChatRoomService_ForwardMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatRoomServiceServer).LeaveRoom(ctx, req.(*LeaveRoomRequest))
+		return srv.(ChatRoomServiceServer).ForwardMessage(ctx, req.(*ForwardMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChatRoomService_GetRoomInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRoomInfoRequest)
+func _ChatRoomService_ScheduleMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScheduleMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatRoomServiceServer).GetRoomInfo(ctx, in)
+		return srv.(ChatRoomServiceServer).ScheduleMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChatRoomService_GetRoomInfo_FullMethodName,
+		FullMethod: ChatRoomService_ScheduleMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatRoomServiceServer).GetRoomInfo(ctx, req.(*GetRoomInfoRequest))
+		return srv.(ChatRoomServiceServer).ScheduleMessage(ctx, req.(*ScheduleMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChatRoomService_ListUserRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListUserRoomsRequest)
+func _ChatRoomService_ListScheduledMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListScheduledMessagesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatRoomServiceServer).ListUserRooms(ctx, in)
+		return srv.(ChatRoomServiceServer).ListScheduledMessages(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChatRoomService_ListUserRooms_FullMethodName,
+		FullMethod: ChatRoomService_ListScheduledMessages_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatRoomServiceServer).ListUserRooms(ctx, req.(*ListUserRoomsRequest))
+		return srv.(ChatRoomServiceServer).ListScheduledMessages(ctx, req.(*ListScheduledMessagesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ChatRoomService_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendMessageRequest)
+func _ChatRoomService_CancelScheduledMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelScheduledMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ChatRoomServiceServer).SendMessage(ctx, in)
+		return srv.(ChatRoomServiceServer).CancelScheduledMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ChatRoomService_SendMessage_FullMethodName,
+		FullMethod: ChatRoomService_CancelScheduledMessage_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ChatRoomServiceServer).SendMessage(ctx, req.(*SendMessageRequest))
+		return srv.(ChatRoomServiceServer).CancelScheduledMessage(ctx, req.(*CancelScheduledMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -389,6 +1350,60 @@ func _ChatRoomService_GetMessages_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChatRoomService_GetMessagesAround_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMessagesAroundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).GetMessagesAround(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_GetMessagesAround_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).GetMessagesAround(ctx, req.(*GetMessagesAroundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_GetMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).GetMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_GetMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).GetMessage(ctx, req.(*GetMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_GetThread_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetThreadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).GetThread(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_GetThread_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).GetThread(ctx, req.(*GetThreadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ChatRoomService_StreamMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(StreamMessagesRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -418,6 +1433,42 @@ func _ChatRoomService_MarkAsRead_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChatRoomService_MarkAllAsRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkAllAsReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).MarkAllAsRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_MarkAllAsRead_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).MarkAllAsRead(ctx, req.(*MarkAllAsReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_MarkAsDelivered_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkAsDeliveredRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).MarkAsDelivered(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_MarkAsDelivered_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).MarkAsDelivered(ctx, req.(*MarkAsDeliveredRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ChatRoomService_GetUnreadCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetUnreadCountRequest)
 	if err := dec(in); err != nil {
@@ -436,6 +1487,85 @@ func _ChatRoomService_GetUnreadCount_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChatRoomService_GetRoomMessageCount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomMessageCountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).GetRoomMessageCount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_GetRoomMessageCount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).GetRoomMessageCount(ctx, req.(*GetRoomMessageCountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChatRoomServiceServer).Chat(&grpc.GenericServerStream[ChatStreamRequest, ChatStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChatRoomService_ChatServer = grpc.BidiStreamingServer[ChatStreamRequest, ChatStreamResponse]
+
+func _ChatRoomService_DeleteUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).DeleteUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_DeleteUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).DeleteUserData(ctx, req.(*DeleteUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_ImportRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRoomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).ImportRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_ImportRooms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).ImportRooms(ctx, req.(*ImportRoomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChatRoomService_ImportMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMessagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChatRoomServiceServer).ImportMessages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChatRoomService_ImportMessages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChatRoomServiceServer).ImportMessages(ctx, req.(*ImportMessagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ChatRoomService_ServiceDesc is the grpc.ServiceDesc for ChatRoomService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -455,30 +1585,154 @@ var ChatRoomService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LeaveRoom",
 			Handler:    _ChatRoomService_LeaveRoom_Handler,
 		},
+		{
+			MethodName: "KickMember",
+			Handler:    _ChatRoomService_KickMember_Handler,
+		},
+		{
+			MethodName: "TransferOwnership",
+			Handler:    _ChatRoomService_TransferOwnership_Handler,
+		},
+		{
+			MethodName: "DeleteRoom",
+			Handler:    _ChatRoomService_DeleteRoom_Handler,
+		},
+		{
+			MethodName: "UpdateRoom",
+			Handler:    _ChatRoomService_UpdateRoom_Handler,
+		},
+		{
+			MethodName: "SetRoomMode",
+			Handler:    _ChatRoomService_SetRoomMode_Handler,
+		},
+		{
+			MethodName: "MuteRoom",
+			Handler:    _ChatRoomService_MuteRoom_Handler,
+		},
+		{
+			MethodName: "UnmuteRoom",
+			Handler:    _ChatRoomService_UnmuteRoom_Handler,
+		},
+		{
+			MethodName: "ArchiveRoom",
+			Handler:    _ChatRoomService_ArchiveRoom_Handler,
+		},
+		{
+			MethodName: "UnarchiveRoom",
+			Handler:    _ChatRoomService_UnarchiveRoom_Handler,
+		},
+		{
+			MethodName: "PinRoom",
+			Handler:    _ChatRoomService_PinRoom_Handler,
+		},
+		{
+			MethodName: "UnpinRoom",
+			Handler:    _ChatRoomService_UnpinRoom_Handler,
+		},
+		{
+			MethodName: "RotateRoomKey",
+			Handler:    _ChatRoomService_RotateRoomKey_Handler,
+		},
+		{
+			MethodName: "GetKeyStats",
+			Handler:    _ChatRoomService_GetKeyStats_Handler,
+		},
+		{
+			MethodName: "GetRoomKeyInfo",
+			Handler:    _ChatRoomService_GetRoomKeyInfo_Handler,
+		},
 		{
 			MethodName: "GetRoomInfo",
 			Handler:    _ChatRoomService_GetRoomInfo_Handler,
 		},
+		{
+			MethodName: "GetOnlineMembers",
+			Handler:    _ChatRoomService_GetOnlineMembers_Handler,
+		},
+		{
+			MethodName: "ListRoomMembers",
+			Handler:    _ChatRoomService_ListRoomMembers_Handler,
+		},
 		{
 			MethodName: "ListUserRooms",
 			Handler:    _ChatRoomService_ListUserRooms_Handler,
 		},
+		{
+			MethodName: "ListRooms",
+			Handler:    _ChatRoomService_ListRooms_Handler,
+		},
+		{
+			MethodName: "GetMessagesPaged",
+			Handler:    _ChatRoomService_GetMessagesPaged_Handler,
+		},
 		{
 			MethodName: "SendMessage",
 			Handler:    _ChatRoomService_SendMessage_Handler,
 		},
+		{
+			MethodName: "ForwardMessage",
+			Handler:    _ChatRoomService_ForwardMessage_Handler,
+		},
+		{
+			MethodName: "ScheduleMessage",
+			Handler:    _ChatRoomService_ScheduleMessage_Handler,
+		},
+		{
+			MethodName: "ListScheduledMessages",
+			Handler:    _ChatRoomService_ListScheduledMessages_Handler,
+		},
+		{
+			MethodName: "CancelScheduledMessage",
+			Handler:    _ChatRoomService_CancelScheduledMessage_Handler,
+		},
 		{
 			MethodName: "GetMessages",
 			Handler:    _ChatRoomService_GetMessages_Handler,
 		},
+		{
+			MethodName: "GetMessagesAround",
+			Handler:    _ChatRoomService_GetMessagesAround_Handler,
+		},
+		{
+			MethodName: "GetMessage",
+			Handler:    _ChatRoomService_GetMessage_Handler,
+		},
+		{
+			MethodName: "GetThread",
+			Handler:    _ChatRoomService_GetThread_Handler,
+		},
 		{
 			MethodName: "MarkAsRead",
 			Handler:    _ChatRoomService_MarkAsRead_Handler,
 		},
+		{
+			MethodName: "MarkAllAsRead",
+			Handler:    _ChatRoomService_MarkAllAsRead_Handler,
+		},
+		{
+			MethodName: "MarkAsDelivered",
+			Handler:    _ChatRoomService_MarkAsDelivered_Handler,
+		},
 		{
 			MethodName: "GetUnreadCount",
 			Handler:    _ChatRoomService_GetUnreadCount_Handler,
 		},
+		{
+			MethodName: "GetRoomMessageCount",
+			Handler:    _ChatRoomService_GetRoomMessageCount_Handler,
+		},
+		{
+			MethodName: "DeleteUserData",
+			Handler:    _ChatRoomService_DeleteUserData_Handler,
+		},
+		{
+			MethodName: "ImportRooms",
+			Handler:    _ChatRoomService_ImportRooms_Handler,
+		},
+		{
+			MethodName: "ImportMessages",
+			Handler:    _ChatRoomService_ImportMessages_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -486,6 +1740,12 @@ var ChatRoomService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _ChatRoomService_StreamMessages_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Chat",
+			Handler:       _ChatRoomService_Chat_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "proto/chat.proto",
 }